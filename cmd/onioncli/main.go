@@ -6,21 +6,45 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"onioncli/pkg/config"
 	"onioncli/pkg/tui"
 )
 
 func main() {
+	// "onioncli run" sends requests without the TUI, for scripts and pipelines
+	if len(os.Args) > 1 && os.Args[1] == "run" {
+		os.Exit(runHeadless(os.Args[2:], os.Stdin, os.Stdout, os.Stderr))
+	}
+
+	// Load ~/.onioncli/config.yaml, creating it with defaults on first run
+	configManager, err := config.NewManager()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
 	// Initialize the TUI model
-	model, err := tui.NewModel()
+	model, err := tui.NewModel(configManager)
 	if err != nil {
 		log.Fatalf("Failed to initialize TUI: %v", err)
 	}
 
 	// Initialize the Bubbletea program
-	p := tea.NewProgram(model, tea.WithAltScreen())
+	options := []tea.ProgramOption{tea.WithAltScreen()}
+	if configManager.Get().UI.Mouse {
+		options = append(options, tea.WithMouseCellMotion())
+	}
+	p := tea.NewProgram(model, options...)
 
-	if _, err := p.Run(); err != nil {
+	finalModel, err := p.Run()
+	if err != nil {
 		log.Fatal(err)
 		os.Exit(1)
 	}
+
+	// Keep whatever was being composed for the next launch
+	if m, ok := finalModel.(tui.Model); ok {
+		if err := m.SaveDraft(); err != nil {
+			log.Printf("Failed to save draft: %v", err)
+		}
+	}
 }
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"onioncli/pkg/api"
+	"onioncli/pkg/collections"
+	"onioncli/pkg/config"
+	"onioncli/pkg/tui"
+)
+
+// runResult is the machine-readable outcome of one request, printed with --json
+type runResult struct {
+	Name       string            `json:"name,omitempty"`
+	Method     string            `json:"method"`
+	URL        string            `json:"url"`
+	StatusCode int               `json:"status_code,omitempty"`
+	Status     string            `json:"status,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       string            `json:"body,omitempty"`
+	DurationMS int64             `json:"duration_ms"`
+	Warning    string            `json:"warning,omitempty"`  // e.g. a body shorter than its Content-Length
+	Warnings   []string          `json:"warnings,omitempty"` // from the pre-send checks, e.g. unresolved variables
+	Error      string            `json:"error,omitempty"`
+}
+
+// runHeadless implements "onioncli run [flags] <file|->": it reads a JSON
+// request, collection, .http file or cURL command, sends each request and
+// prints the responses. It returns the process exit code.
+func runHeadless(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	jsonOutput := flags.Bool("json", false, "print one JSON object per request with status, headers, body and timing")
+	noTor := flags.Bool("no-tor", false, "send requests directly instead of through Tor")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: onioncli run [--json] [--no-tor] <file|->")
+		fmt.Fprintln(stderr, "Reads a JSON request, collection, .http file or cURL command; - reads stdin.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return api.ExitRequestError
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return api.ExitRequestError
+	}
+
+	input := stdin
+	if path := flags.Arg(0); path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return api.ExitRequestError
+		}
+		defer file.Close()
+		input = file
+	}
+
+	requests, format, err := collections.ParseDefinition(input)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return api.ExitRequestError
+	}
+
+	configManager, err := config.NewManager()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: failed to load configuration: %v\n", err)
+		return api.ExitRequestError
+	}
+	collectionsManager, err := collections.NewManager()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: failed to load environments: %v\n", err)
+		return api.ExitRequestError
+	}
+
+	// The client is set up as the TUI's, so the Tor proxy, timeout, retries,
+	// redirects, pool and cookie jar settings apply; --no-tor only lasts for
+	// this run and leaves the configuration alone
+	clientConfig := tui.NewClientConfig(configManager)
+	if *noTor {
+		clientConfig.TorEnabled = false
+	}
+	client, err := api.NewClient(clientConfig)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return api.ExitRequestError
+	}
+
+	if !*jsonOutput {
+		fmt.Fprintf(stderr, "Running %d %s from %s definition\n", len(requests), plural(len(requests), "request", "requests"), format)
+	}
+
+	authManager := api.NewAuthManager()
+	authManager.SetClient(client)
+	encoder := json.NewEncoder(stdout)
+	exitCode := api.ExitSuccess
+	for i := range requests {
+		resp, req, warnings, err := sendDefinition(client, authManager, collectionsManager, configManager, &requests[i])
+		if code := api.ExitCode(resp, err, configManager.Get().HTTP.TreatNon2xxAsError); code > exitCode {
+			exitCode = code
+		}
+
+		if *jsonOutput {
+			encoder.Encode(newRunResult(&requests[i], req, resp, warnings, err))
+			continue
+		}
+		for _, warning := range warnings {
+			fmt.Fprintf(stderr, "Warning: %s\n", warning)
+		}
+		printResponse(stdout, stderr, req, resp, err)
+	}
+	return exitCode
+}
+
+// sendDefinition runs a parsed request through the TUI's pre-send pipeline,
+// against the active environment and default headers and with its auth, and
+// sends it. The resolved request is returned so output can show what was
+// actually sent, along with the pipeline's warnings.
+func sendDefinition(client *api.Client, authManager *api.AuthManager, manager *collections.Manager, configManager *config.Manager, definition *collections.CollectionRequest) (*api.Response, *api.Request, []string, error) {
+	definitionReq := definition.ToRequest()
+	req, warnings, err := tui.PrepareRequest(configManager, manager, client, authManager, definitionReq, definition.Auth)
+	if err != nil {
+		return nil, definitionReq, nil, err
+	}
+
+	resp, err := client.Send(req)
+	if err != nil {
+		return nil, req, warnings, err
+	}
+	if err := resp.ApplyPostProcessors(manager.ResolvePostProcess(definition.PostProcess)); err != nil {
+		return resp, req, warnings, fmt.Errorf("post-processing failed: %w", err)
+	}
+	return resp, req, warnings, nil
+}
+
+// newRunResult builds the JSON output for one request
+func newRunResult(definition *collections.CollectionRequest, req *api.Request, resp *api.Response, warnings []string, err error) runResult {
+	result := runResult{Name: definition.Name, Method: req.Method, URL: req.URL, Warnings: warnings}
+	if resp != nil {
+		result.StatusCode = resp.StatusCode
+		result.Status = resp.Status
+		result.Headers = resp.Headers
+		result.Body = resp.Body
+		result.DurationMS = resp.Duration.Milliseconds()
+		result.Warning = resp.LengthWarning
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// printResponse writes the response body to stdout and a status line with
+// the response headers to stderr, so the body can be piped on untouched
+func printResponse(stdout, stderr io.Writer, req *api.Request, resp *api.Response, err error) {
+	if resp == nil {
+		fmt.Fprintf(stderr, "%s %s: %v\n", req.Method, req.URL, err)
+		return
+	}
+
+	fmt.Fprintf(stderr, "%s %s: %s (%v)\n", req.Method, req.URL, resp.Status, resp.Duration.Round(time.Millisecond))
+	keys := make([]string, 0, len(resp.Headers))
+	for key := range resp.Headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(stderr, "  %s: %s\n", key, resp.Headers[key])
+	}
+	if resp.LengthMismatch {
+		fmt.Fprintf(stderr, "Warning: %s\n", resp.LengthWarning)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "Warning: %v\n", err)
+	}
+
+	io.WriteString(stdout, resp.Body)
+	if resp.Body != "" && !strings.HasSuffix(resp.Body, "\n") {
+		io.WriteString(stdout, "\n")
+	}
+}
+
+// plural picks the singular or plural form for n
+func plural(n int, singular, pluralForm string) string {
+	if n == 1 {
+		return singular
+	}
+	return pluralForm
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunHeadlessFromStdin(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Method", r.Method)
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		fmt.Fprintf(w, "%s %s", r.Method, body)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"JSON request", fmt.Sprintf(`{"method": "POST", "url": %q, "body": "hello"}`, server.URL+"/echo"), "POST hello"},
+		{"http file", "PUT " + server.URL + "/echo\nContent-Type: text/plain\n\nhello\n", "PUT hello"},
+		{"curl", "curl -X PATCH " + server.URL + "/echo --data-raw 'hello'", "PATCH hello"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := runHeadless([]string{"--no-tor", "--json", "-"}, strings.NewReader(tt.input), &stdout, &stderr)
+			if code != 0 {
+				t.Fatalf("exit code = %d, stderr: %s", code, stderr.String())
+			}
+
+			var result runResult
+			if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+				t.Fatalf("output is not JSON: %v\n%s", err, stdout.String())
+			}
+			if result.StatusCode != http.StatusOK || result.Body != tt.want {
+				t.Errorf("result = %d %q, want 200 %q", result.StatusCode, result.Body, tt.want)
+			}
+			if result.Headers["X-Method"] == "" {
+				t.Errorf("headers missing from JSON output: %v", result.Headers)
+			}
+		})
+	}
+}
+
+func TestRunHeadlessPlainOutput(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "body text")
+	}))
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	code := runHeadless([]string{"--no-tor", "-"}, strings.NewReader("GET "+server.URL), &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, stderr.String())
+	}
+	if stdout.String() != "body text\n" {
+		t.Errorf("stdout = %q, want only the body", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "200 OK") {
+		t.Errorf("stderr = %q, want the status line", stderr.String())
+	}
+}
+
+func TestRunHeadlessInvalidInput(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	var stdout, stderr bytes.Buffer
+	if code := runHeadless([]string{"-"}, strings.NewReader("curl -X GET"), &stdout, &stderr); code == 0 {
+		t.Error("exit code = 0 for a curl command without a URL")
+	}
+}
+
+func TestRunHeadlessUsesConfiguredClient(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	// A proxy that takes the connection and never answers: requests going
+	// through it fail with the configured timeout
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		var conns []net.Conn
+		defer func() {
+			for _, conn := range conns {
+				conn.Close()
+			}
+		}()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conns = append(conns, conn)
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	configYAML := fmt.Sprintf("tor:\n  enabled: true\n  proxy_addr: %s\n  proxy_port: %s\n  auto_detect: false\nhttp:\n  timeout: 1\n", host, port)
+	if err := os.MkdirAll(filepath.Join(home, ".onioncli"), 0700); err != nil {
+		t.Fatalf("Failed to create config dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".onioncli", "config.yaml"), []byte(configYAML), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	started := time.Now()
+	code := runHeadless([]string{"-"}, strings.NewReader("GET http://example.com/"), &stdout, &stderr)
+	if code == 0 {
+		t.Fatalf("exit code = 0 through a proxy that never answers, stderr: %s", stderr.String())
+	}
+	if elapsed := time.Since(started); elapsed > 10*time.Second || !strings.Contains(stderr.String(), "Client.Timeout") {
+		t.Errorf("request took %v with %q, want the configured Tor proxy and 1s timeout", elapsed, stderr.String())
+	}
+}
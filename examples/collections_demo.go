@@ -47,7 +47,10 @@ func main() {
 		"timeout":    "30",
 		"debug_mode": "true",
 	}
-	devEnv := manager.CreateEnvironment("Development", "Development environment for .onion APIs", devVars)
+	devEnv, err := manager.CreateEnvironment("Development", "Development environment for .onion APIs", devVars, nil)
+	if err != nil {
+		log.Fatalf("Failed to create environment: %v", err)
+	}
 	fmt.Printf("✅ Created environment: %s\n", devEnv.Name)
 
 	// Production environment
@@ -57,7 +60,10 @@ func main() {
 		"timeout":    "60",
 		"debug_mode": "false",
 	}
-	prodEnv := manager.CreateEnvironment("Production", "Production environment for .onion APIs", prodVars)
+	prodEnv, err := manager.CreateEnvironment("Production", "Production environment for .onion APIs", prodVars, nil)
+	if err != nil {
+		log.Fatalf("Failed to create environment: %v", err)
+	}
 	fmt.Printf("✅ Created environment: %s\n", prodEnv.Name)
 
 	// Test environment
@@ -67,7 +73,10 @@ func main() {
 		"timeout":    "15",
 		"debug_mode": "true",
 	}
-	testEnv := manager.CreateEnvironment("Testing", "Testing environment for .onion APIs", testVars)
+	testEnv, err := manager.CreateEnvironment("Testing", "Testing environment for .onion APIs", testVars, nil)
+	if err != nil {
+		log.Fatalf("Failed to create environment: %v", err)
+	}
 	fmt.Printf("✅ Created environment: %s\n", testEnv.Name)
 
 	// Switch to development environment
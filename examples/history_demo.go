@@ -22,7 +22,7 @@ func main() {
 
 	// Create some test requests
 	testRequests := []*api.Request{
-		api.NewRequest("GET", "http://3g2upl4pq6kufc4m.onion"),
+		api.NewRequest("GET", "http://duckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzczad.onion"),
 		api.NewRequest("POST", "https://httpbin.org/post"),
 		api.NewRequest("GET", "https://api.github.com/users/octocat"),
 	}
@@ -43,7 +43,7 @@ func main() {
 
 	fmt.Println("Saving test requests to history...")
 	for i, req := range testRequests {
-		err := manager.Save(req, names[i], descriptions[i])
+		err := manager.Save(req, nil, names[i], descriptions[i])
 		if err != nil {
 			fmt.Printf("❌ Failed to save request %d: %v\n", i+1, err)
 		} else {
@@ -56,7 +56,7 @@ func main() {
 
 	// Test creating requests
 	fmt.Println("\nTesting request creation...")
-	req := api.NewRequest("GET", "http://3g2upl4pq6kufc4m.onion")
+	req := api.NewRequest("GET", "http://duckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzczad.onion")
 	req.SetHeader("User-Agent", "OnionCLI/1.0")
 
 	if err := req.Validate(); err != nil {
@@ -4,7 +4,10 @@ import (
 	"encoding/base64"
 	"fmt"
 	"net/url"
+	"os"
+	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/zalando/go-keyring"
 )
@@ -18,6 +21,7 @@ const (
 	AuthBearer AuthType = "bearer"
 	AuthBasic  AuthType = "basic"
 	AuthCustom AuthType = "custom"
+	AuthOAuth2 AuthType = "oauth2"
 )
 
 // AuthConfig holds authentication configuration
@@ -30,11 +34,21 @@ type AuthConfig struct {
 	Username string            `json:"username,omitempty"`
 	Password string            `json:"password,omitempty"`
 	Custom   map[string]string `json:"custom,omitempty"`
+
+	// OAuth2 client-credentials grant; the token is fetched when needed
+	TokenURL     string `json:"token_url,omitempty"`
+	ClientID     string `json:"client_id,omitempty"`
+	ClientSecret string `json:"client_secret,omitempty"`
+	Scope        string `json:"scope,omitempty"`
 }
 
 // AuthManager handles authentication for requests
 type AuthManager struct {
 	serviceName string
+	client      *Client // sends OAuth2 token requests, see SetClient
+
+	tokensMu sync.Mutex
+	tokens   map[string]oauth2Token // OAuth2 access tokens by oauth2CacheKey
 }
 
 // NewAuthManager creates a new authentication manager
@@ -46,22 +60,82 @@ func NewAuthManager() *AuthManager {
 
 // ApplyAuth applies authentication to a request based on the auth config
 func (am *AuthManager) ApplyAuth(req *Request, config *AuthConfig) error {
+	_, err := am.ApplyAuthWithWarnings(req, config)
+	return err
+}
+
+// ApplyAuthWithWarnings applies authentication like ApplyAuth, expanding
+// ${VAR} references from the process environment first. References to unset
+// environment variables expand to "" and are returned as warnings.
+func (am *AuthManager) ApplyAuthWithWarnings(req *Request, config *AuthConfig) ([]string, error) {
 	if config == nil || config.Type == AuthNone {
-		return nil
+		return nil, nil
 	}
 
+	config, missing := am.resolveEnvRefs(config)
+	var warnings []string
+	for _, name := range missing {
+		warnings = append(warnings, fmt.Sprintf("environment variable %s is not set", name))
+	}
+
+	var err error
 	switch config.Type {
 	case AuthAPIKey:
-		return am.applyAPIKeyAuth(req, config)
+		err = am.applyAPIKeyAuth(req, config)
 	case AuthBearer:
-		return am.applyBearerAuth(req, config)
+		err = am.applyBearerAuth(req, config)
 	case AuthBasic:
-		return am.applyBasicAuth(req, config)
+		err = am.applyBasicAuth(req, config)
 	case AuthCustom:
-		return am.applyCustomAuth(req, config)
+		err = am.applyCustomAuth(req, config)
+	case AuthOAuth2:
+		err = am.applyOAuth2Auth(req, config)
 	default:
-		return fmt.Errorf("unsupported authentication type: %s", config.Type)
+		err = fmt.Errorf("unsupported authentication type: %s", config.Type)
+	}
+
+	if err != nil && len(warnings) > 0 {
+		err = fmt.Errorf("%w (%s)", err, strings.Join(warnings, "; "))
+	}
+	return warnings, err
+}
+
+// envRefPattern matches ${NAME} references to process environment variables
+var envRefPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// resolveEnvRefs returns a copy of the auth config with ${VAR} references in
+// its credential fields expanded from the process environment, along with the
+// names of referenced variables that are unset
+func (am *AuthManager) resolveEnvRefs(config *AuthConfig) (*AuthConfig, []string) {
+	var missing []string
+	seen := make(map[string]bool)
+	expand := func(value string) string {
+		return envRefPattern.ReplaceAllStringFunc(value, func(ref string) string {
+			name := envRefPattern.FindStringSubmatch(ref)[1]
+			resolved, ok := os.LookupEnv(name)
+			if !ok && !seen[name] {
+				seen[name] = true
+				missing = append(missing, name)
+			}
+			return resolved
+		})
+	}
+
+	resolved := *config
+	resolved.APIKey = expand(config.APIKey)
+	resolved.Token = expand(config.Token)
+	resolved.Username = expand(config.Username)
+	resolved.Password = expand(config.Password)
+	resolved.ClientID = expand(config.ClientID)
+	resolved.ClientSecret = expand(config.ClientSecret)
+	if len(config.Custom) > 0 {
+		resolved.Custom = make(map[string]string, len(config.Custom))
+		for key, value := range config.Custom {
+			resolved.Custom[key] = expand(value)
+		}
 	}
+
+	return &resolved, missing
 }
 
 // applyAPIKeyAuth applies API key authentication
@@ -149,6 +223,110 @@ func (am *AuthManager) DeleteCredentials(service, username string) error {
 	return keyring.Delete(am.serviceName+"-"+service, username)
 }
 
+// keyringRefPrefix marks an auth field whose value is kept in the system
+// keyring under the field's account name, e.g. "keyring:token"
+const keyringRefPrefix = "keyring:"
+
+// StoreSecrets saves the secret fields of config in the system keyring under
+// service and returns a copy holding only references to them, safe to write
+// to disk. ${VAR} references are left as they are, since they hold no secret.
+func (am *AuthManager) StoreSecrets(service string, config *AuthConfig) (*AuthConfig, error) {
+	return mapSecrets(config, func(account, value string) (string, error) {
+		if envRefPattern.FindString(value) == value {
+			return value, nil
+		}
+		if err := am.StoreCredentials(service, account, value); err != nil {
+			return "", fmt.Errorf("failed to store %s in the keyring: %w", account, err)
+		}
+		return keyringRefPrefix + account, nil
+	})
+}
+
+// LoadSecrets returns a copy of config with its keyring references, as
+// written by StoreSecrets, replaced by the secrets they point to
+func (am *AuthManager) LoadSecrets(service string, config *AuthConfig) (*AuthConfig, error) {
+	return mapSecrets(config, func(account, value string) (string, error) {
+		ref, ok := strings.CutPrefix(value, keyringRefPrefix)
+		if !ok {
+			return value, nil
+		}
+		secret, err := am.GetCredentials(service, ref)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s from the keyring: %w", ref, err)
+		}
+		return secret, nil
+	})
+}
+
+// DeleteSecrets removes the keyring entries config refers to, except those
+// keep still refers to. Entries already gone are not an error.
+func (am *AuthManager) DeleteSecrets(service string, config, keep *AuthConfig) error {
+	kept := make(map[string]bool)
+	if keep != nil {
+		mapSecrets(keep, func(account, value string) (string, error) {
+			kept[value] = true
+			return value, nil
+		})
+	}
+
+	var firstErr error
+	mapSecrets(config, func(account, value string) (string, error) {
+		ref, ok := strings.CutPrefix(value, keyringRefPrefix)
+		if !ok || kept[value] {
+			return value, nil
+		}
+		if err := am.DeleteCredentials(service, ref); err != nil && err != keyring.ErrNotFound && firstErr == nil {
+			firstErr = fmt.Errorf("failed to delete %s from the keyring: %w", ref, err)
+		}
+		return value, nil
+	})
+	return firstErr
+}
+
+// mapSecrets returns a copy of config with each non-empty secret field,
+// including sensitive custom headers, replaced by fn(account, value), where
+// account names the field
+func mapSecrets(config *AuthConfig, fn func(account, value string) (string, error)) (*AuthConfig, error) {
+	if config == nil {
+		return nil, nil
+	}
+
+	mapped := *config
+	for _, field := range []struct {
+		account string
+		value   *string
+	}{
+		{"api_key", &mapped.APIKey},
+		{"token", &mapped.Token},
+		{"password", &mapped.Password},
+		{"client_secret", &mapped.ClientSecret},
+	} {
+		if *field.value == "" {
+			continue
+		}
+		value, err := fn(field.account, *field.value)
+		if err != nil {
+			return nil, err
+		}
+		*field.value = value
+	}
+
+	if len(config.Custom) > 0 {
+		mapped.Custom = make(map[string]string, len(config.Custom))
+		for key, value := range config.Custom {
+			if IsSensitiveName(key) && value != "" {
+				var err error
+				if value, err = fn("custom:"+key, value); err != nil {
+					return nil, err
+				}
+			}
+			mapped.Custom[key] = value
+		}
+	}
+
+	return &mapped, nil
+}
+
 // ListStoredServices returns a list of services with stored credentials
 func (am *AuthManager) ListStoredServices() ([]string, error) {
 	// Note: go-keyring doesn't provide a list function, so we'll need to track this separately
@@ -189,6 +367,17 @@ func (am *AuthManager) ValidateAuthConfig(config *AuthConfig) error {
 			return fmt.Errorf("custom headers are required")
 		}
 
+	case AuthOAuth2:
+		if config.TokenURL == "" {
+			return fmt.Errorf("token URL is required for OAuth2")
+		}
+		if !strings.HasPrefix(config.TokenURL, "http://") && !strings.HasPrefix(config.TokenURL, "https://") {
+			return fmt.Errorf("token URL must start with http:// or https://")
+		}
+		if config.ClientID == "" || config.ClientSecret == "" {
+			return fmt.Errorf("client ID and secret are required for OAuth2")
+		}
+
 	default:
 		return fmt.Errorf("unsupported authentication type: %s", config.Type)
 	}
@@ -204,6 +393,7 @@ func (am *AuthManager) GetAuthTypes() []AuthType {
 		AuthBearer,
 		AuthBasic,
 		AuthCustom,
+		AuthOAuth2,
 	}
 }
 
@@ -220,6 +410,8 @@ func (am *AuthManager) GetAuthTypeDescription(authType AuthType) string {
 		return "Basic Authentication (username/password)"
 	case AuthCustom:
 		return "Custom headers"
+	case AuthOAuth2:
+		return "OAuth2 client credentials (bearer token fetched automatically)"
 	default:
 		return "Unknown authentication type"
 	}
@@ -269,6 +461,12 @@ func (am *AuthManager) CreateAuthConfigFromInput(authType AuthType, inputs map[s
 			}
 		}
 
+	case AuthOAuth2:
+		config.TokenURL = strings.TrimSpace(inputs["token_url"])
+		config.ClientID = inputs["client_id"]
+		config.ClientSecret = inputs["client_secret"]
+		config.Scope = strings.TrimSpace(inputs["scope"])
+
 	default:
 		return nil, fmt.Errorf("unsupported authentication type: %s", authType)
 	}
@@ -294,6 +492,9 @@ func (am *AuthManager) MaskSensitiveData(config *AuthConfig) *AuthConfig {
 	if masked.Password != "" {
 		masked.Password = "********"
 	}
+	if masked.ClientSecret != "" {
+		masked.ClientSecret = "********"
+	}
 
 	// Mask custom headers that might contain sensitive data
 	if len(masked.Custom) > 0 {
@@ -311,8 +512,40 @@ func (am *AuthManager) MaskSensitiveData(config *AuthConfig) *AuthConfig {
 	return &masked
 }
 
+// StripSecrets returns a copy of the auth config with credential values removed,
+// keeping only the non-secret shape (type, key name, location, username)
+func StripSecrets(config *AuthConfig) *AuthConfig {
+	if config == nil {
+		return nil
+	}
+
+	stripped := *config
+	stripped.APIKey = ""
+	stripped.Token = ""
+	stripped.Password = ""
+	stripped.ClientSecret = ""
+
+	if len(config.Custom) > 0 {
+		stripped.Custom = make(map[string]string)
+		for key, value := range config.Custom {
+			if IsSensitiveName(key) {
+				value = ""
+			}
+			stripped.Custom[key] = value
+		}
+	}
+
+	return &stripped
+}
+
 // maskString masks a string showing only first and last few characters
 func (am *AuthManager) maskString(s string) string {
+	return maskSecret(s)
+}
+
+// maskSecret masks a secret, keeping its first and last three characters
+// when it is long enough that they don't give it away
+func maskSecret(s string) string {
 	if len(s) <= 8 {
 		return "****"
 	}
@@ -321,14 +554,20 @@ func (am *AuthManager) maskString(s string) string {
 
 // isSensitiveHeader checks if a header name typically contains sensitive data
 func (am *AuthManager) isSensitiveHeader(headerName string) bool {
+	return IsSensitiveName(headerName)
+}
+
+// IsSensitiveName checks if a header or variable name typically holds sensitive data
+func IsSensitiveName(name string) bool {
 	sensitive := []string{
 		"authorization", "x-api-key", "x-auth-token", "x-access-token",
-		"api-key", "auth-token", "access-token", "secret", "password",
+		"api-key", "api_key", "auth-token", "access-token", "token",
+		"secret", "password", "cookie",
 	}
 
-	headerLower := strings.ToLower(headerName)
+	nameLower := strings.ToLower(name)
 	for _, s := range sensitive {
-		if strings.Contains(headerLower, s) {
+		if strings.Contains(nameLower, s) {
 			return true
 		}
 	}
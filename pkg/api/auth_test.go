@@ -0,0 +1,159 @@
+package api
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+func TestResolveEnvRefs(t *testing.T) {
+	t.Setenv("ONIONCLI_TEST_TOKEN", "secret-token")
+	t.Setenv("ONIONCLI_TEST_USER", "alice")
+
+	am := NewAuthManager()
+	config := &AuthConfig{
+		Type:     AuthBasic,
+		Token:    "${ONIONCLI_TEST_TOKEN}",
+		Username: "${ONIONCLI_TEST_USER}@example",
+		Password: "pa$$word-${ONIONCLI_TEST_UNSET}-${ONIONCLI_TEST_UNSET}",
+		Custom:   map[string]string{"X-Token": "Bearer ${ONIONCLI_TEST_TOKEN}"},
+	}
+
+	resolved, missing := am.resolveEnvRefs(config)
+
+	if resolved.Token != "secret-token" {
+		t.Errorf("Expected token to expand, got %q", resolved.Token)
+	}
+	if resolved.Username != "alice@example" {
+		t.Errorf("Expected username to expand, got %q", resolved.Username)
+	}
+	if resolved.Password != "pa$$word--" {
+		t.Errorf("Expected unset references to expand to empty, got %q", resolved.Password)
+	}
+	if resolved.Custom["X-Token"] != "Bearer secret-token" {
+		t.Errorf("Expected custom header to expand, got %q", resolved.Custom["X-Token"])
+	}
+	if expected := []string{"ONIONCLI_TEST_UNSET"}; !reflect.DeepEqual(missing, expected) {
+		t.Errorf("Expected missing %v, got %v", expected, missing)
+	}
+
+	// The stored config keeps its references
+	if config.Token != "${ONIONCLI_TEST_TOKEN}" || config.Custom["X-Token"] != "Bearer ${ONIONCLI_TEST_TOKEN}" {
+		t.Errorf("Expected original config to be unchanged, got %+v", config)
+	}
+}
+
+func TestApplyAuthWithEnvRefs(t *testing.T) {
+	t.Setenv("ONIONCLI_TEST_TOKEN", "secret-token")
+	am := NewAuthManager()
+
+	req := NewRequest("GET", "http://example.onion")
+	warnings, err := am.ApplyAuthWithWarnings(req, &AuthConfig{Type: AuthBearer, Token: "${ONIONCLI_TEST_TOKEN}"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", warnings)
+	}
+	if got := req.Headers["Authorization"]; got != "Bearer secret-token" {
+		t.Errorf("Expected expanded bearer header, got %q", got)
+	}
+
+	req = NewRequest("GET", "http://example.onion")
+	warnings, err = am.ApplyAuthWithWarnings(req, &AuthConfig{Type: AuthCustom, Custom: map[string]string{"X-Key": "${ONIONCLI_TEST_UNSET}"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "ONIONCLI_TEST_UNSET") {
+		t.Errorf("Expected a warning naming the unset variable, got %v", warnings)
+	}
+
+	err = am.ApplyAuth(NewRequest("GET", "http://example.onion"), &AuthConfig{Type: AuthBearer, Token: "${ONIONCLI_TEST_UNSET}"})
+	if err == nil || !strings.Contains(err.Error(), "ONIONCLI_TEST_UNSET is not set") {
+		t.Errorf("Expected an error naming the unset variable, got %v", err)
+	}
+}
+
+func TestStoreAndLoadSecrets(t *testing.T) {
+	keyring.MockInit()
+	am := NewAuthManager()
+
+	config := &AuthConfig{
+		Type:     AuthBasic,
+		Username: "alice",
+		Password: "hunter2",
+		Custom:   map[string]string{"X-Auth-Token": "abc123", "X-Trace": "on"},
+	}
+	stored, err := am.StoreSecrets("env-1", config)
+	if err != nil {
+		t.Fatalf("StoreSecrets() error = %v", err)
+	}
+	if stored.Username != "alice" || stored.Password != "keyring:password" {
+		t.Errorf("stored basic auth = %q:%q, want alice:keyring:password", stored.Username, stored.Password)
+	}
+	if stored.Custom["X-Auth-Token"] != "keyring:custom:X-Auth-Token" || stored.Custom["X-Trace"] != "on" {
+		t.Errorf("stored custom headers = %v", stored.Custom)
+	}
+	if config.Password != "hunter2" || config.Custom["X-Auth-Token"] != "abc123" {
+		t.Errorf("StoreSecrets() modified its input: %+v", config)
+	}
+
+	loaded, err := am.LoadSecrets("env-1", stored)
+	if err != nil {
+		t.Fatalf("LoadSecrets() error = %v", err)
+	}
+	if !reflect.DeepEqual(loaded, config) {
+		t.Errorf("LoadSecrets() = %+v, want %+v", loaded, config)
+	}
+
+	if _, err := am.LoadSecrets("env-2", stored); err == nil {
+		t.Error("LoadSecrets() from another service should fail")
+	}
+}
+
+func TestStoreSecretsKeepsEnvRefs(t *testing.T) {
+	keyring.MockInit()
+	am := NewAuthManager()
+
+	stored, err := am.StoreSecrets("env-1", &AuthConfig{Type: AuthBearer, Token: "${ONIONCLI_TEST_TOKEN}"})
+	if err != nil {
+		t.Fatalf("StoreSecrets() error = %v", err)
+	}
+	if stored.Token != "${ONIONCLI_TEST_TOKEN}" {
+		t.Errorf("stored token = %q, want the ${VAR} reference kept", stored.Token)
+	}
+	if _, err := am.GetCredentials("env-1", "token"); err != keyring.ErrNotFound {
+		t.Errorf("keyring entry for an env ref: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestDeleteSecrets(t *testing.T) {
+	keyring.MockInit()
+	am := NewAuthManager()
+
+	old, err := am.StoreSecrets("env-1", &AuthConfig{Type: AuthAPIKey, APIKey: "old-key", Token: "old-token"})
+	if err != nil {
+		t.Fatalf("StoreSecrets() error = %v", err)
+	}
+	current, err := am.StoreSecrets("env-1", &AuthConfig{Type: AuthBearer, Token: "new-token"})
+	if err != nil {
+		t.Fatalf("StoreSecrets() error = %v", err)
+	}
+
+	if err := am.DeleteSecrets("env-1", old, current); err != nil {
+		t.Fatalf("DeleteSecrets() error = %v", err)
+	}
+	if _, err := am.GetCredentials("env-1", "api_key"); err != keyring.ErrNotFound {
+		t.Errorf("unused api_key: err = %v, want ErrNotFound", err)
+	}
+	if token, err := am.GetCredentials("env-1", "token"); err != nil || token != "new-token" {
+		t.Errorf("token still in use = %q, %v; want new-token", token, err)
+	}
+
+	// Deleting again finds nothing, which is not an error
+	if err := am.DeleteSecrets("env-1", old, nil); err != nil {
+		t.Errorf("DeleteSecrets() of missing entries error = %v", err)
+	}
+}
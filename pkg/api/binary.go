@@ -0,0 +1,111 @@
+package api
+
+import (
+	"mime"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// binaryThreshold is the share of a body's sniffed prefix that may be
+// invalid UTF-8 or control characters before the body counts as binary.
+// Text in a legacy encoding has the odd invalid byte; images and archives
+// are mostly made of them.
+const binaryThreshold = 0.1
+
+// binaryMediaTypes are the application/ types that are never text
+var binaryMediaTypes = map[string]bool{
+	"application/octet-stream":        true,
+	"application/pdf":                 true,
+	"application/zip":                 true,
+	"application/gzip":                true,
+	"application/x-gzip":              true,
+	"application/x-tar":               true,
+	"application/x-bzip2":             true,
+	"application/x-xz":                true,
+	"application/x-7z-compressed":     true,
+	"application/zstd":                true,
+	"application/wasm":                true,
+	"application/protobuf":            true,
+	"application/x-protobuf":          true,
+	"application/vnd.google.protobuf": true,
+	"application/msgpack":             true,
+	"application/x-msgpack":           true,
+	"application/cbor":                true,
+}
+
+// BodyBytes returns the body exactly as received, after content decoding
+// and any post-processing
+func (r *Response) BodyBytes() []byte {
+	if r.RawBody != nil {
+		return r.RawBody
+	}
+	return []byte(r.Body)
+}
+
+// IsBinary reports whether the body is not text and should not be written
+// to a terminal: the Content-Type (or the sniffed type) names an image,
+// audio, video, font or archive type, or the start of the body is mostly
+// invalid UTF-8 and control characters
+func (r *Response) IsBinary() bool {
+	if r.Body == "" {
+		return false
+	}
+
+	contentType, _ := r.ContentType()
+	if isBinaryMediaType(contentType) {
+		return true
+	}
+
+	body := r.BodyBytes()
+	if len(body) > sniffLength {
+		body = body[:sniffLength]
+	}
+	return looksBinary(body)
+}
+
+// isBinaryMediaType reports whether a Content-Type names a binary format.
+// SVG is XML, so it is text.
+func isBinaryMediaType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	if strings.HasSuffix(mediaType, "+xml") || strings.HasSuffix(mediaType, "+json") {
+		return false
+	}
+	for _, prefix := range []string{"image/", "audio/", "video/", "font/"} {
+		if strings.HasPrefix(mediaType, prefix) {
+			return true
+		}
+	}
+	return binaryMediaTypes[mediaType]
+}
+
+// looksBinary reports whether body holds a NUL byte or more than
+// binaryThreshold of invalid UTF-8 and non-whitespace control characters.
+// A rune cut off at the end of body is not counted.
+func looksBinary(body []byte) bool {
+	if len(body) == 0 {
+		return false
+	}
+
+	suspect := 0
+	for i := 0; i < len(body); {
+		r, size := utf8.DecodeRune(body[i:])
+		switch {
+		case r == 0:
+			return true
+		case r == utf8.RuneError && size == 1:
+			if !utf8.FullRune(body[i:]) {
+				i = len(body)
+				continue
+			}
+			suspect++
+		case unicode.IsControl(r) && !unicode.IsSpace(r):
+			suspect++
+		}
+		i += size
+	}
+	return float64(suspect) > binaryThreshold*float64(len(body))
+}
@@ -0,0 +1,38 @@
+package api
+
+import "testing"
+
+func TestIsBinary(t *testing.T) {
+	png := "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+	latin1 := "caf\xe9 cr\xe8me br\xfbl\xe9e, served with a long enough sentence around it"
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        bool
+	}{
+		{"image by content type", "image/png", png, true},
+		{"sniffed image", "", png, true},
+		{"gzip", "application/gzip", "\x1f\x8b\x08\x00", true},
+		{"mislabelled as text", "text/plain", "\x01\x02\x03\x04\x05\x06\x07\x08", true},
+		{"NUL byte", "application/json", "{\"a\":\x00}", true},
+		{"JSON", "application/json", `{"name":"café"}`, false},
+		{"SVG", "image/svg+xml", "<svg/>", false},
+		{"legacy encoding text", "text/plain", latin1, false},
+		{"text with tabs", "", "a\tb\r\nc", false},
+		{"empty", "application/octet-stream", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := &Response{Headers: map[string]string{}, Body: tt.body}
+			if tt.contentType != "" {
+				response.Headers["Content-Type"] = tt.contentType
+			}
+			if got := response.IsBinary(); got != tt.want {
+				t.Errorf("IsBinary() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
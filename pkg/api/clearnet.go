@@ -0,0 +1,95 @@
+package api
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// unroutableSuffixes are name suffixes that only resolve on local networks,
+// so Tor exits cannot reach them
+var unroutableSuffixes = []string{".local", ".localhost", ".internal", ".lan", ".home.arpa"}
+
+// ValidateClearnetURL checks that a non-onion URL can be sent through Tor. It
+// returns an error for an unsupported scheme or a malformed host, and warnings
+// for hosts that work badly over Tor: IP literals, which skip Tor's remote DNS
+// and are often blocked by exit policies, and private or local-only names.
+func ValidateClearnetURL(rawURL string) ([]string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL format: %w", err)
+	}
+
+	if !isSupportedScheme(u.Scheme) {
+		return nil, fmt.Errorf("unsupported scheme: %s (use http, https, ws or wss)", u.Scheme)
+	}
+
+	host := u.Hostname()
+	if host == "" {
+		return nil, fmt.Errorf("URL has no host")
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		warnings := []string{fmt.Sprintf("%s is an IP literal: it bypasses Tor's DNS resolution and many exits refuse direct IP connections - prefer a hostname", host)}
+		if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsUnspecified() {
+			warnings = append(warnings, fmt.Sprintf("%s is a private or local address and cannot be reached through Tor", host))
+		}
+		return warnings, nil
+	}
+
+	// Internationalized names are checked, and resolved by Tor, in their
+	// ASCII form, e.g. bücher.de as xn--bcher-kva.de
+	name, err := idna.Lookup.ToASCII(host)
+	if err != nil {
+		if !strings.Contains(host, "_") {
+			return nil, fmt.Errorf("invalid hostname %q: %w", host, err)
+		}
+		name = host // idna rejects underscores, which are only warned about below
+	}
+	if err := validateHostname(name); err != nil {
+		return nil, err
+	}
+
+	var warnings []string
+	if strings.Contains(name, "_") {
+		warnings = append(warnings, fmt.Sprintf("%s contains an underscore, which some resolvers reject in hostnames", host))
+	}
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	if !strings.Contains(name, ".") {
+		return append(warnings, fmt.Sprintf("%s is a single-label name and will not resolve through Tor", host)), nil
+	}
+	for _, suffix := range unroutableSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return append(warnings, fmt.Sprintf("%s is a local network name and cannot be reached through Tor", host)), nil
+		}
+	}
+	return warnings, nil
+}
+
+// validateHostname checks an ASCII DNS name: at most 253 characters of
+// dot-separated labels, each 1-63 letters, digits, hyphens or underscores,
+// not starting or ending with a hyphen. A trailing dot is allowed.
+func validateHostname(host string) error {
+	name := strings.TrimSuffix(host, ".")
+	if len(name) > 253 {
+		return fmt.Errorf("invalid hostname %q: longer than 253 characters", host)
+	}
+
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || len(label) > 63 {
+			return fmt.Errorf("invalid hostname %q: empty or overlong label", host)
+		}
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return fmt.Errorf("invalid hostname %q: label %q starts or ends with a hyphen", host, label)
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+				return fmt.Errorf("invalid hostname %q: unexpected character %q", host, c)
+			}
+		}
+	}
+	return nil
+}
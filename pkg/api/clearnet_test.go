@@ -0,0 +1,126 @@
+package api
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestValidateClearnetURL(t *testing.T) {
+	tests := []struct {
+		url          string
+		wantErr      bool
+		wantWarnings int
+	}{
+		{"https://example.com/api", false, 0},
+		{"http://api.example.com:8080/", false, 0},
+		{"https://example.com./", false, 0},
+		{"http://93.184.216.34/", false, 1},    // public IPv4 literal
+		{"http://[2606:2800::1]/", false, 1},   // public IPv6 literal
+		{"http://192.168.1.10/", false, 2},     // private IPv4 literal
+		{"http://127.0.0.1:8080/", false, 2},   // loopback
+		{"http://intranet/", false, 1},         // single label
+		{"http://printer.local/", false, 1},    // mDNS name
+		{"ftp://example.com/", true, 0},        // unsupported scheme
+		{"http:///path", true, 0},              // no host
+		{"http://-bad-.example.com/", true, 0}, // hyphen at label edge
+		{"http://exa_mple.com/", false, 1},     // underscore
+		{"https://bücher.de/", false, 0},       // internationalized name
+		{"http://exa!mple.com/", true, 0},      // invalid character
+		{"http://example..com/", true, 0},      // empty label
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			warnings, err := ValidateClearnetURL(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateClearnetURL(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			}
+			if len(warnings) != tt.wantWarnings {
+				t.Errorf("ValidateClearnetURL(%q) warnings = %v, want %d", tt.url, warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+// TestTorClientResolvesRemotely checks that hostnames reach the SOCKS5 proxy
+// as domain names rather than being resolved locally first
+func TestTorClientResolvesRemotely(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer listener.Close()
+
+	type target struct {
+		addressType byte
+		host        string
+		port        uint16
+	}
+	targets := make(chan target, 1)
+
+	// A minimal SOCKS5 proxy that records the CONNECT target and answers HTTP itself
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+
+		greeting := make([]byte, 2)
+		io.ReadFull(reader, greeting)
+		io.ReadFull(reader, make([]byte, greeting[1]))
+		conn.Write([]byte{5, 0}) // no authentication
+
+		header := make([]byte, 4)
+		io.ReadFull(reader, header)
+		var host string
+		switch header[3] {
+		case 3: // domain name
+			length, _ := reader.ReadByte()
+			name := make([]byte, length)
+			io.ReadFull(reader, name)
+			host = string(name)
+		case 1:
+			ip := make([]byte, 4)
+			io.ReadFull(reader, ip)
+			host = net.IP(ip).String()
+		case 4:
+			ip := make([]byte, 16)
+			io.ReadFull(reader, ip)
+			host = net.IP(ip).String()
+		}
+		port := make([]byte, 2)
+		io.ReadFull(reader, port)
+		targets <- target{header[3], host, binary.BigEndian.Uint16(port)}
+		conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+
+		if _, err := http.ReadRequest(reader); err == nil {
+			io.WriteString(conn, "HTTP/1.1 200 OK\r\nContent-Length: 2\r\nConnection: close\r\n\r\nok")
+		}
+	}()
+
+	client, err := NewClient(&ClientConfig{TorEnabled: true, TorProxy: listener.Addr().String(), Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	// .invalid never resolves locally, so this only works with remote DNS
+	resp, err := client.Send(NewRequest("GET", "http://service.example.invalid/"))
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if resp.Body != "ok" {
+		t.Errorf("Body = %q, want %q", resp.Body, "ok")
+	}
+
+	got := <-targets
+	if got.addressType != 3 || got.host != "service.example.invalid" || got.port != 80 {
+		t.Errorf("SOCKS5 CONNECT target = %+v, want domain name service.example.invalid:80", got)
+	}
+}
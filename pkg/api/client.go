@@ -2,6 +2,8 @@ package api
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -14,17 +16,69 @@ import (
 
 // Client represents an HTTP client with optional Tor proxy support
 type Client struct {
-	httpClient *http.Client
-	torEnabled bool
-	torProxy   string
-	timeout    time.Duration
+	httpClient     *http.Client
+	torEnabled     bool
+	torProxy       string
+	torProxySource string
+	timeout        time.Duration
+	pool           ConnectionPool
+	retryPolicy    RetryPolicy // used by Send and SendContext
+
+	controlPort     int
+	controlPassword string
+
+	interactiveRedirects bool
+	disableRedirects     bool
+	maxRedirects         int
+
+	jar *cookieJar // nil unless UseCookieJar
+
+	insecureSkipVerify bool
+	userAgent          string
 }
 
 // ClientConfig holds configuration for the API client
 type ClientConfig struct {
-	TorProxy   string        // Tor SOCKS5 proxy address (default: 127.0.0.1:9050)
-	TorEnabled bool          // Whether to route requests through Tor
-	Timeout    time.Duration // Request timeout (default: 30s)
+	TorProxy        string         // Tor SOCKS5 proxy address (default: 127.0.0.1:9050)
+	TorEnabled      bool           // Whether to route requests through Tor
+	Timeout         time.Duration  // Request timeout (default: 30s)
+	AutoDetectProxy bool           // Fall back to ALL_PROXY/SOCKS_PROXY if TorProxy is not reachable
+	Pool            ConnectionPool // Connection reuse over Tor (default: a new circuit stream per request)
+	MaxRetries      int            // Extra attempts after a retryable network/Tor failure (default: 0)
+	MaxRedirects    int            // Redirects followed before giving up with a RedirectError (default: 10)
+	ControlPort     int            // Tor control port on the proxy's host, used by NewCircuit (default: 9051)
+	ControlPassword string         // Control port password; empty uses cookie authentication
+
+	// Return 3xx responses instead of following them, so each hop can be
+	// inspected and followed with NextRedirect
+	InteractiveRedirects bool
+
+	// Return 3xx responses as the final response, like http.follow_redirects
+	// set to false. The zero value follows up to MaxRedirects.
+	DisableRedirects bool
+
+	// Keep cookies from Set-Cookie responses and send them with later
+	// requests to the same site, for the life of the client. The zero value
+	// is stateless.
+	UseCookieJar bool
+
+	// Accept any TLS certificate, e.g. a self-signed one on a test server.
+	// The zero value verifies certificates.
+	InsecureSkipVerify bool
+
+	// User-Agent sent with requests that don't set their own; empty leaves
+	// Go's default
+	UserAgent string
+}
+
+// ConnectionPool tunes connection reuse for the Tor transport. Reusing
+// connections speeds up collection and batch runs against a single onion
+// service, but lets that service link requests that arrive over the same
+// connection. The zero value keeps one connection per request.
+type ConnectionPool struct {
+	KeepAlives      bool // Reuse connections between requests
+	MaxIdleConns    int  // Idle connections kept across all hosts (0 = no limit); needs KeepAlives
+	MaxConnsPerHost int  // Concurrent connections per host (0 = no limit)
 }
 
 // DefaultConfig returns a default client configuration
@@ -43,13 +97,36 @@ func NewClient(config *ClientConfig) (*Client, error) {
 	}
 
 	client := &Client{
-		torEnabled: config.TorEnabled,
-		torProxy:   config.TorProxy,
-		timeout:    config.Timeout,
+		torEnabled:     config.TorEnabled,
+		torProxy:       config.TorProxy,
+		torProxySource: ProxySourceConfig,
+		timeout:        config.Timeout,
+		pool:           config.Pool,
+		retryPolicy:    RetryPolicy{MaxAttempts: config.MaxRetries + 1, BaseDelay: defaultRetryBackoff},
+
+		controlPort:     config.ControlPort,
+		controlPassword: config.ControlPassword,
+
+		interactiveRedirects: config.InteractiveRedirects,
+		disableRedirects:     config.DisableRedirects,
+		maxRedirects:         config.MaxRedirects,
+
+		insecureSkipVerify: config.InsecureSkipVerify,
+		userAgent:          config.UserAgent,
+	}
+	if client.maxRedirects <= 0 {
+		client.maxRedirects = defaultMaxRedirects
+	}
+	if config.UseCookieJar {
+		client.jar = newCookieJar()
+	}
+
+	if config.TorEnabled && config.AutoDetectProxy {
+		client.torProxy, client.torProxySource = ResolveTorProxy(config.TorProxy, true)
 	}
 
 	if config.TorEnabled {
-		httpClient, err := createTorClient(config.TorProxy, config.Timeout)
+		httpClient, err := createTorClient(client.torProxy, config.Timeout, config.Pool, nil)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create Tor client: %w", err)
 		}
@@ -59,24 +136,47 @@ func NewClient(config *ClientConfig) (*Client, error) {
 			Timeout: config.Timeout,
 		}
 	}
+	client.configureRedirects(client.httpClient)
+	client.configureCookies(client.httpClient)
+	client.configureTLS(client.httpClient)
 
 	return client, nil
 }
 
-// createTorClient creates an HTTP client configured to use Tor SOCKS5 proxy
-func createTorClient(torProxy string, timeout time.Duration) (*http.Client, error) {
-	// Create a SOCKS5 dialer
-	dialer, err := proxy.SOCKS5("tcp", torProxy, nil, proxy.Direct)
+// configureTLS turns off certificate verification on httpClient's transport
+// when the client was created with InsecureSkipVerify
+func (c *Client) configureTLS(httpClient *http.Client) {
+	if !c.insecureSkipVerify {
+		return
+	}
+	transport, ok := httpClient.Transport.(*http.Transport)
+	if !ok {
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+		httpClient.Transport = transport
+	}
+	transport.TLSClientConfig = c.tlsConfig()
+}
+
+// tlsConfig returns the TLS settings for connections the client makes
+func (c *Client) tlsConfig() *tls.Config {
+	return &tls.Config{InsecureSkipVerify: c.insecureSkipVerify}
+}
+
+// createTorClient creates an HTTP client configured to use Tor SOCKS5 proxy.
+// Tor isolates streams by SOCKS credentials, so passing unique auth gets a
+// fresh circuit.
+func createTorClient(torProxy string, timeout time.Duration, pool ConnectionPool, auth *proxy.Auth) (*http.Client, error) {
+	dialContext, err := torDialer(torProxy, auth)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create SOCKS5 dialer: %w", err)
+		return nil, err
 	}
 
 	// Create a custom transport with the SOCKS5 dialer
 	transport := &http.Transport{
-		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
-			return dialer.Dial(network, addr)
-		},
-		DisableKeepAlives: true, // Recommended for Tor
+		DialContext:       dialContext,
+		DisableKeepAlives: !pool.KeepAlives, // Recommended for Tor unless throughput matters more
+		MaxIdleConns:      pool.MaxIdleConns,
+		MaxConnsPerHost:   pool.MaxConnsPerHost,
 	}
 
 	return &http.Client{
@@ -85,7 +185,35 @@ func createTorClient(torProxy string, timeout time.Duration) (*http.Client, erro
 	}, nil
 }
 
-// IsOnionURL checks if a URL is a .onion address
+// torDialer returns a dial function that connects through the Tor SOCKS5
+// proxy. Callers hand it the unresolved "host:port", and the SOCKS5 dialer
+// sends hostnames to Tor as domain names, so DNS is resolved by Tor (socks5h
+// semantics) and never leaks to the local resolver.
+func torDialer(torProxy string, auth *proxy.Auth) (func(ctx context.Context, network, addr string) (net.Conn, error), error) {
+	dialer, err := proxy.SOCKS5("tcp", torProxy, auth, proxy.Direct)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SOCKS5 dialer: %w", err)
+	}
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if contextDialer, ok := dialer.(proxy.ContextDialer); ok {
+			return contextDialer.DialContext(ctx, network, addr)
+		}
+		return dialer.Dial(network, addr)
+	}, nil
+}
+
+// ErrV2OnionAddress is returned by ValidateOnionURL for 16-character v2
+// onion addresses. Tor dropped v2 onion services in 0.4.6, so they can
+// never be reached.
+var ErrV2OnionAddress = errors.New("v2 onion addresses are no longer supported")
+
+// v2OnionPattern matches a deprecated 16-character v2 onion host
+var v2OnionPattern = regexp.MustCompile(`^[a-z2-7]{16}\.onion$`)
+
+// IsOnionURL checks if a URL is a .onion address. It still recognises v2
+// addresses, so they are routed and reported as onions; ValidateOnionURL
+// rejects them.
 func IsOnionURL(rawURL string) bool {
 	u, err := url.Parse(rawURL)
 	if err != nil {
@@ -104,14 +232,18 @@ func ValidateOnionURL(rawURL string) error {
 		return fmt.Errorf("invalid URL format: %w", err)
 	}
 
-	if u.Scheme != "http" && u.Scheme != "https" {
-		return fmt.Errorf("unsupported scheme: %s (use http or https)", u.Scheme)
+	if !isSupportedScheme(u.Scheme) {
+		return fmt.Errorf("unsupported scheme: %s (use http, https, ws or wss)", u.Scheme)
 	}
 
 	if !IsOnionURL(rawURL) {
 		return fmt.Errorf("not a valid .onion address: %s", u.Host)
 	}
 
+	if v2OnionPattern.MatchString(u.Host) {
+		return fmt.Errorf("%w: %s is a 16-character v2 address, only 56-character v3 addresses work", ErrV2OnionAddress, u.Host)
+	}
+
 	return nil
 }
 
@@ -124,7 +256,8 @@ func (c *Client) TestTorConnection() error {
 	// Try to connect to the Tor proxy
 	conn, err := net.DialTimeout("tcp", c.torProxy, 5*time.Second)
 	if err != nil {
-		return fmt.Errorf("cannot connect to Tor proxy at %s: %w (is Tor running?)", c.torProxy, err)
+		return fmt.Errorf("cannot connect to Tor proxy at %s (%s): %w (is Tor running?)",
+			c.torProxy, describeProxySource(c.torProxySource), err)
 	}
 	conn.Close()
 
@@ -136,11 +269,32 @@ func (c *Client) GetHTTPClient() *http.Client {
 	return c.httpClient
 }
 
+// GetTorProxy returns the Tor proxy address in use
+func (c *Client) GetTorProxy() string {
+	return c.torProxy
+}
+
+// GetTorProxySource describes where the Tor proxy address came from,
+// e.g. "from config" or "using ALL_PROXY"
+func (c *Client) GetTorProxySource() string {
+	return describeProxySource(c.torProxySource)
+}
+
+// GetTimeout returns the client's default request timeout
+func (c *Client) GetTimeout() time.Duration {
+	return c.timeout
+}
+
 // IsTorEnabled returns whether Tor routing is enabled
 func (c *Client) IsTorEnabled() bool {
 	return c.torEnabled
 }
 
+// RetriesEnabled returns whether failed requests are retried
+func (c *Client) RetriesEnabled() bool {
+	return c.retryPolicy.MaxAttempts > 1
+}
+
 // SetTorEnabled enables or disables Tor routing
 func (c *Client) SetTorEnabled(enabled bool) error {
 	if c.torEnabled == enabled {
@@ -154,6 +308,12 @@ func (c *Client) SetTorEnabled(enabled bool) error {
 		TorProxy:   c.torProxy,
 		TorEnabled: enabled,
 		Timeout:    c.timeout,
+		Pool:       c.pool,
+		MaxRetries: c.retryPolicy.MaxAttempts - 1,
+
+		InteractiveRedirects: c.interactiveRedirects,
+		DisableRedirects:     c.disableRedirects,
+		MaxRedirects:         c.maxRedirects,
 	}
 
 	newClient, err := NewClient(config)
@@ -162,5 +322,6 @@ func (c *Client) SetTorEnabled(enabled bool) error {
 	}
 
 	c.httpClient = newClient.httpClient
+	c.configureCookies(c.httpClient)
 	return nil
 }
@@ -1,6 +1,11 @@
 package api
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -46,12 +51,13 @@ func TestValidateOnionURL(t *testing.T) {
 	tests := []struct {
 		url       string
 		shouldErr bool
+		v2        bool
 	}{
-		{"http://3g2upl4pq6kufc4m.onion", false},
-		{"https://facebookwkhpilnemxj7asaniu7vnjjbiltxjqhye3mhbshg7kx5tfyd.onion", false},
-		{"ftp://3g2upl4pq6kufc4m.onion", true}, // Invalid scheme
-		{"http://google.com", true},            // Not .onion
-		{"invalid-url", true},                  // Invalid URL
+		{"https://facebookwkhpilnemxj7asaniu7vnjjbiltxjqhye3mhbshg7kx5tfyd.onion", false, false},
+		{"http://3g2upl4pq6kufc4m.onion", true, true},                                         // Deprecated v2
+		{"ftp://facebookwkhpilnemxj7asaniu7vnjjbiltxjqhye3mhbshg7kx5tfyd.onion", true, false}, // Invalid scheme
+		{"http://google.com", true, false},                                                    // Not .onion
+		{"invalid-url", true, false},                                                          // Invalid URL
 	}
 
 	for _, test := range tests {
@@ -62,6 +68,30 @@ func TestValidateOnionURL(t *testing.T) {
 		if !test.shouldErr && err != nil {
 			t.Errorf("ValidateOnionURL(%s) should not have returned an error: %v", test.url, err)
 		}
+		if got := errors.Is(err, ErrV2OnionAddress); got != test.v2 {
+			t.Errorf("ValidateOnionURL(%s) v2 error = %v, expected %v", test.url, got, test.v2)
+		}
+	}
+}
+
+func TestSendRejectsV2Onion(t *testing.T) {
+	client, err := NewClient(&ClientConfig{TorEnabled: false, Timeout: 10 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	url := "http://3g2upl4pq6kufc4m.onion"
+	_, err = client.Send(NewRequest("GET", url))
+	if !errors.Is(err, ErrV2OnionAddress) {
+		t.Fatalf("Expected ErrV2OnionAddress even with Tor disabled, got %v", err)
+	}
+
+	diag := NewErrorAnalyzer().AnalyzeError(err, url)
+	if diag.Type != ErrorTypeValidation {
+		t.Errorf("Expected validation diagnostic, got %s", diag.Type)
+	}
+	if len(diag.Suggestions) == 0 {
+		t.Error("Expected a suggestion for the v2 address")
 	}
 }
 
@@ -136,3 +166,268 @@ func TestRequestValidation(t *testing.T) {
 		t.Error("Request with invalid JSON should return error")
 	}
 }
+
+func TestValidateJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string // start of the error, empty for valid JSON
+	}{
+		{"valid", "{\"a\": [1, 2]}", ""},
+		{"surrounding whitespace", "\n  [1]\n", ""},
+		{"trailing comma", "{\n  \"a\": 1,\n}", "line 3, column 1: invalid character '}'"},
+		{"trailing text", `{"a":1} x`, "line 1, column 9: invalid character 'x'"},
+		{"column counts runes", `{"é": x}`, "line 1, column 7: invalid character 'x'"},
+		{"truncated", "[1,", "line 1, column 3: unexpected end of JSON input"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateJSON(tt.body)
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("ValidateJSON() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("ValidateJSON() error = %v, want %q...", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestResponseBodyHash(t *testing.T) {
+	resp := &Response{Body: `{"id": 1}`}
+	hash := resp.BodyHash()
+
+	if len(hash) != 64 {
+		t.Fatalf("Expected 64 character SHA-256 hex digest, got %d characters", len(hash))
+	}
+
+	// Hash must be stable across calls and across responses with the same body
+	if resp.BodyHash() != hash {
+		t.Error("Expected BodyHash to be stable across calls")
+	}
+
+	same := &Response{Body: `{"id": 1}`}
+	if !resp.SameBody(same) {
+		t.Error("Expected responses with identical bodies to match")
+	}
+
+	different := &Response{Body: `{"id": 2}`}
+	if resp.SameBody(different) {
+		t.Error("Expected responses with different bodies not to match")
+	}
+
+	// Known digest of the empty body
+	empty := &Response{}
+	if empty.BodyHash() != "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" {
+		t.Errorf("Unexpected hash for empty body: %s", empty.BodyHash())
+	}
+
+	if len(resp.ShortBodyHash()) != 12 {
+		t.Errorf("Expected 12 character short hash, got %q", resp.ShortBodyHash())
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name               string
+		resp               *Response
+		err                error
+		treatNon2xxAsError bool
+		expected           int
+	}{
+		{"success", &Response{StatusCode: 200}, nil, false, ExitSuccess},
+		{"success strict", &Response{StatusCode: 204}, nil, true, ExitSuccess},
+		{"not found lenient", &Response{StatusCode: 404}, nil, false, ExitSuccess},
+		{"not found strict", &Response{StatusCode: 404}, nil, true, ExitHTTPError},
+		{"server error strict", &Response{StatusCode: 503}, nil, true, ExitHTTPError},
+		{"redirect strict", &Response{StatusCode: 302}, nil, true, ExitHTTPError},
+		{"send error", nil, errors.New("connection refused"), false, ExitRequestError},
+	}
+
+	for _, test := range tests {
+		result := ExitCode(test.resp, test.err, test.treatNon2xxAsError)
+		if result != test.expected {
+			t.Errorf("%s: ExitCode() = %d, expected %d", test.name, result, test.expected)
+		}
+	}
+}
+
+func TestAnalyzeResponse(t *testing.T) {
+	analyzer := NewErrorAnalyzer()
+
+	if diag := analyzer.AnalyzeResponse(&Response{StatusCode: 200, Status: "200 OK"}, "http://example.com"); diag != nil {
+		t.Errorf("Expected no diagnostic for 2xx response, got %v", diag)
+	}
+
+	diag := analyzer.AnalyzeResponse(&Response{StatusCode: 401, Status: "401 Unauthorized"}, "http://example.com")
+	if diag == nil || diag.Type != ErrorTypeAuth || diag.StatusCode != 401 {
+		t.Errorf("Expected auth diagnostic for 401, got %+v", diag)
+	}
+
+	diag = analyzer.AnalyzeResponse(&Response{StatusCode: 500, Status: "500 Internal Server Error"}, "http://example.com")
+	if diag == nil || diag.Type != ErrorTypeHTTP {
+		t.Errorf("Expected HTTP diagnostic for 500, got %+v", diag)
+	}
+}
+
+func TestSendContextCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client, err := NewClient(&ClientConfig{TorEnabled: false, Timeout: 10 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = client.SendContext(ctx, NewRequest("GET", server.URL))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestRequestTimeoutOverride(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(200 * time.Millisecond):
+			w.Write([]byte("ok"))
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	t.Run("shorter than the client timeout", func(t *testing.T) {
+		client, err := NewClient(&ClientConfig{TorEnabled: false, Timeout: 10 * time.Second})
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		req := NewRequest("GET", server.URL)
+		req.Timeout = 50 * time.Millisecond
+		start := time.Now()
+		_, err = client.Send(req)
+		if err == nil {
+			t.Fatal("Expected the request timeout to abort the request")
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Request took %v, want it aborted after about %v", elapsed, req.Timeout)
+		}
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+		}
+		if diagnostic := NewErrorAnalyzer().AnalyzeError(err, server.URL); diagnostic.Type != ErrorTypeTimeout {
+			t.Errorf("Error type = %s, want %s", diagnostic.Type, ErrorTypeTimeout)
+		}
+	})
+
+	t.Run("longer than the client timeout", func(t *testing.T) {
+		client, err := NewClient(&ClientConfig{TorEnabled: false, Timeout: 50 * time.Millisecond})
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+
+		req := NewRequest("GET", server.URL)
+		req.Timeout = 5 * time.Second
+		resp, err := client.Send(req)
+		if err != nil {
+			t.Fatalf("Expected the longer request timeout to apply, got %v", err)
+		}
+		if resp.Body != "ok" {
+			t.Errorf("Body = %q, want %q", resp.Body, "ok")
+		}
+
+		// Without an override the client default still applies
+		if _, err := client.Send(NewRequest("GET", server.URL)); err == nil {
+			t.Error("Expected the client timeout to apply when the request has none")
+		}
+	})
+}
+
+func TestTorClientConnectionPool(t *testing.T) {
+	tests := []struct {
+		name string
+		pool ConnectionPool
+	}{
+		{name: "default keeps one connection per request", pool: ConnectionPool{}},
+		{name: "tuned pool", pool: ConnectionPool{KeepAlives: true, MaxIdleConns: 20, MaxConnsPerHost: 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(&ClientConfig{
+				TorProxy:   "127.0.0.1:9050",
+				TorEnabled: true,
+				Timeout:    10 * time.Second,
+				Pool:       tt.pool,
+			})
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+
+			transport, ok := client.httpClient.Transport.(*http.Transport)
+			if !ok {
+				t.Fatalf("Expected *http.Transport, got %T", client.httpClient.Transport)
+			}
+			if transport.DisableKeepAlives != !tt.pool.KeepAlives {
+				t.Errorf("DisableKeepAlives = %v, want %v", transport.DisableKeepAlives, !tt.pool.KeepAlives)
+			}
+			if transport.MaxIdleConns != tt.pool.MaxIdleConns {
+				t.Errorf("MaxIdleConns = %d, want %d", transport.MaxIdleConns, tt.pool.MaxIdleConns)
+			}
+			if transport.MaxConnsPerHost != tt.pool.MaxConnsPerHost {
+				t.Errorf("MaxConnsPerHost = %d, want %d", transport.MaxConnsPerHost, tt.pool.MaxConnsPerHost)
+			}
+
+			// Toggling Tor must not drop the pool settings
+			if err := client.SetTorEnabled(false); err != nil {
+				t.Fatalf("SetTorEnabled(false) failed: %v", err)
+			}
+			if err := client.SetTorEnabled(true); err != nil {
+				t.Fatalf("SetTorEnabled(true) failed: %v", err)
+			}
+			transport = client.httpClient.Transport.(*http.Transport)
+			if transport.MaxConnsPerHost != tt.pool.MaxConnsPerHost {
+				t.Errorf("After toggling Tor, MaxConnsPerHost = %d, want %d", transport.MaxConnsPerHost, tt.pool.MaxConnsPerHost)
+			}
+		})
+	}
+}
+
+func TestClientUserAgent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.UserAgent()))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(&ClientConfig{TorEnabled: false, Timeout: 10 * time.Second, UserAgent: "onioncli-test"})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	resp, err := client.Send(NewRequest("GET", server.URL))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if resp.Body != "onioncli-test" {
+		t.Errorf("User-Agent = %q, want the configured one", resp.Body)
+	}
+
+	// A header on the request wins
+	req := NewRequest("GET", server.URL)
+	req.Headers["User-Agent"] = "custom"
+	resp, err = client.Send(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if resp.Body != "custom" {
+		t.Errorf("User-Agent = %q, want the request's own", resp.Body)
+	}
+}
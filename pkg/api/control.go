@@ -0,0 +1,195 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// controlTimeout bounds a whole control-port exchange
+const controlTimeout = 5 * time.Second
+
+// controlConn is an open connection to Tor's control port
+type controlConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// dialControl connects to the control port
+func (c *Client) dialControl(ctx context.Context) (*controlConn, error) {
+	dialer := net.Dialer{Timeout: controlTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", c.ControlAddress())
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Now().Add(controlTimeout))
+	return &controlConn{conn: conn, reader: bufio.NewReader(conn)}, nil
+}
+
+// command sends one command and returns its reply lines
+func (cc *controlConn) command(command string) ([]string, error) {
+	if _, err := fmt.Fprintf(cc.conn, "%s\r\n", command); err != nil {
+		return nil, err
+	}
+	return readControlReply(cc.reader)
+}
+
+// Close closes the connection
+func (cc *controlConn) Close() error {
+	return cc.conn.Close()
+}
+
+// readControlReply reads one control-port reply up to its final line
+// ("250 OK"), returning the lines without their status code. A status other
+// than 250 is returned as an error.
+func readControlReply(reader *bufio.Reader) ([]string, error) {
+	var lines []string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return lines, fmt.Errorf("incomplete control port reply: %w", err)
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if len(line) < 4 {
+			return lines, fmt.Errorf("unexpected control port reply %q", line)
+		}
+		if !strings.HasPrefix(line, "250") {
+			return lines, fmt.Errorf("control port replied %q", line)
+		}
+		lines = append(lines, line[4:])
+		if line[3] == ' ' {
+			return lines, nil
+		}
+	}
+}
+
+// protocolInfo is the part of a PROTOCOLINFO reply needed to authenticate
+type protocolInfo struct {
+	version     string
+	authMethods []string
+	cookieFile  string
+}
+
+// parseProtocolInfo reads the Tor version, auth methods and cookie file
+// from PROTOCOLINFO reply lines such as
+// `AUTH METHODS=COOKIE,SAFECOOKIE COOKIEFILE="/run/tor/control.authcookie"`
+func parseProtocolInfo(lines []string) protocolInfo {
+	var info protocolInfo
+	for _, line := range lines {
+		if rest, ok := strings.CutPrefix(line, "VERSION Tor="); ok {
+			info.version, _ = strconv.Unquote(rest)
+		}
+		if rest, ok := strings.CutPrefix(line, "AUTH "); ok {
+			if methods, ok := strings.CutPrefix(rest, "METHODS="); ok {
+				methods, cookie, _ := strings.Cut(methods, " ")
+				info.authMethods = strings.Split(methods, ",")
+				if file, ok := strings.CutPrefix(cookie, "COOKIEFILE="); ok {
+					info.cookieFile, _ = strconv.Unquote(file)
+				}
+			}
+		}
+	}
+	return info
+}
+
+// hasMethod reports whether Tor accepts the auth method
+func (info protocolInfo) hasMethod(method string) bool {
+	for _, m := range info.authMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// authenticateCommand picks how to authenticate: the configured password,
+// no authentication if Tor allows it, or the contents of the cookie file
+func (info protocolInfo) authenticateCommand(password string, readFile func(string) ([]byte, error)) (string, error) {
+	switch {
+	case password != "":
+		return "AUTHENTICATE " + strconv.Quote(password), nil
+	case info.hasMethod("NULL"):
+		return "AUTHENTICATE", nil
+	case info.hasMethod("COOKIE") && info.cookieFile != "":
+		cookie, err := readFile(info.cookieFile)
+		if err != nil {
+			return "", fmt.Errorf("cannot read the control auth cookie: %w", err)
+		}
+		return "AUTHENTICATE " + hex.EncodeToString(cookie), nil
+	case info.hasMethod("HASHEDPASSWORD"):
+		return "", fmt.Errorf("Tor's control port requires a password")
+	default:
+		return "", fmt.Errorf("no supported control port authentication method (Tor offers %s)",
+			strings.Join(info.authMethods, ", "))
+	}
+}
+
+// NewCircuit asks Tor for new circuits by sending SIGNAL NEWNYM on the
+// control port, authenticating with the configured password or Tor's auth
+// cookie. Later requests, including ones over pooled connections, use the
+// new circuits. Failures are returned as a Tor DiagnosticError.
+func (c *Client) NewCircuit() error {
+	return c.NewCircuitContext(context.Background())
+}
+
+// NewCircuitContext is NewCircuit with a context for cancellation
+func (c *Client) NewCircuitContext(ctx context.Context) error {
+	cc, err := c.dialControl(ctx)
+	if err != nil {
+		return controlError(fmt.Sprintf("Tor control port unreachable at %s: %v", c.ControlAddress(), err), err,
+			"Enable the control port in torrc: ControlPort "+strconv.Itoa(c.controlPortNumber()),
+			"Enable CookieAuthentication 1, or set HashedControlPassword and tor.control_password in the config",
+			"Restart Tor after changing torrc",
+			"If Tor's control port is not on the default port, set tor.control_port")
+	}
+	defer cc.Close()
+
+	lines, err := cc.command("PROTOCOLINFO 1")
+	if err != nil {
+		return controlError(fmt.Sprintf("Tor control port did not answer PROTOCOLINFO: %v", err), err,
+			"Check that "+c.ControlAddress()+" is Tor's control port and not another service")
+	}
+
+	authenticate, err := parseProtocolInfo(lines).authenticateCommand(c.controlPassword, os.ReadFile)
+	if err == nil {
+		_, err = cc.command(authenticate)
+	}
+	if err != nil {
+		return controlError(fmt.Sprintf("Tor control port authentication failed: %v", err), err,
+			"Set tor.control_password to the password hashed in torrc's HashedControlPassword",
+			"With CookieAuthentication 1, make sure your user can read Tor's auth cookie (e.g. join the debian-tor group)")
+	}
+
+	if _, err := cc.command("SIGNAL NEWNYM"); err != nil {
+		return controlError(fmt.Sprintf("Tor refused SIGNAL NEWNYM: %v", err), err,
+			"The control user may lack permission to send signals")
+	}
+
+	// Pooled connections stay on their old circuit, so drop them
+	c.httpClient.CloseIdleConnections()
+	return nil
+}
+
+// controlError wraps a control-port failure as a Tor DiagnosticError
+func controlError(message string, cause error, suggestions ...string) *DiagnosticError {
+	return &DiagnosticError{
+		Type:        ErrorTypeTor,
+		Message:     message,
+		Cause:       cause,
+		Suggestions: suggestions,
+	}
+}
+
+// controlPortNumber returns the configured control port or Tor's default
+func (c *Client) controlPortNumber() int {
+	if c.controlPort > 0 {
+		return c.controlPort
+	}
+	return defaultControlPort
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestAuthenticateCommand(t *testing.T) {
+	readCookie := func(path string) ([]byte, error) {
+		if path != "/run/tor/control.authcookie" {
+			return nil, errors.New("no such file")
+		}
+		return []byte{0xde, 0xad, 0xbe, 0xef}, nil
+	}
+
+	tests := []struct {
+		name     string
+		reply    string
+		password string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "password",
+			reply:    "AUTH METHODS=HASHEDPASSWORD",
+			password: "s3cret",
+			want:     `AUTHENTICATE "s3cret"`,
+		},
+		{name: "no authentication", reply: "AUTH METHODS=NULL", want: "AUTHENTICATE"},
+		{
+			name:  "cookie",
+			reply: `AUTH METHODS=COOKIE,SAFECOOKIE COOKIEFILE="/run/tor/control.authcookie"`,
+			want:  "AUTHENTICATE deadbeef",
+		},
+		{name: "unreadable cookie", reply: `AUTH METHODS=COOKIE COOKIEFILE="/missing"`, wantErr: true},
+		{name: "password not configured", reply: "AUTH METHODS=HASHEDPASSWORD", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := parseProtocolInfo([]string{"PROTOCOLINFO 1", tt.reply})
+			got, err := info.authenticateCommand(tt.password, readCookie)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("authenticateCommand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("authenticateCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeControlPort answers PROTOCOLINFO with NULL auth, AUTHENTICATE, and
+// replies to SIGNAL with signalReply, recording the commands it received
+func fakeControlPort(t *testing.T, signalReply string) (port int, commands chan []string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	commands = make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var received []string
+		reader := bufio.NewReader(conn)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				commands <- received
+				return
+			}
+			command := strings.TrimSpace(line)
+			received = append(received, command)
+			switch {
+			case strings.HasPrefix(command, "PROTOCOLINFO"):
+				conn.Write([]byte("250-PROTOCOLINFO 1\r\n250-AUTH METHODS=NULL\r\n250-VERSION Tor=\"0.4.8.9\"\r\n250 OK\r\n"))
+			case strings.HasPrefix(command, "SIGNAL"):
+				conn.Write([]byte(signalReply + "\r\n"))
+			default:
+				conn.Write([]byte("250 OK\r\n"))
+			}
+		}
+	}()
+	return listener.Addr().(*net.TCPAddr).Port, commands
+}
+
+func TestNewCircuit(t *testing.T) {
+	port, commands := fakeControlPort(t, "250 OK")
+	client, err := NewClient(&ClientConfig{TorProxy: "127.0.0.1:9050", ControlPort: port})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	if err := client.NewCircuit(); err != nil {
+		t.Fatalf("NewCircuit() error = %v", err)
+	}
+	if got := strings.Join(<-commands, ", "); got != "PROTOCOLINFO 1, AUTHENTICATE, SIGNAL NEWNYM" {
+		t.Errorf("Commands = %s", got)
+	}
+}
+
+func TestNewCircuitErrors(t *testing.T) {
+	port, _ := fakeControlPort(t, "552 Unrecognized signal")
+	client, _ := NewClient(&ClientConfig{TorProxy: "127.0.0.1:9050", ControlPort: port})
+	err := client.NewCircuit()
+	var diagErr *DiagnosticError
+	if !errors.As(err, &diagErr) || diagErr.Type != ErrorTypeTor {
+		t.Fatalf("NewCircuit() error = %v, want a Tor DiagnosticError", err)
+	}
+	if !strings.Contains(diagErr.Message, "NEWNYM") {
+		t.Errorf("Message = %q, want it to mention NEWNYM", diagErr.Message)
+	}
+
+	// Nothing listens on a closed listener's port
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	closedPort := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	client, _ = NewClient(&ClientConfig{TorProxy: "127.0.0.1:9050", ControlPort: closedPort})
+	err = client.NewCircuit()
+	if !errors.As(err, &diagErr) || diagErr.Type != ErrorTypeTor {
+		t.Fatalf("NewCircuit() error = %v, want a Tor DiagnosticError", err)
+	}
+	if len(diagErr.Suggestions) == 0 || !strings.Contains(diagErr.Suggestions[0], "ControlPort") {
+		t.Errorf("Suggestions = %v, want torrc ControlPort advice first", diagErr.Suggestions)
+	}
+}
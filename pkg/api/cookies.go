@@ -0,0 +1,119 @@
+package api
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"sort"
+	"sync"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// HostCookies is the cookies the jar would send to one host
+type HostCookies struct {
+	Host    string
+	Cookies []*http.Cookie // name and value only; the jar does not expose the rest
+}
+
+// cookieJar is a cookiejar.Jar that remembers where cookies were set, since
+// the standard jar cannot list what it holds. The public suffix list keeps
+// cookies to the site that set them: "onion" is a public suffix, so one
+// onion service cannot set a cookie for every other.
+type cookieJar struct {
+	*cookiejar.Jar
+
+	mu   sync.Mutex
+	urls map[string]*url.URL // where cookies were set, by scheme, host and path
+}
+
+// newCookieJar returns an empty jar
+func newCookieJar() *cookieJar {
+	// cookiejar.New only fails on invalid options
+	jar, _ := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+	return &cookieJar{Jar: jar, urls: make(map[string]*url.URL)}
+}
+
+// SetCookies stores cookies from a response to u and remembers u
+func (j *cookieJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.Jar.SetCookies(u, cookies)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	where := &url.URL{Scheme: u.Scheme, Host: u.Host, Path: u.Path}
+	j.urls[where.String()] = where
+}
+
+// list returns the cookies held for each host cookies were set by, sorted
+// by host then name. Expired cookies are left out.
+func (j *cookieJar) list() []HostCookies {
+	j.mu.Lock()
+	urls := make([]*url.URL, 0, len(j.urls))
+	for _, u := range j.urls {
+		urls = append(urls, u)
+	}
+	j.mu.Unlock()
+
+	byHost := make(map[string]map[string]*http.Cookie)
+	for _, u := range urls {
+		for _, cookie := range j.Cookies(u) {
+			if byHost[u.Host] == nil {
+				byHost[u.Host] = make(map[string]*http.Cookie)
+			}
+			byHost[u.Host][cookie.Name] = cookie
+		}
+	}
+
+	hosts := make([]HostCookies, 0, len(byHost))
+	for host, cookies := range byHost {
+		entry := HostCookies{Host: host}
+		for _, cookie := range cookies {
+			entry.Cookies = append(entry.Cookies, cookie)
+		}
+		sort.Slice(entry.Cookies, func(a, b int) bool { return entry.Cookies[a].Name < entry.Cookies[b].Name })
+		hosts = append(hosts, entry)
+	}
+	sort.Slice(hosts, func(a, b int) bool { return hosts[a].Host < hosts[b].Host })
+	return hosts
+}
+
+// configureCookies gives httpClient the client's cookie jar, if it has one
+func (c *Client) configureCookies(httpClient *http.Client) {
+	if c.jar != nil {
+		httpClient.Jar = c.jar
+	}
+}
+
+// CookieJarEnabled reports whether the client keeps cookies between requests
+func (c *Client) CookieJarEnabled() bool {
+	return c.jar != nil
+}
+
+// Cookies returns the cookies the client holds, by host. It returns nil when
+// the cookie jar is disabled.
+func (c *Client) Cookies() []HostCookies {
+	if c.jar == nil {
+		return nil
+	}
+	return c.jar.list()
+}
+
+// KeepCookies makes the client use previous's cookie jar, so cookies
+// survive rebuilding the client with new settings. It does nothing unless
+// both clients keep cookies.
+func (c *Client) KeepCookies(previous *Client) {
+	if c.jar == nil || previous == nil || previous.jar == nil {
+		return
+	}
+	c.jar = previous.jar
+	c.httpClient.Jar = c.jar
+}
+
+// ClearCookies forgets every cookie the client holds
+func (c *Client) ClearCookies() {
+	if c.jar == nil {
+		return
+	}
+	c.jar = newCookieJar()
+	c.httpClient.Jar = c.jar
+}
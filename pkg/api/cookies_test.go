@@ -0,0 +1,103 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCookieJar(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/"})
+			return
+		}
+		if cookie, err := r.Cookie("session"); err == nil {
+			w.Write([]byte(cookie.Value))
+		}
+	}))
+	defer server.Close()
+
+	send := func(t *testing.T, client *Client, url string) string {
+		t.Helper()
+		resp, err := client.Send(NewRequest("GET", url))
+		if err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+		return resp.Body
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		client, err := NewClient(&ClientConfig{TorEnabled: false, Timeout: 10 * time.Second})
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		send(t, client, server.URL+"/login")
+		if got := send(t, client, server.URL+"/me"); got != "" {
+			t.Errorf("stateless client sent cookie %q", got)
+		}
+		if client.CookieJarEnabled() || client.Cookies() != nil {
+			t.Error("stateless client should have no cookies")
+		}
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		client, err := NewClient(&ClientConfig{TorEnabled: false, Timeout: 10 * time.Second, UseCookieJar: true})
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		send(t, client, server.URL+"/login")
+		if got := send(t, client, server.URL+"/me"); got != "abc" {
+			t.Errorf("cookie sent = %q, want abc", got)
+		}
+
+		// Another host name for the same server is another site
+		other := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+		if got := send(t, client, other+"/me"); got != "" {
+			t.Errorf("cookie leaked to another host: %q", got)
+		}
+
+		cookies := client.Cookies()
+		if len(cookies) != 1 || len(cookies[0].Cookies) != 1 || cookies[0].Cookies[0].Name != "session" {
+			t.Errorf("Cookies() = %+v, want the session cookie", cookies)
+		}
+
+		// A client rebuilt with new settings keeps the cookies
+		rebuilt, err := NewClient(&ClientConfig{TorEnabled: false, Timeout: 5 * time.Second, UseCookieJar: true})
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		rebuilt.KeepCookies(client)
+		if got := send(t, rebuilt, server.URL+"/me"); got != "abc" {
+			t.Errorf("cookie sent by the rebuilt client = %q, want abc", got)
+		}
+
+		client.ClearCookies()
+		if got := send(t, client, server.URL+"/me"); got != "" {
+			t.Errorf("cookie sent after ClearCookies: %q", got)
+		}
+		if cookies := client.Cookies(); len(cookies) != 0 {
+			t.Errorf("Cookies() after ClearCookies = %+v", cookies)
+		}
+	})
+}
+
+func TestCookieJarScopesOnionServices(t *testing.T) {
+	jar := newCookieJar()
+	setter, _ := url.Parse("http://aaaa.onion/")
+	jar.SetCookies(setter, []*http.Cookie{
+		{Name: "host", Value: "1"},
+		{Name: "everyone", Value: "2", Domain: "onion"},
+	})
+
+	other, _ := url.Parse("http://bbbb.onion/")
+	if cookies := jar.Cookies(other); len(cookies) != 0 {
+		t.Errorf("cookies leaked to another onion service: %v", cookies)
+	}
+	if cookies := jar.Cookies(setter); len(cookies) != 1 || cookies[0].Name != "host" {
+		t.Errorf("cookies for the setter = %v, want only the host cookie", cookies)
+	}
+}
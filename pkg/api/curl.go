@@ -0,0 +1,450 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// IsCurlCommand reports whether text is a cURL command line
+func IsCurlCommand(text string) bool {
+	fields := strings.Fields(text)
+	return len(fields) > 0 && fields[0] == "curl"
+}
+
+// curlIgnoredFlagsWithValue are cURL options that take a value but have no
+// bearing on the request itself
+var curlIgnoredFlagsWithValue = map[string]bool{
+	"-o": true, "--output": true, "-m": true, "--max-time": true,
+	"--connect-timeout": true, "-x": true, "--proxy": true,
+	"--socks5-hostname": true, "-w": true, "--write-out": true,
+	"--retry": true, "-c": true, "--cookie-jar": true,
+	"-D": true, "--dump-header": true, "--max-redirs": true,
+	"-Y": true, "--speed-limit": true, "-y": true, "--speed-time": true,
+	"--retry-delay": true, "--retry-max-time": true,
+}
+
+// curlUnsupportedFlagsWithValue are cURL options that take a value and
+// change the request in ways the builder can't express, so importing a
+// command that uses them is refused rather than guessed at
+var curlUnsupportedFlagsWithValue = map[string]bool{
+	"-T": true, "--upload-file": true, "-K": true, "--config": true,
+	"-E": true, "--cert": true, "--key": true, "--cacert": true,
+	"-r": true, "--range": true, "-C": true, "--continue-at": true,
+	"-z": true, "--time-cond": true, "-U": true, "--proxy-user": true,
+	"-Q": true, "--quote": true, "-P": true, "--ftp-port": true,
+	"-t": true, "--telnet-option": true, "--resolve": true,
+	"--connect-to": true, "--unix-socket": true, "--request-target": true,
+	"--oauth2-bearer": true, "--aws-sigv4": true,
+}
+
+// curlShortFlagsWithValue are the letters of the short options that take a
+// value, which may follow the letter directly (-XPOST)
+const curlShortFlagsWithValue = "XHduAebFomxwcDYyTKErCzUQPt"
+
+// ParseCurl parses a cURL command line, as copied from browser developer
+// tools, into a request. It understands -X, -H, the -d/--data family, -F,
+// -u, -A, -e, -b, -I, -G and --url; transfer options such as -s, -L and -k
+// are ignored, and options that take a value it can't use are an error.
+// Quoting and backslash line continuations are handled as a shell would.
+// Credentials given with -u are returned as basic auth rather than as a
+// header, and auth is nil without them.
+func ParseCurl(command string) (req *Request, auth *AuthConfig, err error) {
+	args, err := splitShellWords(command)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(args) == 0 || args[0] != "curl" {
+		return nil, nil, fmt.Errorf("not a curl command: it must start with \"curl\"")
+	}
+
+	req = &Request{Headers: make(map[string]string)}
+	var data []string
+	var formFields, formFiles map[string]string
+	getWithData := false
+
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+
+		// Long options may carry their value after "=", short ones straight
+		// after the letter, behind any options that take none (-sXPOST)
+		flag, inline, hasInline := arg, "", false
+		if strings.HasPrefix(arg, "--") {
+			flag, inline, hasInline = strings.Cut(arg, "=")
+		} else if len(arg) > 2 && arg[0] == '-' {
+			if at := strings.IndexAny(arg[1:], curlShortFlagsWithValue); at >= 0 {
+				flag, inline = "-"+arg[1+at:2+at], arg[2+at:]
+				hasInline = inline != ""
+			}
+		}
+		value := func() (string, error) {
+			if hasInline {
+				return inline, nil
+			}
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("curl option %s needs a value", flag)
+			}
+			i++
+			return args[i], nil
+		}
+
+		switch {
+		case flag == "-X" || flag == "--request":
+			if req.Method, err = value(); err != nil {
+				return nil, nil, err
+			}
+		case flag == "-H" || flag == "--header":
+			header, err := value()
+			if err != nil {
+				return nil, nil, err
+			}
+			name, headerValue, ok := strings.Cut(header, ":")
+			if !ok {
+				return nil, nil, fmt.Errorf("invalid curl header %q", header)
+			}
+			req.Headers[strings.TrimSpace(name)] = strings.TrimSpace(headerValue)
+		case flag == "-d" || flag == "--data" || flag == "--data-raw" || flag == "--data-binary" ||
+			flag == "--data-ascii" || flag == "--data-urlencode" || flag == "--json":
+			body, err := value()
+			if err != nil {
+				return nil, nil, err
+			}
+			if strings.HasPrefix(body, "@") && flag != "--data-raw" {
+				return nil, nil, fmt.Errorf("curl data from a file (%s) is not supported", body)
+			}
+			if flag == "--data-urlencode" {
+				name, raw, ok := strings.Cut(body, "=")
+				if ok {
+					body = name + "=" + url.QueryEscape(raw)
+				} else {
+					body = url.QueryEscape(body)
+				}
+			}
+			if flag == "--json" {
+				setDefaultHeader(req.Headers, "Content-Type", "application/json")
+				setDefaultHeader(req.Headers, "Accept", "application/json")
+			}
+			data = append(data, body)
+		case flag == "-F" || flag == "--form" || flag == "--form-string":
+			part, err := value()
+			if err != nil {
+				return nil, nil, err
+			}
+			name, partValue, ok := strings.Cut(part, "=")
+			if !ok || name == "" {
+				return nil, nil, fmt.Errorf("invalid curl form field %q: expected name=value", part)
+			}
+			if formFields == nil {
+				formFields, formFiles = make(map[string]string), make(map[string]string)
+			}
+			switch {
+			case flag == "--form-string":
+				formFields[name] = partValue
+			case strings.HasPrefix(partValue, "@"):
+				// Attributes such as ;type= are left for the upload to work out
+				path, _, _ := strings.Cut(partValue[1:], ";")
+				formFiles[name] = path
+			case strings.HasPrefix(partValue, "<"):
+				return nil, nil, fmt.Errorf("curl form field from a file's contents (%s) is not supported", part)
+			default:
+				formFields[name] = partValue
+			}
+		case flag == "-u" || flag == "--user":
+			credentials, err := value()
+			if err != nil {
+				return nil, nil, err
+			}
+			username, password, _ := strings.Cut(credentials, ":")
+			auth = &AuthConfig{Type: AuthBasic, Username: username, Password: password}
+		case flag == "-A" || flag == "--user-agent":
+			agent, err := value()
+			if err != nil {
+				return nil, nil, err
+			}
+			req.Headers["User-Agent"] = agent
+		case flag == "-e" || flag == "--referer":
+			referer, err := value()
+			if err != nil {
+				return nil, nil, err
+			}
+			req.Headers["Referer"] = referer
+		case flag == "-b" || flag == "--cookie":
+			cookie, err := value()
+			if err != nil {
+				return nil, nil, err
+			}
+			req.Headers["Cookie"] = cookie
+		case flag == "-I" || flag == "--head":
+			req.Method = "HEAD"
+		case flag == "-G" || flag == "--get":
+			getWithData = true
+		case flag == "--url":
+			if req.URL, err = value(); err != nil {
+				return nil, nil, err
+			}
+		case curlIgnoredFlagsWithValue[flag]:
+			if _, err := value(); err != nil {
+				return nil, nil, err
+			}
+		case curlUnsupportedFlagsWithValue[flag]:
+			return nil, nil, fmt.Errorf("curl option %s is not supported", flag)
+		case strings.HasPrefix(arg, "-") && arg != "-":
+			// Other options (-s, -L, -k, --compressed, ...) don't change the request
+		default:
+			req.URL = arg
+		}
+	}
+
+	body := strings.Join(data, "&")
+	switch {
+	case formFields != nil && (body != "" || getWithData):
+		return nil, nil, fmt.Errorf("curl -F can't be combined with -d or -G")
+	case formFields != nil:
+		req.SetMultipartBody(formFields, formFiles)
+		if req.Method == "" {
+			req.Method = "POST"
+		}
+	case getWithData && body != "":
+		separator := "?"
+		if strings.Contains(req.URL, "?") {
+			separator = "&"
+		}
+		req.URL += separator + body
+		if req.Method == "" {
+			req.Method = "GET"
+		}
+	case body != "":
+		req.Body = body
+		if req.Method == "" {
+			req.Method = "POST"
+		}
+		setDefaultHeader(req.Headers, "Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	if req.URL == "" {
+		return nil, nil, fmt.Errorf("curl command has no URL")
+	}
+	if req.Method == "" {
+		req.Method = "GET"
+	}
+	req.Method = strings.ToUpper(req.Method)
+	return req, auth, nil
+}
+
+// setDefaultHeader sets a header unless it is already present in any case
+func setDefaultHeader(headers map[string]string, name, value string) {
+	for key := range headers {
+		if strings.EqualFold(key, name) {
+			return
+		}
+	}
+	headers[name] = value
+}
+
+// defaultTorProxy is where .onion requests are sent in an exported cURL
+// command when no proxy is given, as they can't be reached without Tor
+const defaultTorProxy = "127.0.0.1:9050"
+
+// shellSafe matches words that need no quoting in a POSIX shell
+var shellSafe = regexp.MustCompile(`^[A-Za-z0-9@%+=:,./_-]+$`)
+
+// ToCurl renders the request as a runnable cURL command, one option per
+// line. torProxy, when set, is passed as --socks5-hostname so the command
+// goes through Tor the way OnionCLI sent it, with DNS resolved by Tor;
+// .onion URLs get the default Tor proxy even without one. Headers are
+// rendered as sent, so auth applied to the request is included. Streamed
+// bodies refer to their files rather than being inlined.
+func (r *Request) ToCurl(torProxy string) string {
+	if torProxy == "" && IsOnionURL(r.URL) {
+		torProxy = defaultTorProxy
+	}
+
+	args := []string{"curl"}
+	if r.Method == "HEAD" {
+		args = append(args, "--head")
+	} else {
+		args = append(args, "-X "+shellQuote(r.Method))
+	}
+	if torProxy != "" {
+		args = append(args, "--socks5-hostname "+shellQuote(torProxy))
+	}
+	if r.Timeout > 0 {
+		args = append(args, "--max-time "+strconv.FormatFloat(r.Timeout.Seconds(), 'f', -1, 64))
+	}
+
+	keys := make([]string, 0, len(r.Headers))
+	for key := range r.Headers {
+		// curl writes its own multipart Content-Type, with its own boundary
+		if r.Multipart != nil && strings.EqualFold(key, "Content-Type") {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		args = append(args, "-H "+shellQuote(key+": "+r.Headers[key]))
+	}
+
+	switch {
+	case r.Multipart != nil:
+		for _, name := range sortedKeys(r.Multipart.Fields) {
+			args = append(args, "--form-string "+shellQuote(name+"="+r.Multipart.Fields[name]))
+		}
+		for _, name := range sortedKeys(r.Multipart.Files) {
+			args = append(args, "-F "+shellQuote(name+"=@"+r.Multipart.Files[name]))
+		}
+	case r.BodyFile != "":
+		args = append(args, "--data-binary "+shellQuote("@"+r.BodyFile))
+	case r.Body != "":
+		args = append(args, "--data-raw "+shellQuote(r.Body))
+	}
+
+	args = append(args, shellQuote(r.URL))
+	return strings.Join(args, " \\\n  ")
+}
+
+// shellQuote quotes s for a POSIX shell, single-quoting it unless it is
+// made only of characters the shell leaves alone
+func shellQuote(s string) string {
+	if shellSafe.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// splitShellWords splits a command line into words the way a POSIX shell
+// would for quoting: single quotes are literal, double quotes allow backslash
+// escapes, $'...' strings decode C escapes as bash does (browsers use them
+// for bodies with quotes or newlines), and backslash-newline continues the
+// line
+func splitShellWords(command string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+
+	for i := 0; i < len(command); i++ {
+		c := command[i]
+		switch {
+		case c == '\\' && strings.HasPrefix(command[i+1:], "\r\n"):
+			i += 2 // a continuation copied with Windows line endings
+		case c == '\\' && i+1 < len(command):
+			i++
+			if command[i] != '\n' {
+				word.WriteByte(command[i])
+				inWord = true
+			}
+		case c == '$' && i+1 < len(command) && command[i+1] == '\'':
+			end, err := readANSICQuoted(command, i+2, &word)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+			inWord = true
+		case c == '\'':
+			end := strings.IndexByte(command[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote in curl command")
+			}
+			word.WriteString(command[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+		case c == '"':
+			i++
+			for ; i < len(command) && command[i] != '"'; i++ {
+				if command[i] == '\\' && i+1 < len(command) && strings.IndexByte("\"\\$`\n", command[i+1]) >= 0 {
+					i++
+					if command[i] == '\n' {
+						continue
+					}
+				}
+				word.WriteByte(command[i])
+			}
+			if i >= len(command) {
+				return nil, fmt.Errorf("unterminated double quote in curl command")
+			}
+			inWord = true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// ansiCEscapes are the single-character escapes decoded inside $'...'
+var ansiCEscapes = map[byte]byte{
+	'n': '\n', 't': '\t', 'r': '\r', 'a': '\a', 'b': '\b', 'f': '\f', 'v': '\v',
+	'e': 0x1b, 'E': 0x1b, '\\': '\\', '\'': '\'', '"': '"', '?': '?',
+}
+
+// readANSICQuoted decodes a $'...' string starting just after the opening
+// quote, writing it to word, and returns the index of the closing quote
+func readANSICQuoted(command string, start int, word *strings.Builder) (int, error) {
+	for i := start; i < len(command); i++ {
+		c := command[i]
+		if c == '\'' {
+			return i, nil
+		}
+		if c != '\\' || i+1 >= len(command) {
+			word.WriteByte(c)
+			continue
+		}
+
+		i++
+		if decoded, ok := ansiCEscapes[command[i]]; ok {
+			word.WriteByte(decoded)
+			continue
+		}
+		if command[i] == 'x' {
+			// \xHH: one or two hex digits
+			end := i + 1
+			for end < len(command) && end < i+3 && isHexDigit(command[end]) {
+				end++
+			}
+			if end > i+1 {
+				value, _ := strconv.ParseUint(command[i+1:end], 16, 8)
+				word.WriteByte(byte(value))
+				i = end - 1
+				continue
+			}
+		}
+		if command[i] == 'u' || command[i] == 'U' {
+			// \uHHHH and \UHHHHHHHH: a Unicode code point
+			digits := 4
+			if command[i] == 'U' {
+				digits = 8
+			}
+			end := i + 1
+			for end < len(command) && end < i+1+digits && isHexDigit(command[end]) {
+				end++
+			}
+			if end > i+1 {
+				value, _ := strconv.ParseUint(command[i+1:end], 16, 32)
+				word.WriteRune(rune(value))
+				i = end - 1
+				continue
+			}
+		}
+		// Unknown escapes are kept as written
+		word.WriteByte('\\')
+		word.WriteByte(command[i])
+	}
+	return 0, fmt.Errorf("unterminated $'...' quote in curl command")
+}
+
+// isHexDigit reports whether c is a hexadecimal digit
+func isHexDigit(c byte) bool {
+	return ('0' <= c && c <= '9') || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F')
+}
@@ -0,0 +1,217 @@
+package api
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCurl(t *testing.T) {
+	tests := []struct {
+		name        string
+		command     string
+		wantMethod  string
+		wantURL     string
+		wantHeaders map[string]string
+		wantBody    string
+		wantAuth    *AuthConfig
+	}{
+		{
+			name:       "bare URL",
+			command:    "curl http://example.onion/users",
+			wantMethod: "GET",
+			wantURL:    "http://example.onion/users",
+		},
+		{
+			name:        "method, headers and body",
+			command:     `curl -X put -H 'Content-Type: application/json' -H "X-Trace: a b" --data-raw '{"name":"alice"}' http://example.onion/users/1`,
+			wantMethod:  "PUT",
+			wantURL:     "http://example.onion/users/1",
+			wantHeaders: map[string]string{"Content-Type": "application/json", "X-Trace": "a b"},
+			wantBody:    `{"name":"alice"}`,
+		},
+		{
+			name:        "data implies POST with a form body",
+			command:     "curl -d name=alice -d role=admin http://example.onion/users",
+			wantMethod:  "POST",
+			wantURL:     "http://example.onion/users",
+			wantHeaders: map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+			wantBody:    "name=alice&role=admin",
+		},
+		{
+			name:       "basic auth",
+			command:    "curl -u alice:s3cret http://example.onion/me",
+			wantMethod: "GET",
+			wantURL:    "http://example.onion/me",
+			wantAuth:   &AuthConfig{Type: AuthBasic, Username: "alice", Password: "s3cret"},
+		},
+		{
+			name:        "line continuations, as copied from devtools",
+			command:     "curl 'http://example.onion/api' \\\n  -H 'Accept: application/json' \\\r\n  --compressed",
+			wantMethod:  "GET",
+			wantURL:     "http://example.onion/api",
+			wantHeaders: map[string]string{"Accept": "application/json"},
+		},
+		{
+			name:        "ANSI-C quoted body",
+			command:     `curl http://example.onion/notes -H 'Content-Type: text/plain' --data-raw $'it\'s\nfine \x41é'`,
+			wantMethod:  "POST",
+			wantURL:     "http://example.onion/notes",
+			wantHeaders: map[string]string{"Content-Type": "text/plain"},
+			wantBody:    "it's\nfine Aé",
+		},
+		{
+			name:        "attached short option values",
+			command:     "curl -XPOST '-HAccept: text/plain' -sd a=b http://example.onion/x",
+			wantMethod:  "POST",
+			wantURL:     "http://example.onion/x",
+			wantHeaders: map[string]string{"Accept": "text/plain", "Content-Type": "application/x-www-form-urlencoded"},
+			wantBody:    "a=b",
+		},
+		{
+			name:       "--url and ignored options",
+			command:    "curl -s -L -k -o out.json --max-time 10 --url=http://example.onion/x",
+			wantMethod: "GET",
+			wantURL:    "http://example.onion/x",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, auth, err := ParseCurl(tt.command)
+			if err != nil {
+				t.Fatalf("ParseCurl() error = %v", err)
+			}
+			if req.Method != tt.wantMethod || req.URL != tt.wantURL {
+				t.Errorf("ParseCurl() = %s %s, want %s %s", req.Method, req.URL, tt.wantMethod, tt.wantURL)
+			}
+			if len(req.Headers) != len(tt.wantHeaders) {
+				t.Errorf("Headers = %v, want %v", req.Headers, tt.wantHeaders)
+			}
+			for key, value := range tt.wantHeaders {
+				if req.Headers[key] != value {
+					t.Errorf("Header %s = %q, want %q", key, req.Headers[key], value)
+				}
+			}
+			if req.Body != tt.wantBody {
+				t.Errorf("Body = %q, want %q", req.Body, tt.wantBody)
+			}
+			if (auth == nil) != (tt.wantAuth == nil) || (auth != nil && (auth.Type != tt.wantAuth.Type || auth.Username != tt.wantAuth.Username || auth.Password != tt.wantAuth.Password)) {
+				t.Errorf("auth = %+v, want %+v", auth, tt.wantAuth)
+			}
+		})
+	}
+}
+
+func TestParseCurlErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+	}{
+		{"not curl", "wget http://example.onion"},
+		{"empty", "   "},
+		{"no URL", "curl -X POST -d a=b"},
+		{"missing option value", "curl http://example.onion -H"},
+		{"header without colon", "curl -H 'Accept' http://example.onion"},
+		{"unterminated single quote", "curl 'http://example.onion"},
+		{"unterminated double quote", `curl "http://example.onion`},
+		{"unterminated ANSI-C quote", `curl --data-raw $'abc http://example.onion`},
+		{"data from a file", "curl -d @body.json http://example.onion"},
+		{"unsupported option with a value", "curl -T upload.bin http://example.onion"},
+		{"unsupported attached option", "curl -rbytes=0-99 http://example.onion"},
+		{"form and data together", "curl -F a=b -d c=d http://example.onion"},
+		{"form field without a name", "curl -F =b http://example.onion"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := ParseCurl(tt.command); err == nil {
+				t.Errorf("ParseCurl(%q) error = nil, want an error", tt.command)
+			}
+		})
+	}
+}
+
+func TestParseCurlForm(t *testing.T) {
+	req, _, err := ParseCurl(`curl -F name=alice -F 'avatar=@/tmp/a.png;type=image/png' --form-string 'note=@literal' http://example.onion/upload`)
+	if err != nil {
+		t.Fatalf("ParseCurl() error = %v", err)
+	}
+	if req.Method != "POST" {
+		t.Errorf("Method = %s, want POST", req.Method)
+	}
+	if req.Multipart == nil {
+		t.Fatal("Expected a multipart body")
+	}
+	if req.Multipart.Fields["name"] != "alice" || req.Multipart.Fields["note"] != "@literal" {
+		t.Errorf("Fields = %v, want name and the literal note", req.Multipart.Fields)
+	}
+	if req.Multipart.Files["avatar"] != "/tmp/a.png" {
+		t.Errorf("Files = %v, want avatar=/tmp/a.png", req.Multipart.Files)
+	}
+	if !strings.HasPrefix(req.Headers["Content-Type"], "multipart/form-data; boundary=") {
+		t.Errorf("Content-Type = %q, want multipart/form-data with a boundary", req.Headers["Content-Type"])
+	}
+}
+
+func TestToCurl(t *testing.T) {
+	req := NewRequest("POST", "http://example.onion/users?q=a b")
+	req.SetHeader("Authorization", "Bearer t0k'en")
+	req.SetHeader("Content-Type", "application/json")
+	req.Body = `{"name": "it's me"}`
+	req.Timeout = 1500 * time.Millisecond
+
+	want := `curl \
+  -X POST \
+  --socks5-hostname 127.0.0.1:9150 \
+  --max-time 1.5 \
+  -H 'Authorization: Bearer t0k'\''en' \
+  -H 'Content-Type: application/json' \
+  --data-raw '{"name": "it'\''s me"}' \
+  'http://example.onion/users?q=a b'`
+	if got := req.ToCurl("127.0.0.1:9150"); got != want {
+		t.Errorf("ToCurl() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestToCurlBodies(t *testing.T) {
+	head := NewRequest("HEAD", "https://example.com/")
+	if got, want := head.ToCurl(""), "curl \\\n  --head \\\n  https://example.com/"; got != want {
+		t.Errorf("HEAD ToCurl() = %q, want %q", got, want)
+	}
+
+	file := NewRequest("PUT", "https://example.com/upload")
+	file.SetBodyFile("/tmp/my file.bin")
+	if got := file.ToCurl(""); !strings.Contains(got, `--data-binary '@/tmp/my file.bin'`) {
+		t.Errorf("body file ToCurl() = %q, want --data-binary with the quoted path", got)
+	}
+
+	form := NewRequest("POST", "https://example.com/form")
+	form.SetMultipartBody(map[string]string{"note": "@not a file"}, map[string]string{"avatar": "/tmp/a.png"})
+	got := form.ToCurl("")
+	for _, part := range []string{`--form-string 'note=@not a file'`, `-F avatar=@/tmp/a.png`} {
+		if !strings.Contains(got, part) {
+			t.Errorf("multipart ToCurl() = %q, want it to contain %q", got, part)
+		}
+	}
+	if strings.Contains(strings.ToLower(got), "content-type") {
+		t.Errorf("multipart ToCurl() = %q, should leave the boundary Content-Type to curl", got)
+	}
+}
+
+func TestToCurlRoundTrip(t *testing.T) {
+	req := NewRequest("PATCH", "http://example.onion/a?x=1&y=$HOME")
+	req.SetHeader("X-Quote", `say "hi" and 'bye' \ done`)
+	req.Body = "line one\nline two with `backticks` and $vars"
+
+	parsed, _, err := ParseCurl(req.ToCurl("127.0.0.1:9050"))
+	if err != nil {
+		t.Fatalf("ParseCurl(ToCurl()) error = %v", err)
+	}
+	if parsed.Method != req.Method || parsed.URL != req.URL || parsed.Body != req.Body {
+		t.Errorf("round trip = %s %s %q, want %s %s %q", parsed.Method, parsed.URL, parsed.Body, req.Method, req.URL, req.Body)
+	}
+	if parsed.Headers["X-Quote"] != req.Headers["X-Quote"] {
+		t.Errorf("round trip header = %q, want %q", parsed.Headers["X-Quote"], req.Headers["X-Quote"])
+	}
+}
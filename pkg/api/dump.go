@@ -0,0 +1,131 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptrace"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// headerRecorder captures the header fields the transport writes for a
+// request, in wire order, including the ones it adds itself such as Host,
+// User-Agent and Accept-Encoding. A followed redirect starts a new request,
+// so only the last one's fields are kept.
+type headerRecorder struct {
+	mu      sync.Mutex
+	fields  []string
+	written bool // the current request's headers are complete
+}
+
+// withHeaderRecorder returns ctx traced by a new recorder
+func withHeaderRecorder(ctx context.Context) (context.Context, *headerRecorder) {
+	recorder := &headerRecorder{}
+	trace := &httptrace.ClientTrace{
+		WroteHeaderField: recorder.field,
+		WroteHeaders:     recorder.done,
+	}
+	return httptrace.WithClientTrace(ctx, trace), recorder
+}
+
+func (hr *headerRecorder) field(key string, values []string) {
+	hr.mu.Lock()
+	defer hr.mu.Unlock()
+	if hr.written {
+		hr.fields, hr.written = nil, false
+	}
+	for _, value := range values {
+		hr.fields = append(hr.fields, key+": "+value)
+	}
+}
+
+func (hr *headerRecorder) done() {
+	hr.mu.Lock()
+	defer hr.mu.Unlock()
+	hr.written = true
+}
+
+// lines returns the recorded header lines
+func (hr *headerRecorder) lines() []string {
+	hr.mu.Lock()
+	defer hr.mu.Unlock()
+	return append([]string(nil), hr.fields...)
+}
+
+// dumpRequest renders the request that produced httpResp as it went over
+// the wire: request line, header fields and body. The body is req's, which
+// a redirect that changed the method (e.g. POST to GET after a 303) dropped;
+// streamed bodies are summarised rather than read again.
+func dumpRequest(req *Request, httpResp *http.Response, recorder *headerRecorder) string {
+	final := httpResp.Request
+	lines := []string{fmt.Sprintf("%s %s %s", final.Method, final.URL.RequestURI(), httpResp.Proto)}
+	lines = append(lines, recorder.lines()...)
+
+	dump := strings.Join(lines, "\n") + "\n\n"
+	if final.Method != req.Method {
+		return dump
+	}
+	switch {
+	case req.Multipart != nil:
+		dump += fmt.Sprintf("[multipart form, %d bytes]", final.ContentLength)
+	case req.BodyFile != "":
+		dump += fmt.Sprintf("[%d bytes streamed from %s]", final.ContentLength, req.BodyFile)
+	default:
+		dump += req.Body
+	}
+	return dump
+}
+
+// dumpResponse renders a response's status line and every header value as
+// received, in name order; the decoded body is Response.Body
+func dumpResponse(httpResp *http.Response) string {
+	names := make([]string, 0, len(httpResp.Header))
+	for name := range httpResp.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := []string{fmt.Sprintf("%s %s", httpResp.Proto, httpResp.Status)}
+	for _, name := range names {
+		for _, value := range httpResp.Header[name] {
+			lines = append(lines, name+": "+value)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// MaskDumpHeaders masks the values of sensitive header fields in a request or
+// response dump, as MaskSensitiveData does for auth configs. An auth scheme
+// such as Bearer or Basic is kept so the kind of credential still shows.
+// Only the header block is touched; the body after the first blank line is
+// left as it is.
+func MaskDumpHeaders(dump string) string {
+	lines := strings.Split(dump, "\n")
+	for i, line := range lines {
+		if line == "" {
+			break
+		}
+		name, value, ok := strings.Cut(line, ": ")
+		if !ok || !IsSensitiveName(name) {
+			continue
+		}
+		if scheme, secret, found := strings.Cut(value, " "); found && isAuthScheme(scheme) {
+			value = scheme + " " + maskSecret(secret)
+		} else {
+			value = maskSecret(value)
+		}
+		lines[i] = name + ": " + value
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isAuthScheme reports whether s is an Authorization scheme name
+func isAuthScheme(s string) bool {
+	switch strings.ToLower(s) {
+	case "basic", "bearer", "digest", "token", "apikey":
+		return true
+	}
+	return false
+}
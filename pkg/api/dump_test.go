@@ -0,0 +1,85 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRequestAndResponseDump(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new?x=1", http.StatusSeeOther)
+			return
+		}
+		w.Header().Add("Set-Cookie", "a=1")
+		w.Header().Add("Set-Cookie", "b=2")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(&ClientConfig{TorEnabled: false, Timeout: 10 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	t.Run("direct", func(t *testing.T) {
+		req := NewRequest("POST", server.URL+"/items")
+		req.SetHeader("Authorization", "Bearer abcdefghijkl")
+		req.SetBody("hello")
+		resp, err := client.Send(req)
+		if err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+
+		for _, want := range []string{"POST /items HTTP/1.1\n", "Host: ", "Authorization: Bearer abcdefghijkl\n", "Content-Length: 5\n", "\n\nhello"} {
+			if !strings.Contains(resp.RequestDump, want) {
+				t.Errorf("RequestDump missing %q:\n%s", want, resp.RequestDump)
+			}
+		}
+		for _, want := range []string{"HTTP/1.1 201 Created\n", "Set-Cookie: a=1\nSet-Cookie: b=2"} {
+			if !strings.Contains(resp.ResponseDump, want) {
+				t.Errorf("ResponseDump missing %q:\n%s", want, resp.ResponseDump)
+			}
+		}
+	})
+
+	t.Run("redirect changing the method", func(t *testing.T) {
+		req := NewRequest("POST", server.URL+"/old")
+		req.SetBody("hello")
+		resp, err := client.Send(req)
+		if err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+		if !strings.HasPrefix(resp.RequestDump, "GET /new?x=1 HTTP/1.1\n") {
+			t.Errorf("RequestDump should show the redirected request:\n%s", resp.RequestDump)
+		}
+		if strings.Contains(resp.RequestDump, "hello") || strings.Contains(resp.RequestDump, "Content-Length") {
+			t.Errorf("RequestDump should not show the dropped body:\n%s", resp.RequestDump)
+		}
+	})
+}
+
+func TestMaskDumpHeaders(t *testing.T) {
+	dump := "GET / HTTP/1.1\n" +
+		"Authorization: Bearer abcdefghijkl\n" +
+		"X-Api-Key: 0123456789\n" +
+		"Cookie: short\n" +
+		"Accept: */*\n" +
+		"\n" +
+		"Authorization: body text is left alone"
+
+	want := "GET / HTTP/1.1\n" +
+		"Authorization: Bearer abc****jkl\n" +
+		"X-Api-Key: 012****789\n" +
+		"Cookie: ****\n" +
+		"Accept: */*\n" +
+		"\n" +
+		"Authorization: body text is left alone"
+
+	if got := MaskDumpHeaders(dump); got != want {
+		t.Errorf("MaskDumpHeaders() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// decodeContentEncoding undoes a gzip or deflate Content-Encoding. net/http
+// only does this itself when it chose Accept-Encoding, so bodies requested
+// with an explicit Accept-Encoding header arrive still compressed.
+func decodeContentEncoding(body []byte, contentEncoding string) ([]byte, error) {
+	var reader io.Reader
+	switch strings.ToLower(strings.TrimSpace(contentEncoding)) {
+	case "", "identity":
+		return body, nil
+	case "gzip", "x-gzip":
+		gz, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip body: %w", err)
+		}
+		defer gz.Close()
+		reader = gz
+	case "deflate":
+		// HTTP deflate is zlib-wrapped, but some servers send raw deflate
+		if zr, err := zlib.NewReader(bytes.NewReader(body)); err == nil {
+			defer zr.Close()
+			reader = zr
+		} else {
+			reader = flate.NewReader(bytes.NewReader(body))
+		}
+	default:
+		return nil, fmt.Errorf("unsupported Content-Encoding %q", contentEncoding)
+	}
+
+	decoded, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s body: %w", contentEncoding, err)
+	}
+	return decoded, nil
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDecodeContentEncoding(t *testing.T) {
+	const plain = `{"representation":"json"}`
+
+	var gz, zl, raw bytes.Buffer
+	gw := gzip.NewWriter(&gz)
+	gw.Write([]byte(plain))
+	gw.Close()
+	zw := zlib.NewWriter(&zl)
+	zw.Write([]byte(plain))
+	zw.Close()
+	fw, _ := flate.NewWriter(&raw, flate.DefaultCompression)
+	fw.Write([]byte(plain))
+	fw.Close()
+
+	tests := []struct {
+		name     string
+		body     []byte
+		encoding string
+		wantErr  bool
+	}{
+		{"identity", []byte(plain), "", false},
+		{"gzip", gz.Bytes(), "gzip", false},
+		{"zlib deflate", zl.Bytes(), "deflate", false},
+		{"raw deflate", raw.Bytes(), "Deflate", false},
+		{"corrupt gzip", []byte("not gzip"), "gzip", true},
+		{"brotli", []byte(plain), "br", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, err := decodeContentEncoding(tt.body, tt.encoding)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && string(decoded) != plain {
+				t.Errorf("Expected %q, got %q", plain, decoded)
+			}
+		})
+	}
+}
+
+func TestSendDecodesExplicitAcceptEncoding(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		gw := gzip.NewWriter(w)
+		gw.Write([]byte("hello"))
+		gw.Close()
+	}))
+	defer server.Close()
+
+	client, err := NewClient(&ClientConfig{TorEnabled: false, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	req := NewRequest("GET", server.URL)
+	req.SetHeader("Accept-Encoding", "gzip")
+	resp, err := client.Send(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if resp.Body != "hello" {
+		t.Errorf("Expected decoded body, got %q", resp.Body)
+	}
+}
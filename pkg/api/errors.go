@@ -1,9 +1,11 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"net"
 	"strings"
+	"time"
 )
 
 // ErrorType represents different categories of errors
@@ -17,6 +19,8 @@ const (
 	ErrorTypeTimeout    ErrorType = "timeout"
 	ErrorTypeDNS        ErrorType = "dns"
 	ErrorTypeHTTP       ErrorType = "http"
+	ErrorTypeRateLimit  ErrorType = "rate_limit"
+	ErrorTypeRedirect   ErrorType = "redirect"
 	ErrorTypeUnknown    ErrorType = "unknown"
 )
 
@@ -28,6 +32,18 @@ type DiagnosticError struct {
 	Suggestions []string  `json:"suggestions"`
 	URL         string    `json:"url,omitempty"`
 	StatusCode  int       `json:"status_code,omitempty"`
+
+	// RetryAfter is the server-requested backoff for rate-limited responses
+	RetryAfter time.Duration `json:"retry_after,omitempty"`
+
+	// Attempts lists every try when the client retried before giving up
+	Attempts []AttemptInfo `json:"attempts,omitempty"`
+
+	// Redirects is the chain followed before the redirect limit was hit
+	Redirects []RedirectHop `json:"redirects,omitempty"`
+
+	// Probes are live connection checks run for Tor errors, see RunTorDiagnostics
+	Probes []ProbeResult `json:"probes,omitempty"`
 }
 
 // Error implements the error interface
@@ -54,9 +70,37 @@ func (ea *ErrorAnalyzer) AnalyzeError(err error, requestURL string) *DiagnosticE
 		return nil
 	}
 
+	// Classify exhausted retries by the last failure, keeping the attempt history
+	var retryErr *RetryError
+	if errors.As(err, &retryErr) {
+		diagnostic := ea.AnalyzeError(retryErr.Err, requestURL)
+		diagnostic.Message = retryErr.Error()
+		diagnostic.Cause = err
+		diagnostic.Attempts = retryErr.Attempts
+		if isOnionUnreachable(retryErr.Err) {
+			diagnostic.Suggestions = append(diagnostic.Suggestions, fmt.Sprintf(
+				"The service may be offline: it stayed unreachable across %d attempts", len(retryErr.Attempts)))
+		}
+		return diagnostic
+	}
+
 	// Parse URL for context
 	isOnion := IsOnionURL(requestURL)
 
+	var redirectErr *RedirectError
+	if errors.As(err, &redirectErr) {
+		return ea.analyzeRedirectError(redirectErr, requestURL, isOnion)
+	}
+
+	// Checked first, as the message would otherwise read as a Tor error
+	if errors.Is(err, ErrV2OnionAddress) {
+		return ea.analyzeV2OnionError(err, requestURL)
+	}
+
+	if errors.Is(err, ErrWebSocketHandshake) || errors.Is(err, ErrWebSocketClosed) {
+		return ea.analyzeWebSocketError(err, requestURL, isOnion)
+	}
+
 	// Analyze different error types
 	switch {
 	case ea.isTorError(err):
@@ -74,6 +118,70 @@ func (ea *ErrorAnalyzer) AnalyzeError(err error, requestURL string) *DiagnosticE
 	}
 }
 
+// AnalyzeResponse turns a non-2xx response into a diagnostic error.
+// It returns nil for successful responses.
+func (ea *ErrorAnalyzer) AnalyzeResponse(resp *Response, requestURL string) *DiagnosticError {
+	if resp == nil || resp.IsSuccess() {
+		return nil
+	}
+
+	isOnion := IsOnionURL(requestURL)
+	errorType := ErrorTypeHTTP
+	var suggestions []string
+	var retryAfter time.Duration
+
+	switch {
+	case resp.IsRateLimited():
+		errorType = ErrorTypeRateLimit
+		if delay, ok := resp.RetryAfter(); ok {
+			retryAfter = delay
+			suggestions = []string{fmt.Sprintf("The server asked to wait %v before retrying", delay)}
+		} else {
+			suggestions = []string{"The server did not say how long to wait - back off before retrying"}
+		}
+		suggestions = append(suggestions, "Reduce the request rate to this service")
+	case resp.StatusCode == 401 || resp.StatusCode == 403:
+		errorType = ErrorTypeAuth
+		suggestions = []string{
+			"Check your authentication credentials",
+			"Verify the authentication method is correct",
+			"Ensure API keys or tokens are valid and not expired",
+		}
+	case resp.StatusCode == 404:
+		suggestions = []string{
+			"Check the request path for typos",
+			"Verify the resource exists on the server",
+		}
+	case resp.IsClientError():
+		suggestions = []string{
+			"Check the request method, headers, and body",
+			"Verify the request matches what the API expects",
+		}
+	case resp.IsServerError():
+		suggestions = []string{
+			"The server encountered an error - try the request again later",
+			"Check the response body for error details",
+		}
+		if isOnion {
+			suggestions = append(suggestions, "The .onion service might be overloaded or misconfigured")
+		}
+	default:
+		suggestions = []string{
+			"The server returned an unexpected status code",
+			"Check the response headers and body for details",
+		}
+	}
+
+	return &DiagnosticError{
+		Type:        errorType,
+		Message:     fmt.Sprintf("Server responded with %s", resp.Status),
+		Suggestions: suggestions,
+		URL:         requestURL,
+		StatusCode:  resp.StatusCode,
+		RetryAfter:  retryAfter,
+	}
+}
+
 // isTorError checks if the error is related to Tor
 func (ea *ErrorAnalyzer) isTorError(err error) bool {
 	errStr := strings.ToLower(err.Error())
@@ -94,6 +202,13 @@ func (ea *ErrorAnalyzer) isTorError(err error) bool {
 	return false
 }
 
+// isOnionUnreachable checks for the SOCKS failure Tor reports when it could
+// not reach an onion service, usually because it is offline or its
+// introduction points are temporarily unreachable
+func isOnionUnreachable(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "general socks server failure")
+}
+
 // isNetworkError checks if the error is a network-related error
 func (ea *ErrorAnalyzer) isNetworkError(err error) bool {
 	if netErr, ok := err.(net.Error); ok {
@@ -178,7 +293,7 @@ func (ea *ErrorAnalyzer) analyzeTorError(err error, requestURL string, isOnion b
 		suggestions = append(suggestions, "Tor proxy is not running or not accessible on 127.0.0.1:9050")
 	}
 
-	if strings.Contains(err.Error(), "general socks server failure") {
+	if isOnionUnreachable(err) {
 		suggestions = append(suggestions,
 			"The .onion service might be down or unreachable",
 			"Try a different .onion URL to test Tor connectivity",
@@ -300,6 +415,80 @@ func (ea *ErrorAnalyzer) analyzeAuthError(err error, requestURL string) *Diagnos
 	}
 }
 
+// analyzeRedirectError analyzes a request that hit the redirect limit
+func (ea *ErrorAnalyzer) analyzeRedirectError(err *RedirectError, requestURL string, isOnion bool) *DiagnosticError {
+	var suggestions []string
+	if err.IsLoop() {
+		suggestions = append(suggestions, "The server redirects in a loop - check the URL, scheme and trailing slash")
+	} else {
+		suggestions = append(suggestions, "Request the final URL in the chain directly")
+	}
+	suggestions = append(suggestions,
+		"Login pages often redirect back and forth when authentication or cookies are missing - check your auth settings",
+		"Raise http.max_redirects if the chain is expected to be this long")
+	if isOnion {
+		suggestions = append(suggestions, "The .onion service may be redirecting between mirrors - try one of the mirrors directly")
+	}
+
+	return &DiagnosticError{
+		Type:        ErrorTypeRedirect,
+		Message:     err.Error(),
+		Cause:       err,
+		Suggestions: suggestions,
+		URL:         requestURL,
+		Redirects:   err.Chain,
+	}
+}
+
+// analyzeV2OnionError explains a request to a retired v2 onion address
+func (ea *ErrorAnalyzer) analyzeV2OnionError(err error, requestURL string) *DiagnosticError {
+	suggestions := []string{
+		"v2 onion services were retired in Tor 0.4.6 and no longer route, so this address can never be reached",
+		"Find the service's 56-character v3 address from its operator or an up-to-date directory",
+	}
+
+	return &DiagnosticError{
+		Type:        ErrorTypeValidation,
+		Message:     fmt.Sprintf("Invalid .onion address: %v", err),
+		Cause:       err,
+		Suggestions: suggestions,
+		URL:         requestURL,
+	}
+}
+
+// analyzeWebSocketError explains a refused WebSocket upgrade or a
+// connection the server closed
+func (ea *ErrorAnalyzer) analyzeWebSocketError(err error, requestURL string, isOnion bool) *DiagnosticError {
+	if errors.Is(err, ErrWebSocketClosed) {
+		suggestions := []string{
+			"The server ended the session; connect again to reopen it",
+			"Servers often close idle or unauthenticated sockets - check the protocol expects what was sent",
+		}
+		if isOnion {
+			suggestions = append(suggestions, "Tor circuits can drop long-lived connections; Ctrl+N requests a new circuit")
+		}
+		return &DiagnosticError{
+			Type:        ErrorTypeNetwork,
+			Message:     err.Error(),
+			Cause:       err,
+			Suggestions: suggestions,
+			URL:         requestURL,
+		}
+	}
+
+	return &DiagnosticError{
+		Type:    ErrorTypeHTTP,
+		Message: err.Error(),
+		Cause:   err,
+		Suggestions: []string{
+			"Check the path: the server answered, but not with a WebSocket upgrade there",
+			"Try wss:// if the service only accepts WebSockets over TLS, or ws:// if it has no TLS",
+			"Add any authentication or headers the endpoint requires to the headers field",
+		},
+		URL: requestURL,
+	}
+}
+
 // analyzeGenericError analyzes generic errors
 func (ea *ErrorAnalyzer) analyzeGenericError(err error, requestURL string) *DiagnosticError {
 	suggestions := []string{
@@ -342,10 +531,16 @@ func (de *DiagnosticError) GetDiagnosticSummary() string {
 	return summary.String()
 }
 
+// RetryDelay returns how long to wait before retrying, honoring any
+// server-requested backoff
+func (de *DiagnosticError) RetryDelay() time.Duration {
+	return de.RetryAfter
+}
+
 // IsRetryable returns true if the error might be resolved by retrying
 func (de *DiagnosticError) IsRetryable() bool {
 	switch de.Type {
-	case ErrorTypeTimeout, ErrorTypeNetwork:
+	case ErrorTypeTimeout, ErrorTypeNetwork, ErrorTypeRateLimit:
 		return true
 	case ErrorTypeTor:
 		// Some Tor errors are retryable (circuit issues), others are not (Tor not running)
@@ -0,0 +1,22 @@
+package api
+
+// Exit codes used when running requests non-interactively
+const (
+	ExitSuccess      = 0 // Request completed (and succeeded, if non-2xx counts as an error)
+	ExitRequestError = 1 // Request could not be sent or no response was received
+	ExitHTTPError    = 2 // Response status was not 2xx and non-2xx is treated as an error
+)
+
+// ExitCode returns the process exit code for the outcome of a request.
+// A non-2xx response only produces a nonzero code when treatNon2xxAsError is set.
+func ExitCode(resp *Response, err error, treatNon2xxAsError bool) int {
+	if err != nil || resp == nil {
+		return ExitRequestError
+	}
+
+	if treatNon2xxAsError && !resp.IsSuccess() {
+		return ExitHTTPError
+	}
+
+	return ExitSuccess
+}
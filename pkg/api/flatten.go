@@ -0,0 +1,91 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// PathValue is a single JSON leaf value and its full path, e.g. $.data.items[0].id
+type PathValue struct {
+	Path  string `json:"path"`
+	Value string `json:"value"` // JSON encoding of the leaf, e.g. 42 or "abc"
+}
+
+// String formats the pair as "path = value"
+func (pv PathValue) String() string {
+	return pv.Path + " = " + pv.Value
+}
+
+// identifierPattern matches object keys that can use dot notation
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// FlattenJSON lists every leaf value in a JSON body with its full path.
+// Object keys are sorted, array elements keep their order, and empty objects
+// and arrays are reported as leaves. Returns nil if the body is not JSON.
+func (r *Response) FlattenJSON() []PathValue {
+	decoder := json.NewDecoder(strings.NewReader(r.Body))
+	decoder.UseNumber() // keep large IDs exact
+
+	var data interface{}
+	if err := decoder.Decode(&data); err != nil {
+		return nil
+	}
+
+	var values []PathValue
+	flattenValue("$", data, &values)
+	return values
+}
+
+// flattenValue appends the leaves under value to values
+func flattenValue(path string, value interface{}, values *[]PathValue) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			*values = append(*values, PathValue{Path: path, Value: "{}"})
+			return
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			flattenValue(childPath(path, key), v[key], values)
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			*values = append(*values, PathValue{Path: path, Value: "[]"})
+			return
+		}
+		for i, item := range v {
+			flattenValue(fmt.Sprintf("%s[%d]", path, i), item, values)
+		}
+	default:
+		*values = append(*values, PathValue{Path: path, Value: encodeLeaf(v)})
+	}
+}
+
+// childPath appends an object key using dot notation, or bracket notation
+// for keys that are not plain identifiers
+func childPath(path, key string) string {
+	if identifierPattern.MatchString(key) {
+		return path + "." + key
+	}
+	quoted, _ := json.Marshal(key)
+	return path + "[" + string(quoted) + "]"
+}
+
+// encodeLeaf encodes a scalar JSON value without HTML escaping
+func encodeLeaf(value interface{}) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Sprint(value)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFlattenJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []PathValue
+	}{
+		{
+			name: "nested objects and arrays",
+			body: `{"data":{"items":[{"id":42,"name":"a"},{"id":43,"tags":["x","y"]}]},"ok":true}`,
+			want: []PathValue{
+				{Path: "$.data.items[0].id", Value: "42"},
+				{Path: "$.data.items[0].name", Value: `"a"`},
+				{Path: "$.data.items[1].id", Value: "43"},
+				{Path: "$.data.items[1].tags[0]", Value: `"x"`},
+				{Path: "$.data.items[1].tags[1]", Value: `"y"`},
+				{Path: "$.ok", Value: "true"},
+			},
+		},
+		{
+			name: "top-level array",
+			body: `[1, null, [2]]`,
+			want: []PathValue{
+				{Path: "$[0]", Value: "1"},
+				{Path: "$[1]", Value: "null"},
+				{Path: "$[2][0]", Value: "2"},
+			},
+		},
+		{
+			name: "empty containers are leaves",
+			body: `{"list":[],"meta":{}}`,
+			want: []PathValue{
+				{Path: "$.list", Value: "[]"},
+				{Path: "$.meta", Value: "{}"},
+			},
+		},
+		{
+			name: "keys that need bracket notation",
+			body: `{"content-type":"a<b","with space":1,"_ok":2}`,
+			want: []PathValue{
+				{Path: "$._ok", Value: "2"},
+				{Path: `$["content-type"]`, Value: `"a<b"`},
+				{Path: `$["with space"]`, Value: "1"},
+			},
+		},
+		{
+			name: "large numbers keep precision",
+			body: `{"id":12345678901234567890}`,
+			want: []PathValue{
+				{Path: "$.id", Value: "12345678901234567890"},
+			},
+		},
+		{
+			name: "scalar body",
+			body: `"hello"`,
+			want: []PathValue{
+				{Path: "$", Value: `"hello"`},
+			},
+		},
+		{
+			name: "not JSON",
+			body: `<html></html>`,
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := (&Response{Body: tt.body}).FlattenJSON()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FlattenJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathValueString(t *testing.T) {
+	pv := PathValue{Path: "$.data.items[0].id", Value: "42"}
+	if got, want := pv.String(), "$.data.items[0].id = 42"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
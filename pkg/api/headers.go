@@ -0,0 +1,92 @@
+package api
+
+import (
+	"regexp"
+	"strings"
+)
+
+// HeaderField is a single parsed header, in the order it appeared
+type HeaderField struct {
+	Name  string
+	Value string
+}
+
+var (
+	// headerNamePattern matches an RFC 9110 token, optionally prefixed by ':' for HTTP/2 pseudo-headers
+	headerNamePattern = regexp.MustCompile("^:?[!#$%&'*+\\-.^_`|~0-9A-Za-z]+$")
+
+	// startLinePattern matches request lines ("GET /path HTTP/1.1") and status lines ("HTTP/2 200")
+	startLinePattern = regexp.MustCompile(`^(?:[A-Z]+ \S+ HTTP/\d(?:\.\d)?|HTTP/\d(?:\.\d)? \d{3}.*)$`)
+)
+
+// ParseHeaderBlock parses a raw block of "Name: value" lines, such as one
+// copied from browser devtools. Request and status lines and HTTP/2
+// pseudo-headers (":method", ":path", ...) are dropped, folded continuation
+// lines are joined onto the previous header, and repeated headers are
+// combined. Lines that are not headers are returned in skipped.
+func ParseHeaderBlock(raw string) (headers []HeaderField, skipped []string) {
+	index := make(map[string]int)
+	last := -1 // index of the header a continuation line would extend
+
+	for _, line := range strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			last = -1
+			continue
+		}
+
+		// obs-fold: a line starting with whitespace continues the previous value
+		if (line[0] == ' ' || line[0] == '\t') && last >= 0 {
+			headers[last].Value = strings.TrimSpace(headers[last].Value + " " + trimmed)
+			continue
+		}
+
+		if startLinePattern.MatchString(trimmed) {
+			last = -1
+			continue
+		}
+
+		name, value, ok := splitHeaderLine(trimmed)
+		if !ok {
+			skipped = append(skipped, trimmed)
+			last = -1
+			continue
+		}
+		if strings.HasPrefix(name, ":") {
+			last = -1
+			continue
+		}
+
+		key := strings.ToLower(name)
+		if i, exists := index[key]; exists {
+			separator := ", "
+			if key == "cookie" {
+				separator = "; "
+			}
+			headers[i].Value += separator + value
+			last = i
+			continue
+		}
+
+		index[key] = len(headers)
+		last = len(headers)
+		headers = append(headers, HeaderField{Name: name, Value: value})
+	}
+
+	return headers, skipped
+}
+
+// splitHeaderLine splits "Name: value", allowing the leading ':' of pseudo-headers
+func splitHeaderLine(line string) (name, value string, ok bool) {
+	separator := strings.Index(line[1:], ":")
+	if separator < 0 {
+		return "", "", false
+	}
+	separator++
+
+	name = strings.TrimSpace(line[:separator])
+	if !headerNamePattern.MatchString(name) {
+		return "", "", false
+	}
+	return name, strings.TrimSpace(line[separator+1:]), true
+}
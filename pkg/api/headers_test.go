@@ -0,0 +1,90 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseHeaderBlock(t *testing.T) {
+	tests := []struct {
+		name        string
+		raw         string
+		wantHeaders []HeaderField
+		wantSkipped []string
+	}{
+		{
+			name: "HTTP/2 devtools block with pseudo-headers",
+			raw: ":authority: example.onion\n" +
+				":method: GET\n" +
+				":path: /api/users?page=2\n" +
+				":scheme: https\n" +
+				"accept: application/json\n" +
+				"accept-language: en-US,en;q=0.9\n" +
+				"user-agent: Mozilla/5.0 (X11; Linux x86_64)\n",
+			wantHeaders: []HeaderField{
+				{Name: "accept", Value: "application/json"},
+				{Name: "accept-language", Value: "en-US,en;q=0.9"},
+				{Name: "user-agent", Value: "Mozilla/5.0 (X11; Linux x86_64)"},
+			},
+		},
+		{
+			name: "HTTP/1.1 raw block with request line and CRLF",
+			raw: "POST /login HTTP/1.1\r\n" +
+				"Host: example.onion\r\n" +
+				"Content-Type: application/x-www-form-urlencoded\r\n" +
+				"Referer: http://example.onion/login\r\n",
+			wantHeaders: []HeaderField{
+				{Name: "Host", Value: "example.onion"},
+				{Name: "Content-Type", Value: "application/x-www-form-urlencoded"},
+				{Name: "Referer", Value: "http://example.onion/login"},
+			},
+		},
+		{
+			name: "response status line is dropped",
+			raw:  "HTTP/2 200\ncontent-type: text/html\n",
+			wantHeaders: []HeaderField{
+				{Name: "content-type", Value: "text/html"},
+			},
+		},
+		{
+			name: "folded continuation lines",
+			raw:  "X-Long: first part\n  second part\n\tthird part\nAccept: */*",
+			wantHeaders: []HeaderField{
+				{Name: "X-Long", Value: "first part second part third part"},
+				{Name: "Accept", Value: "*/*"},
+			},
+		},
+		{
+			name: "repeated headers are combined",
+			raw:  "Cookie: a=1\nAccept: text/html\ncookie: b=2\nAccept: application/json",
+			wantHeaders: []HeaderField{
+				{Name: "Cookie", Value: "a=1; b=2"},
+				{Name: "Accept", Value: "text/html, application/json"},
+			},
+		},
+		{
+			name: "devtools general section is skipped",
+			raw:  "Request URL: http://example.onion/\nRequest Method: GET\nnot a header\nAccept: */*",
+			wantHeaders: []HeaderField{
+				{Name: "Accept", Value: "*/*"},
+			},
+			wantSkipped: []string{"Request URL: http://example.onion/", "Request Method: GET", "not a header"},
+		},
+		{
+			name: "empty block",
+			raw:  "\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers, skipped := ParseHeaderBlock(tt.raw)
+			if !reflect.DeepEqual(headers, tt.wantHeaders) {
+				t.Errorf("ParseHeaderBlock() headers = %v, want %v", headers, tt.wantHeaders)
+			}
+			if !reflect.DeepEqual(skipped, tt.wantSkipped) {
+				t.Errorf("ParseHeaderBlock() skipped = %v, want %v", skipped, tt.wantSkipped)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"mime"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// htmlParagraphTags are set apart from the text around them by a blank line
+var htmlParagraphTags = map[string]bool{
+	"p": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"pre": true, "blockquote": true, "ul": true, "ol": true, "dl": true, "table": true, "hr": true,
+}
+
+// htmlBlockTags start on a line of their own
+var htmlBlockTags = map[string]bool{
+	"div": true, "section": true, "article": true, "header": true, "footer": true,
+	"nav": true, "main": true, "aside": true, "form": true, "title": true, "tr": true,
+	"dt": true, "dd": true, "figure": true, "figcaption": true, "address": true,
+}
+
+// htmlHiddenTags hold content that isn't meant to be read
+var htmlHiddenTags = map[string]bool{
+	"script": true, "style": true, "noscript": true, "template": true, "svg": true,
+}
+
+// IsHTML reports whether the response body is an HTML page, going by the
+// header or, without one, by sniffing
+func (r *Response) IsHTML() bool {
+	contentType, _ := r.ContentType()
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "text/html" || mediaType == "application/xhtml+xml"
+}
+
+// HTMLText extracts the readable text of an HTML body: scripts and styles
+// are dropped, whitespace is collapsed as a browser would, block elements
+// start new lines, list items get bullets and links are followed by their
+// target in brackets
+func (r *Response) HTMLText() string {
+	var w htmlTextWriter
+	w.newlines = 2 // no blank lines before the first text
+
+	tokenizer := html.NewTokenizer(strings.NewReader(r.Body))
+	hidden := ""  // the hidden element being skipped, if any
+	href := ""    // target of the link being read
+	preDepth := 0 // inside <pre>, whitespace is kept
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			break
+		}
+		token := tokenizer.Token()
+
+		if hidden != "" {
+			if tokenType == html.EndTagToken && token.Data == hidden {
+				hidden = ""
+			}
+			continue
+		}
+
+		switch tokenType {
+		case html.TextToken:
+			if preDepth > 0 {
+				w.writePre(token.Data)
+			} else {
+				w.writeText(token.Data)
+			}
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			switch name := token.Data; {
+			case htmlHiddenTags[name]:
+				if tokenType == html.StartTagToken {
+					hidden = name
+				}
+			case name == "br":
+				w.lineBreak(1)
+			case name == "li":
+				w.lineBreak(1)
+				w.writeWord("•")
+				w.space = true
+			case name == "td" || name == "th":
+				w.space = true
+			case name == "a":
+				href = htmlAttr(token, "href")
+			case name == "img":
+				if alt := strings.TrimSpace(htmlAttr(token, "alt")); alt != "" {
+					w.writeText("[" + alt + "]")
+				}
+			case htmlParagraphTags[name]:
+				w.lineBreak(2)
+			case htmlBlockTags[name]:
+				w.lineBreak(1)
+			}
+			if token.Data == "pre" && tokenType == html.StartTagToken {
+				preDepth++
+			}
+
+		case html.EndTagToken:
+			switch name := token.Data; {
+			case name == "a":
+				if href != "" && !strings.HasPrefix(href, "#") && !strings.HasPrefix(href, "javascript:") {
+					w.writeText(" [" + href + "]")
+				}
+				href = ""
+			case name == "pre":
+				preDepth = max(preDepth-1, 0)
+				w.lineBreak(2)
+			case htmlParagraphTags[name]:
+				w.lineBreak(2)
+			case htmlBlockTags[name] || name == "li":
+				w.lineBreak(1)
+			}
+		}
+	}
+
+	return strings.TrimSpace(w.b.String())
+}
+
+// htmlAttr returns the value of a tag's attribute, or "" without one
+func htmlAttr(token html.Token, key string) string {
+	for _, attr := range token.Attr {
+		if attr.Key == key {
+			return strings.TrimSpace(attr.Val)
+		}
+	}
+	return ""
+}
+
+// htmlTextWriter lays out extracted text, collapsing runs of whitespace
+// and line breaks
+type htmlTextWriter struct {
+	b        strings.Builder
+	newlines int  // line breaks ending the text so far
+	space    bool // whitespace was seen since the last word
+}
+
+// writeText writes text with its whitespace collapsed to single spaces
+func (w *htmlTextWriter) writeText(text string) {
+	if text != "" && strings.TrimLeft(text, " \t\r\n\f") != text {
+		w.space = true
+	}
+	for i, word := range strings.Fields(text) {
+		if i > 0 {
+			w.space = true
+		}
+		w.writeWord(word)
+	}
+	if text != "" && strings.TrimRight(text, " \t\r\n\f") != text {
+		w.space = true
+	}
+}
+
+// writeWord writes a word, after a space if whitespace came before it
+// on the same line
+func (w *htmlTextWriter) writeWord(word string) {
+	if w.space && w.newlines == 0 {
+		w.b.WriteByte(' ')
+	}
+	w.b.WriteString(word)
+	w.newlines = 0
+	w.space = false
+}
+
+// writePre writes preformatted text as it is
+func (w *htmlTextWriter) writePre(text string) {
+	if text == "" {
+		return
+	}
+	w.b.WriteString(text)
+	trimmed := strings.TrimRight(text, "\n")
+	w.newlines = len(text) - len(trimmed)
+	if trimmed == "" {
+		w.newlines = min(w.newlines, 2)
+	}
+	w.space = false
+}
+
+// lineBreak ends the line, leaving n-1 blank lines unless the text already
+// ends with that many
+func (w *htmlTextWriter) lineBreak(n int) {
+	for w.newlines < n {
+		w.b.WriteByte('\n')
+		w.newlines++
+	}
+	w.space = false
+}
@@ -0,0 +1,78 @@
+package api
+
+import "testing"
+
+func TestIsHTML(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		body   string
+		want   bool
+	}{
+		{"html header", "text/html; charset=utf-8", "<p>hi</p>", true},
+		{"xhtml header", "application/xhtml+xml", "<html/>", true},
+		{"sniffed", "", "<!DOCTYPE html><html><body>hi</body></html>", true},
+		{"json", "application/json", `{"a":1}`, false},
+		{"xml", "application/xml", "<a/>", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := &Response{Headers: map[string]string{}, Body: tt.body}
+			if tt.header != "" {
+				response.Headers["Content-Type"] = tt.header
+			}
+			if got := response.IsHTML(); got != tt.want {
+				t.Errorf("IsHTML() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTMLText(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			"paragraphs and headings",
+			"<html><head><title>Market</title><style>p { color: red }</style></head><body>\n<h1>Welcome</h1>\n<p>Hello,\n   <b>world</b>!</p><p>Second&nbsp;line &amp; more</p></body></html>",
+			"Market\n\nWelcome\n\nHello, world!\n\nSecond line & more",
+		},
+		{
+			"scripts are dropped",
+			"<div>before</div><script>var x = '<p>nope</p>';</script><div>after</div>",
+			"before\nafter",
+		},
+		{
+			"lists and links",
+			`<ul><li><a href="/about">About us</a></li><li><a href="#top">Top</a></li></ul>`,
+			"• About us [/about]\n• Top",
+		},
+		{
+			"line breaks and images",
+			`one<br>two<br/><img src="x.png" alt="logo">`,
+			"one\ntwo\n[logo]",
+		},
+		{
+			"preformatted text keeps its layout",
+			"<p>Code:</p><pre>a  b\n  c</pre><p>done</p>",
+			"Code:\n\na  b\n  c\n\ndone",
+		},
+		{
+			"table cells",
+			"<table><tr><th>Name</th><th>Price</th></tr><tr><td>Tea</td><td>2</td></tr></table>",
+			"Name Price\nTea 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := &Response{Headers: map[string]string{"Content-Type": "text/html"}, Body: tt.body}
+			if got := response.HTMLText(); got != tt.want {
+				t.Errorf("HTMLText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
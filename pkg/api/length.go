@@ -0,0 +1,40 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// mayHaveBody reports whether a response to method with status can carry a
+// body (RFC 9110 §6.4.1). HEAD responses, 1xx, 204 and 304 never do, though
+// their Content-Length describes the body a GET would have got.
+func mayHaveBody(method string, status int) bool {
+	if method == http.MethodHead {
+		return false
+	}
+	return status >= 200 && status != http.StatusNoContent && status != http.StatusNotModified
+}
+
+// checkBodyLength compares the bytes received against the declared
+// Content-Length. A body that ends early is reported rather than discarded,
+// since a partial response from an unstable circuit is still worth seeing.
+// net/http drops Content-Length when a response is also chunked (RFC 9112
+// §6.3), so that combination never reaches this check.
+func checkBodyLength(declared int64, received int, readErr error) (string, error) {
+	if readErr != nil {
+		if !errors.Is(readErr, io.ErrUnexpectedEOF) {
+			return "", readErr
+		}
+		if declared < 0 {
+			return fmt.Sprintf("chunked body ended before its final chunk after %d bytes - possibly truncated", received), nil
+		}
+		return fmt.Sprintf("declared %d bytes, received %d - possibly truncated", declared, received), nil
+	}
+
+	if declared >= 0 && int64(received) != declared {
+		return fmt.Sprintf("declared %d bytes, received %d", declared, received), nil
+	}
+	return "", nil
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// serveRaw answers one HTTP request with a raw response and closes the connection
+func serveRaw(t *testing.T, raw string) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if _, err := http.ReadRequest(bufio.NewReader(conn)); err == nil {
+			io.WriteString(conn, raw)
+		}
+	}()
+	return "http://" + listener.Addr().String() + "/"
+}
+
+func TestLengthMismatch(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		raw          string
+		wantBody     int
+		wantMismatch bool
+		wantWarning  string
+	}{
+		{
+			name:     "matching length",
+			raw:      "HTTP/1.1 200 OK\r\nContent-Length: 5\r\n\r\nhello",
+			wantBody: 5,
+		},
+		{
+			name:         "truncated body",
+			raw:          "HTTP/1.1 200 OK\r\nContent-Length: 1024\r\n\r\n" + strings.Repeat("x", 900),
+			wantBody:     900,
+			wantMismatch: true,
+			wantWarning:  "declared 1024 bytes, received 900 - possibly truncated",
+		},
+		{
+			name:         "chunked body cut short",
+			raw:          "HTTP/1.1 200 OK\r\nTransfer-Encoding: chunked\r\n\r\n5\r\nhello\r\n",
+			wantBody:     5,
+			wantMismatch: true,
+			wantWarning:  "chunked body ended before its final chunk after 5 bytes - possibly truncated",
+		},
+		{
+			name:   "HEAD with the length of the GET body",
+			method: "HEAD",
+			raw:    "HTTP/1.1 200 OK\r\nContent-Length: 1024\r\n\r\n",
+		},
+		{
+			name: "no content",
+			raw:  "HTTP/1.1 204 No Content\r\nContent-Length: 10\r\n\r\n",
+		},
+		{
+			name: "not modified",
+			raw:  "HTTP/1.1 304 Not Modified\r\nContent-Length: 100\r\n\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(&ClientConfig{TorEnabled: false, Timeout: 5 * time.Second})
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+
+			method := tt.method
+			if method == "" {
+				method = "GET"
+			}
+			resp, err := client.Send(NewRequest(method, serveRaw(t, tt.raw)))
+			if err != nil {
+				t.Fatalf("Send() error = %v", err)
+			}
+			if len(resp.Body) != tt.wantBody {
+				t.Errorf("len(Body) = %d, want %d", len(resp.Body), tt.wantBody)
+			}
+			if resp.LengthMismatch != tt.wantMismatch || resp.LengthWarning != tt.wantWarning {
+				t.Errorf("LengthMismatch = %v (%q), want %v (%q)",
+					resp.LengthMismatch, resp.LengthWarning, tt.wantMismatch, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestMayHaveBody(t *testing.T) {
+	tests := []struct {
+		method string
+		status int
+		want   bool
+	}{
+		{"GET", 200, true},
+		{"GET", 404, true},
+		{"HEAD", 200, false},
+		{"GET", 100, false},
+		{"GET", 103, false},
+		{"GET", 204, false},
+		{"GET", 304, false},
+	}
+
+	for _, tt := range tests {
+		if got := mayHaveBody(tt.method, tt.status); got != tt.want {
+			t.Errorf("mayHaveBody(%s, %d) = %v, want %v", tt.method, tt.status, got, tt.want)
+		}
+	}
+}
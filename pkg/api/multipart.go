@@ -0,0 +1,139 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"sort"
+	"strings"
+)
+
+// MultipartBody is a multipart/form-data body of form fields and files. The
+// files are streamed from disk when the request is sent.
+type MultipartBody struct {
+	Boundary string            `json:"boundary"`
+	Fields   map[string]string `json:"fields,omitempty"`
+	Files    map[string]string `json:"files,omitempty"` // field name -> file path
+}
+
+// SetMultipartBody makes the request a multipart/form-data upload of fields
+// and files (field name -> file path), replacing any other body and setting
+// Content-Type with the part boundary
+func (r *Request) SetMultipartBody(fields map[string]string, files map[string]string) {
+	boundary := multipart.NewWriter(io.Discard).Boundary()
+	r.Multipart = &MultipartBody{Boundary: boundary, Fields: fields, Files: files}
+	r.Body = ""
+	r.BodyFile = ""
+
+	for key := range r.Headers {
+		if strings.EqualFold(key, "Content-Type") {
+			delete(r.Headers, key)
+		}
+	}
+	r.SetHeader("Content-Type", "multipart/form-data; boundary="+boundary)
+}
+
+// validateFiles checks that every file to upload exists and is a regular file
+func (mb *MultipartBody) validateFiles() error {
+	for _, name := range sortedKeys(mb.Files) {
+		path := mb.Files[name]
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("file for form field %q: %w", name, err)
+		}
+		if info.IsDir() {
+			return fmt.Errorf("file for form field %q: %s is a directory", name, path)
+		}
+	}
+	return nil
+}
+
+// openMultipartBody lays out req.Multipart as a reader over the encoded
+// part headers and the open files, so files are streamed rather than read
+// into memory. It returns the reader, the exact body length for the
+// Content-Length header, and the files to close afterwards.
+func openMultipartBody(req *Request) (io.Reader, int64, io.Closer, error) {
+	mb := req.Multipart
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	if err := writer.SetBoundary(mb.Boundary); err != nil {
+		return nil, 0, nil, fmt.Errorf("invalid multipart boundary: %w", err)
+	}
+
+	var readers []io.Reader
+	var files multiCloser
+	var size int64
+	flush := func() {
+		if buf.Len() > 0 {
+			segment := bytes.Clone(buf.Bytes())
+			readers = append(readers, bytes.NewReader(segment))
+			size += int64(len(segment))
+			buf.Reset()
+		}
+	}
+
+	for _, name := range sortedKeys(mb.Fields) {
+		if err := writer.WriteField(name, mb.Fields[name]); err != nil {
+			return nil, 0, nil, err
+		}
+	}
+	for _, name := range sortedKeys(mb.Files) {
+		path := mb.Files[name]
+		file, err := os.Open(path)
+		if err != nil {
+			files.Close()
+			return nil, 0, nil, fmt.Errorf("failed to open file for form field %q: %w", name, err)
+		}
+		files = append(files, file)
+
+		info, err := file.Stat()
+		if err != nil {
+			files.Close()
+			return nil, 0, nil, fmt.Errorf("failed to stat file for form field %q: %w", name, err)
+		}
+		if _, err := writer.CreateFormFile(name, info.Name()); err != nil {
+			files.Close()
+			return nil, 0, nil, err
+		}
+		flush()
+		readers = append(readers, file)
+		size += info.Size()
+	}
+	if err := writer.Close(); err != nil {
+		files.Close()
+		return nil, 0, nil, err
+	}
+	flush()
+
+	reader := &countingReader{
+		reader:   io.MultiReader(readers...),
+		total:    size,
+		progress: req.UploadProgress,
+	}
+	return reader, size, files, nil
+}
+
+// multiCloser closes several files
+type multiCloser []io.Closer
+
+func (mc multiCloser) Close() error {
+	var first error
+	for _, c := range mc {
+		if err := c.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// sortedKeys returns m's keys in order, so parts are written deterministically
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
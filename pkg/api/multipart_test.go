@@ -0,0 +1,116 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSendMultipartBody(t *testing.T) {
+	dir := t.TempDir()
+	avatar := filepath.Join(dir, "avatar.png")
+	if err := os.WriteFile(avatar, []byte("PNG-BYTES"), 0644); err != nil {
+		t.Fatalf("Failed to write temp file: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		file, header, err := r.FormFile("avatar")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		content, _ := io.ReadAll(file)
+		fmt.Fprintf(w, "%s|%s|%s|%s|%v", r.FormValue("user"), r.FormValue("note"), header.Filename, content, r.ContentLength > 0)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(&ClientConfig{TorEnabled: false, Timeout: 30 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	req := NewRequest("POST", server.URL)
+	req.SetHeader("content-type", "application/json")
+	req.SetMultipartBody(map[string]string{"user": "alice", "note": "hi there"}, map[string]string{"avatar": avatar})
+
+	if got := req.Headers["Content-Type"]; got != "multipart/form-data; boundary="+req.Multipart.Boundary {
+		t.Errorf("Content-Type = %q, want multipart/form-data with the boundary", got)
+	}
+	if _, ok := req.Headers["content-type"]; ok {
+		t.Error("Expected the old content-type header to be replaced")
+	}
+
+	var lastSent, lastTotal int64
+	req.UploadProgress = func(sent, total int64) { lastSent, lastTotal = sent, total }
+
+	resp, err := client.Send(req)
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if want := "alice|hi there|avatar.png|PNG-BYTES|true"; resp.Body != want {
+		t.Errorf("Server saw %q, want %q", resp.Body, want)
+	}
+	if lastTotal == 0 || lastSent != lastTotal {
+		t.Errorf("Expected progress to end at the total, got %d/%d", lastSent, lastTotal)
+	}
+}
+
+func TestMultipartBodyLength(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.bin")
+	if err := os.WriteFile(path, []byte(strings.Repeat("x", 5000)), 0644); err != nil {
+		t.Fatalf("Failed to write temp file: %v", err)
+	}
+
+	req := NewRequest("POST", "http://example.onion/upload")
+	req.SetMultipartBody(map[string]string{"a": "1"}, map[string]string{"file": path})
+
+	reader, size, files, err := openMultipartBody(req)
+	if err != nil {
+		t.Fatalf("openMultipartBody() error = %v", err)
+	}
+	defer files.Close()
+
+	body, _ := io.ReadAll(reader)
+	if int64(len(body)) != size {
+		t.Errorf("Declared length %d, body is %d bytes", size, len(body))
+	}
+	if !strings.HasSuffix(string(body), "--"+req.Multipart.Boundary+"--\r\n") {
+		t.Errorf("Body does not end with the closing boundary: %q", string(body[len(body)-60:]))
+	}
+}
+
+func TestMultipartValidation(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name  string
+		files map[string]string
+		body  string
+	}{
+		{name: "missing file", files: map[string]string{"file": filepath.Join(dir, "missing.bin")}},
+		{name: "directory", files: map[string]string{"file": dir}},
+		{name: "body as well", body: "raw"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := NewRequest("POST", "http://example.onion/upload")
+			req.SetMultipartBody(map[string]string{"a": "1"}, tt.files)
+			req.Body = tt.body
+			if err := req.Validate(); err == nil {
+				t.Error("Expected a validation error")
+			}
+		})
+	}
+}
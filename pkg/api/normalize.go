@@ -0,0 +1,154 @@
+package api
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Characters allowed unescaped in each URL component, in addition to
+// letters, digits and the unreserved marks "-._~" (RFC 3986)
+const (
+	pathChars     = "/:@!$&'()*+,;="
+	queryChars    = pathChars + "?"
+	fragmentChars = queryChars
+)
+
+// schemeLike matches a leading "scheme:" such as "mailto:", but not the
+// "host:port" of a URL typed without a scheme
+var schemeLike = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*:([^0-9]|$)`)
+
+// NormalizeURL validates a URL and percent-encodes any characters in its
+// path, query, and fragment that are not allowed there, such as spaces or
+// non-ASCII text. Existing %XX escapes are left alone, so already encoded URLs
+// are not double-encoded. It returns the normalized URL and a description of
+// each fix applied. A URL without a scheme gets AssumedScheme's, reported as
+// an "assumed http://" fix.
+func NormalizeURL(raw string) (string, []string, error) {
+	var fixes []string
+
+	trimmed := strings.TrimSpace(raw)
+	if trimmed != raw {
+		fixes = append(fixes, "trimmed surrounding whitespace")
+	}
+	if trimmed == "" {
+		return "", nil, fmt.Errorf("URL is required")
+	}
+
+	// Split off scheme://host before touching the rest
+	schemeEnd := strings.Index(trimmed, "://")
+	if schemeEnd < 0 && !schemeLike.MatchString(trimmed) {
+		scheme := AssumedScheme(trimmed)
+		trimmed = scheme + "://" + strings.TrimPrefix(trimmed, "//")
+		schemeEnd = len(scheme)
+		fixes = append(fixes, "assumed "+scheme+"://")
+	}
+	if schemeEnd <= 0 {
+		return "", nil, fmt.Errorf("URL must start with http:// or https://")
+	}
+	rest := trimmed[schemeEnd+3:]
+	authorityEnd := strings.IndexAny(rest, "/?#")
+	if authorityEnd < 0 {
+		authorityEnd = len(rest)
+	}
+	prefix := trimmed[:schemeEnd+3+authorityEnd]
+	rest = rest[authorityEnd:]
+
+	var fragment, query string
+	hasFragment, hasQuery := false, false
+	if i := strings.Index(rest, "#"); i >= 0 {
+		fragment, rest, hasFragment = rest[i+1:], rest[:i], true
+	}
+	if i := strings.Index(rest, "?"); i >= 0 {
+		query, rest, hasQuery = rest[i+1:], rest[:i], true
+	}
+	path := rest
+
+	normalized := prefix
+	if encoded := encodeComponent(path, pathChars); encoded != path {
+		fixes = append(fixes, fmt.Sprintf("encoded path %q as %q", path, encoded))
+		path = encoded
+	}
+	normalized += path
+
+	if hasQuery {
+		if encoded := encodeComponent(query, queryChars); encoded != query {
+			fixes = append(fixes, fmt.Sprintf("encoded query %q as %q", query, encoded))
+			query = encoded
+		}
+		normalized += "?" + query
+	}
+
+	if hasFragment {
+		if encoded := encodeComponent(fragment, fragmentChars); encoded != fragment {
+			fixes = append(fixes, fmt.Sprintf("encoded fragment %q as %q", fragment, encoded))
+			fragment = encoded
+		}
+		normalized += "#" + fragment
+	}
+
+	u, err := url.Parse(normalized)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	if !isSupportedScheme(u.Scheme) {
+		return "", nil, fmt.Errorf("unsupported scheme: %s (use http, https, ws or wss)", u.Scheme)
+	}
+	if u.Host == "" {
+		return "", nil, fmt.Errorf("URL must include a host")
+	}
+
+	return normalized, fixes, nil
+}
+
+// AssumedScheme returns the scheme used for a URL typed without one: http
+// for onion services, which usually run without TLS, and https for
+// everything else
+func AssumedScheme(raw string) string {
+	authority := strings.TrimPrefix(strings.TrimSpace(raw), "//")
+	if end := strings.IndexAny(authority, "/?#"); end >= 0 {
+		authority = authority[:end]
+	}
+	if at := strings.LastIndex(authority, "@"); at >= 0 {
+		authority = authority[at+1:]
+	}
+	host := authority
+	if h, _, err := net.SplitHostPort(authority); err == nil {
+		host = h
+	}
+	if strings.HasSuffix(strings.ToLower(host), ".onion") {
+		return "http"
+	}
+	return "https"
+}
+
+// encodeComponent percent-encodes every byte of s that is neither unreserved,
+// in allowed, nor part of an existing valid %XX escape
+func encodeComponent(s, allowed string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]):
+			b.WriteByte(c)
+		case isUnreserved(c) || (c < 0x80 && strings.IndexByte(allowed, c) >= 0):
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// isUnreserved reports whether c is an RFC 3986 unreserved character
+func isUnreserved(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+// isHex reports whether c is a hexadecimal digit
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
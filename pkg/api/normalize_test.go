@@ -0,0 +1,80 @@
+package api
+
+import "testing"
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		raw       string
+		expected  string
+		wantFixes bool
+		wantErr   bool
+	}{
+		{"already valid", "http://example.onion/api/users?id=1", "http://example.onion/api/users?id=1", false, false},
+		{"space in path", "http://example.onion/my path", "http://example.onion/my%20path", true, false},
+		{"space in query", "http://example.onion/search?q=hello world", "http://example.onion/search?q=hello%20world", true, false},
+		{"unicode path", "http://example.onion/café", "http://example.onion/caf%C3%A9", true, false},
+		{"already encoded", "http://example.onion/my%20path?q=a%2Fb", "http://example.onion/my%20path?q=a%2Fb", false, false},
+		{"partially encoded", "http://example.onion/a%20b c", "http://example.onion/a%20b%20c", true, false},
+		{"stray percent", "http://example.onion/100%", "http://example.onion/100%25", true, false},
+		{"fragment", "http://example.onion/#sec tion", "http://example.onion/#sec%20tion", true, false},
+		{"surrounding whitespace", "  http://example.onion/  ", "http://example.onion/", true, false},
+		{"no path", "http://example.onion", "http://example.onion", false, false},
+		{"scheme-less onion", "example.onion/path", "http://example.onion/path", true, false},
+		{"scheme-less onion with port", "example.onion:8080", "http://example.onion:8080", true, false},
+		{"scheme-less clearnet", "api.example.com/v1?q=1", "https://api.example.com/v1?q=1", true, false},
+		{"scheme-less localhost", "localhost:8080/health", "https://localhost:8080/health", true, false},
+		{"protocol-relative", "//example.onion/", "http://example.onion/", true, false},
+		{"non-http scheme without slashes", "mailto:admin@example.onion", "", false, true},
+		{"unsupported scheme", "ftp://example.onion/file", "", false, true},
+		{"missing host", "http:///path", "", false, true},
+		{"empty", "   ", "", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, fixes, err := NormalizeURL(tt.raw)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("NormalizeURL(%q) expected error, got %q", tt.raw, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeURL(%q) unexpected error: %v", tt.raw, err)
+			}
+			if got != tt.expected {
+				t.Errorf("NormalizeURL(%q) = %q, expected %q", tt.raw, got, tt.expected)
+			}
+			if (len(fixes) > 0) != tt.wantFixes {
+				t.Errorf("NormalizeURL(%q) fixes = %v, wantFixes %v", tt.raw, fixes, tt.wantFixes)
+			}
+
+			// Normalizing again must be a no-op
+			again, fixes, err := NormalizeURL(got)
+			if err != nil || again != got || len(fixes) > 0 {
+				t.Errorf("NormalizeURL(%q) is not idempotent: %q, %v, %v", got, again, fixes, err)
+			}
+		})
+	}
+}
+
+func TestAssumedScheme(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"example.onion", "http"},
+		{"EXAMPLE.ONION/path", "http"},
+		{"user:pass@example.onion:8080/x", "http"},
+		{"example.com", "https"},
+		{"onion.example.com/example.onion", "https"},
+		{"127.0.0.1:8080", "https"},
+	}
+
+	for _, tt := range tests {
+		if got := AssumedScheme(tt.raw); got != tt.want {
+			t.Errorf("AssumedScheme(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
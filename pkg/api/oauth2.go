@@ -0,0 +1,169 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// oauth2RefreshMargin is how long before expiry a cached token is replaced,
+// so a request sent over a slow circuit doesn't arrive with an expired one
+const oauth2RefreshMargin = 30 * time.Second
+
+// oauth2Token is an access token cached from a token endpoint
+type oauth2Token struct {
+	accessToken string
+	refreshAt   time.Time // zero when the endpoint gave no expires_in
+}
+
+// fresh reports whether the token can still be sent at now
+func (t oauth2Token) fresh(now time.Time) bool {
+	return t.refreshAt.IsZero() || now.Before(t.refreshAt)
+}
+
+// oauth2RefreshAt is when a token issued at issued and valid for lifetime
+// should be replaced: 30s before it expires, or halfway through a lifetime
+// too short for that
+func oauth2RefreshAt(issued time.Time, lifetime time.Duration) time.Time {
+	if lifetime <= 2*oauth2RefreshMargin {
+		return issued.Add(lifetime / 2)
+	}
+	return issued.Add(lifetime - oauth2RefreshMargin)
+}
+
+// oauth2TokenResponse is the token endpoint's JSON answer, success or error
+type oauth2TokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int    `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// SetClient sets the client OAuth2 token requests are sent with, so they
+// take the same Tor route as the requests they authenticate
+func (am *AuthManager) SetClient(client *Client) {
+	am.client = client
+}
+
+// OAuth2Token returns an access token for an OAuth2 client-credentials
+// config, from the cache while it is more than 30s from expiring and from
+// the token endpoint otherwise
+func (am *AuthManager) OAuth2Token(ctx context.Context, config *AuthConfig) (string, error) {
+	resolved, missing := am.resolveEnvRefs(config)
+	token, err := am.oauth2Token(ctx, resolved)
+	if err != nil && len(missing) > 0 {
+		err = fmt.Errorf("%w (environment variables not set: %s)", err, strings.Join(missing, ", "))
+	}
+	return token, err
+}
+
+// HasOAuth2Token reports whether a token for config is cached and fresh, so
+// applying the config won't contact the token endpoint
+func (am *AuthManager) HasOAuth2Token(config *AuthConfig) bool {
+	resolved, _ := am.resolveEnvRefs(config)
+	am.tokensMu.Lock()
+	defer am.tokensMu.Unlock()
+	token, ok := am.tokens[oauth2CacheKey(resolved)]
+	return ok && token.fresh(time.Now())
+}
+
+// applyOAuth2Auth applies OAuth2 client-credentials authentication
+func (am *AuthManager) applyOAuth2Auth(req *Request, config *AuthConfig) error {
+	token, err := am.oauth2Token(context.Background(), config)
+	if err != nil {
+		return err
+	}
+	req.SetHeader("Authorization", "Bearer "+token)
+	return nil
+}
+
+// oauth2Token returns a cached token for the resolved config, or fetches one
+func (am *AuthManager) oauth2Token(ctx context.Context, config *AuthConfig) (string, error) {
+	key := oauth2CacheKey(config)
+	am.tokensMu.Lock()
+	token, ok := am.tokens[key]
+	am.tokensMu.Unlock()
+	if ok && token.fresh(time.Now()) {
+		return token.accessToken, nil
+	}
+
+	token, err := am.fetchOAuth2Token(ctx, config)
+	if err != nil {
+		return "", err
+	}
+
+	am.tokensMu.Lock()
+	if am.tokens == nil {
+		am.tokens = make(map[string]oauth2Token)
+	}
+	am.tokens[key] = token
+	am.tokensMu.Unlock()
+	return token.accessToken, nil
+}
+
+// fetchOAuth2Token asks the token endpoint for a client-credentials token,
+// authenticating the client with HTTP Basic as RFC 6749 section 2.3.1 asks
+// every server to support
+func (am *AuthManager) fetchOAuth2Token(ctx context.Context, config *AuthConfig) (oauth2Token, error) {
+	if am.client == nil {
+		return oauth2Token{}, fmt.Errorf("OAuth2 token request needs a client")
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if config.Scope != "" {
+		form.Set("scope", config.Scope)
+	}
+	req := NewRequest("POST", config.TokenURL)
+	req.SetHeader("Content-Type", "application/x-www-form-urlencoded")
+	req.SetHeader("Accept", "application/json")
+	req.SetBody(form.Encode())
+	if err := am.applyBasicAuth(req, &AuthConfig{
+		Username: url.QueryEscape(config.ClientID),
+		Password: url.QueryEscape(config.ClientSecret),
+	}); err != nil {
+		return oauth2Token{}, err
+	}
+
+	requested := time.Now()
+	resp, err := am.client.SendContext(ctx, req)
+	if err != nil {
+		return oauth2Token{}, fmt.Errorf("OAuth2 token request failed: %w", err)
+	}
+
+	var body oauth2TokenResponse
+	jsonErr := json.Unmarshal([]byte(resp.Body), &body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if jsonErr == nil && body.Error != "" {
+			if body.ErrorDescription != "" {
+				return oauth2Token{}, fmt.Errorf("OAuth2 token endpoint returned %s: %s", body.Error, body.ErrorDescription)
+			}
+			return oauth2Token{}, fmt.Errorf("OAuth2 token endpoint returned %s", body.Error)
+		}
+		return oauth2Token{}, fmt.Errorf("OAuth2 token endpoint returned %s", resp.Status)
+	}
+	if jsonErr != nil {
+		return oauth2Token{}, fmt.Errorf("OAuth2 token response is not JSON: %w", jsonErr)
+	}
+	if body.AccessToken == "" {
+		return oauth2Token{}, fmt.Errorf("OAuth2 token response has no access_token")
+	}
+	if body.TokenType != "" && !strings.EqualFold(body.TokenType, "bearer") {
+		return oauth2Token{}, fmt.Errorf("OAuth2 token type %q is not supported, only bearer", body.TokenType)
+	}
+
+	token := oauth2Token{accessToken: body.AccessToken}
+	if body.ExpiresIn > 0 {
+		// Count from when the request was sent, the earliest it could have been issued
+		token.refreshAt = oauth2RefreshAt(requested, time.Duration(body.ExpiresIn)*time.Second)
+	}
+	return token, nil
+}
+
+// oauth2CacheKey identifies the token a resolved config is issued
+func oauth2CacheKey(config *AuthConfig) string {
+	return strings.Join([]string{config.TokenURL, config.ClientID, config.ClientSecret, config.Scope}, "\x00")
+}
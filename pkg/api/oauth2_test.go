@@ -0,0 +1,145 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// tokenServer issues numbered tokens to client "cli" with secret "s3cret"
+func tokenServer(t *testing.T, expiresIn int) (*httptest.Server, *int32) {
+	var issued int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		clientID, secret, ok := r.BasicAuth()
+		if !ok || clientID != "cli" || secret != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":"invalid_client","error_description":"bad credentials"}`)
+			return
+		}
+		if r.Method != "POST" || r.FormValue("grant_type") != "client_credentials" || r.FormValue("scope") != "read write" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":"invalid_request"}`)
+			return
+		}
+		n := atomic.AddInt32(&issued, 1)
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":%d}`, n, expiresIn)
+	}))
+	t.Cleanup(server.Close)
+	return server, &issued
+}
+
+func newOAuth2Manager(t *testing.T) *AuthManager {
+	client, err := NewClient(&ClientConfig{TorEnabled: false, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	am := NewAuthManager()
+	am.SetClient(client)
+	return am
+}
+
+func TestApplyOAuth2Auth(t *testing.T) {
+	server, issued := tokenServer(t, 3600)
+	am := newOAuth2Manager(t)
+	config := &AuthConfig{Type: AuthOAuth2, TokenURL: server.URL, ClientID: "cli", ClientSecret: "s3cret", Scope: "read write"}
+
+	if am.HasOAuth2Token(config) {
+		t.Error("Expected no cached token before the first request")
+	}
+	for i := 0; i < 2; i++ {
+		req := NewRequest("GET", "http://example.com/")
+		if err := am.ApplyAuth(req, config); err != nil {
+			t.Fatalf("ApplyAuth() error = %v", err)
+		}
+		if got := req.Headers["Authorization"]; got != "Bearer token-1" {
+			t.Errorf("Authorization = %q, want the cached token", got)
+		}
+	}
+	if got := atomic.LoadInt32(issued); got != 1 {
+		t.Errorf("Expected 1 token request, got %d", got)
+	}
+	if !am.HasOAuth2Token(config) {
+		t.Error("Expected the token to be cached")
+	}
+
+	// Within 30s of expiry the token is replaced
+	key := oauth2CacheKey(config)
+	am.tokens[key] = oauth2Token{accessToken: "token-1", refreshAt: time.Now().Add(-time.Second)}
+	token, err := am.OAuth2Token(context.Background(), config)
+	if err != nil {
+		t.Fatalf("OAuth2Token() error = %v", err)
+	}
+	if token != "token-2" {
+		t.Errorf("OAuth2Token() = %q, want a refreshed token", token)
+	}
+}
+
+func TestOAuth2TokenErrors(t *testing.T) {
+	server, _ := tokenServer(t, 3600)
+	am := newOAuth2Manager(t)
+
+	_, err := am.OAuth2Token(context.Background(), &AuthConfig{
+		Type: AuthOAuth2, TokenURL: server.URL, ClientID: "cli", ClientSecret: "wrong", Scope: "read write",
+	})
+	if err == nil || !strings.Contains(err.Error(), "invalid_client: bad credentials") {
+		t.Errorf("Expected the endpoint's error, got %v", err)
+	}
+
+	_, err = NewAuthManager().OAuth2Token(context.Background(), &AuthConfig{
+		Type: AuthOAuth2, TokenURL: server.URL, ClientID: "cli", ClientSecret: "s3cret",
+	})
+	if err == nil {
+		t.Error("Expected an error without a client")
+	}
+}
+
+func TestOAuth2RefreshAt(t *testing.T) {
+	issued := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		lifetime time.Duration
+		want     time.Duration
+	}{
+		{time.Hour, time.Hour - 30*time.Second},
+		{90 * time.Second, time.Minute},
+		{time.Minute, 30 * time.Second},
+		{20 * time.Second, 10 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := oauth2RefreshAt(issued, tt.lifetime).Sub(issued); got != tt.want {
+			t.Errorf("oauth2RefreshAt(%v) = +%v, want +%v", tt.lifetime, got, tt.want)
+		}
+	}
+}
+
+func TestCreateOAuth2AuthConfig(t *testing.T) {
+	am := NewAuthManager()
+	config, err := am.CreateAuthConfigFromInput(AuthOAuth2, map[string]string{
+		"token_url": " http://example.onion/token ", "client_id": "cli", "client_secret": "s3cret", "scope": "read",
+	})
+	if err != nil {
+		t.Fatalf("CreateAuthConfigFromInput() error = %v", err)
+	}
+	if config.TokenURL != "http://example.onion/token" || config.ClientID != "cli" || config.Scope != "read" {
+		t.Errorf("Unexpected config %+v", config)
+	}
+
+	if _, err := am.CreateAuthConfigFromInput(AuthOAuth2, map[string]string{"token_url": "example.onion/token", "client_id": "cli", "client_secret": "s3cret"}); err == nil {
+		t.Error("Expected an error for a token URL without a scheme")
+	}
+	if _, err := am.CreateAuthConfigFromInput(AuthOAuth2, map[string]string{"token_url": "http://example.onion/token", "client_id": "cli"}); err == nil {
+		t.Error("Expected an error without a client secret")
+	}
+
+	if masked := am.MaskSensitiveData(config); masked.ClientSecret == "s3cret" {
+		t.Error("Expected the client secret to be masked")
+	}
+	if stripped := StripSecrets(config); stripped.ClientSecret != "" {
+		t.Error("Expected the client secret to be stripped")
+	}
+}
@@ -0,0 +1,193 @@
+package api
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ResponsePostProcessor transforms a response body before it is displayed,
+// e.g. to decode or decrypt a payload the service wraps in its own encoding
+type ResponsePostProcessor interface {
+	// Name is the identifier requests use to select the processor
+	Name() string
+	// Process returns the transformed body. params come from the request's
+	// post-processing step, e.g. a decryption key.
+	Process(body []byte, params map[string]string) ([]byte, error)
+}
+
+// PostProcessStep selects a registered post-processor and its parameters
+type PostProcessStep struct {
+	Processor string            `json:"processor"`
+	Params    map[string]string `json:"params,omitempty"`
+}
+
+var (
+	postProcessorsMu sync.RWMutex
+	postProcessors   = make(map[string]ResponsePostProcessor)
+)
+
+func init() {
+	RegisterPostProcessor(base64Processor{})
+	RegisterPostProcessor(aesGCMProcessor{})
+}
+
+// RegisterPostProcessor makes a post-processor available by name, replacing
+// any processor already registered under that name
+func RegisterPostProcessor(processor ResponsePostProcessor) {
+	postProcessorsMu.Lock()
+	defer postProcessorsMu.Unlock()
+	postProcessors[processor.Name()] = processor
+}
+
+// LookupPostProcessor returns the post-processor registered under name
+func LookupPostProcessor(name string) (ResponsePostProcessor, bool) {
+	postProcessorsMu.RLock()
+	defer postProcessorsMu.RUnlock()
+	processor, ok := postProcessors[name]
+	return processor, ok
+}
+
+// PostProcessorNames returns the names of all registered post-processors
+func PostProcessorNames() []string {
+	postProcessorsMu.RLock()
+	defer postProcessorsMu.RUnlock()
+	names := make([]string, 0, len(postProcessors))
+	for name := range postProcessors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyPostProcessors runs the steps over the response body in order and
+// records each one in Transforms. If a step fails the body is left unchanged.
+func (r *Response) ApplyPostProcessors(steps []PostProcessStep) error {
+	body := []byte(r.Body)
+	var transforms []string
+	for _, step := range steps {
+		processor, ok := LookupPostProcessor(step.Processor)
+		if !ok {
+			return fmt.Errorf("unknown post-processor %q (available: %s)", step.Processor, strings.Join(PostProcessorNames(), ", "))
+		}
+
+		processed, err := processor.Process(body, step.Params)
+		if err != nil {
+			return fmt.Errorf("post-processor %s: %w", step.Processor, err)
+		}
+		body = processed
+		transforms = append(transforms, step.Processor)
+	}
+
+	if len(transforms) > 0 {
+		r.Body = string(body)
+		r.RawBody = body
+		r.Transforms = append(r.Transforms, transforms...)
+		r.bodyHash = ""
+	}
+	return nil
+}
+
+// base64Processor decodes a base64 body. The "encoding" param selects "std"
+// or "url"; without it both alphabets, padded or not, are tried.
+type base64Processor struct{}
+
+func (base64Processor) Name() string { return "base64" }
+
+func (base64Processor) Process(body []byte, params map[string]string) ([]byte, error) {
+	return decodeBase64(strings.TrimSpace(string(body)), params["encoding"])
+}
+
+// decodeBase64 decodes standard or URL-safe base64, with or without padding
+func decodeBase64(value, encoding string) ([]byte, error) {
+	var encodings []*base64.Encoding
+	switch encoding {
+	case "std":
+		encodings = []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding}
+	case "url":
+		encodings = []*base64.Encoding{base64.URLEncoding, base64.RawURLEncoding}
+	case "":
+		encodings = []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding}
+	default:
+		return nil, fmt.Errorf("unknown base64 encoding %q (use 'std' or 'url')", encoding)
+	}
+
+	var err error
+	for _, enc := range encodings {
+		var decoded []byte
+		if decoded, err = enc.DecodeString(value); err == nil {
+			return decoded, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid base64: %w", err)
+}
+
+// aesGCMProcessor decrypts an AES-GCM body laid out as nonce || ciphertext.
+// Params: "key" (hex or base64, 16/24/32 bytes), "input" ("base64" by
+// default, or "raw" for binary bodies) and an optional "aad".
+type aesGCMProcessor struct{}
+
+func (aesGCMProcessor) Name() string { return "aes-gcm" }
+
+func (aesGCMProcessor) Process(body []byte, params map[string]string) ([]byte, error) {
+	key, err := decodeKey(params["key"])
+	if err != nil {
+		return nil, err
+	}
+
+	switch params["input"] {
+	case "", "base64":
+		if body, err = decodeBase64(strings.TrimSpace(string(body)), ""); err != nil {
+			return nil, err
+		}
+	case "raw":
+	default:
+		return nil, fmt.Errorf("unknown input %q (use 'base64' or 'raw')", params["input"])
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) < gcm.NonceSize() {
+		return nil, fmt.Errorf("body is shorter than the %d-byte nonce", gcm.NonceSize())
+	}
+
+	nonce, ciphertext := body[:gcm.NonceSize()], body[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, []byte(params["aad"]))
+	if err != nil {
+		return nil, fmt.Errorf("decryption failed (wrong key or corrupted body)")
+	}
+	return plaintext, nil
+}
+
+// decodeKey decodes a hex or base64 AES key
+func decodeKey(value string) ([]byte, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, fmt.Errorf("key is required")
+	}
+
+	key, err := hex.DecodeString(value)
+	if err != nil {
+		if key, err = decodeBase64(value, ""); err != nil {
+			return nil, fmt.Errorf("key must be hex or base64")
+		}
+	}
+
+	switch len(key) {
+	case 16, 24, 32:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("key must be 16, 24 or 32 bytes, got %d", len(key))
+	}
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// encryptGCM seals plaintext as nonce || ciphertext for the AES processor tests
+func encryptGCM(t *testing.T, key []byte, plaintext, aad string) []byte {
+	t.Helper()
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatalf("Failed to create cipher: %v", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatalf("Failed to create GCM: %v", err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	for i := range nonce {
+		nonce[i] = byte(i)
+	}
+	return gcm.Seal(nonce, nonce, []byte(plaintext), []byte(aad))
+}
+
+func TestBase64PostProcessor(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		params   map[string]string
+		expected string
+		wantErr  bool
+	}{
+		{"standard", base64.StdEncoding.EncodeToString([]byte(`{"ok":true}`)) + "\n", nil, `{"ok":true}`, false},
+		{"unpadded", base64.RawStdEncoding.EncodeToString([]byte("hi!?")), nil, "hi!?", false},
+		{"url safe", base64.URLEncoding.EncodeToString([]byte{0xfb, 0xff}), map[string]string{"encoding": "url"}, "\xfb\xff", false},
+		{"wrong alphabet", base64.URLEncoding.EncodeToString([]byte{0xfb, 0xff}), map[string]string{"encoding": "std"}, "", true},
+		{"not base64", "not base64!", nil, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &Response{Body: tt.body}
+			err := resp.ApplyPostProcessors([]PostProcessStep{{Processor: "base64", Params: tt.params}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr {
+				if resp.Body != tt.body || len(resp.Transforms) != 0 {
+					t.Errorf("Expected body to be unchanged after an error, got %q %v", resp.Body, resp.Transforms)
+				}
+				return
+			}
+			if resp.Body != tt.expected {
+				t.Errorf("Expected body %q, got %q", tt.expected, resp.Body)
+			}
+			if len(resp.Transforms) != 1 || resp.Transforms[0] != "base64" {
+				t.Errorf("Expected transforms [base64], got %v", resp.Transforms)
+			}
+		})
+	}
+}
+
+func TestAESGCMPostProcessor(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	hexKey := hex.EncodeToString(key)
+	sealed := encryptGCM(t, key, `{"secret":"onion"}`, "")
+
+	tests := []struct {
+		name    string
+		body    string
+		params  map[string]string
+		wantErr string
+	}{
+		{"base64 body, hex key", base64.StdEncoding.EncodeToString(sealed), map[string]string{"key": hexKey}, ""},
+		{"raw body, base64 key", string(sealed), map[string]string{"key": base64.StdEncoding.EncodeToString(key), "input": "raw"}, ""},
+		{"with aad", base64.StdEncoding.EncodeToString(encryptGCM(t, key, `{"secret":"onion"}`, "v1")), map[string]string{"key": hexKey, "aad": "v1"}, ""},
+		{"wrong key", base64.StdEncoding.EncodeToString(sealed), map[string]string{"key": strings.Repeat("00", 32)}, "decryption failed"},
+		{"missing key", base64.StdEncoding.EncodeToString(sealed), nil, "key is required"},
+		{"short key", base64.StdEncoding.EncodeToString(sealed), map[string]string{"key": "abcd"}, "16, 24 or 32 bytes"},
+		{"truncated body", base64.StdEncoding.EncodeToString(sealed[:4]), map[string]string{"key": hexKey}, "nonce"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &Response{Body: tt.body}
+			err := resp.ApplyPostProcessors([]PostProcessStep{{Processor: "aes-gcm", Params: tt.params}})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if resp.Body != `{"secret":"onion"}` {
+				t.Errorf("Expected decrypted body, got %q", resp.Body)
+			}
+		})
+	}
+}
+
+func TestApplyPostProcessorsChain(t *testing.T) {
+	resp := &Response{Body: base64.StdEncoding.EncodeToString([]byte(base64.StdEncoding.EncodeToString([]byte("inner"))))}
+	hash := resp.BodyHash()
+
+	steps := []PostProcessStep{{Processor: "base64"}, {Processor: "base64"}}
+	if err := resp.ApplyPostProcessors(steps); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resp.Body != "inner" {
+		t.Errorf("Expected body %q, got %q", "inner", resp.Body)
+	}
+	if resp.BodyHash() == hash {
+		t.Error("Expected body hash to be recomputed after post-processing")
+	}
+
+	err := resp.ApplyPostProcessors([]PostProcessStep{{Processor: "rot13"}})
+	if err == nil || !strings.Contains(err.Error(), "unknown post-processor") {
+		t.Errorf("Expected unknown post-processor error, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// ProxySourceConfig indicates the Tor proxy address came from configuration
+const ProxySourceConfig = "config"
+
+// proxyEnvVars lists the environment variables checked for a SOCKS proxy, in order
+var proxyEnvVars = []string{"ALL_PROXY", "all_proxy", "SOCKS_PROXY", "socks_proxy"}
+
+// ParseProxyURL parses a SOCKS proxy URL such as socks5://host:port or
+// socks5h://host:port and returns the host:port address
+func ParseProxyURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", fmt.Errorf("proxy URL is empty")
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid proxy URL: %w", err)
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "socks5", "socks5h":
+	default:
+		return "", fmt.Errorf("unsupported proxy scheme: %q (use socks5 or socks5h)", u.Scheme)
+	}
+
+	host, port, err := net.SplitHostPort(u.Host)
+	if err != nil {
+		return "", fmt.Errorf("proxy URL must include host and port: %w", err)
+	}
+	if host == "" || port == "" {
+		return "", fmt.Errorf("proxy URL must include host and port")
+	}
+
+	return net.JoinHostPort(host, port), nil
+}
+
+// ProxyFromEnvironment returns the SOCKS proxy address from the first supported
+// environment variable, along with the variable name it was read from
+func ProxyFromEnvironment() (addr string, source string, ok bool) {
+	for _, name := range proxyEnvVars {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+
+		addr, err := ParseProxyURL(value)
+		if err != nil {
+			continue // Not a SOCKS proxy we can use
+		}
+		return addr, name, true
+	}
+
+	return "", "", false
+}
+
+// ResolveTorProxy picks the Tor proxy to use. The configured address is preferred;
+// if autoDetect is set and it does not accept connections, a SOCKS proxy from the
+// environment is used instead. The returned source is ProxySourceConfig or the
+// name of the environment variable used.
+func ResolveTorProxy(configured string, autoDetect bool) (addr string, source string) {
+	if !autoDetect || proxyReachable(configured) {
+		return configured, ProxySourceConfig
+	}
+
+	if envAddr, envName, ok := ProxyFromEnvironment(); ok {
+		return envAddr, envName
+	}
+
+	return configured, ProxySourceConfig
+}
+
+// proxyReachable checks whether a proxy is accepting TCP connections
+func proxyReachable(addr string) bool {
+	if addr == "" {
+		return false
+	}
+
+	conn, err := net.DialTimeout("tcp", addr, time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// describeProxySource returns a human readable description of a proxy source
+func describeProxySource(source string) string {
+	if source == "" || source == ProxySourceConfig {
+		return "from config"
+	}
+	return "using " + source
+}
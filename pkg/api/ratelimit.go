@@ -0,0 +1,44 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseRetryAfter parses a Retry-After header value, which is either a number
+// of seconds or an HTTP date, into a delay relative to now. Dates in the past
+// yield a zero delay.
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := date.Sub(now); delay > 0 {
+			return delay.Round(time.Second), true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
+
+// IsRateLimited reports whether the response is a 429 Too Many Requests
+func (r *Response) IsRateLimited() bool {
+	return r.StatusCode == http.StatusTooManyRequests
+}
+
+// RetryAfter returns the delay requested by the response's Retry-After header
+func (r *Response) RetryAfter() (time.Duration, bool) {
+	return ParseRetryAfter(r.Headers["Retry-After"], time.Now())
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"seconds", "42", 42 * time.Second, true},
+		{"zero seconds", "0", 0, true},
+		{"seconds with whitespace", " 5 ", 5 * time.Second, true},
+		{"HTTP date", "Wed, 15 Jan 2025 12:01:30 GMT", 90 * time.Second, true},
+		{"HTTP date in the past", "Wed, 15 Jan 2025 11:00:00 GMT", 0, true},
+		{"negative seconds", "-5", 0, false},
+		{"empty", "", 0, false},
+		{"garbage", "soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, ok := ParseRetryAfter(tt.value, now)
+			if delay != tt.expected || ok != tt.ok {
+				t.Errorf("ParseRetryAfter(%q) = %v, %v; expected %v, %v", tt.value, delay, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRateLimitedResponse(t *testing.T) {
+	resp := &Response{
+		StatusCode: 429,
+		Status:     "429 Too Many Requests",
+		Headers:    map[string]string{"Retry-After": "30"},
+	}
+
+	diag := NewErrorAnalyzer().AnalyzeResponse(resp, "http://example.com")
+	if diag == nil || diag.Type != ErrorTypeRateLimit {
+		t.Fatalf("Expected rate limit diagnostic, got %+v", diag)
+	}
+	if diag.RetryDelay() != 30*time.Second {
+		t.Errorf("Expected 30s retry delay, got %v", diag.RetryDelay())
+	}
+	if !diag.IsRetryable() {
+		t.Error("Expected rate limited response to be retryable")
+	}
+}
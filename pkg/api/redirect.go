@@ -0,0 +1,143 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// defaultMaxRedirects matches the limit net/http applies by default
+const defaultMaxRedirects = 10
+
+// RedirectHop is one request in a redirect chain. StatusCode is the status of
+// the response that redirected to URL, and is 0 for the original request.
+type RedirectHop struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code,omitempty"`
+}
+
+// RedirectError is returned when a request is redirected more times than the
+// client allows
+type RedirectError struct {
+	Limit int
+	Chain []RedirectHop
+}
+
+// Error implements the error interface
+func (e *RedirectError) Error() string {
+	if e.IsLoop() {
+		return fmt.Sprintf("redirect loop: stopped after %d redirects", e.Limit)
+	}
+	return fmt.Sprintf("stopped after %d redirects", e.Limit)
+}
+
+// IsLoop reports whether the chain visits the same URL more than once
+func (e *RedirectError) IsLoop() bool {
+	seen := make(map[string]bool, len(e.Chain))
+	for _, hop := range e.Chain {
+		if seen[hop.URL] {
+			return true
+		}
+		seen[hop.URL] = true
+	}
+	return false
+}
+
+// configureRedirects makes httpClient hand 3xx responses back to the caller
+// when redirects are interactive or disabled, and otherwise caps the number
+// of redirects followed
+func (c *Client) configureRedirects(httpClient *http.Client) {
+	if c.interactiveRedirects || c.disableRedirects {
+		httpClient.CheckRedirect = pauseOnRedirect
+		return
+	}
+	httpClient.CheckRedirect = c.limitRedirects
+}
+
+// limitRedirects stops once maxRedirects redirects have been followed,
+// recording the chain so it can be shown to the user
+func (c *Client) limitRedirects(req *http.Request, via []*http.Request) error {
+	if len(via) < c.maxRedirects {
+		return nil
+	}
+
+	chain := make([]RedirectHop, 0, len(via)+1)
+	for _, hop := range append(via, req) {
+		entry := RedirectHop{URL: hop.URL.String()}
+		if hop.Response != nil {
+			entry.StatusCode = hop.Response.StatusCode
+		}
+		chain = append(chain, entry)
+	}
+	return &RedirectError{Limit: c.maxRedirects, Chain: chain}
+}
+
+// pauseOnRedirect stops at the first redirect. http.ErrUseLastResponse is the
+// sentinel that makes http.Client return the 3xx response instead of an error.
+func pauseOnRedirect(req *http.Request, via []*http.Request) error {
+	return http.ErrUseLastResponse
+}
+
+// IsRedirect reports whether the response is a redirect with a Location to follow
+func (r *Response) IsRedirect() bool {
+	switch r.StatusCode {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return r.Headers["Location"] != ""
+	}
+	return false
+}
+
+// NextRedirect builds the request that follows a redirect response, applying
+// the same rules as net/http: 301/302/303 become a bodyless GET (HEAD stays
+// HEAD), 307/308 resend the method and body, and credentials are dropped when
+// the redirect leaves the original host. Returns nil if resp is not a redirect.
+func NextRedirect(req *Request, resp *Response) (*Request, error) {
+	if !resp.IsRedirect() {
+		return nil, nil
+	}
+
+	base, err := url.Parse(req.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request URL: %w", err)
+	}
+	location, err := base.Parse(resp.Headers["Location"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid redirect Location %q: %w", resp.Headers["Location"], err)
+	}
+	location.Fragment = ""
+
+	next := &Request{
+		Method:   req.Method,
+		URL:      location.String(),
+		Headers:  make(map[string]string, len(req.Headers)),
+		Body:     req.Body,
+		BodyFile: req.BodyFile,
+
+		Multipart: req.Multipart,
+	}
+
+	keepBody := resp.StatusCode == http.StatusTemporaryRedirect || resp.StatusCode == http.StatusPermanentRedirect
+	if !keepBody {
+		if req.Method != http.MethodHead {
+			next.Method = http.MethodGet
+		}
+		next.Body = ""
+		next.BodyFile = ""
+		next.Multipart = nil
+	}
+
+	sameHost := strings.EqualFold(base.Host, location.Host)
+	for key, value := range req.Headers {
+		switch {
+		case !keepBody && (strings.EqualFold(key, "Content-Type") || strings.EqualFold(key, "Content-Length")):
+			continue
+		case !sameHost && (strings.EqualFold(key, "Authorization") || strings.EqualFold(key, "Cookie")):
+			continue
+		}
+		next.Headers[key] = value
+	}
+
+	return next, nil
+}
@@ -0,0 +1,208 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newRedirectServer serves /start -> 302 /middle -> 307 /end, echoing the
+// method, body and Authorization header that reach /end
+func newRedirectServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/start", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc"})
+		http.Redirect(w, r, "/middle?step=2#frag", http.StatusFound)
+	})
+	mux.HandleFunc("/middle", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/end", http.StatusTemporaryRedirect)
+	})
+	mux.HandleFunc("/end", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Write([]byte(r.Method + " " + string(body) + " " + r.Header.Get("Authorization")))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestInteractiveRedirects(t *testing.T) {
+	server := newRedirectServer(t)
+	client, err := NewClient(&ClientConfig{TorEnabled: false, Timeout: 5 * time.Second, InteractiveRedirects: true})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	req := NewRequest("POST", server.URL+"/start")
+	req.SetBody("payload")
+	req.SetHeader("Authorization", "Bearer token")
+
+	var path []int
+	for hop := 0; hop < 5; hop++ {
+		resp, err := client.Send(req)
+		if err != nil {
+			t.Fatalf("Hop %d failed: %v", hop, err)
+		}
+		path = append(path, resp.StatusCode)
+
+		if hop == 0 && resp.Headers["Set-Cookie"] == "" {
+			t.Error("Expected the paused redirect to expose its Set-Cookie header")
+		}
+
+		next, err := NextRedirect(req, resp)
+		if err != nil {
+			t.Fatalf("NextRedirect failed: %v", err)
+		}
+		if next == nil {
+			if resp.Body != "GET  Bearer token" {
+				t.Errorf("Expected the final hop to be a bodyless GET with auth, got %q", resp.Body)
+			}
+			break
+		}
+		req = next
+	}
+
+	if len(path) != 3 || path[0] != 302 || path[1] != 307 || path[2] != 200 {
+		t.Errorf("Expected hops 302, 307, 200, got %v", path)
+	}
+}
+
+func TestAutomaticRedirects(t *testing.T) {
+	server := newRedirectServer(t)
+	client, err := NewClient(&ClientConfig{TorEnabled: false, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	resp, err := client.Send(NewRequest("GET", server.URL+"/start"))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if resp.StatusCode != 200 {
+		t.Errorf("Expected redirects to be followed by default, got %d", resp.StatusCode)
+	}
+	if resp.FinalURL != server.URL+"/end" {
+		t.Errorf("FinalURL = %q, want %q", resp.FinalURL, server.URL+"/end")
+	}
+}
+
+func TestDisabledRedirects(t *testing.T) {
+	server := newRedirectServer(t)
+	client, err := NewClient(&ClientConfig{TorEnabled: false, Timeout: 5 * time.Second, DisableRedirects: true})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	resp, err := client.Send(NewRequest("GET", server.URL+"/start"))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusFound {
+		t.Errorf("Expected the 302 to be returned, got %d", resp.StatusCode)
+	}
+	if resp.FinalURL != "" {
+		t.Errorf("FinalURL = %q for a response that was not redirected, want empty", resp.FinalURL)
+	}
+}
+
+func TestNextRedirect(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		status     int
+		location   string
+		wantURL    string
+		wantMethod string
+		wantBody   string
+		wantAuth   bool
+	}{
+		{"302 becomes GET", "POST", 302, "/next", "http://example.onion/next", "GET", "", true},
+		{"303 keeps HEAD", "HEAD", 303, "/next", "http://example.onion/next", "HEAD", "", true},
+		{"307 keeps method and body", "PUT", 307, "next", "http://example.onion/api/next", "PUT", "data", true},
+		{"308 to another host drops auth", "POST", 308, "http://other.onion/x", "http://other.onion/x", "POST", "data", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := NewRequest(tt.method, "http://example.onion/api/start")
+			req.SetBody("data")
+			req.SetHeader("Content-Type", "text/plain")
+			req.SetHeader("Authorization", "Bearer token")
+			resp := &Response{StatusCode: tt.status, Headers: map[string]string{"Location": tt.location}}
+
+			next, err := NextRedirect(req, resp)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if next.URL != tt.wantURL || next.Method != tt.wantMethod || next.Body != tt.wantBody {
+				t.Errorf("Expected %s %s %q, got %s %s %q", tt.wantMethod, tt.wantURL, tt.wantBody, next.Method, next.URL, next.Body)
+			}
+			if _, ok := next.Headers["Authorization"]; ok != tt.wantAuth {
+				t.Errorf("Expected Authorization kept %v, got %v", tt.wantAuth, ok)
+			}
+			if _, ok := next.Headers["Content-Type"]; ok != (tt.wantBody != "") {
+				t.Errorf("Expected Content-Type only with a body, got %v", next.Headers)
+			}
+		})
+	}
+
+	if next, _ := NextRedirect(NewRequest("GET", "http://example.onion"), &Response{StatusCode: 200}); next != nil {
+		t.Errorf("Expected no redirect for a 200, got %v", next)
+	}
+}
+
+func TestRedirectLimit(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/a", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/b", http.StatusFound)
+	})
+	mux.HandleFunc("/b", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/a", http.StatusMovedPermanently)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClient(&ClientConfig{TorEnabled: false, Timeout: 5 * time.Second, MaxRedirects: 3})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	_, err = client.Send(NewRequest("GET", server.URL+"/a"))
+	var redirectErr *RedirectError
+	if !errors.As(err, &redirectErr) {
+		t.Fatalf("Send() error = %v, want a RedirectError", err)
+	}
+
+	want := []RedirectHop{
+		{URL: server.URL + "/a"},
+		{URL: server.URL + "/b", StatusCode: http.StatusFound},
+		{URL: server.URL + "/a", StatusCode: http.StatusMovedPermanently},
+		{URL: server.URL + "/b", StatusCode: http.StatusFound},
+	}
+	if len(redirectErr.Chain) != len(want) {
+		t.Fatalf("Chain = %v, want %v", redirectErr.Chain, want)
+	}
+	for i := range want {
+		if redirectErr.Chain[i] != want[i] {
+			t.Errorf("Chain[%d] = %v, want %v", i, redirectErr.Chain[i], want[i])
+		}
+	}
+	if !redirectErr.IsLoop() {
+		t.Error("IsLoop() = false for an a -> b -> a chain")
+	}
+
+	diagnostic := NewErrorAnalyzer().AnalyzeError(err, server.URL+"/a")
+	if diagnostic.Type != ErrorTypeRedirect {
+		t.Errorf("Type = %q, want %q", diagnostic.Type, ErrorTypeRedirect)
+	}
+	if len(diagnostic.Redirects) != len(want) {
+		t.Errorf("Redirects has %d hops, want %d", len(diagnostic.Redirects), len(want))
+	}
+	if diagnostic.IsRetryable() {
+		t.Error("IsRetryable() = true for a redirect loop")
+	}
+}
@@ -0,0 +1,299 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+)
+
+// minMaskedLength is the shortest secret also masked in free text such as
+// error messages; shorter ones would mangle unrelated words
+const minMaskedLength = 4
+
+// ReportInfo is the context gathered for a diagnostic report
+type ReportInfo struct {
+	Error   *DiagnosticError
+	Request *Request    // the resolved request that failed, if any
+	Auth    *AuthConfig // the auth applied to it, if any
+
+	TorEnabled     bool
+	TorProxy       string
+	TorProxySource string
+	Probes         []ProbeResult // live Tor checks; Error.Probes are used if nil
+
+	// Config holds the settings relevant to the failure, by config key
+	Config map[string]string
+
+	// Secrets are further values to mask wherever they appear
+	Secrets []string
+
+	Generated time.Time
+}
+
+// BuildDiagnosticReport renders a plain-text report to attach to bug
+// reports: version and platform, Tor reachability, the request, the full
+// error with its attempts and suggestions, and the relevant settings.
+// Sensitive headers, query parameters and credentials are masked as
+// MaskSensitiveData masks them, and those values are also masked wherever
+// else they appear, such as inside error messages. Request bodies are left
+// out, only their size is reported.
+func BuildDiagnosticReport(info ReportInfo) string {
+	secrets := reportSecrets(info)
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "OnionCLI diagnostic report\n")
+	if !info.Generated.IsZero() {
+		fmt.Fprintf(&b, "Generated: %s\n", info.Generated.UTC().Format(time.RFC3339))
+	}
+	fmt.Fprintf(&b, "Version:   %s\n", buildVersion())
+	fmt.Fprintf(&b, "Platform:  %s/%s, %s\n", runtime.GOOS, runtime.GOARCH, runtime.Version())
+
+	fmt.Fprintf(&b, "\n== Tor ==\n")
+	fmt.Fprintf(&b, "Enabled: %v\n", info.TorEnabled)
+	if info.TorProxy != "" {
+		fmt.Fprintf(&b, "Proxy:   %s (%s)\n", info.TorProxy, info.TorProxySource)
+	}
+	probes := info.Probes
+	if probes == nil && info.Error != nil {
+		probes = info.Error.Probes
+	}
+	if len(probes) == 0 {
+		fmt.Fprintf(&b, "Checks:  not run\n")
+	}
+	for _, probe := range probes {
+		outcome := "FAIL"
+		switch {
+		case probe.Skipped:
+			outcome = "SKIP"
+		case probe.Passed:
+			outcome = "PASS"
+		}
+		fmt.Fprintf(&b, "  [%s] %s: %s\n", outcome, probe.Name, probe.Detail)
+	}
+
+	if req := info.Request; req != nil {
+		fmt.Fprintf(&b, "\n== Request ==\n")
+		fmt.Fprintf(&b, "%s %s\n", req.Method, maskURL(req.URL))
+		for _, key := range sortedKeys(req.Headers) {
+			fmt.Fprintf(&b, "  %s: %s\n", key, maskHeader(key, req.Headers[key]))
+		}
+		switch {
+		case req.Multipart != nil:
+			fmt.Fprintf(&b, "Body: multipart form with %d fields and %d files (not included)\n",
+				len(req.Multipart.Fields), len(req.Multipart.Files))
+		case req.BodyFile != "":
+			fmt.Fprintf(&b, "Body: streamed from a file (not included)\n")
+		case req.Body != "":
+			fmt.Fprintf(&b, "Body: %d bytes (not included)\n", len(req.Body))
+		default:
+			fmt.Fprintf(&b, "Body: empty\n")
+		}
+		if req.Timeout > 0 {
+			fmt.Fprintf(&b, "Timeout: %v\n", req.Timeout)
+		}
+	}
+	if info.Auth != nil && info.Auth.Type != AuthNone {
+		masked := NewAuthManager().MaskSensitiveData(info.Auth)
+		fmt.Fprintf(&b, "Auth: %s", masked.Type)
+		for _, field := range []struct{ name, value string }{
+			{"key name", masked.KeyName}, {"location", masked.Location}, {"api key", masked.APIKey},
+			{"token", masked.Token}, {"username", masked.Username}, {"password", masked.Password},
+			{"token url", maskURL(masked.TokenURL)}, {"client id", masked.ClientID},
+			{"client secret", masked.ClientSecret}, {"scope", masked.Scope},
+		} {
+			if field.value != "" {
+				fmt.Fprintf(&b, ", %s %s", field.name, field.value)
+			}
+		}
+		fmt.Fprintf(&b, "\n")
+	}
+
+	if err := info.Error; err != nil {
+		fmt.Fprintf(&b, "\n== Error ==\n")
+		fmt.Fprintf(&b, "Type:    %s\n", err.Type)
+		fmt.Fprintf(&b, "Message: %s\n", err.Message)
+		if err.Cause != nil {
+			fmt.Fprintf(&b, "Cause:   %v\n", err.Cause)
+		}
+		if err.URL != "" {
+			fmt.Fprintf(&b, "URL:     %s\n", maskURL(err.URL))
+		}
+		if err.StatusCode != 0 {
+			fmt.Fprintf(&b, "Status:  %d\n", err.StatusCode)
+		}
+		if err.RetryAfter > 0 {
+			fmt.Fprintf(&b, "Retry after: %v\n", err.RetryAfter)
+		}
+		if len(err.Attempts) > 0 {
+			fmt.Fprintf(&b, "Attempts:\n")
+			for _, attempt := range err.Attempts {
+				fmt.Fprintf(&b, "  %s\n", attempt)
+			}
+		}
+		if len(err.Redirects) > 0 {
+			fmt.Fprintf(&b, "Redirects:\n")
+			for _, hop := range err.Redirects {
+				fmt.Fprintf(&b, "  %d %s\n", hop.StatusCode, maskURL(hop.URL))
+			}
+		}
+		if len(err.Suggestions) > 0 {
+			fmt.Fprintf(&b, "Suggestions:\n")
+			for _, suggestion := range err.Suggestions {
+				fmt.Fprintf(&b, "  - %s\n", suggestion)
+			}
+		}
+	}
+
+	if len(info.Config) > 0 {
+		fmt.Fprintf(&b, "\n== Config ==\n")
+		for _, key := range sortedKeys(info.Config) {
+			value := info.Config[key]
+			if IsSensitiveName(key) && value != "" {
+				value = maskSecret(value)
+			}
+			fmt.Fprintf(&b, "%s: %s\n", key, value)
+		}
+	}
+
+	return maskAll(b.String(), secrets)
+}
+
+// buildVersion describes the running binary from its build info, e.g.
+// "v1.2.0" or "(devel), commit 1a2b3c4 (modified)"
+func buildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+
+	version := info.Main.Version
+	if version == "" {
+		version = "(devel)"
+	}
+	var revision string
+	modified := false
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if revision != "" {
+		if len(revision) > 7 {
+			revision = revision[:7]
+		}
+		version += ", commit " + revision
+		if modified {
+			version += " (modified)"
+		}
+	}
+	return version
+}
+
+// reportSecrets collects every credential value that must not appear in a
+// report: sensitive headers and query parameters, URL passwords and auth
+func reportSecrets(info ReportInfo) []string {
+	secrets := append([]string(nil), info.Secrets...)
+
+	if req := info.Request; req != nil {
+		for key, value := range req.Headers {
+			if IsSensitiveName(key) {
+				secrets = append(secrets, value)
+				// "Bearer <token>": the token alone may show up elsewhere
+				if _, credential, ok := strings.Cut(value, " "); ok {
+					secrets = append(secrets, credential)
+				}
+			}
+		}
+		secrets = append(secrets, urlSecrets(req.URL)...)
+	}
+	if info.Error != nil {
+		secrets = append(secrets, urlSecrets(info.Error.URL)...)
+	}
+	if auth := info.Auth; auth != nil {
+		secrets = append(secrets, auth.APIKey, auth.Token, auth.Password)
+		for key, value := range auth.Custom {
+			if IsSensitiveName(key) {
+				secrets = append(secrets, value)
+			}
+		}
+	}
+	return secrets
+}
+
+// urlSecrets returns a URL's password and sensitive query parameter values
+func urlSecrets(rawURL string) []string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil
+	}
+
+	var secrets []string
+	if password, ok := u.User.Password(); ok {
+		secrets = append(secrets, password)
+	}
+	for key, values := range u.Query() {
+		if IsSensitiveName(key) {
+			secrets = append(secrets, values...)
+		}
+	}
+	return secrets
+}
+
+// maskURL masks a URL's password and sensitive query parameters
+func maskURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	if u.RawQuery != "" {
+		// Rebuilt by hand so the parameter order and encoding are kept
+		pairs := strings.Split(u.RawQuery, "&")
+		for i, pair := range pairs {
+			key, value, ok := strings.Cut(pair, "=")
+			name, err := url.QueryUnescape(key)
+			if ok && err == nil && IsSensitiveName(name) {
+				if unescaped, err := url.QueryUnescape(value); err == nil {
+					value = unescaped
+				}
+				pairs[i] = key + "=" + maskSecret(value)
+			}
+		}
+		u.RawQuery = strings.Join(pairs, "&")
+	}
+	return u.Redacted()
+}
+
+// maskHeader masks the value of a sensitive header
+func maskHeader(name, value string) string {
+	if !IsSensitiveName(name) {
+		return value
+	}
+	// Keep the scheme of "Bearer <token>" and "Basic <credentials>"
+	if scheme, credential, ok := strings.Cut(value, " "); ok && !strings.Contains(scheme, "=") {
+		return scheme + " " + maskSecret(credential)
+	}
+	return maskSecret(value)
+}
+
+// maskAll masks every occurrence of the secrets in text, longest first so a
+// secret containing another is masked whole
+func maskAll(text string, secrets []string) string {
+	sort.Slice(secrets, func(i, j int) bool { return len(secrets[i]) > len(secrets[j]) })
+	for _, secret := range secrets {
+		if len(secret) < minMaskedLength {
+			continue
+		}
+		text = strings.ReplaceAll(text, secret, maskSecret(secret))
+		if escaped := url.QueryEscape(secret); escaped != secret {
+			text = strings.ReplaceAll(text, escaped, maskSecret(secret))
+		}
+	}
+	return text
+}
@@ -1,20 +1,55 @@
 package api
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
+// Methods lists the HTTP methods OnionCLI can send, in display order
+var Methods = []string{"GET", "POST", "PUT", "DELETE", "PATCH", "HEAD", "OPTIONS"}
+
+// IsValidMethod reports whether method (case-insensitive) is one of Methods
+func IsValidMethod(method string) bool {
+	method = strings.ToUpper(method)
+	for _, m := range Methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
 // Request represents an HTTP request to be sent
 type Request struct {
 	Method  string            `json:"method"`
 	URL     string            `json:"url"`
 	Headers map[string]string `json:"headers"`
 	Body    string            `json:"body"`
+
+	// BodyFile, when set, is streamed from disk as the body instead of Body
+	BodyFile string `json:"body_file,omitempty"`
+
+	// Multipart, when set, is sent as a multipart/form-data body instead of
+	// Body; see SetMultipartBody
+	Multipart *MultipartBody `json:"multipart,omitempty"`
+
+	// UploadProgress, when set, is called as BodyFile or Multipart is sent
+	UploadProgress UploadProgressFunc `json:"-"`
+
+	// RetryProgress, when set, is called before each retry of the request
+	RetryProgress RetryProgressFunc `json:"-"`
+
+	// Timeout, when non-zero, replaces the client's timeout for each attempt
+	Timeout time.Duration `json:"timeout,omitempty"`
 }
 
 // Response represents an HTTP response received
@@ -25,6 +60,34 @@ type Response struct {
 	Body       string            `json:"body"`
 	Duration   time.Duration     `json:"duration"`
 	Timestamp  time.Time         `json:"timestamp"`
+	TLSInfo    *TLSInfo          `json:"tls_info,omitempty"`   // nil for plain HTTP
+	Transforms []string          `json:"transforms,omitempty"` // post-processors applied to Body
+
+	// RawBody holds the same bytes as Body, kept as a slice so binary
+	// bodies are handled without conversion; see BodyBytes and IsBinary.
+	// Body alone is serialized.
+	RawBody []byte `json:"-"`
+
+	// FinalURL is where a followed redirect chain ended; empty if the
+	// request was not redirected
+	FinalURL string `json:"final_url,omitempty"`
+
+	// SetCookies holds every Set-Cookie value; Headers keeps only the first
+	SetCookies []string `json:"set_cookies,omitempty"`
+
+	// LengthMismatch is set when the body received doesn't match the declared
+	// Content-Length or a chunked body ended early; LengthWarning says how
+	LengthMismatch bool   `json:"length_mismatch,omitempty"`
+	LengthWarning  string `json:"length_warning,omitempty"`
+
+	// RequestDump is the request as sent, after auth and substitution:
+	// request line, every header field in wire order and the body.
+	// ResponseDump is the status line and headers as received. Both may
+	// hold credentials, so they are never serialized; see MaskDumpHeaders.
+	RequestDump  string `json:"-"`
+	ResponseDump string `json:"-"`
+
+	bodyHash string // cached SHA-256 of Body, see BodyHash
 }
 
 // NewRequest creates a new API request
@@ -69,11 +132,23 @@ func (r *Request) Validate() error {
 		return fmt.Errorf("HTTP method is required")
 	}
 
+	if r.BodyFile != "" && r.Body != "" {
+		return fmt.Errorf("request cannot have both a body and a body file")
+	}
+
+	if r.Multipart != nil {
+		if r.Body != "" || r.BodyFile != "" {
+			return fmt.Errorf("request cannot have both a body and a multipart form")
+		}
+		if err := r.Multipart.validateFiles(); err != nil {
+			return err
+		}
+	}
+
 	// Validate JSON body if Content-Type is application/json
 	if contentType, exists := r.Headers["Content-Type"]; exists {
 		if strings.Contains(contentType, "application/json") && r.Body != "" {
-			var js json.RawMessage
-			if err := json.Unmarshal([]byte(r.Body), &js); err != nil {
+			if err := ValidateJSON(r.Body); err != nil {
 				return fmt.Errorf("invalid JSON body: %w", err)
 			}
 		}
@@ -82,54 +157,165 @@ func (r *Request) Validate() error {
 	return nil
 }
 
+// ValidateJSON returns an error if body is not valid JSON, locating a
+// syntax error by line and column
+func ValidateJSON(body string) error {
+	var js json.RawMessage
+	err := json.Unmarshal([]byte(body), &js)
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		line, column := textPosition(body, syntaxErr.Offset)
+		return fmt.Errorf("line %d, column %d: %w", line, column, err)
+	}
+	return err
+}
+
+// textPosition converts a byte offset in text to a 1-based line and column,
+// counting the column in runes
+func textPosition(text string, offset int64) (int, int) {
+	offset = min(offset, int64(len(text)))
+	before := text[:offset]
+	line := strings.Count(before, "\n") + 1
+	column := utf8.RuneCountInString(before[strings.LastIndex(before, "\n")+1:])
+	return line, column
+}
+
 // Send sends the HTTP request using the provided client
 func (c *Client) Send(req *Request) (*Response, error) {
+	return c.SendContext(context.Background(), req)
+}
+
+// SendContext sends the HTTP request, aborting it if ctx is cancelled. Failures
+// are retried as configured by ClientConfig.MaxRetries.
+func (c *Client) SendContext(ctx context.Context, req *Request) (*Response, error) {
+	return c.SendWithRetryContext(ctx, req, c.retryPolicy)
+}
+
+// SendWithRetry sends the HTTP request, retrying retryable failures with
+// exponential backoff as policy allows instead of the client's own setting.
+// A 429 response is retryable too, after at least its Retry-After delay.
+func (c *Client) SendWithRetry(req *Request, policy RetryPolicy) (*Response, error) {
+	return c.SendWithRetryContext(context.Background(), req, policy)
+}
+
+// SendWithRetryContext is SendWithRetry, aborting the request and any wait
+// before a retry if ctx is cancelled
+func (c *Client) SendWithRetryContext(ctx context.Context, req *Request, policy RetryPolicy) (*Response, error) {
 	if err := req.Validate(); err != nil {
 		return nil, fmt.Errorf("request validation failed: %w", err)
 	}
 
-	// Check if this is an .onion URL and Tor is disabled
-	if IsOnionURL(req.URL) && !c.torEnabled {
-		return nil, fmt.Errorf(".onion URLs require Tor to be enabled")
+	if IsWebSocketURL(req.URL) {
+		return nil, fmt.Errorf("ws:// and wss:// URLs open a WebSocket and cannot be sent as HTTP requests")
+	}
+	if err := c.checkRoute(req.URL); err != nil {
+		return nil, err
 	}
 
-	// Validate .onion URLs
-	if IsOnionURL(req.URL) {
-		if err := ValidateOnionURL(req.URL); err != nil {
-			return nil, fmt.Errorf("invalid .onion URL: %w", err)
+	return c.sendWithRetries(ctx, req, policy)
+}
+
+// checkRoute validates .onion URLs before asking Tor for one that can't
+// work, and rejects clearnet hosts a Tor exit can't be given by name. The
+// warnings ValidateClearnetURL returns are left for callers to show.
+func (c *Client) checkRoute(rawURL string) error {
+	if IsOnionURL(rawURL) {
+		if err := ValidateOnionURL(rawURL); err != nil {
+			return fmt.Errorf("invalid .onion URL: %w", err)
+		}
+		if !c.torEnabled {
+			return fmt.Errorf(".onion URLs require Tor to be enabled")
+		}
+	} else if c.torEnabled {
+		if _, err := ValidateClearnetURL(rawURL); err != nil {
+			return fmt.Errorf("invalid URL: %w", err)
 		}
 	}
+	return nil
+}
 
+// sendOnce makes a single attempt at sending the request with httpClient
+func (c *Client) sendOnce(ctx context.Context, httpClient *http.Client, req *Request) (*Response, error) {
 	startTime := time.Now()
 
+	// A per-request timeout is a context deadline, which aborts the dial,
+	// the wait for headers and the body read alike; the client's own
+	// timeout is lifted so the request's can be longer as well as shorter
+	if req.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, req.Timeout)
+		defer cancel()
+		overridden := *httpClient
+		overridden.Timeout = 0
+		httpClient = &overridden
+	}
+
 	// Create HTTP request
 	var bodyReader io.Reader
-	if req.Body != "" {
+	var contentLength int64
+	streamed := req.BodyFile != "" || req.Multipart != nil
+	if streamed {
+		// Stream large bodies from disk rather than holding them in memory
+		open := openBodyFile
+		if req.Multipart != nil {
+			open = openMultipartBody
+		}
+		reader, size, files, err := open(req)
+		if err != nil {
+			return nil, err
+		}
+		defer files.Close()
+		bodyReader, contentLength = reader, size
+	} else if req.Body != "" {
 		bodyReader = strings.NewReader(req.Body)
 	}
 
-	httpReq, err := http.NewRequest(req.Method, req.URL, bodyReader)
+	ctx, recorder := withHeaderRecorder(ctx)
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, req.URL, bodyReader)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
+	if streamed {
+		httpReq.ContentLength = contentLength
+		if contentLength == 0 {
+			httpReq.Body = http.NoBody
+		}
+	}
 
 	// Set headers
 	for key, value := range req.Headers {
 		httpReq.Header.Set(key, value)
 	}
+	if c.userAgent != "" && httpReq.Header.Get("User-Agent") == "" {
+		httpReq.Header.Set("User-Agent", c.userAgent)
+	}
 
 	// Send the request
-	httpResp, err := c.httpClient.Do(httpReq)
+	httpResp, err := httpClient.Do(httpReq)
 	if err != nil {
+		if req.Timeout > 0 && errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("failed to send request: no response within the request timeout of %v: %w", req.Timeout, err)
+		}
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer httpResp.Body.Close()
 
-	// Read response body
+	// Read response body, keeping it even if it ends early
 	bodyBytes, err := io.ReadAll(httpResp.Body)
+	declared := httpResp.ContentLength
+	if !mayHaveBody(httpReq.Method, httpResp.StatusCode) {
+		declared = -1
+	}
+	lengthWarning, err := checkBodyLength(declared, len(bodyBytes), err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
+	if !httpResp.Uncompressed {
+		// Undecodable bodies are shown as received
+		if decoded, err := decodeContentEncoding(bodyBytes, httpResp.Header.Get("Content-Encoding")); err == nil {
+			bodyBytes = decoded
+		}
+	}
 
 	// Convert response headers to map
 	headers := make(map[string]string)
@@ -146,9 +332,22 @@ func (c *Client) Send(req *Request) (*Response, error) {
 		Status:     httpResp.Status,
 		Headers:    headers,
 		Body:       string(bodyBytes),
+		RawBody:    bodyBytes,
 		Duration:   duration,
 		Timestamp:  time.Now(),
+		TLSInfo:    newTLSInfo(httpResp.TLS),
+		SetCookies: httpResp.Header.Values("Set-Cookie"),
+
+		LengthMismatch: lengthWarning != "",
+		LengthWarning:  lengthWarning,
+
+		RequestDump:  dumpRequest(req, httpResp, recorder),
+		ResponseDump: dumpResponse(httpResp),
 	}
+	if final := httpResp.Request.URL.String(); final != httpReq.URL.String() {
+		response.FinalURL = final
+	}
+	response.BodyHash()
 
 	return response, nil
 }
@@ -159,9 +358,8 @@ func (r *Response) PrettyPrintJSON() (string, error) {
 		return "", nil
 	}
 
-	// Check if the response is JSON
-	contentType, exists := r.Headers["Content-Type"]
-	if !exists || !strings.Contains(contentType, "application/json") {
+	// Check if the response is JSON, sniffing the body if the header is missing
+	if !r.IsJSON() {
 		return r.Body, nil // Return as-is if not JSON
 	}
 
@@ -178,6 +376,55 @@ func (r *Response) PrettyPrintJSON() (string, error) {
 	return string(prettyJSON), nil
 }
 
+// PrettyPrintXML formats an XML response body for better readability,
+// indenting nested elements
+func (r *Response) PrettyPrintXML() (string, error) {
+	if r.Body == "" {
+		return "", nil
+	}
+
+	if !r.IsXML() {
+		return r.Body, nil // Return as-is if not XML
+	}
+
+	pretty, err := indentXML(r.Body)
+	if err != nil {
+		return r.Body, nil // Return as-is if not well-formed XML
+	}
+	return pretty, nil
+}
+
+// Fingerprint identifies a request by method, URL and a SHA-256 of its body,
+// so the same request can be recognised wherever it has been saved
+func (r *Request) Fingerprint() string {
+	sum := sha256.Sum256([]byte(r.Body))
+	return r.Method + " " + r.URL + " " + hex.EncodeToString(sum[:])
+}
+
+// BodyHash returns the hex-encoded SHA-256 of the response body.
+// The hash is computed once and cached, so it can be used cheaply to
+// tell whether two responses are byte-identical.
+func (r *Response) BodyHash() string {
+	if r.bodyHash == "" {
+		sum := sha256.Sum256([]byte(r.Body))
+		r.bodyHash = hex.EncodeToString(sum[:])
+	}
+	return r.bodyHash
+}
+
+// ShortBodyHash returns a short prefix of BodyHash for display
+func (r *Response) ShortBodyHash() string {
+	return r.BodyHash()[:12]
+}
+
+// SameBody reports whether two responses have byte-identical bodies
+func (r *Response) SameBody(other *Response) bool {
+	if other == nil {
+		return false
+	}
+	return r.BodyHash() == other.BodyHash()
+}
+
 // IsSuccess returns true if the response status code indicates success (2xx)
 func (r *Response) IsSuccess() bool {
 	return r.StatusCode >= 200 && r.StatusCode < 300
@@ -0,0 +1,250 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/proxy"
+)
+
+// defaultRetryBackoff is the delay before the first retry; later retries wait longer
+const defaultRetryBackoff = time.Second
+
+// defaultRetryAttempts is how many attempts DefaultRetryPolicy makes
+const defaultRetryAttempts = 3
+
+// maxRetryAfter is the longest Retry-After a retry waits out; a rate-limited
+// response asking for more is returned as it is
+const maxRetryAfter = time.Minute
+
+// idempotentMethods have the same effect sent twice as once, so they are
+// retried whatever the failure; other methods only when they failed before
+// any of the request was written
+var idempotentMethods = map[string]bool{
+	"GET":     true,
+	"HEAD":    true,
+	"OPTIONS": true,
+	"TRACE":   true,
+	"PUT":     true,
+	"DELETE":  true,
+}
+
+// RetryPolicy controls how often a request failing with a retryable error is
+// sent again. Errors that can't be fixed by repeating the request, such as an
+// invalid URL or a refused TLS handshake, fail on the first attempt.
+type RetryPolicy struct {
+	MaxAttempts int           // attempts in total, the first included; 1 or less never retries
+	BaseDelay   time.Duration // wait before the first retry, doubled before each one after it
+}
+
+// DefaultRetryPolicy returns a policy of 3 attempts starting 1s apart
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: defaultRetryAttempts, BaseDelay: defaultRetryBackoff}
+}
+
+// Delay returns the wait after the given number of failed attempts:
+// BaseDelay, then twice that, four times that and so on
+func (p RetryPolicy) Delay(failed int) time.Duration {
+	if failed < 1 {
+		return 0
+	}
+	return p.BaseDelay << (failed - 1)
+}
+
+// AttemptInfo describes one attempt at sending a request
+type AttemptInfo struct {
+	Number     int           `json:"number"`
+	Duration   time.Duration `json:"duration"`
+	ErrorType  ErrorType     `json:"error_type"`
+	Message    string        `json:"message"`
+	NewCircuit bool          `json:"new_circuit,omitempty"` // sent over a fresh Tor circuit
+}
+
+// String formats the attempt, e.g. "attempt 2: new circuit, timeout"
+func (a AttemptInfo) String() string {
+	var parts []string
+	if a.NewCircuit {
+		parts = append(parts, "new circuit")
+	}
+	parts = append(parts, strings.ReplaceAll(string(a.ErrorType), "_", " "))
+	return fmt.Sprintf("attempt %d: %s", a.Number, strings.Join(parts, ", "))
+}
+
+// RetryNotice describes a retry that is about to be sent
+type RetryNotice struct {
+	Attempt    int       // number of the attempt about to be sent, from 2
+	Total      int       // most attempts that will be made
+	Reason     ErrorType // why the previous attempt failed
+	OnionDown  bool      // the previous attempt could not reach the onion service
+	NewCircuit bool      // the retry goes over a fresh Tor circuit
+}
+
+// String formats the notice, e.g. "onion service unreachable, trying a new circuit (2/3)"
+func (n RetryNotice) String() string {
+	reason := strings.ReplaceAll(string(n.Reason), "_", " ")
+	if n.OnionDown {
+		reason = "onion service unreachable"
+	}
+	action := "retrying"
+	if n.NewCircuit {
+		action = "trying a new circuit"
+	}
+	return fmt.Sprintf("%s, %s (%d/%d)", reason, action, n.Attempt, n.Total)
+}
+
+// RetryProgressFunc is called before each retry of a request
+type RetryProgressFunc func(notice RetryNotice)
+
+// RetryError is returned when every attempt at sending a request failed
+type RetryError struct {
+	Attempts []AttemptInfo
+	Elapsed  time.Duration
+	Err      error // error from the last attempt
+}
+
+// Error implements the error interface
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("failed after %d attempts over %v: %v",
+		len(e.Attempts), e.Elapsed.Round(time.Second), e.Err)
+}
+
+// Unwrap returns the last attempt's error
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// sendWithRetries sends the request, retrying retryable transport failures
+// and rate-limited responses as the policy allows. Retries over Tor use a
+// fresh circuit, which is what usually gets an onion service that Tor
+// reported unreachable answering again.
+func (c *Client) sendWithRetries(ctx context.Context, req *Request, policy RetryPolicy) (*Response, error) {
+	if policy.MaxAttempts <= 1 {
+		return c.sendOnce(ctx, c.httpClient, req)
+	}
+
+	analyzer := NewErrorAnalyzer()
+	start := time.Now()
+	var attempts []AttemptInfo
+	onionDown := false
+
+	for number := 1; ; number++ {
+		httpClient, newCircuit := c.httpClient, false
+		if number > 1 {
+			httpClient, newCircuit = c.retryClient()
+			if req.RetryProgress != nil {
+				req.RetryProgress(RetryNotice{
+					Attempt:    number,
+					Total:      policy.MaxAttempts,
+					Reason:     attempts[len(attempts)-1].ErrorType,
+					OnionDown:  onionDown,
+					NewCircuit: newCircuit,
+				})
+			}
+		}
+
+		attemptStart := time.Now()
+		resp, err := c.sendOnce(ctx, httpClient, req)
+		if httpClient != c.httpClient {
+			// Nothing reuses a retry's transport once its body has been read
+			httpClient.CloseIdleConnections()
+		}
+		if err == nil {
+			// A 429 was refused before being applied, so whatever the method
+			// it is sent again once the server's backoff has passed
+			diagnostic := analyzer.AnalyzeResponse(resp, req.URL)
+			if diagnostic == nil || !diagnostic.IsRetryable() || number >= policy.MaxAttempts ||
+				diagnostic.RetryDelay() > maxRetryAfter {
+				return resp, nil
+			}
+			attempts = append(attempts, AttemptInfo{
+				Number:     number,
+				Duration:   time.Since(attemptStart),
+				ErrorType:  diagnostic.Type,
+				Message:    diagnostic.Message,
+				NewCircuit: newCircuit,
+			})
+			onionDown = false
+
+			select {
+			case <-time.After(max(policy.Delay(number), diagnostic.RetryDelay())):
+			case <-ctx.Done():
+				return resp, nil
+			}
+			continue
+		}
+
+		diagnostic := analyzer.AnalyzeError(err, req.URL)
+		attempts = append(attempts, AttemptInfo{
+			Number:     number,
+			Duration:   time.Since(attemptStart),
+			ErrorType:  diagnostic.Type,
+			Message:    err.Error(),
+			NewCircuit: newCircuit,
+		})
+
+		// Only failures to reach the server are worth repeating. Tor reports an
+		// onion service it can't reach as a general SOCKS failure, which is
+		// often temporary, so that is retried on a new circuit too.
+		onionDown = IsOnionURL(req.URL) && isOnionUnreachable(err)
+		var urlErr *url.Error
+		retryable := errors.As(err, &urlErr) && (diagnostic.IsRetryable() || onionDown)
+		// A POST that timed out may have been applied by the server already
+		if !idempotentMethods[strings.ToUpper(req.Method)] && !onionDown && !failedBeforeWrite(err) {
+			retryable = false
+		}
+		if !retryable || ctx.Err() != nil || number >= policy.MaxAttempts {
+			if len(attempts) == 1 {
+				return nil, err
+			}
+			return nil, &RetryError{Attempts: attempts, Elapsed: time.Since(start), Err: err}
+		}
+
+		select {
+		case <-time.After(policy.Delay(number)):
+		case <-ctx.Done():
+			return nil, &RetryError{Attempts: attempts, Elapsed: time.Since(start), Err: ctx.Err()}
+		}
+	}
+}
+
+// failedBeforeWrite reports whether err happened while connecting, through
+// the SOCKS proxy or directly, so none of the request reached the server
+func failedBeforeWrite(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && (opErr.Op == "dial" || strings.HasPrefix(opErr.Op, "socks"))
+}
+
+// retryClient returns the client for a retry: over Tor, one whose unique
+// SOCKS credentials make Tor build a new circuit
+func (c *Client) retryClient() (*http.Client, bool) {
+	if !c.torEnabled || c.torProxySource != ProxySourceConfig {
+		return c.httpClient, false // Other SOCKS proxies may not accept credentials
+	}
+
+	token := make([]byte, 8)
+	if _, err := rand.Read(token); err != nil {
+		return c.httpClient, false
+	}
+
+	auth := &proxy.Auth{User: "onioncli-" + hex.EncodeToString(token), Password: "x"}
+	httpClient, err := createTorClient(c.torProxy, c.timeout, c.pool, auth)
+	if err != nil {
+		return c.httpClient, false
+	}
+	c.configureRedirects(httpClient)
+	c.configureCookies(httpClient)
+	c.configureTLS(httpClient)
+	return httpClient, true
+}
@@ -0,0 +1,405 @@
+package api
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// slowServer answers after a delay for the first slowCalls requests
+func slowServer(t *testing.T, slowCalls int32) (*httptest.Server, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= slowCalls {
+			time.Sleep(300 * time.Millisecond)
+		}
+		w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func newRetryClient(t *testing.T, maxRetries int) *Client {
+	client, err := NewClient(&ClientConfig{TorEnabled: false, Timeout: 100 * time.Millisecond, MaxRetries: maxRetries})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client.retryPolicy.BaseDelay = 0
+	return client
+}
+
+func TestSendRetriesTimeouts(t *testing.T) {
+	server, calls := slowServer(t, 2)
+	client := newRetryClient(t, 2)
+
+	resp, err := client.Send(NewRequest("GET", server.URL))
+	if err != nil {
+		t.Fatalf("Expected third attempt to succeed, got %v", err)
+	}
+	if resp.Body != "ok" {
+		t.Errorf("Unexpected body %q", resp.Body)
+	}
+	if got := atomic.LoadInt32(calls); got != 3 {
+		t.Errorf("Expected 3 attempts, got %d", got)
+	}
+}
+
+func TestSendDoesNotRetryNonIdempotentTimeouts(t *testing.T) {
+	server, calls := slowServer(t, 2)
+	client := newRetryClient(t, 2)
+
+	if _, err := client.Send(NewRequest("POST", server.URL)); err == nil {
+		t.Fatal("Expected the timed out POST to fail")
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("Expected a single attempt at a POST that may have been applied, got %d", got)
+	}
+}
+
+func TestFailedBeforeWrite(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"dial", &url.Error{Op: "Post", Err: &net.OpError{Op: "dial", Err: errors.New("i/o timeout")}}, true},
+		{"socks connect", &url.Error{Op: "Post", Err: &net.OpError{Op: "socks connect", Err: errors.New("host unreachable")}}, true},
+		{"dns", &url.Error{Op: "Post", Err: &net.DNSError{Err: "no such host", Name: "example.invalid"}}, true},
+		{"read", &url.Error{Op: "Post", Err: &net.OpError{Op: "read", Err: errors.New("connection reset by peer")}}, false},
+		{"timeout awaiting headers", &url.Error{Op: "Post", Err: errors.New("context deadline exceeded")}, false},
+	}
+
+	for _, tt := range tests {
+		if got := failedBeforeWrite(tt.err); got != tt.want {
+			t.Errorf("failedBeforeWrite(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSendRetriesExhausted(t *testing.T) {
+	server, _ := slowServer(t, 100)
+	client := newRetryClient(t, 2)
+
+	_, err := client.Send(NewRequest("GET", server.URL))
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("Expected RetryError, got %v", err)
+	}
+	if len(retryErr.Attempts) != 3 {
+		t.Fatalf("Expected 3 attempts, got %d", len(retryErr.Attempts))
+	}
+	if !strings.HasPrefix(err.Error(), "failed after 3 attempts over") {
+		t.Errorf("Unexpected error message %q", err.Error())
+	}
+
+	diagnostic := NewErrorAnalyzer().AnalyzeError(err, server.URL)
+	if len(diagnostic.Attempts) != 3 {
+		t.Fatalf("Expected attempts on the diagnostic error, got %d", len(diagnostic.Attempts))
+	}
+	for i, attempt := range diagnostic.Attempts {
+		if attempt.Number != i+1 {
+			t.Errorf("Attempt %d numbered %d", i+1, attempt.Number)
+		}
+		if attempt.ErrorType != ErrorTypeTimeout {
+			t.Errorf("Attempt %d type = %s, want %s", i+1, attempt.ErrorType, ErrorTypeTimeout)
+		}
+	}
+	if diagnostic.Message != err.Error() {
+		t.Errorf("Expected diagnostic message to summarise the attempts, got %q", diagnostic.Message)
+	}
+}
+
+func TestSendWithoutRetries(t *testing.T) {
+	server, calls := slowServer(t, 100)
+	client := newRetryClient(t, 0)
+
+	_, err := client.Send(NewRequest("GET", server.URL))
+	if err == nil {
+		t.Fatal("Expected timeout error")
+	}
+	var retryErr *RetryError
+	if errors.As(err, &retryErr) {
+		t.Error("Expected a plain error when retries are disabled")
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("Expected 1 attempt, got %d", got)
+	}
+}
+
+func TestSendWithRetryPolicy(t *testing.T) {
+	server, calls := slowServer(t, 2)
+	client := newRetryClient(t, 0)
+
+	resp, err := client.SendWithRetry(NewRequest("GET", server.URL), RetryPolicy{MaxAttempts: 3})
+	if err != nil {
+		t.Fatalf("Expected third attempt to succeed, got %v", err)
+	}
+	if resp.Body != "ok" {
+		t.Errorf("Unexpected body %q", resp.Body)
+	}
+	if got := atomic.LoadInt32(calls); got != 3 {
+		t.Errorf("Expected 3 attempts, got %d", got)
+	}
+}
+
+func TestSendWithRetryNonRetryable(t *testing.T) {
+	server, calls := slowServer(t, 0)
+	client := newRetryClient(t, 0)
+
+	// A TLS handshake with a plain HTTP server fails the same way every time
+	url := strings.Replace(server.URL, "http://", "https://", 1)
+	_, err := client.SendWithRetry(NewRequest("GET", url), RetryPolicy{MaxAttempts: 3})
+	if err == nil {
+		t.Fatal("Expected TLS error")
+	}
+	var retryErr *RetryError
+	if errors.As(err, &retryErr) {
+		t.Errorf("Expected the first error to be returned, got %v", err)
+	}
+	if got := atomic.LoadInt32(calls); got != 0 {
+		t.Errorf("Expected no request to reach the handler, got %d", got)
+	}
+}
+
+func TestSendWithRetryRateLimited(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+	client := newRetryClient(t, 0)
+
+	var notices []RetryNotice
+	req := NewRequest("POST", server.URL)
+	req.RetryProgress = func(notice RetryNotice) { notices = append(notices, notice) }
+	start := time.Now()
+	resp, err := client.SendWithRetry(req, RetryPolicy{MaxAttempts: 3})
+	if err != nil {
+		t.Fatalf("Expected the retry after the 429 to succeed, got %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || resp.Body != "ok" {
+		t.Errorf("Unexpected response %d %q", resp.StatusCode, resp.Body)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("Expected 2 attempts, got %d", got)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("Expected the retry to wait out Retry-After, sent after %v", elapsed)
+	}
+	if len(notices) != 1 || notices[0].Reason != ErrorTypeRateLimit {
+		t.Errorf("Expected one rate limit retry notice, got %+v", notices)
+	}
+}
+
+func TestSendWithRetryRateLimitExhausted(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	t.Cleanup(server.Close)
+	client := newRetryClient(t, 0)
+
+	resp, err := client.SendWithRetry(NewRequest("GET", server.URL), RetryPolicy{MaxAttempts: 2})
+	if err != nil {
+		t.Fatalf("Expected the last 429 to be returned as a response, got %v", err)
+	}
+	if !resp.IsRateLimited() {
+		t.Errorf("Expected status 429, got %d", resp.StatusCode)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("Expected 2 attempts, got %d", got)
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 4, BaseDelay: time.Second}
+	want := []time.Duration{0, time.Second, 2 * time.Second, 4 * time.Second}
+	for failed, delay := range want {
+		if got := policy.Delay(failed); got != delay {
+			t.Errorf("Delay(%d) = %v, want %v", failed, got, delay)
+		}
+	}
+
+	if got := DefaultRetryPolicy(); got.MaxAttempts != 3 || got.BaseDelay != time.Second {
+		t.Errorf("DefaultRetryPolicy() = %+v", got)
+	}
+}
+
+func TestAttemptInfoString(t *testing.T) {
+	tests := []struct {
+		attempt AttemptInfo
+		want    string
+	}{
+		{AttemptInfo{Number: 1, ErrorType: ErrorTypeTimeout}, "attempt 1: timeout"},
+		{AttemptInfo{Number: 2, ErrorType: ErrorTypeTimeout, NewCircuit: true}, "attempt 2: new circuit, timeout"},
+		{AttemptInfo{Number: 3, ErrorType: ErrorTypeRateLimit}, "attempt 3: rate limit"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.attempt.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+// flakySOCKSProxy is a SOCKS5 proxy that answers the first failures CONNECTs
+// with "general SOCKS server failure", as Tor does for an unreachable onion
+// service, then answers HTTP requests itself. It records the usernames sent.
+func flakySOCKSProxy(t *testing.T, failures int) (string, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	users := make(chan string, 10)
+	go func() {
+		for connections := 0; ; connections++ {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn, fail bool) {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+
+				greeting := make([]byte, 2)
+				io.ReadFull(reader, greeting)
+				methods := make([]byte, greeting[1])
+				io.ReadFull(reader, methods)
+
+				// Accept username/password when offered, like Tor's IsolateSOCKSAuth
+				user := ""
+				if strings.IndexByte(string(methods), 2) >= 0 {
+					conn.Write([]byte{5, 2})
+					version := make([]byte, 2)
+					io.ReadFull(reader, version)
+					name := make([]byte, version[1])
+					io.ReadFull(reader, name)
+					length, _ := reader.ReadByte()
+					io.ReadFull(reader, make([]byte, length))
+					conn.Write([]byte{1, 0})
+					user = string(name)
+				} else {
+					conn.Write([]byte{5, 0})
+				}
+				users <- user
+
+				header := make([]byte, 4)
+				io.ReadFull(reader, header)
+				length, _ := reader.ReadByte()
+				io.ReadFull(reader, make([]byte, int(length)+2)) // domain name and port
+
+				if fail {
+					conn.Write([]byte{5, 1, 0, 1, 0, 0, 0, 0, 0, 0})
+					return
+				}
+				conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+				if _, err := http.ReadRequest(reader); err == nil {
+					io.WriteString(conn, "HTTP/1.1 200 OK\r\nContent-Length: 2\r\nConnection: close\r\n\r\nok")
+				}
+			}(conn, connections < failures)
+		}
+	}()
+	return listener.Addr().String(), users
+}
+
+func newOnionRetryClient(t *testing.T, proxyAddr string, maxRetries int) *Client {
+	client, err := NewClient(&ClientConfig{TorEnabled: true, TorProxy: proxyAddr, Timeout: 5 * time.Second, MaxRetries: maxRetries})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client.retryPolicy.BaseDelay = 0
+	return client
+}
+
+func TestSendRetriesUnreachableOnion(t *testing.T) {
+	proxyAddr, users := flakySOCKSProxy(t, 2)
+	client := newOnionRetryClient(t, proxyAddr, 2)
+
+	req := NewRequest("GET", "http://"+strings.Repeat("a", 56)+".onion/")
+	var notices []string
+	req.RetryProgress = func(notice RetryNotice) {
+		notices = append(notices, notice.String())
+	}
+
+	resp, err := client.Send(req)
+	if err != nil {
+		t.Fatalf("Expected the third attempt to succeed, got %v", err)
+	}
+	if resp.Body != "ok" {
+		t.Errorf("Body = %q, want %q", resp.Body, "ok")
+	}
+
+	want := []string{
+		"onion service unreachable, trying a new circuit (2/3)",
+		"onion service unreachable, trying a new circuit (3/3)",
+	}
+	if strings.Join(notices, "|") != strings.Join(want, "|") {
+		t.Errorf("Retry notices = %q, want %q", notices, want)
+	}
+
+	// Each retry must use fresh SOCKS credentials so Tor builds a new circuit
+	seen := map[string]bool{}
+	for i := 0; i < 3; i++ {
+		user := <-users
+		if i > 0 && (user == "" || seen[user]) {
+			t.Errorf("Attempt %d used SOCKS username %q, want a new one", i+1, user)
+		}
+		seen[user] = true
+	}
+}
+
+func TestSendUnreachableOnionExhausted(t *testing.T) {
+	proxyAddr, _ := flakySOCKSProxy(t, 100)
+	client := newOnionRetryClient(t, proxyAddr, 1)
+
+	url := "http://" + strings.Repeat("b", 56) + ".onion/"
+	_, err := client.Send(NewRequest("GET", url))
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("Expected RetryError, got %v", err)
+	}
+	if len(retryErr.Attempts) != 2 {
+		t.Errorf("Expected 2 attempts, got %d", len(retryErr.Attempts))
+	}
+
+	diagnostic := NewErrorAnalyzer().AnalyzeError(err, url)
+	if diagnostic.Type != ErrorTypeTor {
+		t.Errorf("Type = %s, want %s", diagnostic.Type, ErrorTypeTor)
+	}
+	last := diagnostic.Suggestions[len(diagnostic.Suggestions)-1]
+	if !strings.HasPrefix(last, "The service may be offline") {
+		t.Errorf("Expected an offline suggestion last, got %q", last)
+	}
+}
+
+func TestRetryNoticeString(t *testing.T) {
+	tests := []struct {
+		notice RetryNotice
+		want   string
+	}{
+		{RetryNotice{Attempt: 2, Total: 3, Reason: ErrorTypeTor, OnionDown: true, NewCircuit: true}, "onion service unreachable, trying a new circuit (2/3)"},
+		{RetryNotice{Attempt: 2, Total: 2, Reason: ErrorTypeTimeout}, "timeout, retrying (2/2)"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.notice.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Severity ranks a security finding
+type Severity string
+
+const (
+	SeverityHigh   Severity = "high"
+	SeverityMedium Severity = "medium"
+	SeverityLow    Severity = "low"
+	SeverityInfo   Severity = "info" // header present and sensible, or not applicable
+)
+
+// severityRank orders findings from most to least severe
+var severityRank = map[Severity]int{
+	SeverityHigh:   0,
+	SeverityMedium: 1,
+	SeverityLow:    2,
+	SeverityInfo:   3,
+}
+
+// SecurityFinding is one observation from a response-headers security audit
+type SecurityFinding struct {
+	Header   string   `json:"header"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// minHSTSMaxAge is the shortest Strict-Transport-Security max-age (180 days)
+// not reported as too short
+const minHSTSMaxAge = 180 * 24 * 60 * 60
+
+// versionPattern matches a version number in a Server or X-Powered-By value
+var versionPattern = regexp.MustCompile(`\d+(\.\d+)+`)
+
+// AuditSecurityHeaders checks the response headers for security-relevant
+// properties: transport security, content security policy, framing and MIME
+// sniffing protection, software disclosure and cookie flags. Findings are
+// ordered from most to least severe; headers that are present and sensible
+// are reported as info so the list shows what was checked.
+func AuditSecurityHeaders(resp *Response) []SecurityFinding {
+	var findings []SecurityFinding
+	add := func(header string, severity Severity, format string, args ...interface{}) {
+		findings = append(findings, SecurityFinding{Header: header, Severity: severity, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// Strict-Transport-Security only has an effect over HTTPS
+	hsts, hasHSTS := responseHeader(resp, "Strict-Transport-Security")
+	switch {
+	case resp.TLSInfo == nil:
+		add("Strict-Transport-Security", SeverityInfo, "Not applicable: response was not received over HTTPS")
+	case !hasHSTS:
+		add("Strict-Transport-Security", SeverityMedium, "Missing: browsers may connect over plain HTTP first")
+	default:
+		if maxAge, ok := directiveValue(hsts, "max-age"); !ok {
+			add("Strict-Transport-Security", SeverityLow, "No max-age directive, so the policy is ignored")
+		} else if seconds, err := strconv.Atoi(maxAge); err != nil || seconds < minHSTSMaxAge {
+			add("Strict-Transport-Security", SeverityLow, "max-age=%s is shorter than 180 days", maxAge)
+		} else {
+			add("Strict-Transport-Security", SeverityInfo, "Present: %s", hsts)
+		}
+	}
+
+	csp, hasCSP := responseHeader(resp, "Content-Security-Policy")
+	if !hasCSP {
+		add("Content-Security-Policy", SeverityMedium, "Missing: no restriction on script, style or frame sources")
+	} else if strings.Contains(csp, "'unsafe-inline'") || strings.Contains(csp, "'unsafe-eval'") {
+		add("Content-Security-Policy", SeverityLow, "Allows 'unsafe-inline' or 'unsafe-eval', weakening XSS protection")
+	} else {
+		add("Content-Security-Policy", SeverityInfo, "Present: %s", csp)
+	}
+
+	// CSP frame-ancestors supersedes X-Frame-Options
+	frameOptions, hasFrameOptions := responseHeader(resp, "X-Frame-Options")
+	_, hasFrameAncestors := directiveValue(csp, "frame-ancestors")
+	switch {
+	case hasFrameOptions && (strings.EqualFold(frameOptions, "DENY") || strings.EqualFold(frameOptions, "SAMEORIGIN")):
+		add("X-Frame-Options", SeverityInfo, "Present: %s", frameOptions)
+	case hasFrameOptions:
+		add("X-Frame-Options", SeverityLow, "Unsupported value %q, use DENY or SAMEORIGIN", frameOptions)
+	case hasFrameAncestors:
+		add("X-Frame-Options", SeverityInfo, "Missing, but CSP frame-ancestors controls framing")
+	default:
+		add("X-Frame-Options", SeverityMedium, "Missing: the page can be framed (clickjacking)")
+	}
+
+	contentTypeOptions, hasContentTypeOptions := responseHeader(resp, "X-Content-Type-Options")
+	switch {
+	case !hasContentTypeOptions:
+		add("X-Content-Type-Options", SeverityLow, "Missing: browsers may MIME-sniff the body")
+	case !strings.EqualFold(contentTypeOptions, "nosniff"):
+		add("X-Content-Type-Options", SeverityLow, "Unsupported value %q, use nosniff", contentTypeOptions)
+	default:
+		add("X-Content-Type-Options", SeverityInfo, "Present: nosniff")
+	}
+
+	// Software banners help fingerprint a service, which matters for onion
+	// services trying to stay unlinkable to a clearnet host
+	for _, name := range []string{"Server", "X-Powered-By"} {
+		value, ok := responseHeader(resp, name)
+		switch {
+		case !ok:
+			add(name, SeverityInfo, "Not disclosed")
+		case versionPattern.MatchString(value):
+			add(name, SeverityMedium, "Discloses software version: %s", value)
+		default:
+			add(name, SeverityLow, "Discloses software: %s", value)
+		}
+	}
+
+	cookies := resp.SetCookies
+	if setCookie, ok := responseHeader(resp, "Set-Cookie"); ok && len(cookies) == 0 {
+		cookies = []string{setCookie}
+	}
+	for _, cookie := range cookies {
+		findings = append(findings, auditCookie(cookie)...)
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return severityRank[findings[i].Severity] < severityRank[findings[j].Severity]
+	})
+	return findings
+}
+
+// auditCookie checks one Set-Cookie value for the Secure, HttpOnly and SameSite flags
+func auditCookie(setCookie string) []SecurityFinding {
+	parts := strings.Split(setCookie, ";")
+	name, _, _ := strings.Cut(strings.TrimSpace(parts[0]), "=")
+	header := "Set-Cookie " + name
+
+	var secure, httpOnly bool
+	sameSite := ""
+	for _, part := range parts[1:] {
+		attribute, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch strings.ToLower(attribute) {
+		case "secure":
+			secure = true
+		case "httponly":
+			httpOnly = true
+		case "samesite":
+			sameSite = strings.ToLower(strings.TrimSpace(value))
+		}
+	}
+
+	var missing []string
+	if !secure {
+		missing = append(missing, "Secure")
+	}
+	if !httpOnly {
+		missing = append(missing, "HttpOnly")
+	}
+	if sameSite == "" {
+		missing = append(missing, "SameSite")
+	}
+
+	var findings []SecurityFinding
+	if sameSite == "none" && !secure {
+		findings = append(findings, SecurityFinding{Header: header, Severity: SeverityHigh,
+			Message: "SameSite=None without Secure: sent on cross-site requests and rejected by modern browsers"})
+	}
+	switch {
+	case len(missing) == 0:
+		findings = append(findings, SecurityFinding{Header: header, Severity: SeverityInfo,
+			Message: "Secure, HttpOnly and SameSite=" + sameSite})
+	case !secure || !httpOnly:
+		findings = append(findings, SecurityFinding{Header: header, Severity: SeverityMedium,
+			Message: "Missing " + strings.Join(missing, ", ")})
+	default:
+		findings = append(findings, SecurityFinding{Header: header, Severity: SeverityLow,
+			Message: "Missing SameSite"})
+	}
+	return findings
+}
+
+// responseHeader looks up a response header, ignoring case
+func responseHeader(resp *Response, name string) (string, bool) {
+	for key, value := range resp.Headers {
+		if strings.EqualFold(key, name) {
+			return strings.TrimSpace(value), true
+		}
+	}
+	return "", false
+}
+
+// directiveValue returns the value of a directive in a header such as
+// "max-age=31536000; includeSubDomains" or a CSP "frame-ancestors 'none'"
+func directiveValue(header, directive string) (string, bool) {
+	for _, part := range strings.Split(header, ";") {
+		part = strings.TrimSpace(part)
+		name, value := part, ""
+		if i := strings.IndexAny(part, "= "); i >= 0 {
+			name, value = part[:i], part[i+1:]
+		}
+		if strings.EqualFold(name, directive) {
+			return strings.Trim(strings.TrimSpace(value), `"`), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"testing"
+)
+
+// findingFor returns the first finding for header, or nil
+func findingFor(findings []SecurityFinding, header string) *SecurityFinding {
+	for i := range findings {
+		if findings[i].Header == header {
+			return &findings[i]
+		}
+	}
+	return nil
+}
+
+func TestAuditSecurityHeadersPresent(t *testing.T) {
+	resp := &Response{
+		Headers: map[string]string{
+			"Strict-Transport-Security": "max-age=31536000; includeSubDomains",
+			"Content-Security-Policy":   "default-src 'self'; frame-ancestors 'none'",
+			"X-Frame-Options":           "DENY",
+			"X-Content-Type-Options":    "nosniff",
+		},
+		TLSInfo:    &TLSInfo{},
+		SetCookies: []string{"session=abc; Path=/; Secure; HttpOnly; SameSite=Strict"},
+	}
+
+	findings := AuditSecurityHeaders(resp)
+	for _, finding := range findings {
+		if finding.Severity != SeverityInfo {
+			t.Errorf("%s: severity = %s (%s), want info", finding.Header, finding.Severity, finding.Message)
+		}
+	}
+	for _, header := range []string{"Strict-Transport-Security", "Content-Security-Policy", "X-Frame-Options",
+		"X-Content-Type-Options", "Server", "X-Powered-By", "Set-Cookie session"} {
+		if findingFor(findings, header) == nil {
+			t.Errorf("No finding for %s", header)
+		}
+	}
+}
+
+func TestAuditSecurityHeadersAbsent(t *testing.T) {
+	resp := &Response{
+		Headers: map[string]string{
+			"Server":       "nginx/1.18.0",
+			"X-Powered-By": "PHP",
+		},
+		TLSInfo: &TLSInfo{},
+		SetCookies: []string{
+			"session=abc; Path=/",
+			"prefs=dark; Secure; HttpOnly",
+			"track=1; SameSite=None",
+		},
+	}
+
+	tests := []struct {
+		header   string
+		severity Severity
+	}{
+		{"Strict-Transport-Security", SeverityMedium},
+		{"Content-Security-Policy", SeverityMedium},
+		{"X-Frame-Options", SeverityMedium},
+		{"X-Content-Type-Options", SeverityLow},
+		{"Server", SeverityMedium},
+		{"X-Powered-By", SeverityLow},
+		{"Set-Cookie session", SeverityMedium},
+		{"Set-Cookie prefs", SeverityLow},
+		{"Set-Cookie track", SeverityHigh},
+	}
+
+	findings := AuditSecurityHeaders(resp)
+	for _, tt := range tests {
+		finding := findingFor(findings, tt.header)
+		if finding == nil {
+			t.Errorf("No finding for %s", tt.header)
+			continue
+		}
+		if finding.Severity != tt.severity {
+			t.Errorf("%s: severity = %s (%s), want %s", tt.header, finding.Severity, finding.Message, tt.severity)
+		}
+	}
+
+	// Most severe first
+	if findings[0].Severity != SeverityHigh {
+		t.Errorf("First finding = %+v, want the high severity cookie", findings[0])
+	}
+	for i := 1; i < len(findings); i++ {
+		if severityRank[findings[i].Severity] < severityRank[findings[i-1].Severity] {
+			t.Errorf("Findings out of order at %d: %s after %s", i, findings[i].Severity, findings[i-1].Severity)
+		}
+	}
+}
+
+func TestAuditSecurityHeadersWeakValues(t *testing.T) {
+	resp := &Response{
+		Headers: map[string]string{
+			"strict-transport-security": "max-age=3600",
+			"content-security-policy":   "script-src 'self' 'unsafe-inline'",
+			"x-frame-options":           "ALLOW-FROM https://example.com",
+			"x-content-type-options":    "sniff",
+			"set-cookie":                "id=1; Secure; HttpOnly; SameSite=Lax",
+		},
+		TLSInfo: &TLSInfo{},
+	}
+
+	findings := AuditSecurityHeaders(resp)
+	for _, header := range []string{"Strict-Transport-Security", "Content-Security-Policy", "X-Frame-Options", "X-Content-Type-Options"} {
+		if finding := findingFor(findings, header); finding == nil || finding.Severity != SeverityLow {
+			t.Errorf("%s: finding = %+v, want low severity", header, finding)
+		}
+	}
+	if finding := findingFor(findings, "Set-Cookie id"); finding == nil || finding.Severity != SeverityInfo {
+		t.Errorf("Set-Cookie from Headers: finding = %+v, want info", finding)
+	}
+}
+
+func TestAuditSecurityHeadersPlainHTTP(t *testing.T) {
+	findings := AuditSecurityHeaders(&Response{Headers: map[string]string{}})
+	if finding := findingFor(findings, "Strict-Transport-Security"); finding == nil || finding.Severity != SeverityInfo {
+		t.Errorf("HSTS over plain HTTP: finding = %+v, want info", finding)
+	}
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// sniffLength is how much of the body http.DetectContentType looks at
+const sniffLength = 512
+
+// ContentType returns the response's Content-Type. When the header is missing
+// or cannot be parsed, the type is sniffed from the body instead and sniffed
+// is true. An empty body without a header has no content type.
+func (r *Response) ContentType() (contentType string, sniffed bool) {
+	if header := strings.TrimSpace(r.Headers["Content-Type"]); header != "" {
+		if _, _, err := mime.ParseMediaType(header); err == nil {
+			return header, false
+		}
+	}
+
+	if r.Body == "" {
+		return "", false
+	}
+	return sniffContentType([]byte(r.Body)), true
+}
+
+// IsJSON reports whether the response body is JSON, going by the header or,
+// without one, by sniffing
+func (r *Response) IsJSON() bool {
+	contentType, _ := r.ContentType()
+	return strings.Contains(strings.ToLower(contentType), "json")
+}
+
+// IsXML reports whether the response body is XML: the header names an XML
+// type, such as application/xml, text/xml or application/soap+xml, or the
+// body starts with an XML declaration
+func (r *Response) IsXML() bool {
+	if strings.HasPrefix(strings.TrimSpace(r.Body), "<?xml") {
+		return true
+	}
+	contentType, _ := r.ContentType()
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml")
+}
+
+// sniffContentType infers a media type from a body. http.DetectContentType
+// does not recognise JSON, so JSON objects and arrays are checked first.
+func sniffContentType(body []byte) string {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid(trimmed) {
+		return "application/json"
+	}
+
+	if len(body) > sniffLength {
+		body = body[:sniffLength]
+	}
+	return http.DetectContentType(body)
+}
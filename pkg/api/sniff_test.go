@@ -0,0 +1,53 @@
+package api
+
+import "testing"
+
+func TestContentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      string
+		body        string
+		wantType    string
+		wantSniffed bool
+	}{
+		{"header present", "application/json; charset=utf-8", `{"ok":true}`, "application/json; charset=utf-8", false},
+		{"header wins over body", "text/plain", `{"ok":true}`, "text/plain", false},
+		{"absent header JSON object", "", `  {"ok": true}`, "application/json", true},
+		{"absent header JSON array", "", `[1, 2, 3]`, "application/json", true},
+		{"absent header HTML", "", "<!DOCTYPE html><html><body>hi</body></html>", "text/html; charset=utf-8", true},
+		{"absent header invalid JSON", "", `{"ok":`, "text/plain; charset=utf-8", true},
+		{"invalid header", "not a type;;", "<html><body>hi</body></html>", "text/html; charset=utf-8", true},
+		{"absent header empty body", "", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := &Response{Headers: map[string]string{}, Body: tt.body}
+			if tt.header != "" {
+				response.Headers["Content-Type"] = tt.header
+			}
+
+			contentType, sniffed := response.ContentType()
+			if contentType != tt.wantType || sniffed != tt.wantSniffed {
+				t.Errorf("ContentType() = %q, %v, want %q, %v", contentType, sniffed, tt.wantType, tt.wantSniffed)
+			}
+		})
+	}
+}
+
+func TestPrettyPrintJSONWithoutContentType(t *testing.T) {
+	response := &Response{Headers: map[string]string{}, Body: `{"a":1}`}
+
+	got, err := response.PrettyPrintJSON()
+	if err != nil {
+		t.Fatalf("PrettyPrintJSON() error = %v", err)
+	}
+	if want := "{\n  \"a\": 1\n}"; got != want {
+		t.Errorf("PrettyPrintJSON() = %q, want %q", got, want)
+	}
+
+	html := &Response{Headers: map[string]string{}, Body: "<html><body>{}</body></html>"}
+	if html.IsJSON() {
+		t.Error("IsJSON() = true for a header-less HTML body")
+	}
+}
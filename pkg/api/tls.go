@@ -0,0 +1,49 @@
+package api
+
+import (
+	"crypto/tls"
+	"time"
+)
+
+// TLSInfo describes the certificate presented by an HTTPS server
+type TLSInfo struct {
+	Version     string    `json:"version"`
+	CipherSuite string    `json:"cipher_suite"`
+	Subject     string    `json:"subject"`
+	Issuer      string    `json:"issuer"`
+	NotBefore   time.Time `json:"not_before"`
+	NotAfter    time.Time `json:"not_after"`
+	DNSNames    []string  `json:"dns_names,omitempty"`
+	SelfSigned  bool      `json:"self_signed"`
+	Expired     bool      `json:"expired"`
+	NotYetValid bool      `json:"not_yet_valid"`
+}
+
+// HasWarnings reports whether the certificate should not be trusted as-is
+func (t *TLSInfo) HasWarnings() bool {
+	return t.SelfSigned || t.Expired || t.NotYetValid
+}
+
+// newTLSInfo extracts certificate details from a TLS connection state,
+// returning nil for plain HTTP responses
+func newTLSInfo(state *tls.ConnectionState) *TLSInfo {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return nil
+	}
+
+	cert := state.PeerCertificates[0]
+	now := time.Now()
+
+	return &TLSInfo{
+		Version:     tls.VersionName(state.Version),
+		CipherSuite: tls.CipherSuiteName(state.CipherSuite),
+		Subject:     cert.Subject.String(),
+		Issuer:      cert.Issuer.String(),
+		NotBefore:   cert.NotBefore,
+		NotAfter:    cert.NotAfter,
+		DNSNames:    cert.DNSNames,
+		SelfSigned:  cert.Subject.String() == cert.Issuer.String() && cert.CheckSignatureFrom(cert) == nil,
+		Expired:     now.After(cert.NotAfter),
+		NotYetValid: now.Before(cert.NotBefore),
+	}
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestResponseTLSInfo(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+
+	tlsServer := httptest.NewTLSServer(handler)
+	defer tlsServer.Close()
+
+	client, err := NewClient(&ClientConfig{TorEnabled: false, Timeout: 10 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client.httpClient = tlsServer.Client() // trusts the test certificate
+
+	resp, err := client.Send(NewRequest("GET", tlsServer.URL))
+	if err != nil {
+		t.Fatalf("HTTPS request failed: %v", err)
+	}
+
+	info := resp.TLSInfo
+	if info == nil {
+		t.Fatal("Expected TLS info for HTTPS response")
+	}
+	if !info.SelfSigned {
+		t.Error("Expected test server certificate to be reported as self-signed")
+	}
+	if info.Expired || info.NotYetValid {
+		t.Errorf("Expected test certificate to be currently valid, got %+v", info)
+	}
+	if !info.HasWarnings() {
+		t.Error("Expected self-signed certificate to have warnings")
+	}
+	if info.Subject == "" || info.Issuer == "" || info.Version == "" {
+		t.Errorf("Expected subject, issuer and version to be set, got %+v", info)
+	}
+
+	plainServer := httptest.NewServer(handler)
+	defer plainServer.Close()
+
+	client.httpClient = plainServer.Client()
+	resp, err = client.Send(NewRequest("GET", plainServer.URL))
+	if err != nil {
+		t.Fatalf("HTTP request failed: %v", err)
+	}
+	if resp.TLSInfo != nil {
+		t.Errorf("Expected no TLS info for plain HTTP, got %+v", resp.TLSInfo)
+	}
+}
+
+func TestInsecureSkipVerify(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(&ClientConfig{TorEnabled: false, Timeout: 10 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if _, err := client.Send(NewRequest("GET", server.URL)); err == nil {
+		t.Error("Expected the self-signed certificate to be rejected by default")
+	}
+
+	client, err = NewClient(&ClientConfig{TorEnabled: false, Timeout: 10 * time.Second, InsecureSkipVerify: true})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	resp, err := client.Send(NewRequest("GET", server.URL))
+	if err != nil {
+		t.Fatalf("Expected InsecureSkipVerify to accept the certificate, got %v", err)
+	}
+	if resp.Body != "ok" {
+		t.Errorf("Body = %q, want %q", resp.Body, "ok")
+	}
+}
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// defaultControlPort is Tor's default ControlPort, used on the SOCKS proxy's host
+	defaultControlPort = 9051
+
+	// torCheckURL reports whether a request arrived through Tor
+	torCheckURL = "https://check.torproject.org/api/ip"
+
+	// probeTimeout bounds each local probe; the check service goes through
+	// Tor and gets the client's request timeout instead
+	probeTimeout = 3 * time.Second
+)
+
+// ProbeResult is the outcome of one live connection check
+type ProbeResult struct {
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Skipped bool   `json:"skipped,omitempty"` // an earlier check failed, so this one could not run
+	Detail  string `json:"detail"`
+}
+
+// TorProber runs the individual checks behind RunTorDiagnostics. Client
+// implements it against the real Tor proxy.
+type TorProber interface {
+	SOCKSAddress() string
+	ControlAddress() string
+
+	// ProbeSOCKS checks that the SOCKS port accepts connections
+	ProbeSOCKS(ctx context.Context) error
+
+	// ProbeControlPort checks that the control port answers, returning the
+	// Tor version it reports, or "" if it reported none
+	ProbeControlPort(ctx context.Context) (string, error)
+
+	// ProbeTorCheck asks a check service whether requests arrive through Tor
+	ProbeTorCheck(ctx context.Context) (bool, error)
+}
+
+// RunTorDiagnostics probes the Tor setup and returns a checklist: whether
+// the SOCKS port is open, whether the control port is reachable, the Tor
+// version it reports, and whether a Tor check service is reachable through
+// the proxy. Checks that depend on a failed one are reported as skipped.
+func RunTorDiagnostics(ctx context.Context, prober TorProber) []ProbeResult {
+	var results []ProbeResult
+
+	socksErr := prober.ProbeSOCKS(ctx)
+	if socksErr != nil {
+		results = append(results, ProbeResult{Name: "SOCKS port", Detail: fmt.Sprintf("%s: %v", prober.SOCKSAddress(), socksErr)})
+	} else {
+		results = append(results, ProbeResult{Name: "SOCKS port", Passed: true, Detail: "open at " + prober.SOCKSAddress()})
+	}
+
+	version, controlErr := prober.ProbeControlPort(ctx)
+	if controlErr != nil {
+		results = append(results,
+			ProbeResult{Name: "Control port", Detail: fmt.Sprintf("%s: %v", prober.ControlAddress(), controlErr)},
+			ProbeResult{Name: "Tor version", Skipped: true, Detail: "control port unreachable"})
+	} else {
+		results = append(results, ProbeResult{Name: "Control port", Passed: true, Detail: "reachable at " + prober.ControlAddress()})
+		if version == "" {
+			results = append(results, ProbeResult{Name: "Tor version", Detail: "not reported by the control port"})
+		} else {
+			results = append(results, ProbeResult{Name: "Tor version", Passed: true, Detail: version})
+		}
+	}
+
+	if socksErr != nil {
+		return append(results, ProbeResult{Name: "Tor check service", Skipped: true, Detail: "SOCKS port closed"})
+	}
+	isTor, err := prober.ProbeTorCheck(ctx)
+	switch {
+	case err != nil:
+		results = append(results, ProbeResult{Name: "Tor check service", Detail: fmt.Sprintf("unreachable through Tor: %v", err)})
+	case !isTor:
+		results = append(results, ProbeResult{Name: "Tor check service", Detail: "reachable, but requests are not arriving through Tor"})
+	default:
+		results = append(results, ProbeResult{Name: "Tor check service", Passed: true, Detail: "reachable, requests arrive through Tor"})
+	}
+	return results
+}
+
+// DiagnoseTor runs RunTorDiagnostics against the client's Tor proxy
+func (c *Client) DiagnoseTor(ctx context.Context) []ProbeResult {
+	return RunTorDiagnostics(ctx, c)
+}
+
+// SOCKSAddress returns the Tor SOCKS proxy address
+func (c *Client) SOCKSAddress() string {
+	return c.torProxy
+}
+
+// ControlAddress returns the Tor control port address: the configured
+// control port, or Tor's default, on the SOCKS proxy's host
+func (c *Client) ControlAddress() string {
+	host, _, err := net.SplitHostPort(c.torProxy)
+	if err != nil {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, strconv.Itoa(c.controlPortNumber()))
+}
+
+// ProbeSOCKS checks that the SOCKS port accepts connections
+func (c *Client) ProbeSOCKS(ctx context.Context) error {
+	dialer := net.Dialer{Timeout: probeTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", c.torProxy)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// ProbeControlPort connects to the control port and asks for PROTOCOLINFO,
+// which Tor answers before authentication, to read its version
+func (c *Client) ProbeControlPort(ctx context.Context) (string, error) {
+	cc, err := c.dialControl(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer cc.Close()
+
+	lines, err := cc.command("PROTOCOLINFO 1")
+	if err != nil {
+		return "", err
+	}
+	return parseProtocolInfo(lines).version, nil
+}
+
+// ProbeTorCheck asks the Tor Project's check service, through the client's
+// proxy, whether the request arrived through Tor
+func (c *Client) ProbeTorCheck(ctx context.Context) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, torCheckURL, nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("check service responded with %s", resp.Status)
+	}
+
+	var result struct {
+		IsTor bool `json:"IsTor"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, fmt.Errorf("unexpected check service response: %w", err)
+	}
+	return result.IsTor, nil
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// mockProber returns canned results and records which probes ran
+type mockProber struct {
+	socksErr   error
+	version    string
+	controlErr error
+	isTor      bool
+	checkErr   error
+	ran        []string
+}
+
+func (p *mockProber) SOCKSAddress() string   { return "127.0.0.1:9050" }
+func (p *mockProber) ControlAddress() string { return "127.0.0.1:9051" }
+
+func (p *mockProber) ProbeSOCKS(ctx context.Context) error {
+	p.ran = append(p.ran, "socks")
+	return p.socksErr
+}
+
+func (p *mockProber) ProbeControlPort(ctx context.Context) (string, error) {
+	p.ran = append(p.ran, "control")
+	return p.version, p.controlErr
+}
+
+func (p *mockProber) ProbeTorCheck(ctx context.Context) (bool, error) {
+	p.ran = append(p.ran, "check")
+	return p.isTor, p.checkErr
+}
+
+// probeOutcome summarises a result as "pass", "fail" or "skip"
+func probeOutcome(result ProbeResult) string {
+	switch {
+	case result.Skipped:
+		return "skip"
+	case result.Passed:
+		return "pass"
+	default:
+		return "fail"
+	}
+}
+
+func TestRunTorDiagnostics(t *testing.T) {
+	refused := errors.New("connection refused")
+	tests := []struct {
+		name     string
+		prober   *mockProber
+		outcomes string // SOCKS port, control port, Tor version, check service
+		ran      string
+	}{
+		{"all healthy", &mockProber{version: "0.4.8.9", isTor: true}, "pass pass pass pass", "socks control check"},
+		{"Tor not running", &mockProber{socksErr: refused, controlErr: refused}, "fail fail skip skip", "socks control"},
+		{"no control port", &mockProber{controlErr: refused, isTor: true}, "pass fail skip pass", "socks control check"},
+		{"version not reported", &mockProber{isTor: true}, "pass pass fail pass", "socks control check"},
+		{"check service unreachable", &mockProber{version: "0.4.8.9", checkErr: errors.New("timeout")}, "pass pass pass fail", "socks control check"},
+		{"not exiting through Tor", &mockProber{version: "0.4.8.9"}, "pass pass pass fail", "socks control check"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := RunTorDiagnostics(context.Background(), tt.prober)
+
+			var names, outcomes []string
+			for _, result := range results {
+				names = append(names, result.Name)
+				outcomes = append(outcomes, probeOutcome(result))
+			}
+			if got := strings.Join(names, ", "); got != "SOCKS port, Control port, Tor version, Tor check service" {
+				t.Errorf("Checks = %s", got)
+			}
+			if got := strings.Join(outcomes, " "); got != tt.outcomes {
+				t.Errorf("Outcomes = %s, want %s (%+v)", got, tt.outcomes, results)
+			}
+			if got := strings.Join(tt.prober.ran, " "); got != tt.ran {
+				t.Errorf("Probes run = %s, want %s", got, tt.ran)
+			}
+		})
+	}
+}
+
+func TestReadProtocolInfoVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "version",
+			reply: "250-PROTOCOLINFO 1\r\n250-AUTH METHODS=COOKIE,SAFECOOKIE COOKIEFILE=\"/run/tor/control.authcookie\"\r\n250-VERSION Tor=\"0.4.8.9\"\r\n250 OK\r\n",
+			want:  "0.4.8.9",
+		},
+		{name: "no version", reply: "250-PROTOCOLINFO 1\r\n250 OK\r\n", want: ""},
+		{name: "error reply", reply: "515 Authentication required.\r\n", wantErr: true},
+		{name: "cut short", reply: "250-PROTOCOLINFO 1\r\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, err := readControlReply(bufio.NewReader(strings.NewReader(tt.reply)))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readControlReply() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := parseProtocolInfo(lines).version; got != tt.want {
+				t.Errorf("parseProtocolInfo().version = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientControlAddress(t *testing.T) {
+	client := &Client{torProxy: "10.0.0.5:9150"}
+	if got := client.ControlAddress(); got != "10.0.0.5:9051" {
+		t.Errorf("ControlAddress() = %q, want %q", got, "10.0.0.5:9051")
+	}
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// UploadProgressFunc is called as a streamed request body is sent
+type UploadProgressFunc func(sent, total int64)
+
+// SetBodyFile streams the file at path as the request body instead of Body
+func (r *Request) SetBodyFile(path string) {
+	r.BodyFile = path
+	r.Body = ""
+}
+
+// countingReader reports how many bytes have been read through it
+type countingReader struct {
+	reader   io.Reader
+	sent     int64
+	total    int64
+	progress UploadProgressFunc
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.reader.Read(p)
+	if n > 0 {
+		cr.sent += int64(n)
+		if cr.progress != nil {
+			cr.progress(cr.sent, cr.total)
+		}
+	}
+	return n, err
+}
+
+// openBodyFile opens req.BodyFile for streaming, returning the body reader,
+// its length for the Content-Length header, and the file to close afterwards
+func openBodyFile(req *Request) (io.Reader, int64, io.Closer, error) {
+	file, err := os.Open(req.BodyFile)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("failed to open body file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, 0, nil, fmt.Errorf("failed to stat body file: %w", err)
+	}
+	if info.IsDir() {
+		file.Close()
+		return nil, 0, nil, fmt.Errorf("body file %s is a directory", req.BodyFile)
+	}
+
+	reader := &countingReader{
+		reader:   file,
+		total:    info.Size(),
+		progress: req.UploadProgress,
+	}
+	return reader, info.Size(), file, nil
+}
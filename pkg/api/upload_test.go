@@ -0,0 +1,112 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSendBodyFileStreams(t *testing.T) {
+	const size = 64 << 20 // 64 MiB
+
+	path := filepath.Join(t.TempDir(), "payload.bin")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	if err := file.Truncate(size); err != nil {
+		t.Fatalf("Failed to size temp file: %v", err)
+	}
+	file.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, err := io.Copy(io.Discard, r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, "%d %d %v", r.ContentLength, received, r.TransferEncoding)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(&ClientConfig{TorEnabled: false, Timeout: 30 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	var lastSent, lastTotal int64
+	calls := 0
+	req := NewRequest("PUT", server.URL)
+	req.SetBodyFile(path)
+	req.UploadProgress = func(sent, total int64) {
+		calls++
+		lastSent, lastTotal = sent, total
+	}
+
+	resp, err := client.Send(req)
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+
+	want := fmt.Sprintf("%d %d []", size, size)
+	if resp.Body != want {
+		t.Errorf("Expected server to see %q (Content-Length, bytes, no chunking), got %q", want, resp.Body)
+	}
+	if calls < 2 {
+		t.Errorf("Expected multiple progress callbacks, got %d", calls)
+	}
+	if lastSent != size || lastTotal != size {
+		t.Errorf("Expected final progress %d/%d, got %d/%d", size, size, lastSent, lastTotal)
+	}
+}
+
+func TestSendBodyFileErrors(t *testing.T) {
+	client, err := NewClient(&ClientConfig{TorEnabled: false, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		req  func() *Request
+	}{
+		{
+			name: "missing file",
+			req: func() *Request {
+				req := NewRequest("POST", "http://127.0.0.1:1/upload")
+				req.SetBodyFile(filepath.Join(t.TempDir(), "missing.bin"))
+				return req
+			},
+		},
+		{
+			name: "directory",
+			req: func() *Request {
+				req := NewRequest("POST", "http://127.0.0.1:1/upload")
+				req.SetBodyFile(t.TempDir())
+				return req
+			},
+		},
+		{
+			name: "body and body file",
+			req: func() *Request {
+				req := NewRequest("POST", "http://127.0.0.1:1/upload")
+				req.BodyFile = "payload.bin"
+				req.Body = "inline"
+				return req
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := client.Send(tt.req()); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
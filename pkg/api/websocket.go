@@ -0,0 +1,196 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+var (
+	// ErrWebSocketClosed is returned by WebSocket.Receive once the server
+	// has closed the connection
+	ErrWebSocketClosed = errors.New("WebSocket closed by the server")
+
+	// ErrWebSocketHandshake wraps a server's refusal to upgrade to a
+	// WebSocket, once the connection itself was made
+	ErrWebSocketHandshake = errors.New("WebSocket handshake failed")
+)
+
+// isSupportedScheme reports whether a URL scheme can be requested: http and
+// https for requests, ws and wss for WebSockets
+func isSupportedScheme(scheme string) bool {
+	switch scheme {
+	case "http", "https", "ws", "wss":
+		return true
+	}
+	return false
+}
+
+// IsWebSocketURL reports whether a URL has a ws:// or wss:// scheme
+func IsWebSocketURL(rawURL string) bool {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "ws" || u.Scheme == "wss"
+}
+
+// WebSocketMessage is one message received on a WebSocket
+type WebSocketMessage struct {
+	Data     string
+	Binary   bool // sent as a binary frame rather than text
+	Received time.Time
+}
+
+// WebSocket is an open WebSocket connection. Sends may come from any
+// goroutine; Receive is meant for a single reader.
+type WebSocket struct {
+	URL string
+
+	conn *websocket.Conn
+	mu   sync.Mutex // serialises sends
+}
+
+// DialWebSocket opens a WebSocket to a ws:// or wss:// URL, through the Tor
+// SOCKS5 proxy when Tor is enabled, so onion services are reached the same
+// way as by Send. headers are sent with the handshake, along with any
+// cookies the client holds for the site and the client's User-Agent unless
+// headers set one. Certificates are checked as for Send. The handshake is
+// bounded by the client's timeout unless ctx has an earlier deadline.
+func (c *Client) DialWebSocket(ctx context.Context, rawURL string, headers map[string]string) (*WebSocket, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL format: %w", err)
+	}
+	if u.Scheme != "ws" && u.Scheme != "wss" {
+		return nil, fmt.Errorf("unsupported scheme: %s (use ws or wss)", u.Scheme)
+	}
+
+	if err := c.checkRoute(rawURL); err != nil {
+		return nil, err
+	}
+
+	// The Origin is the site itself, as a page served from it would send
+	httpScheme := "http"
+	if u.Scheme == "wss" {
+		httpScheme = "https"
+	}
+	siteURL := &url.URL{Scheme: httpScheme, Host: u.Host, Path: u.Path}
+	config, err := websocket.NewConfig(rawURL, httpScheme+"://"+u.Host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid WebSocket URL: %w", err)
+	}
+	for key, value := range headers {
+		config.Header.Set(key, value)
+	}
+	if c.userAgent != "" && config.Header.Get("User-Agent") == "" {
+		config.Header.Set("User-Agent", c.userAgent)
+	}
+	if c.jar != nil {
+		for _, cookie := range c.jar.Cookies(siteURL) {
+			config.Header.Add("Cookie", cookie.String())
+		}
+	}
+
+	if _, ok := ctx.Deadline(); !ok && c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	dial := (&net.Dialer{}).DialContext
+	if c.torEnabled {
+		if dial, err = torDialer(c.torProxy, nil); err != nil {
+			return nil, err
+		}
+	}
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), map[string]string{"ws": "80", "wss": "443"}[u.Scheme])
+	}
+	conn, err := dial(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Abandon the handshake if ctx ends first
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if u.Scheme == "wss" {
+		tlsConfig := c.tlsConfig()
+		tlsConfig.ServerName = u.Hostname()
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			stop()
+			conn.Close()
+			return nil, err
+		}
+		conn = tlsConn
+	}
+
+	ws, err := websocket.NewClient(config, conn)
+	if !stop() {
+		conn.Close()
+		return nil, fmt.Errorf("WebSocket handshake with %s: %w", u.Host, ctx.Err())
+	}
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("%w with %s: %w", ErrWebSocketHandshake, u.Host, err)
+	}
+	conn.SetDeadline(time.Time{})
+
+	return &WebSocket{URL: rawURL, conn: ws}, nil
+}
+
+// messageCodec receives a frame's payload along with whether it was binary
+var messageCodec = websocket.Codec{
+	Marshal: func(v interface{}) ([]byte, byte, error) {
+		return []byte(v.(string)), websocket.TextFrame, nil
+	},
+	Unmarshal: func(data []byte, payloadType byte, v interface{}) error {
+		msg := v.(*WebSocketMessage)
+		msg.Data = string(data)
+		msg.Binary = payloadType == websocket.BinaryFrame
+		return nil
+	},
+}
+
+// SendText sends text as a single text frame
+func (ws *WebSocket) SendText(text string) error {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	return messageCodec.Send(ws.conn, text)
+}
+
+// Receive waits for the next message. Pings are answered while waiting. It
+// returns ErrWebSocketClosed when the server sends a close frame or drops
+// the connection.
+func (ws *WebSocket) Receive() (WebSocketMessage, error) {
+	var msg WebSocketMessage
+	if err := messageCodec.Receive(ws.conn, &msg); err != nil {
+		if errors.Is(err, io.EOF) {
+			return msg, ErrWebSocketClosed
+		}
+		return msg, err
+	}
+	msg.Received = time.Now()
+	return msg, nil
+}
+
+// Close sends a close frame and closes the connection. A pending Receive
+// returns an error.
+func (ws *WebSocket) Close() error {
+	return ws.conn.Close()
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+func TestWebSocket(t *testing.T) {
+	server := httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+		var text string
+		if err := websocket.Message.Receive(ws, &text); err != nil {
+			return
+		}
+		websocket.Message.Send(ws, "echo: "+text+" from "+ws.Request().Header.Get("X-Client"))
+		websocket.Message.Send(ws, []byte{0x01, 0x02})
+		ws.Close()
+	}))
+	defer server.Close()
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	client, err := NewClient(&ClientConfig{TorEnabled: false, Timeout: 10 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	ws, err := client.DialWebSocket(context.Background(), wsURL, map[string]string{"X-Client": "onioncli"})
+	if err != nil {
+		t.Fatalf("DialWebSocket failed: %v", err)
+	}
+	defer ws.Close()
+
+	if err := ws.SendText("hello"); err != nil {
+		t.Fatalf("SendText failed: %v", err)
+	}
+	msg, err := ws.Receive()
+	if err != nil || msg.Data != "echo: hello from onioncli" || msg.Binary {
+		t.Errorf("Receive() = %+v, %v; want the text echo", msg, err)
+	}
+	msg, err = ws.Receive()
+	if err != nil || !msg.Binary || msg.Data != "\x01\x02" {
+		t.Errorf("Receive() = %+v, %v; want the binary frame", msg, err)
+	}
+	if _, err := ws.Receive(); !errors.Is(err, ErrWebSocketClosed) {
+		t.Errorf("Receive() after close error = %v, want ErrWebSocketClosed", err)
+	}
+
+	if _, err := client.Send(NewRequest("GET", wsURL)); err == nil {
+		t.Error("Send accepted a ws:// URL")
+	}
+}
+
+func TestDialWebSocketRefused(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(&ClientConfig{TorEnabled: false, Timeout: 10 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+	_, err = client.DialWebSocket(context.Background(), wsURL, nil)
+	if !errors.Is(err, ErrWebSocketHandshake) {
+		t.Fatalf("DialWebSocket error = %v, want ErrWebSocketHandshake", err)
+	}
+	if diagnostic := NewErrorAnalyzer().AnalyzeError(err, wsURL); diagnostic.Type != ErrorTypeHTTP {
+		t.Errorf("diagnostic type = %s, want %s", diagnostic.Type, ErrorTypeHTTP)
+	}
+
+	if _, err := client.DialWebSocket(context.Background(), server.URL, nil); err == nil {
+		t.Error("DialWebSocket accepted an http:// URL")
+	}
+}
+
+func TestIsWebSocketURL(t *testing.T) {
+	for rawURL, want := range map[string]bool{
+		"ws://example.onion/socket":  true,
+		"WSS://example.com/":         true,
+		" wss://example.com/feed ":   true,
+		"https://example.com/socket": false,
+		"example.com":                false,
+	} {
+		if got := IsWebSocketURL(rawURL); got != want {
+			t.Errorf("IsWebSocketURL(%q) = %v, want %v", rawURL, got, want)
+		}
+	}
+}
+
+func TestDialWebSocketTLSAndUserAgent(t *testing.T) {
+	userAgents := make(chan string, 2)
+	server := httptest.NewTLSServer(websocket.Handler(func(ws *websocket.Conn) {
+		userAgents <- ws.Request().Header.Get("User-Agent")
+		ws.Close()
+	}))
+	defer server.Close()
+	wsURL := "wss" + strings.TrimPrefix(server.URL, "https")
+
+	verifying, err := NewClient(&ClientConfig{TorEnabled: false, Timeout: 10 * time.Second, UserAgent: "OnionCLI/1.0"})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if _, err := verifying.DialWebSocket(context.Background(), wsURL, nil); err == nil {
+		t.Error("DialWebSocket accepted a self-signed certificate with verification on")
+	}
+
+	client, err := NewClient(&ClientConfig{TorEnabled: false, Timeout: 10 * time.Second, UserAgent: "OnionCLI/1.0", InsecureSkipVerify: true})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	ws, err := client.DialWebSocket(context.Background(), wsURL, nil)
+	if err != nil {
+		t.Fatalf("DialWebSocket with InsecureSkipVerify failed: %v", err)
+	}
+	ws.Close()
+	if got := <-userAgents; got != "OnionCLI/1.0" {
+		t.Errorf("User-Agent = %q, want the client's", got)
+	}
+
+	ws, err = client.DialWebSocket(context.Background(), wsURL, map[string]string{"User-Agent": "Custom/2.0"})
+	if err != nil {
+		t.Fatalf("DialWebSocket failed: %v", err)
+	}
+	ws.Close()
+	if got := <-userAgents; got != "Custom/2.0" {
+		t.Errorf("User-Agent = %q, want the caller's", got)
+	}
+}
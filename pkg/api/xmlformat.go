@@ -0,0 +1,119 @@
+package api
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// xmlNode is an element, text, comment, processing instruction or directive
+// of a parsed XML document
+type xmlNode struct {
+	start    *xml.StartElement // set for elements
+	text     string            // text, or the rendered form of other nodes
+	isText   bool
+	children []*xmlNode
+}
+
+// indentXML re-indents an XML document by two spaces per level. Elements
+// holding only text stay on one line and empty ones are self-closed;
+// whitespace between elements is dropped. Namespace prefixes are kept as
+// written.
+func indentXML(body string) (string, error) {
+	decoder := xml.NewDecoder(strings.NewReader(body))
+	root := &xmlNode{}
+	stack := []*xmlNode{root}
+	for {
+		token, err := decoder.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		parent := stack[len(stack)-1]
+		switch t := token.(type) {
+		case xml.StartElement:
+			start := t.Copy()
+			node := &xmlNode{start: &start}
+			parent.children = append(parent.children, node)
+			stack = append(stack, node)
+		case xml.EndElement:
+			if parent.start == nil || parent.start.Name != t.Name {
+				return "", fmt.Errorf("unexpected closing tag </%s>", xmlName(t.Name))
+			}
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if text := strings.TrimSpace(string(t)); text != "" {
+				parent.children = append(parent.children, &xmlNode{text: text, isText: true})
+			}
+		case xml.Comment:
+			parent.children = append(parent.children, &xmlNode{text: "<!--" + string(t) + "-->"})
+		case xml.ProcInst:
+			parent.children = append(parent.children, &xmlNode{text: "<?" + t.Target + " " + string(t.Inst) + "?>"})
+		case xml.Directive:
+			parent.children = append(parent.children, &xmlNode{text: "<!" + string(t) + ">"})
+		}
+	}
+	if len(stack) > 1 {
+		return "", fmt.Errorf("unclosed tag <%s>", xmlName(stack[len(stack)-1].start.Name))
+	}
+
+	var b strings.Builder
+	for _, node := range root.children {
+		writeXMLNode(&b, node, 0)
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+// writeXMLNode writes node and its children at depth
+func writeXMLNode(b *strings.Builder, node *xmlNode, depth int) {
+	indent := strings.Repeat("  ", depth)
+	if node.start == nil {
+		text := node.text
+		if node.isText {
+			text = escapeXML(text)
+		}
+		b.WriteString(indent + text + "\n")
+		return
+	}
+
+	tag := xmlName(node.start.Name)
+	b.WriteString(indent + "<" + tag)
+	for _, attr := range node.start.Attr {
+		b.WriteString(" " + xmlName(attr.Name) + `="` + escapeXML(attr.Value) + `"`)
+	}
+
+	switch {
+	case len(node.children) == 0:
+		b.WriteString("/>\n")
+	case len(node.children) == 1 && node.children[0].isText:
+		b.WriteString(">" + escapeXML(node.children[0].text) + "</" + tag + ">\n")
+	default:
+		b.WriteString(">\n")
+		for _, child := range node.children {
+			writeXMLNode(b, child, depth+1)
+		}
+		b.WriteString(indent + "</" + tag + ">\n")
+	}
+}
+
+// xmlName renders a name with its namespace prefix, if any
+func xmlName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
+
+// xmlEscaper escapes text or an attribute value for XML. Unlike
+// xml.EscapeText it keeps line breaks, which read better in a viewer.
+var xmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+// escapeXML escapes text or an attribute value for XML
+func escapeXML(text string) string {
+	return xmlEscaper.Replace(text)
+}
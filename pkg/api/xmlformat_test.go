@@ -0,0 +1,48 @@
+package api
+
+import "testing"
+
+func TestPrettyPrintXML(t *testing.T) {
+	soap := `<?xml version="1.0" encoding="UTF-8"?><soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><!-- result --><m:Price xmlns:m="urn:prices" currency="EUR">1 &lt; 2</m:Price><m:Empty/></soap:Body></soap:Envelope>`
+	soapWant := `<?xml version="1.0" encoding="UTF-8"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body>
+    <!-- result -->
+    <m:Price xmlns:m="urn:prices" currency="EUR">1 &lt; 2</m:Price>
+    <m:Empty/>
+  </soap:Body>
+</soap:Envelope>`
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        string
+	}{
+		{"SOAP by declaration", "", soap, soapWant},
+		{"by content type", "application/xml", "<a>\n  <b>x</b>  <c/>\n</a>", "<a>\n  <b>x</b>\n  <c/>\n</a>"},
+		{"text/xml with charset", "text/xml; charset=utf-8", "<a><b>x</b></a>", "<a>\n  <b>x</b>\n</a>"},
+		{"+xml type", "application/atom+xml", "<feed><title>t</title></feed>", "<feed>\n  <title>t</title>\n</feed>"},
+		{"mismatched tags", "application/xml", "<a><b></a></b>", "<a><b></a></b>"},
+		{"unclosed", "application/xml", "<a><b>x</b>", "<a><b>x</b>"},
+		{"not XML", "text/plain", "<a><b>x</b></a>", "<a><b>x</b></a>"},
+		{"empty", "application/xml", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := &Response{Headers: map[string]string{}, Body: tt.body}
+			if tt.contentType != "" {
+				response.Headers["Content-Type"] = tt.contentType
+			}
+
+			got, err := response.PrettyPrintXML()
+			if err != nil {
+				t.Fatalf("PrettyPrintXML() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("PrettyPrintXML() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,322 @@
+package collections
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"onioncli/pkg/api"
+)
+
+// TestResult is the outcome of one assertion
+type TestResult struct {
+	Test    string `json:"test"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message,omitempty"` // why it failed, or why it could not be run
+}
+
+// RunTests checks the request's tests against resp, in order. Each test is
+// one assertion; blank ones and ones starting with # are skipped.
+//
+//	status == 200                    status code; ==, !=, <, <=, >, >=
+//	duration < 5s                    time taken, as a Go duration; same operators
+//	header Content-Type contains json
+//	                                 header, by case-insensitive name; exists, ==, !=, contains
+//	jsonpath $.data.id exists        value in a JSON body; exists, ==, !=, contains
+//
+// The value is the rest of the line, so it may contain spaces; a double-quoted
+// value is unquoted first. JSONPath supports $ followed by .key, ['key'] and
+// [index] steps. A JSON string is compared by its text, anything else by its
+// encoding, e.g. 42, true or null.
+func RunTests(req *CollectionRequest, resp *api.Response) []TestResult {
+	if req == nil || resp == nil {
+		return nil
+	}
+
+	var results []TestResult
+	for _, test := range req.Tests {
+		test = strings.TrimSpace(test)
+		if test == "" || strings.HasPrefix(test, "#") {
+			continue
+		}
+		results = append(results, runTest(test, resp))
+	}
+	return results
+}
+
+// runTest checks a single assertion
+func runTest(test string, resp *api.Response) TestResult {
+	subject, rest := cutField(test)
+
+	var passed bool
+	var got string
+	var err error
+	switch strings.ToLower(subject) {
+	case "status":
+		passed, got, err = checkStatus(rest, resp)
+	case "duration":
+		passed, got, err = checkDuration(rest, resp)
+	case "header":
+		passed, got, err = checkHeader(rest, resp)
+	case "jsonpath":
+		passed, got, err = checkJSONPath(rest, resp)
+	default:
+		err = fmt.Errorf("unknown check %q, expected status, duration, header or jsonpath", subject)
+	}
+
+	result := TestResult{Test: test, Passed: passed && err == nil}
+	switch {
+	case err != nil:
+		result.Message = "invalid test: " + err.Error()
+	case !passed:
+		result.Message = "got " + got
+	}
+	return result
+}
+
+// checkStatus compares the status code, e.g. "== 200"
+func checkStatus(rest string, resp *api.Response) (bool, string, error) {
+	op, value := cutField(rest)
+	want, err := strconv.Atoi(value)
+	if err != nil {
+		return false, "", fmt.Errorf("status code %q is not a number", value)
+	}
+	passed, err := compareOrdered(op, resp.StatusCode, want)
+	return passed, strconv.Itoa(resp.StatusCode), err
+}
+
+// checkDuration compares how long the request took, e.g. "< 5s"
+func checkDuration(rest string, resp *api.Response) (bool, string, error) {
+	op, value := cutField(rest)
+	want, err := time.ParseDuration(value)
+	if err != nil {
+		return false, "", fmt.Errorf("duration %q is not valid, e.g. 500ms or 5s", value)
+	}
+	passed, err := compareOrdered(op, resp.Duration, want)
+	return passed, resp.Duration.Round(time.Millisecond).String(), err
+}
+
+// checkHeader matches a response header, e.g. "Content-Type contains json"
+func checkHeader(rest string, resp *api.Response) (bool, string, error) {
+	name, rest := cutField(rest)
+	if name == "" {
+		return false, "", fmt.Errorf("missing header name")
+	}
+
+	var got string
+	present := false
+	for key, value := range resp.Headers {
+		if strings.EqualFold(key, name) {
+			got, present = value, true
+			break
+		}
+	}
+
+	op, want := cutField(rest)
+	passed, err := matchText(op, got, present, want)
+	if !present {
+		got = "(not set)"
+	}
+	return passed, got, err
+}
+
+// checkJSONPath matches a value in a JSON body, e.g. "$.data.id exists"
+func checkJSONPath(rest string, resp *api.Response) (bool, string, error) {
+	path, rest := cutJSONPath(rest)
+	steps, err := parseJSONPath(path)
+	if err != nil {
+		return false, "", err
+	}
+	op, want := cutField(rest)
+
+	decoder := json.NewDecoder(strings.NewReader(resp.Body))
+	decoder.UseNumber() // keep large IDs exact
+	var data interface{}
+	if err := decoder.Decode(&data); err != nil {
+		// Still report a malformed operator rather than blaming the body
+		if _, err := matchText(op, "", false, want); err != nil {
+			return false, "", err
+		}
+		return false, "a body that is not JSON", nil
+	}
+
+	value, present := lookupJSONPath(data, steps)
+	got := ""
+	if present {
+		got = jsonText(value)
+	}
+	passed, err := matchText(op, got, present, want)
+	if !present {
+		got = "(no value)"
+	}
+	return passed, got, err
+}
+
+// compareOrdered applies a comparison operator
+func compareOrdered[T cmp.Ordered](op string, got, want T) (bool, error) {
+	switch op {
+	case "==":
+		return got == want, nil
+	case "!=":
+		return got != want, nil
+	case "<":
+		return got < want, nil
+	case "<=":
+		return got <= want, nil
+	case ">":
+		return got > want, nil
+	case ">=":
+		return got >= want, nil
+	}
+	return false, fmt.Errorf("unknown operator %q, expected ==, !=, <, <=, > or >=", op)
+}
+
+// matchText applies a text operator to a value that may be missing
+func matchText(op, got string, present bool, want string) (bool, error) {
+	if op == "exists" {
+		if want != "" {
+			return false, fmt.Errorf("exists takes no value")
+		}
+		return present, nil
+	}
+
+	if unquoted, err := strconv.Unquote(want); err == nil && strings.HasPrefix(want, `"`) {
+		want = unquoted
+	} else if want == "" {
+		return false, fmt.Errorf("missing value after %q", op)
+	}
+
+	switch op {
+	case "==":
+		return present && got == want, nil
+	case "!=":
+		return !present || got != want, nil
+	case "contains":
+		return present && strings.Contains(got, want), nil
+	}
+	return false, fmt.Errorf("unknown operator %q, expected exists, ==, != or contains", op)
+}
+
+// jsonPathStep is one .key, ['key'] or [index] step of a JSONPath
+type jsonPathStep struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parseJSONPath splits a path such as $.items[0]['first name'] into steps
+func parseJSONPath(path string) ([]jsonPathStep, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("JSONPath %q must start with $", path)
+	}
+
+	var steps []jsonPathStep
+	rest := path[1:]
+	for rest != "" {
+		switch {
+		case rest[0] == '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			key := rest[1 : end+1]
+			if key == "" {
+				return nil, fmt.Errorf("JSONPath %q has an empty key", path)
+			}
+			steps = append(steps, jsonPathStep{key: key})
+			rest = rest[end+1:]
+		case strings.HasPrefix(rest, "['") || strings.HasPrefix(rest, `["`):
+			end := strings.Index(rest[2:], string(rest[1])+"]")
+			if end < 0 {
+				return nil, fmt.Errorf("JSONPath %q has an unclosed key", path)
+			}
+			steps = append(steps, jsonPathStep{key: rest[2 : end+2]})
+			rest = rest[end+4:]
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("JSONPath %q has an unclosed index", path)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("JSONPath %q has an invalid index %q", path, rest[1:end])
+			}
+			steps = append(steps, jsonPathStep{index: index, isIndex: true})
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("JSONPath %q is not valid at %q", path, rest)
+		}
+	}
+	return steps, nil
+}
+
+// lookupJSONPath follows steps into decoded JSON, reporting whether a value
+// is there
+func lookupJSONPath(data interface{}, steps []jsonPathStep) (interface{}, bool) {
+	current := data
+	for _, step := range steps {
+		if step.isIndex {
+			items, ok := current.([]interface{})
+			if !ok || step.index >= len(items) {
+				return nil, false
+			}
+			current = items[step.index]
+			continue
+		}
+
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = object[step.key]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// jsonText is how a JSON value is compared: a string by its text, anything
+// else by its encoding
+func jsonText(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
+
+// cutField splits s into its first whitespace-separated field and the
+// trimmed rest
+func cutField(s string) (string, string) {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexAny(s, " \t"); i >= 0 {
+		return s[:i], strings.TrimSpace(s[i:])
+	}
+	return s, ""
+}
+
+// cutJSONPath is cutField for a leading JSONPath, whose quoted keys may
+// contain spaces
+func cutJSONPath(s string) (string, string) {
+	s = strings.TrimSpace(s)
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == ' ' || c == '\t':
+			return s[:i], strings.TrimSpace(s[i:])
+		}
+	}
+	return s, ""
+}
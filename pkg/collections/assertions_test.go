@@ -0,0 +1,86 @@
+package collections
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"onioncli/pkg/api"
+)
+
+func TestRunTests(t *testing.T) {
+	resp := &api.Response{
+		StatusCode: 201,
+		Status:     "201 Created",
+		Headers:    map[string]string{"Content-Type": "application/json; charset=utf-8"},
+		Body:       `{"data": {"id": 12345678901234567890, "name": "alice", "tags": ["a", "b"], "first name": "Al", "deleted": null}}`,
+		Duration:   1200 * time.Millisecond,
+	}
+
+	tests := []struct {
+		test        string
+		wantPassed  bool
+		wantMessage string
+	}{
+		{"status == 201", true, ""},
+		{"status < 300", true, ""},
+		{"status == 200", false, "got 201"},
+		{"duration < 5s", true, ""},
+		{"duration <= 1s", false, "got 1.2s"},
+		{"header content-type contains application/json", true, ""},
+		{"header Content-Type == application/json", false, "got application/json; charset=utf-8"},
+		{"header X-Request-Id exists", false, "got (not set)"},
+		{"header X-Request-Id != abc", true, ""},
+		{"jsonpath $.data.id exists", true, ""},
+		{"jsonpath $.data.id == 12345678901234567890", true, ""},
+		{`jsonpath $.data.name == "alice"`, true, ""},
+		{"jsonpath $.data.name == alice", true, ""},
+		{"jsonpath $.data.tags[1] == b", true, ""},
+		{"jsonpath $.data.tags[2] exists", false, "got (no value)"},
+		{"jsonpath $.data['first name'] == Al", true, ""},
+		{"jsonpath $.data.deleted == null", true, ""},
+		{"jsonpath $.data.tags contains \"a\"", true, ""},
+		{"jsonpath $.missing.id exists", false, "got (no value)"},
+		{"status = 201", false, "invalid test: unknown operator"},
+		{"status == ok", false, "invalid test: status code"},
+		{"duration < soon", false, "invalid test: duration"},
+		{"header Content-Type exists json", false, "invalid test: exists takes no value"},
+		{"jsonpath data.id exists", false, "invalid test: JSONPath \"data.id\" must start with $"},
+		{"jsonpath $.data[x] exists", false, "invalid test: JSONPath \"$.data[x]\" has an invalid index"},
+		{"jsonpath $.data.name ==", false, "invalid test: missing value"},
+		{"body contains alice", false, "invalid test: unknown check"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.test, func(t *testing.T) {
+			results := RunTests(&CollectionRequest{Tests: []string{tt.test}}, resp)
+			if len(results) != 1 {
+				t.Fatalf("Expected 1 result, got %d", len(results))
+			}
+			got := results[0]
+			if got.Test != tt.test || got.Passed != tt.wantPassed || !strings.HasPrefix(got.Message, tt.wantMessage) {
+				t.Errorf("RunTests() = %+v, want passed %v with message %q", got, tt.wantPassed, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestRunTestsSkipsBlankAndComments(t *testing.T) {
+	req := &CollectionRequest{Tests: []string{"", "  # status == 500", " status == 200 "}}
+	results := RunTests(req, &api.Response{StatusCode: 200})
+	if len(results) != 1 || results[0].Test != "status == 200" || !results[0].Passed {
+		t.Errorf("Unexpected results: %+v", results)
+	}
+
+	if results := RunTests(req, nil); results != nil {
+		t.Errorf("Expected no results without a response, got %+v", results)
+	}
+}
+
+func TestRunTestsNonJSONBody(t *testing.T) {
+	req := &CollectionRequest{Tests: []string{"jsonpath $.id exists"}}
+	results := RunTests(req, &api.Response{StatusCode: 200, Body: "<html></html>"})
+	if len(results) != 1 || results[0].Passed || results[0].Message != "got a body that is not JSON" {
+		t.Errorf("Unexpected results: %+v", results)
+	}
+}
@@ -0,0 +1,128 @@
+package collections
+
+import (
+	"fmt"
+	"sort"
+)
+
+// VariableAudit reports how each environment's variables are used by the
+// requests in all collections
+type VariableAudit struct {
+	// References maps each referenced variable to the requests using it, as "Collection / Request"
+	References   map[string][]string
+	Environments []EnvironmentAudit
+}
+
+// EnvironmentAudit is the variable usage for one environment
+type EnvironmentAudit struct {
+	EnvironmentID   string
+	EnvironmentName string
+	Referenced      []string // defined and referenced
+	Unused          []string // defined but never referenced
+	Undefined       []string // referenced but not defined
+}
+
+// AuditVariables scans every collection request for {{var}} references and
+// compares them with each environment's variables. Variables referenced by
+// other variables in the same environment (e.g. {{host}} inside base_url)
+// count as used, as does base_url when any request has a relative URL.
+func (m *Manager) AuditVariables() VariableAudit {
+	audit := VariableAudit{References: make(map[string][]string)}
+
+	usesBaseURL := false
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, collection := range m.collections {
+		for _, req := range collection.Requests {
+			location := collection.Name + " / " + req.Name
+			for _, name := range requestVariables(req) {
+				audit.References[name] = append(audit.References[name], location)
+			}
+			if isRelativeURL(req.URL) {
+				usesBaseURL = true
+			}
+		}
+	}
+
+	for _, env := range m.environments {
+		used := make(map[string]bool)
+		for name := range audit.References {
+			used[name] = true
+		}
+		if usesBaseURL {
+			used["base_url"] = true
+		}
+		for _, name := range environmentVariableReferences(env) {
+			used[name] = true
+		}
+
+		envAudit := EnvironmentAudit{EnvironmentID: env.ID, EnvironmentName: env.Name}
+		for name := range env.Variables {
+			if used[name] {
+				envAudit.Referenced = append(envAudit.Referenced, name)
+			} else {
+				envAudit.Unused = append(envAudit.Unused, name)
+			}
+		}
+		for name := range audit.References {
+			if _, ok := env.Variables[name]; !ok {
+				envAudit.Undefined = append(envAudit.Undefined, name)
+			}
+		}
+		if usesBaseURL {
+			if _, ok := env.Variables["base_url"]; !ok {
+				envAudit.Undefined = append(envAudit.Undefined, "base_url")
+			}
+		}
+
+		sort.Strings(envAudit.Referenced)
+		sort.Strings(envAudit.Unused)
+		sort.Strings(envAudit.Undefined)
+		audit.Environments = append(audit.Environments, envAudit)
+	}
+
+	return audit
+}
+
+// DeleteVariables removes the named variables from an environment
+func (m *Manager) DeleteVariables(envID string, names []string) error {
+	env := m.environment(envID)
+	if env == nil {
+		return fmt.Errorf("environment not found: %s", envID)
+	}
+
+	// The copy's variables are its own, so they can be edited in place
+	for _, name := range names {
+		delete(env.Variables, name)
+	}
+	return m.UpdateEnvironment(env.ID, env.Name, env.Description, env.Variables, env.SecretKeys)
+}
+
+// requestVariables returns the variables a collection request references in
+// its URL, headers, body and auth settings
+func requestVariables(req CollectionRequest) []string {
+	texts := []string{req.URL, req.Body}
+	for key, value := range req.Headers {
+		texts = append(texts, key, value)
+	}
+	if auth := req.Auth; auth != nil {
+		texts = append(texts, auth.APIKey, auth.KeyName, auth.Token, auth.Username, auth.Password)
+		for key, value := range auth.Custom {
+			texts = append(texts, key, value)
+		}
+	}
+
+	names := ReferencedVariables(texts...)
+	sort.Strings(names)
+	return names
+}
+
+// environmentVariableReferences returns variables referenced from within an
+// environment's own values
+func environmentVariableReferences(env Environment) []string {
+	values := make([]string, 0, len(env.Variables))
+	for _, value := range env.Variables {
+		values = append(values, value)
+	}
+	return ReferencedVariables(values...)
+}
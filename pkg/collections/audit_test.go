@@ -0,0 +1,95 @@
+package collections
+
+import (
+	"reflect"
+	"testing"
+
+	"onioncli/pkg/api"
+)
+
+func TestRequestVariables(t *testing.T) {
+	req := CollectionRequest{
+		URL:     "{{base_url}}/users/{{user_id}}",
+		Headers: map[string]string{"X-{{header_name}}": "Bearer {{token}}"},
+		Body:    `{"page": {{page}}, "id": "{{user_id}}"}`,
+		Auth:    &api.AuthConfig{Type: api.AuthBasic, Username: "{{username}}", Password: "{{password}}"},
+	}
+
+	got := requestVariables(req)
+	expected := []string{"base_url", "header_name", "page", "password", "token", "user_id", "username"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestAuditVariables(t *testing.T) {
+	manager := newTestManager(t)
+
+	collection := manager.CreateCollection("API", "")
+	requests := []*api.Request{
+		api.NewRequest("GET", "/users/{{user_id}}"),
+		api.NewRequest("POST", "{{api_url}}/login"),
+	}
+	requests[1].Headers["Authorization"] = "Bearer {{token}}"
+	for _, req := range requests {
+		if err := manager.AddRequestToCollection(collection.ID, req, req.URL, ""); err != nil {
+			t.Fatalf("Failed to add request: %v", err)
+		}
+	}
+
+	env := createTestEnvironment(t, manager, "Audit", "", map[string]string{
+		"base_url": "http://{{host}}",
+		"host":     "example.onion",
+		"api_url":  "http://api.onion",
+		"user_id":  "42",
+		"stale":    "old",
+	})
+
+	audit := manager.AuditVariables()
+
+	if got := audit.References["token"]; len(got) != 1 || got[0] != "API / {{api_url}}/login" {
+		t.Errorf("Expected token to be referenced by the login request, got %v", got)
+	}
+
+	var envAudit *EnvironmentAudit
+	for i := range audit.Environments {
+		if audit.Environments[i].EnvironmentID == env.ID {
+			envAudit = &audit.Environments[i]
+		}
+	}
+	if envAudit == nil {
+		t.Fatalf("Expected an audit for environment %s", env.ID)
+	}
+
+	if expected := []string{"api_url", "base_url", "host", "user_id"}; !reflect.DeepEqual(envAudit.Referenced, expected) {
+		t.Errorf("Expected referenced %v, got %v", expected, envAudit.Referenced)
+	}
+	if expected := []string{"stale"}; !reflect.DeepEqual(envAudit.Unused, expected) {
+		t.Errorf("Expected unused %v, got %v", expected, envAudit.Unused)
+	}
+	if expected := []string{"token"}; !reflect.DeepEqual(envAudit.Undefined, expected) {
+		t.Errorf("Expected undefined %v, got %v", expected, envAudit.Undefined)
+	}
+}
+
+func TestDeleteVariables(t *testing.T) {
+	manager := newTestManager(t)
+	env := createTestEnvironment(t, manager, "Cleanup", "", map[string]string{"keep": "1", "drop": "2"})
+
+	if err := manager.DeleteVariables(env.ID, []string{"drop"}); err != nil {
+		t.Fatalf("Failed to delete variables: %v", err)
+	}
+
+	for _, e := range manager.GetEnvironments() {
+		if e.ID != env.ID {
+			continue
+		}
+		if expected := map[string]string{"keep": "1"}; !reflect.DeepEqual(e.Variables, expected) {
+			t.Errorf("Expected variables %v, got %v", expected, e.Variables)
+		}
+	}
+
+	if err := manager.DeleteVariables("missing", []string{"keep"}); err == nil {
+		t.Error("Expected an error for an unknown environment")
+	}
+}
@@ -0,0 +1,221 @@
+package collections
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"onioncli/pkg/api"
+)
+
+// BundleFormat identifies OnionCLI collection bundle files
+const BundleFormat = "onioncli-bundle"
+
+// Bundle is a self-contained, shareable package of one collection and the
+// environment needed to run it
+type Bundle struct {
+	Format      string       `json:"format"`
+	Version     int          `json:"version"`
+	ExportedAt  time.Time    `json:"exported_at"`
+	Collection  Collection   `json:"collection"`
+	Environment *Environment `json:"environment,omitempty"`
+}
+
+// ExportBundle writes a collection plus an environment to a single JSON file.
+// If envID is empty the active environment is used. Unless includeSecrets is set,
+// secret-looking variables, header values, and auth credentials are removed;
+// with it, secret variables are read from the keyring into the bundle.
+func (m *Manager) ExportBundle(collectionID, envID, filename string, includeSecrets bool) error {
+	collection, err := m.GetCollection(collectionID)
+	if err != nil {
+		return err
+	}
+
+	var env *Environment
+	if envID != "" {
+		if env = m.environment(envID); env == nil {
+			return fmt.Errorf("environment not found: %s", envID)
+		}
+	} else {
+		env = m.GetActiveEnvironment()
+	}
+
+	bundle := Bundle{
+		Format:     BundleFormat,
+		Version:    1,
+		ExportedAt: time.Now(),
+		Collection: copyCollection(collection),
+	}
+	if env != nil {
+		envCopy := copyEnvironment(env)
+		// The auth's secrets are references into this machine's keyring,
+		// which mean nothing elsewhere, so it is left out
+		envCopy.Auth = nil
+		if includeSecrets {
+			if envCopy.Variables, err = m.EnvironmentVariables(env); err != nil {
+				return err
+			}
+		}
+		bundle.Environment = &envCopy
+	}
+
+	if !includeSecrets {
+		sanitizeBundle(&bundle)
+	}
+
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal bundle: %w", err)
+	}
+
+	return os.WriteFile(filename, data, 0600)
+}
+
+// ImportBundle imports a bundle file, restoring its collection and environment
+// with fresh IDs. The imported environment is not activated.
+func (m *Manager) ImportBundle(filename string) (*Collection, *Environment, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+
+	var bundle Bundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse bundle: %w", err)
+	}
+
+	return m.importBundle(&bundle)
+}
+
+// importBundle stores the contents of a parsed bundle
+func (m *Manager) importBundle(bundle *Bundle) (*Collection, *Environment, error) {
+	if bundle.Format != BundleFormat {
+		return nil, nil, fmt.Errorf("not an OnionCLI bundle (format %q)", bundle.Format)
+	}
+
+	collection := copyCollection(&bundle.Collection)
+	collection.ID = generateID()
+	collection.CreatedAt = time.Now()
+	collection.UpdatedAt = time.Now()
+	for i := range collection.Requests {
+		collection.Requests[i].ID = generateID()
+	}
+
+	stored, err := m.addImportedCollection(&collection)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var env *Environment
+	if bundle.Environment != nil {
+		variables := make(map[string]string)
+		for k, v := range bundle.Environment.Variables {
+			variables[k] = v
+		}
+		env, err = m.CreateEnvironment(bundle.Environment.Name, bundle.Environment.Description, variables, bundle.Environment.SecretKeys)
+		if err != nil {
+			return stored, nil, err
+		}
+	}
+
+	return stored, env, nil
+}
+
+// isBundle reports whether raw JSON data looks like a bundle file
+func isBundle(data []byte) bool {
+	var probe struct {
+		Format string `json:"format"`
+	}
+	return json.Unmarshal(data, &probe) == nil && probe.Format == BundleFormat
+}
+
+// sanitizeBundle removes secrets from a bundle in place
+func sanitizeBundle(bundle *Bundle) {
+	bundle.Collection.Auth = api.StripSecrets(bundle.Collection.Auth)
+	for key := range bundle.Collection.Variables {
+		if api.IsSensitiveName(key) {
+			bundle.Collection.Variables[key] = ""
+		}
+	}
+
+	sanitizeHeaders(bundle.Collection.DefaultHeaders)
+
+	for i := range bundle.Collection.Requests {
+		req := &bundle.Collection.Requests[i]
+		req.Auth = api.StripSecrets(req.Auth)
+		sanitizeHeaders(req.Headers)
+	}
+
+	if bundle.Environment != nil {
+		for key := range bundle.Environment.Variables {
+			if api.IsSensitiveName(key) {
+				bundle.Environment.Variables[key] = ""
+			}
+		}
+	}
+}
+
+// sanitizeHeaders blanks secret-looking header values in place
+func sanitizeHeaders(headers map[string]string) {
+	for key, value := range headers {
+		// Keep templated values like "Bearer {{token}}" - they hold no secret themselves
+		if api.IsSensitiveName(key) && !strings.Contains(value, "{{") {
+			headers[key] = ""
+		}
+	}
+}
+
+// copyCollection returns a deep copy of a collection
+func copyCollection(collection *Collection) Collection {
+	c := *collection
+
+	c.Variables = make(map[string]string)
+	for k, v := range collection.Variables {
+		c.Variables[k] = v
+	}
+
+	if collection.DefaultHeaders != nil {
+		c.DefaultHeaders = make(map[string]string, len(collection.DefaultHeaders))
+		for k, v := range collection.DefaultHeaders {
+			c.DefaultHeaders[k] = v
+		}
+	}
+
+	c.Requests = make([]CollectionRequest, len(collection.Requests))
+	for i, req := range collection.Requests {
+		reqCopy := req
+		reqCopy.Headers = make(map[string]string)
+		for k, v := range req.Headers {
+			reqCopy.Headers[k] = v
+		}
+		if req.Auth != nil {
+			authCopy := *req.Auth
+			reqCopy.Auth = &authCopy
+		}
+		c.Requests[i] = reqCopy
+	}
+
+	if collection.Auth != nil {
+		authCopy := *collection.Auth
+		c.Auth = &authCopy
+	}
+
+	return c
+}
+
+// copyEnvironment returns a deep copy of an environment
+func copyEnvironment(env *Environment) Environment {
+	e := *env
+	e.Variables = make(map[string]string)
+	for k, v := range env.Variables {
+		e.Variables[k] = v
+	}
+	e.SecretKeys = append([]string(nil), env.SecretKeys...)
+	if env.Auth != nil {
+		authCopy := *env.Auth
+		e.Auth = &authCopy
+	}
+	return e
+}
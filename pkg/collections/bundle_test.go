@@ -0,0 +1,113 @@
+package collections
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"onioncli/pkg/api"
+)
+
+func TestBundleRoundTrip(t *testing.T) {
+	manager := newTestManager(t)
+
+	collection := manager.CreateCollection("Users API", "User endpoints")
+	req := api.NewRequest("POST", "{{base_url}}/users")
+	req.SetHeader("Authorization", "Bearer {{api_token}}")
+	req.SetHeader("X-Api-Key", "hardcoded-secret")
+	req.SetBody(`{"name": "alice"}`)
+	if err := manager.AddRequestToCollection(collection.ID, req, "Create user", ""); err != nil {
+		t.Fatalf("Failed to add request: %v", err)
+	}
+
+	env := createTestEnvironment(t, manager, "Staging", "Staging onion", map[string]string{
+		"base_url":  "http://staging.onion",
+		"api_token": "super-secret",
+	})
+	if err := manager.SetEnvironmentAuth(env.ID, &api.AuthConfig{Type: api.AuthBearer, Token: "keyring:token"}); err != nil {
+		t.Fatalf("SetEnvironmentAuth failed: %v", err)
+	}
+
+	filename := filepath.Join(t.TempDir(), "bundle.json")
+	if err := manager.ExportBundle(collection.ID, env.ID, filename, false); err != nil {
+		t.Fatalf("ExportBundle failed: %v", err)
+	}
+	if info, err := os.Stat(filename); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Bundle file mode = %v, %v, want 0600", info.Mode().Perm(), err)
+	}
+
+	imported, importedEnv, err := manager.ImportBundle(filename)
+	if err != nil {
+		t.Fatalf("ImportBundle failed: %v", err)
+	}
+
+	if imported.ID == collection.ID {
+		t.Error("Expected imported collection to get a fresh ID")
+	}
+	if imported.Name != "Users API" || len(imported.Requests) != 1 {
+		t.Fatalf("Unexpected imported collection: %+v", imported)
+	}
+
+	importedReq := imported.Requests[0]
+	if importedReq.Body != `{"name": "alice"}` || importedReq.URL != "{{base_url}}/users" {
+		t.Errorf("Request was not preserved: %+v", importedReq)
+	}
+	if importedReq.Headers["Authorization"] != "Bearer {{api_token}}" {
+		t.Errorf("Expected templated auth header to be kept, got %q", importedReq.Headers["Authorization"])
+	}
+	if importedReq.Headers["X-Api-Key"] != "" {
+		t.Errorf("Expected hardcoded secret header to be removed, got %q", importedReq.Headers["X-Api-Key"])
+	}
+
+	if importedEnv == nil {
+		t.Fatal("Expected environment to be imported")
+	}
+	if importedEnv.ID == env.ID || importedEnv.IsActive {
+		t.Errorf("Expected a fresh, inactive environment, got %+v", importedEnv)
+	}
+	if importedEnv.Variables["base_url"] != "http://staging.onion" {
+		t.Errorf("Expected base_url to be kept, got %q", importedEnv.Variables["base_url"])
+	}
+	if importedEnv.Variables["api_token"] != "" {
+		t.Errorf("Expected secret variable to be excluded, got %q", importedEnv.Variables["api_token"])
+	}
+
+	// Secrets are kept when explicitly requested
+	if err := manager.ExportBundle(collection.ID, env.ID, filename, true); err != nil {
+		t.Fatalf("ExportBundle with secrets failed: %v", err)
+	}
+	_, withSecrets, err := manager.ImportBundle(filename)
+	if err != nil {
+		t.Fatalf("ImportBundle failed: %v", err)
+	}
+	if withSecrets.Variables["api_token"] != "super-secret" {
+		t.Errorf("Expected secret to be included, got %q", withSecrets.Variables["api_token"])
+	}
+	if withSecrets.Auth != nil {
+		t.Errorf("Expected environment auth to stay behind with its keyring, got %+v", withSecrets.Auth)
+	}
+}
+
+func TestImportCollectionReportsBundleEnvironment(t *testing.T) {
+	manager := newTestManager(t)
+	collection := manager.CreateCollection("Users API", "")
+	env := createTestEnvironment(t, manager, "Staging", "", map[string]string{"base_url": "http://staging.onion"})
+
+	filename := filepath.Join(t.TempDir(), "bundle.json")
+	if err := manager.ExportBundle(collection.ID, env.ID, filename, false); err != nil {
+		t.Fatalf("ExportBundle failed: %v", err)
+	}
+
+	before := len(manager.GetEnvironments())
+	imported, err := manager.ImportCollection(context.Background(), filename, nil)
+	if err != nil {
+		t.Fatalf("ImportCollection failed: %v", err)
+	}
+	if imported.ImportEnvironment != "Staging" {
+		t.Errorf("ImportEnvironment = %q, want %q", imported.ImportEnvironment, "Staging")
+	}
+	if got := len(manager.GetEnvironments()); got != before+1 {
+		t.Errorf("Expected the bundle's environment to be created, got %d environments, had %d", got, before)
+	}
+}
@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"onioncli/pkg/api"
@@ -19,22 +22,33 @@ type Collection struct {
 	Requests    []CollectionRequest `json:"requests"`
 	Variables   map[string]string   `json:"variables"`
 	Auth        *api.AuthConfig     `json:"auth,omitempty"`
-	CreatedAt   time.Time           `json:"created_at"`
-	UpdatedAt   time.Time           `json:"updated_at"`
+	// DefaultHeaders are sent with every request in the collection unless
+	// the request sets the same header itself
+	DefaultHeaders map[string]string `json:"default_headers,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+	// ImportSkipped is how many malformed items the import that created
+	// the collection left out, for reporting; it isn't saved
+	ImportSkipped int `json:"-"`
+	// ImportEnvironment names the environment a bundle import created
+	// alongside the collection, for reporting; it isn't saved
+	ImportEnvironment string `json:"-"`
 }
 
 // CollectionRequest represents a request within a collection
 type CollectionRequest struct {
-	ID          string            `json:"id"`
-	Name        string            `json:"name"`
-	Description string            `json:"description"`
-	Method      string            `json:"method"`
-	URL         string            `json:"url"`
-	Headers     map[string]string `json:"headers"`
-	Body        string            `json:"body"`
-	Auth        *api.AuthConfig   `json:"auth,omitempty"`
-	Tests       []string          `json:"tests,omitempty"`
-	CreatedAt   time.Time         `json:"created_at"`
+	ID          string                `json:"id"`
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	Method      string                `json:"method"`
+	URL         string                `json:"url"`
+	Headers     map[string]string     `json:"headers"`
+	Body        string                `json:"body"`
+	Auth        *api.AuthConfig       `json:"auth,omitempty"`
+	Tests       []string              `json:"tests,omitempty"`
+	PostProcess []api.PostProcessStep `json:"post_process,omitempty"` // applied to the response body, in order
+	Extract     map[string]string     `json:"extract,omitempty"`      // variable name → JSONPath, set for later requests of a sequence run
+	CreatedAt   time.Time             `json:"created_at"`
 }
 
 // Environment represents a set of variables for different contexts
@@ -43,6 +57,8 @@ type Environment struct {
 	Name        string            `json:"name"`
 	Description string            `json:"description"`
 	Variables   map[string]string `json:"variables"`
+	SecretKeys  []string          `json:"secret_keys,omitempty"` // variables whose values are kept in the system keyring, empty in Variables
+	Auth        *api.AuthConfig   `json:"auth,omitempty"`        // secrets are keyring references, see api.AuthManager.StoreSecrets
 	IsActive    bool              `json:"is_active"`
 	CreatedAt   time.Time         `json:"created_at"`
 	UpdatedAt   time.Time         `json:"updated_at"`
@@ -50,11 +66,16 @@ type Environment struct {
 
 // Manager handles collections and environments
 type Manager struct {
+	mu             sync.RWMutex // guards collections, environments and activeEnv, reached from import and run goroutines
 	collections    []Collection
 	environments   []Environment
 	activeEnv      *Environment
 	collectionsDir string
+	snippetsDir    string
 	envFile        string
+	sessionFile    string
+	draftFile      string
+	secrets        *api.AuthManager // keeps secret variable values in the system keyring
 }
 
 // NewManager creates a new collections manager
@@ -66,18 +87,28 @@ func NewManager() (*Manager, error) {
 
 	configDir := filepath.Join(homeDir, ".onioncli")
 	collectionsDir := filepath.Join(configDir, "collections")
+	snippetsDir := filepath.Join(configDir, "snippets")
 	envFile := filepath.Join(configDir, "environments.json")
+	sessionFile := filepath.Join(configDir, "session.json")
+	draftFile := filepath.Join(configDir, "draft.json")
 
 	// Create directories
 	if err := os.MkdirAll(collectionsDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create collections directory: %w", err)
 	}
+	if err := os.MkdirAll(snippetsDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create snippets directory: %w", err)
+	}
 
 	manager := &Manager{
 		collections:    make([]Collection, 0),
 		environments:   make([]Environment, 0),
 		collectionsDir: collectionsDir,
+		snippetsDir:    snippetsDir,
 		envFile:        envFile,
+		sessionFile:    sessionFile,
+		draftFile:      draftFile,
+		secrets:        api.NewAuthManager(),
 	}
 
 	// Load existing data
@@ -106,14 +137,6 @@ func NewManager() (*Manager, error) {
 		manager.environments = append(manager.environments, defaultEnv)
 		manager.activeEnv = &manager.environments[0]
 		manager.SaveEnvironments()
-	} else {
-		// Find active environment
-		for i := range manager.environments {
-			if manager.environments[i].IsActive {
-				manager.activeEnv = &manager.environments[i]
-				break
-			}
-		}
 	}
 
 	return manager, nil
@@ -131,13 +154,19 @@ func (m *Manager) CreateCollection(name, description string) *Collection {
 		UpdatedAt:   time.Now(),
 	}
 
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.collections = append(m.collections, collection)
 	m.SaveCollection(&collection)
-	return &m.collections[len(m.collections)-1]
+	stored := copyCollection(&collection)
+	return &stored
 }
 
 // AddRequestToCollection adds a request to a collection
 func (m *Manager) AddRequestToCollection(collectionID string, req *api.Request, name, description string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	for i := range m.collections {
 		if m.collections[i].ID == collectionID {
 			collectionReq := CollectionRequest{
@@ -166,11 +195,25 @@ func (m *Manager) AddRequestToCollection(collectionID string, req *api.Request,
 
 // GetCollections returns all collections
 func (m *Manager) GetCollections() []Collection {
-	return m.collections
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.collections)
 }
 
-// GetCollection returns a specific collection
+// GetCollection returns a copy of a specific collection
 func (m *Manager) GetCollection(id string) (*Collection, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	collection, err := m.collection(id)
+	if err != nil {
+		return nil, err
+	}
+	stored := copyCollection(collection)
+	return &stored, nil
+}
+
+// collection finds a collection by ID; the caller holds mu
+func (m *Manager) collection(id string) (*Collection, error) {
 	for i := range m.collections {
 		if m.collections[i].ID == id {
 			return &m.collections[i], nil
@@ -179,8 +222,53 @@ func (m *Manager) GetCollection(id string) (*Collection, error) {
 	return nil, fmt.Errorf("collection not found: %s", id)
 }
 
+// SetCollectionDefaultHeaders replaces the headers sent with every request
+// in a collection; an empty map removes them
+func (m *Manager) SetCollectionDefaultHeaders(id string, headers map[string]string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	collection, err := m.collection(id)
+	if err != nil {
+		return err
+	}
+	if len(headers) == 0 {
+		headers = nil
+	}
+	collection.DefaultHeaders = headers
+	collection.UpdatedAt = time.Now()
+	return m.SaveCollection(collection)
+}
+
+// HeadersFor returns the headers to send with req: the collection's default
+// headers overlaid with the request's own, which win regardless of case.
+// The app-wide default headers are applied later, only where neither sets a
+// header. A nil collection contributes no defaults.
+func (c *Collection) HeadersFor(req *CollectionRequest) map[string]string {
+	var defaults map[string]string
+	if c != nil {
+		defaults = c.DefaultHeaders
+	}
+	headers := make(map[string]string, len(defaults)+len(req.Headers))
+	for name, value := range defaults {
+		headers[name] = value
+	}
+	for name, value := range req.Headers {
+		for existing := range headers {
+			if strings.EqualFold(existing, name) {
+				delete(headers, existing)
+			}
+		}
+		headers[name] = value
+	}
+	return headers
+}
+
 // DeleteCollection deletes a collection
 func (m *Manager) DeleteCollection(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	for i, collection := range m.collections {
 		if collection.ID == id {
 			// Remove from slice
@@ -194,35 +282,106 @@ func (m *Manager) DeleteCollection(id string) error {
 	return fmt.Errorf("collection not found: %s", id)
 }
 
-// CreateEnvironment creates a new environment
-func (m *Manager) CreateEnvironment(name, description string, variables map[string]string) *Environment {
+// CreateEnvironment creates a new environment. The values of the variables
+// named in secretKeys are stored in the system keyring, not on disk.
+func (m *Manager) CreateEnvironment(name, description string, variables map[string]string, secretKeys []string) (*Environment, error) {
+	id := generateID()
+	stored, secretKeys, err := m.storeSecrets(id, variables, secretKeys)
+	if err != nil {
+		return nil, err
+	}
+
 	env := Environment{
-		ID:          generateID(),
+		ID:          id,
 		Name:        name,
 		Description: description,
-		Variables:   variables,
+		Variables:   stored,
+		SecretKeys:  secretKeys,
 		IsActive:    false,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
 
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.environments = append(m.environments, env)
-	m.SaveEnvironments()
-	return &m.environments[len(m.environments)-1]
+	m.refreshActiveEnv()
+	created := copyEnvironment(&env)
+	return &created, m.saveEnvironments()
 }
 
-// GetEnvironments returns all environments
+// refreshActiveEnv re-points activeEnv into the environments slice, which
+// may have been reallocated by an append; the caller holds mu
+func (m *Manager) refreshActiveEnv() {
+	m.activeEnv = nil
+	for i := range m.environments {
+		if m.environments[i].IsActive {
+			m.activeEnv = &m.environments[i]
+			return
+		}
+	}
+}
+
+// GetEnvironments returns copies of all environments
 func (m *Manager) GetEnvironments() []Environment {
-	return m.environments
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	environments := make([]Environment, len(m.environments))
+	for i := range m.environments {
+		environments[i] = copyEnvironment(&m.environments[i])
+	}
+	return environments
 }
 
-// GetActiveEnvironment returns the currently active environment
+// environment returns a copy of the environment with id, or nil
+func (m *Manager) environment(id string) *Environment {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	env := m.findEnvironment(id)
+	if env == nil {
+		return nil
+	}
+	stored := copyEnvironment(env)
+	return &stored
+}
+
+// findEnvironment returns the environment with id, or nil; the caller holds mu
+func (m *Manager) findEnvironment(id string) *Environment {
+	for i := range m.environments {
+		if m.environments[i].ID == id {
+			return &m.environments[i]
+		}
+	}
+	return nil
+}
+
+// activeEnvID returns the active environment's ID, or "" if none is active
+func (m *Manager) activeEnvID() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.activeEnv == nil {
+		return ""
+	}
+	return m.activeEnv.ID
+}
+
+// GetActiveEnvironment returns a copy of the currently active environment,
+// or nil if none is active
 func (m *Manager) GetActiveEnvironment() *Environment {
-	return m.activeEnv
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.activeEnv == nil {
+		return nil
+	}
+	active := copyEnvironment(m.activeEnv)
+	return &active
 }
 
 // SetActiveEnvironment sets the active environment
 func (m *Manager) SetActiveEnvironment(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	// Deactivate all environments
 	for i := range m.environments {
 		m.environments[i].IsActive = false
@@ -233,45 +392,306 @@ func (m *Manager) SetActiveEnvironment(id string) error {
 		if m.environments[i].ID == id {
 			m.environments[i].IsActive = true
 			m.activeEnv = &m.environments[i]
-			return m.SaveEnvironments()
+			return m.saveEnvironments()
 		}
 	}
 
 	return fmt.Errorf("environment not found: %s", id)
 }
 
-// SubstituteVariables replaces variables in a string with environment values
+// UpdateEnvironment updates an environment's name, description, and
+// variables. The values of the variables named in secretKeys are stored in
+// the system keyring; an empty one keeps the secret already stored. Secrets
+// the environment no longer has are deleted from the keyring.
+func (m *Manager) UpdateEnvironment(id, name, description string, variables map[string]string, secretKeys []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.environments {
+		env := &m.environments[i]
+		if env.ID == id {
+			stored, secretKeys, err := m.storeSecrets(id, variables, secretKeys)
+			if err != nil {
+				return err
+			}
+
+			previous := env.SecretKeys
+			env.Name = name
+			env.Description = description
+			env.Variables = stored
+			env.SecretKeys = secretKeys
+			env.UpdatedAt = time.Now()
+			if err := m.saveEnvironments(); err != nil {
+				return err
+			}
+			return m.deleteSecrets(env, previous)
+		}
+	}
+
+	return fmt.Errorf("environment not found: %s", id)
+}
+
+// DeleteEnvironment removes an environment and the keyring entries of its
+// secret variables and auth. The active environment can't be deleted.
+func (m *Manager) DeleteEnvironment(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.environments {
+		env := m.environments[i]
+		if env.ID != id {
+			continue
+		}
+		if env.IsActive {
+			return fmt.Errorf("cannot delete the active environment: %s", env.Name)
+		}
+
+		m.environments = append(m.environments[:i], m.environments[i+1:]...)
+		m.refreshActiveEnv()
+		if err := m.saveEnvironments(); err != nil {
+			return err
+		}
+		secretKeys := env.SecretKeys
+		env.SecretKeys = nil
+		if err := m.deleteSecrets(&env, secretKeys); err != nil {
+			return err
+		}
+		if env.Auth != nil {
+			return m.secrets.DeleteSecrets(secretService(env.ID), env.Auth, nil)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("environment not found: %s", id)
+}
+
+// SetEnvironmentAuth sets the authentication stored with an environment; nil
+// removes it. Secrets must already be replaced by keyring references.
+func (m *Manager) SetEnvironmentAuth(id string, auth *api.AuthConfig) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.environments {
+		if m.environments[i].ID == id {
+			m.environments[i].Auth = auth
+			m.environments[i].UpdatedAt = time.Now()
+			return m.saveEnvironments()
+		}
+	}
+
+	return fmt.Errorf("environment not found: %s", id)
+}
+
+// DuplicateEnvironment clones an environment under a new ID with "(copy)"
+// appended to its name. The copy is inactive and has its own variables map.
+func (m *Manager) DuplicateEnvironment(id string) (*Environment, error) {
+	source := m.environment(id)
+	if source == nil {
+		return nil, fmt.Errorf("environment not found: %s", id)
+	}
+	variables, err := m.EnvironmentVariables(source)
+	if err != nil {
+		return nil, err
+	}
+	return m.CreateEnvironment(source.Name+" (copy)", source.Description, variables, source.SecretKeys)
+}
+
+// SubstituteVariables replaces variables in a string with environment
+// values, then placeholders calling a built-in function such as {{uuid}}
+// with its result (see templateFunctions). A variable takes precedence over
+// a function of the same name, and variables can be used as function
+// arguments, e.g. {{base64 "{{user}}:{{password}}"}}.
 func (m *Manager) SubstituteVariables(input string) string {
-	if m.activeEnv == nil {
+	return m.SubstituteWithEnvironment(input, m.activeEnvID())
+}
+
+// SubstituteWithEnvironment is SubstituteVariables using the variables of the
+// environment with envID, which need not be active. An empty envID
+// substitutes functions only; an unknown one returns input unchanged.
+func (m *Manager) SubstituteWithEnvironment(input string, envID string) string {
+	if envID == "" {
+		return m.substitute(nil, input)
+	}
+	env := m.environment(envID)
+	if env == nil {
 		return input
 	}
+	return m.substitute(env, input)
+}
 
+// substitute replaces env's variables, if env is set, then function calls
+func (m *Manager) substitute(env *Environment, input string) string {
 	result := input
-	for key, value := range m.activeEnv.Variables {
-		placeholder := fmt.Sprintf("{{%s}}", key)
-		result = strings.ReplaceAll(result, placeholder, value)
+	if env != nil {
+		for key, value := range env.Variables {
+			placeholder := fmt.Sprintf("{{%s}}", key)
+			if !strings.Contains(result, placeholder) {
+				continue
+			}
+			if env.IsSecret(key) {
+				secret, err := m.loadSecret(env, key)
+				if err != nil {
+					continue // left unresolved, so it is reported as such
+				}
+				value = secret
+			}
+			result = strings.ReplaceAll(result, placeholder, value)
+		}
 	}
 
-	return result
+	return applyTemplateFunctions(result)
 }
 
 // ProcessRequest processes a request with variable substitution
 func (m *Manager) ProcessRequest(req *api.Request) *api.Request {
+	processedReq, _ := m.ProcessRequestWithWarnings(req)
+	return processedReq
+}
+
+// ProcessRequestWithWarnings processes a request with variable substitution,
+// prefixing relative URLs with the active environment's base_url. Problems that
+// did not stop processing, such as a missing base_url, are returned as warnings.
+func (m *Manager) ProcessRequestWithWarnings(req *api.Request) (*api.Request, []string) {
+	return m.ProcessRequestWithEnvironment(req, m.activeEnvID())
+}
+
+// ProcessRequestWithEnvironment is ProcessRequestWithWarnings using the
+// environment with envID instead of the active one, so a request can be
+// resolved for any environment without switching to it. An empty envID
+// resolves with no environment; an unknown one returns req unchanged, with
+// a warning saying so.
+func (m *Manager) ProcessRequestWithEnvironment(req *api.Request, envID string) (*api.Request, []string) {
+	if envID == "" {
+		return m.processRequest(nil, req)
+	}
+	env := m.environment(envID)
+	if env == nil {
+		return req, []string{fmt.Sprintf("environment not found: %s", envID)}
+	}
+	return m.processRequest(env, req)
+}
+
+// processRequest substitutes env's variables, if env is set, into a copy of req
+func (m *Manager) processRequest(env *Environment, req *api.Request) (*api.Request, []string) {
+	var warnings []string
+
 	processedReq := &api.Request{
 		Method:  req.Method,
-		URL:     m.SubstituteVariables(req.URL),
+		URL:     m.substitute(env, req.URL),
 		Headers: make(map[string]string),
-		Body:    m.SubstituteVariables(req.Body),
+		Body:    m.substitute(env, req.Body),
+
+		BodyFile:       m.substitute(env, req.BodyFile),
+		UploadProgress: req.UploadProgress,
+		Timeout:        req.Timeout,
+	}
+	if req.Multipart != nil {
+		processedReq.Multipart = &api.MultipartBody{
+			Boundary: req.Multipart.Boundary,
+			Fields:   m.substituteMap(env, req.Multipart.Fields),
+			Files:    m.substituteMap(env, req.Multipart.Files),
+		}
+	}
+
+	// Relative URLs like "/api/v1/users" are resolved against base_url
+	if isRelativeURL(processedReq.URL) {
+		baseURL := m.baseURL(env)
+		if baseURL == "" {
+			where := "the active environment"
+			if env != nil && env.ID != m.activeEnvID() {
+				where = env.Name
+			}
+			warnings = append(warnings, fmt.Sprintf("base_url is not set in %s, so relative URL %q cannot be resolved", where, processedReq.URL))
+		} else {
+			processedReq.URL = strings.TrimRight(baseURL, "/") + processedReq.URL
+		}
 	}
 
 	// Process headers
 	for key, value := range req.Headers {
-		processedKey := m.SubstituteVariables(key)
-		processedValue := m.SubstituteVariables(value)
+		processedKey := m.substitute(env, key)
+		processedValue := m.substitute(env, value)
 		processedReq.Headers[processedKey] = processedValue
 	}
 
-	return processedReq
+	return processedReq, warnings
+}
+
+// substituteMap substitutes env's variables in the keys and values of a form map
+func (m *Manager) substituteMap(env *Environment, values map[string]string) map[string]string {
+	if values == nil {
+		return nil
+	}
+	result := make(map[string]string, len(values))
+	for key, value := range values {
+		result[m.substitute(env, key)] = m.substitute(env, value)
+	}
+	return result
+}
+
+// Contains reports whether any collection holds a request that, resolved
+// against the active environment, has the same method, URL and body as req
+func (m *Manager) Contains(req *api.Request) bool {
+	fingerprint := req.Fingerprint()
+	for _, collection := range m.GetCollections() {
+		for i := range collection.Requests {
+			resolved := m.ProcessRequest(collection.Requests[i].ToRequest())
+			if normalizedURL, _, err := api.NormalizeURL(resolved.URL); err == nil {
+				resolved.URL = normalizedURL
+			}
+			if resolved.Fingerprint() == fingerprint {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ResolvePostProcess returns a copy of post-processing steps with {{var}}
+// placeholders in their params substituted, so keys can live in a secret
+// environment variable rather than the collection file
+func (m *Manager) ResolvePostProcess(steps []api.PostProcessStep) []api.PostProcessStep {
+	resolved := make([]api.PostProcessStep, len(steps))
+	for i, step := range steps {
+		resolved[i] = api.PostProcessStep{Processor: step.Processor, Params: make(map[string]string, len(step.Params))}
+		for key, value := range step.Params {
+			resolved[i].Params[key] = m.SubstituteVariables(value)
+		}
+	}
+	return resolved
+}
+
+// baseURL returns env's resolved base_url variable
+func (m *Manager) baseURL(env *Environment) string {
+	if env == nil {
+		return ""
+	}
+	return strings.TrimSpace(m.substitute(env, env.Variables["base_url"]))
+}
+
+// variablePattern matches {{name}} placeholders
+var variablePattern = regexp.MustCompile(`\{\{([^{}]+)\}\}`)
+
+// ReferencedVariables returns the distinct variable names referenced as
+// {{name}} in the given texts, in order of first appearance. Calls to
+// built-in functions such as {{uuid}} are not variables and are left out.
+func ReferencedVariables(texts ...string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, text := range texts {
+		for _, match := range variablePattern.FindAllStringSubmatch(text, -1) {
+			if name := match[1]; !seen[name] && !isTemplateFunction(name) {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// isRelativeURL reports whether a URL is a path with no scheme or host
+func isRelativeURL(rawURL string) bool {
+	return strings.HasPrefix(rawURL, "/") && !strings.HasPrefix(rawURL, "//")
 }
 
 // LoadCollections loads all collections from disk
@@ -281,7 +701,7 @@ func (m *Manager) LoadCollections() error {
 		return err
 	}
 
-	m.collections = make([]Collection, 0)
+	loaded := make([]Collection, 0)
 	for _, file := range files {
 		var collection Collection
 		data, err := os.ReadFile(file)
@@ -293,9 +713,12 @@ func (m *Manager) LoadCollections() error {
 			continue // Skip corrupted files
 		}
 
-		m.collections = append(m.collections, collection)
+		loaded = append(loaded, collection)
 	}
 
+	m.mu.Lock()
+	m.collections = loaded
+	m.mu.Unlock()
 	return nil
 }
 
@@ -320,11 +743,24 @@ func (m *Manager) LoadEnvironments() error {
 		return err
 	}
 
-	return json.Unmarshal(data, &m.environments)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := json.Unmarshal(data, &m.environments); err != nil {
+		return err
+	}
+	m.refreshActiveEnv()
+	return nil
 }
 
 // SaveEnvironments saves environments to disk
 func (m *Manager) SaveEnvironments() error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.saveEnvironments()
+}
+
+// saveEnvironments saves environments to disk; the caller holds mu
+func (m *Manager) saveEnvironments() error {
 	data, err := json.MarshalIndent(m.environments, "", "  ")
 	if err != nil {
 		return err
@@ -350,7 +786,21 @@ func (cr *CollectionRequest) ToRequest() *api.Request {
 	return req
 }
 
-// generateID generates a unique ID
+var (
+	idMu   sync.Mutex
+	lastID int64
+)
+
+// generateID generates a unique ID. IDs are timestamps that are forced to be
+// strictly increasing so that tight loops (e.g. imports) never produce duplicates.
 func generateID() string {
-	return fmt.Sprintf("%d", time.Now().UnixNano())
+	idMu.Lock()
+	defer idMu.Unlock()
+
+	id := time.Now().UnixNano()
+	if id <= lastID {
+		id = lastID + 1
+	}
+	lastID = id
+	return fmt.Sprintf("%d", id)
 }
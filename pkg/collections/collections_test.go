@@ -0,0 +1,416 @@
+package collections
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"onioncli/pkg/api"
+)
+
+// newTestManager creates a manager whose files live in a temporary home directory
+func newTestManager(t *testing.T) *Manager {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	return manager
+}
+
+// editCollection changes the stored collection with id in place, as no
+// Manager method sets every field tests need, and returns a copy of it
+func editCollection(t *testing.T, manager *Manager, id string, edit func(collection *Collection)) *Collection {
+	t.Helper()
+	manager.mu.Lock()
+	collection, err := manager.collection(id)
+	if err == nil {
+		edit(collection)
+	}
+	manager.mu.Unlock()
+	if err != nil {
+		t.Fatalf("Failed to edit collection: %v", err)
+	}
+	stored, _ := manager.GetCollection(id)
+	return stored
+}
+
+func createTestEnvironment(t *testing.T, manager *Manager, name, description string, variables map[string]string) *Environment {
+	t.Helper()
+	env, err := manager.CreateEnvironment(name, description, variables, nil)
+	if err != nil {
+		t.Fatalf("Failed to create environment: %v", err)
+	}
+	return env
+}
+
+func TestProcessRequestBaseURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		baseURL     string
+		url         string
+		expected    string
+		wantWarning bool
+	}{
+		{"relative URL", "http://example.onion", "/api/v1/users", "http://example.onion/api/v1/users", false},
+		{"base_url with trailing slash", "http://example.onion/", "/api/v1/users", "http://example.onion/api/v1/users", false},
+		{"absolute URL untouched", "http://example.onion", "http://other.onion/users", "http://other.onion/users", false},
+		{"variable resolving to relative", "http://example.onion", "{{path}}", "http://example.onion/status", false},
+		{"missing base_url", "", "/api/v1/users", "/api/v1/users", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			variables := map[string]string{"path": "/status"}
+			if tt.baseURL != "" {
+				variables["base_url"] = tt.baseURL
+			}
+			env := createTestEnvironment(t, manager, "Test", "", variables)
+			if err := manager.SetActiveEnvironment(env.ID); err != nil {
+				t.Fatalf("Failed to activate environment: %v", err)
+			}
+
+			processed, warnings := manager.ProcessRequestWithWarnings(api.NewRequest("GET", tt.url))
+			if processed.URL != tt.expected {
+				t.Errorf("Expected URL %q, got %q", tt.expected, processed.URL)
+			}
+			if (len(warnings) > 0) != tt.wantWarning {
+				t.Errorf("Expected warning %v, got %v", tt.wantWarning, warnings)
+			}
+			if tt.wantWarning && !strings.Contains(warnings[0], "base_url") {
+				t.Errorf("Expected warning to mention base_url, got %q", warnings[0])
+			}
+		})
+	}
+}
+
+func TestProcessRequestWithEnvironment(t *testing.T) {
+	manager := newTestManager(t)
+	dev := createTestEnvironment(t, manager, "Dev", "", map[string]string{"base_url": "http://dev.example", "token": "dev-token"})
+	prod := createTestEnvironment(t, manager, "Prod", "", map[string]string{"base_url": "http://prod.example", "token": "prod-token"})
+	if err := manager.SetActiveEnvironment(dev.ID); err != nil {
+		t.Fatalf("Failed to activate environment: %v", err)
+	}
+
+	req := api.NewRequest("GET", "/users")
+	req.SetHeader("Authorization", "Bearer {{token}}")
+	processed, warnings := manager.ProcessRequestWithEnvironment(req, prod.ID)
+	if len(warnings) > 0 {
+		t.Fatalf("Unexpected warnings: %v", warnings)
+	}
+	if processed.URL != "http://prod.example/users" || processed.Headers["Authorization"] != "Bearer prod-token" {
+		t.Errorf("Expected the request resolved for Prod, got %s with %q", processed.URL, processed.Headers["Authorization"])
+	}
+	if active := manager.GetActiveEnvironment(); active == nil || active.ID != dev.ID {
+		t.Error("Resolving for another environment should not change the active one")
+	}
+
+	empty := createTestEnvironment(t, manager, "Empty", "", map[string]string{})
+	_, warnings = manager.ProcessRequestWithEnvironment(req, empty.ID)
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "Empty") {
+		t.Errorf("Expected a missing base_url warning naming the environment, got %v", warnings)
+	}
+
+	unchanged, warnings := manager.ProcessRequestWithEnvironment(req, "missing")
+	if unchanged != req || len(warnings) != 1 {
+		t.Errorf("Expected an unknown environment to return the request unchanged with a warning, got %v", warnings)
+	}
+}
+
+func TestSubstituteWithEnvironment(t *testing.T) {
+	manager := newTestManager(t)
+	dev := createTestEnvironment(t, manager, "Dev", "", map[string]string{"host": "dev.onion"})
+	prod := createTestEnvironment(t, manager, "Prod", "", map[string]string{"host": "prod.onion"})
+	if err := manager.SetActiveEnvironment(dev.ID); err != nil {
+		t.Fatalf("Failed to activate environment: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		envID string
+		want  string
+	}{
+		{"named environment", "http://{{host}}/", prod.ID, "http://prod.onion/"},
+		{"active environment", "http://{{host}}/", dev.ID, "http://dev.onion/"},
+		{"no environment applies functions only", "{{host}} {{base64 \"a\"}}", "", "{{host}} YQ=="},
+		{"unknown environment leaves input unchanged", "{{host}} {{base64 \"a\"}}", "missing", "{{host}} {{base64 \"a\"}}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := manager.SubstituteWithEnvironment(tt.input, tt.envID); got != tt.want {
+				t.Errorf("SubstituteWithEnvironment(%q, %q) = %q, want %q", tt.input, tt.envID, got, tt.want)
+			}
+		})
+	}
+	if got := manager.SubstituteVariables("{{host}}"); got != "dev.onion" {
+		t.Errorf("SubstituteVariables should still use the active environment, got %q", got)
+	}
+}
+
+func TestReferencedVariables(t *testing.T) {
+	names := ReferencedVariables("{{base_url}}/users/{{id}}", "Bearer {{token}}", `{"id": "{{id}}", "nonce": "{{uuid}}", "n": {{randomInt 1 10}}}`)
+	expected := []string{"base_url", "id", "token"}
+
+	if strings.Join(names, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+}
+
+func TestUpdateEnvironment(t *testing.T) {
+	manager := newTestManager(t)
+	env := createTestEnvironment(t, manager, "Dev", "", map[string]string{"base_url": "http://old.onion"})
+
+	if err := manager.UpdateEnvironment(env.ID, "Dev", "updated", map[string]string{"base_url": "http://new.onion"}, nil); err != nil {
+		t.Fatalf("UpdateEnvironment failed: %v", err)
+	}
+	if err := manager.UpdateEnvironment("missing", "", "", nil, nil); err == nil {
+		t.Error("Expected error for unknown environment")
+	}
+
+	// Reload from disk to check the update was persisted
+	reloaded, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to reload manager: %v", err)
+	}
+	for _, e := range reloaded.GetEnvironments() {
+		if e.ID == env.ID {
+			if e.Variables["base_url"] != "http://new.onion" || e.Description != "updated" {
+				t.Errorf("Expected updated environment to be persisted, got %+v", e)
+			}
+			return
+		}
+	}
+	t.Errorf("Updated environment %s not found after reload", env.ID)
+}
+
+func TestUpdateActiveEnvironment(t *testing.T) {
+	manager := newTestManager(t)
+	env := createTestEnvironment(t, manager, "Staging", "", map[string]string{"base_url": "http://old.onion"})
+	if err := manager.SetActiveEnvironment(env.ID); err != nil {
+		t.Fatalf("SetActiveEnvironment() error = %v", err)
+	}
+
+	if err := manager.UpdateEnvironment(env.ID, "Staging", "", map[string]string{"base_url": "http://new.onion"}, nil); err != nil {
+		t.Fatalf("UpdateEnvironment() error = %v", err)
+	}
+
+	active := manager.GetActiveEnvironment()
+	if active == nil || active.ID != env.ID || !active.IsActive {
+		t.Fatalf("Expected %s to stay active, got %+v", env.ID, active)
+	}
+	if got := manager.SubstituteVariables("{{base_url}}/users"); got != "http://new.onion/users" {
+		t.Errorf("SubstituteVariables() = %q, want the edited value", got)
+	}
+}
+
+func TestDuplicateEnvironment(t *testing.T) {
+	manager := newTestManager(t)
+	original := createTestEnvironment(t, manager, "Dev", "Development", map[string]string{"base_url": "http://dev.onion"})
+	if err := manager.SetActiveEnvironment(original.ID); err != nil {
+		t.Fatalf("Failed to activate environment: %v", err)
+	}
+	originalID := original.ID
+
+	clone, err := manager.DuplicateEnvironment(originalID)
+	if err != nil {
+		t.Fatalf("DuplicateEnvironment failed: %v", err)
+	}
+
+	if clone.ID == originalID {
+		t.Error("Expected clone to have a distinct ID")
+	}
+	if clone.Name != "Dev (copy)" {
+		t.Errorf("Expected name 'Dev (copy)', got %q", clone.Name)
+	}
+	if clone.IsActive {
+		t.Error("Expected clone to be inactive")
+	}
+	if clone.Variables["base_url"] != "http://dev.onion" {
+		t.Errorf("Expected clone to copy variables, got %v", clone.Variables)
+	}
+
+	// Changing the clone must not affect the original
+	clone.Variables["base_url"] = "http://staging.onion"
+	if active := manager.GetActiveEnvironment(); active.ID != originalID || active.Variables["base_url"] != "http://dev.onion" {
+		t.Errorf("Expected original environment to be unchanged, got %+v", active)
+	}
+
+	if _, err := manager.DuplicateEnvironment("missing"); err == nil {
+		t.Error("Expected error for unknown environment")
+	}
+}
+
+func TestProcessRequestKeepsBodyFile(t *testing.T) {
+	manager := newTestManager(t)
+	env := createTestEnvironment(t, manager, "Test", "", map[string]string{"dir": "/tmp/uploads"})
+	if err := manager.SetActiveEnvironment(env.ID); err != nil {
+		t.Fatalf("Failed to activate environment: %v", err)
+	}
+
+	req := api.NewRequest("PUT", "http://example.onion/upload")
+	req.SetBodyFile("{{dir}}/payload.bin")
+
+	processed := manager.ProcessRequest(req)
+	if processed.BodyFile != "/tmp/uploads/payload.bin" {
+		t.Errorf("Expected body file to survive substitution, got %q", processed.BodyFile)
+	}
+}
+
+func TestProcessRequestKeepsTimeout(t *testing.T) {
+	manager := newTestManager(t)
+	req := api.NewRequest("GET", "http://example.onion/slow")
+	req.Timeout = 90 * time.Second
+
+	if processed := manager.ProcessRequest(req); processed.Timeout != req.Timeout {
+		t.Errorf("Expected timeout %v to survive substitution, got %v", req.Timeout, processed.Timeout)
+	}
+}
+
+func TestContains(t *testing.T) {
+	manager := newTestManager(t)
+	env := createTestEnvironment(t, manager, "Test", "", map[string]string{"base_url": "http://example.onion", "id": "42"})
+	if err := manager.SetActiveEnvironment(env.ID); err != nil {
+		t.Fatalf("Failed to activate environment: %v", err)
+	}
+
+	collection := manager.CreateCollection("Users", "")
+	if err := manager.AddRequestToCollection(collection.ID, api.NewRequest("GET", "/users/{{id}}"), "Get user", ""); err != nil {
+		t.Fatalf("Failed to add request: %v", err)
+	}
+
+	if !manager.Contains(api.NewRequest("GET", "http://example.onion/users/42")) {
+		t.Error("Expected the resolved collection request to match")
+	}
+	if manager.Contains(api.NewRequest("GET", "http://example.onion/users/43")) {
+		t.Error("Expected a different URL not to match")
+	}
+}
+
+func TestHeadersFor(t *testing.T) {
+	collection := &Collection{DefaultHeaders: map[string]string{
+		"Accept":    "application/json",
+		"X-Api-Key": "collection-key",
+	}}
+	req := &CollectionRequest{Headers: map[string]string{
+		"x-api-key": "request-key",
+		"X-Trace":   "1",
+	}}
+
+	headers := collection.HeadersFor(req)
+	want := map[string]string{"Accept": "application/json", "x-api-key": "request-key", "X-Trace": "1"}
+	if len(headers) != len(want) {
+		t.Fatalf("HeadersFor = %v, want %v", headers, want)
+	}
+	for name, value := range want {
+		if headers[name] != value {
+			t.Errorf("HeadersFor[%q] = %q, want %q", name, headers[name], value)
+		}
+	}
+	if req.Headers["Accept"] != "" || collection.DefaultHeaders["X-Api-Key"] != "collection-key" {
+		t.Error("HeadersFor should not modify the request or the collection")
+	}
+
+	var none *Collection
+	if headers := none.HeadersFor(req); len(headers) != 2 {
+		t.Errorf("Expected a nil collection to add no headers, got %v", headers)
+	}
+}
+
+func TestSetCollectionDefaultHeaders(t *testing.T) {
+	manager := newTestManager(t)
+	collection := manager.CreateCollection("Shop", "")
+
+	if err := manager.SetCollectionDefaultHeaders(collection.ID, map[string]string{"Accept": "application/json"}); err != nil {
+		t.Fatalf("SetCollectionDefaultHeaders failed: %v", err)
+	}
+	if err := manager.SetCollectionDefaultHeaders("missing", nil); err == nil {
+		t.Error("Expected error for unknown collection")
+	}
+
+	reloaded, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to reload manager: %v", err)
+	}
+	stored, err := reloaded.GetCollection(collection.ID)
+	if err != nil {
+		t.Fatalf("Collection not found after reload: %v", err)
+	}
+	if stored.DefaultHeaders["Accept"] != "application/json" {
+		t.Errorf("Expected default headers to be persisted, got %v", stored.DefaultHeaders)
+	}
+
+	if err := manager.SetCollectionDefaultHeaders(collection.ID, map[string]string{}); err != nil {
+		t.Fatalf("SetCollectionDefaultHeaders failed: %v", err)
+	}
+	if stored, _ := manager.GetCollection(collection.ID); stored.DefaultHeaders != nil {
+		t.Errorf("Expected an empty map to remove the default headers, got %v", stored.DefaultHeaders)
+	}
+}
+
+func TestCollectionsConcurrentAccess(t *testing.T) {
+	manager := newTestManager(t)
+	collection := manager.CreateCollection("Shop", "")
+
+	// Imports and runs use the manager from goroutines while the TUI reads it
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			req := api.NewRequest("GET", fmt.Sprintf("http://example.onion/%d", i))
+			if err := manager.AddRequestToCollection(collection.ID, req, fmt.Sprintf("Request %d", i), ""); err != nil {
+				t.Errorf("AddRequestToCollection failed: %v", err)
+			}
+			manager.CreateCollection(fmt.Sprintf("Extra %d", i), "")
+		}(i)
+		go func() {
+			defer wg.Done()
+			for _, c := range manager.GetCollections() {
+				_ = len(c.Requests)
+			}
+			manager.AuditVariables()
+		}()
+	}
+	wg.Wait()
+
+	stored, err := manager.GetCollection(collection.ID)
+	if err != nil {
+		t.Fatalf("GetCollection failed: %v", err)
+	}
+	if len(stored.Requests) != 8 {
+		t.Errorf("Expected 8 requests, got %d", len(stored.Requests))
+	}
+	if got := len(manager.GetCollections()); got != 9 {
+		t.Errorf("Expected 9 collections, got %d", got)
+	}
+}
+
+func TestDeleteCollection(t *testing.T) {
+	manager := newTestManager(t)
+	keep := manager.CreateCollection("Keep", "")
+	drop := manager.CreateCollection("Drop", "")
+
+	if err := manager.DeleteCollection(drop.ID); err != nil {
+		t.Fatalf("DeleteCollection failed: %v", err)
+	}
+	if err := manager.DeleteCollection(drop.ID); err == nil {
+		t.Error("Expected error deleting a missing collection")
+	}
+	if _, err := manager.GetCollection(keep.ID); err != nil {
+		t.Errorf("Kept collection not found: %v", err)
+	}
+
+	reloaded, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to reload manager: %v", err)
+	}
+	if got := len(reloaded.GetCollections()); got != 1 {
+		t.Errorf("Expected 1 collection after reload, got %d", got)
+	}
+}
@@ -0,0 +1,246 @@
+package collections
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"onioncli/pkg/api"
+)
+
+// DataDrivenResult is the outcome of running a request for one CSV row
+type DataDrivenResult struct {
+	Row        int               `json:"row"` // 1-based data row, excluding the header
+	Values     map[string]string `json:"values"`
+	StatusCode int               `json:"status_code,omitempty"`
+	Status     string            `json:"status,omitempty"`
+	Duration   time.Duration     `json:"duration"`
+	Error      string            `json:"error,omitempty"`
+	Start      time.Duration     `json:"start"` // offset from the start of the run
+	End        time.Duration     `json:"end"`
+}
+
+// Passed reports whether the row's request succeeded with a 2xx response
+func (r DataDrivenResult) Passed() bool {
+	return r.Error == "" && r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// DataDrivenRun holds the results of running a request once per CSV row
+type DataDrivenRun struct {
+	RequestName string             `json:"request_name"`
+	Columns     []string           `json:"columns"`
+	Results     []DataDrivenResult `json:"results"`
+	Elapsed     time.Duration      `json:"elapsed"`
+}
+
+// Summary returns the number of passed and failed rows
+func (r *DataDrivenRun) Summary() (passed, failed int) {
+	for _, result := range r.Results {
+		if result.Passed() {
+			passed++
+		} else {
+			failed++
+		}
+	}
+	return passed, failed
+}
+
+// RunDataDriven runs a collection request once per row of a CSV file. The CSV
+// header names the columns, and each row's values replace the matching
+// {{column}} placeholders before environment variables are applied.
+func (m *Manager) RunDataDriven(requestID, csvPath string, client *api.Client) (*DataDrivenRun, error) {
+	return m.RunDataDrivenWithProgress(context.Background(), requestID, csvPath, client, nil)
+}
+
+// RunDataDrivenWithProgress is RunDataDriven with cancellation and progress
+// reporting. Per-row request failures are recorded in the results; only
+// problems with the request or CSV file are returned as errors.
+func (m *Manager) RunDataDrivenWithProgress(ctx context.Context, requestID, csvPath string, client *api.Client, progress ProgressReporter) (*DataDrivenRun, error) {
+	collectionReq, err := m.findRequest(requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, rows, err := readCSV(csvPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if missing := m.missingPlaceholders(collectionReq, columns); len(missing) > 0 {
+		return nil, fmt.Errorf("CSV is missing columns for placeholders: %s", strings.Join(missing, ", "))
+	}
+
+	run := &DataDrivenRun{
+		RequestName: collectionReq.Name,
+		Columns:     columns,
+		Results:     make([]DataDrivenResult, 0, len(rows)),
+	}
+	authManager := api.NewAuthManager()
+	authManager.SetClient(client)
+	started := time.Now()
+	defer func() { run.Elapsed = time.Since(started) }()
+
+	reportProgress(progress, 0, len(rows))
+	for i, row := range rows {
+		if err := ctx.Err(); err != nil {
+			return run, fmt.Errorf("run cancelled: %w", err)
+		}
+
+		values := make(map[string]string, len(columns))
+		for j, column := range columns {
+			values[column] = row[j]
+		}
+
+		result := DataDrivenResult{Row: i + 1, Values: values, Start: time.Since(started)}
+		resp, err := m.sendRow(ctx, client, authManager, collectionReq, values)
+		result.End = time.Since(started)
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.StatusCode = resp.StatusCode
+			result.Status = resp.Status
+			result.Duration = resp.Duration
+		}
+		run.Results = append(run.Results, result)
+
+		reportProgress(progress, i+1, len(rows))
+	}
+
+	return run, nil
+}
+
+// sendRow substitutes values, such as a CSV row's or a sequence run's
+// variables, into the request and sends it
+func (m *Manager) sendRow(ctx context.Context, client *api.Client, authManager *api.AuthManager, collectionReq *CollectionRequest, values map[string]string) (*api.Response, error) {
+	req := collectionReq.ToRequest()
+	req.URL = substituteValues(req.URL, values)
+	req.Body = substituteValues(req.Body, values)
+	headers := make(map[string]string, len(req.Headers))
+	for key, value := range req.Headers {
+		headers[substituteValues(key, values)] = substituteValues(value, values)
+	}
+	req.Headers = headers
+
+	req = m.ProcessRequest(req)
+
+	normalizedURL, _, err := api.NormalizeURL(req.URL)
+	if err != nil {
+		return nil, err
+	}
+	req.URL = normalizedURL
+
+	if collectionReq.Auth != nil {
+		if err := authManager.ApplyAuth(req, collectionReq.Auth); err != nil {
+			return nil, fmt.Errorf("authentication failed: %w", err)
+		}
+	}
+
+	return client.SendContext(ctx, req)
+}
+
+// findRequest finds a collection request by ID across all collections. It
+// returns a copy carrying its collection's default headers, and its
+// collection's auth when it has none of its own.
+func (m *Manager) findRequest(requestID string) (*CollectionRequest, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for i := range m.collections {
+		for j := range m.collections[i].Requests {
+			if m.collections[i].Requests[j].ID == requestID {
+				req := m.collections[i].Requests[j]
+				req.Headers = m.collections[i].HeadersFor(&req)
+				if req.Auth == nil {
+					req.Auth = m.collections[i].Auth
+				}
+				return &req, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("request not found: %s", requestID)
+}
+
+// missingPlaceholders returns placeholders in the request that are neither
+// CSV columns nor variables in the active environment
+func (m *Manager) missingPlaceholders(req *CollectionRequest, columns []string) []string {
+	available := make(map[string]bool)
+	for _, column := range columns {
+		available[column] = true
+	}
+	if env := m.GetActiveEnvironment(); env != nil {
+		for key := range env.Variables {
+			available[key] = true
+		}
+	}
+
+	texts := []string{req.URL, req.Body}
+	for key, value := range req.Headers {
+		texts = append(texts, key, value)
+	}
+
+	var missing []string
+	for _, name := range ReferencedVariables(texts...) {
+		if !available[name] {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// readCSV reads a CSV file with a header row, returning the column names and data rows
+func readCSV(path string) ([]string, [][]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open CSV: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, fmt.Errorf("CSV file is empty")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse CSV header: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for i, column := range header {
+		column = strings.TrimSpace(column)
+		if column == "" {
+			return nil, nil, fmt.Errorf("CSV column %d has an empty name", i+1)
+		}
+		if seen[column] {
+			return nil, nil, fmt.Errorf("CSV column %q appears more than once", column)
+		}
+		seen[column] = true
+		header[i] = column
+	}
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse CSV: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil, fmt.Errorf("CSV has no data rows")
+	}
+
+	return header, rows, nil
+}
+
+// substituteValues replaces {{key}} placeholders with the given values
+func substituteValues(input string, values map[string]string) string {
+	for key, value := range values {
+		input = strings.ReplaceAll(input, "{{"+key+"}}", value)
+	}
+	return input
+}
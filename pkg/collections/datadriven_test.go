@@ -0,0 +1,145 @@
+package collections
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"onioncli/pkg/api"
+)
+
+// writeCSV writes CSV content to a temp file and returns its path
+func writeCSV(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "data.csv")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write CSV: %v", err)
+	}
+	return path
+}
+
+func TestRunDataDriven(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.RequestURI())
+		mu.Unlock()
+		if strings.Contains(r.URL.Path, "missing") {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	manager := newTestManager(t)
+	collection := manager.CreateCollection("Users", "")
+	if err := manager.AddRequestToCollection(collection.ID, api.NewRequest("GET", server.URL+"/users/{{id}}?name={{name}}"), "Get user", ""); err != nil {
+		t.Fatalf("Failed to add request: %v", err)
+	}
+	stored, _ := manager.GetCollection(collection.ID)
+	requestID := stored.Requests[0].ID
+
+	client, err := api.NewClient(&api.ClientConfig{TorEnabled: false, Timeout: 10 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	csvPath := writeCSV(t, "id,name\n1,alice\nmissing,bob smith\n")
+	run, err := manager.RunDataDriven(requestID, csvPath, client)
+	if err != nil {
+		t.Fatalf("RunDataDriven failed: %v", err)
+	}
+
+	if len(run.Results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(run.Results))
+	}
+	if run.Results[0].StatusCode != 200 || run.Results[1].StatusCode != 404 {
+		t.Errorf("Unexpected status codes: %d, %d", run.Results[0].StatusCode, run.Results[1].StatusCode)
+	}
+	if passed, failed := run.Summary(); passed != 1 || failed != 1 {
+		t.Errorf("Expected 1 passed and 1 failed, got %d and %d", passed, failed)
+	}
+
+	first, second := run.Results[0], run.Results[1]
+	if first.End < first.Start || second.Start < first.End || run.Elapsed < second.End {
+		t.Errorf("Expected sequential timings within the run, got %v-%v, %v-%v of %v",
+			first.Start, first.End, second.Start, second.End, run.Elapsed)
+	}
+
+	expectedPaths := []string{"/users/1?name=alice", "/users/missing?name=bob%20smith"}
+	if strings.Join(paths, " ") != strings.Join(expectedPaths, " ") {
+		t.Errorf("Expected requests %v, got %v", expectedPaths, paths)
+	}
+}
+
+func TestRunDataDrivenCollectionAuth(t *testing.T) {
+	var authorization []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = append(authorization, r.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+
+	manager := newTestManager(t)
+	collection := manager.CreateCollection("Users", "")
+	if err := manager.AddRequestToCollection(collection.ID, api.NewRequest("GET", server.URL+"/users/{{id}}"), "Get user", ""); err != nil {
+		t.Fatalf("Failed to add request: %v", err)
+	}
+	stored := editCollection(t, manager, collection.ID, func(collection *Collection) {
+		collection.Auth = &api.AuthConfig{Type: api.AuthBearer, Token: "c0llection"}
+	})
+
+	client, err := api.NewClient(&api.ClientConfig{TorEnabled: false, Timeout: 10 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if _, err := manager.RunDataDriven(stored.Requests[0].ID, writeCSV(t, "id\n1\n"), client); err != nil {
+		t.Fatalf("RunDataDriven failed: %v", err)
+	}
+
+	if len(authorization) != 1 || authorization[0] != "Bearer c0llection" {
+		t.Errorf("Expected the collection's auth to be sent, got %q", authorization)
+	}
+}
+
+func TestRunDataDrivenErrors(t *testing.T) {
+	manager := newTestManager(t)
+	collection := manager.CreateCollection("Users", "")
+	if err := manager.AddRequestToCollection(collection.ID, api.NewRequest("GET", "http://example.com/users/{{id}}/{{extra}}"), "Get user", ""); err != nil {
+		t.Fatalf("Failed to add request: %v", err)
+	}
+	stored, _ := manager.GetCollection(collection.ID)
+	requestID := stored.Requests[0].ID
+
+	client, err := api.NewClient(&api.ClientConfig{TorEnabled: false, Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		requestID string
+		csv       string
+		errSubstr string
+	}{
+		{"missing column", requestID, "id\n1\n", "missing columns for placeholders: extra"},
+		{"ragged row", requestID, "id,extra\n1,a\n2\n", "failed to parse CSV"},
+		{"header only", requestID, "id,extra\n", "no data rows"},
+		{"empty file", requestID, "", "empty"},
+		{"duplicate column", requestID, "id,id\n1,2\n", "more than once"},
+		{"unknown request", "nope", "id,extra\n1,a\n", "request not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := manager.RunDataDriven(tt.requestID, writeCSV(t, tt.csv), client)
+			if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
+				t.Errorf("Expected error containing %q, got %v", tt.errSubstr, err)
+			}
+		})
+	}
+}
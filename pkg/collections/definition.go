@@ -0,0 +1,266 @@
+package collections
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"onioncli/pkg/api"
+)
+
+// DefinitionFormat names a request definition format accepted by ParseDefinition
+type DefinitionFormat string
+
+const (
+	FormatJSONRequest DefinitionFormat = "json"       // a single request object
+	FormatCollection  DefinitionFormat = "collection" // an OnionCLI collection or bundle
+	FormatHTTPFile    DefinitionFormat = "http"       // a .http / .rest file
+	FormatCurl        DefinitionFormat = "curl"       // a cURL command line
+)
+
+// ParseDefinition reads request definitions, detecting whether they are a JSON
+// request, an OnionCLI collection or bundle, a .http file or a cURL command.
+// Requests in a collection without their own auth inherit the collection's,
+// and those in a bundle have its environment's variables substituted.
+func ParseDefinition(r io.Reader) ([]CollectionRequest, DefinitionFormat, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read definition: %w", err)
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, "", fmt.Errorf("definition is empty")
+	}
+
+	var requests []CollectionRequest
+	var format DefinitionFormat
+	switch {
+	case trimmed[0] == '{':
+		requests, format, err = parseJSONDefinition(trimmed)
+	case api.IsCurlCommand(string(trimmed)):
+		format = FormatCurl
+		var req *CollectionRequest
+		if req, err = parseCurl(string(trimmed)); err == nil {
+			requests = []CollectionRequest{*req}
+		}
+	default:
+		format = FormatHTTPFile
+		requests, err = parseHTTPFile(string(data))
+	}
+	if err != nil {
+		return nil, format, err
+	}
+	if len(requests) == 0 {
+		return nil, format, fmt.Errorf("no requests found in %s definition", format)
+	}
+
+	for i := range requests {
+		if requests[i].URL == "" {
+			return nil, format, fmt.Errorf("request %d has no URL", i+1)
+		}
+		if requests[i].Method == "" {
+			requests[i].Method = "GET"
+		}
+		requests[i].Method = strings.ToUpper(requests[i].Method)
+	}
+	return requests, format, nil
+}
+
+// parseJSONDefinition parses a single request object, a collection or a bundle
+func parseJSONDefinition(data []byte) ([]CollectionRequest, DefinitionFormat, error) {
+	if isBundle(data) {
+		var bundle Bundle
+		if err := json.Unmarshal(data, &bundle); err != nil {
+			return nil, FormatCollection, fmt.Errorf("failed to parse bundle: %w", err)
+		}
+		requests := inheritDefaults(bundle.Collection)
+		if bundle.Environment != nil {
+			substituteBundleVariables(requests, bundle.Environment.Variables)
+		}
+		return requests, FormatCollection, nil
+	}
+
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, FormatJSONRequest, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	if _, ok := probe["requests"]; ok {
+		var collection Collection
+		if err := json.Unmarshal(data, &collection); err != nil {
+			return nil, FormatCollection, fmt.Errorf("failed to parse collection: %w", err)
+		}
+		return inheritDefaults(collection), FormatCollection, nil
+	}
+
+	var req CollectionRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, FormatJSONRequest, fmt.Errorf("failed to parse request: %w", err)
+	}
+	return []CollectionRequest{req}, FormatJSONRequest, nil
+}
+
+// inheritDefaults returns the collection's requests with its default
+// headers, giving those without auth the collection's auth
+func inheritDefaults(collection Collection) []CollectionRequest {
+	requests := collection.Requests
+	for i := range requests {
+		requests[i].Headers = collection.HeadersFor(&requests[i])
+		if requests[i].Auth == nil {
+			requests[i].Auth = collection.Auth
+		}
+	}
+	return requests
+}
+
+// substituteBundleVariables fills in the requests' URL, headers and body
+// from a bundle environment's variables, so a bundle runs on its own.
+// Variables with no value, such as secrets left out of the export, stay
+// placeholders for the active environment to resolve.
+func substituteBundleVariables(requests []CollectionRequest, variables map[string]string) {
+	values := make(map[string]string, len(variables))
+	for key, value := range variables {
+		if value != "" {
+			values[key] = value
+		}
+	}
+
+	for i := range requests {
+		req := &requests[i]
+		req.URL = substituteValues(req.URL, values)
+		req.Body = substituteValues(req.Body, values)
+		headers := make(map[string]string, len(req.Headers))
+		for key, value := range req.Headers {
+			headers[substituteValues(key, values)] = substituteValues(value, values)
+		}
+		req.Headers = headers
+	}
+}
+
+// httpRequestLine matches "METHOD URL [HTTP/x]" at the start of a .http request
+var httpRequestLine = regexp.MustCompile(`^([A-Za-z]+)\s+(\S+)(\s+HTTP/[\d.]+)?$`)
+
+// httpFileVariable matches "@name = value" variable definitions in .http files
+var httpFileVariable = regexp.MustCompile(`^@([A-Za-z_][A-Za-z0-9_]*)\s*=\s*(.*)$`)
+
+// parseHTTPFile parses a .http file: requests separated by "###" lines, each a
+// request line, headers, a blank line and an optional body. "@name = value"
+// lines define variables used as {{name}}; environment variables are left for
+// the caller to substitute.
+func parseHTTPFile(content string) ([]CollectionRequest, error) {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	variables := make(map[string]string)
+
+	var requests []CollectionRequest
+	for _, block := range splitHTTPBlocks(content) {
+		req, err := parseHTTPBlock(block.lines, variables)
+		if err != nil {
+			return nil, err
+		}
+		if req == nil {
+			continue
+		}
+		req.Name = block.name
+		if req.Name == "" {
+			req.Name = req.Method + " " + req.URL
+		}
+		requests = append(requests, *req)
+	}
+	return requests, nil
+}
+
+// httpBlock is one "###"-separated section of a .http file
+type httpBlock struct {
+	name  string
+	lines []string
+}
+
+// splitHTTPBlocks splits a .http file on "###" separator lines, whose text
+// names the request that follows
+func splitHTTPBlocks(content string) []httpBlock {
+	blocks := []httpBlock{{}}
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "###") {
+			blocks = append(blocks, httpBlock{name: strings.TrimSpace(strings.TrimLeft(line, "#"))})
+			continue
+		}
+		blocks[len(blocks)-1].lines = append(blocks[len(blocks)-1].lines, line)
+	}
+	return blocks
+}
+
+// parseHTTPBlock parses one request, returning nil if the block holds only
+// comments and variables
+func parseHTTPBlock(lines []string, variables map[string]string) (*CollectionRequest, error) {
+	i := 0
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if match := httpFileVariable.FindStringSubmatch(line); match != nil {
+			variables[match[1]] = substituteValues(strings.TrimSpace(match[2]), variables)
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+		break
+	}
+	if i == len(lines) {
+		return nil, nil
+	}
+
+	requestLine := strings.TrimSpace(lines[i])
+	req := &CollectionRequest{Headers: make(map[string]string)}
+	if match := httpRequestLine.FindStringSubmatch(requestLine); match != nil {
+		req.Method, req.URL = match[1], match[2]
+	} else if !strings.ContainsAny(requestLine, " \t") {
+		req.Method, req.URL = "GET", requestLine // a bare URL is a GET
+	} else {
+		return nil, fmt.Errorf("invalid request line %q", requestLine)
+	}
+	req.URL = substituteValues(req.URL, variables)
+
+	for i++; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			break
+		}
+		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid header line %q", line)
+		}
+		req.Headers[strings.TrimSpace(name)] = substituteValues(strings.TrimSpace(value), variables)
+	}
+
+	if i < len(lines) {
+		body := strings.Trim(strings.Join(lines[i+1:], "\n"), "\n")
+		req.Body = substituteValues(body, variables)
+	}
+	return req, nil
+}
+
+// parseCurl parses a cURL command line into a request, keeping -u
+// credentials as its auth
+func parseCurl(command string) (*CollectionRequest, error) {
+	req, auth, err := api.ParseCurl(command)
+	if err != nil {
+		return nil, err
+	}
+	if req.Multipart != nil {
+		return nil, fmt.Errorf("curl -F forms are not supported in request definitions")
+	}
+	return &CollectionRequest{
+		Name:    "curl " + req.URL,
+		Method:  req.Method,
+		URL:     req.URL,
+		Headers: req.Headers,
+		Body:    req.Body,
+		Auth:    auth,
+	}, nil
+}
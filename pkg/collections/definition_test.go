@@ -0,0 +1,169 @@
+package collections
+
+import (
+	"strings"
+	"testing"
+
+	"onioncli/pkg/api"
+)
+
+func TestParseDefinition(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantFormat DefinitionFormat
+		want       []CollectionRequest
+	}{
+		{
+			name:       "JSON request",
+			input:      `{"method": "post", "url": "http://example.onion/users", "headers": {"Content-Type": "application/json"}, "body": "{\"name\":\"alice\"}"}`,
+			wantFormat: FormatJSONRequest,
+			want: []CollectionRequest{{
+				Method:  "POST",
+				URL:     "http://example.onion/users",
+				Headers: map[string]string{"Content-Type": "application/json"},
+				Body:    `{"name":"alice"}`,
+			}},
+		},
+		{
+			name:       "collection",
+			input:      `{"name": "API", "requests": [{"name": "List", "url": "http://example.onion/a"}, {"name": "Create", "method": "POST", "url": "http://example.onion/b"}]}`,
+			wantFormat: FormatCollection,
+			want: []CollectionRequest{
+				{Name: "List", Method: "GET", URL: "http://example.onion/a"},
+				{Name: "Create", Method: "POST", URL: "http://example.onion/b"},
+			},
+		},
+		{
+			name: "bundle",
+			input: `{"format": "onioncli-bundle", "version": 1,
+  "collection": {"name": "API", "requests": [{"name": "Me", "url": "{{base_url}}/me", "headers": {"Authorization": "Bearer {{token}}"}}]},
+  "environment": {"name": "Staging", "variables": {"base_url": "http://staging.onion", "token": ""}}}`,
+			wantFormat: FormatCollection,
+			want: []CollectionRequest{{
+				Name:    "Me",
+				Method:  "GET",
+				URL:     "http://staging.onion/me",
+				Headers: map[string]string{"Authorization": "Bearer {{token}}"}, // left out of the export
+			}},
+		},
+		{
+			name: "http file",
+			input: `@host = http://example.onion
+# comment
+GET {{host}}/users HTTP/1.1
+Accept: application/json
+
+### Create user
+POST {{host}}/users
+Content-Type: application/json
+
+{"name": "bob"}
+`,
+			wantFormat: FormatHTTPFile,
+			want: []CollectionRequest{
+				{Name: "GET http://example.onion/users", Method: "GET", URL: "http://example.onion/users",
+					Headers: map[string]string{"Accept": "application/json"}},
+				{Name: "Create user", Method: "POST", URL: "http://example.onion/users",
+					Headers: map[string]string{"Content-Type": "application/json"}, Body: `{"name": "bob"}`},
+			},
+		},
+		{
+			name: "curl",
+			input: `curl -s -X PUT 'http://example.onion/users/1' \
+  -H "Authorization: Bearer {{token}}" \
+  --data-raw '{"name": "carol"}'`,
+			wantFormat: FormatCurl,
+			want: []CollectionRequest{{
+				Name:   "curl http://example.onion/users/1",
+				Method: "PUT",
+				URL:    "http://example.onion/users/1",
+				Headers: map[string]string{
+					"Authorization": "Bearer {{token}}",
+					"Content-Type":  "application/x-www-form-urlencoded",
+				},
+				Body: `{"name": "carol"}`,
+			}},
+		},
+		{
+			name:       "curl with data defaults to POST",
+			input:      `curl http://example.onion/login -d user=dave -d pass=x`,
+			wantFormat: FormatCurl,
+			want: []CollectionRequest{{
+				Name:    "curl http://example.onion/login",
+				Method:  "POST",
+				URL:     "http://example.onion/login",
+				Headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+				Body:    "user=dave&pass=x",
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, format, err := ParseDefinition(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("ParseDefinition() error = %v", err)
+			}
+			if format != tt.wantFormat {
+				t.Errorf("format = %q, want %q", format, tt.wantFormat)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d requests, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i, want := range tt.want {
+				req := got[i]
+				if req.Name != want.Name || req.Method != want.Method || req.URL != want.URL || req.Body != want.Body {
+					t.Errorf("request %d = %s %s %q (%q), want %s %s %q (%q)",
+						i, req.Method, req.URL, req.Body, req.Name, want.Method, want.URL, want.Body, want.Name)
+				}
+				if len(req.Headers) != len(want.Headers) {
+					t.Errorf("request %d headers = %v, want %v", i, req.Headers, want.Headers)
+				}
+				for key, value := range want.Headers {
+					if req.Headers[key] != value {
+						t.Errorf("request %d header %s = %q, want %q", i, key, req.Headers[key], value)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestParseDefinitionCurlBasicAuth(t *testing.T) {
+	requests, _, err := ParseDefinition(strings.NewReader(`curl -u alice:s3cret --json '{"a":1}' http://example.onion/x`))
+	if err != nil {
+		t.Fatalf("ParseDefinition() error = %v", err)
+	}
+
+	req := requests[0]
+	if req.Auth == nil || req.Auth.Type != api.AuthBasic || req.Auth.Username != "alice" || req.Auth.Password != "s3cret" {
+		t.Errorf("Auth = %+v, want basic alice:s3cret", req.Auth)
+	}
+	if req.Headers["Content-Type"] != "application/json" || req.Method != "POST" {
+		t.Errorf("--json request = %s with headers %v", req.Method, req.Headers)
+	}
+}
+
+func TestParseDefinitionErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"empty", "  \n"},
+		{"curl without URL", "curl -X GET"},
+		{"curl data file", "curl -d @body.json http://example.onion"},
+		{"unterminated quote", "curl 'http://example.onion"},
+		{"bad request line", "GET http://example.onion extra words"},
+		{"JSON without URL", `{"method": "GET"}`},
+		{"only comments", "# nothing here\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := ParseDefinition(strings.NewReader(tt.input)); err == nil {
+				t.Error("ParseDefinition() error = nil, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package collections
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"onioncli/pkg/api"
+)
+
+// Draft is the request builder's unsaved content, kept across restarts.
+// Headers and Body are the raw text of their fields. Only the type of the
+// builder's auth is kept: credentials stay out of the file, and a restored
+// draft must not replace auth the user has set since.
+type Draft struct {
+	Method   string       `json:"method"`
+	URL      string       `json:"url"`
+	Query    string       `json:"query,omitempty"` // query editor, one name=value per line
+	Headers  string       `json:"headers,omitempty"`
+	Body     string       `json:"body,omitempty"`
+	BodyForm bool         `json:"body_form,omitempty"` // body holds multipart form fields
+	AuthType api.AuthType `json:"auth_type,omitempty"`
+	SavedAt  time.Time    `json:"saved_at"`
+}
+
+// IsEmpty reports whether the draft has nothing worth restoring
+func (d Draft) IsEmpty() bool {
+	return d.URL == "" && d.Query == "" && d.Headers == "" && d.Body == ""
+}
+
+// LoadDraft reads the saved draft, or returns nil if there is none
+func (m *Manager) LoadDraft() (*Draft, error) {
+	data, err := os.ReadFile(m.draftFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read draft: %w", err)
+	}
+
+	var draft Draft
+	if err := json.Unmarshal(data, &draft); err != nil {
+		return nil, fmt.Errorf("failed to parse draft: %w", err)
+	}
+	return &draft, nil
+}
+
+// SaveDraft writes the draft, readable only by the user since headers and
+// bodies may hold secrets. An empty draft discards the saved one instead.
+func (m *Manager) SaveDraft(draft Draft) error {
+	if draft.IsEmpty() {
+		return m.DiscardDraft()
+	}
+
+	draft.SavedAt = time.Now()
+	data, err := json.MarshalIndent(draft, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal draft: %w", err)
+	}
+
+	if err := os.WriteFile(m.draftFile, data, 0600); err != nil {
+		return fmt.Errorf("failed to write draft: %w", err)
+	}
+	return nil
+}
+
+// DiscardDraft deletes the saved draft, if any
+func (m *Manager) DiscardDraft() error {
+	if err := os.Remove(m.draftFile); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to discard draft: %w", err)
+	}
+	return nil
+}
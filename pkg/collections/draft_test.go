@@ -0,0 +1,56 @@
+package collections
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"onioncli/pkg/api"
+)
+
+func TestDraftSaveLoadDiscard(t *testing.T) {
+	manager := newTestManager(t)
+
+	draft, err := manager.LoadDraft()
+	if err != nil || draft != nil {
+		t.Fatalf("LoadDraft() with no draft = %v, %v, want nil, nil", draft, err)
+	}
+
+	saved := Draft{
+		Method:   "POST",
+		URL:      "http://example.onion/users",
+		Headers:  "Accept: application/json\n@timeout: 90s",
+		Body:     `{"name": "alice"}`,
+		AuthType: api.AuthBearer,
+	}
+	if err := manager.SaveDraft(saved); err != nil {
+		t.Fatalf("SaveDraft() error = %v", err)
+	}
+	if info, err := os.Stat(manager.draftFile); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Draft file mode = %v, %v, want 0600", info.Mode().Perm(), err)
+	}
+
+	draft, err = manager.LoadDraft()
+	if err != nil || draft == nil {
+		t.Fatalf("LoadDraft() = %v, %v", draft, err)
+	}
+	if data, _ := os.ReadFile(manager.draftFile); strings.Contains(string(data), `"auth"`) {
+		t.Errorf("Draft file = %s, want no auth credentials", data)
+	}
+	if draft.Method != saved.Method || draft.URL != saved.URL || draft.Headers != saved.Headers ||
+		draft.Body != saved.Body || draft.AuthType != api.AuthBearer || draft.SavedAt.IsZero() {
+		t.Errorf("LoadDraft() = %+v, want %+v", draft, saved)
+	}
+
+	// Saving an empty draft discards the saved one
+	if err := manager.SaveDraft(Draft{Method: "GET"}); err != nil {
+		t.Fatalf("SaveDraft(empty) error = %v", err)
+	}
+	if draft, _ := manager.LoadDraft(); draft != nil {
+		t.Errorf("Expected no draft after saving an empty one, got %+v", draft)
+	}
+
+	if err := manager.DiscardDraft(); err != nil {
+		t.Errorf("DiscardDraft() with no draft error = %v", err)
+	}
+}
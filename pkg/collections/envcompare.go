@@ -0,0 +1,93 @@
+package collections
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"onioncli/pkg/api"
+)
+
+// maxEnvironmentConcurrency caps how many environments are sent to at once;
+// each request through Tor may build its own circuit, and Tor only builds
+// a few at a time
+const maxEnvironmentConcurrency = 4
+
+// EnvironmentRequest is a request resolved for one environment
+type EnvironmentRequest struct {
+	Environment string // environment name
+	Request     *api.Request
+	Err         error // why the request could not be resolved; it is not sent
+}
+
+// EnvironmentResult is the outcome of sending a request to one environment
+type EnvironmentResult struct {
+	Environment string
+	URL         string
+	Response    *api.Response // nil if Error is set
+	Error       string
+}
+
+// EnvironmentComparison holds the results of sending one request to several
+// environments, in the order the environments were given
+type EnvironmentComparison struct {
+	Results []EnvironmentResult
+	Elapsed time.Duration
+}
+
+// SendToEnvironments sends each environment's request concurrently, at most
+// maxEnvironmentConcurrency at a time. Requests that failed to resolve or
+// send are recorded in the results rather than stopping the others.
+// Cancelling ctx aborts the requests still in flight.
+func SendToEnvironments(ctx context.Context, client *api.Client, requests []EnvironmentRequest, progress ProgressReporter) *EnvironmentComparison {
+	comparison := &EnvironmentComparison{Results: make([]EnvironmentResult, len(requests))}
+	started := time.Now()
+
+	var mu sync.Mutex // serialises progress so counts arrive in order
+	done := 0
+	finished := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		done++
+		reportProgress(progress, done, len(requests))
+	}
+
+	reportProgress(progress, 0, len(requests))
+	slots := make(chan struct{}, maxEnvironmentConcurrency)
+	var wg sync.WaitGroup
+	for i, target := range requests {
+		result := &comparison.Results[i]
+		result.Environment = target.Environment
+		if target.Err != nil {
+			result.Error = target.Err.Error()
+			finished()
+			continue
+		}
+		result.URL = target.Request.URL
+
+		wg.Add(1)
+		go func(req *api.Request) {
+			defer wg.Done()
+			defer finished()
+
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-ctx.Done():
+				result.Error = ctx.Err().Error()
+				return
+			}
+
+			resp, err := client.SendContext(ctx, req)
+			if err != nil {
+				result.Error = err.Error()
+				return
+			}
+			result.Response = resp
+		}(target.Request)
+	}
+	wg.Wait()
+
+	comparison.Elapsed = time.Since(started)
+	return comparison
+}
@@ -0,0 +1,72 @@
+package collections
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"onioncli/pkg/api"
+)
+
+func TestSendToEnvironments(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		w.Write([]byte(r.URL.Path))
+	}))
+	defer server.Close()
+
+	client, err := api.NewClient(&api.ClientConfig{TorEnabled: false, Timeout: 10 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	names := []string{"a", "b", "c", "d", "e", "f"}
+	var requests []EnvironmentRequest
+	for _, name := range names {
+		requests = append(requests, EnvironmentRequest{Environment: name, Request: api.NewRequest("GET", server.URL+"/"+name)})
+	}
+	requests = append(requests, EnvironmentRequest{Environment: "broken", Err: errors.New("unresolved: host")})
+
+	var reported []int
+	progress := ProgressFunc(func(done, total int) { reported = append(reported, done) })
+	comparison := SendToEnvironments(context.Background(), client, requests, progress)
+
+	if len(comparison.Results) != len(requests) {
+		t.Fatalf("Expected %d results, got %d", len(requests), len(comparison.Results))
+	}
+	for i, name := range names {
+		result := comparison.Results[i]
+		if result.Environment != name || result.Response == nil || result.Response.Body != "/"+name {
+			t.Errorf("Result %d = %+v, expected the response from %s", i, result, name)
+		}
+	}
+	if broken := comparison.Results[len(names)]; broken.Error != "unresolved: host" || broken.Response != nil {
+		t.Errorf("Expected the unresolved request to be reported, not sent: %+v", broken)
+	}
+	if peak > maxEnvironmentConcurrency {
+		t.Errorf("Expected at most %d concurrent requests, got %d", maxEnvironmentConcurrency, peak)
+	}
+	for i, done := range reported {
+		if done != i {
+			t.Fatalf("Expected progress in order, got %v", reported)
+		}
+	}
+	if len(reported) != len(requests)+1 {
+		t.Errorf("Expected %d progress reports, got %v", len(requests)+1, reported)
+	}
+}
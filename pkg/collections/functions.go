@@ -0,0 +1,195 @@
+package collections
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// templateFunction computes the value of a {{name args...}} placeholder
+type templateFunction func(args []string) (string, error)
+
+// templateFunctions are the built-in functions placeholders can call, e.g.
+// {{randomInt 1 100}}. Arguments are separated by spaces; quote one with
+// double quotes to include spaces, as in {{base64 "user:pass word"}}.
+//
+//	now [layout]            current UTC time, RFC 3339 or a Go time layout
+//	timestamp               current Unix time in seconds
+//	uuid                    random version 4 UUID
+//	randomInt min max       random integer from min to max inclusive
+//	base64 text             text in standard base64
+//	hmacSHA256 key message  hex HMAC-SHA256 of message
+var templateFunctions = map[string]templateFunction{
+	"now":        nowFunction,
+	"timestamp":  timestampFunction,
+	"uuid":       uuidFunction,
+	"randomInt":  randomIntFunction,
+	"base64":     base64Function,
+	"hmacSHA256": hmacSHA256Function,
+}
+
+// maxFunctionNesting bounds how deeply function calls can be nested as
+// arguments, e.g. {{hmacSHA256 "key" "{{timestamp}}"}}
+const maxFunctionNesting = 4
+
+// applyTemplateFunctions replaces placeholders that call a built-in function
+// with its result, innermost calls first. Placeholders naming no function,
+// or calling one with the wrong arguments, are left as they are, so
+// templates written for other tools don't break.
+func applyTemplateFunctions(input string) string {
+	result := input
+	for range maxFunctionNesting {
+		if !strings.Contains(result, "{{") {
+			break
+		}
+		next := variablePattern.ReplaceAllStringFunc(result, callTemplateFunction)
+		if next == result {
+			break
+		}
+		result = next
+	}
+	return result
+}
+
+// callTemplateFunction returns the value of a placeholder calling a built-in
+// function, or the placeholder itself
+func callTemplateFunction(placeholder string) string {
+	name, args, err := parseTemplateCall(placeholder[2 : len(placeholder)-2])
+	if err != nil {
+		return placeholder
+	}
+	function, ok := templateFunctions[name]
+	if !ok {
+		return placeholder
+	}
+	value, err := function(args)
+	if err != nil {
+		return placeholder
+	}
+	return value
+}
+
+// isTemplateFunction reports whether a placeholder's contents call a
+// built-in function rather than name a variable
+func isTemplateFunction(contents string) bool {
+	name, _, err := parseTemplateCall(contents)
+	_, ok := templateFunctions[name]
+	return err == nil && ok
+}
+
+// parseTemplateCall splits placeholder contents such as `base64 "a b"` into
+// the function name and its arguments, unquoting quoted ones
+func parseTemplateCall(contents string) (string, []string, error) {
+	var fields []string
+	rest := strings.TrimSpace(contents)
+	for rest != "" {
+		if rest[0] == '"' {
+			quoted, err := strconv.QuotedPrefix(rest)
+			if err != nil {
+				return "", nil, fmt.Errorf("unterminated quote in %q", contents)
+			}
+			field, _ := strconv.Unquote(quoted)
+			fields = append(fields, field)
+			rest = strings.TrimSpace(rest[len(quoted):])
+			continue
+		}
+
+		end := strings.IndexAny(rest, " \t")
+		if end < 0 {
+			end = len(rest)
+		}
+		fields = append(fields, rest[:end])
+		rest = strings.TrimSpace(rest[end:])
+	}
+
+	if len(fields) == 0 {
+		return "", nil, fmt.Errorf("empty placeholder")
+	}
+	return fields[0], fields[1:], nil
+}
+
+// wantArgs checks a function got between min and max arguments
+func wantArgs(name string, args []string, min, max int) error {
+	if len(args) < min || len(args) > max {
+		return fmt.Errorf("%s takes %d to %d arguments, got %d", name, min, max, len(args))
+	}
+	return nil
+}
+
+func nowFunction(args []string) (string, error) {
+	if err := wantArgs("now", args, 0, 1); err != nil {
+		return "", err
+	}
+	layout := time.RFC3339
+	if len(args) == 1 {
+		layout = args[0]
+	}
+	return time.Now().UTC().Format(layout), nil
+}
+
+func timestampFunction(args []string) (string, error) {
+	if err := wantArgs("timestamp", args, 0, 0); err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(time.Now().Unix(), 10), nil
+}
+
+func uuidFunction(args []string) (string, error) {
+	if err := wantArgs("uuid", args, 0, 0); err != nil {
+		return "", err
+	}
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+func randomIntFunction(args []string) (string, error) {
+	if err := wantArgs("randomInt", args, 2, 2); err != nil {
+		return "", err
+	}
+	low, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return "", err
+	}
+	high, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return "", err
+	}
+	if high < low {
+		return "", fmt.Errorf("randomInt range %d to %d is empty", low, high)
+	}
+
+	span := new(big.Int).Sub(big.NewInt(high), big.NewInt(low))
+	n, err := rand.Int(rand.Reader, span.Add(span, big.NewInt(1)))
+	if err != nil {
+		return "", err
+	}
+	return n.Add(n, big.NewInt(low)).String(), nil
+}
+
+func base64Function(args []string) (string, error) {
+	if err := wantArgs("base64", args, 1, 1); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString([]byte(args[0])), nil
+}
+
+func hmacSHA256Function(args []string) (string, error) {
+	if err := wantArgs("hmacSHA256", args, 2, 2); err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, []byte(args[0]))
+	mac.Write([]byte(args[1]))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
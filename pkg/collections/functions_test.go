@@ -0,0 +1,92 @@
+package collections
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"onioncli/pkg/api"
+)
+
+func TestApplyTemplateFunctions(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"base64 quoted", `Basic {{base64 "alice:s3cret pw"}}`, "Basic YWxpY2U6czNjcmV0IHB3"},
+		{"base64 bare", "{{base64 hello}}", "aGVsbG8="},
+		{"hmac", `{{hmacSHA256 key "The quick brown fox jumps over the lazy dog"}}`, "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8"},
+		{"nested call", `{{base64 "{{randomInt 7 7}}"}}`, "Nw=="},
+		{"randomInt single value", "{{randomInt 7 7}}", "7"},
+		{"now with layout", `{{now "2006"}}`, strconv.Itoa(time.Now().UTC().Year())},
+		{"unknown function kept", "{{sign payload}}", "{{sign payload}}"},
+		{"plain variable kept", "{{base_url}}/users", "{{base_url}}/users"},
+		{"wrong arguments kept", "{{randomInt 10}} {{randomInt 5 1}} {{uuid extra}}", "{{randomInt 10}} {{randomInt 5 1}} {{uuid extra}}"},
+		{"unterminated quote kept", `{{base64 "abc}}`, `{{base64 "abc}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := applyTemplateFunctions(tt.input); got != tt.want {
+				t.Errorf("applyTemplateFunctions(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyTemplateFunctionsGenerated(t *testing.T) {
+	got := applyTemplateFunctions("{{uuid}}|{{uuid}}|{{timestamp}}|{{now}}|{{randomInt -5 5}}")
+	parts := strings.Split(got, "|")
+	if len(parts) != 5 {
+		t.Fatalf("Unexpected result %q", got)
+	}
+
+	uuidPattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	if !uuidPattern.MatchString(parts[0]) || parts[0] == parts[1] {
+		t.Errorf("Expected two different version 4 UUIDs, got %q and %q", parts[0], parts[1])
+	}
+	if seconds, err := strconv.ParseInt(parts[2], 10, 64); err != nil || time.Since(time.Unix(seconds, 0)) > time.Minute {
+		t.Errorf("Expected a current Unix timestamp, got %q", parts[2])
+	}
+	if _, err := time.Parse(time.RFC3339, parts[3]); err != nil {
+		t.Errorf("Expected an RFC 3339 time, got %q", parts[3])
+	}
+	if n, err := strconv.Atoi(parts[4]); err != nil || n < -5 || n > 5 {
+		t.Errorf("Expected an integer from -5 to 5, got %q", parts[4])
+	}
+}
+
+func TestProcessRequestAppliesFunctions(t *testing.T) {
+	manager := newTestManager(t)
+	env := createTestEnvironment(t, manager, "Dev", "", map[string]string{
+		"base_url": "http://api.onion",
+		"user":     "alice",
+		"password": "pw",
+		"uuid":     "fixed-id", // a variable wins over the function
+	})
+	if err := manager.SetActiveEnvironment(env.ID); err != nil {
+		t.Fatalf("Failed to activate environment: %v", err)
+	}
+
+	req := api.NewRequest("POST", "/items?n={{randomInt 3 3}}")
+	req.SetHeader("Authorization", `Basic {{base64 "{{user}}:{{password}}"}}`)
+	req.SetHeader("X-Request-Id", "{{uuid}}")
+	req.SetBody(`{"at": {{timestamp}}, "sig": "{{unknown thing}}"}`)
+
+	processed := manager.ProcessRequest(req)
+	if processed.URL != "http://api.onion/items?n=3" {
+		t.Errorf("URL = %q", processed.URL)
+	}
+	if got := processed.Headers["Authorization"]; got != "Basic YWxpY2U6cHc=" {
+		t.Errorf("Authorization = %q", got)
+	}
+	if got := processed.Headers["X-Request-Id"]; got != "fixed-id" {
+		t.Errorf("Expected the uuid variable to win, got %q", got)
+	}
+	if strings.Contains(processed.Body, "{{timestamp}}") || !strings.Contains(processed.Body, `"sig": "{{unknown thing}}"`) {
+		t.Errorf("Body = %q", processed.Body)
+	}
+}
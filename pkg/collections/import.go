@@ -0,0 +1,121 @@
+package collections
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ProgressReporter receives progress updates while an import is running
+type ProgressReporter interface {
+	Report(done, total int)
+}
+
+// ProgressFunc adapts a plain function to the ProgressReporter interface
+type ProgressFunc func(done, total int)
+
+// Report calls f(done, total)
+func (f ProgressFunc) Report(done, total int) {
+	f(done, total)
+}
+
+// reportProgress reports progress if a reporter is set
+func reportProgress(progress ProgressReporter, done, total int) {
+	if progress != nil {
+		progress.Report(done, total)
+	}
+}
+
+// ImportCollection imports a collection from an OnionCLI collection JSON
+// file, a bundle or a Postman v2.1 export. A bundle's environment is created
+// too and named in the collection's ImportEnvironment. Progress is reported
+// per parsed request and the import stops early if ctx is cancelled.
+func (m *Manager) ImportCollection(ctx context.Context, filename string, progress ProgressReporter) (*Collection, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read import file: %w", err)
+	}
+
+	// Bundles carry an environment alongside the collection
+	if isBundle(data) {
+		var bundle Bundle
+		if err := json.Unmarshal(data, &bundle); err != nil {
+			return nil, fmt.Errorf("failed to parse bundle: %w", err)
+		}
+		reportProgress(progress, 0, len(bundle.Collection.Requests))
+		collection, env, err := m.importBundle(&bundle)
+		if err != nil {
+			return nil, err
+		}
+		if env != nil {
+			collection.ImportEnvironment = env.Name
+		}
+		reportProgress(progress, len(collection.Requests), len(collection.Requests))
+		return collection, nil
+	}
+
+	if isPostman(data) {
+		return m.importPostman(ctx, data, progress)
+	}
+
+	var imported Collection
+	if err := json.Unmarshal(data, &imported); err != nil {
+		return nil, fmt.Errorf("failed to parse collection: %w", err)
+	}
+
+	collection := Collection{
+		ID:             generateID(),
+		Name:           imported.Name,
+		Description:    imported.Description,
+		Requests:       make([]CollectionRequest, 0, len(imported.Requests)),
+		Variables:      imported.Variables,
+		Auth:           imported.Auth,
+		DefaultHeaders: imported.DefaultHeaders,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+	if collection.Variables == nil {
+		collection.Variables = make(map[string]string)
+	}
+
+	total := len(imported.Requests)
+	reportProgress(progress, 0, total)
+
+	for i, req := range imported.Requests {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("import cancelled: %w", err)
+		}
+
+		req.ID = generateID()
+		if req.Headers == nil {
+			req.Headers = make(map[string]string)
+		}
+		if req.CreatedAt.IsZero() {
+			req.CreatedAt = time.Now()
+		}
+		collection.Requests = append(collection.Requests, req)
+
+		reportProgress(progress, i+1, total)
+	}
+
+	return m.addImportedCollection(&collection)
+}
+
+// addImportedCollection stores and persists a freshly imported collection
+func (m *Manager) addImportedCollection(collection *Collection) (*Collection, error) {
+	if collection.Name == "" {
+		collection.Name = "Imported Collection"
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.collections = append(m.collections, *collection)
+	if err := m.SaveCollection(collection); err != nil {
+		return nil, fmt.Errorf("failed to save imported collection: %w", err)
+	}
+
+	stored := copyCollection(collection)
+	return &stored, nil
+}
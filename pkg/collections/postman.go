@@ -0,0 +1,548 @@
+package collections
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"onioncli/pkg/api"
+)
+
+// PostmanSchema identifies the Postman Collection v2.1 format
+const PostmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// postmanCollection is a Postman Collection v2.1 document
+type postmanCollection struct {
+	Info     postmanInfo       `json:"info"`
+	Item     []postmanItem     `json:"item"`
+	Auth     *postmanAuth      `json:"auth,omitempty"`
+	Variable []postmanKeyValue `json:"variable,omitempty"`
+}
+
+type postmanInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Schema      string `json:"schema"`
+}
+
+type postmanItem struct {
+	Name    string         `json:"name"`
+	Request postmanRequest `json:"request"`
+}
+
+type postmanRequest struct {
+	Method      string            `json:"method"`
+	Header      []postmanKeyValue `json:"header"`
+	Body        *postmanBody      `json:"body,omitempty"`
+	URL         postmanURL        `json:"url"`
+	Auth        *postmanAuth      `json:"auth,omitempty"`
+	Description string            `json:"description,omitempty"`
+}
+
+type postmanURL struct {
+	Raw string `json:"raw"`
+}
+
+type postmanBody struct {
+	Mode    string              `json:"mode"`
+	Raw     string              `json:"raw"`
+	Options *postmanBodyOptions `json:"options,omitempty"`
+}
+
+type postmanBodyOptions struct {
+	Raw struct {
+		Language string `json:"language"`
+	} `json:"raw"`
+}
+
+// postmanAuth holds the attributes of one auth type under the type's name,
+// e.g. {"type": "bearer", "bearer": [{"key": "token", ...}]}
+type postmanAuth struct {
+	Type   string            `json:"type"`
+	Basic  []postmanKeyValue `json:"basic,omitempty"`
+	Bearer []postmanKeyValue `json:"bearer,omitempty"`
+	APIKey []postmanKeyValue `json:"apikey,omitempty"`
+}
+
+type postmanKeyValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Type  string `json:"type,omitempty"`
+}
+
+// ExportPostman writes a collection as a Postman Collection v2.1 file, so it
+// can be imported into Postman. {{var}} placeholders are already Postman's
+// syntax and are kept as they are. Credentials in the collection's auth are
+// included, so the file is readable only by the user; auth types Postman
+// can't represent the same way are left out with a note in the description.
+func (m *Manager) ExportPostman(collectionID string, filename string) error {
+	collection, err := m.GetCollection(collectionID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(toPostman(collection), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal Postman collection: %w", err)
+	}
+
+	return os.WriteFile(filename, data, 0600)
+}
+
+// toPostman converts a collection to the Postman document written for it
+func toPostman(collection *Collection) postmanCollection {
+	doc := postmanCollection{
+		Info: postmanInfo{
+			Name:        collection.Name,
+			Description: withSkippedAuthNote(collection.Description, collection.Auth),
+			Schema:      PostmanSchema,
+		},
+		Item: make([]postmanItem, 0, len(collection.Requests)),
+		Auth: toPostmanAuth(collection.Auth),
+	}
+
+	for _, key := range sortedKeys(collection.Variables) {
+		doc.Variable = append(doc.Variable, postmanKeyValue{Key: key, Value: collection.Variables[key]})
+	}
+
+	// Postman has no collection-level headers, so the defaults go on each item
+	for _, req := range collection.Requests {
+		req.Headers = collection.HeadersFor(&req)
+		doc.Item = append(doc.Item, toPostmanItem(req))
+	}
+
+	return doc
+}
+
+// toPostmanItem converts one collection request
+func toPostmanItem(req CollectionRequest) postmanItem {
+	name := req.Name
+	if name == "" {
+		name = req.Method + " " + req.URL
+	}
+
+	request := postmanRequest{
+		Method:      req.Method,
+		Header:      make([]postmanKeyValue, 0, len(req.Headers)),
+		URL:         postmanURL{Raw: req.URL},
+		Auth:        toPostmanAuth(req.Auth),
+		Description: withSkippedAuthNote(req.Description, req.Auth),
+	}
+
+	contentType := ""
+	for _, key := range sortedKeys(req.Headers) {
+		request.Header = append(request.Header, postmanKeyValue{Key: key, Value: req.Headers[key], Type: "text"})
+		if strings.EqualFold(key, "Content-Type") {
+			contentType = req.Headers[key]
+		}
+	}
+
+	if req.Body != "" {
+		request.Body = &postmanBody{Mode: "raw", Raw: req.Body}
+		// Postman highlights raw bodies by language; JSON is the one it acts on
+		if strings.Contains(strings.ToLower(contentType), "json") || json.Valid([]byte(req.Body)) {
+			request.Body.Options = &postmanBodyOptions{}
+			request.Body.Options.Raw.Language = "json"
+		}
+	}
+
+	return postmanItem{Name: name, Request: request}
+}
+
+// toPostmanAuth maps auth to Postman's auth object. Basic, bearer and API key
+// auth have direct Postman equivalents; custom header auth and OAuth2 don't
+// map onto a single Postman auth type and are skipped, so the request falls
+// back to whatever auth Postman inherits from its parent.
+func toPostmanAuth(auth *api.AuthConfig) *postmanAuth {
+	if auth == nil {
+		return nil
+	}
+
+	switch auth.Type {
+	case api.AuthBasic:
+		return &postmanAuth{Type: "basic", Basic: []postmanKeyValue{
+			{Key: "username", Value: auth.Username, Type: "string"},
+			{Key: "password", Value: auth.Password, Type: "string"},
+		}}
+	case api.AuthBearer:
+		return &postmanAuth{Type: "bearer", Bearer: []postmanKeyValue{
+			{Key: "token", Value: auth.Token, Type: "string"},
+		}}
+	case api.AuthAPIKey:
+		keyName := auth.KeyName
+		if keyName == "" {
+			keyName = "X-API-Key"
+		}
+		in := "header"
+		if auth.Location == "query" {
+			in = "query"
+		}
+		return &postmanAuth{Type: "apikey", APIKey: []postmanKeyValue{
+			{Key: "key", Value: keyName, Type: "string"},
+			{Key: "value", Value: auth.APIKey, Type: "string"},
+			{Key: "in", Value: in, Type: "string"},
+		}}
+	case api.AuthNone:
+		return &postmanAuth{Type: "noauth"}
+	}
+
+	// Custom auth is a set of arbitrary headers and OAuth2 fetches its token
+	// at send time; neither fits one Postman auth object, so they are left
+	// out and withSkippedAuthNote says so in the description
+	return nil
+}
+
+// withSkippedAuthNote appends a note to description when toPostmanAuth drops
+// auth, so whoever imports the file knows to set it up in Postman
+func withSkippedAuthNote(description string, auth *api.AuthConfig) string {
+	if auth == nil || toPostmanAuth(auth) != nil {
+		return description
+	}
+	note := fmt.Sprintf("OnionCLI %s auth was not exported: Postman has no equivalent, so set it up by hand.", auth.Type)
+	if description == "" {
+		return note
+	}
+	return description + "\n\n" + note
+}
+
+// sortedKeys returns the keys of m in order, so exports are stable
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// postmanImportItem is an item as read from a Postman file: a request, or a
+// folder of further items. Fields that Postman writes in more than one shape
+// are kept raw and decoded per item, so one odd item can't fail the import.
+type postmanImportItem struct {
+	Name    string             `json:"name"`
+	Item    []json.RawMessage  `json:"item"`
+	Request json.RawMessage    `json:"request"`
+	Auth    *postmanImportAuth `json:"auth"`
+}
+
+type postmanImportRequest struct {
+	Method      string             `json:"method"`
+	Header      []postmanParam     `json:"header"`
+	Body        *postmanImportBody `json:"body"`
+	URL         json.RawMessage    `json:"url"`
+	Auth        *postmanImportAuth `json:"auth"`
+	Description json.RawMessage    `json:"description"`
+}
+
+type postmanImportBody struct {
+	Mode       string         `json:"mode"`
+	Raw        string         `json:"raw"`
+	URLEncoded []postmanParam `json:"urlencoded"`
+}
+
+type postmanImportAuth struct {
+	Type   string         `json:"type"`
+	Basic  []postmanParam `json:"basic"`
+	Bearer []postmanParam `json:"bearer"`
+	APIKey []postmanParam `json:"apikey"`
+	OAuth2 []postmanParam `json:"oauth2"`
+}
+
+// postmanParam is a key/value entry: a header, form field, auth attribute or
+// variable. Values may be any JSON type.
+type postmanParam struct {
+	Key      string          `json:"key"`
+	Value    json.RawMessage `json:"value"`
+	Disabled bool            `json:"disabled"`
+}
+
+// value returns the param's value as text, JSON strings unquoted
+func (p postmanParam) value() string {
+	return postmanText(p.Value)
+}
+
+// ImportPostman imports a Postman Collection v2.1 file. Folders are
+// flattened, each request named after its folder path ("Users / Create").
+// Raw and URL-encoded bodies are kept; other body modes, such as form-data
+// with files, are left out. Items that can't be read are skipped rather than
+// failing the import, and counted in the collection's ImportSkipped.
+func (m *Manager) ImportPostman(filename string) (*Collection, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Postman file: %w", err)
+	}
+
+	return m.importPostman(context.Background(), data, nil)
+}
+
+// importPostman stores the collection in a parsed Postman file
+func (m *Manager) importPostman(ctx context.Context, data []byte, progress ProgressReporter) (*Collection, error) {
+	var doc struct {
+		Info struct {
+			Name        string          `json:"name"`
+			Description json.RawMessage `json:"description"`
+			Schema      string          `json:"schema"`
+		} `json:"info"`
+		Item     []json.RawMessage  `json:"item"`
+		Auth     *postmanImportAuth `json:"auth"`
+		Variable []postmanParam     `json:"variable"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse Postman collection: %w", err)
+	}
+	if !strings.Contains(doc.Info.Schema, "/collection/v2.1") {
+		return nil, fmt.Errorf("unsupported Postman collection schema %q: only v2.1 can be imported", doc.Info.Schema)
+	}
+
+	collection := Collection{
+		ID:          generateID(),
+		Name:        doc.Info.Name,
+		Description: postmanText(doc.Info.Description),
+		Requests:    make([]CollectionRequest, 0, len(doc.Item)),
+		Variables:   make(map[string]string),
+		Auth:        fromPostmanAuth(doc.Auth),
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	for _, variable := range doc.Variable {
+		if variable.Key != "" && !variable.Disabled {
+			collection.Variables[variable.Key] = variable.value()
+		}
+	}
+
+	items, skipped := flattenPostmanItems(doc.Item, "", nil)
+	reportProgress(progress, 0, len(items))
+	for i, item := range items {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("import cancelled: %w", err)
+		}
+		if req, ok := item.toCollectionRequest(); ok {
+			collection.Requests = append(collection.Requests, req)
+		} else {
+			skipped++
+		}
+		reportProgress(progress, i+1, len(items))
+	}
+
+	stored, err := m.addImportedCollection(&collection)
+	if err != nil {
+		return nil, err
+	}
+	stored.ImportSkipped = skipped
+	return stored, nil
+}
+
+// postmanLeaf is a request item found while flattening folders, with the
+// name and auth it gets from them
+type postmanLeaf struct {
+	name    string
+	request json.RawMessage
+	auth    *postmanImportAuth // nearest auth set on the item or a folder above it
+}
+
+// flattenPostmanItems lists the request items under items, depth first,
+// and counts the items dropped for being neither a folder nor a request
+func flattenPostmanItems(items []json.RawMessage, prefix string, auth *postmanImportAuth) (leaves []postmanLeaf, skipped int) {
+	for _, raw := range items {
+		var item postmanImportItem
+		if err := json.Unmarshal(raw, &item); err != nil {
+			skipped++
+			continue
+		}
+
+		name := item.Name
+		if prefix != "" {
+			name = prefix + " / " + name
+		}
+		itemAuth := auth
+		if item.Auth != nil {
+			itemAuth = item.Auth
+		}
+
+		switch {
+		case item.Item != nil:
+			folder, folderSkipped := flattenPostmanItems(item.Item, name, itemAuth)
+			leaves = append(leaves, folder...)
+			skipped += folderSkipped
+		case len(item.Request) > 0:
+			leaves = append(leaves, postmanLeaf{name: name, request: item.Request, auth: itemAuth})
+		default:
+			skipped++
+		}
+	}
+	return leaves, skipped
+}
+
+// toCollectionRequest converts a request item, reporting false if it has no
+// usable URL or can't be read
+func (leaf postmanLeaf) toCollectionRequest() (CollectionRequest, bool) {
+	var request postmanImportRequest
+	// A request may be written as just its URL, sent with GET
+	var urlOnly string
+	if err := json.Unmarshal(leaf.request, &urlOnly); err == nil {
+		request.URL = leaf.request
+	} else if err := json.Unmarshal(leaf.request, &request); err != nil {
+		return CollectionRequest{}, false
+	}
+
+	rawURL, ok := postmanURLText(request.URL)
+	if !ok || rawURL == "" {
+		return CollectionRequest{}, false
+	}
+
+	method := strings.ToUpper(strings.TrimSpace(request.Method))
+	if method == "" {
+		method = "GET"
+	}
+
+	req := CollectionRequest{
+		ID:          generateID(),
+		Name:        leaf.name,
+		Description: postmanText(request.Description),
+		Method:      method,
+		URL:         rawURL,
+		Headers:     make(map[string]string),
+		Body:        request.Body.text(),
+		CreatedAt:   time.Now(),
+	}
+	if req.Name == "" {
+		req.Name = method + " " + rawURL
+	}
+
+	for _, header := range request.Header {
+		if header.Key != "" && !header.Disabled {
+			req.Headers[header.Key] = header.value()
+		}
+	}
+
+	// The request's own auth wins, then the nearest folder's; "inherit"
+	// means the same as leaving it unset
+	auth := request.Auth
+	if auth == nil || auth.Type == "inherit" {
+		auth = leaf.auth
+	}
+	req.Auth = fromPostmanAuth(auth)
+
+	return req, true
+}
+
+// text returns a body as OnionCLI sends it: raw text as it is, URL-encoded
+// fields encoded in order
+func (b *postmanImportBody) text() string {
+	if b == nil {
+		return ""
+	}
+
+	switch b.Mode {
+	case "raw":
+		return b.Raw
+	case "urlencoded":
+		var pairs []string
+		for _, field := range b.URLEncoded {
+			if !field.Disabled {
+				pairs = append(pairs, url.QueryEscape(field.Key)+"="+url.QueryEscape(field.value()))
+			}
+		}
+		return strings.Join(pairs, "&")
+	}
+	return ""
+}
+
+// fromPostmanAuth maps a Postman auth block to an auth config. Basic,
+// bearer, API key and client-credentials OAuth2 map directly; "noauth"
+// turns auth off, and anything else is dropped.
+func fromPostmanAuth(auth *postmanImportAuth) *api.AuthConfig {
+	if auth == nil {
+		return nil
+	}
+
+	switch auth.Type {
+	case "basic":
+		attrs := postmanAttrs(auth.Basic)
+		return &api.AuthConfig{Type: api.AuthBasic, Username: attrs["username"], Password: attrs["password"]}
+	case "bearer":
+		return &api.AuthConfig{Type: api.AuthBearer, Token: postmanAttrs(auth.Bearer)["token"]}
+	case "apikey":
+		attrs := postmanAttrs(auth.APIKey)
+		config := &api.AuthConfig{Type: api.AuthAPIKey, KeyName: attrs["key"], APIKey: attrs["value"], Location: "header"}
+		if attrs["in"] == "query" {
+			config.Location = "query"
+		}
+		return config
+	case "oauth2":
+		attrs := postmanAttrs(auth.OAuth2)
+		if attrs["grant_type"] != "client_credentials" {
+			return nil
+		}
+		return &api.AuthConfig{
+			Type:         api.AuthOAuth2,
+			TokenURL:     attrs["accessTokenUrl"],
+			ClientID:     attrs["clientId"],
+			ClientSecret: attrs["clientSecret"],
+			Scope:        attrs["scope"],
+		}
+	case "noauth":
+		return &api.AuthConfig{Type: api.AuthNone}
+	}
+
+	return nil
+}
+
+// postmanAttrs indexes auth attributes by key
+func postmanAttrs(params []postmanParam) map[string]string {
+	attrs := make(map[string]string, len(params))
+	for _, param := range params {
+		attrs[param.Key] = param.value()
+	}
+	return attrs
+}
+
+// postmanURLText returns a request URL, written either as a string or as an
+// object holding it in raw
+func postmanURLText(raw json.RawMessage) (string, bool) {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return strings.TrimSpace(text), true
+	}
+	var object struct {
+		Raw string `json:"raw"`
+	}
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(object.Raw), true
+}
+
+// postmanText returns a JSON value as text: strings unquoted, description
+// objects as their content, anything else as written
+func postmanText(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	var description struct {
+		Content *string `json:"content"`
+	}
+	if err := json.Unmarshal(raw, &description); err == nil && description.Content != nil {
+		return *description.Content
+	}
+	return string(raw)
+}
+
+// isPostman reports whether raw JSON data looks like a Postman collection
+func isPostman(data []byte) bool {
+	var probe struct {
+		Info struct {
+			Schema string `json:"schema"`
+		} `json:"info"`
+	}
+	return json.Unmarshal(data, &probe) == nil && strings.Contains(probe.Info.Schema, "schema.getpostman.com")
+}
@@ -0,0 +1,267 @@
+package collections
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"onioncli/pkg/api"
+)
+
+func TestExportPostman(t *testing.T) {
+	manager := newTestManager(t)
+
+	collection := manager.CreateCollection("Users API", "User endpoints")
+	create := api.NewRequest("POST", "{{base_url}}/users")
+	create.SetHeader("Content-Type", "application/json")
+	create.SetBody(`{"name": "alice"}`)
+	if err := manager.AddRequestToCollection(collection.ID, create, "Create user", "Adds a user"); err != nil {
+		t.Fatalf("Failed to add request: %v", err)
+	}
+	list := api.NewRequest("GET", "{{base_url}}/users")
+	if err := manager.AddRequestToCollection(collection.ID, list, "List users", ""); err != nil {
+		t.Fatalf("Failed to add request: %v", err)
+	}
+
+	collection = editCollection(t, manager, collection.ID, func(collection *Collection) {
+		collection.Variables["base_url"] = "http://users.onion"
+		collection.Auth = &api.AuthConfig{Type: api.AuthBearer, Token: "{{api_token}}"}
+		collection.Requests[0].Auth = &api.AuthConfig{Type: api.AuthAPIKey, APIKey: "secret", KeyName: "api_key", Location: "query"}
+		collection.Requests[1].Auth = &api.AuthConfig{Type: api.AuthCustom, Custom: map[string]string{"X-Session": "abc"}}
+	})
+
+	filename := filepath.Join(t.TempDir(), "users.postman_collection.json")
+	if err := manager.ExportPostman(collection.ID, filename); err != nil {
+		t.Fatalf("ExportPostman failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("Failed to read export: %v", err)
+	}
+	var doc postmanCollection
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Export is not valid JSON: %v", err)
+	}
+
+	if doc.Info.Name != "Users API" || doc.Info.Schema != PostmanSchema {
+		t.Errorf("Unexpected info: %+v", doc.Info)
+	}
+	if len(doc.Variable) != 1 || doc.Variable[0] != (postmanKeyValue{Key: "base_url", Value: "http://users.onion"}) {
+		t.Errorf("Unexpected variables: %+v", doc.Variable)
+	}
+	if doc.Auth == nil || doc.Auth.Type != "bearer" || doc.Auth.Bearer[0].Value != "{{api_token}}" {
+		t.Errorf("Expected collection bearer auth, got %+v", doc.Auth)
+	}
+	if len(doc.Item) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(doc.Item))
+	}
+
+	created := doc.Item[0].Request
+	if doc.Item[0].Name != "Create user" || created.Method != "POST" || created.URL.Raw != "{{base_url}}/users" {
+		t.Errorf("Unexpected first item: %+v", doc.Item[0])
+	}
+	if created.Description != "Adds a user" {
+		t.Errorf("Expected description to be kept, got %q", created.Description)
+	}
+	if len(created.Header) != 1 || created.Header[0].Key != "Content-Type" || created.Header[0].Value != "application/json" {
+		t.Errorf("Unexpected headers: %+v", created.Header)
+	}
+	if created.Body == nil || created.Body.Mode != "raw" || created.Body.Raw != `{"name": "alice"}` ||
+		created.Body.Options == nil || created.Body.Options.Raw.Language != "json" {
+		t.Errorf("Unexpected body: %+v", created.Body)
+	}
+	wantAPIKey := []postmanKeyValue{
+		{Key: "key", Value: "api_key", Type: "string"},
+		{Key: "value", Value: "secret", Type: "string"},
+		{Key: "in", Value: "query", Type: "string"},
+	}
+	if created.Auth == nil || created.Auth.Type != "apikey" || len(created.Auth.APIKey) != 3 {
+		t.Fatalf("Expected apikey auth, got %+v", created.Auth)
+	}
+	for i, want := range wantAPIKey {
+		if created.Auth.APIKey[i] != want {
+			t.Errorf("apikey[%d] = %+v, want %+v", i, created.Auth.APIKey[i], want)
+		}
+	}
+
+	listed := doc.Item[1].Request
+	if listed.Auth != nil {
+		t.Errorf("Expected custom auth to be skipped, got %+v", listed.Auth)
+	}
+	if !strings.Contains(listed.Description, "custom auth was not exported") {
+		t.Errorf("Expected a note about the skipped custom auth, got %q", listed.Description)
+	}
+	if created.Description != "Adds a user" {
+		t.Errorf("Expected no note on a request whose auth was exported, got %q", created.Description)
+	}
+	if info, err := os.Stat(filename); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Export file mode = %v, %v, want 0600", info.Mode().Perm(), err)
+	}
+	if listed.Body != nil {
+		t.Errorf("Expected no body for GET, got %+v", listed.Body)
+	}
+}
+
+func TestExportPostmanUnknownCollection(t *testing.T) {
+	manager := newTestManager(t)
+	if err := manager.ExportPostman("missing", filepath.Join(t.TempDir(), "out.json")); err == nil {
+		t.Error("Expected an error for an unknown collection")
+	}
+}
+
+func TestImportPostman(t *testing.T) {
+	manager := newTestManager(t)
+
+	doc := `{
+  "info": {"name": "Shop", "description": {"content": "Shop API"}, "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"},
+  "variable": [{"key": "base_url", "value": "http://shop.onion"}, {"key": "retries", "value": 3}, {"key": "off", "value": "x", "disabled": true}],
+  "auth": {"type": "bearer", "bearer": [{"key": "token", "value": "{{token}}", "type": "string"}]},
+  "item": [
+    {"name": "Orders", "auth": {"type": "basic", "basic": [{"key": "username", "value": "alice"}, {"key": "password", "value": "pw"}]}, "item": [
+      {"name": "Create", "request": {
+        "method": "post",
+        "header": [{"key": "Content-Type", "value": "application/json"}, {"key": "X-Debug", "value": "1", "disabled": true}],
+        "body": {"mode": "raw", "raw": "{\"sku\": \"{{sku}}\"}"},
+        "url": {"raw": "{{base_url}}/orders", "host": ["{{base_url}}"], "path": ["orders"]}
+      }},
+      {"name": "Search", "request": {
+        "method": "POST",
+        "body": {"mode": "urlencoded", "urlencoded": [{"key": "q", "value": "a b"}, {"key": "skip", "value": "1", "disabled": true}]},
+        "url": "{{base_url}}/orders/search",
+        "auth": {"type": "apikey", "apikey": [{"key": "key", "value": "api_key"}, {"key": "value", "value": "k"}, {"key": "in", "value": "query"}]}
+      }}
+    ]},
+    {"name": "Health", "request": "{{base_url}}/health"},
+    {"name": "No URL", "request": {"method": "GET"}},
+    {"name": "Broken", "request": {"method": 42, "url": "http://x"}},
+    "not an item"
+  ]
+}`
+	filename := filepath.Join(t.TempDir(), "shop.postman_collection.json")
+	if err := os.WriteFile(filename, []byte(doc), 0644); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+
+	collection, err := manager.ImportPostman(filename)
+	if err != nil {
+		t.Fatalf("ImportPostman failed: %v", err)
+	}
+
+	if collection.Name != "Shop" || collection.Description != "Shop API" {
+		t.Errorf("Unexpected collection: %q, %q", collection.Name, collection.Description)
+	}
+	wantVariables := map[string]string{"base_url": "http://shop.onion", "retries": "3"}
+	if !reflect.DeepEqual(collection.Variables, wantVariables) {
+		t.Errorf("Variables = %v, want %v", collection.Variables, wantVariables)
+	}
+	if collection.Auth == nil || collection.Auth.Type != api.AuthBearer || collection.Auth.Token != "{{token}}" {
+		t.Errorf("Expected collection bearer auth, got %+v", collection.Auth)
+	}
+
+	if len(collection.Requests) != 3 {
+		t.Fatalf("Expected 3 requests with malformed items skipped, got %d: %+v", len(collection.Requests), collection.Requests)
+	}
+	if collection.ImportSkipped != 3 {
+		t.Errorf("ImportSkipped = %d, want 3", collection.ImportSkipped)
+	}
+
+	create := collection.Requests[0]
+	if create.Name != "Orders / Create" || create.Method != "POST" || create.URL != "{{base_url}}/orders" {
+		t.Errorf("Unexpected first request: %+v", create)
+	}
+	if !reflect.DeepEqual(create.Headers, map[string]string{"Content-Type": "application/json"}) {
+		t.Errorf("Expected disabled headers to be dropped, got %v", create.Headers)
+	}
+	if create.Body != `{"sku": "{{sku}}"}` {
+		t.Errorf("Body = %q", create.Body)
+	}
+	if create.Auth == nil || create.Auth.Type != api.AuthBasic || create.Auth.Username != "alice" || create.Auth.Password != "pw" {
+		t.Errorf("Expected folder basic auth to be inherited, got %+v", create.Auth)
+	}
+
+	search := collection.Requests[1]
+	if search.Body != "q=a+b" {
+		t.Errorf("Expected URL-encoded body, got %q", search.Body)
+	}
+	if search.Auth == nil || search.Auth.Type != api.AuthAPIKey || search.Auth.KeyName != "api_key" ||
+		search.Auth.APIKey != "k" || search.Auth.Location != "query" {
+		t.Errorf("Unexpected apikey auth: %+v", search.Auth)
+	}
+
+	health := collection.Requests[2]
+	if health.Name != "Health" || health.Method != "GET" || health.URL != "{{base_url}}/health" || health.Auth != nil {
+		t.Errorf("Unexpected URL-only request: %+v", health)
+	}
+
+	if _, err := manager.GetCollection(collection.ID); err != nil {
+		t.Errorf("Imported collection was not stored: %v", err)
+	}
+}
+
+func TestImportPostmanRejectsOtherFiles(t *testing.T) {
+	manager := newTestManager(t)
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"not JSON", "{", "failed to parse Postman collection"},
+		{"v2.0 schema", `{"info": {"name": "Old", "schema": "https://schema.getpostman.com/json/collection/v2.0.0/collection.json"}}`, "only v2.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(dir, "collection.json")
+			if err := os.WriteFile(filename, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write fixture: %v", err)
+			}
+			_, err := manager.ImportPostman(filename)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ImportPostman() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPostmanRoundTrip(t *testing.T) {
+	manager := newTestManager(t)
+
+	collection := manager.CreateCollection("Round Trip", "")
+	req := api.NewRequest("PUT", "{{base_url}}/items/1")
+	req.SetHeader("Accept", "application/json")
+	req.SetBody(`{"done": true}`)
+	if err := manager.AddRequestToCollection(collection.ID, req, "Update item", ""); err != nil {
+		t.Fatalf("Failed to add request: %v", err)
+	}
+	collection = editCollection(t, manager, collection.ID, func(collection *Collection) {
+		collection.Requests[0].Auth = &api.AuthConfig{Type: api.AuthBasic, Username: "bob", Password: "secret"}
+	})
+
+	filename := filepath.Join(t.TempDir(), "round-trip.postman_collection.json")
+	if err := manager.ExportPostman(collection.ID, filename); err != nil {
+		t.Fatalf("ExportPostman failed: %v", err)
+	}
+
+	// Through ImportCollection, as the collections screen imports files
+	imported, err := manager.ImportCollection(context.Background(), filename, nil)
+	if err != nil {
+		t.Fatalf("ImportCollection failed: %v", err)
+	}
+	if imported.ID == collection.ID || len(imported.Requests) != 1 {
+		t.Fatalf("Unexpected imported collection: %+v", imported)
+	}
+
+	got := imported.Requests[0]
+	want := collection.Requests[0]
+	if got.Name != want.Name || got.Method != want.Method || got.URL != want.URL || got.Body != want.Body ||
+		!reflect.DeepEqual(got.Headers, want.Headers) || !reflect.DeepEqual(got.Auth, want.Auth) {
+		t.Errorf("Round trip changed the request:\n got %+v\nwant %+v", got, want)
+	}
+}
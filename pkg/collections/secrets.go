@@ -0,0 +1,119 @@
+package collections
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/zalando/go-keyring"
+)
+
+// secretService is the keyring service an environment's secret variables
+// are kept under; it is shared with the environment's stored auth
+func secretService(envID string) string {
+	return "environment-" + envID
+}
+
+// secretAccount is the keyring account a secret variable is kept under
+func secretAccount(name string) string {
+	return "variable:" + name
+}
+
+// IsSecret reports whether the variable name's value is kept in the system
+// keyring rather than in Variables
+func (e *Environment) IsSecret(name string) bool {
+	for _, key := range e.SecretKeys {
+		if key == name {
+			return true
+		}
+	}
+	return false
+}
+
+// storeSecrets stores the values of the secret variables among variables in
+// the keyring of the environment with envID. It returns the variables to
+// keep on disk, with secret values emptied, and the sorted secret keys,
+// ignoring those variables does not set. An empty secret value keeps the
+// value already stored.
+func (m *Manager) storeSecrets(envID string, variables map[string]string, secretKeys []string) (map[string]string, []string, error) {
+	stored := make(map[string]string, len(variables))
+	for key, value := range variables {
+		stored[key] = value
+	}
+
+	var keys []string
+	seen := make(map[string]bool, len(secretKeys))
+	for _, key := range secretKeys {
+		value, ok := variables[key]
+		if !ok || seen[key] {
+			continue
+		}
+		seen[key] = true
+		if value != "" {
+			if err := m.secrets.StoreCredentials(secretService(envID), secretAccount(key), value); err != nil {
+				return nil, nil, fmt.Errorf("failed to store %s in the keyring: %w", key, err)
+			}
+		}
+		stored[key] = ""
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return stored, keys, nil
+}
+
+// deleteSecrets removes the keyring entries of the secret variables in
+// previous that env no longer has. Entries already gone are not an error.
+func (m *Manager) deleteSecrets(env *Environment, previous []string) error {
+	for _, key := range previous {
+		if env.IsSecret(key) {
+			continue
+		}
+		err := m.secrets.DeleteCredentials(secretService(env.ID), secretAccount(key))
+		if err != nil && err != keyring.ErrNotFound {
+			return fmt.Errorf("failed to delete %s from the keyring: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// loadSecret reads a secret variable's value from env's keyring
+func (m *Manager) loadSecret(env *Environment, name string) (string, error) {
+	value, err := m.secrets.GetCredentials(secretService(env.ID), secretAccount(name))
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s from the keyring: %w", name, err)
+	}
+	return value, nil
+}
+
+// SecretValues returns the value of every secret variable set in any
+// environment, so they can be masked wherever they might be shown
+func (m *Manager) SecretValues() []string {
+	var values []string
+	environments := m.GetEnvironments()
+	for i := range environments {
+		env := &environments[i]
+		for _, key := range env.SecretKeys {
+			if value, err := m.loadSecret(env, key); err == nil && value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// EnvironmentVariables returns a copy of env's variables with the values of
+// its secrets read from the keyring. A secret never given a value is empty.
+func (m *Manager) EnvironmentVariables(env *Environment) (map[string]string, error) {
+	variables := make(map[string]string, len(env.Variables))
+	for key, value := range env.Variables {
+		if env.IsSecret(key) {
+			secret, err := m.loadSecret(env, key)
+			if err != nil && !errors.Is(err, keyring.ErrNotFound) {
+				return nil, err
+			}
+			value = secret
+		}
+		variables[key] = value
+	}
+	return variables, nil
+}
@@ -0,0 +1,124 @@
+package collections
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+func TestSecretVariables(t *testing.T) {
+	keyring.MockInit()
+	manager := newTestManager(t)
+
+	env, err := manager.CreateEnvironment("Prod", "", map[string]string{"host": "prod.onion", "token": "s3cret"}, []string{"token", "missing"})
+	if err != nil {
+		t.Fatalf("CreateEnvironment() error = %v", err)
+	}
+	id := env.ID
+	if !reflect.DeepEqual(env.SecretKeys, []string{"token"}) {
+		t.Errorf("SecretKeys = %v, want [token]", env.SecretKeys)
+	}
+	if env.Variables["token"] != "" {
+		t.Errorf("secret value kept in Variables: %q", env.Variables["token"])
+	}
+	data, err := os.ReadFile(manager.envFile)
+	if err != nil {
+		t.Fatalf("Failed to read environments: %v", err)
+	}
+	if strings.Contains(string(data), "s3cret") {
+		t.Errorf("secret value written to disk:\n%s", data)
+	}
+
+	if got := manager.SubstituteWithEnvironment("{{host}} {{token}}", id); got != "prod.onion s3cret" {
+		t.Errorf("SubstituteWithEnvironment() = %q, want the secret resolved", got)
+	}
+	if got := manager.SecretValues(); !reflect.DeepEqual(got, []string{"s3cret"}) {
+		t.Errorf("SecretValues() = %q, want [s3cret]", got)
+	}
+
+	// An empty secret keeps its stored value
+	if err := manager.UpdateEnvironment(id, "Prod", "", map[string]string{"host": "prod.onion", "token": ""}, []string{"token"}); err != nil {
+		t.Fatalf("UpdateEnvironment() error = %v", err)
+	}
+	if got := manager.SubstituteWithEnvironment("{{token}}", id); got != "s3cret" {
+		t.Errorf("after keeping the secret, got %q", got)
+	}
+
+	clone, err := manager.DuplicateEnvironment(id)
+	if err != nil {
+		t.Fatalf("DuplicateEnvironment() error = %v", err)
+	}
+	if got := manager.SubstituteWithEnvironment("{{token}}", clone.ID); got != "s3cret" {
+		t.Errorf("duplicate resolved %q, want its own copy of the secret", got)
+	}
+
+	// No longer secret: the value is stored in the clear and the keyring
+	// entry is removed
+	if err := manager.UpdateEnvironment(id, "Prod", "", map[string]string{"token": "plain"}, nil); err != nil {
+		t.Fatalf("UpdateEnvironment() error = %v", err)
+	}
+	if got := manager.SubstituteWithEnvironment("{{token}}", id); got != "plain" {
+		t.Errorf("after unmarking the secret, got %q", got)
+	}
+	if _, err := keyring.Get("onioncli-"+secretService(id), secretAccount("token")); err != keyring.ErrNotFound {
+		t.Errorf("keyring entry after unmarking: err = %v, want ErrNotFound", err)
+	}
+	if got := manager.SubstituteWithEnvironment("{{token}}", clone.ID); got != "s3cret" {
+		t.Errorf("unmarking the original changed the duplicate's secret: %q", got)
+	}
+}
+
+func TestSecretVariableMissingFromKeyring(t *testing.T) {
+	keyring.MockInit()
+	manager := newTestManager(t)
+
+	env, err := manager.CreateEnvironment("Prod", "", map[string]string{"token": ""}, []string{"token"})
+	if err != nil {
+		t.Fatalf("CreateEnvironment() error = %v", err)
+	}
+	if got := manager.SubstituteWithEnvironment("Bearer {{token}}", env.ID); got != "Bearer {{token}}" {
+		t.Errorf("SubstituteWithEnvironment() = %q, want the placeholder left unresolved", got)
+	}
+	variables, err := manager.EnvironmentVariables(env)
+	if err != nil || variables["token"] != "" {
+		t.Errorf("EnvironmentVariables() = %v, %v, want an empty token", variables, err)
+	}
+}
+
+func TestDeleteEnvironment(t *testing.T) {
+	keyring.MockInit()
+	manager := newTestManager(t)
+
+	env, err := manager.CreateEnvironment("Prod", "", map[string]string{"token": "s3cret"}, []string{"token"})
+	if err != nil {
+		t.Fatalf("CreateEnvironment() error = %v", err)
+	}
+	id := env.ID
+
+	if err := manager.DeleteEnvironment(id); err != nil {
+		t.Fatalf("DeleteEnvironment() error = %v", err)
+	}
+	if manager.findEnvironment(id) != nil {
+		t.Error("Expected the environment to be gone")
+	}
+	if _, err := keyring.Get(secretService(id), secretAccount("token")); err != keyring.ErrNotFound {
+		t.Errorf("Expected the secret to be removed from the keyring, got %v", err)
+	}
+	if err := manager.DeleteEnvironment(id); err == nil {
+		t.Error("Expected error for unknown environment")
+	}
+
+	active, err := manager.CreateEnvironment("Dev", "", nil, nil)
+	if err != nil {
+		t.Fatalf("CreateEnvironment() error = %v", err)
+	}
+	if err := manager.SetActiveEnvironment(active.ID); err != nil {
+		t.Fatalf("SetActiveEnvironment() error = %v", err)
+	}
+	if err := manager.DeleteEnvironment(active.ID); err == nil {
+		t.Error("Expected the active environment to be kept")
+	}
+}
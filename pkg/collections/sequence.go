@@ -0,0 +1,189 @@
+package collections
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"onioncli/pkg/api"
+)
+
+// SequenceResult is the outcome of one request in a sequence run
+type SequenceResult struct {
+	RequestName string            `json:"request_name"`
+	Method      string            `json:"method"`
+	StatusCode  int               `json:"status_code,omitempty"`
+	Status      string            `json:"status,omitempty"`
+	Duration    time.Duration     `json:"duration"`
+	Extracted   map[string]string `json:"extracted,omitempty"` // variables set from this response
+	Tests       []TestResult      `json:"tests,omitempty"`
+	Error       string            `json:"error,omitempty"`
+}
+
+// Passed reports whether the request got a 2xx response, every value it
+// extracts was found and all of its tests passed
+func (r SequenceResult) Passed() bool {
+	if r.Error != "" || r.StatusCode < 200 || r.StatusCode >= 300 {
+		return false
+	}
+	for _, test := range r.Tests {
+		if !test.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// SequenceRun holds the results of running a collection's requests in order
+type SequenceRun struct {
+	CollectionName string            `json:"collection_name"`
+	Results        []SequenceResult  `json:"results"`
+	NotRun         []string          `json:"not_run,omitempty"` // requests skipped after the chain stopped
+	Variables      map[string]string `json:"variables,omitempty"`
+	Elapsed        time.Duration     `json:"elapsed"`
+}
+
+// Summary returns the number of passed and failed requests
+func (r *SequenceRun) Summary() (passed, failed int) {
+	for _, result := range r.Results {
+		if result.Passed() {
+			passed++
+		} else {
+			failed++
+		}
+	}
+	return passed, failed
+}
+
+// RunSequence runs a collection's requests in order. After each response,
+// the request's Extract paths are read from its JSON body into variables
+// that later requests in the run can use as {{name}}; they take precedence
+// over the active environment and last only for the run. The run stops at
+// the first request that fails, unless continueOnError is set. Per-request
+// failures are recorded in the results; only problems with the collection
+// or cancellation are returned as errors.
+func (m *Manager) RunSequence(ctx context.Context, collectionID string, client *api.Client, continueOnError bool, progress ProgressReporter) (*SequenceRun, error) {
+	collection, err := m.GetCollection(collectionID)
+	if err != nil {
+		return nil, err
+	}
+	if len(collection.Requests) == 0 {
+		return nil, fmt.Errorf("collection %s has no requests", collection.Name)
+	}
+	requests := make([]CollectionRequest, len(collection.Requests))
+	copy(requests, collection.Requests)
+	for i := range requests {
+		requests[i].Headers = collection.HeadersFor(&requests[i])
+		if requests[i].Auth == nil {
+			requests[i].Auth = collection.Auth
+		}
+	}
+
+	run := &SequenceRun{
+		CollectionName: collection.Name,
+		Results:        make([]SequenceResult, 0, len(requests)),
+		Variables:      make(map[string]string),
+	}
+	authManager := api.NewAuthManager()
+	authManager.SetClient(client)
+	started := time.Now()
+	defer func() { run.Elapsed = time.Since(started) }()
+
+	reportProgress(progress, 0, len(requests))
+	for i := range requests {
+		if err := ctx.Err(); err != nil {
+			return run, fmt.Errorf("run cancelled: %w", err)
+		}
+
+		result := m.runStep(ctx, client, authManager, &requests[i], run.Variables)
+		run.Results = append(run.Results, result)
+		reportProgress(progress, i+1, len(requests))
+
+		if !result.Passed() && !continueOnError {
+			for _, skipped := range requests[i+1:] {
+				run.NotRun = append(run.NotRun, skipped.Name)
+			}
+			break
+		}
+	}
+
+	return run, nil
+}
+
+// runStep sends one request of a sequence with the run's variables, then
+// checks its tests and adds the values it extracts to variables
+func (m *Manager) runStep(ctx context.Context, client *api.Client, authManager *api.AuthManager, req *CollectionRequest, variables map[string]string) SequenceResult {
+	result := SequenceResult{RequestName: req.Name, Method: req.Method}
+
+	resp, err := m.sendRow(ctx, client, authManager, req, variables)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.StatusCode = resp.StatusCode
+	result.Status = resp.Status
+	result.Duration = resp.Duration
+
+	if err := resp.ApplyPostProcessors(m.ResolvePostProcess(req.PostProcess)); err != nil {
+		result.Error = fmt.Sprintf("post-processing failed: %v", err)
+		return result
+	}
+	result.Tests = RunTests(req, resp)
+
+	extracted, err := extractVariables(req.Extract, resp.Body)
+	if err != nil {
+		result.Error = err.Error()
+	}
+	if len(extracted) > 0 {
+		result.Extracted = extracted
+		for name, value := range extracted {
+			variables[name] = value
+		}
+	}
+	return result
+}
+
+// extractVariables reads each variable's JSONPath from a JSON body. Values
+// that can't be found are reported together; the rest are still returned.
+func extractVariables(paths map[string]string, body string) (map[string]string, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	names := make([]string, 0, len(paths))
+	for name := range paths {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber() // keep large IDs exact
+	var data interface{}
+	if err := decoder.Decode(&data); err != nil {
+		return nil, fmt.Errorf("could not extract %s: response body is not JSON", strings.Join(names, ", "))
+	}
+
+	extracted := make(map[string]string, len(paths))
+	var problems []string
+	for _, name := range names {
+		steps, err := parseJSONPath(paths[name])
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		value, found := lookupJSONPath(data, steps)
+		if !found {
+			problems = append(problems, fmt.Sprintf("%s: no value at %s", name, paths[name]))
+			continue
+		}
+		extracted[name] = jsonText(value)
+	}
+
+	if len(problems) > 0 {
+		return extracted, fmt.Errorf("could not extract %s", strings.Join(problems, "; "))
+	}
+	return extracted, nil
+}
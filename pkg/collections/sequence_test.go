@@ -0,0 +1,190 @@
+package collections
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"onioncli/pkg/api"
+)
+
+func TestRunSequence(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			w.Write([]byte(`{"token": "t0k3n", "user": {"id": 42}}`))
+		case "/users/42":
+			if r.Header.Get("Authorization") != "Bearer t0k3n" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"name": "alice"}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	manager := newTestManager(t)
+	collection := manager.CreateCollection("Login flow", "")
+	login := api.NewRequest("POST", server.URL+"/login")
+	profile := api.NewRequest("GET", server.URL+"/users/{{user_id}}")
+	profile.SetHeader("Authorization", "Bearer {{token}}")
+	for _, step := range []struct {
+		req  *api.Request
+		name string
+	}{
+		{login, "Login"},
+		{profile, "Profile"},
+		{api.NewRequest("DELETE", server.URL+"/broken"), "Broken"},
+		{api.NewRequest("POST", server.URL+"/login"), "After"},
+	} {
+		if err := manager.AddRequestToCollection(collection.ID, step.req, step.name, ""); err != nil {
+			t.Fatalf("Failed to add request: %v", err)
+		}
+	}
+	editCollection(t, manager, collection.ID, func(collection *Collection) {
+		collection.Requests[0].Extract = map[string]string{"token": "$.token", "user_id": "$.user.id"}
+		collection.Requests[1].Tests = []string{"jsonpath $.name == alice"}
+	})
+
+	client, err := api.NewClient(&api.ClientConfig{TorEnabled: false, Timeout: 10 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	t.Run("stops at the first failure", func(t *testing.T) {
+		var reported []int
+		progress := ProgressFunc(func(done, total int) { reported = append(reported, done) })
+
+		run, err := manager.RunSequence(context.Background(), collection.ID, client, false, progress)
+		if err != nil {
+			t.Fatalf("RunSequence failed: %v", err)
+		}
+
+		if len(run.Results) != 3 {
+			t.Fatalf("Expected 3 results before stopping, got %+v", run.Results)
+		}
+		want := map[string]string{"token": "t0k3n", "user_id": "42"}
+		if !reflect.DeepEqual(run.Results[0].Extracted, want) || !reflect.DeepEqual(run.Variables, want) {
+			t.Errorf("Extracted = %v, variables = %v, want %v", run.Results[0].Extracted, run.Variables, want)
+		}
+		if !run.Results[1].Passed() || len(run.Results[1].Tests) != 1 {
+			t.Errorf("Expected the profile request to use the extracted values, got %+v", run.Results[1])
+		}
+		if run.Results[2].Passed() || run.Results[2].StatusCode != http.StatusInternalServerError {
+			t.Errorf("Expected the broken request to fail, got %+v", run.Results[2])
+		}
+		if !reflect.DeepEqual(run.NotRun, []string{"After"}) {
+			t.Errorf("NotRun = %v, want [After]", run.NotRun)
+		}
+		if passed, failed := run.Summary(); passed != 2 || failed != 1 {
+			t.Errorf("Summary() = %d, %d, want 2, 1", passed, failed)
+		}
+		if !reflect.DeepEqual(reported, []int{0, 1, 2, 3}) {
+			t.Errorf("Progress = %v", reported)
+		}
+	})
+
+	t.Run("continues on error", func(t *testing.T) {
+		run, err := manager.RunSequence(context.Background(), collection.ID, client, true, nil)
+		if err != nil {
+			t.Fatalf("RunSequence failed: %v", err)
+		}
+		if len(run.Results) != 4 || len(run.NotRun) != 0 || !run.Results[3].Passed() {
+			t.Errorf("Expected all 4 requests to run, got %+v (not run %v)", run.Results, run.NotRun)
+		}
+	})
+
+	t.Run("variables last only for the run", func(t *testing.T) {
+		if got := manager.SubstituteVariables("{{token}}"); got != "{{token}}" {
+			t.Errorf("Expected extracted variables to be gone after the run, got %q", got)
+		}
+	})
+}
+
+func TestRunSequenceExtractFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": 1}`))
+	}))
+	defer server.Close()
+
+	manager := newTestManager(t)
+	collection := manager.CreateCollection("Extract", "")
+	if err := manager.AddRequestToCollection(collection.ID, api.NewRequest("GET", server.URL), "Get", ""); err != nil {
+		t.Fatalf("Failed to add request: %v", err)
+	}
+	editCollection(t, manager, collection.ID, func(collection *Collection) {
+		collection.Requests[0].Extract = map[string]string{"id": "$.id", "token": "$.token"}
+	})
+
+	client, err := api.NewClient(&api.ClientConfig{TorEnabled: false, Timeout: 10 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	run, err := manager.RunSequence(context.Background(), collection.ID, client, false, nil)
+	if err != nil {
+		t.Fatalf("RunSequence failed: %v", err)
+	}
+
+	result := run.Results[0]
+	if result.Passed() || !strings.Contains(result.Error, "token: no value at $.token") {
+		t.Errorf("Expected a missing value to fail the request, got %+v", result)
+	}
+	if result.Extracted["id"] != "1" {
+		t.Errorf("Expected values that were found to be kept, got %v", result.Extracted)
+	}
+
+	if _, err := manager.RunSequence(context.Background(), "missing", client, false, nil); err == nil {
+		t.Error("Expected an error for an unknown collection")
+	}
+	empty := manager.CreateCollection("Empty", "")
+	if _, err := manager.RunSequence(context.Background(), empty.ID, client, false, nil); err == nil {
+		t.Error("Expected an error for a collection without requests")
+	}
+}
+
+func TestRunSequenceDefaultHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Tenant") != "acme" || r.Header.Get("Accept") != r.URL.Query().Get("accept") {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	manager := newTestManager(t)
+	collection := manager.CreateCollection("Defaults", "")
+	plain := api.NewRequest("GET", server.URL+"/?accept=application/json")
+	override := api.NewRequest("GET", server.URL+"/?accept=text/csv")
+	override.SetHeader("accept", "text/csv")
+	for _, req := range []*api.Request{plain, override} {
+		if err := manager.AddRequestToCollection(collection.ID, req, req.URL, ""); err != nil {
+			t.Fatalf("Failed to add request: %v", err)
+		}
+	}
+	defaults := map[string]string{"X-Tenant": "acme", "Accept": "application/json"}
+	if err := manager.SetCollectionDefaultHeaders(collection.ID, defaults); err != nil {
+		t.Fatalf("SetCollectionDefaultHeaders failed: %v", err)
+	}
+
+	client, err := api.NewClient(&api.ClientConfig{TorEnabled: false, Timeout: 10 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	run, err := manager.RunSequence(context.Background(), collection.ID, client, false, nil)
+	if err != nil {
+		t.Fatalf("RunSequence failed: %v", err)
+	}
+	for _, result := range run.Results {
+		if !result.Passed() {
+			t.Errorf("Expected %s to get the default headers under its own, got %+v", result.RequestName, result)
+		}
+	}
+	if len(run.Results) != 2 {
+		t.Errorf("Expected both requests to run, got %+v", run.Results)
+	}
+}
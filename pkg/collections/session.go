@@ -0,0 +1,65 @@
+package collections
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Session points at what the user was last working on, so it can be
+// reopened on the next launch. The active environment is stored with the
+// environments themselves.
+type Session struct {
+	CollectionID string `json:"collection_id,omitempty"`
+	RequestID    string `json:"request_id,omitempty"`
+}
+
+// LoadSession reads the saved session. A missing file is an empty session.
+func (m *Manager) LoadSession() (Session, error) {
+	var session Session
+	data, err := os.ReadFile(m.sessionFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return session, nil
+		}
+		return session, fmt.Errorf("failed to read session: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &session); err != nil {
+		return Session{}, fmt.Errorf("failed to parse session: %w", err)
+	}
+	return session, nil
+}
+
+// SaveSession records the last opened collection and request
+func (m *Manager) SaveSession(session Session) error {
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+
+	if err := os.WriteFile(m.sessionFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+	return nil
+}
+
+// ResolveSession looks up the session's collection and request. Either is
+// nil if it was never set or has since been deleted.
+func (m *Manager) ResolveSession(session Session) (*Collection, *CollectionRequest) {
+	if session.CollectionID == "" {
+		return nil, nil
+	}
+
+	collection, err := m.GetCollection(session.CollectionID)
+	if err != nil {
+		return nil, nil
+	}
+
+	for i := range collection.Requests {
+		if collection.Requests[i].ID == session.RequestID {
+			return collection, &collection.Requests[i]
+		}
+	}
+	return collection, nil
+}
@@ -0,0 +1,83 @@
+package collections
+
+import (
+	"os"
+	"testing"
+
+	"onioncli/pkg/api"
+)
+
+func TestSessionSaveAndRestore(t *testing.T) {
+	manager := newTestManager(t)
+
+	// No session yet
+	session, err := manager.LoadSession()
+	if err != nil {
+		t.Fatalf("LoadSession() error = %v", err)
+	}
+	if collection, request := manager.ResolveSession(session); collection != nil || request != nil {
+		t.Errorf("Empty session resolved to %v, %v", collection, request)
+	}
+
+	collection := manager.CreateCollection("API", "")
+	if err := manager.AddRequestToCollection(collection.ID, api.NewRequest("GET", "http://example.onion/a"), "List", ""); err != nil {
+		t.Fatalf("AddRequestToCollection() error = %v", err)
+	}
+	if err := manager.AddRequestToCollection(collection.ID, api.NewRequest("POST", "http://example.onion/b"), "Create", ""); err != nil {
+		t.Fatalf("AddRequestToCollection() error = %v", err)
+	}
+	collection, _ = manager.GetCollection(collection.ID)
+	saved := Session{CollectionID: collection.ID, RequestID: collection.Requests[1].ID}
+	if err := manager.SaveSession(saved); err != nil {
+		t.Fatalf("SaveSession() error = %v", err)
+	}
+
+	// A new manager, as on the next launch, reads it back
+	restarted, err := NewManager()
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	session, err = restarted.LoadSession()
+	if err != nil {
+		t.Fatalf("LoadSession() error = %v", err)
+	}
+	if session != saved {
+		t.Errorf("LoadSession() = %+v, want %+v", session, saved)
+	}
+	restoredCollection, restoredRequest := restarted.ResolveSession(session)
+	if restoredCollection == nil || restoredCollection.Name != "API" {
+		t.Fatalf("Restored collection = %+v, want API", restoredCollection)
+	}
+	if restoredRequest == nil || restoredRequest.Name != "Create" {
+		t.Errorf("Restored request = %+v, want Create", restoredRequest)
+	}
+
+	// A deleted request still restores its collection
+	session.RequestID = "gone"
+	if c, r := restarted.ResolveSession(session); c == nil || r != nil {
+		t.Errorf("ResolveSession() with deleted request = %v, %v, want collection only", c, r)
+	}
+
+	// A deleted collection restores nothing
+	if err := restarted.DeleteCollection(collection.ID); err != nil {
+		t.Fatalf("DeleteCollection() error = %v", err)
+	}
+	if c, r := restarted.ResolveSession(saved); c != nil || r != nil {
+		t.Errorf("ResolveSession() with deleted collection = %v, %v, want nothing", c, r)
+	}
+}
+
+func TestLoadSessionCorrupted(t *testing.T) {
+	manager := newTestManager(t)
+	if err := os.WriteFile(manager.sessionFile, []byte("{not json"), 0644); err != nil {
+		t.Fatalf("Failed to write session: %v", err)
+	}
+
+	session, err := manager.LoadSession()
+	if err == nil {
+		t.Error("LoadSession() error = nil, want a parse error")
+	}
+	if session != (Session{}) {
+		t.Errorf("LoadSession() = %+v, want an empty session", session)
+	}
+}
@@ -0,0 +1,103 @@
+package collections
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Snippet is a named, reusable request body fragment. {{variable}}
+// placeholders are resolved against the active environment on insertion.
+type Snippet struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Snippets returns all saved snippets sorted by name
+func (m *Manager) Snippets() ([]Snippet, error) {
+	files, err := filepath.Glob(filepath.Join(m.snippetsDir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	snippets := make([]Snippet, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue // Skip unreadable files
+		}
+
+		var snippet Snippet
+		if err := json.Unmarshal(data, &snippet); err != nil {
+			continue // Skip corrupted files
+		}
+		snippets = append(snippets, snippet)
+	}
+
+	sort.Slice(snippets, func(i, j int) bool {
+		return strings.ToLower(snippets[i].Name) < strings.ToLower(snippets[j].Name)
+	})
+	return snippets, nil
+}
+
+// SaveSnippet saves body as a snippet, replacing any snippet with the same name
+func (m *Manager) SaveSnippet(name, body string) (*Snippet, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("snippet name is required")
+	}
+	if strings.TrimSpace(body) == "" {
+		return nil, fmt.Errorf("snippet body is empty")
+	}
+
+	snippets, err := m.Snippets()
+	if err != nil {
+		return nil, err
+	}
+
+	snippet := Snippet{ID: generateID(), Name: name, Body: body, CreatedAt: time.Now()}
+	for _, existing := range snippets {
+		if strings.EqualFold(existing.Name, name) {
+			snippet.ID = existing.ID
+			snippet.CreatedAt = existing.CreatedAt
+		}
+	}
+
+	data, err := json.MarshalIndent(snippet, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(m.snippetFile(snippet.ID), data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to save snippet: %w", err)
+	}
+	return &snippet, nil
+}
+
+// DeleteSnippet removes a snippet
+func (m *Manager) DeleteSnippet(id string) error {
+	if err := os.Remove(m.snippetFile(id)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("snippet not found: %s", id)
+		}
+		return err
+	}
+	return nil
+}
+
+// ResolveSnippet substitutes the active environment's variables into a
+// snippet's body, returning the text and any placeholders left unresolved
+func (m *Manager) ResolveSnippet(snippet Snippet) (string, []string) {
+	body := m.SubstituteVariables(snippet.Body)
+	return body, ReferencedVariables(body)
+}
+
+// snippetFile returns the path a snippet is stored at
+func (m *Manager) snippetFile(id string) string {
+	return filepath.Join(m.snippetsDir, id+".json")
+}
@@ -0,0 +1,63 @@
+package collections
+
+import "testing"
+
+func TestSnippetResolution(t *testing.T) {
+	manager := newTestManager(t)
+	env := createTestEnvironment(t, manager, "Staging", "", map[string]string{"user_id": "42", "tenant": "acme"})
+	if err := manager.SetActiveEnvironment(env.ID); err != nil {
+		t.Fatalf("Failed to activate environment: %v", err)
+	}
+
+	if _, err := manager.SaveSnippet("Create order", `{"user": "{{user_id}}", "tenant": "{{tenant}}", "coupon": "{{coupon}}"}`); err != nil {
+		t.Fatalf("SaveSnippet failed: %v", err)
+	}
+
+	snippets, err := manager.Snippets()
+	if err != nil || len(snippets) != 1 {
+		t.Fatalf("Snippets() = %v, %v, want one snippet", snippets, err)
+	}
+
+	body, unresolved := manager.ResolveSnippet(snippets[0])
+	if want := `{"user": "42", "tenant": "acme", "coupon": "{{coupon}}"}`; body != want {
+		t.Errorf("ResolveSnippet() body = %q, want %q", body, want)
+	}
+	if len(unresolved) != 1 || unresolved[0] != "coupon" {
+		t.Errorf("ResolveSnippet() unresolved = %v, want [coupon]", unresolved)
+	}
+}
+
+func TestSaveSnippetReplacesByName(t *testing.T) {
+	manager := newTestManager(t)
+
+	first, err := manager.SaveSnippet("Login", `{"user": "a"}`)
+	if err != nil {
+		t.Fatalf("SaveSnippet failed: %v", err)
+	}
+	second, err := manager.SaveSnippet("login", `{"user": "b"}`)
+	if err != nil {
+		t.Fatalf("SaveSnippet failed: %v", err)
+	}
+	if second.ID != first.ID {
+		t.Errorf("Expected saving under the same name to replace the snippet")
+	}
+
+	snippets, _ := manager.Snippets()
+	if len(snippets) != 1 || snippets[0].Body != `{"user": "b"}` {
+		t.Fatalf("Snippets() = %+v, want the replaced snippet only", snippets)
+	}
+
+	if err := manager.DeleteSnippet(first.ID); err != nil {
+		t.Fatalf("DeleteSnippet failed: %v", err)
+	}
+	if snippets, _ := manager.Snippets(); len(snippets) != 0 {
+		t.Errorf("Expected no snippets after delete, got %d", len(snippets))
+	}
+
+	if _, err := manager.SaveSnippet(" ", "x"); err == nil {
+		t.Error("Expected an error for an empty name")
+	}
+	if _, err := manager.SaveSnippet("Empty", "  "); err == nil {
+		t.Error("Expected an error for an empty body")
+	}
+}
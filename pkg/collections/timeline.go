@@ -0,0 +1,56 @@
+package collections
+
+import "time"
+
+// CriticalPath returns the indexes of the results that determined the run's
+// total time, in order. It starts from the request that finished last and
+// walks back through the request that finished latest before each one
+// started, so overlapping requests that didn't hold up the run are skipped.
+func (r *DataDrivenRun) CriticalPath() []int {
+	if len(r.Results) == 0 {
+		return nil
+	}
+
+	current := 0
+	for i, result := range r.Results {
+		if result.End > r.Results[current].End {
+			current = i
+		}
+	}
+
+	path := []int{current}
+	for {
+		previous := -1
+		for i, result := range r.Results {
+			if result.End > r.Results[current].Start || i == current {
+				continue
+			}
+			if previous == -1 || result.End > r.Results[previous].End {
+				previous = i
+			}
+		}
+		if previous == -1 {
+			break
+		}
+		path = append(path, previous)
+		current = previous
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// Slowest returns the index of the result that took the longest, or -1 if
+// the run has no results
+func (r *DataDrivenRun) Slowest() int {
+	slowest := -1
+	var longest time.Duration
+	for i, result := range r.Results {
+		if d := result.End - result.Start; slowest == -1 || d > longest {
+			slowest, longest = i, d
+		}
+	}
+	return slowest
+}
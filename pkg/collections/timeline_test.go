@@ -0,0 +1,49 @@
+package collections
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCriticalPath(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name     string
+		results  [][2]time.Duration // start, end
+		expected []int
+	}{
+		{"empty", nil, nil},
+		{"sequential", [][2]time.Duration{{0, 10 * ms}, {10 * ms, 50 * ms}, {50 * ms, 60 * ms}}, []int{0, 1, 2}},
+		{"overlapping", [][2]time.Duration{{0, 40 * ms}, {0, 10 * ms}, {10 * ms, 20 * ms}, {40 * ms, 45 * ms}}, []int{0, 3}},
+		{"parallel", [][2]time.Duration{{0, 30 * ms}, {0, 80 * ms}, {0, 20 * ms}}, []int{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run := &DataDrivenRun{}
+			for _, timing := range tt.results {
+				run.Results = append(run.Results, DataDrivenResult{Start: timing[0], End: timing[1]})
+			}
+			if got := run.CriticalPath(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected critical path %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSlowest(t *testing.T) {
+	run := &DataDrivenRun{}
+	if got := run.Slowest(); got != -1 {
+		t.Errorf("Expected -1 for an empty run, got %d", got)
+	}
+
+	run.Results = []DataDrivenResult{
+		{Start: 0, End: 10 * time.Millisecond},
+		{Start: 10 * time.Millisecond, End: 90 * time.Millisecond},
+		{Start: 90 * time.Millisecond, End: 100 * time.Millisecond},
+	}
+	if got := run.Slowest(); got != 1 {
+		t.Errorf("Expected result 1 to be slowest, got %d", got)
+	}
+}
@@ -4,9 +4,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
+
+	"onioncli/pkg/api"
+	"onioncli/pkg/history"
 )
 
 // Config represents the application configuration
@@ -34,30 +40,55 @@ type TorConfig struct {
 	ProxyPort  int    `mapstructure:"proxy_port" json:"proxy_port"`
 	Timeout    int    `mapstructure:"timeout" json:"timeout"` // seconds
 	AutoDetect bool   `mapstructure:"auto_detect" json:"auto_detect"`
+
+	// Connection reuse; see api.ConnectionPool for the anonymity tradeoff
+	KeepAlives      bool `mapstructure:"keep_alives" json:"keep_alives"`
+	MaxIdleConns    int  `mapstructure:"max_idle_conns" json:"max_idle_conns"`
+	MaxConnsPerHost int  `mapstructure:"max_conns_per_host" json:"max_conns_per_host"`
+
+	// Control port, used to request a new circuit (SIGNAL NEWNYM). An empty
+	// password falls back to cookie authentication.
+	ControlPort     int    `mapstructure:"control_port" json:"control_port"`
+	ControlPassword string `mapstructure:"control_password" json:"control_password"`
 }
 
 // HTTPConfig holds HTTP-specific configuration
 type HTTPConfig struct {
-	Timeout         int    `mapstructure:"timeout" json:"timeout"` // seconds
-	FollowRedirects bool   `mapstructure:"follow_redirects" json:"follow_redirects"`
-	MaxRedirects    int    `mapstructure:"max_redirects" json:"max_redirects"`
-	VerifySSL       bool   `mapstructure:"verify_ssl" json:"verify_ssl"`
-	UserAgent       string `mapstructure:"user_agent" json:"user_agent"`
+	Timeout            int    `mapstructure:"timeout" json:"timeout"` // seconds
+	FollowRedirects    bool   `mapstructure:"follow_redirects" json:"follow_redirects"`
+	MaxRedirects       int    `mapstructure:"max_redirects" json:"max_redirects"`
+	VerifySSL          bool   `mapstructure:"verify_ssl" json:"verify_ssl"`
+	UserAgent          string `mapstructure:"user_agent" json:"user_agent"`
+	TreatNon2xxAsError bool   `mapstructure:"treat_non_2xx_as_error" json:"treat_non_2xx_as_error"` // 4xx/5xx count as failures
+	AutoRetry          bool   `mapstructure:"auto_retry" json:"auto_retry"`                         // retry network/Tor failures with backoff
+	MaxRetries         int    `mapstructure:"max_retries" json:"max_retries"`                       // extra attempts when auto_retry is on
+
+	InteractiveRedirects bool `mapstructure:"interactive_redirects" json:"interactive_redirects"` // pause on each 3xx instead of auto-following
+	UseCookieJar         bool `mapstructure:"use_cookie_jar" json:"use_cookie_jar"`               // keep cookies between requests, per site
 }
 
 // UIConfig holds UI-specific configuration
 type UIConfig struct {
-	Theme           string `mapstructure:"theme" json:"theme"`
-	ShowLineNumbers bool   `mapstructure:"show_line_numbers" json:"show_line_numbers"`
-	AutoSave        bool   `mapstructure:"auto_save" json:"auto_save"`
-	ConfirmExit     bool   `mapstructure:"confirm_exit" json:"confirm_exit"`
+	Theme            string `mapstructure:"theme" json:"theme"`
+	ShowLineNumbers  bool   `mapstructure:"show_line_numbers" json:"show_line_numbers"`
+	AutoSave         bool   `mapstructure:"auto_save" json:"auto_save"`
+	ConfirmExit      bool   `mapstructure:"confirm_exit" json:"confirm_exit"`
+	DefaultMethod    string `mapstructure:"default_method" json:"default_method"`
+	Accessible       bool   `mapstructure:"accessible" json:"accessible"`               // plain-text output for screen readers
+	PromptSave       bool   `mapstructure:"prompt_save" json:"prompt_save"`             // offer to save successful unsaved requests
+	AutoFormatPaste  bool   `mapstructure:"auto_format_paste" json:"auto_format_paste"` // pretty-print JSON pasted into the body
+	RestoreSession   bool   `mapstructure:"restore_session" json:"restore_session"`     // reopen the last collection and request on startup
+	ConfirmMutations bool   `mapstructure:"confirm_mutations" json:"confirm_mutations"` // ask before sending POST, PUT, PATCH and DELETE
+	Mouse            bool   `mapstructure:"mouse" json:"mouse"`                         // click to focus fields, wheel to scroll
+	Keymap           string `mapstructure:"keymap" json:"keymap"`                       // "default" or "vim"
 }
 
 // HistoryConfig holds history-specific configuration
 type HistoryConfig struct {
-	Enabled    bool `mapstructure:"enabled" json:"enabled"`
-	MaxEntries int  `mapstructure:"max_entries" json:"max_entries"`
-	AutoSave   bool `mapstructure:"auto_save" json:"auto_save"`
+	Enabled         bool `mapstructure:"enabled" json:"enabled"`
+	MaxEntries      int  `mapstructure:"max_entries" json:"max_entries"` // entries kept, oldest dropped first; 0 keeps every entry
+	AutoSave        bool `mapstructure:"auto_save" json:"auto_save"`
+	MaxResponseBody int  `mapstructure:"max_response_body" json:"max_response_body"` // bytes of each response body kept; 0 keeps none
 }
 
 // Manager handles configuration loading, saving, and management
@@ -117,6 +148,11 @@ func (m *Manager) setDefaults() {
 	m.viper.SetDefault("tor.proxy_port", 9050)
 	m.viper.SetDefault("tor.timeout", 30)
 	m.viper.SetDefault("tor.auto_detect", true)
+	m.viper.SetDefault("tor.keep_alives", false)
+	m.viper.SetDefault("tor.max_idle_conns", 0)
+	m.viper.SetDefault("tor.max_conns_per_host", 0)
+	m.viper.SetDefault("tor.control_port", 9051)
+	m.viper.SetDefault("tor.control_password", "")
 
 	// HTTP defaults
 	m.viper.SetDefault("http.timeout", 30)
@@ -124,17 +160,31 @@ func (m *Manager) setDefaults() {
 	m.viper.SetDefault("http.max_redirects", 10)
 	m.viper.SetDefault("http.verify_ssl", true)
 	m.viper.SetDefault("http.user_agent", "OnionCLI/1.0")
+	m.viper.SetDefault("http.treat_non_2xx_as_error", false)
+	m.viper.SetDefault("http.auto_retry", false)
+	m.viper.SetDefault("http.max_retries", 3)
+	m.viper.SetDefault("http.interactive_redirects", false)
+	m.viper.SetDefault("http.use_cookie_jar", false)
 
 	// UI defaults
 	m.viper.SetDefault("ui.theme", "dark")
 	m.viper.SetDefault("ui.show_line_numbers", true)
 	m.viper.SetDefault("ui.auto_save", true)
 	m.viper.SetDefault("ui.confirm_exit", false)
+	m.viper.SetDefault("ui.default_method", "GET")
+	m.viper.SetDefault("ui.accessible", false)
+	m.viper.SetDefault("ui.prompt_save", false)
+	m.viper.SetDefault("ui.auto_format_paste", false)
+	m.viper.SetDefault("ui.restore_session", false)
+	m.viper.SetDefault("ui.confirm_mutations", false)
+	m.viper.SetDefault("ui.mouse", true)
+	m.viper.SetDefault("ui.keymap", "default")
 
 	// History defaults
 	m.viper.SetDefault("history.enabled", true)
-	m.viper.SetDefault("history.max_entries", 100)
+	m.viper.SetDefault("history.max_entries", history.DefaultMaxEntries)
 	m.viper.SetDefault("history.auto_save", true)
+	m.viper.SetDefault("history.max_response_body", history.DefaultMaxResponseBody)
 
 	// Default headers
 	m.viper.SetDefault("default_headers", map[string]string{
@@ -152,28 +202,37 @@ func (m *Manager) getDefaultConfig() *Config {
 			ProxyPort:  9050,
 			Timeout:    30,
 			AutoDetect: true,
+
+			ControlPort: 9051,
 		},
 		HTTP: HTTPConfig{
-			Timeout:         30,
-			FollowRedirects: true,
-			MaxRedirects:    10,
-			VerifySSL:       true,
-			UserAgent:       "OnionCLI/1.0",
+			Timeout:            30,
+			FollowRedirects:    true,
+			MaxRedirects:       10,
+			VerifySSL:          true,
+			UserAgent:          "OnionCLI/1.0",
+			TreatNon2xxAsError: false,
+			MaxRetries:         3,
+			UseCookieJar:       false,
 		},
 		UI: UIConfig{
 			Theme:           "dark",
 			ShowLineNumbers: true,
 			AutoSave:        true,
 			ConfirmExit:     false,
+			DefaultMethod:   "GET",
+			Mouse:           true,
+			Keymap:          "default",
 		},
 		DefaultHeaders: map[string]string{
 			"User-Agent": "OnionCLI/1.0",
 			"Accept":     "application/json, text/plain, */*",
 		},
 		History: HistoryConfig{
-			Enabled:    true,
-			MaxEntries: 100,
-			AutoSave:   true,
+			Enabled:         true,
+			MaxEntries:      history.DefaultMaxEntries,
+			AutoSave:        true,
+			MaxResponseBody: history.DefaultMaxResponseBody,
 		},
 	}
 }
@@ -189,6 +248,11 @@ func (m *Manager) Load() error {
 		return fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	// Before auto_retry existed, a non-zero max_retries turned retries on
+	if !m.viper.InConfig("http.auto_retry") && m.viper.InConfig("http.max_retries") && config.HTTP.MaxRetries > 0 {
+		config.HTTP.AutoRetry = true
+	}
+
 	m.config = config
 	return nil
 }
@@ -200,15 +264,31 @@ func (m *Manager) Save() error {
 	}
 
 	// Update viper with current config values
-	m.viper.Set("tor", m.config.Tor)
-	m.viper.Set("http", m.config.HTTP)
-	m.viper.Set("ui", m.config.UI)
-	m.viper.Set("default_headers", m.config.DefaultHeaders)
-	m.viper.Set("history", m.config.History)
+	setConfig(m.viper, m.config)
 
 	return m.viper.WriteConfig()
 }
 
+// setConfig sets every value of config on v under the same keys Load reads
+// them back from
+func setConfig(v *viper.Viper, config *Config) {
+	setSection(v, "tor", config.Tor)
+	setSection(v, "http", config.HTTP)
+	setSection(v, "ui", config.UI)
+	v.Set("default_headers", config.DefaultHeaders)
+	setSection(v, "history", config.History)
+}
+
+// setSection sets each field of section on v under prefix and the field's
+// mapstructure name
+func setSection(v *viper.Viper, prefix string, section any) {
+	value := reflect.ValueOf(section)
+	for i := 0; i < value.NumField(); i++ {
+		key := value.Type().Field(i).Tag.Get("mapstructure")
+		v.Set(prefix+"."+key, value.Field(i).Interface())
+	}
+}
+
 // Get returns the current configuration
 func (m *Manager) Get() *Config {
 	return m.config
@@ -229,6 +309,15 @@ func (m *Manager) GetHTTPTimeout() time.Duration {
 	return time.Duration(m.config.HTTP.Timeout) * time.Second
 }
 
+// GetMaxRetries returns how many times a failed request is retried: none
+// unless http.auto_retry is on
+func (m *Manager) GetMaxRetries() int {
+	if !m.config.HTTP.AutoRetry {
+		return 0
+	}
+	return m.config.HTTP.MaxRetries
+}
+
 // GetTorTimeout returns the Tor timeout as a duration
 func (m *Manager) GetTorTimeout() time.Duration {
 	return time.Duration(m.config.Tor.Timeout) * time.Second
@@ -283,6 +372,94 @@ func (m *Manager) GetDefaultHeaders() map[string]string {
 	return headers
 }
 
+// DefaultHeadersFor returns the default headers that apply to a request with
+// the given method. A default header key may end in a method condition:
+// "Content-Type [POST,PUT,PATCH]" applies only to those methods and
+// "Content-Type [!GET,HEAD]" to every method except those. When a conditional
+// and an unconditional default name the same header, the conditional one wins.
+func (m *Manager) DefaultHeadersFor(method string) map[string]string {
+	method = strings.ToUpper(method)
+
+	keys := make([]string, 0, len(m.config.DefaultHeaders))
+	for key := range m.config.DefaultHeaders {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	headers := make(map[string]string)
+	conditional := make(map[string]string)
+	for _, key := range keys {
+		name, condition, err := parseHeaderCondition(key)
+		if err != nil || !condition.matches(method) {
+			continue
+		}
+		if condition.methods == nil {
+			headers[name] = m.config.DefaultHeaders[key]
+		} else {
+			conditional[name] = m.config.DefaultHeaders[key]
+		}
+	}
+
+	for name, value := range conditional {
+		for existing := range headers {
+			if strings.EqualFold(existing, name) {
+				delete(headers, existing)
+			}
+		}
+		headers[name] = value
+	}
+	return headers
+}
+
+// methodCondition limits a default header to (or excludes it from) a set of methods
+type methodCondition struct {
+	methods []string // nil means every method
+	exclude bool
+}
+
+// matches reports whether the condition allows the method
+func (c methodCondition) matches(method string) bool {
+	if c.methods == nil {
+		return true
+	}
+	for _, m := range c.methods {
+		if m == method {
+			return !c.exclude
+		}
+	}
+	return c.exclude
+}
+
+// parseHeaderCondition splits a default header key like
+// "Content-Type [POST,PUT]" into the header name and its method condition
+func parseHeaderCondition(key string) (string, methodCondition, error) {
+	key = strings.TrimSpace(key)
+	open := strings.LastIndex(key, "[")
+	if open == -1 || !strings.HasSuffix(key, "]") {
+		return key, methodCondition{}, nil
+	}
+
+	name := strings.TrimSpace(key[:open])
+	spec := strings.TrimSpace(key[open+1 : len(key)-1])
+	if name == "" {
+		return "", methodCondition{}, fmt.Errorf("default header %q has no name", key)
+	}
+
+	var condition methodCondition
+	if strings.HasPrefix(spec, "!") {
+		condition.exclude = true
+		spec = spec[1:]
+	}
+	for _, method := range strings.Split(spec, ",") {
+		method = strings.ToUpper(strings.TrimSpace(method))
+		if !api.IsValidMethod(method) {
+			return "", methodCondition{}, fmt.Errorf("default header %q has invalid method %q", key, method)
+		}
+		condition.methods = append(condition.methods, method)
+	}
+	return name, condition, nil
+}
+
 // Validate validates the configuration
 func (m *Manager) Validate() error {
 	if m.config == nil {
@@ -294,10 +471,18 @@ func (m *Manager) Validate() error {
 		return fmt.Errorf("invalid Tor proxy port: %d", m.config.Tor.ProxyPort)
 	}
 
+	if m.config.Tor.ControlPort < 0 || m.config.Tor.ControlPort > 65535 {
+		return fmt.Errorf("invalid Tor control port: %d", m.config.Tor.ControlPort)
+	}
+
 	if m.config.Tor.Timeout < 1 {
 		return fmt.Errorf("Tor timeout must be at least 1 second")
 	}
 
+	if m.config.Tor.MaxIdleConns < 0 || m.config.Tor.MaxConnsPerHost < 0 {
+		return fmt.Errorf("Tor connection limits cannot be negative")
+	}
+
 	// Validate HTTP settings
 	if m.config.HTTP.Timeout < 1 {
 		return fmt.Errorf("HTTP timeout must be at least 1 second")
@@ -307,9 +492,29 @@ func (m *Manager) Validate() error {
 		return fmt.Errorf("max redirects cannot be negative")
 	}
 
+	if m.config.HTTP.MaxRetries < 0 {
+		return fmt.Errorf("max retries cannot be negative")
+	}
+
+	// Validate UI settings
+	if m.config.UI.DefaultMethod != "" && !api.IsValidMethod(m.config.UI.DefaultMethod) {
+		return fmt.Errorf("invalid default method: %s", m.config.UI.DefaultMethod)
+	}
+
+	// Validate default header method conditions
+	for key := range m.config.DefaultHeaders {
+		if _, _, err := parseHeaderCondition(key); err != nil {
+			return err
+		}
+	}
+
 	// Validate History settings
-	if m.config.History.MaxEntries < 1 {
-		return fmt.Errorf("history max entries must be at least 1")
+	if m.config.History.MaxEntries < 0 {
+		return fmt.Errorf("history max entries cannot be negative (use 0 to keep every entry)")
+	}
+
+	if m.config.History.MaxResponseBody < 0 {
+		return fmt.Errorf("history max response body cannot be negative")
 	}
 
 	return nil
@@ -329,11 +534,7 @@ func (m *Manager) GetConfigPath() string {
 // Export exports the configuration to a file
 func (m *Manager) Export(filename string) error {
 	tempViper := viper.New()
-	tempViper.Set("tor", m.config.Tor)
-	tempViper.Set("http", m.config.HTTP)
-	tempViper.Set("ui", m.config.UI)
-	tempViper.Set("default_headers", m.config.DefaultHeaders)
-	tempViper.Set("history", m.config.History)
+	setConfig(tempViper, m.config)
 
 	return tempViper.WriteConfigAs(filename)
 }
@@ -0,0 +1,154 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDefaultHeadersFor(t *testing.T) {
+	manager := &Manager{config: &Config{DefaultHeaders: map[string]string{
+		"User-Agent":                    "OnionCLI/1.0",
+		"Content-Type [POST,put,PATCH]": "application/json",
+		"Accept [!HEAD]":                "application/json",
+		"X-Mode":                        "default",
+		"x-mode [DELETE]":               "delete",
+	}}}
+
+	tests := []struct {
+		method   string
+		expected map[string]string
+	}{
+		{"GET", map[string]string{"User-Agent": "OnionCLI/1.0", "Accept": "application/json", "X-Mode": "default"}},
+		{"post", map[string]string{"User-Agent": "OnionCLI/1.0", "Accept": "application/json", "X-Mode": "default", "Content-Type": "application/json"}},
+		{"PUT", map[string]string{"User-Agent": "OnionCLI/1.0", "Accept": "application/json", "X-Mode": "default", "Content-Type": "application/json"}},
+		{"HEAD", map[string]string{"User-Agent": "OnionCLI/1.0", "X-Mode": "default"}},
+		{"DELETE", map[string]string{"User-Agent": "OnionCLI/1.0", "Accept": "application/json", "x-mode": "delete"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			if got := manager.DefaultHeadersFor(tt.method); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestValidateDefaultHeaderConditions(t *testing.T) {
+	tests := []struct {
+		key     string
+		wantErr bool
+	}{
+		{"Content-Type [POST, PUT]", false},
+		{"Accept [!GET]", false},
+		{"Content-Type [FETCH]", true},
+		{"Content-Type []", true},
+		{"[POST]", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			manager := &Manager{}
+			manager.config = manager.getDefaultConfig()
+			manager.config.DefaultHeaders = map[string]string{tt.key: "value"}
+
+			if err := manager.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateHistoryMaxEntries(t *testing.T) {
+	tests := []struct {
+		maxEntries int
+		wantErr    bool
+	}{
+		{100, false},
+		{0, false}, // unlimited
+		{-1, true},
+	}
+
+	for _, tt := range tests {
+		manager := &Manager{}
+		manager.config = manager.getDefaultConfig()
+		manager.config.History.MaxEntries = tt.maxEntries
+
+		if err := manager.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("max_entries %d: expected error %v, got %v", tt.maxEntries, tt.wantErr, err)
+		}
+	}
+}
+
+func TestGetMaxRetries(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string // written to config.yaml; empty starts from the defaults
+		want   int
+	}{
+		{name: "defaults leave retries off", want: 0},
+		{name: "auto_retry on", config: "http:\n  auto_retry: true\n", want: 3},
+		{name: "auto_retry off", config: "http:\n  auto_retry: false\n  max_retries: 5\n", want: 0},
+		{name: "max_retries from before auto_retry", config: "http:\n  max_retries: 2\n", want: 2},
+		{name: "max_retries of 0 from before auto_retry", config: "http:\n  max_retries: 0\n", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			if tt.config != "" {
+				dir := filepath.Join(home, ".onioncli")
+				if err := os.MkdirAll(dir, 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(tt.config), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			m, err := NewManager()
+			if err != nil {
+				t.Fatalf("NewManager() error = %v", err)
+			}
+			if got := m.GetMaxRetries(); got != tt.want {
+				t.Errorf("GetMaxRetries() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSaveRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	m, err := NewManager()
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	cfg := m.Get()
+	cfg.Tor.ProxyAddr = "10.0.0.1"
+	cfg.Tor.ProxyPort = 9150
+	cfg.Tor.MaxConnsPerHost = 4
+	cfg.HTTP.VerifySSL = false
+	cfg.HTTP.UserAgent = "Custom/2.0"
+	cfg.HTTP.AutoRetry = true
+	cfg.HTTP.MaxRetries = 5
+	cfg.UI.Theme = "light"
+	cfg.UI.Keymap = "vim"
+	cfg.DefaultHeaders = map[string]string{"accept": "text/plain"}
+	cfg.History.MaxEntries = 500
+	cfg.History.MaxResponseBody = 2048
+	if err := m.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	reloaded, err := NewManager()
+	if err != nil {
+		t.Fatalf("NewManager() after Save error = %v", err)
+	}
+	if got := reloaded.Get(); !reflect.DeepEqual(got, cfg) {
+		t.Errorf("Reloaded config = %+v, want %+v", got, cfg)
+	}
+}
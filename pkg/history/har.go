@@ -0,0 +1,360 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// harFile is a HAR 1.2 document. Import reads only what is needed to replay
+// requests; export fills in every field the format requires.
+type harFile struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"` // milliseconds
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	Comment         string      `json:"comment,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// harTimings splits an entry's time into phases; only send, wait and
+// receive are required
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+type harPostData struct {
+	MimeType string         `json:"mimeType"`
+	Text     string         `json:"text"`
+	Params   []harNameValue `json:"params,omitempty"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// skippedHARHeaders are recomputed by the client, so replaying them would be wrong
+var skippedHARHeaders = map[string]bool{
+	"content-length":    true,
+	"transfer-encoding": true,
+	"connection":        true,
+}
+
+// ProgressReporter receives progress updates while a HAR file is imported
+type ProgressReporter interface {
+	Report(done, total int)
+}
+
+// ImportHAR parses a HAR file (as saved by browser devtools) into history
+// entries, reporting each HAR entry parsed to progress, which may be nil.
+// Entries whose URLs are not http or https, such as data: URIs, are
+// skipped. Response status is kept as metadata; bodies are not.
+func ImportHAR(ctx context.Context, path string, progress ProgressReporter) ([]HistoryEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read HAR file: %w", err)
+	}
+
+	var har harFile
+	if err := json.Unmarshal(data, &har); err != nil {
+		return nil, fmt.Errorf("failed to parse HAR file: %w", err)
+	}
+
+	baseID := time.Now().UnixNano()
+	entries := make([]HistoryEntry, 0, len(har.Log.Entries))
+	for i, harEntry := range har.Log.Entries {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if entry, ok := harEntry.toHistoryEntry(); ok {
+			entry.ID = fmt.Sprintf("%d", baseID+int64(i))
+			entries = append(entries, entry)
+		}
+		if progress != nil {
+			progress.Report(i+1, len(har.Log.Entries))
+		}
+	}
+
+	return entries, nil
+}
+
+// toHistoryEntry converts a HAR entry, reporting false for non-HTTP URLs
+func (e harEntry) toHistoryEntry() (HistoryEntry, bool) {
+	u, err := url.Parse(e.Request.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return HistoryEntry{}, false
+	}
+
+	// Some tools record the query only in queryString
+	if u.RawQuery == "" && len(e.Request.QueryString) > 0 {
+		query := url.Values{}
+		for _, param := range e.Request.QueryString {
+			query.Add(param.Name, param.Value)
+		}
+		u.RawQuery = query.Encode()
+	}
+	u.Fragment = ""
+
+	entry := HistoryEntry{
+		Method:    strings.ToUpper(e.Request.Method),
+		URL:       u.String(),
+		Headers:   make(map[string]string),
+		Body:      e.Request.PostData.body(),
+		Timestamp: time.Now(),
+	}
+
+	if started, err := time.Parse(time.RFC3339Nano, e.StartedDateTime); err == nil {
+		entry.Timestamp = started
+	}
+
+	for _, header := range e.Request.Headers {
+		name := strings.TrimSpace(header.Name)
+		key := strings.ToLower(name)
+		if name == "" || strings.HasPrefix(name, ":") || skippedHARHeaders[key] {
+			continue
+		}
+		if existing, ok := entry.Headers[name]; ok {
+			separator := ", "
+			if key == "cookie" {
+				separator = "; "
+			}
+			entry.Headers[name] = existing + separator + header.Value
+			continue
+		}
+		entry.Headers[name] = header.Value
+	}
+
+	if e.Response.Status != 0 {
+		entry.Response = &ResponseSnapshot{
+			StatusCode: e.Response.Status,
+			Status:     strings.TrimSpace(fmt.Sprintf("%d %s", e.Response.Status, e.Response.StatusText)),
+			DurationMS: int64(e.Time),
+			Timestamp:  entry.Timestamp,
+		}
+		entry.Description = fmt.Sprintf("Imported from HAR (%s, %.0fms)", entry.Response.Status, e.Time)
+	} else {
+		entry.Description = "Imported from HAR (no response)"
+	}
+
+	return entry, true
+}
+
+// body returns the request body, encoding form params when there is no raw text
+func (p *harPostData) body() string {
+	if p == nil {
+		return ""
+	}
+	if p.Text != "" || len(p.Params) == 0 {
+		return p.Text
+	}
+
+	form := url.Values{}
+	for _, param := range p.Params {
+		form.Add(param.Name, param.Value)
+	}
+	return form.Encode()
+}
+
+// AddImported adds entries parsed by ImportHAR to history. They go before
+// existing entries, as the newest, and the oldest entries are trimmed if
+// history is now over its maximum.
+func (m *Manager) AddImported(entries []HistoryEntry) error {
+	if len(entries) == 0 {
+		return fmt.Errorf("no HTTP requests found in HAR file")
+	}
+
+	// Most recent first, like entries saved from the request builder
+	imported := make([]HistoryEntry, len(entries))
+	for i, entry := range entries {
+		imported[len(entries)-1-i] = entry
+	}
+	m.entries = append(imported, m.entries...)
+	m.trim()
+
+	return m.saveToFile()
+}
+
+// harUnknownMimeType is what browsers record when a type isn't known
+const harUnknownMimeType = "x-unknown"
+
+// ExportHAR writes history as a HAR 1.2 file, oldest entry first. Response
+// headers and the HTTP version aren't recorded, so they are left empty, and
+// sizes that weren't measured are -1 as HAR allows. Entries without a
+// response get status 0, as browsers record requests that never completed.
+// The file is readable only by the user, as it holds headers and bodies.
+func (m *Manager) ExportHAR(filename string) error {
+	har := harFile{Log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "onioncli", Version: creatorVersion()},
+		Entries: make([]harEntry, 0, len(m.entries)),
+	}}
+	for i := len(m.entries) - 1; i >= 0; i-- {
+		har.Log.Entries = append(har.Log.Entries, m.entries[i].toHAREntry())
+	}
+
+	data, err := json.MarshalIndent(har, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal HAR: %w", err)
+	}
+	return os.WriteFile(filename, data, 0600)
+}
+
+// toHAREntry converts a history entry for ExportHAR
+func (entry *HistoryEntry) toHAREntry() harEntry {
+	e := harEntry{
+		StartedDateTime: entry.Timestamp.Format(time.RFC3339Nano),
+		Request: harRequest{
+			Method:      entry.Method,
+			URL:         entry.URL,
+			Cookies:     []harNameValue{},
+			Headers:     harHeaders(entry.Headers),
+			QueryString: harQueryString(entry.URL),
+			HeadersSize: -1,
+			BodySize:    len(entry.Body),
+		},
+		Response: harResponse{
+			Cookies:     []harNameValue{},
+			Headers:     []harNameValue{},
+			Content:     harContent{MimeType: harUnknownMimeType},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		Comment: entry.Name,
+	}
+
+	for name, value := range entry.Headers {
+		switch strings.ToLower(name) {
+		case "cookie":
+			if cookies, err := http.ParseCookie(value); err == nil {
+				for _, cookie := range cookies {
+					e.Request.Cookies = append(e.Request.Cookies, harNameValue{Name: cookie.Name, Value: cookie.Value})
+				}
+			}
+		case "content-type":
+			if entry.Body != "" {
+				e.Request.PostData = &harPostData{MimeType: value, Text: entry.Body}
+			}
+		}
+	}
+	if entry.Body != "" && e.Request.PostData == nil {
+		e.Request.PostData = &harPostData{Text: entry.Body}
+	}
+
+	if resp := entry.Response; resp != nil {
+		e.Response.Status = resp.StatusCode
+		e.Response.StatusText = strings.TrimSpace(strings.TrimPrefix(resp.Status, strconv.Itoa(resp.StatusCode)))
+		e.Response.Content.Size = resp.BodySize
+		e.Response.Content.Text = resp.Body
+		if resp.BodyTruncated {
+			e.Response.Content.Comment = fmt.Sprintf("%d of %d bytes recorded", len(resp.Body), resp.BodySize)
+		}
+		e.Time = float64(resp.DurationMS)
+		e.Timings.Wait = e.Time
+	}
+
+	return e
+}
+
+// harHeaders converts a header map to HAR's name/value list, sorted by name
+func harHeaders(headers map[string]string) []harNameValue {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	list := make([]harNameValue, len(names))
+	for i, name := range names {
+		list[i] = harNameValue{Name: name, Value: headers[name]}
+	}
+	return list
+}
+
+// harQueryString lists rawURL's query params in order, decoded
+func harQueryString(rawURL string) []harNameValue {
+	params := []harNameValue{}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return params
+	}
+	for _, pair := range strings.Split(u.RawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(pair, "=")
+		if decoded, err := url.QueryUnescape(name); err == nil {
+			name = decoded
+		}
+		if decoded, err := url.QueryUnescape(value); err == nil {
+			value = decoded
+		}
+		params = append(params, harNameValue{Name: name, Value: value})
+	}
+	return params
+}
+
+// creatorVersion is the running binary's module version, for the HAR creator
+func creatorVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "(devel)"
+}
@@ -0,0 +1,240 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"onioncli/pkg/api"
+)
+
+func TestImportHAR(t *testing.T) {
+	entries, err := ImportHAR(context.Background(), filepath.Join("testdata", "session.har"), nil)
+	if err != nil {
+		t.Fatalf("ImportHAR() failed: %v", err)
+	}
+
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries (data: URI skipped), got %d", len(entries))
+	}
+
+	get := entries[0]
+	if get.Method != "GET" || get.URL != "http://exampleonionaddr.onion/api/users?page=2" {
+		t.Errorf("Unexpected first request: %s %s", get.Method, get.URL)
+	}
+	wantHeaders := map[string]string{
+		"Accept": "application/json",
+		"Cookie": "session=abc; theme=dark",
+	}
+	if !reflect.DeepEqual(get.Headers, wantHeaders) {
+		t.Errorf("Headers = %v, want %v (pseudo-headers dropped, cookies combined)", get.Headers, wantHeaders)
+	}
+	if get.Response == nil || get.Response.StatusCode != 200 || get.Response.Status != "200 OK" || get.Response.DurationMS != 1234 {
+		t.Errorf("Expected response metadata 200 OK in 1234ms, got %+v", get.Response)
+	}
+	if want := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC); !get.Timestamp.Equal(want) {
+		t.Errorf("Timestamp = %v, want %v", get.Timestamp, want)
+	}
+
+	post := entries[1]
+	if post.Method != "POST" {
+		t.Errorf("Expected method to be upper-cased, got %q", post.Method)
+	}
+	if post.Body != "pass=s3cret&user=alice" {
+		t.Errorf("Expected form params to be encoded as the body, got %q", post.Body)
+	}
+	if _, ok := post.Headers["Content-Length"]; ok {
+		t.Error("Expected Content-Length to be dropped")
+	}
+
+	search := entries[2]
+	if search.URL != "http://example.com/search?q=tor+hidden" {
+		t.Errorf("Expected query string to be rebuilt from queryString, got %q", search.URL)
+	}
+	if search.Response != nil {
+		t.Errorf("Expected no response metadata, got %+v", search.Response)
+	}
+
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.ID == "" || seen[entry.ID] {
+			t.Errorf("Expected unique IDs, got %q", entry.ID)
+		}
+		seen[entry.ID] = true
+	}
+}
+
+func TestImportHARErrors(t *testing.T) {
+	dir := t.TempDir()
+	invalid := filepath.Join(dir, "invalid.har")
+	if err := os.WriteFile(invalid, []byte("not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ImportHAR(context.Background(), filepath.Join(dir, "missing.har"), nil); err == nil {
+		t.Error("Expected error for missing file")
+	}
+	if _, err := ImportHAR(context.Background(), invalid, nil); err == nil {
+		t.Error("Expected error for invalid JSON")
+	}
+}
+
+func TestManagerAddImported(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	var reported [][2]int
+	progress := progressFunc(func(done, total int) { reported = append(reported, [2]int{done, total}) })
+	imported, err := ImportHAR(context.Background(), filepath.Join("testdata", "session.har"), progress)
+	if err != nil {
+		t.Fatalf("ImportHAR() failed: %v", err)
+	}
+	if len(reported) == 0 || reported[len(reported)-1][0] != reported[len(reported)-1][1] {
+		t.Errorf("Expected progress to finish at the total, got %v", reported)
+	}
+	if err := manager.AddImported(imported); err != nil {
+		t.Fatalf("AddImported() failed: %v", err)
+	}
+	if err := manager.AddImported(nil); err == nil {
+		t.Error("Expected an error when nothing was imported")
+	}
+
+	// Imported entries persist and the latest capture is listed first
+	reloaded, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to reload manager: %v", err)
+	}
+	entries := reloaded.GetEntries()
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 persisted entries, got %d", len(entries))
+	}
+	if entries[0].URL != "http://example.com/search?q=tor+hidden" {
+		t.Errorf("Expected most recent capture first, got %s", entries[0].URL)
+	}
+}
+
+func TestImportHARCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ImportHAR(ctx, filepath.Join("testdata", "session.har"), nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+// progressFunc adapts a function to ProgressReporter
+type progressFunc func(done, total int)
+
+func (f progressFunc) Report(done, total int) { f(done, total) }
+
+func TestExportHAR(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	login := api.NewRequest("POST", "http://example.onion/login?next=%2Fhome&debug")
+	login.SetHeader("Content-Type", "application/json")
+	login.SetHeader("Cookie", "session=abc; theme=dark")
+	login.SetBody(`{"user": "alice"}`)
+	resp := &api.Response{StatusCode: 201, Status: "201 Created", Body: `{"ok": true}`, Duration: 250 * time.Millisecond}
+	if err := manager.Save(login, resp, "Login", ""); err != nil {
+		t.Fatalf("Failed to save request: %v", err)
+	}
+	if err := manager.Save(api.NewRequest("GET", "http://example.onion/pending"), nil, "Pending", ""); err != nil {
+		t.Fatalf("Failed to save request: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "history.har")
+	if err := manager.ExportHAR(path); err != nil {
+		t.Fatalf("ExportHAR() failed: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("Expected the export to be private (0600), got %v", perm)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var har harFile
+	if err := json.Unmarshal(data, &har); err != nil {
+		t.Fatalf("Exported HAR is not valid JSON: %v", err)
+	}
+
+	if har.Log.Version != "1.2" || har.Log.Creator.Name != "onioncli" {
+		t.Errorf("Unexpected log header: %+v %+v", har.Log.Version, har.Log.Creator)
+	}
+	if len(har.Log.Entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(har.Log.Entries))
+	}
+
+	first := har.Log.Entries[0]
+	if first.Request.Method != "POST" || first.Comment != "Login" {
+		t.Errorf("Expected the oldest entry first, got %s %q", first.Request.Method, first.Comment)
+	}
+	wantHeaders := []harNameValue{{"Content-Type", "application/json"}, {"Cookie", "session=abc; theme=dark"}}
+	if !reflect.DeepEqual(first.Request.Headers, wantHeaders) {
+		t.Errorf("Headers = %v, want %v", first.Request.Headers, wantHeaders)
+	}
+	wantCookies := []harNameValue{{"session", "abc"}, {"theme", "dark"}}
+	if !reflect.DeepEqual(first.Request.Cookies, wantCookies) {
+		t.Errorf("Cookies = %v, want %v", first.Request.Cookies, wantCookies)
+	}
+	wantQuery := []harNameValue{{"next", "/home"}, {"debug", ""}}
+	if !reflect.DeepEqual(first.Request.QueryString, wantQuery) {
+		t.Errorf("QueryString = %v, want %v", first.Request.QueryString, wantQuery)
+	}
+	if first.Request.PostData == nil || first.Request.PostData.MimeType != "application/json" || first.Request.PostData.Text != `{"user": "alice"}` {
+		t.Errorf("Unexpected postData: %+v", first.Request.PostData)
+	}
+	if first.Response.Status != 201 || first.Response.StatusText != "Created" || first.Response.Content.Text != `{"ok": true}` {
+		t.Errorf("Unexpected response: %+v", first.Response)
+	}
+	if first.Time != 250 || first.Timings.Wait != 250 {
+		t.Errorf("Expected 250ms spent waiting, got time %v, timings %+v", first.Time, first.Timings)
+	}
+	if started, err := time.Parse(time.RFC3339Nano, first.StartedDateTime); err != nil || !started.Equal(manager.GetEntries()[1].Timestamp) {
+		t.Errorf("startedDateTime = %q, want the entry timestamp", first.StartedDateTime)
+	}
+
+	pending := har.Log.Entries[1]
+	if pending.Response.Status != 0 || pending.Request.PostData != nil || pending.Time != 0 {
+		t.Errorf("Expected an entry without a response to have status 0 and no body, got %+v", pending)
+	}
+
+	// Required arrays must be present even when empty
+	var raw struct {
+		Log struct {
+			Entries []struct {
+				Response map[string]json.RawMessage `json:"response"`
+			} `json:"entries"`
+		} `json:"log"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{"cookies", "headers"} {
+		if got := string(raw.Log.Entries[1].Response[field]); got != "[]" {
+			t.Errorf("response.%s = %s, want []", field, got)
+		}
+	}
+
+	// The export reads back in
+	entries, err := ImportHAR(context.Background(), path, nil)
+	if err != nil || len(entries) != 2 || entries[0].Body != `{"user": "alice"}` {
+		t.Errorf("Expected the export to import again, got %v (%v)", entries, err)
+	}
+}
@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"onioncli/pkg/api"
 )
@@ -20,12 +23,41 @@ type HistoryEntry struct {
 	Body        string            `json:"body"`
 	Timestamp   time.Time         `json:"timestamp"`
 	Description string            `json:"description"`
+	IsFavorite  bool              `json:"is_favorite,omitempty"` // kept however long history grows
+
+	// Response is what the request got back, set for entries imported from a
+	// HAR capture or saved after a response arrived
+	Response *ResponseSnapshot `json:"response,omitempty"`
+}
+
+// ResponseSnapshot is the response a history entry received. Body is capped
+// at the manager's limit; BodyHash is always of the full body, so runs still
+// compare correctly when their bodies were cut short.
+type ResponseSnapshot struct {
+	StatusCode    int       `json:"status_code"`
+	Status        string    `json:"status"`
+	DurationMS    int64     `json:"duration_ms"`
+	Body          string    `json:"body,omitempty"`
+	BodySize      int       `json:"body_size"`
+	BodyTruncated bool      `json:"body_truncated,omitempty"`
+	BodyHash      string    `json:"body_hash,omitempty"`
+	Timestamp     time.Time `json:"timestamp"`
 }
 
+// DefaultMaxResponseBody is how many bytes of a response body are kept with
+// an entry unless SetMaxResponseBody says otherwise
+const DefaultMaxResponseBody = 64 * 1024
+
+// DefaultMaxEntries is how many entries history keeps unless SetMaxEntries
+// says otherwise
+const DefaultMaxEntries = 100
+
 // Manager handles request history persistence
 type Manager struct {
-	historyFile string
-	entries     []HistoryEntry
+	historyFile     string
+	entries         []HistoryEntry
+	maxEntries      int // 0 keeps every entry
+	maxResponseBody int
 }
 
 // NewManager creates a new history manager
@@ -43,8 +75,10 @@ func NewManager() (*Manager, error) {
 	historyFile := filepath.Join(configDir, "history.json")
 
 	manager := &Manager{
-		historyFile: historyFile,
-		entries:     make([]HistoryEntry, 0),
+		historyFile:     historyFile,
+		entries:         make([]HistoryEntry, 0),
+		maxEntries:      DefaultMaxEntries,
+		maxResponseBody: DefaultMaxResponseBody,
 	}
 
 	// Load existing history
@@ -58,8 +92,22 @@ func NewManager() (*Manager, error) {
 	return manager, nil
 }
 
-// Save saves a request to history
-func (m *Manager) Save(req *api.Request, name, description string) error {
+// SetMaxEntries sets how many entries history keeps, dropping the oldest
+// beyond that on the next save or import; 0 keeps every entry. Favorites
+// are always kept and don't count towards the limit.
+func (m *Manager) SetMaxEntries(limit int) {
+	m.maxEntries = max(limit, 0)
+}
+
+// SetMaxResponseBody sets how many bytes of each response body are stored;
+// 0 keeps only the status, timing and hash
+func (m *Manager) SetMaxResponseBody(limit int) {
+	m.maxResponseBody = max(limit, 0)
+}
+
+// Save saves a request to history along with the response it received, so
+// it can be reviewed and later runs compared against it. resp may be nil.
+func (m *Manager) Save(req *api.Request, resp *api.Response, name, description string) error {
 	entry := HistoryEntry{
 		ID:          generateID(),
 		Name:        name,
@@ -76,15 +124,67 @@ func (m *Manager) Save(req *api.Request, name, description string) error {
 		entry.Headers[k] = v
 	}
 
+	if resp != nil {
+		entry.Response = m.snapshot(resp)
+	}
+
 	// Add to entries (prepend to show most recent first)
 	m.entries = append([]HistoryEntry{entry}, m.entries...)
+	m.trim()
 
-	// Limit history size to 100 entries
-	if len(m.entries) > 100 {
-		m.entries = m.entries[:100]
+	return m.saveToFile()
+}
+
+// snapshot records resp, its body cut to the manager's limit. Binary bodies
+// aren't kept, as they don't survive being stored as JSON text.
+func (m *Manager) snapshot(resp *api.Response) *ResponseSnapshot {
+	snapshot := &ResponseSnapshot{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		DurationMS: resp.Duration.Milliseconds(),
+		BodySize:   len(resp.Body),
+		BodyHash:   resp.BodyHash(),
+		Timestamp:  resp.Timestamp,
+	}
+	if snapshot.Timestamp.IsZero() {
+		snapshot.Timestamp = time.Now()
+	}
+	if !utf8.ValidString(resp.Body) {
+		snapshot.BodyTruncated = resp.Body != ""
+		return snapshot
 	}
 
-	return m.saveToFile()
+	snapshot.Body = resp.Body
+	if len(snapshot.Body) > m.maxResponseBody {
+		cut := m.maxResponseBody
+		// Don't split a multi-byte character
+		for cut > 0 && !utf8.RuneStart(snapshot.Body[cut]) {
+			cut--
+		}
+		snapshot.Body = snapshot.Body[:cut]
+		snapshot.BodyTruncated = true
+	}
+	return snapshot
+}
+
+// trim drops the entries beyond the configured maximum, not counting
+// favorites. Entries are kept most recent first, so the oldest go.
+func (m *Manager) trim() {
+	if m.maxEntries == 0 {
+		return
+	}
+	kept := m.entries[:0]
+	others := 0
+	for _, entry := range m.entries {
+		if !entry.IsFavorite {
+			if others == m.maxEntries {
+				continue
+			}
+			others++
+		}
+		kept = append(kept, entry)
+	}
+	m.entries = kept
 }
 
 // Load loads history from file
@@ -122,6 +222,47 @@ func (m *Manager) GetEntry(id string) (*HistoryEntry, error) {
 	return nil, fmt.Errorf("entry with ID %s not found", id)
 }
 
+// HasResponse reports whether the entry has a stored response body to compare
+func (entry *HistoryEntry) HasResponse() bool {
+	return entry.Response != nil && entry.Response.BodyHash != ""
+}
+
+// PreviousRun finds the most recent earlier entry for the same request that has
+// a stored response. Entries match by name, or by method and URL when unnamed.
+// Returns nil if there is no earlier run.
+func (m *Manager) PreviousRun(id string) (*HistoryEntry, error) {
+	index := -1
+	for i, entry := range m.entries {
+		if entry.ID == id {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("entry with ID %s not found", id)
+	}
+
+	current := m.entries[index]
+	// Entries are stored newest first, so earlier runs follow this one
+	for _, entry := range m.entries[index+1:] {
+		if !entry.HasResponse() {
+			continue
+		}
+		if sameRequest(current, entry) {
+			return &entry, nil
+		}
+	}
+	return nil, nil
+}
+
+// sameRequest reports whether two entries are runs of the same request
+func sameRequest(a, b HistoryEntry) bool {
+	if a.Name != "" || b.Name != "" {
+		return a.Name == b.Name
+	}
+	return a.Method == b.Method && a.URL == b.URL
+}
+
 // ToRequest converts a history entry back to an API request
 func (entry *HistoryEntry) ToRequest() *api.Request {
 	req := api.NewRequest(entry.Method, entry.URL)
@@ -139,6 +280,18 @@ func (entry *HistoryEntry) ToRequest() *api.Request {
 	return req
 }
 
+// Contains reports whether a request with the same method, URL and body has
+// already been saved
+func (m *Manager) Contains(req *api.Request) bool {
+	fingerprint := req.Fingerprint()
+	for i := range m.entries {
+		if m.entries[i].ToRequest().Fingerprint() == fingerprint {
+			return true
+		}
+	}
+	return false
+}
+
 // Delete removes an entry from history
 func (m *Manager) Delete(id string) error {
 	for i, entry := range m.entries {
@@ -151,6 +304,40 @@ func (m *Manager) Delete(id string) error {
 	return fmt.Errorf("entry with ID %s not found", id)
 }
 
+// DeleteMany removes the entries with the given IDs, saving once, and
+// returns how many were removed. IDs no longer in history are skipped.
+func (m *Manager) DeleteMany(ids []string) (int, error) {
+	remove := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		remove[id] = true
+	}
+
+	kept := m.entries[:0]
+	for _, entry := range m.entries {
+		if !remove[entry.ID] {
+			kept = append(kept, entry)
+		}
+	}
+	removed := len(m.entries) - len(kept)
+	m.entries = kept
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, m.saveToFile()
+}
+
+// ToggleFavorite marks or unmarks an entry as a favorite, returning whether
+// it now is one
+func (m *Manager) ToggleFavorite(id string) (bool, error) {
+	for i := range m.entries {
+		if m.entries[i].ID == id {
+			m.entries[i].IsFavorite = !m.entries[i].IsFavorite
+			return m.entries[i].IsFavorite, m.saveToFile()
+		}
+	}
+	return false, fmt.Errorf("entry with ID %s not found", id)
+}
+
 // Clear removes all entries from history
 func (m *Manager) Clear() error {
 	m.entries = make([]HistoryEntry, 0)
@@ -162,10 +349,7 @@ func (m *Manager) Search(query string) []HistoryEntry {
 	var results []HistoryEntry
 
 	for _, entry := range m.entries {
-		if contains(entry.Name, query) ||
-			contains(entry.URL, query) ||
-			contains(entry.Description, query) ||
-			contains(entry.Method, query) {
+		if entry.matches(query) {
 			results = append(results, entry)
 		}
 	}
@@ -173,6 +357,48 @@ func (m *Manager) Search(query string) []HistoryEntry {
 	return results
 }
 
+// Count returns how many entries history holds
+func (m *Manager) Count() int {
+	return len(m.entries)
+}
+
+// GetEntriesPage returns up to limit entries starting at offset, most recent
+// first, so large histories can be shown a page at a time
+func (m *Manager) GetEntriesPage(offset, limit int) []HistoryEntry {
+	return page(m.entries, offset, limit)
+}
+
+// SearchPage returns up to limit of the entries matching query, skipping
+// the first offset matches, along with how many match in all. An empty
+// query matches every entry; favoritesOnly leaves out the rest.
+func (m *Manager) SearchPage(query string, favoritesOnly bool, offset, limit int) ([]HistoryEntry, int) {
+	if query == "" && !favoritesOnly {
+		return m.GetEntriesPage(offset, limit), len(m.entries)
+	}
+
+	var results []HistoryEntry
+	total := 0
+	for _, entry := range m.entries {
+		if !entry.matches(query) || (favoritesOnly && !entry.IsFavorite) {
+			continue
+		}
+		if total >= offset && len(results) < limit {
+			results = append(results, entry)
+		}
+		total++
+	}
+	return results, total
+}
+
+// page copies the window of entries from offset, at most limit long
+func page(entries []HistoryEntry, offset, limit int) []HistoryEntry {
+	if offset < 0 || limit <= 0 || offset >= len(entries) {
+		return nil
+	}
+	end := min(offset+limit, len(entries))
+	return slices.Clone(entries[offset:end])
+}
+
 // GetRecentEntries returns the most recent N entries
 func (m *Manager) GetRecentEntries(limit int) []HistoryEntry {
 	if limit > len(m.entries) {
@@ -186,40 +412,18 @@ func generateID() string {
 	return fmt.Sprintf("%d", time.Now().UnixNano())
 }
 
+// matches reports whether the entry's name, URL, description or method
+// contains query, ignoring case
+func (entry *HistoryEntry) matches(query string) bool {
+	return contains(entry.Name, query) ||
+		contains(entry.URL, query) ||
+		contains(entry.Description, query) ||
+		contains(entry.Method, query)
+}
+
 // contains checks if a string contains a substring (case-insensitive)
 func contains(s, substr string) bool {
-	if substr == "" {
-		return true
-	}
-	// Simple case-insensitive search - convert both to lowercase
-	sLower := ""
-	substrLower := ""
-
-	// Manual lowercase conversion to avoid importing strings
-	for _, r := range s {
-		if r >= 'A' && r <= 'Z' {
-			sLower += string(r + 32)
-		} else {
-			sLower += string(r)
-		}
-	}
-
-	for _, r := range substr {
-		if r >= 'A' && r <= 'Z' {
-			substrLower += string(r + 32)
-		} else {
-			substrLower += string(r)
-		}
-	}
-
-	// Check if substring exists
-	for i := 0; i <= len(sLower)-len(substrLower); i++ {
-		if sLower[i:i+len(substrLower)] == substrLower {
-			return true
-		}
-	}
-
-	return false
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
 }
 
 // Export exports history to a JSON file
@@ -244,13 +448,13 @@ func (m *Manager) Import(filename string) error {
 		return fmt.Errorf("failed to unmarshal import data: %w", err)
 	}
 
-	// Merge with existing entries (imported entries go to the end)
+	// Merge with existing entries, most recent first, so the oldest of
+	// either are the ones trimmed
 	m.entries = append(m.entries, importedEntries...)
-
-	// Limit total size
-	if len(m.entries) > 100 {
-		m.entries = m.entries[:100]
-	}
+	slices.SortStableFunc(m.entries, func(a, b HistoryEntry) int {
+		return b.Timestamp.Compare(a.Timestamp)
+	})
+	m.trim()
 
 	return m.saveToFile()
 }
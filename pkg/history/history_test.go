@@ -0,0 +1,395 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"onioncli/pkg/api"
+)
+
+func TestContains(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	saved := api.NewRequest("POST", "http://example.onion/users")
+	saved.SetBody(`{"name":"alice"}`)
+	if err := manager.Save(saved, nil, "Create user", ""); err != nil {
+		t.Fatalf("Failed to save request: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		body     string
+		expected bool
+	}{
+		{"same request", "POST", "http://example.onion/users", `{"name":"alice"}`, true},
+		{"different body", "POST", "http://example.onion/users", `{"name":"bob"}`, false},
+		{"different method", "PUT", "http://example.onion/users", `{"name":"alice"}`, false},
+		{"different URL", "POST", "http://example.onion/admins", `{"name":"alice"}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := api.NewRequest(tt.method, tt.url)
+			req.SetBody(tt.body)
+			if got := manager.Contains(req); got != tt.expected {
+				t.Errorf("Expected Contains to be %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestPreviousRun(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	req := api.NewRequest("GET", "http://example.onion/status")
+	save := func(name, body string, withResponse bool) string {
+		t.Helper()
+		var resp *api.Response
+		if withResponse {
+			resp = &api.Response{StatusCode: 200, Status: "200 OK", Body: body}
+		}
+		if err := manager.Save(req, resp, name, ""); err != nil {
+			t.Fatalf("Failed to save request: %v", err)
+		}
+		return manager.GetEntries()[0].ID
+	}
+
+	first := save("Status", `{"ok":true}`, true)
+	save("Other", `{"ok":true}`, true)
+	save("Status", "", false)
+	latest := save("Status", `{"ok":false}`, true)
+
+	previous, err := manager.PreviousRun(latest)
+	if err != nil {
+		t.Fatalf("PreviousRun failed: %v", err)
+	}
+	if previous == nil || previous.ID != first {
+		t.Fatalf("PreviousRun = %+v, want the first Status run (skipping other names and entries without a response)", previous)
+	}
+	if previous.Response.BodyHash == manager.GetEntries()[0].Response.BodyHash {
+		t.Error("Expected different bodies to have different hashes")
+	}
+
+	if previous, err := manager.PreviousRun(first); err != nil || previous != nil {
+		t.Errorf("PreviousRun(first) = %+v, %v, want nil, nil", previous, err)
+	}
+	if _, err := manager.PreviousRun("missing"); err == nil {
+		t.Error("Expected an error for an unknown entry")
+	}
+}
+
+func TestSaveResponseSnapshot(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	manager.SetMaxResponseBody(8)
+
+	req := api.NewRequest("GET", "http://example.onion/greeting")
+	received := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		body          string
+		wantBody      string
+		wantTruncated bool
+	}{
+		{"fits", "hello", "hello", false},
+		{"cut at the limit", "hello, world", "hello, w", true},
+		{"cut before a split character", "abcdefgé", "abcdefg", true},
+		{"binary not kept", "\xff\xfe\x00", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &api.Response{StatusCode: 200, Status: "200 OK", Body: tt.body, Duration: 1500 * time.Millisecond, Timestamp: received}
+			if err := manager.Save(req, resp, "Greeting", ""); err != nil {
+				t.Fatalf("Failed to save request: %v", err)
+			}
+
+			got := manager.GetEntries()[0].Response
+			if got == nil {
+				t.Fatal("Expected a response snapshot")
+			}
+			if got.Body != tt.wantBody || got.BodyTruncated != tt.wantTruncated {
+				t.Errorf("Body = %q (truncated %v), want %q (truncated %v)", got.Body, got.BodyTruncated, tt.wantBody, tt.wantTruncated)
+			}
+			if got.StatusCode != 200 || got.DurationMS != 1500 || got.BodySize != len(tt.body) || !got.Timestamp.Equal(received) {
+				t.Errorf("Unexpected snapshot: %+v", got)
+			}
+			if got.BodyHash != resp.BodyHash() {
+				t.Error("Expected the hash of the full body")
+			}
+		})
+	}
+}
+
+// syntheticManager returns a manager holding n generated entries, not
+// backed by a file
+func syntheticManager(n int) *Manager {
+	entries := make([]HistoryEntry, n)
+	started := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	for i := range entries {
+		method := "GET"
+		if i%10 == 0 {
+			method = "POST"
+		}
+		entries[i] = HistoryEntry{
+			ID:        fmt.Sprintf("%d", i),
+			Name:      fmt.Sprintf("Request %d", i),
+			Method:    method,
+			URL:       fmt.Sprintf("http://example.onion/items/%d", i),
+			Headers:   map[string]string{"Accept": "application/json"},
+			Timestamp: started.Add(-time.Duration(i) * time.Minute),
+		}
+	}
+	return &Manager{entries: entries, maxResponseBody: DefaultMaxResponseBody}
+}
+
+func TestGetEntriesPage(t *testing.T) {
+	manager := syntheticManager(250)
+
+	tests := []struct {
+		name      string
+		offset    int
+		limit     int
+		wantFirst string
+		wantLen   int
+	}{
+		{"first page", 0, 100, "0", 100},
+		{"middle page", 100, 100, "100", 100},
+		{"last page is short", 200, 100, "200", 50},
+		{"past the end", 250, 100, "", 0},
+		{"negative offset", -1, 100, "", 0},
+		{"no limit", 0, 0, "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := manager.GetEntriesPage(tt.offset, tt.limit)
+			if len(page) != tt.wantLen {
+				t.Fatalf("Expected %d entries, got %d", tt.wantLen, len(page))
+			}
+			if len(page) > 0 && page[0].ID != tt.wantFirst {
+				t.Errorf("Expected page to start at entry %s, got %s", tt.wantFirst, page[0].ID)
+			}
+		})
+	}
+
+	if manager.Count() != 250 {
+		t.Errorf("Count() = %d, want 250", manager.Count())
+	}
+	page := manager.GetEntriesPage(0, 1)
+	page[0].Name = "changed"
+	if manager.GetEntriesPage(0, 1)[0].Name == "changed" {
+		t.Error("Expected pages to be copies")
+	}
+}
+
+func TestSearchPage(t *testing.T) {
+	manager := syntheticManager(250)
+
+	page, total := manager.SearchPage("post", false, 10, 5)
+	if total != 25 {
+		t.Errorf("Expected 25 POST entries in all, got %d", total)
+	}
+	if len(page) != 5 || page[0].ID != "100" || page[4].ID != "140" {
+		t.Errorf("Expected the 11th to 15th matches, got %+v", page)
+	}
+
+	if page, total := manager.SearchPage("", false, 240, 100); total != 250 || len(page) != 10 {
+		t.Errorf("Expected an empty query to page through everything, got %d of %d", len(page), total)
+	}
+	if page, total := manager.SearchPage("ITEMS/249", false, 0, 100); total != 1 || len(page) != 1 {
+		t.Errorf("Expected a case-insensitive URL match, got %d of %d", len(page), total)
+	}
+	if page, total := manager.SearchPage("missing", false, 0, 100); total != 0 || page != nil {
+		t.Errorf("Expected no matches, got %d of %d", len(page), total)
+	}
+}
+
+func BenchmarkGetEntriesPage(b *testing.B) {
+	manager := syntheticManager(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		offset := (i * 100) % manager.Count()
+		manager.GetEntriesPage(offset, 100)
+	}
+}
+
+func BenchmarkSearchPage(b *testing.B) {
+	manager := syntheticManager(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		manager.SearchPage("items/9", false, 0, 100)
+	}
+}
+
+func TestMaxEntries(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	manager.SetMaxEntries(3)
+
+	for i := 0; i < 5; i++ {
+		if err := manager.Save(api.NewRequest("GET", fmt.Sprintf("http://example.onion/%d", i)), nil, fmt.Sprintf("Request %d", i), ""); err != nil {
+			t.Fatalf("Failed to save request: %v", err)
+		}
+	}
+	var names []string
+	for _, entry := range manager.GetEntries() {
+		names = append(names, entry.Name)
+	}
+	if strings.Join(names, ",") != "Request 4,Request 3,Request 2" {
+		t.Errorf("Expected the 3 most recent saves, got %v", names)
+	}
+
+	// An import keeps the most recent of the existing and imported entries
+	old := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	imported := []HistoryEntry{
+		{ID: "new", Name: "Imported new", Method: "GET", URL: "http://example.onion/new", Timestamp: time.Now().Add(time.Hour)},
+		{ID: "old", Name: "Imported old", Method: "GET", URL: "http://example.onion/old", Timestamp: old},
+	}
+	data, err := json.Marshal(imported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "import.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Import(path); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	names = nil
+	for _, entry := range manager.GetEntries() {
+		names = append(names, entry.Name)
+	}
+	if strings.Join(names, ",") != "Imported new,Request 4,Request 3" {
+		t.Errorf("Expected the newest import then the most recent saves, got %v", names)
+	}
+
+	// 0 keeps everything
+	manager.SetMaxEntries(0)
+	for i := 0; i < 5; i++ {
+		if err := manager.Save(api.NewRequest("GET", "http://example.onion/more"), nil, "More", ""); err != nil {
+			t.Fatalf("Failed to save request: %v", err)
+		}
+	}
+	if manager.Count() != 8 {
+		t.Errorf("Expected unlimited history to keep all 8 entries, got %d", manager.Count())
+	}
+}
+
+func TestToggleFavorite(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	manager.SetMaxEntries(2)
+
+	if err := manager.Save(api.NewRequest("GET", "http://example.onion/keep"), nil, "Keep", ""); err != nil {
+		t.Fatalf("Failed to save request: %v", err)
+	}
+	id := manager.GetEntries()[0].ID
+	favorite, err := manager.ToggleFavorite(id)
+	if err != nil || !favorite {
+		t.Fatalf("Expected the entry to become a favorite, got %v, %v", favorite, err)
+	}
+
+	// The favorite outlives max_entries and doesn't count towards it
+	for i := 0; i < 4; i++ {
+		if err := manager.Save(api.NewRequest("GET", fmt.Sprintf("http://example.onion/%d", i)), nil, fmt.Sprintf("Request %d", i), ""); err != nil {
+			t.Fatalf("Failed to save request: %v", err)
+		}
+	}
+	var names []string
+	for _, entry := range manager.GetEntries() {
+		names = append(names, entry.Name)
+	}
+	if strings.Join(names, ",") != "Request 3,Request 2,Keep" {
+		t.Errorf("Expected the 2 most recent saves and the favorite, got %v", names)
+	}
+
+	favorites, total := manager.SearchPage("", true, 0, 100)
+	if total != 1 || len(favorites) != 1 || favorites[0].ID != id {
+		t.Errorf("Expected only the favorite, got %d of %d", len(favorites), total)
+	}
+	if _, total := manager.SearchPage("request", true, 0, 100); total != 0 {
+		t.Errorf("Expected no favorites matching 'request', got %d", total)
+	}
+
+	// The flag is persisted
+	reloaded, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to reload manager: %v", err)
+	}
+	entry, err := reloaded.GetEntry(id)
+	if err != nil || !entry.IsFavorite {
+		t.Fatalf("Expected the favorite to survive a reload, got %+v, %v", entry, err)
+	}
+
+	if favorite, err := manager.ToggleFavorite(id); err != nil || favorite {
+		t.Errorf("Expected the entry to stop being a favorite, got %v, %v", favorite, err)
+	}
+	if _, err := manager.ToggleFavorite("missing"); err == nil {
+		t.Error("Expected an error for an unknown entry")
+	}
+}
+
+func TestDeleteMany(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		if err := manager.Save(api.NewRequest("GET", fmt.Sprintf("http://example.onion/%d", i)), nil, fmt.Sprintf("Request %d", i), ""); err != nil {
+			t.Fatalf("Failed to save request: %v", err)
+		}
+	}
+	entries := manager.GetEntries()
+	ids := []string{entries[0].ID, entries[2].ID, "missing"}
+
+	removed, err := manager.DeleteMany(ids)
+	if err != nil {
+		t.Fatalf("DeleteMany failed: %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("Expected 2 entries removed, got %d", removed)
+	}
+
+	reloaded, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to reload manager: %v", err)
+	}
+	var names []string
+	for _, entry := range reloaded.GetEntries() {
+		names = append(names, entry.Name)
+	}
+	if strings.Join(names, ",") != "Request 2,Request 0" {
+		t.Errorf("Expected the unselected entries to remain, got %v", names)
+	}
+
+	if removed, err := reloaded.DeleteMany([]string{"missing"}); err != nil || removed != 0 {
+		t.Errorf("Expected nothing removed for unknown IDs, got %d, %v", removed, err)
+	}
+}
@@ -0,0 +1,244 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"onioncli/pkg/api"
+	"onioncli/pkg/collections"
+)
+
+// Accessible mode renders responses, errors and status messages as linear
+// plain text without borders, colours or emoji, for screen readers.
+
+// statusLabels are the spoken equivalents of the status indicator icons
+var statusLabels = map[StatusType]string{
+	StatusInfo:    "Info",
+	StatusSuccess: "Success",
+	StatusWarning: "Warning",
+	StatusError:   "Error",
+	StatusLoading: "Working",
+}
+
+// describeResponse summarises a response in one sentence per fact, e.g.
+// "Status 200 OK. Duration 1.2 seconds. 5 headers. Body 1.4 kilobytes, JSON."
+func describeResponse(response *api.Response) string {
+	facts := []string{
+		fmt.Sprintf("Status %s.", response.Status),
+		fmt.Sprintf("Duration %s.", describeDuration(response.Duration)),
+		fmt.Sprintf("%d %s.", len(response.Headers), plural(len(response.Headers), "header", "headers")),
+	}
+
+	if response.Body == "" {
+		facts = append(facts, "No body.")
+	} else {
+		contentType, sniffed := response.ContentType()
+		described := describeContentType(contentType)
+		if sniffed {
+			described += " (sniffed, no valid Content-Type)"
+		}
+		facts = append(facts, fmt.Sprintf("Body %s, %s.", describeSize(len(response.Body)), described))
+	}
+
+	if response.LengthMismatch {
+		facts = append(facts, fmt.Sprintf("Body length warning: %s.", response.LengthWarning))
+	}
+
+	if len(response.Transforms) > 0 {
+		facts = append(facts, fmt.Sprintf("Body post-processed with %s.", strings.Join(response.Transforms, ", then ")))
+	}
+
+	if info := response.TLSInfo; info != nil && info.HasWarnings() {
+		facts = append(facts, fmt.Sprintf("Certificate warning: %s.", strings.Join(certificateProblems(info), ", ")))
+	}
+
+	return strings.Join(facts, " ")
+}
+
+// formatAccessibleResponse renders the summary followed by any test
+// results, headers, certificate and body as plain text. An HTML body is
+// shown as its extracted text in the text view, and as markup otherwise.
+func formatAccessibleResponse(response *api.Response, tests []collections.TestResult, htmlView htmlViewMode) string {
+	sections := []string{describeResponse(response), ""}
+
+	if len(tests) > 0 {
+		sections = append(sections, describeTestResults(tests)...)
+		sections = append(sections, "")
+	}
+
+	if response.FinalURL != "" {
+		sections = append(sections, fmt.Sprintf("Redirected to %s.", response.FinalURL), "")
+	}
+
+	if len(response.Headers) > 0 {
+		keys := make([]string, 0, len(response.Headers))
+		for key := range response.Headers {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		sections = append(sections, "Headers:")
+		for _, key := range keys {
+			sections = append(sections, fmt.Sprintf("%s: %s", key, response.Headers[key]))
+		}
+		sections = append(sections, "")
+	}
+
+	if info := response.TLSInfo; info != nil {
+		sections = append(sections,
+			"Certificate:",
+			fmt.Sprintf("Subject: %s", info.Subject),
+			fmt.Sprintf("Issuer: %s", info.Issuer),
+			fmt.Sprintf("Valid from %s to %s", info.NotBefore.Format("2006-01-02"), info.NotAfter.Format("2006-01-02")),
+			fmt.Sprintf("Protocol: %s, %s", info.Version, info.CipherSuite),
+			"")
+	}
+
+	if response.IsBinary() {
+		sections = append(sections, "Body: "+binaryHint)
+	} else if response.Body != "" {
+		body, err := response.PrettyPrintJSON()
+		if err != nil {
+			body = response.Body
+		}
+		if response.IsHTML() && htmlView == htmlText {
+			body = response.HTMLText()
+		} else if response.IsXML() {
+			body, _ = response.PrettyPrintXML()
+		}
+		sections = append(sections, "Body:", body)
+	}
+
+	return strings.Join(sections, "\n")
+}
+
+// formatAccessibleError renders a diagnostic error as plain text
+func formatAccessibleError(err *api.DiagnosticError) string {
+	sections := []string{
+		fmt.Sprintf("%s error. %s", errorTypeName(err.Type), err.Message),
+	}
+
+	if err.URL != "" {
+		sections = append(sections, fmt.Sprintf("URL: %s", err.URL))
+	}
+	if err.StatusCode != 0 {
+		sections = append(sections, fmt.Sprintf("Status code: %d", err.StatusCode))
+	}
+
+	if len(err.Attempts) > 0 {
+		sections = append(sections, "", "Attempts:")
+		for _, attempt := range err.Attempts {
+			sections = append(sections, fmt.Sprintf("%s, %v.", attempt, attempt.Duration.Round(time.Millisecond)))
+		}
+	}
+
+	if len(err.Redirects) > 0 {
+		sections = append(sections, "", "Redirect chain:")
+		for i, hop := range err.Redirects {
+			if hop.StatusCode == 0 {
+				sections = append(sections, fmt.Sprintf("%d. %s", i+1, hop.URL))
+			} else {
+				sections = append(sections, fmt.Sprintf("%d. %s, after status %d.", i+1, hop.URL, hop.StatusCode))
+			}
+		}
+	}
+
+	if len(err.Probes) > 0 {
+		sections = append(sections, "", "Connection checks:")
+		for _, result := range err.Probes {
+			outcome := "Fail"
+			if result.Skipped {
+				outcome = "Skipped"
+			} else if result.Passed {
+				outcome = "Pass"
+			}
+			sections = append(sections, fmt.Sprintf("%s: %s. %s.", result.Name, outcome, result.Detail))
+		}
+	}
+
+	if len(err.Suggestions) > 0 {
+		sections = append(sections, "", "Suggestions:")
+		for i, suggestion := range err.Suggestions {
+			sections = append(sections, fmt.Sprintf("%d. %s", i+1, suggestion))
+		}
+	}
+
+	sections = append(sections, "")
+	if err.IsRetryable() {
+		sections = append(sections, "Retrying the request might resolve this error.")
+	} else {
+		sections = append(sections, "Retrying is unlikely to resolve this error.")
+	}
+
+	return strings.Join(sections, "\n")
+}
+
+// describeDuration formats a duration in words, e.g. "1.2 seconds"
+func describeDuration(d time.Duration) string {
+	if d < time.Second {
+		ms := int(d.Milliseconds())
+		return fmt.Sprintf("%d %s", ms, plural(ms, "millisecond", "milliseconds"))
+	}
+	return fmt.Sprintf("%.1f seconds", d.Seconds())
+}
+
+// describeSize formats a byte count in words, e.g. "1.4 kilobytes"
+func describeSize(n int) string {
+	switch {
+	case n < 1024:
+		return fmt.Sprintf("%d %s", n, plural(n, "byte", "bytes"))
+	case n < 1024*1024:
+		return fmt.Sprintf("%.1f kilobytes", float64(n)/1024)
+	default:
+		return fmt.Sprintf("%.1f megabytes", float64(n)/(1024*1024))
+	}
+}
+
+// describeContentType names a Content-Type for reading aloud
+func describeContentType(contentType string) string {
+	switch contentTypeExtension(contentType) {
+	case ".json":
+		return "JSON"
+	case ".html":
+		return "HTML"
+	case ".xml":
+		return "XML"
+	case ".js":
+		return "JavaScript"
+	case ".css":
+		return "CSS"
+	case ".yaml":
+		return "YAML"
+	case ".csv":
+		return "CSV"
+	case ".md":
+		return "Markdown"
+	}
+	if contentType == "" {
+		return "unknown type"
+	}
+	return strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0])
+}
+
+// plural picks the singular or plural form for n
+func plural(n int, singular, pluralForm string) string {
+	if n == 1 {
+		return singular
+	}
+	return pluralForm
+}
+
+// errorTypeName names an error type for display, e.g. "Rate limit" for
+// rate_limit
+func errorTypeName(errorType api.ErrorType) string {
+	name := strings.ReplaceAll(string(errorType), "_", " ")
+	if name == "" {
+		return ""
+	}
+	first, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(first)) + name[size:]
+}
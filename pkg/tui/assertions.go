@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"fmt"
+
+	"onioncli/pkg/collections"
+)
+
+// countPassed returns how many of the results passed
+func countPassed(results []collections.TestResult) int {
+	passed := 0
+	for _, result := range results {
+		if result.Passed {
+			passed++
+		}
+	}
+	return passed
+}
+
+// testSummary is a one-line tally of the results, e.g. "✗ 1 of 3 tests failed"
+func testSummary(results []collections.TestResult) string {
+	passed := countPassed(results)
+	if passed == len(results) {
+		return testPassedStyle.Render(fmt.Sprintf("✓ %d of %d %s passed", passed, len(results), plural(len(results), "test", "tests")))
+	}
+	failed := len(results) - passed
+	return testFailedStyle.Render(fmt.Sprintf("✗ %d of %d %s failed", failed, len(results), plural(len(results), "test", "tests")))
+}
+
+// formatTestResults lists each test, passes in green and failures in red
+// with the reason
+func formatTestResults(results []collections.TestResult) []string {
+	lines := make([]string, 0, len(results))
+	for _, result := range results {
+		if result.Passed {
+			lines = append(lines, testPassedStyle.Render("  ✓ "+result.Test))
+			continue
+		}
+		lines = append(lines, testFailedStyle.Render(fmt.Sprintf("  ✗ %s: %s", result.Test, result.Message)))
+	}
+	return lines
+}
+
+// describeTestResults lists the results as plain sentences for accessible mode
+func describeTestResults(results []collections.TestResult) []string {
+	lines := []string{fmt.Sprintf("Tests: %d of %d passed.", countPassed(results), len(results))}
+	for _, result := range results {
+		if result.Passed {
+			lines = append(lines, fmt.Sprintf("Passed: %s.", result.Test))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("Failed: %s, %s.", result.Test, result.Message))
+	}
+	return lines
+}
@@ -102,6 +102,28 @@ func NewAuthDialog(width, height int) AuthDialog {
 	headersInput.Width = width - 20
 	inputs["headers"] = headersInput
 
+	// OAuth2 client-credentials inputs
+	tokenURLInput := textinput.New()
+	tokenURLInput.Placeholder = "Token endpoint, e.g. http://example.onion/oauth/token"
+	tokenURLInput.Width = width - 20
+	inputs["token_url"] = tokenURLInput
+
+	clientIDInput := textinput.New()
+	clientIDInput.Placeholder = "Enter client ID..."
+	clientIDInput.Width = width - 20
+	inputs["client_id"] = clientIDInput
+
+	clientSecretInput := textinput.New()
+	clientSecretInput.Placeholder = "Enter client secret..."
+	clientSecretInput.EchoMode = textinput.EchoPassword
+	clientSecretInput.Width = width - 20
+	inputs["client_secret"] = clientSecretInput
+
+	scopeInput := textinput.New()
+	scopeInput.Placeholder = "Scopes, space separated (optional)"
+	scopeInput.Width = width - 20
+	inputs["scope"] = scopeInput
+
 	return AuthDialog{
 		visible:      false,
 		authManager:  authManager,
@@ -220,7 +242,7 @@ func (ad AuthDialog) View() string {
 	return lipgloss.Place(ad.width, ad.height, lipgloss.Center, lipgloss.Center,
 		lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#7D56F4")).
+			BorderForeground(palette.Primary).
 			Padding(1).
 			Render(content))
 }
@@ -250,6 +272,12 @@ func (ad AuthDialog) renderInputFields(authType api.AuthType) string {
 
 	case api.AuthCustom:
 		sections = append(sections, ad.renderInput("headers", "Custom Headers:"))
+
+	case api.AuthOAuth2:
+		sections = append(sections, ad.renderInput("token_url", "Token URL:"))
+		sections = append(sections, ad.renderInput("client_id", "Client ID:"))
+		sections = append(sections, ad.renderInput("client_secret", "Client Secret:"))
+		sections = append(sections, ad.renderInput("scope", "Scope:"))
 	}
 
 	help := helpStyle.Render("Tab to switch fields, Enter to save, Esc to cancel")
@@ -301,6 +329,10 @@ func (ad *AuthDialog) focusFirstInput(authType api.AuthType) {
 		input := ad.inputs["headers"]
 		input.Focus()
 		ad.inputs["headers"] = input
+	case api.AuthOAuth2:
+		input := ad.inputs["token_url"]
+		input.Focus()
+		ad.inputs["token_url"] = input
 	}
 }
 
@@ -317,6 +349,8 @@ func (ad *AuthDialog) focusNextInput() {
 			inputOrder = []string{"username", "password"}
 		case api.AuthCustom:
 			inputOrder = []string{"headers"}
+		case api.AuthOAuth2:
+			inputOrder = []string{"token_url", "client_id", "client_secret", "scope"}
 		default:
 			return
 		}
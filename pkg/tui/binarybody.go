@@ -0,0 +1,37 @@
+package tui
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"onioncli/pkg/api"
+)
+
+// hexPreviewBytes is how much of a binary body the response viewer dumps
+const hexPreviewBytes = 256
+
+// binaryDescription names a binary body's type and size, e.g.
+// "image/png, 12.3 KiB"
+func binaryDescription(response *api.Response) string {
+	contentType, _ := response.ContentType()
+	if contentType == "" {
+		contentType = "unknown type"
+	}
+	return fmt.Sprintf("%s, %s", contentType, formatBytes(int64(len(response.BodyBytes()))))
+}
+
+// binaryPreview renders a hexdump of the start of a binary body. Bytes
+// outside printable ASCII show as dots, so nothing reaches the terminal raw.
+func binaryPreview(response *api.Response) string {
+	body := response.BodyBytes()
+	shown := min(len(body), hexPreviewBytes)
+	dump := strings.TrimSuffix(hex.Dump(body[:shown]), "\n")
+	if shown < len(body) {
+		dump += fmt.Sprintf("\n... %d more bytes", len(body)-shown)
+	}
+	return dump
+}
+
+// binaryHint tells the user how to get at a binary body
+const binaryHint = "Binary body not shown. Press w to save it to a file."
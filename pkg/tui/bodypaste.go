@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"onioncli/pkg/api"
+)
+
+// pasteMinRunes is how many runes one key message must carry to count as a
+// paste on terminals without bracketed paste; typing delivers one at a time
+const pasteMinRunes = 16
+
+// isPaste reports whether a key message inserted pasted text
+func isPaste(msg tea.KeyMsg) bool {
+	return msg.Type == tea.KeyRunes && (msg.Paste || len(msg.Runes) >= pasteMinRunes)
+}
+
+// formatJSONBody pretty-prints body when the whole of it parses as JSON.
+// It returns false, leaving the body alone, for anything else or for JSON
+// that is already formatted.
+func formatJSONBody(body string) (string, bool) {
+	trimmed := strings.TrimSpace(body)
+	if trimmed == "" || !json.Valid([]byte(trimmed)) {
+		return body, false
+	}
+
+	var formatted bytes.Buffer
+	if err := json.Indent(&formatted, []byte(trimmed), "", "  "); err != nil {
+		return body, false
+	}
+	if formatted.String() == body {
+		return body, false
+	}
+	return formatted.String(), true
+}
+
+// formatPastedBody pretty-prints the body editor after a paste when
+// ui.auto_format_paste is on and the body is JSON
+func (m Model) formatPastedBody(msg tea.Msg) Model {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || !isPaste(keyMsg) || !m.configManager.Get().UI.AutoFormatPaste {
+		return m
+	}
+
+	if formatted, ok := formatJSONBody(m.bodyArea.Value()); ok {
+		m.bodyArea.SetValue(formatted)
+		m.statusIndicator.Show("Formatted pasted JSON", StatusInfo)
+	}
+	return m
+}
+
+// looksLikeJSON reports whether body is meant as JSON: it parses as JSON,
+// starts like an object or array, or the Content-Type says so
+func looksLikeJSON(body, contentType string) bool {
+	trimmed := strings.TrimSpace(body)
+	return json.Valid([]byte(trimmed)) ||
+		strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") ||
+		strings.Contains(strings.ToLower(contentType), "json")
+}
+
+// formatBody pretty-prints the body editor's JSON in place (Ctrl+J), or
+// shows where it fails to parse. Bodies that are not JSON are left alone.
+func (m Model) formatBody() Model {
+	if m.bodyForm {
+		m.statusIndicator.Show("The body is a multipart form; Ctrl+F switches to raw to format JSON", StatusInfo)
+		return m
+	}
+
+	body := m.bodyArea.Value()
+	contentType := ""
+	for name, value := range m.parseHeaders(m.headersArea.Value()) {
+		if strings.EqualFold(name, "Content-Type") {
+			contentType = value
+		}
+	}
+	if strings.TrimSpace(body) == "" || !looksLikeJSON(body, contentType) {
+		m.statusIndicator.Show("The body isn't JSON, nothing to format", StatusInfo)
+		return m
+	}
+
+	if err := api.ValidateJSON(body); err != nil {
+		m.statusIndicator.Show(fmt.Sprintf("Invalid JSON at %v", err), StatusError)
+		return m
+	}
+	formatted, ok := formatJSONBody(body)
+	if !ok {
+		m.statusIndicator.Show("JSON is valid and already formatted", StatusSuccess)
+		return m
+	}
+	m.bodyArea.SetValue(formatted)
+	m.statusIndicator.Show("Formatted JSON body", StatusSuccess)
+	return m
+}
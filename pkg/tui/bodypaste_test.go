@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestIsPaste(t *testing.T) {
+	tests := []struct {
+		name     string
+		msg      tea.KeyMsg
+		expected bool
+	}{
+		{"typed rune", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")}, false},
+		{"bracketed paste", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("{}"), Paste: true}, true},
+		{"many runes in one update", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(`{"name":"alice","id":1}`)}, true},
+		{"enter", tea.KeyMsg{Type: tea.KeyEnter}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isPaste(tt.msg); got != tt.expected {
+				t.Errorf("isPaste() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatJSONBody(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		want      string
+		formatted bool
+	}{
+		{"minified object", `{"name":"alice","tags":["a","b"]}`, "{\n  \"name\": \"alice\",\n  \"tags\": [\n    \"a\",\n    \"b\"\n  ]\n}", true},
+		{"surrounding whitespace", "  [1,2]\n", "[\n  1,\n  2\n]", true},
+		{"already formatted", "{\n  \"a\": 1\n}", "{\n  \"a\": 1\n}", false},
+		{"invalid JSON", `{"name":"alice",`, `{"name":"alice",`, false},
+		{"JSON followed by text", `{"a":1} trailing`, `{"a":1} trailing`, false},
+		{"plain text", "name=alice&id=1", "name=alice&id=1", false},
+		{"empty", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, formatted := formatJSONBody(tt.body)
+			if got != tt.want || formatted != tt.formatted {
+				t.Errorf("formatJSONBody() = %q, %v, want %q, %v", got, formatted, tt.want, tt.formatted)
+			}
+		})
+	}
+}
+
+func TestLooksLikeJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		expected    bool
+	}{
+		{"valid object", `{"a":1}`, "", true},
+		{"broken object", `{"a":1,`, "", true},
+		{"broken array", "\n  [1,", "", true},
+		{"JSON scalar", "42", "", true},
+		{"JSON content type", "name: alice", "application/json; charset=utf-8", true},
+		{"form body", "name=alice&id=1", "application/x-www-form-urlencoded", false},
+		{"plain text", "hello", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := looksLikeJSON(tt.body, tt.contentType); got != tt.expected {
+				t.Errorf("looksLikeJSON() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"onioncli/pkg/collections"
+)
+
+// Fields of the bundle export dialog, in tab order
+const (
+	bundleFieldEnvironment = iota
+	bundleFieldSecrets
+	bundleFieldPath
+)
+
+// ExportBundleMsg asks for the collection chosen for export to be written
+// to a bundle file together with an environment
+type ExportBundleMsg struct {
+	environmentID  string
+	filename       string
+	includeSecrets bool
+}
+
+// BundleExportDialog picks the environment to bundle with a collection,
+// whether to include secrets, and the file to write
+type BundleExportDialog struct {
+	environments   []collections.Environment
+	selected       int // index into environments
+	includeSecrets bool
+	pathInput      textinput.Model
+	focusedField   int
+	visible        bool
+}
+
+// NewBundleExportDialog creates a new bundle export dialog
+func NewBundleExportDialog() BundleExportDialog {
+	pathInput := textinput.New()
+	pathInput.Placeholder = "Path to write the bundle..."
+	pathInput.CharLimit = 500
+	pathInput.Width = 50
+
+	return BundleExportDialog{pathInput: pathInput}
+}
+
+// Show opens the dialog for a collection named name, starting on the
+// active environment and with secrets left out
+func (d *BundleExportDialog) Show(name string, environments []collections.Environment) tea.Cmd {
+	d.environments = environments
+	d.selected = 0
+	for i, env := range environments {
+		if env.IsActive {
+			d.selected = i
+		}
+	}
+	d.includeSecrets = false
+	d.pathInput.SetValue(bundleFileName(name))
+	d.pathInput.CursorEnd()
+	d.focusedField = bundleFieldEnvironment
+	d.visible = true
+	d.updateFocus()
+	return textinput.Blink
+}
+
+// Hide hides the dialog
+func (d *BundleExportDialog) Hide() {
+	d.visible = false
+	d.environments = nil
+	d.pathInput.SetValue("")
+	d.pathInput.Blur()
+}
+
+// updateFocus focuses the path input while its field is focused
+func (d *BundleExportDialog) updateFocus() {
+	if d.focusedField == bundleFieldPath {
+		d.pathInput.Focus()
+	} else {
+		d.pathInput.Blur()
+	}
+}
+
+// Update handles dialog updates. Up and down pick the environment, space
+// toggles secrets, and Enter exports from any field.
+func (d BundleExportDialog) Update(msg tea.Msg) (BundleExportDialog, tea.Cmd) {
+	if !d.visible {
+		return d, nil
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "tab":
+			d.focusedField = (d.focusedField + 1) % 3
+			d.updateFocus()
+			return d, nil
+		case "shift+tab":
+			d.focusedField = (d.focusedField + 2) % 3
+			d.updateFocus()
+			return d, nil
+		case "up", "down":
+			if d.focusedField == bundleFieldEnvironment && len(d.environments) > 0 {
+				count := len(d.environments)
+				if msg.String() == "up" {
+					d.selected = (d.selected + count - 1) % count
+				} else {
+					d.selected = (d.selected + 1) % count
+				}
+				return d, nil
+			}
+		case " ":
+			if d.focusedField == bundleFieldSecrets {
+				d.includeSecrets = !d.includeSecrets
+				return d, nil
+			}
+		case "enter":
+			path := strings.TrimSpace(d.pathInput.Value())
+			if path == "" {
+				return d, nil // Nothing to write without a path
+			}
+			exportMsg := ExportBundleMsg{filename: path, includeSecrets: d.includeSecrets}
+			if len(d.environments) > 0 {
+				exportMsg.environmentID = d.environments[d.selected].ID
+			}
+			return d, func() tea.Msg { return exportMsg }
+		case "esc":
+			d.Hide()
+			return d, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	if d.focusedField == bundleFieldPath {
+		d.pathInput, cmd = d.pathInput.Update(msg)
+	}
+	return d, cmd
+}
+
+// View renders the dialog
+func (d BundleExportDialog) View() string {
+	if !d.visible {
+		return ""
+	}
+
+	sections := []string{titleStyle.Render("Export Bundle")}
+
+	environmentLines := make([]string, len(d.environments))
+	for i, env := range d.environments {
+		if i == d.selected {
+			environmentLines[i] = lipgloss.NewStyle().Foreground(palette.Primary).Bold(true).Render("▸ " + env.Name)
+		} else {
+			environmentLines[i] = "  " + env.Name
+		}
+	}
+	secrets := "[ ] Include secrets (written in plain text)"
+	if d.includeSecrets {
+		secrets = "[x] Include secrets (written in plain text)"
+	}
+
+	fields := []struct {
+		field int
+		label string
+		view  string
+	}{
+		{bundleFieldEnvironment, "Environment:", strings.Join(environmentLines, "\n")},
+		{bundleFieldSecrets, "Secrets:", secrets},
+		{bundleFieldPath, "File:", d.pathInput.View()},
+	}
+	for _, f := range fields {
+		style := blurredStyle
+		if d.focusedField == f.field {
+			style = focusedStyle
+		}
+		sections = append(sections, style.Render(fmt.Sprintf("%s\n%s", f.label, f.view)))
+	}
+
+	sections = append(sections, helpStyle.Render("↑/↓ to pick an environment, Space to toggle secrets, Tab/Shift+Tab to switch fields, Enter to export, Esc to cancel"))
+
+	content := strings.Join(sections, "\n\n")
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(palette.Primary).
+		Padding(1).
+		Render(content)
+}
+
+// bundleFileName suggests a file name for a collection exported as a bundle
+func bundleFileName(name string) string {
+	return collectionSlug(name) + ".onioncli-bundle.json"
+}
@@ -0,0 +1,44 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"onioncli/pkg/collections"
+)
+
+func TestBundleExportDialog(t *testing.T) {
+	environments := []collections.Environment{
+		{ID: "dev", Name: "Dev"},
+		{ID: "staging", Name: "Staging", IsActive: true},
+	}
+	d := NewBundleExportDialog()
+	d.Show("Users API", environments)
+	if got := d.pathInput.Value(); got != "users-api.onioncli-bundle.json" {
+		t.Errorf("Suggested path = %q", got)
+	}
+	if d.selected != 1 {
+		t.Errorf("Expected the active environment to be picked first, got %d", d.selected)
+	}
+
+	for _, key := range []tea.KeyMsg{
+		{Type: tea.KeyDown},
+		{Type: tea.KeyTab},
+		{Type: tea.KeySpace, Runes: []rune{' '}},
+	} {
+		d, _ = d.Update(key)
+	}
+	_, cmd := d.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Expected Enter to export")
+	}
+	msg, ok := cmd().(ExportBundleMsg)
+	if !ok {
+		t.Fatalf("Expected ExportBundleMsg, got %T", cmd())
+	}
+	want := ExportBundleMsg{environmentID: "dev", filename: "users-api.onioncli-bundle.json", includeSecrets: true}
+	if msg != want {
+		t.Errorf("Export message = %+v, want %+v", msg, want)
+	}
+}
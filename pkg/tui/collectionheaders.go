@@ -0,0 +1,144 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"onioncli/pkg/api"
+	"onioncli/pkg/collections"
+)
+
+// SaveCollectionHeadersMsg asks for a collection's default headers to be
+// replaced
+type SaveCollectionHeadersMsg struct {
+	id      string
+	headers map[string]string
+}
+
+// CollectionHeadersDialog edits the headers sent with every request in a
+// collection
+type CollectionHeadersDialog struct {
+	collectionID string
+	name         string
+	headersArea  textarea.Model // one "Name: value" per line
+	err          string         // why the last save was rejected
+	visible      bool
+}
+
+// NewCollectionHeadersDialog creates a new collection headers dialog
+func NewCollectionHeadersDialog() CollectionHeadersDialog {
+	headersArea := textarea.New()
+	headersArea.Placeholder = "Accept: application/json\nX-Api-Key: {{api_key}}"
+	headersArea.ShowLineNumbers = false
+	headersArea.CharLimit = 0
+	headersArea.SetWidth(60)
+	headersArea.SetHeight(8)
+
+	return CollectionHeadersDialog{headersArea: headersArea}
+}
+
+// Show shows the dialog pre-filled with the collection's default headers
+func (d *CollectionHeadersDialog) Show(collection *collections.Collection) tea.Cmd {
+	d.visible = true
+	d.collectionID = collection.ID
+	d.name = collection.Name
+	d.err = ""
+	d.headersArea.SetValue(formatHeaderLines(collection.DefaultHeaders))
+	return d.headersArea.Focus()
+}
+
+// Hide hides the dialog
+func (d *CollectionHeadersDialog) Hide() {
+	d.visible = false
+	d.collectionID = ""
+	d.err = ""
+	d.headersArea.Blur()
+}
+
+// SetError shows why saving failed, keeping the dialog open
+func (d *CollectionHeadersDialog) SetError(err error) {
+	d.err = err.Error()
+}
+
+// Update handles dialog updates. Enter starts a new line; Ctrl+S saves.
+func (d CollectionHeadersDialog) Update(msg tea.Msg) (CollectionHeadersDialog, tea.Cmd) {
+	if !d.visible {
+		return d, nil
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "ctrl+s":
+			return d.save()
+		case "esc":
+			d.Hide()
+			return d, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	d.headersArea, cmd = d.headersArea.Update(msg)
+	return d, cmd
+}
+
+// save parses the headers and emits a SaveCollectionHeadersMsg
+func (d CollectionHeadersDialog) save() (CollectionHeadersDialog, tea.Cmd) {
+	fields, skipped := api.ParseHeaderBlock(d.headersArea.Value())
+	if len(skipped) > 0 {
+		d.err = fmt.Sprintf("Not a header (use Name: value): %s", skipped[0])
+		return d, nil
+	}
+
+	headers := make(map[string]string, len(fields))
+	for _, field := range fields {
+		headers[field.Name] = field.Value
+	}
+
+	d.err = ""
+	saveMsg := SaveCollectionHeadersMsg{id: d.collectionID, headers: headers}
+	return d, func() tea.Msg { return saveMsg }
+}
+
+// View renders the dialog
+func (d CollectionHeadersDialog) View() string {
+	if !d.visible {
+		return ""
+	}
+
+	sections := []string{
+		titleStyle.Render("Default Headers: " + d.name),
+		focusedStyle.Render("Sent with every request in the collection; a request's own header of the same name wins.\n" + d.headersArea.View()),
+	}
+	if d.err != "" {
+		sections = append(sections, errorStyle.Render(d.err))
+	}
+	sections = append(sections, helpStyle.Render("One Name: value per line, Ctrl+S to save, Esc to cancel"))
+
+	content := strings.Join(sections, "\n\n")
+	return lipgloss.Place(80, 24, lipgloss.Center, lipgloss.Center,
+		lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(palette.Primary).
+			Padding(1).
+			Render(content))
+}
+
+// formatHeaderLines renders headers as "Name: value" lines, sorted by name
+func formatHeaderLines(headers map[string]string) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := make([]string, len(names))
+	for i, name := range names {
+		lines[i] = name + ": " + headers[name]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -1,14 +1,19 @@
 package tui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"unicode"
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"onioncli/pkg/api"
 	"onioncli/pkg/collections"
 )
 
@@ -27,6 +32,9 @@ func (c CollectionItem) Title() string {
 
 func (c CollectionItem) Description() string {
 	requestCount := len(c.collection.Requests)
+	if headerCount := len(c.collection.DefaultHeaders); headerCount > 0 {
+		return fmt.Sprintf("%s (%d requests, %d default headers)", c.collection.Description, requestCount, headerCount)
+	}
 	return fmt.Sprintf("%s (%d requests)", c.collection.Description, requestCount)
 }
 
@@ -57,6 +65,35 @@ type CollectionsViewer struct {
 	width              int
 	height             int
 	createDialog       CreateCollectionDialog
+	importDialog       FilePromptDialog
+	importProgress     ProgressIndicator
+	importCancel       context.CancelFunc
+	importCh           chan tea.Msg
+	exportDialog       FilePromptDialog
+	exportCollection   *collections.Collection // collection the export dialog is for
+	bundleDialog       BundleExportDialog
+	headersDialog      CollectionHeadersDialog
+	headersReturn      CollectionViewState // view the headers dialog goes back to
+	statusMessage      string
+	rememberSession    bool // save the opened collection and loaded request for next launch
+
+	// Data-driven runs of a single request over CSV rows
+	client       *api.Client
+	runDialog    FilePromptDialog
+	runRequest   *collections.CollectionRequest
+	runProgress  ProgressIndicator
+	runCancel    context.CancelFunc
+	runCh        chan tea.Msg
+	runResults   viewport.Model
+	hasRunResult bool
+	lastRun      *collections.DataDrivenRun
+	waterfall    bool // show the last run as a timeline instead of a table
+
+	// Sequence runs of a whole collection, which share the run state above
+	continueOnError bool // keep going after a failed request
+	sequenceResults viewport.Model
+	lastSequence    *collections.SequenceRun
+	lastResultsView CollectionViewState // results view R reopens, for the latest run of either kind
 }
 
 // CollectionViewState represents the current view state
@@ -66,10 +103,17 @@ const (
 	ViewCollections CollectionViewState = iota
 	ViewRequests
 	ViewCreateCollection
+	ViewImportCollection
+	ViewDataRun
+	ViewDataRunResults
+	ViewExportPostman
+	ViewSequenceResults
+	ViewCollectionHeaders
+	ViewExportBundle
 )
 
 // NewCollectionsViewer creates a new collections viewer
-func NewCollectionsViewer(manager *collections.Manager, width, height int) CollectionsViewer {
+func NewCollectionsViewer(manager *collections.Manager, client *api.Client, width, height int) CollectionsViewer {
 	// Create collections list
 	collections := manager.GetCollections()
 	items := make([]list.Item, len(collections))
@@ -90,6 +134,13 @@ func NewCollectionsViewer(manager *collections.Manager, width, height int) Colle
 	requestsList.SetFilteringEnabled(true)
 	requestsList.SetShowHelp(true)
 
+	importDialog := NewFilePromptDialog("Import Collection", "Path to collection file...", "import",
+		func(path string) tea.Msg { return StartImportMsg{filename: path} })
+	exportDialog := NewFilePromptDialog("Export to Postman", "Path to write the Postman v2.1 collection...", "export",
+		func(path string) tea.Msg { return ExportPostmanMsg{filename: path} })
+	runDialog := NewFilePromptDialog("Data-driven Run", "Path to CSV file (header row names the placeholders)...", "run",
+		func(path string) tea.Msg { return StartDataRunMsg{filename: path} })
+
 	return CollectionsViewer{
 		manager:         manager,
 		collectionsList: collectionsList,
@@ -98,6 +149,16 @@ func NewCollectionsViewer(manager *collections.Manager, width, height int) Colle
 		width:           width,
 		height:          height,
 		createDialog:    NewCreateCollectionDialog(),
+		importDialog:    importDialog,
+		importProgress:  NewProgressIndicator(),
+		exportDialog:    exportDialog,
+		bundleDialog:    NewBundleExportDialog(),
+		headersDialog:   NewCollectionHeadersDialog(),
+		client:          client,
+		runDialog:       runDialog,
+		runProgress:     NewProgressIndicator(),
+		runResults:      viewport.New(width-4, height-10),
+		sequenceResults: viewport.New(width-4, height-10),
 	}
 }
 
@@ -113,8 +174,194 @@ func (cv CollectionsViewer) Update(msg tea.Msg) (CollectionsViewer, tea.Cmd) {
 		return cv, tea.Batch(cmds...)
 	}
 
+	// Handle import dialog and in-flight imports
+	switch msg := msg.(type) {
+	case ImportProgressMsg:
+		cv.importProgress.total = msg.total
+		cv.importProgress.Update(msg.done)
+		return cv, waitForImport(cv.importCh)
+
+	case ImportCompleteMsg:
+		cv.finishImport()
+		if errors.Is(msg.err, context.Canceled) {
+			cv.statusMessage = "Import cancelled"
+		} else if msg.err != nil {
+			cv.statusMessage = fmt.Sprintf("❌ Import failed: %v", msg.err)
+		} else {
+			cv.statusMessage = fmt.Sprintf("✅ Imported %s (%d requests)", msg.collection.Name, len(msg.collection.Requests))
+			if msg.collection.ImportEnvironment != "" {
+				cv.statusMessage = fmt.Sprintf("✅ Imported %s (%d requests) with environment %s", msg.collection.Name,
+					len(msg.collection.Requests), msg.collection.ImportEnvironment)
+			}
+			if msg.collection.ImportSkipped > 0 {
+				cv.statusMessage = fmt.Sprintf("⚠️  Imported %s (%d requests, %d malformed %s skipped)", msg.collection.Name,
+					len(msg.collection.Requests), msg.collection.ImportSkipped, plural(msg.collection.ImportSkipped, "item", "items"))
+			}
+			cv.refreshCollections()
+		}
+		return cv, nil
+
+	case StartImportMsg:
+		cv.importDialog.Hide()
+		cv.currentView = ViewCollections
+		return cv, cv.startImport(msg.filename)
+
+	case ExportPostmanMsg:
+		cv.exportDialog.Hide()
+		cv.currentView = ViewCollections
+		if err := cv.manager.ExportPostman(cv.exportCollection.ID, msg.filename); err != nil {
+			cv.statusMessage = fmt.Sprintf("❌ Export failed: %v", err)
+		} else {
+			cv.statusMessage = fmt.Sprintf("✅ Exported %s to %s (includes any credentials)", cv.exportCollection.Name, msg.filename)
+		}
+		cv.exportCollection = nil
+		return cv, nil
+
+	case ExportBundleMsg:
+		cv.bundleDialog.Hide()
+		cv.currentView = ViewCollections
+		if err := cv.manager.ExportBundle(cv.exportCollection.ID, msg.environmentID, msg.filename, msg.includeSecrets); err != nil {
+			cv.statusMessage = fmt.Sprintf("❌ Export failed: %v", err)
+		} else if msg.includeSecrets {
+			cv.statusMessage = fmt.Sprintf("✅ Exported %s to %s (includes secrets)", cv.exportCollection.Name, msg.filename)
+		} else {
+			cv.statusMessage = fmt.Sprintf("✅ Exported %s to %s (secrets removed)", cv.exportCollection.Name, msg.filename)
+		}
+		cv.exportCollection = nil
+		return cv, nil
+
+	case DataRunProgressMsg:
+		cv.runProgress.total = msg.total
+		cv.runProgress.Update(msg.done)
+		return cv, waitForImport(cv.runCh)
+
+	case DataRunCompleteMsg:
+		cv.finishRun()
+		if errors.Is(msg.err, context.Canceled) {
+			cv.statusMessage = "Data-driven run cancelled"
+		} else if msg.err != nil {
+			cv.statusMessage = fmt.Sprintf("❌ Data-driven run failed: %v", msg.err)
+		}
+		if msg.run != nil && len(msg.run.Results) > 0 {
+			cv.lastRun = msg.run
+			cv.refreshRunResults()
+			cv.runResults.GotoTop()
+			cv.hasRunResult = true
+			cv.lastResultsView = ViewDataRunResults
+			cv.currentView = ViewDataRunResults
+		}
+		return cv, nil
+
+	case SequenceCompleteMsg:
+		cv.finishRun()
+		if errors.Is(msg.err, context.Canceled) {
+			cv.statusMessage = "Sequence run cancelled"
+		} else if msg.err != nil {
+			cv.statusMessage = fmt.Sprintf("❌ Sequence run failed: %v", msg.err)
+		}
+		if msg.run != nil && len(msg.run.Results) > 0 {
+			cv.lastSequence = msg.run
+			cv.sequenceResults.SetContent(renderSequenceResults(msg.run))
+			cv.sequenceResults.GotoTop()
+			cv.hasRunResult = true
+			cv.lastResultsView = ViewSequenceResults
+			cv.currentView = ViewSequenceResults
+		}
+		return cv, nil
+
+	case SaveCollectionHeadersMsg:
+		if err := cv.manager.SetCollectionDefaultHeaders(msg.id, msg.headers); err != nil {
+			cv.headersDialog.SetError(err)
+			return cv, nil
+		}
+		cv.statusMessage = fmt.Sprintf("✅ Saved default headers for %s", cv.headersDialog.name)
+		cv.headersDialog.Hide()
+		cv.currentView = cv.headersReturn
+		cv.collectionChanged(msg.id)
+		return cv, nil
+
+	case StartDataRunMsg:
+		cv.runDialog.Hide()
+		cv.currentView = ViewRequests
+		return cv, cv.startDataRun(msg.filename)
+	}
+
+	if cv.currentView == ViewImportCollection {
+		cv.importDialog, cmd = cv.importDialog.Update(msg)
+		if !cv.importDialog.visible {
+			cv.currentView = ViewCollections
+		}
+		return cv, cmd
+	}
+
+	if cv.currentView == ViewExportPostman {
+		cv.exportDialog, cmd = cv.exportDialog.Update(msg)
+		if !cv.exportDialog.visible {
+			cv.currentView = ViewCollections
+		}
+		return cv, cmd
+	}
+
+	if cv.currentView == ViewExportBundle {
+		cv.bundleDialog, cmd = cv.bundleDialog.Update(msg)
+		if !cv.bundleDialog.visible {
+			cv.currentView = ViewCollections
+		}
+		return cv, cmd
+	}
+
+	if cv.currentView == ViewCollectionHeaders {
+		cv.headersDialog, cmd = cv.headersDialog.Update(msg)
+		if !cv.headersDialog.visible {
+			cv.currentView = cv.headersReturn
+		}
+		return cv, cmd
+	}
+
+	if cv.currentView == ViewDataRun {
+		cv.runDialog, cmd = cv.runDialog.Update(msg)
+		if !cv.runDialog.visible {
+			cv.currentView = ViewRequests
+		}
+		return cv, cmd
+	}
+
+	if cv.currentView == ViewDataRunResults {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "esc", "backspace":
+				cv.currentView = ViewRequests
+				return cv, nil
+			case "w":
+				cv.waterfall = !cv.waterfall
+				cv.refreshRunResults()
+				cv.runResults.GotoTop()
+				return cv, nil
+			}
+		}
+		cv.runResults, cmd = cv.runResults.Update(msg)
+		return cv, cmd
+	}
+
+	if cv.currentView == ViewSequenceResults {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "esc", "backspace":
+				cv.currentView = ViewRequests
+				return cv, nil
+			}
+		}
+		cv.sequenceResults, cmd = cv.sequenceResults.Update(msg)
+		return cv, cmd
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if cv.IsImporting() || cv.IsRunning() {
+			// Ignore other keys while an import or run is in progress; esc cancels via the model
+			return cv, nil
+		}
+
 		switch msg.String() {
 		case "n":
 			// Create new collection
@@ -122,6 +369,53 @@ func (cv CollectionsViewer) Update(msg tea.Msg) (CollectionsViewer, tea.Cmd) {
 			cv.createDialog.Show()
 			return cv, nil
 
+		case "i":
+			// Import collection from file
+			if cv.currentView == ViewCollections {
+				cv.currentView = ViewImportCollection
+				cv.importDialog.Show()
+				return cv, textinput.Blink
+			}
+
+		case "p":
+			// Export the selected collection in Postman's format
+			if cv.currentView == ViewCollections {
+				if selectedItem := cv.collectionsList.SelectedItem(); selectedItem != nil {
+					collectionItem := selectedItem.(CollectionItem)
+					cv.exportCollection = &collectionItem.collection
+					cv.currentView = ViewExportPostman
+					cv.exportDialog.ShowPath(postmanFileName(collectionItem.collection.Name))
+					return cv, textinput.Blink
+				}
+			}
+
+		case "b":
+			// Export the selected collection with an environment as a bundle
+			if cv.currentView == ViewCollections {
+				if selectedItem := cv.collectionsList.SelectedItem(); selectedItem != nil {
+					collectionItem := selectedItem.(CollectionItem)
+					cv.exportCollection = &collectionItem.collection
+					cv.currentView = ViewExportBundle
+					return cv, cv.bundleDialog.Show(collectionItem.collection.Name, cv.manager.GetEnvironments())
+				}
+			}
+
+		case "H":
+			// Edit the default headers of the selected or open collection
+			collection := cv.selectedCollection
+			if cv.currentView == ViewCollections {
+				collection = nil
+				if selectedItem := cv.collectionsList.SelectedItem(); selectedItem != nil {
+					collectionItem := selectedItem.(CollectionItem)
+					collection = &collectionItem.collection
+				}
+			}
+			if collection != nil && (cv.currentView == ViewCollections || cv.currentView == ViewRequests) {
+				cv.headersReturn = cv.currentView
+				cv.currentView = ViewCollectionHeaders
+				return cv, cv.headersDialog.Show(collection)
+			}
+
 		case "enter":
 			if cv.currentView == ViewCollections {
 				// Open selected collection
@@ -130,18 +424,57 @@ func (cv CollectionsViewer) Update(msg tea.Msg) (CollectionsViewer, tea.Cmd) {
 					cv.selectedCollection = &collectionItem.collection
 					cv.loadRequests()
 					cv.currentView = ViewRequests
+					cv.saveSession("")
 					return cv, nil
 				}
 			} else if cv.currentView == ViewRequests {
 				// Load selected request
 				if selectedItem := cv.requestsList.SelectedItem(); selectedItem != nil {
 					requestItem := selectedItem.(RequestItem)
+					cv.saveSession(requestItem.request.ID)
 					return cv, func() tea.Msg {
-						return LoadRequestMsg{request: &requestItem.request}
+						return LoadRequestMsg{collection: cv.selectedCollection, request: &requestItem.request}
 					}
 				}
 			}
 
+		case "D":
+			// Run the selected request once per row of a CSV file
+			if cv.currentView == ViewRequests {
+				if selectedItem := cv.requestsList.SelectedItem(); selectedItem != nil {
+					requestItem := selectedItem.(RequestItem)
+					cv.runRequest = &requestItem.request
+					cv.currentView = ViewDataRun
+					cv.runDialog.Show()
+					return cv, textinput.Blink
+				}
+			}
+
+		case "S":
+			// Run the open collection's requests in order
+			if cv.currentView == ViewRequests {
+				return cv, cv.startSequenceRun()
+			}
+
+		case "c":
+			// Choose whether sequence runs stop at the first failure
+			if cv.currentView == ViewRequests {
+				cv.continueOnError = !cv.continueOnError
+				if cv.continueOnError {
+					cv.statusMessage = "Sequence runs continue after a failed request"
+				} else {
+					cv.statusMessage = "Sequence runs stop at the first failed request"
+				}
+				return cv, nil
+			}
+
+		case "R":
+			// Reopen the results of the last data-driven or sequence run
+			if cv.currentView == ViewRequests && cv.hasRunResult {
+				cv.currentView = cv.lastResultsView
+				return cv, nil
+			}
+
 		case "esc", "backspace":
 			if cv.currentView == ViewRequests {
 				cv.currentView = ViewCollections
@@ -203,6 +536,21 @@ func (cv CollectionsViewer) View() string {
 	if cv.currentView == ViewCreateCollection {
 		return cv.createDialog.View()
 	}
+	if cv.currentView == ViewImportCollection {
+		return cv.importDialog.View()
+	}
+	if cv.currentView == ViewDataRun {
+		return cv.runDialog.View()
+	}
+	if cv.currentView == ViewExportPostman {
+		return cv.exportDialog.View()
+	}
+	if cv.currentView == ViewExportBundle {
+		return cv.bundleDialog.View()
+	}
+	if cv.currentView == ViewCollectionHeaders {
+		return cv.headersDialog.View()
+	}
 
 	var sections []string
 
@@ -214,8 +562,16 @@ func (cv CollectionsViewer) View() string {
 	switch cv.currentView {
 	case ViewCollections:
 		sections = append(sections, cv.collectionsList.View())
-		help := helpStyle.Render("Enter to open, n to create new, d to delete, r to refresh, esc to go back")
-		sections = append(sections, help)
+		if cv.IsImporting() {
+			sections = append(sections, cv.importProgress.View())
+			sections = append(sections, helpStyle.Render("Importing... esc to cancel"))
+		} else {
+			if cv.statusMessage != "" {
+				sections = append(sections, statusStyle.Render(cv.statusMessage))
+			}
+			help := helpStyle.Render("Enter to open, n to create new, i to import, p to export to Postman, b to export a bundle, H for default headers, d to delete, r to refresh, esc to go back")
+			sections = append(sections, help)
+		}
 
 	case ViewRequests:
 		if cv.selectedCollection != nil {
@@ -223,8 +579,39 @@ func (cv CollectionsViewer) View() string {
 			sections = append(sections, lipgloss.NewStyle().Bold(true).Render(collectionTitle))
 		}
 		sections = append(sections, cv.requestsList.View())
-		help := helpStyle.Render("Enter to load request, d to delete, esc to go back to collections")
-		sections = append(sections, help)
+		if cv.IsRunning() {
+			sections = append(sections, cv.runProgress.View())
+			sections = append(sections, helpStyle.Render("Running... esc to cancel"))
+		} else {
+			if cv.statusMessage != "" {
+				sections = append(sections, statusStyle.Render(cv.statusMessage))
+			}
+			onError := "c to continue on failure"
+			if cv.continueOnError {
+				onError = "c to stop on failure"
+			}
+			help := "Enter to load request, D for data-driven run from CSV, S to run all in sequence, H for default headers, " + onError
+			if cv.hasRunResult {
+				help += ", R for last run results"
+			}
+			sections = append(sections, helpStyle.Render(help+", esc to go back to collections"))
+		}
+
+	case ViewDataRunResults:
+		sections = append(sections, lipgloss.NewStyle().Bold(true).Render("Data-driven Run Results"))
+		if cv.statusMessage != "" {
+			sections = append(sections, statusStyle.Render(cv.statusMessage))
+		}
+		sections = append(sections, cv.runResults.View())
+		sections = append(sections, helpStyle.Render("↑/↓ scroll, w to toggle timeline, esc to go back to requests"))
+
+	case ViewSequenceResults:
+		sections = append(sections, lipgloss.NewStyle().Bold(true).Render("Sequence Run Results"))
+		if cv.statusMessage != "" {
+			sections = append(sections, statusStyle.Render(cv.statusMessage))
+		}
+		sections = append(sections, cv.sequenceResults.View())
+		sections = append(sections, helpStyle.Render("↑/↓ scroll, esc to go back to requests"))
 	}
 
 	return strings.Join(sections, "\n\n")
@@ -245,6 +632,38 @@ func (cv *CollectionsViewer) loadRequests() {
 	cv.requestsList.Title = fmt.Sprintf("Requests in %s", cv.selectedCollection.Name)
 }
 
+// SetRememberSession turns saving of the opened collection and request on or off
+func (cv *CollectionsViewer) SetRememberSession(remember bool) {
+	cv.rememberSession = remember
+}
+
+// saveSession records the open collection and, if set, the loaded request
+func (cv *CollectionsViewer) saveSession(requestID string) {
+	if !cv.rememberSession || cv.selectedCollection == nil {
+		return
+	}
+	session := collections.Session{CollectionID: cv.selectedCollection.ID, RequestID: requestID}
+	if err := cv.manager.SaveSession(session); err != nil {
+		cv.statusMessage = fmt.Sprintf("❌ Failed to save session: %v", err)
+	}
+}
+
+// RestoreSession reopens a collection, selecting the given request if set
+func (cv *CollectionsViewer) RestoreSession(collection *collections.Collection, request *collections.CollectionRequest) {
+	cv.selectedCollection = collection
+	cv.loadRequests()
+	cv.currentView = ViewRequests
+	if request == nil {
+		return
+	}
+	for i := range collection.Requests {
+		if collection.Requests[i].ID == request.ID {
+			cv.requestsList.Select(i)
+			break
+		}
+	}
+}
+
 // refreshCollections refreshes the collections list
 func (cv *CollectionsViewer) refreshCollections() {
 	cv.manager.LoadCollections()
@@ -256,6 +675,91 @@ func (cv *CollectionsViewer) refreshCollections() {
 	cv.collectionsList.SetItems(items)
 }
 
+// collectionChanged reloads the collections after the one with id was
+// changed, showing the change if that collection is open
+func (cv *CollectionsViewer) collectionChanged(id string) {
+	cv.refreshCollections()
+	if cv.selectedCollection != nil && cv.selectedCollection.ID == id {
+		cv.selectedCollection, _ = cv.manager.GetCollection(id)
+		cv.loadRequests()
+	}
+}
+
+// startImport runs a collection import off the UI goroutine, streaming progress messages
+func (cv *CollectionsViewer) startImport(filename string) tea.Cmd {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan tea.Msg)
+
+	cv.importCancel = cancel
+	cv.importCh = ch
+	cv.statusMessage = ""
+	cv.importProgress.Show("Importing collection", 0)
+
+	manager := cv.manager
+	go func() {
+		defer close(ch)
+		progress := collections.ProgressFunc(func(done, total int) {
+			select {
+			case ch <- ImportProgressMsg{done: done, total: total}:
+			case <-ctx.Done():
+			}
+		})
+		collection, err := manager.ImportCollection(ctx, filename, progress)
+		ch <- ImportCompleteMsg{collection: collection, err: err}
+	}()
+
+	return waitForImport(ch)
+}
+
+// CancelImport cancels an in-flight import
+func (cv *CollectionsViewer) CancelImport() {
+	if cv.importCancel != nil {
+		cv.importCancel()
+	}
+}
+
+// finishImport clears import state once the import goroutine has finished
+func (cv *CollectionsViewer) finishImport() {
+	if cv.importCancel != nil {
+		cv.importCancel()
+	}
+	cv.importCancel = nil
+	cv.importCh = nil
+	cv.importProgress.Hide()
+}
+
+// IsImporting returns whether an import is currently running
+func (cv CollectionsViewer) IsImporting() bool {
+	return cv.importCh != nil
+}
+
+// IsCapturingInput returns whether a dialog is currently accepting text input
+func (cv CollectionsViewer) IsCapturingInput() bool {
+	return cv.currentView == ViewCreateCollection || cv.currentView == ViewImportCollection ||
+		cv.currentView == ViewDataRun || cv.currentView == ViewExportPostman || cv.currentView == ViewExportBundle || cv.currentView == ViewCollectionHeaders ||
+		cv.collectionsList.FilterState() == list.Filtering || cv.requestsList.FilterState() == list.Filtering
+}
+
+// InSubView returns whether esc should go back within the viewer rather than
+// leaving the collections screen
+func (cv CollectionsViewer) InSubView() bool {
+	return cv.currentView == ViewRequests || cv.currentView == ViewDataRunResults || cv.currentView == ViewSequenceResults
+}
+
+// waitForImport returns a command that waits for the next import message
+func waitForImport(ch <-chan tea.Msg) tea.Cmd {
+	if ch == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		msg, ok := <-ch
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
 // GetSelectedRequest returns the currently selected request
 func (cv CollectionsViewer) GetSelectedRequest() *collections.CollectionRequest {
 	if cv.currentView == ViewRequests {
@@ -273,6 +777,23 @@ func (cv *CollectionsViewer) Resize(width, height int) {
 	cv.height = height
 	cv.collectionsList.SetSize(width-4, height-8)
 	cv.requestsList.SetSize(width-4, height-8)
+	cv.runResults.Width = width - 4
+	cv.runResults.Height = height - 10
+	cv.refreshRunResults()
+	cv.sequenceResults.Width = width - 4
+	cv.sequenceResults.Height = height - 10
+}
+
+// refreshRunResults renders the last run as a table or timeline
+func (cv *CollectionsViewer) refreshRunResults() {
+	if cv.lastRun == nil {
+		return
+	}
+	if cv.waterfall {
+		cv.runResults.SetContent(renderDataRunWaterfall(cv.lastRun, cv.runResults.Width))
+	} else {
+		cv.runResults.SetContent(renderDataRunResults(cv.lastRun))
+	}
 }
 
 // CreateCollectionDialog handles creating new collections
@@ -415,7 +936,7 @@ func (d CreateCollectionDialog) View() string {
 	return lipgloss.Place(80, 20, lipgloss.Center, lipgloss.Center,
 		lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#7D56F4")).
+			BorderForeground(palette.Primary).
 			Padding(1).
 			Render(content))
 }
@@ -426,7 +947,50 @@ type CreateCollectionMsg struct {
 	description string
 }
 
+// StartImportMsg requests that an import be started for a file
+type StartImportMsg struct {
+	filename string
+}
+
+// ExportPostmanMsg requests that the collection chosen for export be written
+// to a file in Postman's format
+type ExportPostmanMsg struct {
+	filename string
+}
+
+// ImportProgressMsg reports progress of an in-flight import
+type ImportProgressMsg struct {
+	done  int
+	total int
+}
+
+// ImportCompleteMsg is sent once an import finishes, fails, or is cancelled
+type ImportCompleteMsg struct {
+	collection *collections.Collection
+	err        error
+}
+
 // LoadRequestMsg represents loading a request from collection
 type LoadRequestMsg struct {
-	request *collections.CollectionRequest
+	collection *collections.Collection
+	request    *collections.CollectionRequest
+}
+
+// postmanFileName suggests a file name for a collection exported to
+// Postman, following Postman's own <name>.postman_collection.json
+func postmanFileName(name string) string {
+	return collectionSlug(name) + ".postman_collection.json"
+}
+
+// collectionSlug turns a collection name into a lowercase, dash-separated
+// file name stem, e.g. "Users API" into "users-api"
+func collectionSlug(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	slug := strings.Join(words, "-")
+	if slug == "" {
+		slug = "collection"
+	}
+	return slug
 }
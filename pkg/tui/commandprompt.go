@@ -0,0 +1,106 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// CommandPrompt is the vim keymap's : line at the bottom of the screen
+type CommandPrompt struct {
+	input   textinput.Model
+	visible bool
+}
+
+// NewCommandPrompt creates a new command prompt
+func NewCommandPrompt() CommandPrompt {
+	input := textinput.New()
+	input.Prompt = ":"
+	input.Placeholder = "q, history, collections, env, settings, builder, help or a line number"
+	input.CharLimit = 64
+	input.Width = 60
+
+	return CommandPrompt{input: input}
+}
+
+// Show opens an empty prompt
+func (cp *CommandPrompt) Show() tea.Cmd {
+	cp.visible = true
+	cp.input.SetValue("")
+	cp.input.Focus()
+	return textinput.Blink
+}
+
+// Hide closes the prompt
+func (cp *CommandPrompt) Hide() {
+	cp.visible = false
+	cp.input.Blur()
+}
+
+// Update handles prompt key presses. It returns the command typed once
+// Enter is pressed, and closes the prompt on Enter or Esc.
+func (cp CommandPrompt) Update(msg tea.Msg) (CommandPrompt, string, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "enter":
+			command := strings.TrimSpace(cp.input.Value())
+			cp.Hide()
+			return cp, command, nil
+		case "esc":
+			cp.Hide()
+			return cp, "", nil
+		}
+	}
+
+	var cmd tea.Cmd
+	cp.input, cmd = cp.input.Update(msg)
+	return cp, "", cmd
+}
+
+// View renders the prompt
+func (cp CommandPrompt) View() string {
+	if !cp.visible {
+		return ""
+	}
+	return cp.input.View()
+}
+
+// IsVisible returns whether the prompt is open
+func (cp CommandPrompt) IsVisible() bool {
+	return cp.visible
+}
+
+// runCommand carries out a command from the : prompt
+func (m Model) runCommand(command string) (Model, tea.Cmd) {
+	switch command {
+	case "":
+		return m, nil
+	case "q", "quit":
+		return m, tea.Quit
+	case "history":
+		m.state = StateHistory
+	case "collections":
+		m.state = StateCollections
+	case "env", "environments":
+		m.state = StateEnvironments
+	case "settings":
+		return m.openSettings(), nil
+	case "builder":
+		m.state = StateRequestBuilder
+		return m.focusField(FocusURL), nil
+	case "help":
+		m.keyboardShortcuts.SetState(m.state)
+		m.keyboardShortcuts.Show()
+	default:
+		line, err := strconv.Atoi(command)
+		if err != nil || line < 1 || m.state != StateResponse || m.currentResponse == nil {
+			m.statusIndicator.Show(fmt.Sprintf("Not a command: %s", command), StatusError)
+			return m, nil
+		}
+		m.responseViewer.GotoLine(line)
+	}
+	return m, nil
+}
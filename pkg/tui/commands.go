@@ -0,0 +1,273 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"onioncli/pkg/collections"
+)
+
+// screenCommand is what a screen action does, whether it comes from a key
+// or the command palette
+type screenCommand struct {
+	run       func(m Model) (Model, tea.Cmd)
+	available func(m Model) bool // nil when the command always applies
+}
+
+// screenCommands holds the command for each screen action. It is filled in
+// by init, since the palette's command lists the others.
+var screenCommands map[keyAction]screenCommand
+
+func init() {
+	screenCommands = map[keyAction]screenCommand{
+		actionCommand: {run: func(m Model) (Model, tea.Cmd) {
+			return m, m.commandPrompt.Show()
+		}},
+
+		// Request builder
+		actionSend: {run: Model.sendGuarded, available: func(m Model) bool { return !m.loading }},
+		actionRetry: {run: func(m Model) (Model, tea.Cmd) {
+			m, ok := m.guardRateLimit()
+			if !ok {
+				return m, nil
+			}
+			m.statusIndicator.Show("Retrying request...", StatusLoading)
+			return m.sendRequest()
+		}, available: func(m Model) bool { return m.currentRequest != nil && !m.loading }},
+		actionDryRun: {run: onBuilder(func(m Model) (Model, tea.Cmd) {
+			m.dryRunReport.Show(m.prepareRequest())
+			return m, nil
+		})},
+		actionNewRequest: {run: onBuilder(func(m Model) (Model, tea.Cmd) {
+			return m.clearForm(), nil
+		})},
+		actionDiscardDraft: {run: onBuilder(func(m Model) (Model, tea.Cmd) {
+			return m.discardDraft(), nil
+		})},
+		actionSaveHistory: {run: onBuilder(func(m Model) (Model, tea.Cmd) {
+			m.saveDialog.Show()
+			return m, nil
+		}), available: func(m Model) bool { return m.currentRequest != nil }},
+		actionSaveToCollection: {run: Model.openSaveToCollection},
+		actionCopyCurl:         {run: Model.copyBuilderAsCurl},
+		actionImportCurl: {run: onBuilder(func(m Model) (Model, tea.Cmd) {
+			m.curlImport.Show()
+			return m, nil
+		})},
+		actionAuth: {run: onBuilder(func(m Model) (Model, tea.Cmd) {
+			m.authDialog.Show()
+			return m, nil
+		})},
+		actionNegotiation: {run: onBuilder(func(m Model) (Model, tea.Cmd) {
+			m.negotiationMenu.Show(m.parseHeaders(m.headersArea.Value()))
+			return m, nil
+		})},
+		actionToggleScheme: {run: onBuilder(func(m Model) (Model, tea.Cmd) {
+			return m.toggleURLScheme(), nil
+		})},
+		actionShowVariables: {run: onBuilder(func(m Model) (Model, tea.Cmd) {
+			m.variablesPanel.Show(collections.ReferencedVariables(
+				m.urlInput.Value(), m.queryArea.Value(), m.headersArea.Value(), m.bodyArea.Value()))
+			return m, nil
+		})},
+		actionInsertVariable: {run: func(m Model) (Model, tea.Cmd) {
+			if m.focusedField != FocusQuery && m.focusedField != FocusHeaders && m.focusedField != FocusBody {
+				m.statusIndicator.Show("Focus the query, headers or body to insert variables", StatusInfo)
+				return m, nil
+			}
+			m.variablePicker.Show()
+			return m, nil
+		}},
+		actionPreviewAll: {run: func(m Model) (Model, tea.Cmd) {
+			m.previewAll = !m.previewAll
+			if m.previewAll {
+				m.statusIndicator.Show("Previewing substitution in headers and body", StatusInfo)
+			} else {
+				m.statusIndicator.Show("Previewing substitution in the URL only", StatusInfo)
+			}
+			return m, nil
+		}},
+		actionSnippets: {run: func(m Model) (Model, tea.Cmd) {
+			if m.focusedField != FocusBody {
+				m.statusIndicator.Show("Focus the body to use snippets", StatusInfo)
+				return m, nil
+			}
+			m.snippetsMenu.Show(m.bodyArea.Value())
+			return m, nil
+		}},
+		actionToggleBodyMode: {run: func(m Model) (Model, tea.Cmd) {
+			return m.toggleBodyMode(), nil
+		}, available: bodyFocused},
+		actionFormatBody: {run: func(m Model) (Model, tea.Cmd) {
+			return m.formatBody(), nil
+		}, available: bodyFocused},
+		actionDiffBody: {run: func(m Model) (Model, tea.Cmd) {
+			if !m.hasLastSentBody {
+				m.statusIndicator.Show("No previously sent body to compare against", StatusInfo)
+				return m, nil
+			}
+			m.diffViewer.Show("Body changes since last send", m.lastSentBody, m.bodyArea.Value())
+			return m, nil
+		}},
+		actionJumpToField: {run: func(m Model) (Model, tea.Cmd) {
+			m.awaitingFieldJump = true
+			m.statusMessage = "Go to field: u URL, q query, m method, h headers, b body, s submit"
+			return m, nil
+		}},
+		actionNextField: {run: func(m Model) (Model, tea.Cmd) {
+			return m.nextField(), nil
+		}},
+		actionPrevField: {run: func(m Model) (Model, tea.Cmd) {
+			return m.prevField(), nil
+		}},
+		actionEnvCompare: {run: Model.startEnvCompare, available: func(m Model) bool { return !m.envCompare.running }},
+		actionErrorDetails: {run: func(m Model) (Model, tea.Cmd) {
+			return m.showErrorDetails(m.errorAlert.Diagnostic())
+		}, available: func(m Model) bool { return m.errorAlert.IsVisible() }},
+
+		// Response
+		actionAutoRetry: {run: func(m Model) (Model, tea.Cmd) {
+			m.autoRetry = !m.autoRetry
+			return m, nil
+		}, available: Model.isRateLimited},
+		actionBodyToRequest: {run: onResponse(func(m Model) (Model, tea.Cmd) {
+			return m.responseToRequest(), nil
+		}), available: hasResponse},
+		actionToggleRepresentation: {run: onResponse(Model.toggleRepresentation), available: func(m Model) bool {
+			return m.currentResponse != nil && !m.loading
+		}},
+		actionPin: {run: onResponse(func(m Model) (Model, tea.Cmd) {
+			return m.pinResponse(), nil
+		}), available: hasResponse},
+		actionUnpin: {run: onResponse(func(m Model) (Model, tea.Cmd) {
+			return m.unpinResponse(), nil
+		}), available: func(m Model) bool { return m.pinnedResponse != nil }},
+		actionAudit: {run: onResponse(func(m Model) (Model, tea.Cmd) {
+			m.securityAudit.Show(m.currentResponse)
+			return m, nil
+		}), available: hasResponse},
+		actionCopyResponseCurl: {run: Model.copyResponseRequestAsCurl, available: func(m Model) bool { return m.currentRequest != nil }},
+		actionSaveBody: {run: onResponse(func(m Model) (Model, tea.Cmd) {
+			return m.promptSaveResponse(), nil
+		}), available: hasResponse},
+		actionFlatten: {run: onResponse(func(m Model) (Model, tea.Cmd) {
+			if !m.responseViewer.ToggleFlatten() {
+				m.statusIndicator.Show("Response body is not JSON", StatusInfo)
+			}
+			return m, nil
+		}), available: hasResponse},
+		actionHTMLView: {run: onResponse(func(m Model) (Model, tea.Cmd) {
+			if m.responseViewer.CycleHTMLView() {
+				m.statusIndicator.Show(fmt.Sprintf("HTML view: %s", m.responseViewer.HTMLView()), StatusInfo)
+			} else {
+				m.statusIndicator.Show("Response body is not HTML", StatusInfo)
+			}
+			return m, nil
+		}), available: hasResponse},
+		actionRawExchange: {run: onResponse(func(m Model) (Model, tea.Cmd) {
+			if !m.responseViewer.ToggleRaw() {
+				m.statusIndicator.Show("No raw exchange recorded for this response", StatusInfo)
+			}
+			return m, nil
+		}), available: hasResponse},
+		actionMaskRaw: {run: func(m Model) (Model, tea.Cmd) {
+			if m.responseViewer.ToggleRawMasking() {
+				m.statusIndicator.Show("Sensitive headers masked", StatusInfo)
+			} else {
+				m.statusIndicator.Show("Sensitive headers shown", StatusWarning)
+			}
+			return m, nil
+		}, available: func(m Model) bool { return m.responseViewer.IsRaw() }},
+		actionOpenExternal: {run: onResponse(Model.openResponseExternally), available: hasResponse},
+
+		// Anywhere
+		actionNewCircuit: {run: Model.requestNewCircuit},
+		actionCookies: {run: func(m Model) (Model, tea.Cmd) {
+			m.cookieViewer.Show(m.client)
+			return m, nil
+		}},
+		actionGoHistory:      {run: goTo(StateHistory)},
+		actionGoCollections:  {run: goTo(StateCollections)},
+		actionGoEnvironments: {run: goTo(StateEnvironments)},
+		actionSettings: {run: func(m Model) (Model, tea.Cmd) {
+			return m.openSettings(), nil
+		}},
+		actionHelp: {run: func(m Model) (Model, tea.Cmd) {
+			m.keyboardShortcuts.SetState(m.state)
+			m.keyboardShortcuts.Toggle()
+			return m, nil
+		}},
+		actionPalette: {run: func(m Model) (Model, tea.Cmd) {
+			return m, m.commandPalette.Show(m.paletteCommands())
+		}},
+		actionQuit: {run: func(m Model) (Model, tea.Cmd) {
+			return m, tea.Quit
+		}},
+	}
+}
+
+// sendGuarded sends the request in the builder unless the rate limit
+// holds it back
+func (m Model) sendGuarded() (Model, tea.Cmd) {
+	m, ok := m.guardRateLimit()
+	if !ok {
+		return m, nil
+	}
+	return m.sendRequest()
+}
+
+// openResponseExternally opens the response as currently shown in the
+// pager or editor
+func (m Model) openResponseExternally() (Model, tea.Cmd) {
+	if m.responseViewer.IsRaw() {
+		return m, openTextInExternalViewer(rawExchangeText(m.currentResponse, m.responseViewer.IsRawMasked()), ".txt")
+	}
+	// The flattened list opens as text so paths can be searched and copied
+	if m.responseViewer.IsFlattened() {
+		return m, openTextInExternalViewer(flattenedText(m.currentResponse.FlattenJSON()), ".txt")
+	}
+	if m.responseViewer.IsHTMLText() {
+		return m, openTextInExternalViewer(m.currentResponse.HTMLText(), ".txt")
+	}
+	if m.currentResponse.IsBinary() {
+		m.statusIndicator.Show(binaryHint, StatusInfo)
+		return m, nil
+	}
+	return m, openInExternalViewer(m.currentResponse)
+}
+
+// onBuilder returns run switched to the request builder first, for when it
+// comes from the palette on another screen
+func onBuilder(run func(m Model) (Model, tea.Cmd)) func(m Model) (Model, tea.Cmd) {
+	return func(m Model) (Model, tea.Cmd) {
+		m.state = StateRequestBuilder
+		return run(m)
+	}
+}
+
+// onResponse returns run switched to the response view first
+func onResponse(run func(m Model) (Model, tea.Cmd)) func(m Model) (Model, tea.Cmd) {
+	return func(m Model) (Model, tea.Cmd) {
+		m.state = StateResponse
+		return run(m)
+	}
+}
+
+// goTo returns a command that switches to state
+func goTo(state AppState) func(m Model) (Model, tea.Cmd) {
+	return func(m Model) (Model, tea.Cmd) {
+		m.state = state
+		return m, nil
+	}
+}
+
+// hasResponse reports whether there is a response to act on
+func hasResponse(m Model) bool {
+	return m.currentResponse != nil
+}
+
+// bodyFocused reports whether the body editor has focus
+func bodyFocused(m Model) bool {
+	return m.focusedField == FocusBody
+}
@@ -0,0 +1,94 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// mutatingMethods ask for confirmation before sending when
+// ui.confirm_mutations is on; every other method is sent straight away
+var mutatingMethods = map[string]bool{
+	"POST":   true,
+	"PUT":    true,
+	"PATCH":  true,
+	"DELETE": true,
+}
+
+// needsSendConfirmation reports whether a request with method should be
+// confirmed before it is sent: confirmation is enabled, the method can
+// change data, and the user hasn't chosen "don't ask again" this session
+func needsSendConfirmation(method string, enabled, skipped bool) bool {
+	return enabled && !skipped && mutatingMethods[strings.ToUpper(method)]
+}
+
+// handleSendConfirmation answers the pending send confirmation: Enter or y
+// sends, a sends and stops asking until restart, Esc or n cancels
+func (m Model) handleSendConfirmation(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "enter", "y", "a":
+		if msg.String() == "a" {
+			m.skipSendConfirmation = true
+		}
+		prepared := *m.pendingSend
+		m.pendingSend = nil
+		if prepared.redirect {
+			return m.dispatchRequest(prepared.request, nil)
+		}
+		if prepared.compare != nil {
+			return m.runEnvCompare(prepared.compare)
+		}
+		return m.sendPrepared(prepared)
+	case "esc", "n":
+		m.pendingSend = nil
+		m.statusIndicator.Show("Send cancelled", StatusInfo)
+	}
+	return m, nil
+}
+
+// renderSendConfirmation renders the confirmation shown before sending a
+// mutating request or one with unresolved variables
+func (m Model) renderSendConfirmation() string {
+	req := m.pendingSend.request
+	unresolved := ""
+	if len(m.pendingSend.unresolved) > 0 {
+		unresolved = "Unresolved variables are sent as typed: " + strings.Join(m.pendingSend.unresolved, ", ")
+	}
+	target := ""
+	if compare := m.pendingSend.compare; compare != nil {
+		target = fmt.Sprintf(" to each of %d environments", len(compare))
+	}
+	if m.configManager.Get().UI.Accessible {
+		prompt := fmt.Sprintf("Send %s request to %s%s?", req.Method, req.URL, target)
+		if unresolved != "" {
+			prompt += " " + unresolved + "."
+		}
+		return prompt + " Press Enter or y to send, a to send and stop asking this session, Esc or n to cancel."
+	}
+
+	methodStyle := lipgloss.NewStyle().Foreground(palette.Error).Bold(true)
+	lines := []string{
+		titleStyle.Render("Confirm Send"),
+		"",
+		fmt.Sprintf("%s %s", methodStyle.Render(req.Method), truncateValue(req.URL, 70)),
+	}
+	if target != "" {
+		lines = append(lines, "Sent"+target)
+	}
+	if unresolved != "" {
+		lines = append(lines, "", lipgloss.NewStyle().Foreground(palette.Warning).Render(unresolved))
+	}
+	lines = append(lines, "", helpStyle.Render("Enter/y send • a send and don't ask again this session • Esc/n cancel"))
+
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(palette.Primary).
+		Padding(1, 2).
+		Render(strings.Join(lines, "\n"))
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, dialog)
+}
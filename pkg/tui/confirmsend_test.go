@@ -0,0 +1,30 @@
+package tui
+
+import "testing"
+
+func TestNeedsSendConfirmation(t *testing.T) {
+	tests := []struct {
+		method  string
+		enabled bool
+		skipped bool
+		want    bool
+	}{
+		{"POST", true, false, true},
+		{"PUT", true, false, true},
+		{"PATCH", true, false, true},
+		{"DELETE", true, false, true},
+		{"delete", true, false, true},
+		{"GET", true, false, false},
+		{"HEAD", true, false, false},
+		{"OPTIONS", true, false, false},
+		{"DELETE", false, false, false}, // option off
+		{"DELETE", true, true, false},   // "don't ask again" this session
+	}
+
+	for _, tt := range tests {
+		if got := needsSendConfirmation(tt.method, tt.enabled, tt.skipped); got != tt.want {
+			t.Errorf("needsSendConfirmation(%q, enabled=%v, skipped=%v) = %v, want %v",
+				tt.method, tt.enabled, tt.skipped, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"onioncli/pkg/api"
+)
+
+// CookieViewer lists the cookies the client holds, by host. Values are
+// masked until revealed.
+type CookieViewer struct {
+	hosts    []api.HostCookies
+	enabled  bool
+	revealed bool
+	visible  bool
+	plain    bool // accessible mode: text labels instead of colours
+}
+
+// NewCookieViewer creates a new cookie viewer
+func NewCookieViewer() CookieViewer {
+	return CookieViewer{visible: false}
+}
+
+// Show displays the client's cookies, masked
+func (cv *CookieViewer) Show(client *api.Client) {
+	cv.revealed = false
+	cv.visible = true
+	cv.Refresh(client)
+}
+
+// Refresh reloads the cookies from the client
+func (cv *CookieViewer) Refresh(client *api.Client) {
+	cv.enabled = client != nil && client.CookieJarEnabled()
+	cv.hosts = nil
+	if cv.enabled {
+		cv.hosts = client.Cookies()
+	}
+}
+
+// Hide hides the viewer
+func (cv *CookieViewer) Hide() {
+	cv.visible = false
+	cv.hosts = nil
+}
+
+// ToggleReveal shows or masks cookie values. Returns true if now shown.
+func (cv *CookieViewer) ToggleReveal() bool {
+	cv.revealed = !cv.revealed
+	return cv.revealed
+}
+
+// SetAccessible switches accessible plain-text rendering on or off
+func (cv *CookieViewer) SetAccessible(accessible bool) {
+	cv.plain = accessible
+}
+
+// IsVisible returns whether the viewer is shown
+func (cv CookieViewer) IsVisible() bool {
+	return cv.visible
+}
+
+// count returns how many cookies the client holds
+func (cv CookieViewer) count() int {
+	total := 0
+	for _, host := range cv.hosts {
+		total += len(host.Cookies)
+	}
+	return total
+}
+
+// cookieValue renders a cookie value, masked unless revealed
+func (cv CookieViewer) cookieValue(value string) string {
+	if !cv.revealed {
+		return secretMask
+	}
+	return truncateValue(value, 50)
+}
+
+// lines renders the cookie list, one host heading then one line per cookie
+func (cv CookieViewer) lines(hostStyle, nameStyle lipgloss.Style) []string {
+	if !cv.enabled {
+		return []string{"The cookie jar is off (http.use_cookie_jar); every request is stateless."}
+	}
+	if len(cv.hosts) == 0 {
+		return []string{"No cookies yet."}
+	}
+
+	var lines []string
+	for _, host := range cv.hosts {
+		lines = append(lines, hostStyle.Render(host.Host))
+		for _, cookie := range host.Cookies {
+			lines = append(lines, fmt.Sprintf("  %s %s", nameStyle.Render(truncateValue(cookie.Name, 24)), cv.cookieValue(cookie.Value)))
+		}
+	}
+	return lines
+}
+
+// View renders the viewer
+func (cv CookieViewer) View(width, height int) string {
+	if !cv.visible {
+		return ""
+	}
+
+	keys := "x clear all • m show/mask values • any other key closes"
+	if cv.plain {
+		lines := []string{fmt.Sprintf("Cookies: %d.", cv.count())}
+		lines = append(lines, cv.lines(lipgloss.NewStyle(), lipgloss.NewStyle())...)
+		lines = append(lines, "Keys: "+keys+".")
+		return strings.Join(lines, "\n")
+	}
+
+	hostStyle := lipgloss.NewStyle().Foreground(palette.Info).Bold(true)
+	nameStyle := lipgloss.NewStyle().Foreground(palette.Highlight).Width(24)
+
+	lines := []string{titleStyle.Render(fmt.Sprintf("Cookies (%d)", cv.count())), ""}
+	lines = append(lines, cv.lines(hostStyle, nameStyle)...)
+	lines = append(lines, "", helpStyle.Render(keys))
+
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(palette.Primary).
+		Padding(1, 2).
+		Render(strings.Join(lines, "\n"))
+
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, dialog)
+}
+
+// updateCookieViewer handles keys while the cookie viewer is open
+func (m Model) updateCookieViewer(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "x":
+		if m.client != nil && m.client.CookieJarEnabled() {
+			m.client.ClearCookies()
+			m.cookieViewer.Refresh(m.client)
+			m.statusIndicator.Show("Cookies cleared", StatusSuccess)
+		}
+	case "m":
+		if m.cookieViewer.ToggleReveal() {
+			m.statusIndicator.Show("Cookie values shown", StatusWarning)
+		} else {
+			m.statusIndicator.Show("Cookie values masked", StatusInfo)
+		}
+	default:
+		m.cookieViewer.Hide()
+	}
+	return m, nil
+}
@@ -0,0 +1,125 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// CurlCopiedMsg carries the outcome of copying a cURL command
+type CurlCopiedMsg struct {
+	command string
+	err     error
+}
+
+// CurlView shows an exported cURL command when it can't be copied
+type CurlView struct {
+	viewport viewport.Model
+	notice   string
+	visible  bool
+}
+
+// NewCurlView creates a new cURL command box
+func NewCurlView() CurlView {
+	vp := viewport.New(76, 14)
+	vp.Style = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		Padding(0, 1)
+
+	return CurlView{viewport: vp}
+}
+
+// Show displays command with a notice saying why it is shown
+func (cv *CurlView) Show(command, notice string) {
+	cv.notice = notice
+	cv.viewport.SetContent(command)
+	cv.viewport.GotoTop()
+	cv.visible = true
+}
+
+// Hide hides the box
+func (cv *CurlView) Hide() {
+	cv.visible = false
+}
+
+// IsVisible returns whether the box is visible
+func (cv CurlView) IsVisible() bool {
+	return cv.visible
+}
+
+// Update scrolls the command; Esc, q or Enter close the box
+func (cv CurlView) Update(msg tea.Msg) (CurlView, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc", "q", "enter":
+			cv.Hide()
+			return cv, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	cv.viewport, cmd = cv.viewport.Update(msg)
+	return cv, cmd
+}
+
+// View renders the box
+func (cv CurlView) View() string {
+	if !cv.visible {
+		return ""
+	}
+	return lipgloss.JoinVertical(lipgloss.Left,
+		titleStyle.Render("cURL Command"),
+		errorStyle.Render(cv.notice),
+		viewportView(cv.viewport, palette.Primary),
+		helpStyle.Render("↑/↓ to scroll, select the text to copy it, Esc to close"))
+}
+
+// curlTorProxy is the proxy an exported command should use: the client's,
+// if it sends through Tor
+func (m Model) curlTorProxy() string {
+	if !m.client.IsTorEnabled() {
+		return ""
+	}
+	return m.client.GetTorProxy()
+}
+
+// copyBuilderAsCurl runs the builder through the pre-send pipeline, so
+// variables, default headers and auth are applied, and copies the result
+// as a cURL command
+func (m Model) copyBuilderAsCurl() (Model, tea.Cmd) {
+	prepared := m.prepareRequest()
+	if prepared.err != nil {
+		m.statusIndicator.Show(fmt.Sprintf("Cannot export as cURL: %v", prepared.err), StatusError)
+		return m, nil
+	}
+	if prepared.needsToken {
+		m.statusIndicator.Show("Cannot export as cURL until the OAuth2 token is fetched: send the request first", StatusWarning)
+		return m, nil
+	}
+	return m, copyCurl(prepared.request.ToCurl(m.curlTorProxy()))
+}
+
+// copyResponseRequestAsCurl copies the request behind the shown response
+func (m Model) copyResponseRequestAsCurl() (Model, tea.Cmd) {
+	return m, copyCurl(m.currentRequest.ToCurl(m.curlTorProxy()))
+}
+
+// copyCurl copies command to the clipboard in the background
+func copyCurl(command string) tea.Cmd {
+	return func() tea.Msg {
+		return CurlCopiedMsg{command: command, err: clipboard.WriteAll(command)}
+	}
+}
+
+// handleCurlCopied confirms the copy, or shows the command to copy by hand
+func (m Model) handleCurlCopied(msg CurlCopiedMsg) Model {
+	if msg.err != nil {
+		m.curlView.Show(msg.command, fmt.Sprintf("Clipboard unavailable (%v)", msg.err))
+		return m
+	}
+	m.statusIndicator.Show("Copied as cURL (includes any credentials)", StatusSuccess)
+	return m
+}
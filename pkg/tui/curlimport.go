@@ -0,0 +1,163 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"onioncli/pkg/api"
+)
+
+// CurlImportMsg carries a parsed cURL command to load into the builder
+type CurlImportMsg struct {
+	request *api.Request
+	auth    *api.AuthConfig
+}
+
+// CurlImportDialog takes a pasted cURL command and parses it
+type CurlImportDialog struct {
+	commandArea textarea.Model
+	err         string // why the last command could not be parsed
+	visible     bool
+}
+
+// NewCurlImportDialog creates a new cURL import dialog
+func NewCurlImportDialog() CurlImportDialog {
+	commandArea := textarea.New()
+	commandArea.Placeholder = "curl 'http://example.onion/api' -H 'Accept: application/json'"
+	commandArea.ShowLineNumbers = false
+	commandArea.CharLimit = 0
+	commandArea.SetWidth(70)
+	commandArea.SetHeight(8)
+
+	return CurlImportDialog{commandArea: commandArea}
+}
+
+// Show shows the dialog with an empty command
+func (d *CurlImportDialog) Show() {
+	d.visible = true
+	d.err = ""
+	d.commandArea.Reset()
+	d.commandArea.Focus()
+}
+
+// Hide hides the dialog
+func (d *CurlImportDialog) Hide() {
+	d.visible = false
+	d.err = ""
+	d.commandArea.Blur()
+}
+
+// IsVisible returns whether the dialog is visible
+func (d CurlImportDialog) IsVisible() bool {
+	return d.visible
+}
+
+// Update handles dialog updates. Pasted text, newlines included, goes into
+// the command; a typed Enter imports it.
+func (d CurlImportDialog) Update(msg tea.Msg) (CurlImportDialog, tea.Cmd) {
+	if !d.visible {
+		return d, nil
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok && !msg.Paste {
+		switch msg.String() {
+		case "enter", "ctrl+s":
+			req, auth, err := api.ParseCurl(strings.TrimSpace(d.commandArea.Value()))
+			if err != nil {
+				d.err = err.Error()
+				return d, nil
+			}
+			d.Hide()
+			return d, func() tea.Msg { return CurlImportMsg{request: req, auth: auth} }
+		case "esc":
+			d.Hide()
+			return d, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	d.commandArea, cmd = d.commandArea.Update(msg)
+	return d, cmd
+}
+
+// View renders the dialog
+func (d CurlImportDialog) View() string {
+	if !d.visible {
+		return ""
+	}
+
+	sections := []string{
+		titleStyle.Render("Import cURL Command"),
+		focusedStyle.Render("Paste a cURL command (e.g. Copy as cURL from browser devtools):\n" + d.commandArea.View()),
+	}
+	if d.err != "" {
+		sections = append(sections, errorStyle.Render(d.err))
+	}
+	sections = append(sections, helpStyle.Render("Enter to import, Esc to cancel"))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(palette.Primary).
+		Padding(1).
+		Render(strings.Join(sections, "\n\n"))
+}
+
+// importCurl parses a cURL command and loads it into the builder
+func (m Model) importCurl(command string) Model {
+	req, auth, err := api.ParseCurl(strings.TrimSpace(command))
+	if err != nil {
+		m.statusIndicator.Show(fmt.Sprintf("Could not import cURL command: %v", err), StatusError)
+		return m
+	}
+	return m.loadCurlRequest(req, auth)
+}
+
+// loadCurlRequest fills the builder from an imported cURL command, replacing
+// the auth if the command carried credentials
+func (m Model) loadCurlRequest(req *api.Request, auth *api.AuthConfig) Model {
+	m.setRequestURL(req.URL)
+	methodKnown := m.selectMethod(req.Method)
+
+	keys := make([]string, 0, len(req.Headers))
+	for key := range req.Headers {
+		// A form's Content-Type carries a boundary made afresh on each send
+		if req.Multipart != nil && strings.EqualFold(key, "Content-Type") {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	headerLines := make([]string, len(keys))
+	for i, key := range keys {
+		headerLines[i] = fmt.Sprintf("%s: %s", key, req.Headers[key])
+	}
+	m.headersArea.SetValue(strings.Join(headerLines, "\n"))
+
+	if req.Multipart != nil {
+		m.setBodyForm(true)
+		m.bodyArea.SetValue(formatFormBody(req.Multipart))
+	} else {
+		m.setBodyForm(false)
+		m.bodyArea.SetValue(req.Body)
+	}
+	m.postProcess = nil
+	m.collectionRequest = nil
+	if auth != nil {
+		m.authConfig = auth
+	}
+
+	switch {
+	case !methodKnown:
+		m.statusIndicator.Show(fmt.Sprintf("Imported cURL command, but %s is not a supported method", req.Method), StatusWarning)
+	case auth != nil:
+		m.statusIndicator.Show(fmt.Sprintf("Imported %s %s with basic auth", req.Method, req.URL), StatusSuccess)
+	default:
+		m.statusIndicator.Show(fmt.Sprintf("Imported %s %s", req.Method, req.URL), StatusSuccess)
+	}
+	return m
+}
@@ -0,0 +1,234 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"onioncli/pkg/collections"
+)
+
+// StartDataRunMsg requests a data-driven run of the selected request using a CSV file
+type StartDataRunMsg struct {
+	filename string
+}
+
+// DataRunProgressMsg reports progress of an in-flight data-driven or sequence run
+type DataRunProgressMsg struct {
+	done  int
+	total int
+}
+
+// DataRunCompleteMsg is sent once a data-driven run finishes, fails, or is cancelled
+type DataRunCompleteMsg struct {
+	run *collections.DataDrivenRun
+	err error
+}
+
+// startDataRun runs the target request once per CSV row off the UI goroutine,
+// streaming progress messages
+func (cv *CollectionsViewer) startDataRun(filename string) tea.Cmd {
+	if cv.runRequest == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan tea.Msg)
+
+	cv.runCancel = cancel
+	cv.runCh = ch
+	cv.statusMessage = ""
+	cv.runProgress.Show(fmt.Sprintf("Running %s", cv.runRequest.Name), 0)
+
+	manager := cv.manager
+	client := cv.client
+	requestID := cv.runRequest.ID
+	go func() {
+		defer close(ch)
+		progress := collections.ProgressFunc(func(done, total int) {
+			select {
+			case ch <- DataRunProgressMsg{done: done, total: total}:
+			case <-ctx.Done():
+			}
+		})
+		run, err := manager.RunDataDrivenWithProgress(ctx, requestID, filename, client, progress)
+		ch <- DataRunCompleteMsg{run: run, err: err}
+	}()
+
+	return waitForImport(ch)
+}
+
+// CancelRun cancels an in-flight data-driven or sequence run
+func (cv *CollectionsViewer) CancelRun() {
+	if cv.runCancel != nil {
+		cv.runCancel()
+	}
+}
+
+// finishRun clears run state once the run goroutine has finished
+func (cv *CollectionsViewer) finishRun() {
+	if cv.runCancel != nil {
+		cv.runCancel()
+	}
+	cv.runCancel = nil
+	cv.runCh = nil
+	cv.runProgress.Hide()
+}
+
+// IsRunning returns whether a data-driven or sequence run is in progress
+func (cv CollectionsViewer) IsRunning() bool {
+	return cv.runCh != nil
+}
+
+// renderDataRunResults renders the per-row results of the last data-driven run
+func renderDataRunResults(run *collections.DataDrivenRun) string {
+	passStyle := lipgloss.NewStyle().Foreground(palette.Success)
+	failStyle := lipgloss.NewStyle().Foreground(palette.Error)
+	headerStyle := lipgloss.NewStyle().Foreground(palette.Info).Bold(true)
+
+	passed, failed := run.Summary()
+	lines := []string{
+		fmt.Sprintf("%s: %s, %s",
+			run.RequestName,
+			passStyle.Render(fmt.Sprintf("%d passed", passed)),
+			failStyle.Render(fmt.Sprintf("%d failed", failed))),
+		"",
+		headerStyle.Render(fmt.Sprintf("%-5s %-8s %-10s %s", "Row", "Status", "Duration", "Values")),
+	}
+
+	for _, result := range run.Results {
+		status := fmt.Sprintf("%d", result.StatusCode)
+		if result.Error != "" {
+			status = "ERR"
+		}
+
+		style := passStyle
+		if !result.Passed() {
+			style = failStyle
+		}
+
+		line := fmt.Sprintf("%-5d %s %-10v %s",
+			result.Row,
+			style.Render(fmt.Sprintf("%-8s", status)),
+			result.Duration.Truncate(1e6),
+			truncateValue(formatRowValues(run.Columns, result.Values), 50))
+		lines = append(lines, line)
+		if result.Error != "" {
+			lines = append(lines, failStyle.Render("      "+result.Error))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// formatRowValues formats a row's values in column order
+func formatRowValues(columns []string, values map[string]string) string {
+	if len(columns) == 0 {
+		for key := range values {
+			columns = append(columns, key)
+		}
+		sort.Strings(columns)
+	}
+
+	pairs := make([]string, len(columns))
+	for i, column := range columns {
+		pairs[i] = column + "=" + values[column]
+	}
+	return strings.Join(pairs, " ")
+}
+
+// renderDataRunWaterfall renders each row's request as a bar on a shared time
+// axis, marking the slowest request and the critical path through the run
+func renderDataRunWaterfall(run *collections.DataDrivenRun, width int) string {
+	barStyle := lipgloss.NewStyle().Foreground(palette.Info)
+	criticalStyle := lipgloss.NewStyle().Foreground(palette.Critical)
+	failStyle := lipgloss.NewStyle().Foreground(palette.Error)
+	headerStyle := lipgloss.NewStyle().Foreground(palette.Info).Bold(true)
+
+	total := run.Elapsed
+	for _, result := range run.Results {
+		if result.End > total {
+			total = result.End
+		}
+	}
+	if total <= 0 {
+		total = 1
+	}
+
+	const labelWidth, durationWidth = 9, 10
+	barWidth := width - labelWidth - durationWidth - 14
+	if barWidth < 10 {
+		barWidth = 10
+	}
+
+	path := run.CriticalPath()
+	onPath := make(map[int]bool, len(path))
+	rows := make([]string, len(path))
+	var pathTime time.Duration
+	for i, index := range path {
+		onPath[index] = true
+		rows[i] = fmt.Sprintf("%d", run.Results[index].Row)
+		pathTime += run.Results[index].End - run.Results[index].Start
+	}
+
+	lines := []string{
+		headerStyle.Render(fmt.Sprintf("%s: total %v", run.RequestName, total.Truncate(time.Millisecond))),
+		fmt.Sprintf("Critical path: %s (%v)",
+			criticalStyle.Render("row "+strings.Join(rows, " → ")), pathTime.Truncate(time.Millisecond)),
+		"",
+	}
+
+	slowest := run.Slowest()
+	scale := func(d time.Duration) int {
+		return int(float64(d) / float64(total) * float64(barWidth))
+	}
+	for i, result := range run.Results {
+		duration := result.End - result.Start
+		offset := scale(result.Start)
+		length := scale(result.End) - offset
+		if length < 1 {
+			length = 1
+		}
+		if offset+length > barWidth {
+			offset = barWidth - length
+		}
+
+		style := barStyle
+		if onPath[i] {
+			style = criticalStyle
+		}
+		if !result.Passed() {
+			style = failStyle
+		}
+
+		marker := " "
+		if onPath[i] {
+			marker = "*"
+		}
+
+		line := fmt.Sprintf("%sRow %-4d│%s%s%s│ %-*v",
+			marker,
+			result.Row,
+			strings.Repeat(" ", offset),
+			style.Render(strings.Repeat("█", length)),
+			strings.Repeat(" ", barWidth-offset-length),
+			durationWidth,
+			duration.Truncate(time.Millisecond))
+		if i == slowest && len(run.Results) > 1 {
+			line += " ◀ slowest"
+		}
+		lines = append(lines, line)
+	}
+
+	totalLabel := total.Truncate(time.Millisecond).String()
+	axis := "0" + strings.Repeat(" ", max(barWidth-len(totalLabel), 1)) + totalLabel
+	lines = append(lines, strings.Repeat(" ", labelWidth+1)+axis)
+	lines = append(lines, "", helpStyle.UnsetMargins().Render("* on the critical path"))
+
+	return strings.Join(lines, "\n")
+}
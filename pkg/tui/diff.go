@@ -0,0 +1,187 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// diffOp represents the kind of change for a diff line
+type diffOp int
+
+const (
+	diffEqual diffOp = iota
+	diffInsert
+	diffDelete
+)
+
+// diffLine is a single line of a line-based diff
+type diffLine struct {
+	op   diffOp
+	text string
+}
+
+// diffLines computes a line-based diff between two texts using the
+// longest common subsequence of lines
+func diffLines(oldText, newText string) []diffLine {
+	a := strings.Split(oldText, "\n")
+	b := strings.Split(newText, "\n")
+
+	// lcs[i][j] holds the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, diffLine{op: diffEqual, text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, diffLine{op: diffDelete, text: a[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{op: diffInsert, text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, diffLine{op: diffDelete, text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, diffLine{op: diffInsert, text: b[j]})
+	}
+
+	return lines
+}
+
+// renderDiff renders a colored unified-style diff of two texts
+func renderDiff(oldText, newText string) string {
+	if oldText == newText {
+		return lipgloss.NewStyle().
+			Foreground(palette.Muted).
+			Italic(true).
+			Render("(No changes)")
+	}
+
+	insertStyle := lipgloss.NewStyle().Foreground(palette.Success)
+	deleteStyle := lipgloss.NewStyle().Foreground(palette.Error)
+
+	var rendered []string
+	for _, line := range diffLines(oldText, newText) {
+		switch line.op {
+		case diffInsert:
+			rendered = append(rendered, insertStyle.Render("+ "+line.text))
+		case diffDelete:
+			rendered = append(rendered, deleteStyle.Render("- "+line.text))
+		default:
+			rendered = append(rendered, "  "+line.text)
+		}
+	}
+
+	return strings.Join(rendered, "\n")
+}
+
+// DiffViewer displays a scrollable diff between two texts
+type DiffViewer struct {
+	viewport viewport.Model
+	title    string
+	visible  bool
+	width    int
+	height   int
+}
+
+// NewDiffViewer creates a new diff viewer
+func NewDiffViewer(width, height int) DiffViewer {
+	vp := viewport.New(width-4, height-8)
+	vp.Style = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		Padding(1)
+
+	return DiffViewer{
+		viewport: vp,
+		visible:  false,
+		width:    width,
+		height:   height,
+	}
+}
+
+// Show displays the diff between oldText and newText
+func (dv *DiffViewer) Show(title, oldText, newText string) {
+	dv.title = title
+	dv.visible = true
+	dv.viewport.SetContent(renderDiff(oldText, newText))
+	dv.viewport.GotoTop()
+}
+
+// Hide hides the diff viewer
+func (dv *DiffViewer) Hide() {
+	dv.visible = false
+}
+
+// Update handles diff viewer updates
+func (dv DiffViewer) Update(msg tea.Msg) (DiffViewer, tea.Cmd) {
+	if !dv.visible {
+		return dv, nil
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc", "q":
+			dv.Hide()
+			return dv, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	dv.viewport, cmd = dv.viewport.Update(msg)
+	return dv, cmd
+}
+
+// View renders the diff viewer
+func (dv DiffViewer) View() string {
+	if !dv.visible {
+		return ""
+	}
+
+	header := titleStyle.Render(dv.title)
+	legend := fmt.Sprintf("%s  %s",
+		lipgloss.NewStyle().Foreground(palette.Error).Render("- previous"),
+		lipgloss.NewStyle().Foreground(palette.Success).Render("+ current"))
+	footer := lipgloss.NewStyle().
+		Foreground(palette.Muted).
+		Render("↑/↓ scroll • esc/q close diff")
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, legend, viewportView(dv.viewport, palette.Primary), footer)
+}
+
+// Resize updates the diff viewer size
+func (dv *DiffViewer) Resize(width, height int) {
+	dv.width = width
+	dv.height = height
+	dv.viewport.Width = width - 4
+	dv.viewport.Height = height - 8
+}
+
+// IsVisible returns whether the diff viewer is visible
+func (dv DiffViewer) IsVisible() bool {
+	return dv.visible
+}
@@ -0,0 +1,127 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"onioncli/pkg/api"
+	"onioncli/pkg/collections"
+)
+
+// draftSaveDelay is how long the builder must be idle before the draft is
+// written, so typing doesn't write the file on every keystroke
+const draftSaveDelay = 2 * time.Second
+
+// draftSaveMsg fires draftSaveDelay after an edit; only the latest counts
+type draftSaveMsg struct {
+	seq int
+}
+
+// currentDraft captures the request builder's fields
+func (m Model) currentDraft() collections.Draft {
+	method := m.defaultMethod()
+	if item, ok := m.methodList.SelectedItem().(HTTPMethod); ok {
+		method = item.name
+	}
+	var authType api.AuthType
+	if m.authConfig != nil && m.authConfig.Type != api.AuthNone {
+		authType = m.authConfig.Type
+	}
+	return collections.Draft{
+		Method:   method,
+		URL:      m.urlInput.Value(),
+		Query:    m.queryArea.Value(),
+		Headers:  m.headersArea.Value(),
+		Body:     m.bodyArea.Value(),
+		BodyForm: m.bodyForm,
+		AuthType: authType,
+	}
+}
+
+// trackDraft notices builder edits and, with ui.auto_save on, schedules a
+// debounced save of the draft
+func (m Model) trackDraft(cmd tea.Cmd) (Model, tea.Cmd) {
+	draft := m.currentDraft()
+	if draft == m.lastDraft {
+		return m, cmd
+	}
+	m.lastDraft = draft
+	m.draftDirty = true
+	if !m.configManager.Get().UI.AutoSave {
+		return m, cmd
+	}
+
+	m.draftSeq++
+	seq := m.draftSeq
+	return m, tea.Batch(cmd, tea.Tick(draftSaveDelay, func(time.Time) tea.Msg {
+		return draftSaveMsg{seq: seq}
+	}))
+}
+
+// markDraftClean records the builder as holding a request the user loaded
+// rather than typed, so the saved draft is left alone until it is edited
+func (m *Model) markDraftClean() {
+	m.lastDraft = m.currentDraft()
+	m.draftDirty = false
+}
+
+// SaveDraft writes the builder's draft if it was edited since it was last
+// loaded, and is called when the program exits
+func (m Model) SaveDraft() error {
+	if !m.draftDirty {
+		return nil
+	}
+	return m.collectionsManager.SaveDraft(m.currentDraft())
+}
+
+// handleDraftSave writes the draft once editing has paused
+func (m Model) handleDraftSave(msg draftSaveMsg) (Model, tea.Cmd) {
+	if msg.seq != m.draftSeq {
+		return m, nil // superseded by a later edit
+	}
+	if err := m.SaveDraft(); err != nil {
+		m.statusIndicator.Show(fmt.Sprintf("Could not save draft: %v", err), StatusWarning)
+	}
+	return m, nil
+}
+
+// restoreDraft loads the draft left by the last run into the builder
+func (m *Model) restoreDraft() {
+	draft, err := m.collectionsManager.LoadDraft()
+	if err != nil {
+		m.statusMessage = fmt.Sprintf("Could not restore draft: %v", err)
+		return
+	}
+	if draft == nil || draft.IsEmpty() {
+		return
+	}
+
+	m.urlInput.SetValue(draft.URL)
+	m.queryArea.SetValue(draft.Query)
+	m.selectMethod(draft.Method)
+	m.headersArea.SetValue(draft.Headers)
+	m.setBodyForm(draft.BodyForm)
+	m.bodyArea.SetValue(draft.Body)
+	m.markDraftClean()
+	if draft.AuthType != "" && m.authConfig == nil {
+		// The credentials weren't saved; the draft only says what they were
+		m.statusIndicator.Show(fmt.Sprintf("Restored unsaved draft; it used %s auth, set it again with a (x discards it)", draft.AuthType), StatusInfo)
+		return
+	}
+	m.statusIndicator.Show("Restored unsaved draft (x discards it)", StatusInfo)
+}
+
+// discardDraft deletes the saved draft and clears the builder
+func (m Model) discardDraft() Model {
+	if err := m.collectionsManager.DiscardDraft(); err != nil {
+		m.statusIndicator.Show(err.Error(), StatusError)
+		return m
+	}
+	m = m.clearForm()
+	m.authConfig = nil
+	m.markDraftClean()
+	m.statusIndicator.Show("Draft discarded", StatusInfo)
+	return m
+}
@@ -0,0 +1,415 @@
+package tui
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"onioncli/pkg/api"
+	"onioncli/pkg/collections"
+)
+
+// preflightCheck is one step of the pre-send pipeline, as reported by a dry run
+type preflightCheck struct {
+	name    string
+	passed  bool
+	warning bool // passed, but with a problem worth knowing about before sending
+	detail  string
+}
+
+// preparedRequest is the result of running the pre-send pipeline
+type preparedRequest struct {
+	request  *api.Request
+	urlFixes []string
+	checks   []preflightCheck
+	err      error // first problem that stops the request being sent
+
+	// unresolved names the {{var}} placeholders left after substitution;
+	// sending asks first, as they would go out literally
+	unresolved []string
+
+	// redirect marks the next hop of a paused redirect, sent as it is
+	// without resetting the redirect path
+	redirect bool
+
+	// compare holds the per-environment requests of a send to every
+	// environment waiting on confirmation; request is the first of them
+	compare []collections.EnvironmentRequest
+
+	// authWarnings name unset ${VAR} references in the auth, which were
+	// sent empty
+	authWarnings []string
+
+	// hostWarnings describe a clearnet host that works badly over Tor,
+	// such as an IP literal
+	hostWarnings []string
+
+	// needsToken is set when the OAuth2 token must be fetched before the
+	// request can be sent, which is left to sendRequest so Update never
+	// waits on the network
+	needsToken bool
+}
+
+// check records the outcome of a pipeline step; err fails it
+func (p *preparedRequest) check(name string, err error, detail string) {
+	c := preflightCheck{name: name, passed: err == nil, detail: detail}
+	if err != nil {
+		c.detail = err.Error()
+	}
+	p.checks = append(p.checks, c)
+}
+
+// warn records a step that passed with problems that don't stop the request
+// being sent
+func (p *preparedRequest) warn(name string, warnings []string) {
+	p.checks = append(p.checks, preflightCheck{name: name, passed: true, warning: true, detail: strings.Join(warnings, "; ")})
+}
+
+// fail records a step whose problem stops the request being sent
+func (p *preparedRequest) fail(name string, err error) preparedRequest {
+	p.check(name, err, "")
+	if p.err == nil {
+		p.err = err
+	}
+	return *p
+}
+
+// prepareRequest runs the full pre-send pipeline on the builder form:
+// default-header merge, variable substitution, query encoding, URL
+// normalization, onion validation, authentication and body validation. Every step is recorded so
+// a dry run can report it; only problems that would stop a send set err.
+func (m Model) prepareRequest() preparedRequest {
+	envID := ""
+	if env := m.collectionsManager.GetActiveEnvironment(); env != nil {
+		envID = env.ID
+	}
+	return m.prepareRequestFor(envID, m.authConfig)
+}
+
+// prepareRequestFor runs the pre-send pipeline resolving variables from the
+// environment with envID and authenticating with authConfig, which may be
+// nil, rather than the active environment and current auth
+func (m Model) prepareRequestFor(envID string, authConfig *api.AuthConfig) preparedRequest {
+	var p preparedRequest
+
+	selectedItem := m.methodList.SelectedItem()
+	if selectedItem == nil {
+		return p.fail("Method", fmt.Errorf("Please select an HTTP method"))
+	}
+	method := selectedItem.(HTTPMethod).name
+	p.check("Method", nil, method)
+
+	rawURL := strings.TrimSpace(m.urlInput.Value())
+	if rawURL == "" {
+		return p.fail("URL", fmt.Errorf("Please enter a URL"))
+	}
+
+	queryParams, err := parseQueryParams(m.queryArea.Value())
+	if err != nil {
+		return p.fail("Query", fmt.Errorf("Invalid query parameters: %v", err))
+	}
+
+	headersText, timeout, err := extractTimeout(m.headersArea.Value())
+	if err != nil {
+		return p.fail("Timeout", err)
+	}
+	if timeout > 0 {
+		p.check("Timeout", nil, fmt.Sprintf("%v (overrides the %v default)", timeout, m.client.GetTimeout()))
+	}
+
+	req := api.NewRequest(method, rawURL)
+	req.Timeout = timeout
+	for key, value := range m.parseHeaders(strings.TrimSpace(headersText)) {
+		req.SetHeader(key, value)
+	}
+
+	body := strings.TrimSpace(m.bodyArea.Value())
+	if m.bodyForm {
+		fields, files, err := parseFormBody(body)
+		if err != nil {
+			return p.fail("Body", fmt.Errorf("Invalid form: %v", err))
+		}
+		req.SetMultipartBody(fields, files)
+	} else if path, ok := bodyFilePath(body); ok {
+		req.SetBodyFile(path)
+	} else if body != "" {
+		req.SetBody(body)
+	}
+	return m.prepareBuilt(p, req, queryParams, envID, authConfig)
+}
+
+// prepareBuilt runs the rest of the pre-send pipeline on a request built from
+// the form or a definition, adding to the checks already in p
+func (m Model) prepareBuilt(p preparedRequest, req *api.Request, queryParams []queryParam, envID string, authConfig *api.AuthConfig) preparedRequest {
+	method := req.Method
+
+	// Default headers fill in anything the request doesn't set itself,
+	// skipping those whose method condition excludes this method
+	var merged []string
+	for key, value := range m.configManager.DefaultHeadersFor(method) {
+		if !hasHeader(req.Headers, key) {
+			req.SetHeader(key, value)
+			merged = append(merged, key)
+		}
+	}
+	p.check("Default headers", nil, mergedHeadersDetail(merged))
+
+	// Process request with variable substitution
+	req, warnings := m.collectionsManager.ProcessRequestWithEnvironment(req, envID)
+	texts := []string{req.URL, req.Body}
+	for key, value := range req.Headers {
+		texts = append(texts, key, value)
+	}
+
+	// Query params are encoded after substitution, so a variable's value is
+	// encoded along with the rest of the param and placeholders stay intact
+	for i, param := range queryParams {
+		queryParams[i] = queryParam{
+			name:  m.collectionsManager.SubstituteWithEnvironment(param.name, envID),
+			value: m.collectionsManager.SubstituteWithEnvironment(param.value, envID),
+		}
+		texts = append(texts, queryParams[i].name, queryParams[i].value)
+	}
+	req.URL = appendQuery(req.URL, encodeQueryParams(queryParams))
+	var variablesErr error
+	if unresolved := collections.ReferencedVariables(texts...); len(unresolved) > 0 {
+		p.unresolved = unresolved
+		variablesErr = fmt.Errorf("unresolved: %s", strings.Join(unresolved, ", "))
+	} else if len(warnings) > 0 {
+		variablesErr = fmt.Errorf("%s", strings.Join(warnings, "; "))
+	}
+	p.check("Variables", variablesErr, "all resolved")
+
+	// Percent-encode the URL after substitution so "{{var}}" placeholders survive
+	normalizedURL, urlFixes, err := api.NormalizeURL(req.URL)
+	if err != nil {
+		message := fmt.Sprintf("Invalid URL: %v", err)
+		if len(warnings) > 0 {
+			message += " (" + strings.Join(warnings, "; ") + ")"
+		}
+		p.fail("URL", fmt.Errorf("%s", message))
+	} else {
+		req.URL = normalizedURL
+		p.urlFixes = urlFixes
+		detail := normalizedURL
+		if len(urlFixes) > 0 {
+			detail += " (fixed: " + strings.Join(urlFixes, ", ") + ")"
+		}
+		p.check("URL", nil, detail)
+
+		// The client re-checks onion addresses on send; report them here too
+		if parsed, err := url.Parse(req.URL); err == nil && strings.HasSuffix(parsed.Hostname(), ".onion") {
+			onionErr := api.ValidateOnionURL(req.URL)
+			if onionErr == nil && !m.client.IsTorEnabled() {
+				onionErr = fmt.Errorf(".onion URLs require Tor to be enabled")
+			}
+			p.check("Onion address", onionErr, "valid, routed via Tor")
+		} else if m.client.IsTorEnabled() {
+			clearnetWarnings, clearnetErr := api.ValidateClearnetURL(req.URL)
+			if clearnetErr == nil && len(clearnetWarnings) > 0 {
+				p.hostWarnings = clearnetWarnings
+				p.warn("Clearnet host", clearnetWarnings)
+			} else {
+				p.check("Clearnet host", clearnetErr, "hostname resolved by Tor")
+			}
+		}
+	}
+
+	if len(queryParams) > 0 {
+		p.check("Query", queryAuthConflict(queryParams, authConfig), fmt.Sprintf("%d %s", len(queryParams), plural(len(queryParams), "param", "params")))
+	}
+
+	// Apply authentication if configured
+	if authConfig != nil {
+		if err := m.authManager.ValidateAuthConfig(authConfig); err != nil {
+			p.fail("Authentication", fmt.Errorf("Authentication failed: %v", err))
+		} else if authConfig.Type == api.AuthOAuth2 && !m.authManager.HasOAuth2Token(authConfig) {
+			p.check("Authentication", nil, "oauth2, token requested from "+authConfig.TokenURL+" when sent")
+			p.needsToken = true
+		} else if warnings, err := m.authManager.ApplyAuthWithWarnings(req, authConfig); err != nil {
+			p.fail("Authentication", fmt.Errorf("Authentication failed: %v", err))
+		} else if len(warnings) > 0 {
+			p.authWarnings = warnings
+			p.warn("Authentication", warnings)
+		} else {
+			p.check("Authentication", nil, string(authConfig.Type))
+		}
+	} else {
+		p.check("Authentication", nil, "none configured")
+	}
+
+	// Validate request
+	if err := req.Validate(); err != nil {
+		p.fail("Body", fmt.Errorf("Request validation failed: %v", err))
+	} else if req.BodyFile != "" {
+		info, err := os.Stat(req.BodyFile)
+		if err == nil && info.IsDir() {
+			err = fmt.Errorf("%s is a directory", req.BodyFile)
+		}
+		if err == nil {
+			p.check("Body", nil, fmt.Sprintf("%s, %s streamed", req.BodyFile, formatBytes(info.Size())))
+		} else {
+			p.check("Body", err, "")
+		}
+	} else if req.Multipart != nil {
+		p.check("Body", nil, "multipart form, "+formSummary(req.Multipart)+" (files streamed)")
+	} else if req.Body != "" {
+		p.check("Body", nil, fmt.Sprintf("%d bytes", len(req.Body)))
+	} else {
+		p.check("Body", nil, "empty")
+	}
+
+	if p.err == nil {
+		p.request = req
+	}
+	return p
+}
+
+// mergedHeadersDetail lists the default headers merged into a request
+func mergedHeadersDetail(names []string) string {
+	if len(names) == 0 {
+		return "none merged"
+	}
+	sort.Strings(names)
+	return fmt.Sprintf("%d merged: %s", len(names), strings.Join(names, ", "))
+}
+
+// hasHeader reports whether headers contains key, ignoring case
+func hasHeader(headers map[string]string, key string) bool {
+	for existing := range headers {
+		if strings.EqualFold(existing, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// DryRunReport shows the checklist produced by a dry run
+type DryRunReport struct {
+	checks  []preflightCheck
+	request *api.Request
+	visible bool
+	plain   bool // accessible mode: text labels instead of symbols and colours
+}
+
+// NewDryRunReport creates a new dry-run report
+func NewDryRunReport() DryRunReport {
+	return DryRunReport{visible: false}
+}
+
+// Show displays the checks from a dry run
+func (dr *DryRunReport) Show(p preparedRequest) {
+	dr.checks = p.checks
+	dr.request = p.request
+	dr.visible = true
+}
+
+// Hide hides the report
+func (dr *DryRunReport) Hide() {
+	dr.visible = false
+	dr.checks = nil
+	dr.request = nil
+}
+
+// SetAccessible switches accessible plain-text rendering on or off
+func (dr *DryRunReport) SetAccessible(accessible bool) {
+	dr.plain = accessible
+}
+
+// IsVisible returns whether the report is shown
+func (dr DryRunReport) IsVisible() bool {
+	return dr.visible
+}
+
+// failures returns the number of failed checks
+func (dr DryRunReport) failures() int {
+	failed := 0
+	for _, c := range dr.checks {
+		if !c.passed {
+			failed++
+		}
+	}
+	return failed
+}
+
+// warnings returns the number of checks that passed with warnings
+func (dr DryRunReport) warnings() int {
+	warned := 0
+	for _, c := range dr.checks {
+		if c.warning {
+			warned++
+		}
+	}
+	return warned
+}
+
+// summary describes the overall result
+func (dr DryRunReport) summary() string {
+	if failed := dr.failures(); failed > 0 {
+		return fmt.Sprintf("%d %s found, nothing was sent", failed, plural(failed, "problem", "problems"))
+	}
+	if warned := dr.warnings(); warned > 0 {
+		return fmt.Sprintf("Ready to send with %d %s, nothing was sent", warned, plural(warned, "warning", "warnings"))
+	}
+	return "Ready to send, nothing was sent"
+}
+
+// View renders the report
+func (dr DryRunReport) View(width, height int) string {
+	if !dr.visible {
+		return ""
+	}
+
+	if dr.plain {
+		lines := []string{"Dry run. " + dr.summary() + "."}
+		for _, c := range dr.checks {
+			result := "Pass"
+			if !c.passed {
+				result = "Fail"
+			} else if c.warning {
+				result = "Warning"
+			}
+			lines = append(lines, fmt.Sprintf("%s: %s. %s", c.name, result, c.detail))
+		}
+		lines = append(lines, "Press any key to close.")
+		return strings.Join(lines, "\n")
+	}
+
+	passStyle := lipgloss.NewStyle().Foreground(palette.Success).Bold(true)
+	failStyle := lipgloss.NewStyle().Foreground(palette.Error).Bold(true)
+	warnStyle := lipgloss.NewStyle().Foreground(palette.Warning).Bold(true)
+	nameStyle := lipgloss.NewStyle().Foreground(palette.Info).Width(16)
+
+	lines := []string{titleStyle.Render("Dry Run"), ""}
+	for _, c := range dr.checks {
+		mark := passStyle.Render("✓")
+		if !c.passed {
+			mark = failStyle.Render("✗")
+		} else if c.warning {
+			mark = warnStyle.Render("⚠")
+		}
+		lines = append(lines, fmt.Sprintf("%s %s %s", mark, nameStyle.Render(c.name), truncateValue(c.detail, 60)))
+	}
+
+	lines = append(lines, "")
+	if dr.failures() > 0 {
+		lines = append(lines, failStyle.Render(dr.summary()))
+	} else if dr.warnings() > 0 {
+		lines = append(lines, warnStyle.Render(dr.summary()))
+	} else {
+		lines = append(lines, passStyle.Render(dr.summary()))
+	}
+	lines = append(lines, helpStyle.Render("Press any key to close"))
+
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(palette.Primary).
+		Padding(1, 2).
+		Render(strings.Join(lines, "\n"))
+
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, dialog)
+}
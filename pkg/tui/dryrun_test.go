@@ -0,0 +1,26 @@
+package tui
+
+import "testing"
+
+func TestDryRunReportSummary(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks []preflightCheck
+		want   string
+	}{
+		{"all passed", []preflightCheck{{name: "URL", passed: true}}, "Ready to send, nothing was sent"},
+		{"warning", []preflightCheck{{name: "URL", passed: true}, {name: "Clearnet host", passed: true, warning: true}},
+			"Ready to send with 1 warning, nothing was sent"},
+		{"failure", []preflightCheck{{name: "URL", passed: false}, {name: "Clearnet host", passed: true, warning: true}},
+			"1 problem found, nothing was sent"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := DryRunReport{checks: tt.checks}
+			if got := report.summary(); got != tt.want {
+				t.Errorf("summary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
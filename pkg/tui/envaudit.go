@@ -0,0 +1,104 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"onioncli/pkg/collections"
+)
+
+// VariableAuditView lists each environment's referenced, unused and undefined variables
+type VariableAuditView struct {
+	audit   collections.VariableAudit
+	cursor  int
+	message string
+}
+
+// Load replaces the audit being shown, keeping the cursor in range
+func (v *VariableAuditView) Load(audit collections.VariableAudit) {
+	v.audit = audit
+	if v.cursor >= len(audit.Environments) {
+		v.cursor = len(audit.Environments) - 1
+	}
+	if v.cursor < 0 {
+		v.cursor = 0
+	}
+}
+
+// Selected returns the audit of the highlighted environment
+func (v VariableAuditView) Selected() *collections.EnvironmentAudit {
+	if v.cursor < 0 || v.cursor >= len(v.audit.Environments) {
+		return nil
+	}
+	return &v.audit.Environments[v.cursor]
+}
+
+// Update moves the selection
+func (v VariableAuditView) Update(msg tea.Msg) VariableAuditView {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "up", "k":
+			if v.cursor > 0 {
+				v.cursor--
+			}
+		case "down", "j":
+			if v.cursor < len(v.audit.Environments)-1 {
+				v.cursor++
+			}
+		}
+	}
+	return v
+}
+
+// View renders the audit
+func (v VariableAuditView) View() string {
+	var sections []string
+	sections = append(sections, titleStyle.Render("Variable Usage Audit"))
+
+	if len(v.audit.Environments) == 0 {
+		sections = append(sections, statusStyle.Render("No environments to audit"))
+	}
+
+	for i, env := range v.audit.Environments {
+		style := blurredStyle
+		if i == v.cursor {
+			style = focusedStyle
+		}
+
+		lines := []string{lipgloss.NewStyle().Bold(true).Render(env.EnvironmentName)}
+		lines = append(lines, auditLine("Referenced", env.Referenced, palette.Success))
+		lines = append(lines, auditLine("Unused", env.Unused, palette.Warning))
+		lines = append(lines, auditLine("Undefined", env.Undefined, palette.Error))
+		if i == v.cursor {
+			for _, name := range env.Undefined {
+				usedBy := strings.Join(v.audit.References[name], ", ")
+				if usedBy == "" {
+					usedBy = "relative request URLs"
+				}
+				lines = append(lines, helpStyle.UnsetMargins().Render(fmt.Sprintf("  %s used by %s", name, usedBy)))
+			}
+		}
+		sections = append(sections, style.Render(strings.Join(lines, "\n")))
+	}
+
+	if v.message != "" {
+		sections = append(sections, successStyle.Render(v.message))
+	}
+
+	sections = append(sections, helpStyle.Render("↑/↓ to select, Enter to edit environment, d to delete unused variables, esc to go back"))
+	return strings.Join(sections, "\n")
+}
+
+// auditLine renders one labelled list of variable names
+func auditLine(label string, names []string, color lipgloss.Color) string {
+	value := "none"
+	if len(names) > 0 {
+		value = strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("%s %s",
+		lipgloss.NewStyle().Foreground(color).Render(fmt.Sprintf("%-11s", label+":")),
+		value)
+}
@@ -0,0 +1,94 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"onioncli/pkg/api"
+	"onioncli/pkg/collections"
+)
+
+// envAuthService is the keyring service an environment's secrets are kept under
+func envAuthService(env *collections.Environment) string {
+	return "environment-" + env.ID
+}
+
+// saveEnvironmentAuth stores config with the active environment, its
+// secrets in the system keyring, so it is restored on the next run. No
+// auth removes the stored one. Without an active environment, or when
+// the keyring is unavailable, the auth only lasts for this session.
+func (m Model) saveEnvironmentAuth(config *api.AuthConfig) Model {
+	env := m.collectionsManager.GetActiveEnvironment()
+	if env == nil {
+		return m
+	}
+	if config != nil && config.Type == api.AuthNone {
+		config = nil
+	}
+
+	var stored *api.AuthConfig
+	if config != nil {
+		var err error
+		if stored, err = m.authManager.StoreSecrets(envAuthService(env), config); err != nil {
+			m.statusIndicator.Show(fmt.Sprintf("Auth not saved with %s, used for this session only: %v", env.Name, err), StatusWarning)
+			return m
+		}
+	}
+
+	previous := env.Auth
+	if err := m.collectionsManager.SetEnvironmentAuth(env.ID, stored); err != nil {
+		m.statusIndicator.Show(fmt.Sprintf("Auth not saved with %s: %v", env.Name, err), StatusWarning)
+		return m
+	}
+	if err := m.authManager.DeleteSecrets(envAuthService(env), previous, stored); err != nil {
+		m.statusIndicator.Show(fmt.Sprintf("Auth saved with %s, but old secrets were kept: %v", env.Name, err), StatusWarning)
+	}
+	return m
+}
+
+// loadEnvironmentAuth makes env's stored auth the current one, reading its
+// secrets from the keyring. An environment without one leaves the current
+// auth as it is.
+func (m *Model) loadEnvironmentAuth(env *collections.Environment) error {
+	if env == nil || env.Auth == nil {
+		return nil
+	}
+	config, err := m.authManager.LoadSecrets(envAuthService(env), env.Auth)
+	if err != nil {
+		return fmt.Errorf("could not load auth for %s: %w", env.Name, err)
+	}
+	m.authConfig = config
+	return nil
+}
+
+// authSummary describes config for display, with its secrets masked
+func authSummary(am *api.AuthManager, config *api.AuthConfig) string {
+	masked := am.MaskSensitiveData(config)
+	if masked == nil {
+		return "none"
+	}
+
+	switch masked.Type {
+	case api.AuthAPIKey:
+		keyName := masked.KeyName
+		if keyName == "" {
+			keyName = "X-API-Key"
+		}
+		return fmt.Sprintf("%s %s=%s", masked.Type, keyName, masked.APIKey)
+	case api.AuthBearer:
+		return fmt.Sprintf("%s %s", masked.Type, masked.Token)
+	case api.AuthBasic:
+		return fmt.Sprintf("%s %s:%s", masked.Type, masked.Username, masked.Password)
+	case api.AuthOAuth2:
+		return fmt.Sprintf("%s %s as %s", masked.Type, masked.TokenURL, masked.ClientID)
+	case api.AuthCustom:
+		pairs := make([]string, 0, len(masked.Custom))
+		for key, value := range masked.Custom {
+			pairs = append(pairs, key+"="+value)
+		}
+		sort.Strings(pairs)
+		return fmt.Sprintf("%s %s", masked.Type, strings.Join(pairs, ", "))
+	}
+	return string(masked.Type)
+}
@@ -0,0 +1,297 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"onioncli/pkg/api"
+	"onioncli/pkg/collections"
+)
+
+// maxCompareDiffLines bounds the bodies diffed line by line; the diff is
+// quadratic in their length
+const maxCompareDiffLines = 2000
+
+// EnvCompareProgressMsg reports progress of a send to every environment
+type EnvCompareProgressMsg struct {
+	done  int
+	total int
+}
+
+// EnvCompareCompleteMsg is sent once every environment has answered or failed
+type EnvCompareCompleteMsg struct {
+	comparison *collections.EnvironmentComparison
+}
+
+// EnvCompareView shows one request sent to every environment side by side
+type EnvCompareView struct {
+	viewport viewport.Model
+	title    string
+	running  bool
+	done     int
+	total    int
+	cancel   context.CancelFunc
+	ch       chan tea.Msg
+	visible  bool
+}
+
+// NewEnvCompareView creates a new environment comparison view
+func NewEnvCompareView(width, height int) EnvCompareView {
+	vp := viewport.New(width-4, height-8)
+	vp.Style = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		Padding(0, 1)
+
+	return EnvCompareView{viewport: vp}
+}
+
+// IsVisible returns whether the comparison is shown
+func (ec EnvCompareView) IsVisible() bool {
+	return ec.visible
+}
+
+// Resize fits the view to the window
+func (ec *EnvCompareView) Resize(width, height int) {
+	ec.viewport.Width = width - 4
+	ec.viewport.Height = height - 8
+}
+
+// Update scrolls the results; Esc or q cancel a send in progress and close
+// the view
+func (ec EnvCompareView) Update(msg tea.Msg) (EnvCompareView, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc", "q":
+			if ec.cancel != nil {
+				ec.cancel()
+			}
+			ec.visible = false
+			return ec, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	ec.viewport, cmd = ec.viewport.Update(msg)
+	return ec, cmd
+}
+
+// View renders the comparison
+func (ec EnvCompareView) View() string {
+	if !ec.visible {
+		return ""
+	}
+
+	status := ""
+	if ec.running {
+		status = statusStyle.Render(fmt.Sprintf("Sending... %d/%d environments answered", ec.done, ec.total))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left,
+		titleStyle.Render(ec.title),
+		status,
+		viewportView(ec.viewport, palette.Primary),
+		helpStyle.Render("↑/↓ scroll • esc/q close (cancels a send in progress)"))
+}
+
+// environmentAuth returns the auth to send to env with: its own stored auth,
+// or none. The builder's auth was set for the active environment's hosts,
+// so it is never sent to another environment's.
+func (m Model) environmentAuth(env *collections.Environment) (*api.AuthConfig, error) {
+	if env.Auth == nil {
+		return nil, nil
+	}
+	return m.authManager.LoadSecrets(envAuthService(env), env.Auth)
+}
+
+// startEnvCompare resolves the builder's request for every environment and,
+// once confirmed as a single send would be, sends them all
+func (m Model) startEnvCompare() (Model, tea.Cmd) {
+	environments := m.collectionsManager.GetEnvironments()
+	if len(environments) < 2 {
+		m.statusIndicator.Show("Create at least two environments to compare", StatusInfo)
+		return m, nil
+	}
+
+	requests := make([]collections.EnvironmentRequest, len(environments))
+	var first *api.Request
+	var unresolved []string
+	for i := range environments {
+		env := &environments[i]
+		requests[i].Environment = env.Name
+
+		auth, err := m.environmentAuth(env)
+		if err != nil {
+			requests[i].Err = fmt.Errorf("could not load auth: %w", err)
+			continue
+		}
+		prepared := m.prepareRequestFor(env.ID, auth)
+		switch {
+		case prepared.err != nil:
+			requests[i].Err = prepared.err
+		case prepared.needsToken:
+			requests[i].Err = fmt.Errorf("no OAuth2 token yet, send the request once to fetch one")
+		default:
+			for _, check := range prepared.checks {
+				if check.name == "Variables" && !check.passed {
+					requests[i].Err = fmt.Errorf("variables: %s", check.detail)
+				}
+			}
+			requests[i].Request = prepared.request
+			if first == nil {
+				first, unresolved = prepared.request, prepared.unresolved
+			}
+		}
+	}
+	if first == nil {
+		// Nothing can be sent; the first problem is usually the same for all
+		m.statusIndicator.Show(fmt.Sprintf("Could not send to any environment: %v", requests[0].Err), StatusError)
+		return m, nil
+	}
+
+	if len(unresolved) > 0 || needsSendConfirmation(first.Method, m.configManager.Get().UI.ConfirmMutations, m.skipSendConfirmation) {
+		m.pendingSend = &preparedRequest{request: first, unresolved: unresolved, compare: requests}
+		return m, nil
+	}
+	return m.runEnvCompare(requests)
+}
+
+// runEnvCompare sends the resolved requests concurrently, showing the
+// comparison view
+func (m Model) runEnvCompare(requests []collections.EnvironmentRequest) (Model, tea.Cmd) {
+	var method string
+	for _, request := range requests {
+		if request.Request != nil {
+			method = request.Request.Method
+			break
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan tea.Msg)
+	m.envCompare.title = fmt.Sprintf("%s %s across %d environments", method, strings.TrimSpace(m.urlInput.Value()), len(requests))
+	m.envCompare.running = true
+	m.envCompare.done, m.envCompare.total = 0, len(requests)
+	m.envCompare.cancel = cancel
+	m.envCompare.ch = ch
+	m.envCompare.visible = true
+	m.envCompare.viewport.SetContent("")
+
+	client := m.client
+	go func() {
+		defer close(ch)
+		progress := collections.ProgressFunc(func(done, total int) {
+			select {
+			case ch <- EnvCompareProgressMsg{done: done, total: total}:
+			case <-ctx.Done():
+			}
+		})
+		comparison := collections.SendToEnvironments(ctx, client, requests, progress)
+		ch <- EnvCompareCompleteMsg{comparison: comparison}
+	}()
+
+	return m, waitForImport(ch)
+}
+
+// handleEnvCompareMsg records progress or the finished comparison
+func (m Model) handleEnvCompareMsg(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case EnvCompareProgressMsg:
+		m.envCompare.done, m.envCompare.total = msg.done, msg.total
+		return m, waitForImport(m.envCompare.ch)
+	case EnvCompareCompleteMsg:
+		if m.envCompare.cancel != nil {
+			m.envCompare.cancel()
+		}
+		m.envCompare.running = false
+		m.envCompare.cancel = nil
+		m.envCompare.ch = nil
+		m.envCompare.viewport.SetContent(renderEnvComparison(msg.comparison))
+		m.envCompare.viewport.GotoTop()
+	}
+	return m, nil
+}
+
+// renderEnvComparison renders a table of each environment's status,
+// duration and body size, then how each body differs from the first
+// environment that answered
+func renderEnvComparison(comparison *collections.EnvironmentComparison) string {
+	headerStyle := lipgloss.NewStyle().Foreground(palette.Info).Bold(true)
+	passStyle := lipgloss.NewStyle().Foreground(palette.Success)
+	failStyle := lipgloss.NewStyle().Foreground(palette.Error)
+	noteStyle := lipgloss.NewStyle().Foreground(palette.Muted)
+
+	width := len("Environment")
+	for _, result := range comparison.Results {
+		width = max(width, len(result.Environment))
+	}
+
+	var baseline *collections.EnvironmentResult
+	for i := range comparison.Results {
+		if comparison.Results[i].Response != nil {
+			baseline = &comparison.Results[i]
+			break
+		}
+	}
+
+	lines := []string{
+		noteStyle.Render(fmt.Sprintf("Finished in %v", comparison.Elapsed.Truncate(time.Millisecond))),
+		"",
+		headerStyle.Render(fmt.Sprintf("%-*s  %-8s %-10s %-10s %s", width, "Environment", "Status", "Duration", "Size", "Body")),
+	}
+	var diffs []string
+	for i := range comparison.Results {
+		result := &comparison.Results[i]
+		resp := result.Response
+		if resp == nil {
+			lines = append(lines, fmt.Sprintf("%-*s  %s %s", width, result.Environment,
+				failStyle.Render(fmt.Sprintf("%-8s", "ERR")), failStyle.Render(result.Error)))
+			continue
+		}
+
+		style := passStyle
+		if !resp.IsSuccess() {
+			style = failStyle
+		}
+		body := "baseline"
+		if result != baseline {
+			if resp.SameBody(baseline.Response) {
+				body = "same"
+			} else {
+				body = "differs"
+				diffs = append(diffs, "", headerStyle.Render(fmt.Sprintf("%s vs %s", result.Environment, baseline.Environment)),
+					bodyDiff(baseline.Response, resp))
+			}
+		}
+		lines = append(lines, fmt.Sprintf("%-*s  %s %-10v %-10s %s", width, result.Environment,
+			style.Render(fmt.Sprintf("%-8d", resp.StatusCode)),
+			resp.Duration.Truncate(time.Millisecond),
+			formatBytes(int64(len(resp.Body))),
+			body))
+	}
+
+	return strings.Join(append(lines, diffs...), "\n")
+}
+
+// bodyDiff diffs two response bodies, pretty-printing JSON first so changes
+// show line by line
+func bodyDiff(baseline, other *api.Response) string {
+	oldText, err := baseline.PrettyPrintJSON()
+	if err != nil {
+		oldText = baseline.Body
+	}
+	newText, err := other.PrettyPrintJSON()
+	if err != nil {
+		newText = other.Body
+	}
+
+	if strings.Count(oldText, "\n") > maxCompareDiffLines || strings.Count(newText, "\n") > maxCompareDiffLines {
+		return lipgloss.NewStyle().Foreground(palette.Muted).Italic(true).
+			Render(fmt.Sprintf("(bodies differ; too long to diff here, over %d lines)", maxCompareDiffLines))
+	}
+	return renderDiff(oldText, newText)
+}
@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"onioncli/pkg/api"
+	"onioncli/pkg/collections"
+)
+
+func TestRenderEnvComparison(t *testing.T) {
+	comparison := &collections.EnvironmentComparison{Results: []collections.EnvironmentResult{
+		{Environment: "dev", Error: "connection refused"},
+		{Environment: "test", Response: &api.Response{StatusCode: 200, Body: "{\"id\":1}"}},
+		{Environment: "stage", Response: &api.Response{StatusCode: 200, Body: "{\"id\":1}"}},
+		{Environment: "prod", Response: &api.Response{StatusCode: 500, Body: "{\"id\":2}"}},
+	}}
+
+	rendered := renderEnvComparison(comparison)
+	rows := map[string]string{}
+	for _, line := range strings.Split(rendered, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && rows[fields[0]] == "" {
+			rows[fields[0]] = line
+		}
+	}
+
+	for env, want := range map[string]string{
+		"dev":   "connection refused",
+		"test":  "baseline",
+		"stage": "same",
+		"prod":  "differs",
+	} {
+		if !strings.Contains(rows[env], want) {
+			t.Errorf("Row for %s = %q, expected it to contain %q", env, rows[env], want)
+		}
+	}
+	if !strings.Contains(rendered, "prod vs test") || strings.Contains(rendered, "stage vs test") {
+		t.Errorf("Expected a body diff for prod only:\n%s", rendered)
+	}
+}
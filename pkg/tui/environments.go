@@ -2,9 +2,11 @@ package tui
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -30,8 +32,14 @@ func (e EnvironmentItem) Title() string {
 }
 
 func (e EnvironmentItem) Description() string {
-	varCount := len(e.environment.Variables)
-	return fmt.Sprintf("%s (%d variables)", e.environment.Description, varCount)
+	counts := fmt.Sprintf("%d variables", len(e.environment.Variables))
+	if secrets := len(e.environment.SecretKeys); secrets > 0 {
+		counts += fmt.Sprintf(", %d %s", secrets, plural(secrets, "secret", "secrets"))
+	}
+	if e.environment.Auth != nil {
+		return fmt.Sprintf("%s (%s, %s auth)", e.environment.Description, counts, e.environment.Auth.Type)
+	}
+	return fmt.Sprintf("%s (%s)", e.environment.Description, counts)
 }
 
 // EnvironmentsViewer handles the environments management interface
@@ -43,6 +51,8 @@ type EnvironmentsViewer struct {
 	height       int
 	createDialog CreateEnvironmentDialog
 	editDialog   EditEnvironmentDialog
+	audit        VariableAuditView
+	pendingCopy  string // ID of a duplicate not yet saved; deleted if its edit is cancelled
 }
 
 // EnvViewState represents the current view state
@@ -52,6 +62,7 @@ const (
 	ViewEnvironments EnvViewState = iota
 	ViewCreateEnvironment
 	ViewEditEnvironment
+	ViewVariableAudit
 )
 
 // NewEnvironmentsViewer creates a new environments viewer
@@ -88,13 +99,22 @@ func (ev EnvironmentsViewer) Update(msg tea.Msg) (EnvironmentsViewer, tea.Cmd) {
 	// Handle dialogs
 	switch ev.currentView {
 	case ViewCreateEnvironment:
-		ev.createDialog, cmd = ev.createDialog.Update(msg)
-		cmds = append(cmds, cmd)
-		return ev, tea.Batch(cmds...)
+		if _, ok := msg.(CreateEnvironmentMsg); !ok {
+			ev.createDialog, cmd = ev.createDialog.Update(msg)
+			if !ev.createDialog.visible {
+				ev.currentView = ViewEnvironments
+			}
+			return ev, cmd
+		}
 	case ViewEditEnvironment:
-		ev.editDialog, cmd = ev.editDialog.Update(msg)
-		cmds = append(cmds, cmd)
-		return ev, tea.Batch(cmds...)
+		if _, ok := msg.(EditEnvironmentMsg); !ok {
+			ev.editDialog, cmd = ev.editDialog.Update(msg)
+			if !ev.editDialog.visible {
+				ev.discardPendingCopy()
+				ev.currentView = ViewEnvironments
+			}
+			return ev, cmd
+		}
 	}
 
 	switch msg := msg.(type) {
@@ -126,12 +146,30 @@ func (ev EnvironmentsViewer) Update(msg tea.Msg) (EnvironmentsViewer, tea.Cmd) {
 				return ev, nil
 			}
 
+		case "c":
+			// Duplicate selected environment and open the copy for editing
+			if selectedItem := ev.envList.SelectedItem(); selectedItem != nil {
+				envItem := selectedItem.(EnvironmentItem)
+				clone, err := ev.manager.DuplicateEnvironment(envItem.environment.ID)
+				if err != nil {
+					return ev, nil
+				}
+				ev.pendingCopy = clone.ID
+				ev.refreshEnvironments()
+				ev.editDialog.Show(clone)
+				ev.currentView = ViewEditEnvironment
+				return ev, textinput.Blink
+			}
+
 		case "d":
 			// Delete environment (except if it's the only one or active)
 			if selectedItem := ev.envList.SelectedItem(); selectedItem != nil {
 				envItem := selectedItem.(EnvironmentItem)
 				if !envItem.environment.IsActive && len(ev.manager.GetEnvironments()) > 1 {
-					// TODO: Implement delete environment
+					// Its secrets and auth go from the keyring with it
+					if err := ev.manager.DeleteEnvironment(envItem.environment.ID); err != nil {
+						return ev, ev.envList.NewStatusMessage(fmt.Sprintf("Could not delete %s: %v", envItem.environment.Name, err))
+					}
 					ev.refreshEnvironments()
 				}
 				return ev, nil
@@ -141,19 +179,35 @@ func (ev EnvironmentsViewer) Update(msg tea.Msg) (EnvironmentsViewer, tea.Cmd) {
 			// Refresh
 			ev.refreshEnvironments()
 			return ev, nil
+
+		case "u":
+			// Audit variable usage across all collections
+			ev.audit.Load(ev.manager.AuditVariables())
+			ev.audit.message = ""
+			ev.currentView = ViewVariableAudit
+			return ev, nil
 		}
 
 	case CreateEnvironmentMsg:
 		// Create new environment
-		ev.manager.CreateEnvironment(msg.name, msg.description, msg.variables)
+		if _, err := ev.manager.CreateEnvironment(msg.name, msg.description, msg.variables, msg.secretKeys); err != nil {
+			ev.createDialog.err = err.Error()
+			ev.currentView = ViewCreateEnvironment
+			return ev, nil
+		}
 		ev.refreshEnvironments()
 		ev.createDialog.Hide()
 		ev.currentView = ViewEnvironments
 		return ev, nil
 
 	case EditEnvironmentMsg:
-		// Update environment
-		// TODO: Implement environment update
+		// Update environment; the active one stays active and its new
+		// variables apply to the next substitution
+		if err := ev.manager.UpdateEnvironment(msg.id, msg.name, msg.description, msg.variables, msg.secretKeys); err != nil {
+			ev.editDialog.SetError(err)
+			return ev, nil
+		}
+		ev.pendingCopy = ""
 		ev.refreshEnvironments()
 		ev.editDialog.Hide()
 		ev.currentView = ViewEnvironments
@@ -174,6 +228,8 @@ func (ev EnvironmentsViewer) View() string {
 		return ev.createDialog.View()
 	case ViewEditEnvironment:
 		return ev.editDialog.View()
+	case ViewVariableAudit:
+		return ev.audit.View()
 	}
 
 	var sections []string
@@ -192,12 +248,76 @@ func (ev EnvironmentsViewer) View() string {
 	sections = append(sections, ev.envList.View())
 
 	// Help
-	help := helpStyle.Render("Enter/Space to activate, n to create new, e to edit, d to delete, r to refresh, esc to go back")
+	help := helpStyle.Render("Enter/Space to activate, n to create new, e to edit, c to duplicate, d to delete, u to audit variables, r to refresh, esc to go back")
 	sections = append(sections, help)
 
 	return strings.Join(sections, "\n\n")
 }
 
+// discardPendingCopy deletes a duplicate whose edit was cancelled, so
+// duplicating only keeps a copy once it is saved
+func (ev *EnvironmentsViewer) discardPendingCopy() {
+	if ev.pendingCopy == "" {
+		return
+	}
+	ev.manager.DeleteEnvironment(ev.pendingCopy)
+	ev.pendingCopy = ""
+	ev.refreshEnvironments()
+}
+
+// IsCapturingInput returns whether a dialog is currently accepting text input
+func (ev EnvironmentsViewer) IsCapturingInput() bool {
+	return ev.currentView == ViewCreateEnvironment || ev.currentView == ViewEditEnvironment ||
+		ev.envList.FilterState() == list.Filtering
+}
+
+// InSubView returns whether esc should go back within the viewer rather than
+// leaving the environments screen
+func (ev EnvironmentsViewer) InSubView() bool {
+	return ev.currentView == ViewVariableAudit
+}
+
+// updateAudit handles keys on the variable audit screen
+func (ev EnvironmentsViewer) updateAudit(msg tea.KeyMsg) (EnvironmentsViewer, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		ev.currentView = ViewEnvironments
+		return ev, nil
+
+	case "enter":
+		// Jump to the selected environment's edit dialog
+		if selected := ev.audit.Selected(); selected != nil {
+			for _, env := range ev.manager.GetEnvironments() {
+				if env.ID == selected.EnvironmentID {
+					ev.editDialog.Show(&env)
+					ev.currentView = ViewEditEnvironment
+					return ev, textinput.Blink
+				}
+			}
+		}
+		return ev, nil
+
+	case "d":
+		// Delete the selected environment's unused variables
+		selected := ev.audit.Selected()
+		if selected == nil || len(selected.Unused) == 0 {
+			return ev, nil
+		}
+		unused, name := selected.Unused, selected.EnvironmentName
+		if err := ev.manager.DeleteVariables(selected.EnvironmentID, unused); err != nil {
+			ev.audit.message = fmt.Sprintf("Failed to delete variables: %v", err)
+			return ev, nil
+		}
+		ev.refreshEnvironments()
+		ev.audit.Load(ev.manager.AuditVariables())
+		ev.audit.message = fmt.Sprintf("Deleted %d unused %s from %s", len(unused), plural(len(unused), "variable", "variables"), name)
+		return ev, nil
+	}
+
+	ev.audit = ev.audit.Update(msg)
+	return ev, nil
+}
+
 // refreshEnvironments refreshes the environments list
 func (ev *EnvironmentsViewer) refreshEnvironments() {
 	environments := ev.manager.GetEnvironments()
@@ -220,7 +340,8 @@ type CreateEnvironmentDialog struct {
 	nameInput        textinput.Model
 	descriptionInput textinput.Model
 	variablesInput   textinput.Model
-	focusedField     int // 0 = name, 1 = description, 2 = variables
+	focusedField     int    // 0 = name, 1 = description, 2 = variables
+	err              string // why the environment could not be created
 	visible          bool
 }
 
@@ -263,6 +384,7 @@ func (d *CreateEnvironmentDialog) Show() {
 // Hide hides the dialog
 func (d *CreateEnvironmentDialog) Hide() {
 	d.visible = false
+	d.err = ""
 	d.nameInput.SetValue("")
 	d.descriptionInput.SetValue("")
 	d.variablesInput.SetValue("")
@@ -294,12 +416,13 @@ func (d CreateEnvironmentDialog) Update(msg tea.Msg) (CreateEnvironmentDialog, t
 				return d, nil // Don't create without name
 			}
 			description := strings.TrimSpace(d.descriptionInput.Value())
-			variables := d.parseVariables(d.variablesInput.Value())
+			variables, secretKeys := parseVariables(d.variablesInput.Value())
 			return d, func() tea.Msg {
 				return CreateEnvironmentMsg{
 					name:        name,
 					description: description,
 					variables:   variables,
+					secretKeys:  secretKeys,
 				}
 			}
 		case "esc":
@@ -338,26 +461,31 @@ func (d *CreateEnvironmentDialog) updateFocus() {
 	}
 }
 
-// parseVariables parses variables from input string
-func (d *CreateEnvironmentDialog) parseVariables(input string) map[string]string {
+// parseVariables parses variables from a "key=value, key2=value2" string,
+// returning the keys marked as secrets with a "secret " prefix separately
+func parseVariables(input string) (map[string]string, []string) {
 	variables := make(map[string]string)
 	if input == "" {
-		return variables
+		return variables, nil
 	}
 
+	var secretKeys []string
 	pairs := strings.Split(input, ",")
 	for _, pair := range pairs {
 		parts := strings.SplitN(strings.TrimSpace(pair), "=", 2)
 		if len(parts) == 2 {
-			key := strings.TrimSpace(parts[0])
+			key, secret := cutSecretPrefix(strings.TrimSpace(parts[0]))
 			value := strings.TrimSpace(parts[1])
 			if key != "" {
 				variables[key] = value
+				if secret {
+					secretKeys = append(secretKeys, key)
+				}
 			}
 		}
 	}
 
-	return variables
+	return variables, secretKeys
 }
 
 // View renders the dialog
@@ -392,7 +520,7 @@ func (d CreateEnvironmentDialog) View() string {
 	sections = append(sections, descSection)
 
 	// Variables input
-	varLabel := "Variables (key=value, comma separated):"
+	varLabel := "Variables (key=value, comma separated; secret key=value to keep it in the keyring):"
 	var varSection string
 	if d.focusedField == 2 {
 		varSection = focusedStyle.Render(fmt.Sprintf("%s\n%s", varLabel, d.variablesInput.View()))
@@ -401,6 +529,10 @@ func (d CreateEnvironmentDialog) View() string {
 	}
 	sections = append(sections, varSection)
 
+	if d.err != "" {
+		sections = append(sections, errorStyle.Render(d.err))
+	}
+
 	// Help
 	help := helpStyle.Render("Tab to switch fields, Enter to create, Esc to cancel")
 	sections = append(sections, help)
@@ -410,36 +542,158 @@ func (d CreateEnvironmentDialog) View() string {
 	return lipgloss.Place(80, 25, lipgloss.Center, lipgloss.Center,
 		lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#7D56F4")).
+			BorderForeground(palette.Primary).
 			Padding(1).
 			Render(content))
 }
 
-// EditEnvironmentDialog handles editing environments (placeholder)
+// EditEnvironmentDialog handles editing an existing environment
 type EditEnvironmentDialog struct {
-	visible bool
+	envID            string
+	nameInput        textinput.Model
+	descriptionInput textinput.Model
+	variablesArea    textarea.Model // one key=value per line
+	focusedField     int            // 0 = name, 1 = description, 2 = variables
+	err              string         // why the last save was rejected
+	visible          bool
 }
 
 // NewEditEnvironmentDialog creates a new edit environment dialog
 func NewEditEnvironmentDialog() EditEnvironmentDialog {
-	return EditEnvironmentDialog{visible: false}
+	nameInput := textinput.New()
+	nameInput.Placeholder = "Enter environment name..."
+	nameInput.CharLimit = 100
+	nameInput.Width = 50
+
+	descriptionInput := textinput.New()
+	descriptionInput.Placeholder = "Enter description (optional)..."
+	descriptionInput.CharLimit = 200
+	descriptionInput.Width = 50
+
+	variablesArea := textarea.New()
+	variablesArea.Placeholder = "base_url=http://example.onion\nsecret token=..."
+	variablesArea.ShowLineNumbers = false
+	variablesArea.CharLimit = 0
+	variablesArea.SetWidth(60)
+	variablesArea.SetHeight(8)
+
+	return EditEnvironmentDialog{
+		nameInput:        nameInput,
+		descriptionInput: descriptionInput,
+		variablesArea:    variablesArea,
+		visible:          false,
+	}
 }
 
-// Show shows the dialog
+// Show shows the dialog pre-filled with the environment's current values
 func (d *EditEnvironmentDialog) Show(env *collections.Environment) {
 	d.visible = true
-	// TODO: Implement environment editing
+	d.envID = env.ID
+	d.err = ""
+	d.nameInput.SetValue(env.Name)
+	d.descriptionInput.SetValue(env.Description)
+	d.variablesArea.SetValue(formatVariableLines(env.Variables, env.SecretKeys))
+	d.focusedField = 0
+	d.updateFocus()
 }
 
 // Hide hides the dialog
 func (d *EditEnvironmentDialog) Hide() {
 	d.visible = false
+	d.envID = ""
+	d.err = ""
+	d.nameInput.Blur()
+	d.descriptionInput.Blur()
+	d.variablesArea.Blur()
 }
 
-// Update handles dialog updates
+// SetError shows why saving failed, keeping the dialog open
+func (d *EditEnvironmentDialog) SetError(err error) {
+	d.err = err.Error()
+}
+
+// Update handles dialog updates. Enter saves from the name and description
+// fields; in the variables editor it starts a new line and Ctrl+S saves.
 func (d EditEnvironmentDialog) Update(msg tea.Msg) (EditEnvironmentDialog, tea.Cmd) {
-	// TODO: Implement environment editing
-	return d, nil
+	if !d.visible {
+		return d, nil
+	}
+
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "tab":
+			d.focusedField = (d.focusedField + 1) % 3
+			d.updateFocus()
+			return d, nil
+		case "shift+tab":
+			d.focusedField = (d.focusedField + 2) % 3
+			d.updateFocus()
+			return d, nil
+		case "enter", "ctrl+s":
+			if msg.String() == "enter" && d.focusedField == 2 {
+				break
+			}
+			return d.save()
+		case "esc":
+			d.Hide()
+			return d, nil
+		}
+	}
+
+	// Update focused input
+	switch d.focusedField {
+	case 0:
+		d.nameInput, cmd = d.nameInput.Update(msg)
+	case 1:
+		d.descriptionInput, cmd = d.descriptionInput.Update(msg)
+	case 2:
+		d.variablesArea, cmd = d.variablesArea.Update(msg)
+	}
+
+	return d, cmd
+}
+
+// save validates the fields and emits an EditEnvironmentMsg
+func (d EditEnvironmentDialog) save() (EditEnvironmentDialog, tea.Cmd) {
+	name := strings.TrimSpace(d.nameInput.Value())
+	if name == "" {
+		d.err = "Name is required"
+		return d, nil
+	}
+	variables, secretKeys, err := parseVariableLines(d.variablesArea.Value())
+	if err != nil {
+		d.err = err.Error()
+		return d, nil
+	}
+
+	d.err = ""
+	editMsg := EditEnvironmentMsg{
+		id:          d.envID,
+		name:        name,
+		description: strings.TrimSpace(d.descriptionInput.Value()),
+		variables:   variables,
+		secretKeys:  secretKeys,
+	}
+	return d, func() tea.Msg { return editMsg }
+}
+
+// updateFocus updates the focus state of inputs
+func (d *EditEnvironmentDialog) updateFocus() {
+	d.nameInput.Blur()
+	d.descriptionInput.Blur()
+	d.variablesArea.Blur()
+
+	switch d.focusedField {
+	case 0:
+		d.nameInput.Focus()
+	case 1:
+		d.descriptionInput.Focus()
+	case 2:
+		d.variablesArea.Focus()
+	}
 }
 
 // View renders the dialog
@@ -447,7 +701,106 @@ func (d EditEnvironmentDialog) View() string {
 	if !d.visible {
 		return ""
 	}
-	return "Environment editing coming soon..."
+
+	var sections []string
+	sections = append(sections, titleStyle.Render("Edit Environment"))
+
+	fields := []struct {
+		label string
+		view  string
+	}{
+		{"Name:", d.nameInput.View()},
+		{"Description:", d.descriptionInput.View()},
+		{"Variables (key=value, one per line; secret key=value to keep it in the keyring):", d.variablesArea.View()},
+	}
+	for i, field := range fields {
+		style := blurredStyle
+		if d.focusedField == i {
+			style = focusedStyle
+		}
+		sections = append(sections, style.Render(fmt.Sprintf("%s\n%s", field.label, field.view)))
+	}
+
+	if d.err != "" {
+		sections = append(sections, errorStyle.Render(d.err))
+	}
+	sections = append(sections, helpStyle.Render("Tab/Shift+Tab to switch fields, Enter to save (Ctrl+S in variables), Esc to cancel"))
+
+	content := strings.Join(sections, "\n\n")
+	return lipgloss.Place(80, 32, lipgloss.Center, lipgloss.Center,
+		lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(palette.Primary).
+			Padding(1).
+			Render(content))
+}
+
+// parseVariableLines parses the variables editor: one key=value per line,
+// skipping blank lines and # comments. Values keep any commas or = signs.
+// Keys marked "secret " are returned separately; a secret left masked gets
+// an empty value, which keeps the one already stored.
+func parseVariableLines(text string) (map[string]string, []string, error) {
+	variables := make(map[string]string)
+	var secretKeys []string
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		key, secret := cutSecretPrefix(strings.TrimSpace(key))
+		if !ok || key == "" {
+			return nil, nil, fmt.Errorf("line %d: expected key=value, got %q", i+1, line)
+		}
+		if _, exists := variables[key]; exists {
+			return nil, nil, fmt.Errorf("line %d: %s is set more than once", i+1, key)
+		}
+		value = strings.TrimSpace(value)
+		if secret {
+			secretKeys = append(secretKeys, key)
+			if value == secretMask {
+				value = ""
+			}
+		}
+		variables[key] = value
+	}
+	return variables, secretKeys, nil
+}
+
+// formatVariableLines renders variables for the editor, one per line in
+// key order, with secrets marked and masked
+func formatVariableLines(variables map[string]string, secretKeys []string) string {
+	keys := make([]string, 0, len(variables))
+	for key := range variables {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	secrets := make(map[string]bool, len(secretKeys))
+	for _, key := range secretKeys {
+		secrets[key] = true
+	}
+
+	lines := make([]string, len(keys))
+	for i, key := range keys {
+		if secrets[key] {
+			lines[i] = secretPrefix + key + "=" + secretMask
+		} else {
+			lines[i] = key + "=" + variables[key]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// cutSecretPrefix strips the "secret " marker from a key in the editors,
+// reporting whether it was there
+func cutSecretPrefix(key string) (string, bool) {
+	name, ok := strings.CutPrefix(key, secretPrefix)
+	if !ok {
+		return key, false
+	}
+	return strings.TrimSpace(name), true
 }
 
 // Message types
@@ -455,6 +808,7 @@ type CreateEnvironmentMsg struct {
 	name        string
 	description string
 	variables   map[string]string
+	secretKeys  []string
 }
 
 type EditEnvironmentMsg struct {
@@ -462,6 +816,7 @@ type EditEnvironmentMsg struct {
 	name        string
 	description string
 	variables   map[string]string
+	secretKeys  []string
 }
 
 type EnvironmentChangedMsg struct {
@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseVariableLines(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        string
+		want        map[string]string
+		wantSecrets []string
+		wantErr     bool
+	}{
+		{
+			name: "one per line",
+			text: "base_url=http://example.onion\n\n# staging token\ntoken = a=b,c\n",
+			want: map[string]string{"base_url": "http://example.onion", "token": "a=b,c"},
+		},
+		{name: "empty value", text: "id=", want: map[string]string{"id": ""}},
+		{
+			name:        "secrets",
+			text:        "secret token=abc\nsecret  password = ********\nid=1",
+			want:        map[string]string{"token": "abc", "password": "", "id": "1"},
+			wantSecrets: []string{"token", "password"},
+		},
+		{name: "empty", text: "", want: map[string]string{}},
+		{name: "missing equals", text: "base_url http://example.onion", wantErr: true},
+		{name: "missing key", text: "=value", wantErr: true},
+		{name: "duplicate key", text: "id=1\nid=2", wantErr: true},
+		{name: "duplicate secret", text: "id=1\nsecret id=2", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, secrets, err := parseVariableLines(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseVariableLines() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (!reflect.DeepEqual(got, tt.want) || !reflect.DeepEqual(secrets, tt.wantSecrets)) {
+				t.Errorf("parseVariableLines() = %v, %v, want %v, %v", got, secrets, tt.want, tt.wantSecrets)
+			}
+		})
+	}
+
+	// Formatting and parsing back is lossless, except that secrets come
+	// back masked, to keep their stored values
+	variables := map[string]string{"b": "2, 3", "a": "x=y", "token": ""}
+	got, secrets, err := parseVariableLines(formatVariableLines(variables, []string{"token"}))
+	if err != nil || !reflect.DeepEqual(got, variables) || !reflect.DeepEqual(secrets, []string{"token"}) {
+		t.Errorf("Round trip = %v, %v, %v, want %v", got, secrets, err, variables)
+	}
+}
@@ -3,6 +3,7 @@ package tui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
@@ -16,8 +17,14 @@ type ErrorViewer struct {
 	viewport viewport.Model
 	error    *api.DiagnosticError
 	visible  bool
+	plain    bool // accessible mode: linear plain text, no borders or emoji
+	probing  bool // connection checks for a Tor error are running
 	width    int
 	height   int
+
+	// Diagnostic report export: the path prompt and the last outcome
+	reportDialog FilePromptDialog
+	notice       string
 }
 
 // NewErrorViewer creates a new error viewer
@@ -25,14 +32,17 @@ func NewErrorViewer(width, height int) ErrorViewer {
 	vp := viewport.New(width-4, height-8)
 	vp.Style = lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#FF5555")).
 		Padding(1)
 
+	reportDialog := NewFilePromptDialog("Write Diagnostic Report", "onioncli-report.txt", "write",
+		func(path string) tea.Msg { return ExportReportMsg{path: path} })
+
 	return ErrorViewer{
-		viewport: vp,
-		visible:  false,
-		width:    width,
-		height:   height,
+		viewport:     vp,
+		visible:      false,
+		width:        width,
+		height:       height,
+		reportDialog: reportDialog,
 	}
 }
 
@@ -40,14 +50,62 @@ func NewErrorViewer(width, height int) ErrorViewer {
 func (ev *ErrorViewer) Show(err *api.DiagnosticError) {
 	ev.error = err
 	ev.visible = true
-	content := ev.formatError(err)
+	ev.probing = false
+	ev.notice = ""
+	ev.refresh()
+}
+
+// StartProbes marks connection checks as running for the shown error
+func (ev *ErrorViewer) StartProbes() {
+	ev.probing = true
+	ev.refresh()
+}
+
+// SetProbes attaches connection check results to err, updating the view
+// if err is still the one shown
+func (ev *ErrorViewer) SetProbes(err *api.DiagnosticError, results []api.ProbeResult) {
+	err.Probes = results
+	if ev.error == err {
+		ev.probing = false
+		ev.refresh()
+	}
+}
+
+// refresh re-renders the shown error into the viewport
+func (ev *ErrorViewer) refresh() {
+	content := ev.formatError(ev.error)
+	if ev.plain {
+		content = formatAccessibleError(ev.error)
+		if ev.probing {
+			content += "\n\nRunning connection checks."
+		}
+	}
 	ev.viewport.SetContent(content)
 }
 
+// SetAccessible switches accessible plain-text rendering on or off
+func (ev *ErrorViewer) SetAccessible(accessible bool) {
+	ev.plain = accessible
+	if accessible {
+		ev.viewport.Style = lipgloss.NewStyle()
+	}
+}
+
 // Hide hides the error viewer
 func (ev *ErrorViewer) Hide() {
 	ev.visible = false
 	ev.error = nil
+	ev.reportDialog.Hide()
+}
+
+// SetNotice shows the outcome of a report export in the footer
+func (ev *ErrorViewer) SetNotice(notice string) {
+	ev.notice = notice
+}
+
+// Diagnostic returns the error shown
+func (ev ErrorViewer) Diagnostic() *api.DiagnosticError {
+	return ev.error
 }
 
 // Update handles error viewer updates
@@ -58,12 +116,23 @@ func (ev ErrorViewer) Update(msg tea.Msg) (ErrorViewer, tea.Cmd) {
 
 	var cmd tea.Cmd
 
+	if ev.reportDialog.IsVisible() {
+		ev.reportDialog, cmd = ev.reportDialog.Update(msg)
+		return ev, cmd
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "esc", "q":
 			ev.Hide()
 			return ev, nil
+		case "c":
+			ev.notice = "Building diagnostic report..."
+			return ev, func() tea.Msg { return ExportReportMsg{} }
+		case "w":
+			ev.reportDialog.Show()
+			return ev, nil
 		}
 	}
 
@@ -77,11 +146,27 @@ func (ev ErrorViewer) View() string {
 		return ""
 	}
 
+	if ev.reportDialog.IsVisible() {
+		if ev.plain {
+			return "Write the diagnostic report to a file. Type a path, Enter writes, Escape cancels.\n" + ev.reportDialog.pathInput.View()
+		}
+		return lipgloss.Place(ev.width, ev.height, lipgloss.Center, lipgloss.Center, ev.reportDialog.View())
+	}
+
+	if ev.plain {
+		lines := []string{viewportView(ev.viewport, palette.Error),
+			"Keys: up and down scroll, c copies a diagnostic report, w writes it to a file, escape closes the error details."}
+		if ev.notice != "" {
+			lines = append(lines, ev.notice)
+		}
+		return lipgloss.JoinVertical(lipgloss.Left, lines...)
+	}
+
 	// Header with error type and icon
 	header := ev.renderErrorHeader()
 
 	// Viewport with error details
-	content := ev.viewport.View()
+	content := viewportView(ev.viewport, palette.Error)
 
 	// Footer with navigation help
 	footer := ev.renderFooter()
@@ -102,25 +187,28 @@ func (ev ErrorViewer) renderErrorHeader() string {
 	switch ev.error.Type {
 	case api.ErrorTypeTor:
 		icon = "🧅"
-		color = lipgloss.Color("#FF6B6B")
+		color = palette.Error
 	case api.ErrorTypeNetwork:
 		icon = "🌐"
-		color = lipgloss.Color("#FF8E53")
+		color = palette.Warning
 	case api.ErrorTypeTimeout:
 		icon = "⏱️"
-		color = lipgloss.Color("#FFD93D")
+		color = palette.Highlight
 	case api.ErrorTypeDNS:
 		icon = "🔍"
-		color = lipgloss.Color("#6BCF7F")
+		color = palette.Success
 	case api.ErrorTypeAuth:
 		icon = "🔐"
-		color = lipgloss.Color("#4D96FF")
+		color = palette.Info
+	case api.ErrorTypeRedirect:
+		icon = "↪"
+		color = palette.Warning
 	default:
 		icon = "❌"
-		color = lipgloss.Color("#FF5555")
+		color = palette.Error
 	}
 
-	title := fmt.Sprintf("%s %s Error", icon, strings.Title(string(ev.error.Type)))
+	title := fmt.Sprintf("%s %s Error", icon, errorTypeName(ev.error.Type))
 
 	return lipgloss.NewStyle().
 		Foreground(color).
@@ -131,8 +219,11 @@ func (ev ErrorViewer) renderErrorHeader() string {
 // renderFooter renders navigation help
 func (ev ErrorViewer) renderFooter() string {
 	help := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#666666")).
-		Render("↑/↓ scroll • esc/q close error details")
+		Foreground(palette.Muted).
+		Render("↑/↓ scroll • c copy diagnostic report • w write report to file • esc/q close error details")
+	if ev.notice != "" {
+		help = lipgloss.NewStyle().Foreground(palette.Info).Render(ev.notice) + "\n" + help
+	}
 
 	return help
 }
@@ -143,7 +234,7 @@ func (ev ErrorViewer) formatError(err *api.DiagnosticError) string {
 
 	// Error message
 	sections = append(sections, lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FF5555")).
+		Foreground(palette.Error).
 		Bold(true).
 		Render("Error Message:"))
 	sections = append(sections, err.Message)
@@ -152,7 +243,7 @@ func (ev ErrorViewer) formatError(err *api.DiagnosticError) string {
 	// URL if available
 	if err.URL != "" {
 		sections = append(sections, lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#8BE9FD")).
+			Foreground(palette.Info).
 			Bold(true).
 			Render("URL:"))
 		sections = append(sections, err.URL)
@@ -162,20 +253,55 @@ func (ev ErrorViewer) formatError(err *api.DiagnosticError) string {
 	// Status code if available
 	if err.StatusCode != 0 {
 		sections = append(sections, lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFB86C")).
+			Foreground(palette.Warning).
 			Bold(true).
 			Render("Status Code:"))
 		sections = append(sections, fmt.Sprintf("%d", err.StatusCode))
 		sections = append(sections, "")
 	}
 
+	// Retry history, when the client retried before giving up
+	if len(err.Attempts) > 0 {
+		sections = append(sections, lipgloss.NewStyle().
+			Foreground(palette.Accent).
+			Bold(true).
+			Render("Attempts:"))
+		for _, attempt := range err.Attempts {
+			sections = append(sections, fmt.Sprintf("  %s (%v)", attempt, attempt.Duration.Round(time.Millisecond)))
+		}
+		sections = append(sections, "")
+	}
+
+	// Redirect chain, when the redirect limit was hit
+	if len(err.Redirects) > 0 {
+		sections = append(sections, lipgloss.NewStyle().
+			Foreground(palette.Accent).
+			Bold(true).
+			Render("Redirect Chain:"))
+		sections = append(sections, formatRedirectChain(err.Redirects)...)
+		sections = append(sections, "")
+	}
+
 	// Error type specific information
 	sections = append(sections, ev.renderTypeSpecificInfo(err))
 
+	// Live connection checks, run when a Tor error is opened
+	if ev.probing || len(err.Probes) > 0 {
+		sections = append(sections, lipgloss.NewStyle().
+			Foreground(palette.Info).
+			Bold(true).
+			Render("🔍 Connection Checks:"))
+		if ev.probing {
+			sections = append(sections, "  Running...")
+		}
+		sections = append(sections, formatProbeResults(err.Probes)...)
+		sections = append(sections, "")
+	}
+
 	// Suggestions
 	if len(err.Suggestions) > 0 {
 		sections = append(sections, lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#50FA7B")).
+			Foreground(palette.Success).
 			Bold(true).
 			Render("💡 Suggestions:"))
 
@@ -189,12 +315,12 @@ func (ev ErrorViewer) formatError(err *api.DiagnosticError) string {
 	// Retry information
 	if err.IsRetryable() {
 		retryInfo := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#F1FA8C")).
+			Foreground(palette.Highlight).
 			Render("🔄 This error might be resolved by retrying the request.")
 		sections = append(sections, retryInfo)
 	} else {
 		retryInfo := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FF79C6")).
+			Foreground(palette.Critical).
 			Render("⚠️  This error is unlikely to be resolved by retrying.")
 		sections = append(sections, retryInfo)
 	}
@@ -202,6 +328,26 @@ func (ev ErrorViewer) formatError(err *api.DiagnosticError) string {
 	return strings.Join(sections, "\n")
 }
 
+// formatProbeResults renders connection checks as a checklist
+func formatProbeResults(results []api.ProbeResult) []string {
+	passStyle := lipgloss.NewStyle().Foreground(palette.Success).Bold(true)
+	failStyle := lipgloss.NewStyle().Foreground(palette.Error).Bold(true)
+	skipStyle := lipgloss.NewStyle().Foreground(palette.Subtle)
+	nameStyle := lipgloss.NewStyle().Width(18)
+
+	lines := make([]string, 0, len(results))
+	for _, result := range results {
+		mark := failStyle.Render("✗")
+		if result.Skipped {
+			mark = skipStyle.Render("–")
+		} else if result.Passed {
+			mark = passStyle.Render("✓")
+		}
+		lines = append(lines, fmt.Sprintf("  %s %s %s", mark, nameStyle.Render(result.Name), result.Detail))
+	}
+	return lines
+}
+
 // renderTypeSpecificInfo renders additional information based on error type
 func (ev ErrorViewer) renderTypeSpecificInfo(err *api.DiagnosticError) string {
 	var info []string
@@ -209,7 +355,7 @@ func (ev ErrorViewer) renderTypeSpecificInfo(err *api.DiagnosticError) string {
 	switch err.Type {
 	case api.ErrorTypeTor:
 		info = append(info, lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#BD93F9")).
+			Foreground(palette.Accent).
 			Bold(true).
 			Render("🧅 Tor-Specific Information:"))
 		info = append(info, "• Tor requests require the Tor service to be running")
@@ -219,7 +365,7 @@ func (ev ErrorViewer) renderTypeSpecificInfo(err *api.DiagnosticError) string {
 
 	case api.ErrorTypeNetwork:
 		info = append(info, lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#BD93F9")).
+			Foreground(palette.Accent).
 			Bold(true).
 			Render("🌐 Network Information:"))
 		info = append(info, "• Check your internet connection")
@@ -229,7 +375,7 @@ func (ev ErrorViewer) renderTypeSpecificInfo(err *api.DiagnosticError) string {
 
 	case api.ErrorTypeTimeout:
 		info = append(info, lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#BD93F9")).
+			Foreground(palette.Accent).
 			Bold(true).
 			Render("⏱️ Timeout Information:"))
 		info = append(info, "• Tor requests typically take 10-30 seconds")
@@ -239,7 +385,7 @@ func (ev ErrorViewer) renderTypeSpecificInfo(err *api.DiagnosticError) string {
 
 	case api.ErrorTypeAuth:
 		info = append(info, lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#BD93F9")).
+			Foreground(palette.Accent).
 			Bold(true).
 			Render("🔐 Authentication Information:"))
 		info = append(info, "• Verify your credentials are correct")
@@ -251,6 +397,28 @@ func (ev ErrorViewer) renderTypeSpecificInfo(err *api.DiagnosticError) string {
 	return strings.Join(info, "\n")
 }
 
+// formatRedirectChain lists each hop with the status that led to it, marking
+// URLs that were visited more than once
+func formatRedirectChain(chain []api.RedirectHop) []string {
+	visits := make(map[string]int, len(chain))
+	for _, hop := range chain {
+		visits[hop.URL]++
+	}
+
+	lines := make([]string, len(chain))
+	for i, hop := range chain {
+		status := "start"
+		if hop.StatusCode != 0 {
+			status = fmt.Sprintf("%d →", hop.StatusCode)
+		}
+		lines[i] = fmt.Sprintf("  %2d. %-7s %s", i+1, status, hop.URL)
+		if visits[hop.URL] > 1 {
+			lines[i] += " (repeated)"
+		}
+	}
+	return lines
+}
+
 // Resize updates the error viewer size
 func (ev *ErrorViewer) Resize(width, height int) {
 	ev.width = width
@@ -269,7 +437,9 @@ type ErrorAlert struct {
 	message     string
 	errorType   api.ErrorType
 	suggestions []string
+	diagnostic  *api.DiagnosticError // full error, for the details view
 	visible     bool
+	plain       bool // accessible mode: plain text, no border or emoji
 }
 
 // NewErrorAlert creates a new error alert
@@ -289,14 +459,26 @@ func (ea *ErrorAlert) Show(err *api.DiagnosticError) {
 	ea.message = err.Message
 	ea.errorType = err.Type
 	ea.suggestions = err.Suggestions
+	ea.diagnostic = err
 	ea.visible = true
 }
 
+// Diagnostic returns the full error behind the alert
+func (ea ErrorAlert) Diagnostic() *api.DiagnosticError {
+	return ea.diagnostic
+}
+
+// SetAccessible switches accessible plain-text rendering on or off
+func (ea *ErrorAlert) SetAccessible(accessible bool) {
+	ea.plain = accessible
+}
+
 // Hide hides the error alert
 func (ea *ErrorAlert) Hide() {
 	ea.visible = false
 	ea.message = ""
 	ea.suggestions = nil
+	ea.diagnostic = nil
 }
 
 // View renders the error alert
@@ -305,19 +487,27 @@ func (ea ErrorAlert) View() string {
 		return ""
 	}
 
+	if ea.plain {
+		content := fmt.Sprintf("Error: %s", ea.message)
+		if len(ea.suggestions) > 0 {
+			content += fmt.Sprintf(" Suggestion: %s", ea.suggestions[0])
+		}
+		return content
+	}
+
 	// Choose color based on error type
 	var color lipgloss.Color
 	switch ea.errorType {
 	case api.ErrorTypeTor:
-		color = lipgloss.Color("#FF6B6B")
+		color = palette.Error
 	case api.ErrorTypeNetwork:
-		color = lipgloss.Color("#FF8E53")
+		color = palette.Warning
 	case api.ErrorTypeTimeout:
-		color = lipgloss.Color("#FFD93D")
+		color = palette.Highlight
 	case api.ErrorTypeAuth:
-		color = lipgloss.Color("#4D96FF")
+		color = palette.Info
 	default:
-		color = lipgloss.Color("#FF5555")
+		color = palette.Error
 	}
 
 	style := lipgloss.NewStyle().
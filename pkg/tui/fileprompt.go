@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// FilePromptDialog prompts for a file path and emits a message when submitted
+type FilePromptDialog struct {
+	title     string
+	action    string
+	submit    func(path string) tea.Msg
+	pathInput textinput.Model
+	visible   bool
+}
+
+// NewFilePromptDialog creates a file prompt. action names what Enter does in
+// the help text and submit builds the message sent for the entered path.
+func NewFilePromptDialog(title, placeholder, action string, submit func(path string) tea.Msg) FilePromptDialog {
+	pathInput := textinput.New()
+	pathInput.Placeholder = placeholder
+	pathInput.CharLimit = 500
+	pathInput.Width = 50
+
+	return FilePromptDialog{
+		title:     title,
+		action:    action,
+		submit:    submit,
+		pathInput: pathInput,
+		visible:   false,
+	}
+}
+
+// Show shows the dialog
+func (d *FilePromptDialog) Show() {
+	d.visible = true
+	d.pathInput.Focus()
+}
+
+// ShowPath shows the dialog with path filled in as a suggestion
+func (d *FilePromptDialog) ShowPath(path string) {
+	d.Show()
+	d.pathInput.SetValue(path)
+	d.pathInput.CursorEnd()
+}
+
+// Hide hides the dialog
+func (d *FilePromptDialog) Hide() {
+	d.visible = false
+	d.pathInput.SetValue("")
+	d.pathInput.Blur()
+}
+
+// Update handles dialog updates
+func (d FilePromptDialog) Update(msg tea.Msg) (FilePromptDialog, tea.Cmd) {
+	if !d.visible {
+		return d, nil
+	}
+
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "enter":
+			path := strings.TrimSpace(d.pathInput.Value())
+			if path == "" {
+				return d, nil // Nothing to submit without a path
+			}
+			submit := d.submit
+			return d, func() tea.Msg { return submit(path) }
+		case "esc":
+			d.Hide()
+			return d, nil
+		}
+	}
+
+	d.pathInput, cmd = d.pathInput.Update(msg)
+	return d, cmd
+}
+
+// View renders the dialog
+func (d FilePromptDialog) View() string {
+	if !d.visible {
+		return ""
+	}
+
+	var sections []string
+
+	title := titleStyle.Render(d.title)
+	sections = append(sections, title)
+	sections = append(sections, focusedStyle.Render(fmt.Sprintf("%s\n%s", "File:", d.pathInput.View())))
+
+	help := helpStyle.Render(fmt.Sprintf("Enter to %s, Esc to cancel", d.action))
+	sections = append(sections, help)
+
+	content := strings.Join(sections, "\n\n")
+	return lipgloss.Place(80, 20, lipgloss.Center, lipgloss.Center,
+		lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(palette.Primary).
+			Padding(1).
+			Render(content))
+}
+
+// IsVisible returns whether the dialog is visible
+func (d FilePromptDialog) IsVisible() bool {
+	return d.visible
+}
@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"onioncli/pkg/api"
+)
+
+const (
+	rawBodyPlaceholder  = "Request body (JSON, XML, or plain text)"
+	formBodyPlaceholder = "One form field per line: name=value, or name=@/path/to/file to upload a file"
+)
+
+// parseFormBody reads the body area in form mode: one "name=value" field
+// per line, or "name=@path" for a file to upload, curl -F style. Blank
+// lines and lines starting with # are skipped.
+func parseFormBody(text string) (fields, files map[string]string, err error) {
+	fields = make(map[string]string)
+	files = make(map[string]string)
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, nil, fmt.Errorf("line %d: expected name=value or name=@file, got %q", i+1, line)
+		}
+		value = strings.TrimSpace(value)
+		if path, isFile := strings.CutPrefix(value, "@"); isFile {
+			path = strings.TrimSpace(path)
+			if path == "" {
+				return nil, nil, fmt.Errorf("line %d: %s=@ needs a file path", i+1, name)
+			}
+			files[name] = path
+			continue
+		}
+		fields[name] = value
+	}
+	return fields, files, nil
+}
+
+// formatFormBody writes a form the way parseFormBody reads it: fields, then
+// files, each sorted by name
+func formatFormBody(form *api.MultipartBody) string {
+	var lines []string
+	for _, name := range slices.Sorted(maps.Keys(form.Fields)) {
+		lines = append(lines, name+"="+form.Fields[name])
+	}
+	for _, name := range slices.Sorted(maps.Keys(form.Files)) {
+		lines = append(lines, name+"=@"+form.Files[name])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// setBodyForm switches the body area between a raw body and a multipart
+// form, keeping its text
+func (m *Model) setBodyForm(form bool) {
+	m.bodyForm = form
+	if form {
+		m.bodyArea.Placeholder = formBodyPlaceholder
+	} else {
+		m.bodyArea.Placeholder = rawBodyPlaceholder
+	}
+}
+
+// toggleBodyMode flips the body between raw and multipart form mode
+func (m Model) toggleBodyMode() Model {
+	m.setBodyForm(!m.bodyForm)
+	if m.bodyForm {
+		m.statusIndicator.Show("Body is a multipart form: name=value or name=@file per line", StatusInfo)
+	} else {
+		m.statusIndicator.Show("Body is sent as raw text", StatusInfo)
+	}
+	return m
+}
+
+// bodyLabel titles the body field with its mode
+func (m Model) bodyLabel() string {
+	if m.bodyForm {
+		return "Request Body (multipart form, Ctrl+F for raw):"
+	}
+	return "Request Body:"
+}
+
+// formSummary counts a form's fields and files, e.g. "2 fields, 1 file"
+func formSummary(form *api.MultipartBody) string {
+	return fmt.Sprintf("%d %s, %d %s",
+		len(form.Fields), plural(len(form.Fields), "field", "fields"),
+		len(form.Files), plural(len(form.Files), "file", "files"))
+}
@@ -0,0 +1,46 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFormBody(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		wantFields map[string]string
+		wantFiles  map[string]string
+		wantErr    bool
+	}{
+		{
+			name:       "fields and files",
+			text:       "user=alice\n\n# comment\nnote = hello = world\navatar=@/tmp/avatar.png",
+			wantFields: map[string]string{"user": "alice", "note": "hello = world"},
+			wantFiles:  map[string]string{"avatar": "/tmp/avatar.png"},
+		},
+		{name: "empty value", text: "tag=", wantFields: map[string]string{"tag": ""}, wantFiles: map[string]string{}},
+		{name: "empty", text: "", wantFields: map[string]string{}, wantFiles: map[string]string{}},
+		{name: "missing equals", text: "user alice", wantErr: true},
+		{name: "missing name", text: "=alice", wantErr: true},
+		{name: "missing file path", text: "avatar=@", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, files, err := parseFormBody(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFormBody() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(fields, tt.wantFields) {
+				t.Errorf("fields = %v, want %v", fields, tt.wantFields)
+			}
+			if !reflect.DeepEqual(files, tt.wantFiles) {
+				t.Errorf("files = %v, want %v", files, tt.wantFiles)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"onioncli/pkg/api"
+)
+
+// pasteHeaderBlock merges a pasted raw header block (e.g. copied from browser
+// devtools) into the headers editor. Pasted values replace existing headers
+// of the same name; request lines and pseudo-headers are dropped.
+func (m Model) pasteHeaderBlock(raw string) Model {
+	pasted, skipped := api.ParseHeaderBlock(raw)
+	if len(pasted) == 0 {
+		m.statusIndicator.Show("No headers found in pasted text", StatusWarning)
+		return m
+	}
+
+	m.headersArea.SetValue(mergeHeaderFields(m.headersArea.Value(), pasted))
+
+	status := fmt.Sprintf("Pasted %d headers", len(pasted))
+	if len(skipped) > 0 {
+		status += fmt.Sprintf(" (skipped %d non-header lines)", len(skipped))
+	}
+	m.statusIndicator.Show(status, StatusSuccess)
+	return m
+}
+
+// mergeHeaderFields merges fields into a raw header block. A header already
+// present, ignoring case, has its line rewritten in place and any repeats of
+// it dropped; new ones are appended. Every other line is kept as it was.
+func mergeHeaderFields(raw string, fields []api.HeaderField) string {
+	lines := strings.Split(raw, "\n")
+	if strings.TrimSpace(raw) == "" {
+		lines = nil
+	}
+	for _, field := range fields {
+		line := fmt.Sprintf("%s: %s", field.Name, field.Value)
+		replaced := false
+		kept := lines[:0]
+		for _, existing := range lines {
+			if name, ok := headerLineName(existing); ok && strings.EqualFold(name, field.Name) {
+				if replaced {
+					continue
+				}
+				existing, replaced = line, true
+			}
+			kept = append(kept, existing)
+		}
+		lines = kept
+		if !replaced {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// removeHeaderField drops a header's lines from a raw header block, ignoring
+// case, and keeps every other line as it was
+func removeHeaderField(raw, name string) string {
+	var kept []string
+	for _, line := range strings.Split(raw, "\n") {
+		if existing, ok := headerLineName(line); ok && strings.EqualFold(existing, name) {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n")
+}
+
+// headerLineName returns the header name of a line in the headers editor,
+// read as leniently as parseHeaders reads it
+func headerLineName(line string) (string, bool) {
+	if _, ok := timeoutDirectiveValue(line); ok {
+		return "", false
+	}
+	name, _, ok := strings.Cut(strings.TrimSpace(line), ":")
+	name = strings.TrimSpace(name)
+	return name, ok && name != ""
+}
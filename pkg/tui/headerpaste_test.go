@@ -0,0 +1,30 @@
+package tui
+
+import (
+	"testing"
+
+	"onioncli/pkg/api"
+)
+
+func TestMergeHeaderFields(t *testing.T) {
+	raw := "X-{{name}}: {{value}}\n  Indented: kept\naccept: text/html\n@timeout: 90s\nAccept: */*"
+	got := mergeHeaderFields(raw, []api.HeaderField{
+		{Name: "Accept", Value: "application/json"},
+		{Name: "Accept-Encoding", Value: "gzip"},
+	})
+	want := "X-{{name}}: {{value}}\n  Indented: kept\nAccept: application/json\n@timeout: 90s\nAccept-Encoding: gzip"
+	if got != want {
+		t.Errorf("mergeHeaderFields() = %q, want %q", got, want)
+	}
+
+	if got := mergeHeaderFields("", []api.HeaderField{{Name: "Accept", Value: "*/*"}}); got != "Accept: */*" {
+		t.Errorf("mergeHeaderFields() into an empty editor = %q", got)
+	}
+}
+
+func TestRemoveHeaderField(t *testing.T) {
+	got := removeHeaderField("Accept: text/html\n@timeout: 90s\n\tX-{{var}}: 1\nACCEPT: */*", "Accept")
+	if want := "@timeout: 90s\n\tX-{{var}}: 1"; got != want {
+		t.Errorf("removeHeaderField() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,46 @@
+package tui
+
+import (
+	"fmt"
+
+	"onioncli/pkg/api"
+	"onioncli/pkg/collections"
+	"onioncli/pkg/config"
+)
+
+// PrepareRequest runs req through the request builder's pre-send pipeline:
+// default headers, variables from the active environment, URL normalization,
+// onion checks, authentication with authConfig and body validation. It
+// returns the request to send, the problems that don't stop it being sent,
+// such as unresolved variables, and the first one that does.
+func PrepareRequest(configManager *config.Manager, collectionsManager *collections.Manager, client *api.Client, authManager *api.AuthManager, req *api.Request, authConfig *api.AuthConfig) (*api.Request, []string, error) {
+	m := Model{
+		configManager:      configManager,
+		collectionsManager: collectionsManager,
+		client:             client,
+		authManager:        authManager,
+	}
+	envID := ""
+	if env := collectionsManager.GetActiveEnvironment(); env != nil {
+		envID = env.ID
+	}
+
+	p := m.prepareBuilt(preparedRequest{}, req, nil, envID, authConfig)
+	if p.err != nil {
+		return nil, nil, p.err
+	}
+	// Without a UI to wait on, the OAuth2 token is fetched here
+	if p.needsToken {
+		if err := authManager.ApplyAuth(p.request, authConfig); err != nil {
+			return nil, nil, fmt.Errorf("authentication failed: %w", err)
+		}
+	}
+
+	var warnings []string
+	for _, check := range p.checks {
+		if !check.passed || check.warning {
+			warnings = append(warnings, check.name+": "+check.detail)
+		}
+	}
+	return p.request, warnings, nil
+}
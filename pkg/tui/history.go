@@ -1,20 +1,28 @@
 package tui
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"onioncli/pkg/api"
+	"onioncli/pkg/collections"
 	"onioncli/pkg/history"
 )
 
 // HistoryItem represents a history entry for the list component
 type HistoryItem struct {
-	entry history.HistoryEntry
+	entry    history.HistoryEntry
+	checkbox string // "[x] " or "[ ] " while entries are selected
 }
 
 func (h HistoryItem) FilterValue() string {
@@ -22,14 +30,25 @@ func (h HistoryItem) FilterValue() string {
 }
 
 func (h HistoryItem) Title() string {
+	title := fmt.Sprintf("%s %s", h.entry.Method, h.entry.URL)
 	if h.entry.Name != "" {
-		return h.entry.Name
+		title = h.entry.Name
 	}
-	return fmt.Sprintf("%s %s", h.entry.Method, h.entry.URL)
+	if h.entry.IsFavorite {
+		title = "⭐ " + title
+	}
+	return h.checkbox + title
 }
 
 func (h HistoryItem) Description() string {
 	timeStr := h.entry.Timestamp.Format("2006-01-02 15:04")
+	if resp := h.entry.Response; resp != nil {
+		timeStr = fmt.Sprintf("%s · %s · %v", timeStr, resp.Status, time.Duration(resp.DurationMS)*time.Millisecond)
+		if resp.BodyHash != "" {
+			// Enough of the hash to spot runs that returned the same body
+			timeStr += " · " + resp.BodyHash[:min(12, len(resp.BodyHash))]
+		}
+	}
 	if h.entry.Description != "" {
 		return fmt.Sprintf("%s - %s", timeStr, h.entry.Description)
 	}
@@ -44,20 +63,70 @@ type HistoryViewer struct {
 	searching   bool
 	width       int
 	height      int
-	allEntries  []history.HistoryEntry
+	query       string          // search applied to the list
+	favorites   bool            // list only favorites
+	total       int             // entries matching query, loaded or not
+	selected    map[string]bool // IDs of the entries picked with space
+	confirm     *bulkDelete     // waiting for y/n before deleting
+	harDialog   FilePromptDialog
+	harProgress ProgressIndicator
+	harCancel   context.CancelFunc
+	harCh       chan tea.Msg
+	exportHAR   FilePromptDialog
+	detail      HistoryDetail
+	message     string
+}
+
+// ImportHARMsg requests importing a HAR capture into history
+type ImportHARMsg struct {
+	filename string
 }
 
+// HARImportProgressMsg reports how many entries of a HAR file have been parsed
+type HARImportProgressMsg struct {
+	done  int
+	total int
+}
+
+// HARImportCompleteMsg is sent once a HAR file has been parsed, failed, or
+// the import was cancelled
+type HARImportCompleteMsg struct {
+	filename string
+	entries  []history.HistoryEntry
+	err      error
+}
+
+// ExportHARMsg requests writing history to a HAR file
+type ExportHARMsg struct {
+	filename string
+}
+
+// bulkDelete is a deletion of one or more entries waiting to be confirmed
+type bulkDelete struct {
+	prompt string
+	ids    []string
+	all    bool // clear the whole history
+}
+
+// CompareRunsMsg asks to diff a history entry's stored response against the
+// previous run of the same request
+type CompareRunsMsg struct {
+	title    string
+	previous string
+	current  string
+}
+
+// historyPageSize is how many entries the history list loads at a time
+const historyPageSize = 100
+
+// historyLoadAhead is how close to the last loaded entry the cursor gets
+// before the next page is loaded
+const historyLoadAhead = 20
+
 // NewHistoryViewer creates a new history viewer
 func NewHistoryViewer(manager *history.Manager, width, height int) HistoryViewer {
 	// Create list
-	items := make([]list.Item, 0)
-	entries := manager.GetEntries()
-
-	for _, entry := range entries {
-		items = append(items, HistoryItem{entry: entry})
-	}
-
-	l := list.New(items, list.NewDefaultDelegate(), width-4, height-8)
+	l := list.New(nil, list.NewDefaultDelegate(), width-4, height-8)
 	l.Title = "Request History"
 	l.SetShowStatusBar(true)
 	l.SetFilteringEnabled(false) // We'll handle our own filtering
@@ -69,15 +138,23 @@ func NewHistoryViewer(manager *history.Manager, width, height int) HistoryViewer
 	searchInput.CharLimit = 100
 	searchInput.Width = width - 10
 
-	return HistoryViewer{
+	hv := HistoryViewer{
 		list:        l,
 		searchInput: searchInput,
 		manager:     manager,
 		searching:   false,
 		width:       width,
 		height:      height,
-		allEntries:  entries,
+		selected:    make(map[string]bool),
+		harDialog: NewFilePromptDialog("Import HAR", "/path/to/capture.har", "import",
+			func(path string) tea.Msg { return ImportHARMsg{filename: path} }),
+		harProgress: NewProgressIndicator(),
+		exportHAR: NewFilePromptDialog("Export HAR", "/path/to/history.har", "export",
+			func(path string) tea.Msg { return ExportHARMsg{filename: path} }),
+		detail: NewHistoryDetail(width, height),
 	}
+	hv.loadEntries()
+	return hv
 }
 
 // Update handles history viewer updates
@@ -86,7 +163,69 @@ func (hv HistoryViewer) Update(msg tea.Msg) (HistoryViewer, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
+	case ImportHARMsg:
+		hv.harDialog.Hide()
+		return hv, hv.startHARImport(msg.filename)
+
+	case HARImportProgressMsg:
+		hv.harProgress.total = msg.total
+		hv.harProgress.Update(msg.done)
+		return hv, waitForImport(hv.harCh)
+
+	case HARImportCompleteMsg:
+		hv.finishHARImport()
+		err := msg.err
+		if err == nil {
+			// Entries are added here, on the UI goroutine, as the list reads them
+			err = hv.manager.AddImported(msg.entries)
+		}
+		if errors.Is(err, context.Canceled) {
+			hv.message = "Import cancelled"
+		} else if err != nil {
+			hv.message = fmt.Sprintf("Import failed: %v", err)
+		} else {
+			hv.message = fmt.Sprintf("Imported %d requests from %s", len(msg.entries), msg.filename)
+			hv.refresh()
+		}
+		return hv, nil
+
+	case ExportHARMsg:
+		hv.exportHAR.Hide()
+		if err := hv.manager.ExportHAR(msg.filename); err != nil {
+			hv.message = fmt.Sprintf("Export failed: %v", err)
+			return hv, nil
+		}
+		hv.message = fmt.Sprintf("Exported %d requests to %s", len(hv.manager.GetEntries()), msg.filename)
+		return hv, nil
+
 	case tea.KeyMsg:
+		if hv.IsImporting() {
+			// Ignore other keys while a HAR import is in progress; esc cancels via the model
+			return hv, nil
+		}
+		if hv.harDialog.IsVisible() {
+			hv.harDialog, cmd = hv.harDialog.Update(msg)
+			return hv, cmd
+		}
+		if hv.exportHAR.IsVisible() {
+			hv.exportHAR, cmd = hv.exportHAR.Update(msg)
+			return hv, cmd
+		}
+		if hv.detail.IsVisible() {
+			hv.detail, cmd = hv.detail.Update(msg)
+			return hv, cmd
+		}
+		if hv.confirm != nil {
+			switch msg.String() {
+			case "y", "enter":
+				hv.runBulkDelete()
+			case "n", "esc":
+				hv.confirm = nil
+				hv.message = "Nothing deleted"
+			}
+			return hv, nil
+		}
+
 		if hv.searching {
 			switch msg.String() {
 			case "enter":
@@ -117,22 +256,69 @@ func (hv HistoryViewer) Update(msg tea.Msg) (HistoryViewer, tea.Cmd) {
 				// Refresh history
 				hv.refresh()
 				return hv, nil
+			case " ":
+				// Select or unselect the entry for a bulk delete
+				hv.toggleSelection()
+				return hv, nil
 			case "d":
-				// Delete selected entry
+				// Delete the picked entries, or else the one under the cursor
+				if len(hv.selected) > 0 {
+					hv.confirmDelete(&bulkDelete{
+						prompt: fmt.Sprintf("Delete %d selected entries?", len(hv.selected)),
+						ids:    slices.Collect(maps.Keys(hv.selected)),
+					})
+					return hv, nil
+				}
 				if selectedItem := hv.list.SelectedItem(); selectedItem != nil {
-					historyItem := selectedItem.(HistoryItem)
-					hv.manager.Delete(historyItem.entry.ID)
-					hv.refresh()
+					entry := selectedItem.(HistoryItem).entry
+					hv.confirmDelete(&bulkDelete{
+						prompt: fmt.Sprintf("Delete %s %s?", entry.Method, truncateValue(entry.URL, 60)),
+						ids:    []string{entry.ID},
+					})
 					return hv, nil
 				}
+			case "D":
+				// Delete everything matching the search or favorites filter
+				hv.confirmDeleteMatching()
+				return hv, nil
 			case "c":
 				// Clear all history
-				hv.manager.Clear()
-				hv.refresh()
+				hv.confirmDelete(&bulkDelete{
+					prompt: fmt.Sprintf("Clear all %d history entries?", hv.manager.Count()),
+					all:    true,
+				})
+				return hv, nil
+			case "i":
+				// Import a HAR capture
+				hv.message = ""
+				hv.harDialog.Show()
+				return hv, textinput.Blink
+			case "e":
+				// Export history as a HAR file
+				hv.message = ""
+				hv.exportHAR.ShowPath("history.har")
+				return hv, textinput.Blink
+			case "f":
+				// Mark or unmark the selected entry as a favorite
+				hv.toggleFavorite()
+				return hv, nil
+			case "F":
+				// Show only favorites, or everything again
+				hv.favorites = !hv.favorites
+				hv.message = ""
+				hv.loadEntries()
+				return hv, nil
+			case "p":
+				// Compare with the previous run of the same request
+				return hv.compareWithPreviousRun()
+			case "v":
+				// View the stored response
+				hv.showResponse()
 				return hv, nil
 			default:
 				hv.list, cmd = hv.list.Update(msg)
 				cmds = append(cmds, cmd)
+				hv.loadMoreIfNeeded()
 			}
 		}
 	}
@@ -142,6 +328,16 @@ func (hv HistoryViewer) Update(msg tea.Msg) (HistoryViewer, tea.Cmd) {
 
 // View renders the history viewer
 func (hv HistoryViewer) View() string {
+	if hv.harDialog.IsVisible() {
+		return hv.harDialog.View()
+	}
+	if hv.exportHAR.IsVisible() {
+		return hv.exportHAR.View()
+	}
+	if hv.detail.IsVisible() {
+		return hv.detail.View()
+	}
+
 	var sections []string
 
 	// Title
@@ -160,18 +356,220 @@ func (hv HistoryViewer) View() string {
 	// List
 	sections = append(sections, hv.list.View())
 
+	if hv.IsImporting() {
+		sections = append(sections, hv.harProgress.View())
+	} else if hv.confirm != nil {
+		sections = append(sections, errorStyle.Render(hv.confirm.prompt+" (y/n)"))
+	} else if hv.message != "" {
+		sections = append(sections, statusStyle.Render(hv.message))
+	}
+
 	// Help
-	if hv.searching {
+	if hv.IsImporting() {
+		sections = append(sections, helpStyle.Render("Importing... esc to cancel"))
+	} else if hv.searching {
 		help := helpStyle.Render("Enter to search, Esc to cancel")
 		sections = append(sections, help)
 	} else {
-		help := helpStyle.Render("Enter to select, / to search, r to refresh, d to delete, c to clear all, esc to go back")
+		help := helpStyle.Render("Enter to select, v to view response, / to search, f to favorite, F for favorites only, p to compare with previous run, i to import HAR, e to export HAR, r to refresh, space to select, d to delete, D to delete all matching, c to clear all, esc to go back")
 		sections = append(sections, help)
 	}
 
 	return strings.Join(sections, "\n\n")
 }
 
+// compareWithPreviousRun diffs the selected entry's stored response against
+// the previous run, or reports that they are identical
+func (hv HistoryViewer) compareWithPreviousRun() (HistoryViewer, tea.Cmd) {
+	entry := hv.GetSelectedEntry()
+	if entry == nil {
+		return hv, nil
+	}
+	if !entry.HasResponse() {
+		hv.message = "This entry was saved without a response to compare"
+		return hv, nil
+	}
+
+	previous, err := hv.manager.PreviousRun(entry.ID)
+	if err != nil {
+		hv.message = fmt.Sprintf("Compare failed: %v", err)
+		return hv, nil
+	}
+	if previous == nil {
+		hv.message = "No previous run of this request with a stored response"
+		return hv, nil
+	}
+
+	when := previous.Timestamp.Format("2006-01-02 15:04")
+	if previous.Response.BodyHash == entry.Response.BodyHash {
+		hv.message = fmt.Sprintf("✅ Identical to previous run (%s, body %s)", when, entry.Response.BodyHash[:12])
+		return hv, nil
+	}
+
+	hv.message = ""
+	title := fmt.Sprintf("Changes since previous run (%s, %s → %s)", when, previous.Response.Status, entry.Response.Status)
+	if previous.Response.BodyTruncated || entry.Response.BodyTruncated {
+		title += ", stored bodies are cut short"
+	}
+	msg := CompareRunsMsg{
+		title:    title,
+		previous: prettyStoredBody(previous.Response.Body),
+		current:  prettyStoredBody(entry.Response.Body),
+	}
+	return hv, func() tea.Msg { return msg }
+}
+
+// toggleSelection picks or unpicks the entry under the cursor and moves
+// down, so a run of entries can be picked with repeated presses
+func (hv *HistoryViewer) toggleSelection() {
+	entry := hv.GetSelectedEntry()
+	if entry == nil {
+		return
+	}
+	if hv.selected[entry.ID] {
+		delete(hv.selected, entry.ID)
+	} else {
+		hv.selected[entry.ID] = true
+	}
+	hv.message = ""
+	hv.reloadItems()
+	hv.list.CursorDown()
+	hv.loadMoreIfNeeded()
+}
+
+// ClearSelection unpicks every selected entry, reporting whether any were
+func (hv *HistoryViewer) ClearSelection() bool {
+	if len(hv.selected) == 0 {
+		return false
+	}
+	clear(hv.selected)
+	hv.message = ""
+	hv.reloadItems()
+	return true
+}
+
+// confirmDelete asks before carrying out a delete
+func (hv *HistoryViewer) confirmDelete(pending *bulkDelete) {
+	empty := len(pending.ids) == 0
+	if pending.all {
+		empty = hv.manager.Count() == 0
+	}
+	if empty {
+		hv.message = "Nothing to delete"
+		return
+	}
+	hv.message = ""
+	hv.confirm = pending
+}
+
+// confirmDeleteMatching asks before deleting every entry that matches the
+// current search and favorites filter
+func (hv *HistoryViewer) confirmDeleteMatching() {
+	if hv.query == "" && !hv.favorites {
+		hv.message = "Search (/) or filter (F) first to delete matching entries; c clears all history"
+		return
+	}
+
+	entries, _ := hv.manager.SearchPage(hv.query, hv.favorites, 0, hv.manager.Count())
+	ids := make([]string, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.ID
+	}
+
+	var filter []string
+	if hv.query != "" {
+		filter = append(filter, fmt.Sprintf("matching %q", hv.query))
+	}
+	if hv.favorites {
+		filter = append(filter, "among favorites")
+	}
+	hv.confirmDelete(&bulkDelete{
+		prompt: fmt.Sprintf("Delete all %d entries %s?", len(ids), strings.Join(filter, " ")),
+		ids:    ids,
+	})
+}
+
+// runBulkDelete carries out the confirmed bulk delete
+func (hv *HistoryViewer) runBulkDelete() {
+	pending := hv.confirm
+	hv.confirm = nil
+
+	if pending.all {
+		if err := hv.manager.Clear(); err != nil {
+			hv.message = fmt.Sprintf("Clear failed: %v", err)
+			return
+		}
+		clear(hv.selected)
+		hv.message = "History cleared"
+		hv.refresh()
+		return
+	}
+
+	removed, err := hv.manager.DeleteMany(pending.ids)
+	if err != nil {
+		hv.message = fmt.Sprintf("Delete failed: %v", err)
+		return
+	}
+	for _, id := range pending.ids {
+		delete(hv.selected, id)
+	}
+	hv.message = fmt.Sprintf("Deleted %d %s", removed, plural(removed, "entry", "entries"))
+	hv.refresh()
+}
+
+// toggleFavorite marks or unmarks the selected entry as a favorite,
+// keeping the cursor where it was
+func (hv *HistoryViewer) toggleFavorite() {
+	entry := hv.GetSelectedEntry()
+	if entry == nil {
+		return
+	}
+	favorite, err := hv.manager.ToggleFavorite(entry.ID)
+	if err != nil {
+		hv.message = fmt.Sprintf("Could not update favorite: %v", err)
+		return
+	}
+	hv.message = "Removed from favorites"
+	if favorite {
+		hv.message = "Added to favorites; kept however long history grows"
+	}
+
+	index := hv.list.Index()
+	hv.loadEntries()
+	for len(hv.list.Items()) <= index && len(hv.list.Items()) < hv.total {
+		hv.loadMore()
+	}
+	hv.list.Select(min(index, len(hv.list.Items())-1))
+}
+
+// showResponse opens the selected entry's stored response
+func (hv *HistoryViewer) showResponse() {
+	entry := hv.GetSelectedEntry()
+	if entry == nil {
+		return
+	}
+	if entry.Response == nil {
+		hv.message = "This entry was saved without a response"
+		return
+	}
+	hv.message = ""
+	hv.detail.Show(*entry)
+}
+
+// prettyStoredBody indents a stored JSON body so diffs line up by field. A
+// binary body becomes a hexdump, like in the response view, so it never
+// reaches the terminal raw.
+func prettyStoredBody(body string) string {
+	response := &api.Response{Headers: map[string]string{}, Body: body}
+	if response.IsBinary() {
+		return "Binary body: " + binaryDescription(response) + "\n\n" + binaryPreview(response)
+	}
+	if pretty, err := response.PrettyPrintJSON(); err == nil {
+		return pretty
+	}
+	return body
+}
+
 // GetSelectedEntry returns the currently selected history entry
 func (hv HistoryViewer) GetSelectedEntry() *history.HistoryEntry {
 	if selectedItem := hv.list.SelectedItem(); selectedItem != nil {
@@ -181,39 +579,148 @@ func (hv HistoryViewer) GetSelectedEntry() *history.HistoryEntry {
 	return nil
 }
 
+// IsCapturingInput returns whether the search input is accepting text, or
+// a dialog, a delete confirmation or the stored response is open
+func (hv HistoryViewer) IsCapturingInput() bool {
+	return hv.searching || hv.confirm != nil || hv.harDialog.IsVisible() || hv.exportHAR.IsVisible() || hv.detail.IsVisible()
+}
+
+// startHARImport parses a HAR file off the UI goroutine, streaming progress messages
+func (hv *HistoryViewer) startHARImport(filename string) tea.Cmd {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan tea.Msg)
+
+	hv.harCancel = cancel
+	hv.harCh = ch
+	hv.message = ""
+	hv.harProgress.Show("Importing HAR", 0)
+
+	go func() {
+		defer close(ch)
+		progress := collections.ProgressFunc(func(done, total int) {
+			select {
+			case ch <- HARImportProgressMsg{done: done, total: total}:
+			case <-ctx.Done():
+			}
+		})
+		entries, err := history.ImportHAR(ctx, filename, progress)
+		ch <- HARImportCompleteMsg{filename: filename, entries: entries, err: err}
+	}()
+
+	return waitForImport(ch)
+}
+
+// CancelImport cancels an in-flight HAR import
+func (hv *HistoryViewer) CancelImport() {
+	if hv.harCancel != nil {
+		hv.harCancel()
+	}
+}
+
+// finishHARImport clears import state once the import goroutine has finished
+func (hv *HistoryViewer) finishHARImport() {
+	if hv.harCancel != nil {
+		hv.harCancel()
+	}
+	hv.harCancel = nil
+	hv.harCh = nil
+	hv.harProgress.Hide()
+}
+
+// IsImporting returns whether a HAR import is currently running
+func (hv HistoryViewer) IsImporting() bool {
+	return hv.harCh != nil
+}
+
 // refresh reloads the history from the manager
 func (hv *HistoryViewer) refresh() {
 	hv.manager.Load() // Reload from file
-	hv.allEntries = hv.manager.GetEntries()
-	hv.resetList()
+	hv.loadEntries()
 }
 
 // resetList resets the list to show all entries
 func (hv *HistoryViewer) resetList() {
-	items := make([]list.Item, 0)
-	for _, entry := range hv.allEntries {
-		items = append(items, HistoryItem{entry: entry})
-	}
-	hv.list.SetItems(items)
+	hv.query = ""
+	hv.loadEntries()
 }
 
 // applySearch filters the list based on search input
 func (hv *HistoryViewer) applySearch() {
-	query := hv.searchInput.Value()
-	if query == "" {
-		hv.resetList()
+	hv.query = hv.searchInput.Value()
+	hv.loadEntries()
+}
+
+// loadEntries shows the first page of the entries matching the search
+func (hv *HistoryViewer) loadEntries() {
+	entries, total := hv.manager.SearchPage(hv.query, hv.favorites, 0, historyPageSize)
+	hv.total = total
+	hv.list.SetItems(hv.historyItems(nil, entries))
+	hv.updateTitle()
+}
+
+// loadMoreIfNeeded loads the next page once the cursor nears the end of
+// the entries loaded so far
+func (hv *HistoryViewer) loadMoreIfNeeded() {
+	items := hv.list.Items()
+	if len(items) >= hv.total || hv.list.Index() < len(items)-historyLoadAhead {
 		return
 	}
+	hv.loadMore()
+}
 
-	// Search through entries
-	filteredEntries := hv.manager.Search(query)
+// loadMore appends the next page of matching entries to the list
+func (hv *HistoryViewer) loadMore() {
+	items := hv.list.Items()
+	entries, total := hv.manager.SearchPage(hv.query, hv.favorites, len(items), historyPageSize)
+	hv.total = total
+	hv.list.SetItems(hv.historyItems(items, entries))
+	hv.updateTitle()
+}
+
+// historyItems appends entries to items as list items
+func (hv *HistoryViewer) historyItems(items []list.Item, entries []history.HistoryEntry) []list.Item {
+	for _, entry := range entries {
+		items = append(items, hv.historyItem(entry))
+	}
+	return items
+}
 
-	items := make([]list.Item, 0)
-	for _, entry := range filteredEntries {
-		items = append(items, HistoryItem{entry: entry})
+// historyItem wraps an entry for the list, with a checkbox while any
+// entries are selected
+func (hv *HistoryViewer) historyItem(entry history.HistoryEntry) HistoryItem {
+	item := HistoryItem{entry: entry}
+	if len(hv.selected) > 0 {
+		item.checkbox = "[ ] "
+		if hv.selected[entry.ID] {
+			item.checkbox = "[x] "
+		}
 	}
+	return item
+}
 
+// reloadItems redraws the loaded items after the selection changes
+func (hv *HistoryViewer) reloadItems() {
+	items := hv.list.Items()
+	for i, item := range items {
+		items[i] = hv.historyItem(item.(HistoryItem).entry)
+	}
 	hv.list.SetItems(items)
+	hv.updateTitle()
+}
+
+// updateTitle shows how many entries are loaded when not all of them are,
+// and whether only favorites are listed
+func (hv *HistoryViewer) updateTitle() {
+	hv.list.Title = "Request History"
+	if hv.favorites {
+		hv.list.Title = "Favorite Requests"
+	}
+	if loaded := len(hv.list.Items()); loaded < hv.total {
+		hv.list.Title += fmt.Sprintf(" (%d of %d loaded)", loaded, hv.total)
+	}
+	if len(hv.selected) > 0 {
+		hv.list.Title += fmt.Sprintf(" · %d selected", len(hv.selected))
+	}
 }
 
 // Resize updates the viewer size
@@ -222,6 +729,7 @@ func (hv *HistoryViewer) Resize(width, height int) {
 	hv.height = height
 	hv.list.SetSize(width-4, height-8)
 	hv.searchInput.Width = width - 10
+	hv.detail.Resize(width, height)
 }
 
 // SaveRequestDialog represents a dialog for saving requests
@@ -361,7 +869,7 @@ func (d SaveRequestDialog) View() string {
 	return lipgloss.Place(80, 20, lipgloss.Center, lipgloss.Center,
 		lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#7D56F4")).
+			BorderForeground(palette.Primary).
 			Padding(1).
 			Render(content))
 }
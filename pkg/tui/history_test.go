@@ -0,0 +1,59 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"onioncli/pkg/history"
+)
+
+func TestHistoryItemDescription(t *testing.T) {
+	entry := history.HistoryEntry{
+		Timestamp: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		Response:  &history.ResponseSnapshot{Status: "200 OK", DurationMS: 143, BodyHash: "9f86d081884c7d659a2feaa0c55ad015"},
+	}
+	want := "2024-05-01 10:00 · 200 OK · 143ms · 9f86d081884c"
+	if got := (HistoryItem{entry: entry}).Description(); got != want {
+		t.Errorf("Description() = %q, want %q", got, want)
+	}
+
+	entry.Response.BodyHash = ""
+	want = "2024-05-01 10:00 · 200 OK · 143ms"
+	if got := (HistoryItem{entry: entry}).Description(); got != want {
+		t.Errorf("Description() without a hash = %q, want %q", got, want)
+	}
+}
+
+func TestHistoryViewerImportHAR(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	manager, err := history.NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hv := NewHistoryViewer(manager, 80, 24)
+
+	// The import runs in the background, streaming progress until it completes
+	var cmd tea.Cmd
+	hv, cmd = hv.Update(ImportHARMsg{filename: filepath.Join("..", "history", "testdata", "session.har")})
+	if !hv.IsImporting() {
+		t.Fatal("Expected an import to be running")
+	}
+	for cmd != nil {
+		msg := cmd()
+		if _, ok := msg.(HARImportCompleteMsg); ok && manager.Count() != 0 {
+			t.Error("Expected entries to be added only once the UI handles completion")
+		}
+		hv, cmd = hv.Update(msg)
+	}
+
+	if hv.IsImporting() {
+		t.Error("Expected the import to have finished")
+	}
+	if manager.Count() != 3 || !strings.HasPrefix(hv.message, "Imported 3 requests") {
+		t.Errorf("Expected 3 imported requests, got %d (%q)", manager.Count(), hv.message)
+	}
+}
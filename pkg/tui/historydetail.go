@@ -0,0 +1,108 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"onioncli/pkg/history"
+)
+
+// HistoryDetail shows the response a history entry stored
+type HistoryDetail struct {
+	viewport viewport.Model
+	title    string
+	summary  string
+	visible  bool
+}
+
+// NewHistoryDetail creates a new stored-response box
+func NewHistoryDetail(width, height int) HistoryDetail {
+	vp := viewport.New(width-4, height-10)
+	vp.Style = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		Padding(0, 1)
+
+	return HistoryDetail{viewport: vp}
+}
+
+// Show displays entry's stored response, which must not be nil
+func (hd *HistoryDetail) Show(entry history.HistoryEntry) {
+	hd.title = fmt.Sprintf("%s %s", entry.Method, entry.URL)
+	hd.summary = responseSummary(entry.Response)
+
+	body := prettyStoredBody(entry.Response.Body)
+	switch {
+	case entry.Response.BodySize == 0 && entry.Response.BodyHash != "":
+		body = "(empty body)"
+	case body == "":
+		body = "(body not stored)"
+	}
+	hd.viewport.SetContent(body)
+	hd.viewport.GotoTop()
+	hd.visible = true
+}
+
+// Hide hides the box
+func (hd *HistoryDetail) Hide() {
+	hd.visible = false
+}
+
+// IsVisible returns whether the box is visible
+func (hd HistoryDetail) IsVisible() bool {
+	return hd.visible
+}
+
+// Resize fits the box to the window
+func (hd *HistoryDetail) Resize(width, height int) {
+	hd.viewport.Width = width - 4
+	hd.viewport.Height = height - 10
+}
+
+// Update scrolls the body; Esc or q close the box
+func (hd HistoryDetail) Update(msg tea.Msg) (HistoryDetail, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc", "q":
+			hd.Hide()
+			return hd, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	hd.viewport, cmd = hd.viewport.Update(msg)
+	return hd, cmd
+}
+
+// View renders the box
+func (hd HistoryDetail) View() string {
+	if !hd.visible {
+		return ""
+	}
+	return lipgloss.JoinVertical(lipgloss.Left,
+		titleStyle.Render("Stored Response"),
+		hd.title,
+		statusStyle.Render(hd.summary),
+		viewportView(hd.viewport, palette.Primary),
+		helpStyle.Render("↑/↓ to scroll, Esc to close"))
+}
+
+// responseSummary describes a stored response in one line, e.g.
+// "200 OK · 143ms · 2.0 KiB (first 1.0 KiB stored) · 2024-05-01 10:00".
+// The size is left out when no body was recorded, as for HAR imports.
+func responseSummary(resp *history.ResponseSnapshot) string {
+	parts := []string{resp.Status, (time.Duration(resp.DurationMS) * time.Millisecond).String()}
+	if resp.BodyHash != "" {
+		size := formatBytes(int64(resp.BodySize))
+		if resp.BodyTruncated && resp.Body != "" {
+			size = fmt.Sprintf("%s (first %s stored)", size, formatBytes(int64(len(resp.Body))))
+		}
+		parts = append(parts, size)
+	}
+	parts = append(parts, resp.Timestamp.Format("2006-01-02 15:04"))
+	return strings.Join(parts, " · ")
+}
@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"onioncli/pkg/history"
+)
+
+func TestResponseSummary(t *testing.T) {
+	received := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		resp history.ResponseSnapshot
+		want string
+	}{
+		{
+			"full body",
+			history.ResponseSnapshot{Status: "200 OK", DurationMS: 143, Body: "{}", BodySize: 2, BodyHash: "abc", Timestamp: received},
+			"200 OK · 143ms · 2 B · 2024-05-01 10:00",
+		},
+		{
+			"cut short",
+			history.ResponseSnapshot{Status: "200 OK", DurationMS: 1500, Body: string(make([]byte, 1024)), BodySize: 2048, BodyTruncated: true, BodyHash: "abc", Timestamp: received},
+			"200 OK · 1.5s · 2.0 KiB (first 1.0 KiB stored) · 2024-05-01 10:00",
+		},
+		{
+			"no body recorded",
+			history.ResponseSnapshot{Status: "404 Not Found", DurationMS: 12, Timestamp: received},
+			"404 Not Found · 12ms · 2024-05-01 10:00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := responseSummary(&tt.resp); got != tt.want {
+				t.Errorf("responseSummary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrettyStoredBody(t *testing.T) {
+	if got := prettyStoredBody(`{"a":1}`); got != "{\n  \"a\": 1\n}" {
+		t.Errorf("prettyStoredBody(JSON) = %q, want it indented", got)
+	}
+
+	png := "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x01"
+	got := prettyStoredBody(png)
+	if !strings.HasPrefix(got, "Binary body: image/png") || strings.Contains(got, "\x00") {
+		t.Errorf("prettyStoredBody(PNG) = %q, want a description and hexdump", got)
+	}
+}
@@ -0,0 +1,60 @@
+package tui
+
+import "onioncli/pkg/api"
+
+// htmlViewMode is how the response viewer shows an HTML body
+type htmlViewMode int
+
+const (
+	htmlHighlighted htmlViewMode = iota // markup with tags and attributes coloured
+	htmlText                            // the readable text, without markup
+	htmlRaw                             // markup as received
+)
+
+// String names the mode for the status line and the body heading
+func (mode htmlViewMode) String() string {
+	switch mode {
+	case htmlText:
+		return "text"
+	case htmlRaw:
+		return "raw"
+	}
+	return "highlighted"
+}
+
+// CycleHTMLView switches an HTML body between highlighted markup, extracted
+// text and raw markup. The choice is kept for later responses. Returns false
+// if the body is not HTML.
+func (rv *ResponseViewer) CycleHTMLView() bool {
+	if rv.response == nil || rv.response.IsBinary() || !rv.response.IsHTML() {
+		return false
+	}
+
+	rv.htmlView = (rv.htmlView + 1) % 3
+	rv.flat = false
+	rv.raw = false
+	rv.setContent(rv.formatResponse(rv.response))
+	rv.viewport.GotoTop()
+	return true
+}
+
+// HTMLView returns how HTML bodies are shown
+func (rv ResponseViewer) HTMLView() htmlViewMode {
+	return rv.htmlView
+}
+
+// IsHTMLText returns whether the body is shown as text extracted from HTML
+func (rv ResponseViewer) IsHTMLText() bool {
+	return rv.htmlView == htmlText && rv.response != nil && rv.response.IsHTML()
+}
+
+// formatHTML renders an HTML body in the selected view
+func (rv ResponseViewer) formatHTML(response *api.Response) string {
+	switch rv.htmlView {
+	case htmlText:
+		return response.HTMLText()
+	case htmlRaw:
+		return response.Body
+	}
+	return rv.highlightXML(response.Body)
+}
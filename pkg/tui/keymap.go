@@ -0,0 +1,338 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// keyAction is what a key does. Movement actions are passed on to the
+// response and the lists; the others run a command (see screenCommands).
+type keyAction int
+
+const (
+	actionNone keyAction = iota
+	actionUp
+	actionDown
+	actionLeft  // the previous page of a list
+	actionRight // the next page of a list
+	actionPrevPage
+	actionNextPage
+	actionTop
+	actionBottom
+	actionSearch
+	actionCommand // open the : command prompt
+
+	// Request builder
+	actionSend
+	actionRetry
+	actionDryRun
+	actionNewRequest
+	actionDiscardDraft
+	actionSaveHistory
+	actionSaveToCollection
+	actionCopyCurl
+	actionImportCurl
+	actionAuth
+	actionNegotiation
+	actionToggleScheme
+	actionShowVariables
+	actionInsertVariable
+	actionPreviewAll
+	actionSnippets
+	actionToggleBodyMode
+	actionFormatBody
+	actionDiffBody
+	actionJumpToField
+	actionNextField
+	actionPrevField
+	actionEnvCompare
+	actionErrorDetails
+
+	// Response
+	actionAutoRetry
+	actionBodyToRequest
+	actionToggleRepresentation
+	actionPin
+	actionUnpin
+	actionAudit
+	actionCopyResponseCurl
+	actionSaveBody
+	actionFlatten
+	actionHTMLView
+	actionRawExchange
+	actionMaskRaw
+	actionOpenExternal
+
+	// Anywhere
+	actionNewCircuit
+	actionCookies
+	actionGoHistory
+	actionGoCollections
+	actionGoEnvironments
+	actionSettings
+	actionHelp
+	actionPalette
+	actionQuit
+)
+
+// keyContext is where a table of screen actions applies
+type keyContext int
+
+const (
+	contextBuilder     keyContext = iota // the request builder, even while typing in a field
+	contextBuilderIdle                   // the request builder while no field is being typed in
+	contextResponse                      // the response view
+	contextScreens                       // every screen but the builder, while it isn't taking text
+)
+
+// Keymap binds keys, or two-key sequences written "g g", to the movement
+// actions, and keys to each context's screen actions. Keys it doesn't bind
+// keep the meaning each screen gives them.
+type Keymap struct {
+	bindings map[string]keyAction
+	prefixes map[string]bool // first keys of the sequences
+	actions  map[keyContext]map[string]keyAction
+	help     []shortcut // listed in the help overlay after the screen's own keys
+}
+
+// keymaps are the values offered for ui.keymap
+var keymaps = []string{"default", "vim"}
+
+// defaultBindings are the keys every keymap starts from
+var defaultBindings = map[string]keyAction{
+	"up":     actionUp,
+	"down":   actionDown,
+	"pgup":   actionPrevPage,
+	"pgdown": actionNextPage,
+	"home":   actionTop,
+	"end":    actionBottom,
+	"/":      actionSearch,
+}
+
+// vimBindings are added to the defaults by the vim keymap
+var vimBindings = map[string]keyAction{
+	"k":      actionUp,
+	"j":      actionDown,
+	"h":      actionLeft,
+	"l":      actionRight,
+	"ctrl+b": actionPrevPage,
+	"ctrl+f": actionNextPage,
+	"g g":    actionTop,
+	"G":      actionBottom,
+	":":      actionCommand,
+}
+
+// defaultActions are the screen actions every keymap starts from
+var defaultActions = map[keyContext]map[string]keyAction{
+	contextBuilder: {
+		"ctrl+enter": actionSend,
+		"ctrl+s":     actionSaveHistory,
+		"ctrl+p":     actionPalette,
+		"ctrl+x":     actionToggleScheme,
+		"ctrl+n":     actionNewCircuit,
+		"ctrl+f":     actionToggleBodyMode,
+		"ctrl+j":     actionFormatBody,
+		"ctrl+y":     actionSnippets,
+		"ctrl+r":     actionInsertVariable,
+		"ctrl+e":     actionPreviewAll,
+		"ctrl+o":     actionShowVariables,
+		"ctrl+l":     actionDiffBody,
+		"ctrl+g":     actionJumpToField,
+		"tab":        actionNextField,
+		"shift+tab":  actionPrevField,
+	},
+	contextBuilderIdle: {
+		"h": actionGoHistory,
+		"c": actionGoCollections,
+		"v": actionGoEnvironments,
+		",": actionSettings,
+		"i": actionImportCurl,
+		"C": actionCopyCurl,
+		"E": actionEnvCompare,
+		"a": actionAuth,
+		"s": actionSaveHistory,
+		"S": actionSaveToCollection,
+		"r": actionRetry,
+		"n": actionNewRequest,
+		"x": actionDiscardDraft,
+		"d": actionDryRun,
+		"K": actionCookies,
+		"e": actionErrorDetails,
+		"?": actionHelp,
+		"q": actionQuit,
+	},
+	contextResponse: {
+		"a": actionAutoRetry,
+		"b": actionBodyToRequest,
+		"t": actionToggleRepresentation,
+		"p": actionPin,
+		"u": actionUnpin,
+		"s": actionAudit,
+		"K": actionCookies,
+		"S": actionSaveToCollection,
+		"c": actionCopyResponseCurl,
+		"w": actionSaveBody,
+		"f": actionFlatten,
+		"h": actionHTMLView,
+		"v": actionRawExchange,
+		"m": actionMaskRaw,
+		"o": actionOpenExternal,
+	},
+	contextScreens: {
+		"?":      actionHelp,
+		"ctrl+p": actionPalette,
+		"q":      actionQuit,
+	},
+}
+
+// vimActions are added to the default screen actions by the vim keymap
+var vimActions = map[keyContext]map[string]keyAction{
+	contextBuilderIdle: {":": actionCommand},
+}
+
+// vimHelp describes the vim keymap's keys in the help overlay
+var vimHelp = []shortcut{
+	{"j/k", "Down / up in lists and the response"},
+	{"h/l", "Previous / next page of a list"},
+	{"Ctrl+B/Ctrl+F", "Page up / down"},
+	{"gg/G", "Jump to the top / bottom"},
+	{":", "Command: q, history, collections, env, settings, builder, help, or a line number"},
+}
+
+// newKeymap returns the named keymap, or the default one for a name it
+// doesn't know
+func newKeymap(name string) Keymap {
+	keymap := Keymap{
+		bindings: make(map[string]keyAction),
+		prefixes: make(map[string]bool),
+		actions:  make(map[keyContext]map[string]keyAction),
+	}
+	for key, action := range defaultBindings {
+		keymap.bindings[key] = action
+	}
+	keymap.addActions(defaultActions)
+	if name == "vim" {
+		keymap.help = vimHelp
+		for key, action := range vimBindings {
+			keymap.bindings[key] = action
+		}
+		keymap.addActions(vimActions)
+	}
+
+	for key := range keymap.bindings {
+		if first, _, ok := strings.Cut(key, " "); ok {
+			keymap.prefixes[first] = true
+		}
+	}
+	return keymap
+}
+
+// addActions binds the screen actions in tables, replacing earlier bindings
+// of the same keys
+func (k Keymap) addActions(tables map[keyContext]map[string]keyAction) {
+	for context, table := range tables {
+		if k.actions[context] == nil {
+			k.actions[context] = make(map[string]keyAction)
+		}
+		for key, action := range table {
+			k.actions[context][key] = action
+		}
+	}
+}
+
+// ScreenAction returns the screen action bound to key in context, or
+// actionNone
+func (k Keymap) ScreenAction(context keyContext, key string) keyAction {
+	return k.actions[context][key]
+}
+
+// Action returns the action for key, pressed after pending (the first key
+// of a sequence, or ""). prefix reports that key starts a sequence and
+// should be held for the next key. A pending key that doesn't complete a
+// sequence is dropped, as vim does.
+func (k Keymap) Action(pending, key string) (action keyAction, prefix bool) {
+	if pending != "" {
+		if action, ok := k.bindings[pending+" "+key]; ok {
+			return action, false
+		}
+	}
+	if k.prefixes[key] {
+		return actionNone, true
+	}
+	return k.bindings[key], false
+}
+
+// actionKeys are the keys the lists already understand for each action
+var actionKeys = map[keyAction]tea.KeyMsg{
+	actionUp:       {Type: tea.KeyUp},
+	actionDown:     {Type: tea.KeyDown},
+	actionLeft:     {Type: tea.KeyLeft},
+	actionRight:    {Type: tea.KeyRight},
+	actionPrevPage: {Type: tea.KeyPgUp},
+	actionNextPage: {Type: tea.KeyPgDown},
+	actionTop:      {Type: tea.KeyHome},
+	actionBottom:   {Type: tea.KeyEnd},
+	actionSearch:   {Type: tea.KeyRunes, Runes: []rune{'/'}},
+}
+
+// handleKeymap runs the movement action the keymap binds to msg on the
+// response and the lists. It reports false for keys it leaves to the screen.
+func (m Model) handleKeymap(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	pending := m.pendingKey
+	m.pendingKey = ""
+
+	action, prefix := m.keymap.Action(pending, msg.String())
+	if prefix {
+		m.pendingKey = msg.String()
+		return m, nil, true
+	}
+	if action == actionNone {
+		return m, nil, false
+	}
+	if action == actionCommand {
+		m, cmd := screenCommands[actionCommand].run(m)
+		return m, cmd, true
+	}
+
+	var cmd tea.Cmd
+	switch m.state {
+	case StateResponse:
+		if m.currentResponse == nil {
+			return m, nil, false
+		}
+		switch action {
+		case actionTop:
+			m.responseViewer.GotoTop()
+		case actionBottom:
+			m.responseViewer.GotoBottom()
+		case actionSearch:
+			cmd = m.responseViewer.StartSearch()
+		case actionLeft, actionRight:
+			return m, nil, false // h switches the HTML view here
+		default:
+			m.responseViewer, cmd = m.responseViewer.Update(actionKeys[action])
+		}
+	case StateHistory:
+		m.historyViewer, cmd = m.historyViewer.Update(actionKeys[action])
+	case StateCollections:
+		m.collectionsViewer, cmd = m.collectionsViewer.Update(actionKeys[action])
+	case StateEnvironments:
+		m.environmentsViewer, cmd = m.environmentsViewer.Update(actionKeys[action])
+	default:
+		return m, nil, false
+	}
+	return m, cmd, true
+}
+
+// runScreenAction runs the command for the screen action the keymap binds
+// to key in context. It reports false when nothing is bound there or the
+// command doesn't apply right now, leaving the key to the screen.
+func (m Model) runScreenAction(context keyContext, key string) (Model, tea.Cmd, bool) {
+	command, ok := screenCommands[m.keymap.ScreenAction(context, key)]
+	if !ok || (command.available != nil && !command.available(m)) {
+		return m, nil, false
+	}
+	m, cmd := command.run(m)
+	return m, cmd, true
+}
@@ -0,0 +1,119 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"onioncli/pkg/config"
+)
+
+func TestKeymapAction(t *testing.T) {
+	tests := []struct {
+		name       string
+		keymap     string
+		pending    string
+		key        string
+		want       keyAction
+		wantPrefix bool
+	}{
+		{"default arrow", "default", "", "down", actionDown, false},
+		{"default search", "default", "", "/", actionSearch, false},
+		{"default leaves j", "default", "", "j", actionNone, false},
+		{"default leaves g", "default", "", "g", actionNone, false},
+		{"default leaves colon", "default", "", ":", actionNone, false},
+		{"unknown keymap is default", "emacs", "", "j", actionNone, false},
+		{"vim j", "vim", "", "j", actionDown, false},
+		{"vim k", "vim", "", "k", actionUp, false},
+		{"vim h", "vim", "", "h", actionLeft, false},
+		{"vim keeps arrows", "vim", "", "up", actionUp, false},
+		{"vim g waits", "vim", "", "g", actionNone, true},
+		{"vim gg", "vim", "g", "g", actionTop, false},
+		{"vim G", "vim", "", "G", actionBottom, false},
+		{"vim g then j", "vim", "g", "j", actionDown, false},
+		{"vim g then unbound", "vim", "g", "x", actionNone, false},
+		{"vim colon", "vim", "", ":", actionCommand, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, prefix := newKeymap(tt.keymap).Action(tt.pending, tt.key)
+			if got != tt.want || prefix != tt.wantPrefix {
+				t.Errorf("Action(%q, %q) = %v, %v, want %v, %v", tt.pending, tt.key, got, prefix, tt.want, tt.wantPrefix)
+			}
+		})
+	}
+}
+
+func TestKeymapScreenAction(t *testing.T) {
+	tests := []struct {
+		name    string
+		keymap  string
+		context keyContext
+		key     string
+		want    keyAction
+	}{
+		{"builder key while typing", "default", contextBuilder, "ctrl+x", actionToggleScheme},
+		{"builder idle key", "default", contextBuilderIdle, "h", actionGoHistory},
+		{"same key on the response", "default", contextResponse, "h", actionHTMLView},
+		{"shared screen key", "default", contextScreens, "?", actionHelp},
+		{"unbound", "default", contextResponse, "z", actionNone},
+		{"default leaves colon in the builder", "default", contextBuilderIdle, ":", actionNone},
+		{"vim colon in the builder", "vim", contextBuilderIdle, ":", actionCommand},
+		{"vim keeps the builder keys", "vim", contextBuilderIdle, "d", actionDryRun},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newKeymap(tt.keymap).ScreenAction(tt.context, tt.key); got != tt.want {
+				t.Errorf("ScreenAction(%v, %q) = %v, want %v", tt.context, tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScreenActionsHaveCommands(t *testing.T) {
+	for _, keymap := range keymaps {
+		for context, table := range newKeymap(keymap).actions {
+			for key, action := range table {
+				if _, ok := screenCommands[action]; !ok {
+					t.Errorf("%s keymap binds %q in context %v to action %v, which has no command", keymap, key, context, action)
+				}
+			}
+		}
+	}
+}
+
+func TestBuilderKeysDispatch(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	configManager, err := config.NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	model, err := NewModel(configManager)
+	if err != nil {
+		t.Fatal(err)
+	}
+	press := func(m Model, key string) (Model, tea.Cmd) {
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		return updated.(Model), cmd
+	}
+
+	// Letters typed into the URL stay there rather than running shortcuts
+	m, cmd := press(*model, "h")
+	m, cmd = press(m, "q")
+	if m.state != StateRequestBuilder || m.urlInput.Value() != "hq" {
+		t.Errorf("Typing in the URL gave state %v and URL %q, want the builder and %q", m.state, m.urlInput.Value(), "hq")
+	}
+	if cmd != nil {
+		if _, quit := cmd().(tea.QuitMsg); quit {
+			t.Error("Typing q in the URL quit")
+		}
+	}
+
+	// Away from the fields the same letter runs its action
+	m = m.focusField(FocusMethod)
+	if m, _ = press(m, "h"); m.state != StateHistory {
+		t.Errorf("h from the method list gave state %v, want history", m.state)
+	}
+}
@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"onioncli/pkg/api"
+)
+
+// cancelRequest aborts the in-flight request and returns to the request builder
+func (m Model) cancelRequest() Model {
+	if m.requestCancel != nil {
+		m.requestCancel()
+		m.requestCancel = nil
+	}
+
+	m.loading = false
+	m.loadingSpinner.Hide()
+	m.state = StateRequestBuilder
+	m.statusIndicator.Show("Request cancelled", StatusWarning)
+	return m
+}
+
+// renderLoading renders the overlay shown while a request is in flight
+func (m Model) renderLoading() string {
+	labelStyle := lipgloss.NewStyle().Foreground(palette.Info).Bold(true)
+
+	var lines []string
+	lines = append(lines, titleStyle.Render("Request in progress"))
+
+	if req := m.currentRequest; req != nil {
+		lines = append(lines, fmt.Sprintf("%s %s", labelStyle.Render(req.Method), req.URL))
+		lines = append(lines, fmt.Sprintf("%s %d", labelStyle.Render("Headers:"), len(req.Headers)))
+		if req.BodyFile != "" {
+			lines = append(lines, fmt.Sprintf("%s %s (streamed)", labelStyle.Render("Body:"), req.BodyFile))
+		} else if req.Multipart != nil {
+			lines = append(lines, fmt.Sprintf("%s multipart form, %s (streamed)", labelStyle.Render("Body:"), formSummary(req.Multipart)))
+		} else if req.Body != "" {
+			lines = append(lines, fmt.Sprintf("%s %d bytes", labelStyle.Render("Body:"), len(req.Body)))
+		}
+		if req.Timeout > 0 {
+			lines = append(lines, fmt.Sprintf("%s %v", labelStyle.Render("Timeout:"), req.Timeout))
+		}
+		for _, fix := range m.urlFixes {
+			lines = append(lines, fmt.Sprintf("%s %s", labelStyle.Render("URL fixed:"), fix))
+		}
+		if api.IsOnionURL(req.URL) {
+			lines = append(lines, fmt.Sprintf("%s %s (%s)", labelStyle.Render("Via Tor:"),
+				m.client.GetTorProxy(), m.client.GetTorProxySource()))
+		}
+	}
+
+	elapsed := time.Since(m.requestStarted).Truncate(100 * time.Millisecond)
+	lines = append(lines, "")
+	lines = append(lines, m.loadingSpinner.View())
+	if m.uploadProgress.IsVisible() {
+		lines = append(lines, m.uploadProgress.View())
+	}
+	if m.retryNotice != "" {
+		lines = append(lines, fmt.Sprintf("%s %s", labelStyle.Render("Retry:"),
+			lipgloss.NewStyle().Foreground(palette.Warning).Render(m.retryNotice)))
+	}
+	lines = append(lines, fmt.Sprintf("%s %v", labelStyle.Render("Elapsed:"), elapsed))
+	lines = append(lines, "")
+	lines = append(lines, lipgloss.NewStyle().
+		Foreground(palette.Highlight).
+		Bold(true).
+		Render("Press Esc to cancel"))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(palette.Primary).
+		Padding(1, 2).
+		Render(content)
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, dialog)
+}
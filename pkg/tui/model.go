@@ -1,8 +1,12 @@
 package tui
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textarea"
@@ -11,6 +15,7 @@ import (
 
 	"onioncli/pkg/api"
 	"onioncli/pkg/collections"
+	"onioncli/pkg/config"
 	"onioncli/pkg/history"
 )
 
@@ -31,6 +36,7 @@ type FocusedField int
 
 const (
 	FocusURL FocusedField = iota
+	FocusQuery
 	FocusMethod
 	FocusHeaders
 	FocusBody
@@ -46,6 +52,7 @@ type Model struct {
 
 	// Request builder components
 	urlInput    textinput.Model
+	queryArea   textarea.Model
 	methodList  list.Model
 	headersArea textarea.Model
 	bodyArea    textarea.Model
@@ -53,6 +60,9 @@ type Model struct {
 	// API client
 	client *api.Client
 
+	// Configuration
+	configManager *config.Manager
+
 	// Authentication
 	authManager *api.AuthManager
 	authDialog  AuthDialog
@@ -62,6 +72,13 @@ type Model struct {
 	collectionsManager *collections.Manager
 	collectionsViewer  CollectionsViewer
 	environmentsViewer EnvironmentsViewer
+	variablesPanel     VariablesPanel
+	variablePicker     VariablePicker
+	snippetsMenu       SnippetsMenu
+
+	settingsView SettingsView
+	curlImport   CurlImportDialog
+	curlView     CurlView
 
 	// History manager
 	historyManager *history.Manager
@@ -72,6 +89,31 @@ type Model struct {
 	currentRequest  *api.Request
 	currentResponse *api.Response
 
+	// Response post-processing declared by the loaded collection request
+	postProcess []api.PostProcessStep
+
+	// The loaded collection request, whose tests run against each response
+	collectionRequest *collections.CollectionRequest
+
+	// Interactive redirects (http.interactive_redirects): each 3xx pauses
+	// with a follow prompt, and every hop of the chain is recorded
+	redirectPrompt bool
+	redirectPath   []redirectHop
+
+	// Offer to save a successful unsaved request (ui.prompt_save); declined
+	// request fingerprints are not offered again this session
+	savePrompt    bool
+	declinedSaves map[string]bool
+
+	// Body editor content at the time of the last send, for diffing
+	lastSentBody    string
+	hasLastSentBody bool
+	diffViewer      DiffViewer
+	previewCache    *previewCache
+	previewAll      bool // preview substitution in the headers and body too, not just the URL
+	envCompare      EnvCompareView
+	webSocket       WebSocketView
+
 	// Response viewer
 	responseViewer ResponseViewer
 
@@ -89,6 +131,89 @@ type Model struct {
 	statusMessage string
 	errorMessage  string
 	loading       bool
+
+	// In-flight request state; requestSeq discards results of cancelled requests
+	requestCancel  context.CancelFunc
+	requestStarted time.Time
+	requestSeq     int
+
+	// Durations of the last few requests, shown as a status bar sparkline
+	latencySamples []latencySample
+	latencyURL     string // the URL the samples were sent to
+
+	// Outcome of the last request, shown under the request builder
+	lastResult *resultSummary
+
+	// Fixes NormalizeURL applied to the last sent URL
+	urlFixes []string
+
+	// Unset ${VAR} references in the auth of the last sent request
+	authWarnings []string
+
+	// Problems with the clearnet host of the last sent request over Tor
+	hostWarnings []string
+
+	// Whether the body area holds multipart form fields rather than a raw body
+	bodyForm bool
+
+	// Unsaved builder content: the last seen draft, whether the user edited
+	// it since it was loaded, and the pending debounced save
+	lastDraft  collections.Draft
+	draftDirty bool
+	draftSeq   int
+
+	// Progress of an "@file" body being streamed
+	uploadProgress ProgressIndicator
+
+	// Latest retry of the in-flight request, e.g. "timeout, retrying (2/3)"
+	retryNotice string
+
+	// Checklist from the last dry run
+	dryRunReport DryRunReport
+
+	// Security findings for the current response's headers
+	securityAudit SecurityAuditView
+	cookieViewer  CookieViewer
+
+	// Dialog for adding the builder's request to a collection
+	collectionSave SaveToCollectionDialog
+
+	// Mutating request waiting for confirmation (ui.confirm_mutations), and
+	// whether the user chose not to be asked again this session
+	pendingSend          *preparedRequest
+	skipSendConfirmation bool
+
+	// Response kept on screen beside the next one for comparison
+	pinnedResponse *api.Response
+	pinnedViewer   ResponseViewer
+	pinnedLabel    string
+
+	// Accept/Accept-Encoding picker and the representation last asked for
+	negotiationMenu NegotiationMenu
+	representation  string
+
+	// Server-requested backoff after a 429 response
+	rateLimitedUntil time.Time
+	rateLimitSeq     int
+	autoRetry        bool
+	retryWarned      bool
+
+	// Field jump leader (ctrl+g) is waiting for a field key
+	awaitingFieldJump bool
+
+	// Keys that move through the response and the lists (ui.keymap); the
+	// first key of a sequence such as gg is held in pendingKey
+	keymap        Keymap
+	pendingKey    string
+	commandPrompt CommandPrompt
+
+	// Fuzzy-filtered list of actions (ctrl+p)
+	commandPalette CommandPalette
+
+	// Open request tabs; the active one's entry is stale, as its state is
+	// held in the builder and response fields above
+	tabs      []requestTab
+	activeTab int
 }
 
 // HTTPMethod represents an HTTP method for the list
@@ -100,16 +225,52 @@ func (m HTTPMethod) FilterValue() string { return m.name }
 func (m HTTPMethod) Title() string       { return m.name }
 func (m HTTPMethod) Description() string { return "" }
 
-// NewModel creates a new TUI model
-func NewModel() (*Model, error) {
+// NewClient builds the API client from the configuration. The TUI sends
+// with it.
+func NewClient(configManager *config.Manager) (*api.Client, error) {
+	return api.NewClient(NewClientConfig(configManager))
+}
+
+// NewClientConfig returns the API client settings the configuration asks
+// for, so "onioncli run" can override some for one run without changing
+// the configuration
+func NewClientConfig(configManager *config.Manager) *api.ClientConfig {
+	cfg := configManager.Get()
+	clientConfig := api.DefaultConfig()
+	clientConfig.TorEnabled = cfg.Tor.Enabled
+	clientConfig.TorProxy = configManager.GetTorProxyAddress()
+	clientConfig.Timeout = configManager.GetHTTPTimeout()
+	clientConfig.AutoDetectProxy = cfg.Tor.AutoDetect
+	clientConfig.MaxRetries = configManager.GetMaxRetries()
+	clientConfig.MaxRedirects = cfg.HTTP.MaxRedirects
+	clientConfig.InteractiveRedirects = cfg.HTTP.InteractiveRedirects
+	clientConfig.DisableRedirects = !cfg.HTTP.FollowRedirects
+	clientConfig.UseCookieJar = cfg.HTTP.UseCookieJar
+	clientConfig.InsecureSkipVerify = !cfg.HTTP.VerifySSL
+	clientConfig.UserAgent = cfg.HTTP.UserAgent
+	clientConfig.ControlPort = cfg.Tor.ControlPort
+	clientConfig.ControlPassword = cfg.Tor.ControlPassword
+	clientConfig.Pool = api.ConnectionPool{
+		KeepAlives:      cfg.Tor.KeepAlives,
+		MaxIdleConns:    cfg.Tor.MaxIdleConns,
+		MaxConnsPerHost: cfg.Tor.MaxConnsPerHost,
+	}
+	return clientConfig
+}
+
+// NewModel creates a new TUI model using the loaded configuration
+func NewModel(configManager *config.Manager) (*Model, error) {
+	applyTheme(configManager.Get().UI.Theme)
+
 	// Initialize API client
-	client, err := api.NewClient(nil)
+	client, err := NewClient(configManager)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create API client: %w", err)
 	}
 
-	// Initialize authentication manager
+	// Initialize authentication manager, fetching OAuth2 tokens over the same route
 	authManager := api.NewAuthManager()
+	authManager.SetClient(client)
 
 	// Initialize error analyzer
 	errorAnalyzer := api.NewErrorAnalyzer()
@@ -125,23 +286,20 @@ func NewModel() (*Model, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create history manager: %w", err)
 	}
+	historyManager.SetMaxEntries(configManager.Get().History.MaxEntries)
+	historyManager.SetMaxResponseBody(configManager.Get().History.MaxResponseBody)
 
 	// Initialize URL input
 	urlInput := textinput.New()
-	urlInput.Placeholder = "Enter .onion URL (e.g., http://3g2upl4pq6kufc4m.onion)"
+	urlInput.Placeholder = "Enter .onion URL (e.g., http://duckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzczad.onion)"
 	urlInput.Focus()
 	urlInput.CharLimit = 500
 	urlInput.Width = 80
 
 	// Initialize HTTP method list
-	methods := []list.Item{
-		HTTPMethod{name: "GET"},
-		HTTPMethod{name: "POST"},
-		HTTPMethod{name: "PUT"},
-		HTTPMethod{name: "DELETE"},
-		HTTPMethod{name: "PATCH"},
-		HTTPMethod{name: "HEAD"},
-		HTTPMethod{name: "OPTIONS"},
+	methods := make([]list.Item, len(api.Methods))
+	for i, method := range api.Methods {
+		methods[i] = HTTPMethod{name: method}
 	}
 
 	methodList := list.New(methods, list.NewDefaultDelegate(), 20, 8)
@@ -150,6 +308,12 @@ func NewModel() (*Model, error) {
 	methodList.SetFilteringEnabled(false)
 	methodList.SetShowHelp(false)
 
+	// Initialize query parameters textarea
+	queryArea := textarea.New()
+	queryArea.Placeholder = queryPlaceholder
+	queryArea.SetWidth(80)
+	queryArea.SetHeight(3)
+
 	// Initialize headers textarea
 	headersArea := textarea.New()
 	headersArea.Placeholder = "Headers (key: value format, one per line)\nUser-Agent: OnionCLI/1.0\nContent-Type: application/json"
@@ -158,7 +322,7 @@ func NewModel() (*Model, error) {
 
 	// Initialize body textarea
 	bodyArea := textarea.New()
-	bodyArea.Placeholder = "Request body (JSON, XML, or plain text)"
+	bodyArea.Placeholder = rawBodyPlaceholder
 	bodyArea.SetWidth(80)
 	bodyArea.SetHeight(5)
 
@@ -166,14 +330,16 @@ func NewModel() (*Model, error) {
 		state:              StateRequestBuilder,
 		focusedField:       FocusURL,
 		urlInput:           urlInput,
+		queryArea:          queryArea,
 		methodList:         methodList,
 		headersArea:        headersArea,
 		bodyArea:           bodyArea,
 		client:             client,
+		configManager:      configManager,
 		authManager:        authManager,
 		authDialog:         NewAuthDialog(80, 24),
 		collectionsManager: collectionsManager,
-		collectionsViewer:  NewCollectionsViewer(collectionsManager, 80, 24),
+		collectionsViewer:  NewCollectionsViewer(collectionsManager, client, 80, 24),
 		environmentsViewer: NewEnvironmentsViewer(collectionsManager, 80, 24),
 		historyManager:     historyManager,
 		historyViewer:      NewHistoryViewer(historyManager, 80, 24),
@@ -182,11 +348,65 @@ func NewModel() (*Model, error) {
 		errorAnalyzer:      errorAnalyzer,
 		errorViewer:        NewErrorViewer(80, 24),
 		errorAlert:         NewErrorAlert(),
+		diffViewer:         NewDiffViewer(80, 24),
+		previewCache:       &previewCache{entries: make(map[string]previewEntry)},
+		envCompare:         NewEnvCompareView(80, 24),
+		webSocket:          NewWebSocketView(80, 24),
 		loadingSpinner:     NewLoadingSpinner(),
+		uploadProgress:     NewProgressIndicator(),
+		dryRunReport:       NewDryRunReport(),
+		securityAudit:      NewSecurityAuditView(),
+		cookieViewer:       NewCookieViewer(),
+		collectionSave:     NewSaveToCollectionDialog(),
+		negotiationMenu:    NewNegotiationMenu(),
 		statusIndicator:    NewStatusIndicator(),
 		keyboardShortcuts:  NewKeyboardShortcuts(),
+		keymap:             newKeymap(configManager.Get().UI.Keymap),
+		commandPrompt:      NewCommandPrompt(),
+		commandPalette:     NewCommandPalette(),
+		variablesPanel:     NewVariablesPanel(collectionsManager),
+		variablePicker:     NewVariablePicker(collectionsManager),
+		snippetsMenu:       NewSnippetsMenu(collectionsManager),
+		settingsView:       NewSettingsView(),
+		curlImport:         NewCurlImportDialog(),
+		curlView:           NewCurlView(),
+		declinedSaves:      make(map[string]bool),
+		tabs:               make([]requestTab, 1),
+	}
+
+	model.keyboardShortcuts.SetKeymapHelp(model.keymap.help)
+
+	if configManager.Get().UI.Accessible {
+		model.responseViewer.SetAccessible(true)
+		model.errorViewer.SetAccessible(true)
+		model.errorAlert.SetAccessible(true)
+		model.statusIndicator.SetAccessible(true)
+		model.dryRunReport.SetAccessible(true)
+		model.securityAudit.SetAccessible(true)
+		model.cookieViewer.SetAccessible(true)
+		model.collectionSave.SetAccessible(true)
+		model.webSocket.SetAccessible(true)
+	}
+
+	// Pre-select the configured default method
+	if defaultMethod := configManager.Get().UI.DefaultMethod; !model.selectMethod(defaultMethod) && defaultMethod != "" {
+		model.statusMessage = fmt.Sprintf("Unknown default_method %q in config, using GET", defaultMethod)
+	}
+
+	if keymap := configManager.Get().UI.Keymap; keymap != "" && !slices.Contains(keymaps, keymap) {
+		model.statusMessage = fmt.Sprintf("Unknown keymap %q in config, using default", keymap)
 	}
 
+	if configManager.Get().UI.RestoreSession {
+		model.collectionsViewer.SetRememberSession(true)
+		model.restoreSession()
+	}
+	if err := model.loadEnvironmentAuth(collectionsManager.GetActiveEnvironment()); err != nil {
+		model.statusMessage = err.Error()
+	}
+	model.markDraftClean()
+	model.restoreDraft()
+
 	return model, nil
 }
 
@@ -195,8 +415,15 @@ func (m Model) Init() tea.Cmd {
 	return textinput.Blink
 }
 
-// Update handles messages and updates the model
+// Update handles messages and updates the model, then tracks edits to the
+// request builder's draft
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	updated, cmd := m.update(msg)
+	return updated.trackDraft(cmd)
+}
+
+// update handles messages and updates the model
+func (m Model) update(msg tea.Msg) (Model, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
 
@@ -204,68 +431,169 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		m.responseViewer.Resize(msg.Width, msg.Height)
+		m = m.resizeResponseViewers()
 		m.historyViewer.Resize(msg.Width, msg.Height)
 		m.collectionsViewer.Resize(msg.Width, msg.Height)
 		m.environmentsViewer.Resize(msg.Width, msg.Height)
 		m.authDialog.Resize(msg.Width, msg.Height)
 		m.errorViewer.Resize(msg.Width, msg.Height)
+		m.diffViewer.Resize(msg.Width, msg.Height)
+		m.envCompare.Resize(msg.Width, msg.Height)
+		m.webSocket.Resize(msg.Width, msg.Height)
 		return m, nil
+
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
 	case tea.KeyMsg:
-		// Handle global shortcuts first, but only if not typing in input fields
-		if m.state == StateRequestBuilder {
-			// Check if we're currently typing in an input field
-			isTypingInInput := (m.focusedField == FocusURL && m.urlInput.Focused()) ||
-				(m.focusedField == FocusHeaders && m.headersArea.Focused()) ||
-				(m.focusedField == FocusBody && m.bodyArea.Focused())
+		// The loading overlay only responds to cancel and quit
+		if m.loading {
+			switch msg.String() {
+			case "esc":
+				return m.cancelRequest(), nil
+			case "ctrl+c":
+				return m, tea.Quit
+			}
+			return m, nil
+		}
 
-			// Handle Enter/Ctrl+Enter for sending requests
-			if msg.String() == "ctrl+enter" ||
-				(msg.String() == "enter" && (m.focusedField == FocusURL || m.focusedField == FocusSubmit)) {
-				if !m.loading {
-					return m.sendRequest()
-				}
+		// Esc closes the shortcuts overlay before doing anything else
+		if m.keyboardShortcuts.IsVisible() && msg.String() == "esc" {
+			m.keyboardShortcuts.Hide()
+			return m, nil
+		}
+
+		// The command prompt takes all keys while open
+		if m.commandPrompt.IsVisible() {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
 			}
+			var command string
+			m.commandPrompt, command, cmd = m.commandPrompt.Update(msg)
+			if command != "" {
+				return m.runCommand(command)
+			}
+			return m, cmd
+		}
 
-			// Handle global shortcuts only if NOT typing in input fields
-			if !isTypingInInput {
-				switch msg.String() {
-				case "h":
-					m.state = StateHistory
-					return m, nil
-				case "c":
-					m.state = StateCollections
-					return m, nil
-				case "v":
-					m.state = StateEnvironments
-					return m, nil
-				case "a":
-					m.authDialog.Show()
-					return m, nil
-				case "s":
-					if m.currentRequest != nil {
-						m.saveDialog.Show()
-					}
-					return m, nil
-				case "r":
-					if m.currentRequest != nil && !m.loading {
-						m.statusIndicator.Show("Retrying request...", StatusLoading)
-						return m.sendRequest()
-					}
-				case "?":
-					m.keyboardShortcuts.Toggle()
-					return m, nil
-				case "e":
-					if m.errorAlert.IsVisible() {
-						diagnosticError := &api.DiagnosticError{
-							Type:        m.errorAlert.errorType,
-							Message:     m.errorAlert.message,
-							Suggestions: m.errorAlert.suggestions,
-						}
-						m.errorViewer.Show(diagnosticError)
-						return m, nil
-					}
-				}
+		// So does the command palette
+		if m.commandPalette.IsVisible() {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			var command *paletteCommand
+			m.commandPalette, command, cmd = m.commandPalette.Update(msg)
+			if command != nil {
+				return command.run(m)
+			}
+			return m, cmd
+		}
+
+		// The diff viewer captures all keys while open
+		if m.diffViewer.IsVisible() {
+			m.diffViewer, cmd = m.diffViewer.Update(msg)
+			return m, cmd
+		}
+
+		// So does the environment comparison
+		if m.envCompare.IsVisible() {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			m.envCompare, cmd = m.envCompare.Update(msg)
+			return m, cmd
+		}
+
+		// And the WebSocket view
+		if m.webSocket.IsVisible() {
+			return m.updateWebSocket(msg)
+		}
+
+		// The error viewer takes all keys while open
+		if m.errorViewer.IsVisible() {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			m.errorViewer, cmd = m.errorViewer.Update(msg)
+			return m, cmd
+		}
+
+		// The cURL command box takes all keys while open
+		if m.curlView.IsVisible() {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			m.curlView, cmd = m.curlView.Update(msg)
+			return m, cmd
+		}
+
+		// The send confirmation takes all keys while open
+		if m.pendingSend != nil {
+			return m.handleSendConfirmation(msg)
+		}
+
+		// Any key closes the security audit
+		if m.securityAudit.IsVisible() {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			m.securityAudit.Hide()
+			return m, nil
+		}
+
+		// The cookie viewer takes all keys while open
+		if m.cookieViewer.IsVisible() {
+			return m.updateCookieViewer(msg)
+		}
+
+		// So does the save-to-collection dialog
+		if m.collectionSave.IsVisible() {
+			return m.updateSaveToCollection(msg)
+		}
+
+		// The response body save prompt takes all keys while open
+		if m.state == StateResponse && m.responseViewer.IsSaving() {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			m.responseViewer, cmd = m.responseViewer.Update(msg)
+			return m, cmd
+		}
+
+		// So does the response search prompt
+		if m.state == StateResponse && m.responseViewer.IsSearching() {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			m.responseViewer, cmd = m.responseViewer.Update(msg)
+			return m, cmd
+		}
+
+		// The settings screen takes all keys while open
+		if m.state == StateSettings {
+			switch msg.String() {
+			case "ctrl+c":
+				return m, tea.Quit
+			case "esc":
+				// Drop the theme being previewed
+				applyTheme(m.configManager.Get().UI.Theme)
+				m.state = StateRequestBuilder
+				return m, nil
+			}
+			m.settingsView, cmd = m.settingsView.Update(msg)
+			return m, cmd
+		}
+
+		// The keymap's keys move through the response and the lists
+		if m.state != StateRequestBuilder && !m.isCapturingInput() {
+			if updated, cmd, ok := m.handleKeymap(msg); ok {
+				return updated, cmd
+			}
+		}
+
+		// Tab shortcuts work from the builder and the response view
+		if (m.state == StateRequestBuilder || m.state == StateResponse) && !m.tabKeysBlocked() {
+			if updated, ok := m.handleTabKey(msg.String()); ok {
+				return updated, nil
 			}
 		}
 
@@ -283,31 +611,149 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Batch(cmds...)
 		}
 
+		// Handle global shortcuts first, but only if not typing in input fields
+		if m.state == StateRequestBuilder {
+			// Any key closes the dry-run report
+			if m.dryRunReport.IsVisible() {
+				if msg.String() == "ctrl+c" {
+					return m, tea.Quit
+				}
+				m.dryRunReport.Hide()
+				return m, nil
+			}
+
+			// The negotiation menu takes all keys while open
+			if m.negotiationMenu.IsVisible() {
+				if msg.String() == "ctrl+c" {
+					return m, tea.Quit
+				}
+				var option *negotiationOption
+				m.negotiationMenu, option = m.negotiationMenu.Update(msg)
+				if option != nil {
+					m = m.applyNegotiation(*option)
+				}
+				return m, nil
+			}
+
+			// The snippets menu takes all keys while open
+			if m.snippetsMenu.IsVisible() {
+				if msg.String() == "ctrl+c" {
+					return m, tea.Quit
+				}
+				var snippet *collections.Snippet
+				m.snippetsMenu, snippet, cmd = m.snippetsMenu.Update(msg)
+				if snippet != nil {
+					m = m.insertSnippet(*snippet)
+				}
+				return m, cmd
+			}
+
+			// The variable picker takes all keys while open
+			if m.variablePicker.IsVisible() {
+				if msg.String() == "ctrl+c" {
+					return m, tea.Quit
+				}
+				var names []string
+				m.variablePicker, names = m.variablePicker.Update(msg)
+				return m.insertVariableReferences(names), nil
+			}
+
+			// The variables panel takes all keys while open
+			if m.variablesPanel.IsVisible() {
+				if msg.String() == "ctrl+c" {
+					return m, tea.Quit
+				}
+				m.variablesPanel, cmd = m.variablesPanel.Update(msg)
+				return m, cmd
+			}
+			// The cURL import dialog takes all keys while open
+			if m.curlImport.IsVisible() {
+				if msg.String() == "ctrl+c" {
+					return m, tea.Quit
+				}
+				m.curlImport, cmd = m.curlImport.Update(msg)
+				return m, cmd
+			}
+			// A cURL command pasted into the URL field is imported whole
+			if msg.Paste && m.focusedField == FocusURL && api.IsCurlCommand(string(msg.Runes)) {
+				return m.importCurl(string(msg.Runes)), nil
+			}
+			// Multi-line pastes into the headers editor are parsed as raw header blocks
+			if msg.Paste && m.focusedField == FocusHeaders && strings.Contains(string(msg.Runes), "\n") {
+				return m.pasteHeaderBlock(string(msg.Runes)), nil
+			}
+
+			// Handle the field jump leader and the key following it
+			if m.awaitingFieldJump {
+				m.awaitingFieldJump = false
+				m.statusMessage = ""
+				if field, ok := fieldJumpKeys[msg.String()]; ok {
+					return m.focusField(field), nil
+				}
+				return m, nil
+			}
+
+			// The keymap's builder keys work even while typing
+			if updated, cmd, ok := m.runScreenAction(contextBuilder, msg.String()); ok {
+				return updated, cmd
+			}
+
+			// Enter sends from the URL field and the submit button
+			if msg.String() == "enter" && (m.focusedField == FocusURL || m.focusedField == FocusSubmit) {
+				return m.sendGuarded()
+			}
+
+			// Check if we're currently typing in an input field
+			isTypingInInput := (m.focusedField == FocusURL && m.urlInput.Focused()) ||
+				(m.focusedField == FocusQuery && m.queryArea.Focused()) ||
+				(m.focusedField == FocusHeaders && m.headersArea.Focused()) ||
+				(m.focusedField == FocusBody && m.bodyArea.Focused())
+
+			// Handle the rest of the builder's keys only if NOT typing in input fields
+			if !isTypingInInput {
+				if updated, cmd, ok := m.runScreenAction(contextBuilderIdle, msg.String()); ok {
+					return updated, cmd
+				}
+			}
+		}
+
+		// The response view's keys, then those every other screen shares
+		if m.state == StateResponse {
+			if updated, cmd, ok := m.runScreenAction(contextResponse, msg.String()); ok {
+				return updated, cmd
+			}
+		}
+		if m.state != StateRequestBuilder && !m.isCapturingInput() {
+			if updated, cmd, ok := m.runScreenAction(contextScreens, msg.String()); ok {
+				return updated, cmd
+			}
+		}
+
 		// Handle remaining global shortcuts
 		switch msg.String() {
-		case "ctrl+c", "q":
+		case "ctrl+c":
 			return m, tea.Quit
 
-		case "ctrl+s":
-			// Quick save shortcut
-			if m.state == StateRequestBuilder && m.currentRequest != nil {
-				m.saveDialog.Show()
-				return m, nil
+		case "y", "n", "x":
+			if m.state == StateResponse && m.redirectPrompt && msg.String() != "x" {
+				return m.answerRedirectPrompt(msg.String() == "y")
 			}
-
-		case "tab":
-			if m.state == StateRequestBuilder {
-				return m.nextField(), nil
+			// The save prompt skips with x, leaving n to the response search
+			if m.state == StateResponse && m.savePrompt && msg.String() != "n" {
+				return m.answerSavePrompt(msg.String() == "y"), nil
+			}
+			if msg.String() == "n" && m.state == StateResponse && m.responseViewer.HasSearch() {
+				return m.nextResponseMatch(1), nil
 			}
 
-		case "shift+tab":
-			if m.state == StateRequestBuilder {
-				return m.prevField(), nil
+		case "N":
+			if m.state == StateResponse && m.responseViewer.HasSearch() {
+				return m.nextResponseMatch(-1), nil
 			}
 
 		case "enter":
 			// Handle enter key in other states (not request builder, which is handled above)
-			if m.state == StateHistory {
+			if m.state == StateHistory && !m.historyViewer.IsCapturingInput() {
 				if entry := m.historyViewer.GetSelectedEntry(); entry != nil {
 					m.loadFromHistory(entry)
 					m.state = StateRequestBuilder
@@ -316,18 +762,45 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		case "esc":
+			if m.state == StateResponse && m.responseViewer.ClearSearch() {
+				return m, nil
+			}
 			if m.state == StateResponse {
 				m.state = StateRequestBuilder
 				m.focusedField = FocusURL
 				m.urlInput.Focus()
 				return m, nil
 			} else if m.state == StateHistory {
+				if m.historyViewer.IsImporting() {
+					m.historyViewer.CancelImport()
+					return m, nil
+				}
+				if m.historyViewer.IsCapturingInput() {
+					break
+				}
+				if m.historyViewer.ClearSelection() {
+					return m, nil
+				}
 				m.state = StateRequestBuilder
 				return m, nil
 			} else if m.state == StateCollections {
+				if m.collectionsViewer.IsImporting() {
+					m.collectionsViewer.CancelImport()
+					return m, nil
+				}
+				if m.collectionsViewer.IsRunning() {
+					m.collectionsViewer.CancelRun()
+					return m, nil
+				}
+				if m.collectionsViewer.IsCapturingInput() || m.collectionsViewer.InSubView() {
+					break
+				}
 				m.state = StateRequestBuilder
 				return m, nil
 			} else if m.state == StateEnvironments {
+				if m.environmentsViewer.IsCapturingInput() || m.environmentsViewer.InSubView() {
+					break
+				}
 				m.state = StateRequestBuilder
 				return m, nil
 			}
@@ -336,9 +809,43 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+	case CurlCopiedMsg:
+		return m.handleCurlCopied(msg), nil
+
+	case OAuth2TokenMsg:
+		return m.handleOAuth2Token(msg)
+
+	case CurlImportMsg:
+		return m.loadCurlRequest(msg.request, msg.auth), nil
+
+	case SaveResponseMsg:
+		return m.saveResponseBody(msg)
+
+	case ResponseSavedMsg:
+		return m.handleResponseSaved(msg), nil
+
+	case SaveSettingsMsg:
+		return m.applySettings(msg.values)
+
+	case EnvCompareProgressMsg, EnvCompareCompleteMsg:
+		return m.handleEnvCompareMsg(msg)
+
+	case WebSocketConnectedMsg, WebSocketMessageMsg, WebSocketSentMsg:
+		return m.handleWebSocketMsg(msg)
+
+	case CompareRunsMsg:
+		m.diffViewer.Show(msg.title, msg.previous, msg.current)
+		return m, nil
+
 	case SaveRequestMsg:
 		if m.currentRequest != nil {
-			err := m.historyManager.Save(m.currentRequest, msg.GetName(), msg.GetDescription())
+			m.savePrompt = false
+			// Keep the response so later runs can be compared against it
+			response := m.currentResponse
+			if m.loading {
+				response = nil // still waiting for this request's response
+			}
+			err := m.historyManager.Save(m.currentRequest, response, msg.GetName(), msg.GetDescription())
 			if err != nil {
 				m.errorMessage = fmt.Sprintf("Failed to save request: %v", err)
 			} else {
@@ -348,10 +855,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.saveDialog.Hide()
 		return m, nil
 
+	case SaveToCollectionMsg:
+		return m.saveToCollection(msg), nil
+
 	case AuthConfiguredMsg:
 		m.authConfig = msg.config
-		m.statusMessage = fmt.Sprintf("✅ Authentication configured: %s", msg.config.Type)
+		m.statusMessage = fmt.Sprintf("✅ Authentication configured: %s", authSummary(m.authManager, msg.config))
 		m.errorMessage = ""
+		m = m.saveEnvironmentAuth(msg.config)
 		return m, nil
 
 	case AuthErrorMsg:
@@ -360,58 +871,144 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case LoadRequestMsg:
-		// Load request from collection
-		req := msg.request
-		m.urlInput.SetValue(req.URL)
-
-		// Set method
-		for i, item := range m.methodList.Items() {
-			if httpMethod, ok := item.(HTTPMethod); ok && httpMethod.name == req.Method {
-				m.methodList.Select(i)
-				break
-			}
-		}
+		m.loadCollectionRequest(msg.collection, msg.request)
+		m.statusMessage = fmt.Sprintf("✅ Loaded request: %s", msg.request.Name)
+		m.state = StateRequestBuilder
+		return m, nil
 
-		// Set headers
-		var headerLines []string
-		for key, value := range req.Headers {
-			headerLines = append(headerLines, fmt.Sprintf("%s: %s", key, value))
+	case ImportProgressMsg, ImportCompleteMsg, DataRunProgressMsg, DataRunCompleteMsg:
+		// Background job messages always go to the collections viewer, even if the user navigated away
+		m.collectionsViewer, cmd = m.collectionsViewer.Update(msg)
+		// A bundle brings an environment with it
+		if complete, ok := msg.(ImportCompleteMsg); ok && complete.collection != nil && complete.collection.ImportEnvironment != "" {
+			m.environmentsViewer.refreshEnvironments()
 		}
-		m.headersArea.SetValue(strings.Join(headerLines, "\n"))
+		return m, cmd
 
-		// Set body
-		m.bodyArea.SetValue(req.Body)
+	case HARImportProgressMsg, HARImportCompleteMsg:
+		// Likewise a HAR import finishes in the history viewer wherever the user is
+		m.historyViewer, cmd = m.historyViewer.Update(msg)
+		return m, cmd
 
-		m.statusMessage = fmt.Sprintf("✅ Loaded request: %s", req.Name)
-		m.state = StateRequestBuilder
+	case ExternalViewerClosedMsg:
+		if msg.err != nil {
+			m.statusIndicator.Show(fmt.Sprintf("External viewer failed: %v", msg.err), StatusError)
+		}
 		return m, nil
 
 	case EnvironmentChangedMsg:
-		// Environment changed
+		// Environment changed; its stored auth, if any, replaces the current one
+		if err := m.loadEnvironmentAuth(msg.environment); err != nil {
+			m.statusIndicator.Show(err.Error(), StatusWarning)
+		}
 		m.statusMessage = fmt.Sprintf("✅ Environment changed to: %s", msg.environment.Name)
+		if msg.environment.Auth != nil {
+			m.statusMessage += fmt.Sprintf(" (auth: %s)", authSummary(m.authManager, m.authConfig))
+		}
 		return m, nil
 
 	case RequestSuccessMsg:
+		if msg.seq != m.requestSeq || !m.loading {
+			return m, nil // Result of a cancelled request
+		}
+		m.requestCancel = nil
+
+		// Decode or decrypt the body as the collection request declares; on
+		// failure the raw body is shown
+		postProcessErr := msg.response.ApplyPostProcessors(m.collectionsManager.ResolvePostProcess(m.postProcess))
+
 		m.currentResponse = msg.response
+		tests := collections.RunTests(m.collectionRequest, msg.response)
+		m.responseViewer.SetTests(tests)
 		m.responseViewer.SetResponse(msg.response)
+		m.savePrompt = m.shouldPromptSave(msg.response)
+		m = m.recordLatency(m.currentRequest.URL, msg.response.Duration, msg.response.IsServerError())
+		m = m.recordResult(msg.response)
+		m = m.recordRedirectHop(msg.response)
 		m.loading = false
 		m.loadingSpinner.Hide()
 
 		// Show success status
 		statusMsg := fmt.Sprintf("Request completed successfully (%v)", msg.response.Duration)
+		if len(m.urlFixes) > 0 {
+			statusMsg += "; URL fixed: " + strings.Join(m.urlFixes, ", ")
+		}
 		m.statusIndicator.Show(statusMsg, StatusSuccess)
+		if warnings := m.sendWarnings(); warnings != "" {
+			m.statusIndicator.Show(statusMsg+"; "+warnings, StatusWarning)
+		}
+		if failed := len(tests) - countPassed(tests); failed > 0 {
+			m.statusIndicator.Show(fmt.Sprintf("%d of %d %s failed", failed, len(tests), plural(len(tests), "test", "tests")), StatusError)
+		}
+		if postProcessErr != nil {
+			m.statusIndicator.Show(fmt.Sprintf("Showing raw body: %v", postProcessErr), StatusWarning)
+		}
 		m.statusMessage = ""
 		m.errorMessage = ""
 		m.errorAlert.Hide()
 		m.state = StateResponse
+
+		// Optionally treat 4xx/5xx responses as errors and show diagnostics
+		if m.configManager.Get().HTTP.TreatNon2xxAsError && m.currentRequest != nil {
+			if diagnosticError := m.errorAnalyzer.AnalyzeResponse(msg.response, m.currentRequest.URL); diagnosticError != nil {
+				m.errorViewer.Show(diagnosticError)
+				m.statusIndicator.Show(fmt.Sprintf("Request failed: %s", msg.response.Status), StatusError)
+			}
+		}
+
+		// Honor the server's backoff on 429 responses
+		if msg.response.IsRateLimited() {
+			return m.startRateLimit(msg.response)
+		}
+		m.clearRateLimit()
+		return m, nil
+
+	case rateLimitTickMsg:
+		return m.handleRateLimitTick(msg)
+
+	case UploadProgressMsg:
+		return m.handleUploadProgress(msg)
+
+	case RetryProgressMsg:
+		return m.handleRetryProgress(msg)
+
+	case NewCircuitMsg:
+		return m.handleNewCircuit(msg)
+
+	case draftSaveMsg:
+		return m.handleDraftSave(msg)
+
+	case ExportReportMsg:
+		return m.exportReport(msg)
+
+	case ReportExportedMsg:
+		m.errorViewer.SetNotice(msg.notice)
+		return m, nil
+
+	case TorProbesMsg:
+		m.errorViewer.SetProbes(msg.diagnostic, msg.results)
 		return m, nil
 
 	case RequestErrorMsg:
+		if msg.seq != m.requestSeq || !m.loading || errors.Is(msg.err, context.Canceled) {
+			return m, nil // Result of a cancelled request
+		}
+		m.requestCancel = nil
 		m.loading = false
 		m.loadingSpinner.Hide()
+		m = m.recordLatency(msg.url, time.Since(m.requestStarted), true)
+
+		// The previous response doesn't belong to this request, so it must
+		// not be saved alongside it or summarised as its result
+		m.currentResponse = nil
+		m.lastResult = nil
 
 		// Analyze the error for better diagnostics
 		diagnosticError := m.errorAnalyzer.AnalyzeError(msg.err, msg.url)
+		if diagnosticError != nil && diagnosticError.Type == api.ErrorTypeTor {
+			diagnosticError.Suggestions = append(diagnosticError.Suggestions,
+				fmt.Sprintf("Tor proxy in use: %s (%s)", m.client.GetTorProxy(), m.client.GetTorProxySource()))
+		}
 		if diagnosticError != nil {
 			m.errorAlert.Show(diagnosticError)
 			m.errorMessage = diagnosticError.Message
@@ -420,6 +1017,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.errorMessage = fmt.Sprintf("Request failed: %v", msg.err)
 			m.statusIndicator.Show("Request failed", StatusError)
 		}
+		if warnings := m.sendWarnings(); warnings != "" {
+			m.statusIndicator.Show("Request failed; "+warnings, StatusError)
+		}
 
 		m.statusMessage = ""
 		return m, nil
@@ -459,6 +1059,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case FocusURL:
 			m.urlInput, cmd = m.urlInput.Update(msg)
 			cmds = append(cmds, cmd)
+		case FocusQuery:
+			m.queryArea, cmd = m.queryArea.Update(msg)
+			cmds = append(cmds, cmd)
 		case FocusMethod:
 			m.methodList, cmd = m.methodList.Update(msg)
 			cmds = append(cmds, cmd)
@@ -468,26 +1071,36 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case FocusBody:
 			m.bodyArea, cmd = m.bodyArea.Update(msg)
 			cmds = append(cmds, cmd)
+			m = m.formatPastedBody(msg)
 		}
 	}
 
 	return m, tea.Batch(cmds...)
 }
 
+// isCapturingInput reports whether the current screen has a text input focused,
+// in which case single-letter shortcuts must be passed through as typing
+func (m Model) isCapturingInput() bool {
+	switch m.state {
+	case StateHistory:
+		return m.historyViewer.IsCapturingInput()
+	case StateCollections:
+		return m.collectionsViewer.IsCapturingInput()
+	case StateEnvironments:
+		return m.environmentsViewer.IsCapturingInput()
+	}
+	return false
+}
+
 // loadFromHistory loads a request from history
 func (m *Model) loadFromHistory(entry *history.HistoryEntry) {
 	req := entry.ToRequest()
 
 	// Set URL
-	m.urlInput.SetValue(req.URL)
+	m.setRequestURL(req.URL)
 
 	// Set method
-	for i, item := range m.methodList.Items() {
-		if httpMethod, ok := item.(HTTPMethod); ok && httpMethod.name == req.Method {
-			m.methodList.Select(i)
-			break
-		}
-	}
+	m.selectMethod(req.Method)
 
 	// Set headers
 	var headerLines []string
@@ -497,114 +1110,260 @@ func (m *Model) loadFromHistory(entry *history.HistoryEntry) {
 	m.headersArea.SetValue(strings.Join(headerLines, "\n"))
 
 	// Set body
+	m.setBodyForm(false)
 	m.bodyArea.SetValue(req.Body)
+	m.clearLastSentBody()
+	m.postProcess = nil
+	m.collectionRequest = nil
+	m.markDraftClean()
 
 	m.statusMessage = fmt.Sprintf("✅ Loaded request: %s", entry.Name)
 }
 
-// nextField moves focus to the next field
-func (m Model) nextField() Model {
-	switch m.focusedField {
-	case FocusURL:
-		m.focusedField = FocusMethod
-		m.urlInput.Blur()
-	case FocusMethod:
-		m.focusedField = FocusHeaders
-		m.headersArea.Focus()
-	case FocusHeaders:
-		m.focusedField = FocusBody
-		m.headersArea.Blur()
-		m.bodyArea.Focus()
-	case FocusBody:
-		m.focusedField = FocusSubmit
-		m.bodyArea.Blur()
-	case FocusSubmit:
-		m.focusedField = FocusURL
-		m.urlInput.Focus()
+// restoreSession reopens the collection and request from the last launch
+func (m *Model) restoreSession() {
+	session, err := m.collectionsManager.LoadSession()
+	if err != nil {
+		m.statusMessage = fmt.Sprintf("Could not restore session: %v", err)
+		return
 	}
-	return m
+
+	collection, request := m.collectionsManager.ResolveSession(session)
+	if collection == nil {
+		return
+	}
+	m.collectionsViewer.RestoreSession(collection, request)
+	if request == nil {
+		m.statusMessage = fmt.Sprintf("Restored collection: %s", collection.Name)
+		return
+	}
+	m.loadCollectionRequest(collection, request)
+	m.statusMessage = fmt.Sprintf("Restored %s from %s", request.Name, collection.Name)
 }
 
-// prevField moves focus to the previous field
-func (m Model) prevField() Model {
-	switch m.focusedField {
+// loadCollectionRequest loads a collection request into the builder, with
+// the collection's default headers under its own
+func (m *Model) loadCollectionRequest(collection *collections.Collection, req *collections.CollectionRequest) {
+	m.setRequestURL(req.URL)
+	m.selectMethod(req.Method)
+
+	var headerLines []string
+	for key, value := range collection.HeadersFor(req) {
+		headerLines = append(headerLines, fmt.Sprintf("%s: %s", key, value))
+	}
+	m.headersArea.SetValue(strings.Join(headerLines, "\n"))
+
+	m.setBodyForm(false)
+	m.bodyArea.SetValue(req.Body)
+	m.clearLastSentBody()
+	m.postProcess = req.PostProcess
+	m.collectionRequest = req
+	m.markDraftClean()
+}
+
+// selectMethod selects the given HTTP method in the method list,
+// returning false if it is not in the list
+func (m *Model) selectMethod(method string) bool {
+	method = strings.ToUpper(method)
+	for i, item := range m.methodList.Items() {
+		if httpMethod, ok := item.(HTTPMethod); ok && httpMethod.name == method {
+			m.methodList.Select(i)
+			return true
+		}
+	}
+	return false
+}
+
+// defaultMethod returns the configured default HTTP method, falling back to GET
+func (m Model) defaultMethod() string {
+	if method := m.configManager.Get().UI.DefaultMethod; api.IsValidMethod(method) {
+		return strings.ToUpper(method)
+	}
+	return "GET"
+}
+
+// clearForm resets the request builder to a blank request using the default method
+func (m Model) clearForm() Model {
+	m.urlInput.SetValue("")
+	m.queryArea.SetValue("")
+	m.headersArea.SetValue("")
+	m.setBodyForm(false)
+	m.bodyArea.SetValue("")
+	m.selectMethod(m.defaultMethod())
+	m.clearLastSentBody()
+	m.postProcess = nil
+	m.collectionRequest = nil
+	m.representation = ""
+	m.errorMessage = ""
+	m.errorAlert.Hide()
+	m.statusMessage = "Form cleared"
+	return m.focusField(FocusURL)
+}
+
+// responseToRequest loads the current response body into the request builder
+// for a read-modify-write: JSON is pretty-printed, anything else is loaded
+// verbatim. The URL is kept and a GET becomes a PUT, with the method field
+// focused so it can be flipped to PATCH or POST.
+func (m Model) responseToRequest() Model {
+	if m.currentResponse == nil {
+		return m
+	}
+
+	if m.urlInput.Value() == "" && m.currentRequest != nil {
+		m.setRequestURL(m.currentRequest.URL)
+	}
+
+	body, err := m.currentResponse.PrettyPrintJSON()
+	isJSON := err == nil && m.currentResponse.FlattenJSON() != nil
+	if err != nil {
+		body = m.currentResponse.Body
+	}
+	m.setBodyForm(false)
+	m.bodyArea.SetValue(body)
+
+	headers, _ := api.ParseHeaderBlock(m.headersArea.Value())
+	hasContentType := false
+	for _, header := range headers {
+		if strings.EqualFold(header.Name, "Content-Type") {
+			hasContentType = true
+		}
+	}
+	if isJSON && !hasContentType {
+		lines := strings.TrimSpace(m.headersArea.Value())
+		if lines != "" {
+			lines += "\n"
+		}
+		m.headersArea.SetValue(lines + "Content-Type: application/json")
+	}
+
+	if m.currentRequest != nil && (m.currentRequest.Method == "GET" || m.currentRequest.Method == "HEAD") {
+		m.selectMethod("PUT")
+	}
+
+	m.state = StateRequestBuilder
+	m.errorMessage = ""
+	m.errorAlert.Hide()
+	m.statusMessage = "Response loaded as request body - pick PUT/PATCH/POST and edit"
+	return m.focusField(FocusMethod)
+}
+
+// clearLastSentBody forgets the last sent body, e.g. after loading a different request
+func (m *Model) clearLastSentBody() {
+	m.lastSentBody = ""
+	m.hasLastSentBody = false
+}
+
+// fieldJumpKeys maps the key pressed after the field-jump leader to a field
+var fieldJumpKeys = map[string]FocusedField{
+	"u": FocusURL,
+	"q": FocusQuery,
+	"m": FocusMethod,
+	"h": FocusHeaders,
+	"b": FocusBody,
+	"s": FocusSubmit,
+}
+
+// fieldCount is the number of focusable fields in the request builder
+const fieldCount = int(FocusSubmit) + 1
+
+// focusField moves focus directly to the given field. Leaving the URL field
+// moves any query typed into it to the query editor.
+func (m Model) focusField(field FocusedField) Model {
+	if m.focusedField == FocusURL && field != FocusURL {
+		m = m.moveQueryToEditor()
+	}
+	m.urlInput.Blur()
+	m.queryArea.Blur()
+	m.headersArea.Blur()
+	m.bodyArea.Blur()
+
+	m.focusedField = field
+	switch field {
 	case FocusURL:
-		m.focusedField = FocusSubmit
-		m.urlInput.Blur()
-	case FocusMethod:
-		m.focusedField = FocusURL
 		m.urlInput.Focus()
+	case FocusQuery:
+		m.queryArea.Focus()
 	case FocusHeaders:
-		m.focusedField = FocusMethod
-		m.headersArea.Blur()
-	case FocusBody:
-		m.focusedField = FocusHeaders
-		m.bodyArea.Blur()
 		m.headersArea.Focus()
-	case FocusSubmit:
-		m.focusedField = FocusBody
+	case FocusBody:
 		m.bodyArea.Focus()
 	}
 	return m
 }
 
-// sendRequest creates and sends the HTTP request
+// nextField moves focus to the next field
+func (m Model) nextField() Model {
+	return m.focusField(FocusedField((int(m.focusedField) + 1) % fieldCount))
+}
+
+// prevField moves focus to the previous field
+func (m Model) prevField() Model {
+	return m.focusField(FocusedField((int(m.focusedField) + fieldCount - 1) % fieldCount))
+}
+
+// sendRequest creates and sends the HTTP request, or connects to a
+// WebSocket URL
 func (m Model) sendRequest() (Model, tea.Cmd) {
-	// Get selected method
-	selectedItem := m.methodList.SelectedItem()
-	if selectedItem == nil {
-		m.errorMessage = "Please select an HTTP method"
+	prepared := m.prepareRequest()
+	if prepared.err != nil {
+		m.errorMessage = prepared.err.Error()
 		return m, nil
 	}
-	method := selectedItem.(HTTPMethod).name
-
-	// Get URL
-	url := strings.TrimSpace(m.urlInput.Value())
-	if url == "" {
-		m.errorMessage = "Please enter a URL"
-		return m, nil
+	if api.IsWebSocketURL(prepared.request.URL) {
+		return m.connectWebSocket(prepared)
 	}
-
-	// Create request
-	req := api.NewRequest(method, url)
-
-	// Parse headers
-	headersText := strings.TrimSpace(m.headersArea.Value())
-	if headersText != "" {
-		headers := m.parseHeaders(headersText)
-		for key, value := range headers {
-			req.SetHeader(key, value)
-		}
+	if prepared.needsToken {
+		return m.fetchOAuth2Token()
 	}
 
-	// Set body
-	body := strings.TrimSpace(m.bodyArea.Value())
-	if body != "" {
-		req.SetBody(body)
+	if len(prepared.unresolved) > 0 || needsSendConfirmation(prepared.request.Method, m.configManager.Get().UI.ConfirmMutations, m.skipSendConfirmation) {
+		m.pendingSend = &prepared
+		return m, nil
 	}
+	return m.sendPrepared(prepared)
+}
 
-	// Process request with variable substitution
-	req = m.collectionsManager.ProcessRequest(req)
+// sendPrepared sends a request that passed the pre-send pipeline
+func (m Model) sendPrepared(prepared preparedRequest) (Model, tea.Cmd) {
+	m.lastSentBody = m.bodyArea.Value()
+	m.hasLastSentBody = true
+	m.redirectPath = nil
+	m, cmd := m.dispatchRequest(prepared.request, prepared.urlFixes)
+	m.authWarnings = prepared.authWarnings
+	m.hostWarnings = prepared.hostWarnings
+	return m, cmd
+}
 
-	// Apply authentication if configured
-	if m.authConfig != nil {
-		if err := m.authManager.ApplyAuth(req, m.authConfig); err != nil {
-			m.errorMessage = fmt.Sprintf("Authentication failed: %v", err)
-			return m, nil
-		}
+// sendWarnings joins the host and auth warnings of the last sent request
+// for the status line, or returns "" if it had none
+func (m Model) sendWarnings() string {
+	var parts []string
+	if len(m.hostWarnings) > 0 {
+		parts = append(parts, strings.Join(m.hostWarnings, "; "))
 	}
-
-	// Validate request
-	if err := req.Validate(); err != nil {
-		m.errorMessage = fmt.Sprintf("Request validation failed: %v", err)
-		return m, nil
+	if len(m.authWarnings) > 0 {
+		parts = append(parts, "auth: "+strings.Join(m.authWarnings, "; "))
 	}
+	return strings.Join(parts, "; ")
+}
 
+// dispatchRequest sends a prepared request in the background, showing the
+// loading overlay until it completes or is cancelled
+func (m Model) dispatchRequest(req *api.Request, urlFixes []string) (Model, tea.Cmd) {
 	m.currentRequest = req
+	ctx, cancel := context.WithCancel(context.Background())
+	m.requestCancel = cancel
+	m.requestStarted = time.Now()
+	m.requestSeq++
 	m.loading = true
 	m.errorMessage = ""
 	m.statusMessage = ""
 	m.errorAlert.Hide()
+	m.urlFixes = urlFixes
+	m.authWarnings = nil
+	m.hostWarnings = nil
+	m.uploadProgress.Hide()
+	m.retryNotice = ""
 
 	// Show loading spinner with appropriate message
 	var spinnerMessage string
@@ -614,13 +1373,20 @@ func (m Model) sendRequest() (Model, tea.Cmd) {
 		spinnerMessage = "Sending request..."
 	}
 
+	sendCmd := m.sendRequestCmd(ctx, m.requestSeq, req)
+	if req.BodyFile != "" || req.Multipart != nil || m.client.RetriesEnabled() {
+		sendCmd = m.sendStreamingRequestCmd(ctx, m.requestSeq, req)
+	}
+
 	return m, tea.Batch(
 		m.loadingSpinner.Show(spinnerMessage),
-		m.sendRequestCmd(req),
+		sendCmd,
 	)
 }
 
-// parseHeaders parses headers from textarea input
+// parseHeaders parses headers from textarea input. It takes any "name: value"
+// line as typed, {{var}} names and indented lines included; the stricter
+// api.ParseHeaderBlock is only for blocks pasted into the editor.
 func (m Model) parseHeaders(headersText string) map[string]string {
 	headers := make(map[string]string)
 	lines := strings.Split(headersText, "\n")
@@ -630,6 +1396,9 @@ func (m Model) parseHeaders(headersText string) map[string]string {
 		if line == "" {
 			continue
 		}
+		if _, ok := timeoutDirectiveValue(line); ok {
+			continue
+		}
 
 		parts := strings.SplitN(line, ":", 2)
 		if len(parts) == 2 {
@@ -645,23 +1414,25 @@ func (m Model) parseHeaders(headersText string) map[string]string {
 }
 
 // sendRequestCmd returns a command to send the HTTP request
-func (m Model) sendRequestCmd(req *api.Request) tea.Cmd {
+func (m Model) sendRequestCmd(ctx context.Context, seq int, req *api.Request) tea.Cmd {
 	return func() tea.Msg {
-		resp, err := m.client.Send(req)
+		resp, err := m.client.SendContext(ctx, req)
 		if err != nil {
-			return RequestErrorMsg{err: err, url: req.URL}
+			return RequestErrorMsg{err: err, url: req.URL, seq: seq}
 		}
-		return RequestSuccessMsg{response: resp}
+		return RequestSuccessMsg{response: resp, seq: seq}
 	}
 }
 
 // RequestSuccessMsg represents a successful request
 type RequestSuccessMsg struct {
 	response *api.Response
+	seq      int
 }
 
 // RequestErrorMsg represents a failed request
 type RequestErrorMsg struct {
 	err error
 	url string
+	seq int
 }
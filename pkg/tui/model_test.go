@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"onioncli/pkg/config"
+)
+
+func TestNewClientUsesConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	configManager, err := config.NewManager()
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	cfg := configManager.Get()
+	cfg.Tor.AutoDetect = false // keep the configured proxy even though nothing listens on it
+	configManager.UpdateTorSettings(true, "10.0.0.2", 9150, cfg.Tor.Timeout)
+	configManager.UpdateHTTPSettings(45, true, 10, true, "OnionCLI/1.0")
+
+	client, err := NewClient(configManager)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if got := client.GetTorProxy(); got != "10.0.0.2:9150" {
+		t.Errorf("GetTorProxy() = %q, want %q", got, "10.0.0.2:9150")
+	}
+	if got := client.GetTimeout(); got != 45*time.Second {
+		t.Errorf("GetTimeout() = %v, want %v", got, 45*time.Second)
+	}
+	if !client.IsTorEnabled() {
+		t.Errorf("IsTorEnabled() = false, want true")
+	}
+
+	configManager.UpdateTorSettings(false, "10.0.0.2", 9150, cfg.Tor.Timeout)
+	client, err = NewClient(configManager)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if client.IsTorEnabled() {
+		t.Errorf("IsTorEnabled() = true with tor.enabled off, want false")
+	}
+}
+
+func TestParseHeadersIsLenient(t *testing.T) {
+	text := "X-{{name}}: {{value}}\n  Indented: kept\nAccept: text/html\naccept: application/json\n@timeout: 90s\nno colon"
+	want := map[string]string{
+		"X-{{name}}": "{{value}}",
+		"Indented":   "kept",
+		"Accept":     "text/html",
+		"accept":     "application/json",
+	}
+	if got := (Model{}).parseHeaders(text); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseHeaders() = %v, want %v", got, want)
+	}
+}
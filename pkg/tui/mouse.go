@@ -0,0 +1,80 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// handleMouse focuses the request builder field that was clicked and
+// scrolls the response and the lists with the wheel. Everything stays
+// reachable from the keyboard; the mouse is ignored while anything covers
+// the current screen.
+func (m Model) handleMouse(msg tea.MouseMsg) (Model, tea.Cmd) {
+	if m.View() != m.renderCurrentState() {
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	switch m.state {
+	case StateRequestBuilder:
+		if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+			return m, nil
+		}
+		if field, ok := m.builderFieldAt(msg.Y); ok {
+			m = m.focusField(field)
+		}
+	case StateResponse:
+		if tea.MouseEvent(msg).IsWheel() {
+			m.responseViewer, cmd = m.responseViewer.Update(msg)
+		}
+	case StateHistory, StateCollections, StateEnvironments:
+		key, ok := wheelKey(msg)
+		if !ok || m.isCapturingInput() {
+			return m, nil
+		}
+		switch m.state {
+		case StateHistory:
+			m.historyViewer, cmd = m.historyViewer.Update(key)
+		case StateCollections:
+			m.collectionsViewer, cmd = m.collectionsViewer.Update(key)
+		case StateEnvironments:
+			m.environmentsViewer, cmd = m.environmentsViewer.Update(key)
+		}
+	}
+	return m, cmd
+}
+
+// wheelKey turns a wheel turn into the arrow key that moves through a list
+// the same way
+func wheelKey(msg tea.MouseMsg) (tea.KeyMsg, bool) {
+	if msg.Action != tea.MouseActionPress {
+		return tea.KeyMsg{}, false
+	}
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		return tea.KeyMsg{Type: tea.KeyUp}, true
+	case tea.MouseButtonWheelDown:
+		return tea.KeyMsg{Type: tea.KeyDown}, true
+	}
+	return tea.KeyMsg{}, false
+}
+
+// builderFieldAt returns the request builder field drawn on screen row y,
+// if any
+func (m Model) builderFieldAt(y int) (FocusedField, bool) {
+	// A view taller than the terminal shows only its last lines
+	y += max(lipgloss.Height(m.renderCurrentState())-m.height, 0)
+	if tabBar := m.renderTabBar(); tabBar != "" {
+		y -= lipgloss.Height(tabBar)
+	}
+
+	top := 0
+	for _, section := range m.builderSections() {
+		height := lipgloss.Height(section.view)
+		if y >= top && y < top+height {
+			return section.field, section.isField
+		}
+		top += height
+	}
+	return 0, false
+}
@@ -0,0 +1,33 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestWheelKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		msg    tea.MouseMsg
+		want   tea.KeyType
+		wantOK bool
+	}{
+		{"wheel up", tea.MouseMsg{Action: tea.MouseActionPress, Button: tea.MouseButtonWheelUp}, tea.KeyUp, true},
+		{"wheel down", tea.MouseMsg{Action: tea.MouseActionPress, Button: tea.MouseButtonWheelDown}, tea.KeyDown, true},
+		{"left click", tea.MouseMsg{Action: tea.MouseActionPress, Button: tea.MouseButtonLeft}, 0, false},
+		{"release", tea.MouseMsg{Action: tea.MouseActionRelease, Button: tea.MouseButtonNone}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, ok := wheelKey(tt.msg)
+			if ok != tt.wantOK {
+				t.Fatalf("wheelKey() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && key.Type != tt.want {
+				t.Errorf("wheelKey() = %v, want %v", key.Type, tt.want)
+			}
+		})
+	}
+}
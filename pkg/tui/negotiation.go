@@ -0,0 +1,198 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"onioncli/pkg/api"
+)
+
+// negotiationOption sets (or, with an empty value, clears) a content
+// negotiation header
+type negotiationOption struct {
+	header string
+	value  string
+	label  string
+}
+
+// negotiationOptions are the common Accept and Accept-Encoding values. Clearing
+// a header lets the configured default header apply again.
+var negotiationOptions = []negotiationOption{
+	{"Accept", "application/json", "JSON"},
+	{"Accept", "application/xml", "XML"},
+	{"Accept", "text/html", "HTML"},
+	{"Accept", "text/plain", "Plain text"},
+	{"Accept", "*/*", "Anything"},
+	{"Accept", "", "Default from config"},
+	{"Accept-Encoding", "gzip", "gzip"},
+	{"Accept-Encoding", "deflate", "deflate"},
+	{"Accept-Encoding", "identity", "Uncompressed"},
+	{"Accept-Encoding", "", "Default"},
+}
+
+// NegotiationMenu picks Accept and Accept-Encoding values for the request
+type NegotiationMenu struct {
+	cursor  int
+	current map[string]string // header values in the editor when shown
+	visible bool
+}
+
+// NewNegotiationMenu creates a new content negotiation menu
+func NewNegotiationMenu() NegotiationMenu {
+	return NegotiationMenu{visible: false}
+}
+
+// Show shows the menu, marking the values the request currently sends
+func (nm *NegotiationMenu) Show(headers map[string]string) {
+	nm.current = headers
+	nm.visible = true
+}
+
+// Hide hides the menu
+func (nm *NegotiationMenu) Hide() {
+	nm.visible = false
+	nm.current = nil
+}
+
+// Update moves the selection. It returns the chosen option once Enter is pressed.
+func (nm NegotiationMenu) Update(msg tea.Msg) (NegotiationMenu, *negotiationOption) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "up", "k":
+			if nm.cursor > 0 {
+				nm.cursor--
+			}
+		case "down", "j":
+			if nm.cursor < len(negotiationOptions)-1 {
+				nm.cursor++
+			}
+		case "enter":
+			option := negotiationOptions[nm.cursor]
+			nm.Hide()
+			return nm, &option
+		case "esc":
+			nm.Hide()
+		}
+	}
+	return nm, nil
+}
+
+// View renders the menu
+func (nm NegotiationMenu) View() string {
+	if !nm.visible {
+		return ""
+	}
+
+	headerStyle := lipgloss.NewStyle().Foreground(palette.Success).Bold(true)
+	selectedStyle := lipgloss.NewStyle().Foreground(palette.Primary).Bold(true)
+
+	lines := []string{titleStyle.Render("Content Negotiation")}
+	section := ""
+	for i, option := range negotiationOptions {
+		if option.header != section {
+			section = option.header
+			lines = append(lines, headerStyle.Render(section+":"))
+		}
+
+		marker := " "
+		if value, ok := lookupHeader(nm.current, option.header); ok && value == option.value || !ok && option.value == "" {
+			marker = "✓"
+		}
+		line := fmt.Sprintf("%s %-20s %s", marker, option.label, option.value)
+		if i == nm.cursor {
+			line = selectedStyle.Render("▸" + line)
+		} else {
+			line = " " + line
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, helpStyle.Render("↑/↓ to select, Enter to apply, Esc to cancel"))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(palette.Primary).
+		Padding(1).
+		Render(strings.Join(lines, "\n"))
+}
+
+// IsVisible returns whether the menu is visible
+func (nm NegotiationMenu) IsVisible() bool {
+	return nm.visible
+}
+
+// lookupHeader finds a header value, ignoring case
+func lookupHeader(headers map[string]string, name string) (string, bool) {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// applyNegotiation writes the chosen header into the headers editor. Headers
+// in the editor take precedence over default headers, so clearing one lets
+// the configured default apply again.
+func (m Model) applyNegotiation(option negotiationOption) Model {
+	if option.value == "" {
+		m.headersArea.SetValue(removeHeaderField(m.headersArea.Value(), option.header))
+		m.statusIndicator.Show(fmt.Sprintf("%s: using default", option.header), StatusInfo)
+	} else {
+		m.headersArea.SetValue(mergeHeaderFields(m.headersArea.Value(),
+			[]api.HeaderField{{Name: option.header, Value: option.value}}))
+		m.statusIndicator.Show(fmt.Sprintf("%s: %s", option.header, option.value), StatusInfo)
+	}
+
+	if option.header == "Accept" {
+		m.representation = option.label
+		if option.value == "" {
+			m.representation = ""
+		}
+	}
+	return m
+}
+
+// toggleRepresentation asks for the other of JSON and XML and resends
+func (m Model) toggleRepresentation() (Model, tea.Cmd) {
+	accept, _ := lookupHeader(m.parseHeaders(m.headersArea.Value()), "Accept")
+	option := negotiationOptions[1] // XML
+	if strings.Contains(accept, "xml") {
+		option = negotiationOptions[0] // JSON
+	}
+
+	m = m.applyNegotiation(option)
+	m, ok := m.guardRateLimit()
+	if !ok {
+		return m, nil
+	}
+	return m.sendRequest()
+}
+
+// renderRepresentation notes which representation the response was requested
+// as and what the server returned
+func (m Model) renderRepresentation() string {
+	if m.representation == "" || m.currentResponse == nil {
+		return ""
+	}
+
+	other := "XML"
+	if m.representation == "XML" {
+		other = "JSON"
+	}
+	received, sniffed := m.currentResponse.ContentType()
+	switch {
+	case received == "":
+		received = "no Content-Type"
+	case sniffed:
+		received += " (sniffed)"
+	}
+
+	if m.configManager.Get().UI.Accessible {
+		return fmt.Sprintf("Requested %s, received %s. Press t to request %s.", m.representation, received, other)
+	}
+	return lipgloss.NewStyle().Foreground(palette.Info).Render(
+		fmt.Sprintf("⇄ Requested %s, received %s • t to request %s", m.representation, received, other))
+}
@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// OAuth2TokenMsg carries the outcome of fetching an OAuth2 token before a send
+type OAuth2TokenMsg struct {
+	seq int
+	err error
+}
+
+// fetchOAuth2Token requests the configured OAuth2 token in the background,
+// under the loading overlay so it can be cancelled, and sends the builder's
+// request once it arrives
+func (m Model) fetchOAuth2Token() (Model, tea.Cmd) {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.requestCancel = cancel
+	m.requestStarted = time.Now()
+	m.requestSeq++
+	m.loading = true
+	m.currentRequest = nil // the overlay would show the previous request
+	m.errorMessage = ""
+	m.statusMessage = ""
+	m.errorAlert.Hide()
+	m.urlFixes = nil
+	m.uploadProgress.Hide()
+	m.retryNotice = ""
+
+	seq, authManager, config := m.requestSeq, m.authManager, m.authConfig
+	return m, tea.Batch(
+		m.loadingSpinner.Show("Requesting OAuth2 token..."),
+		func() tea.Msg {
+			_, err := authManager.OAuth2Token(ctx, config)
+			return OAuth2TokenMsg{seq: seq, err: err}
+		},
+	)
+}
+
+// handleOAuth2Token sends the request now its token is cached, or reports
+// why the token endpoint refused it like any failed request
+func (m Model) handleOAuth2Token(msg OAuth2TokenMsg) (Model, tea.Cmd) {
+	if msg.seq != m.requestSeq || !m.loading || errors.Is(msg.err, context.Canceled) {
+		return m, nil // Result of a cancelled request
+	}
+	if msg.err != nil {
+		return m.update(RequestErrorMsg{
+			err: fmt.Errorf("could not get an OAuth2 token: %w", msg.err),
+			url: m.authConfig.TokenURL,
+			seq: msg.seq,
+		})
+	}
+
+	m.requestCancel = nil
+	m.loading = false
+	m.loadingSpinner.Hide()
+	return m.sendRequest()
+}
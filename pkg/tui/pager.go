@@ -0,0 +1,91 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"onioncli/pkg/api"
+)
+
+// contentTypeExtensions maps Content-Type substrings to file extensions so
+// external tools can pick the right syntax highlighting
+var contentTypeExtensions = []struct {
+	contentType string
+	extension   string
+}{
+	{"json", ".json"},
+	{"html", ".html"},
+	{"xml", ".xml"},
+	{"javascript", ".js"},
+	{"css", ".css"},
+	{"yaml", ".yaml"},
+	{"csv", ".csv"},
+	{"markdown", ".md"},
+}
+
+// ExternalViewerClosedMsg is sent when the external pager or editor exits
+type ExternalViewerClosedMsg struct {
+	err error
+}
+
+// contentTypeExtension returns the file extension for a Content-Type header value
+func contentTypeExtension(contentType string) string {
+	contentType = strings.ToLower(contentType)
+	for _, ct := range contentTypeExtensions {
+		if strings.Contains(contentType, ct.contentType) {
+			return ct.extension
+		}
+	}
+	return ".txt"
+}
+
+// externalViewerCommand returns the user's pager, falling back to their editor and then less
+func externalViewerCommand() []string {
+	for _, name := range []string{"PAGER", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(name)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{"less"}
+}
+
+// openInExternalViewer writes the response body to a temp file and opens it in
+// the user's pager or editor, suspending the TUI until it exits
+func openInExternalViewer(response *api.Response) tea.Cmd {
+	contentType, _ := response.ContentType()
+	return openTextInExternalViewer(response.Body, contentTypeExtension(contentType))
+}
+
+// openTextInExternalViewer writes content to a temp file with the given
+// extension and opens it in the user's pager or editor
+func openTextInExternalViewer(content, extension string) tea.Cmd {
+	file, err := os.CreateTemp("", "onioncli-response-*"+extension)
+	if err != nil {
+		return func() tea.Msg {
+			return ExternalViewerClosedMsg{err: fmt.Errorf("failed to create temp file: %w", err)}
+		}
+	}
+	path := file.Name()
+
+	_, err = file.WriteString(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return func() tea.Msg {
+			return ExternalViewerClosedMsg{err: fmt.Errorf("failed to write temp file: %w", err)}
+		}
+	}
+
+	args := externalViewerCommand()
+	cmd := exec.Command(args[0], append(args[1:], path)...)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		os.Remove(path)
+		return ExternalViewerClosedMsg{err: err}
+	})
+}
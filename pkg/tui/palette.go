@@ -0,0 +1,203 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// paletteRows is how many commands the palette lists at once
+const paletteRows = 12
+
+// paletteCommand is an action offered by the command palette
+type paletteCommand struct {
+	title string
+	keys  string // the shortcut that does the same, if any
+	run   func(m Model) (Model, tea.Cmd)
+}
+
+// CommandPalette lists the actions available on the current screen, narrowed
+// by a fuzzy filter as the user types
+type CommandPalette struct {
+	input    textinput.Model
+	commands []paletteCommand
+	matches  []paletteCommand
+	cursor   int
+	visible  bool
+}
+
+// NewCommandPalette creates a new command palette
+func NewCommandPalette() CommandPalette {
+	input := textinput.New()
+	input.Placeholder = "Type to filter commands"
+	input.CharLimit = 64
+	input.Width = 50
+
+	return CommandPalette{input: input}
+}
+
+// Show opens the palette with the given commands and an empty filter
+func (cp *CommandPalette) Show(commands []paletteCommand) tea.Cmd {
+	cp.commands = commands
+	cp.input.SetValue("")
+	cp.filter()
+	cp.visible = true
+	cp.input.Focus()
+	return textinput.Blink
+}
+
+// Hide closes the palette
+func (cp *CommandPalette) Hide() {
+	cp.visible = false
+	cp.commands = nil
+	cp.matches = nil
+	cp.input.Blur()
+}
+
+// Update handles palette key presses. It returns the chosen command once
+// Enter is pressed.
+func (cp CommandPalette) Update(msg tea.Msg) (CommandPalette, *paletteCommand, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "up", "ctrl+p":
+			if cp.cursor > 0 {
+				cp.cursor--
+			}
+			return cp, nil, nil
+		case "down", "ctrl+n":
+			if cp.cursor < len(cp.matches)-1 {
+				cp.cursor++
+			}
+			return cp, nil, nil
+		case "enter":
+			if len(cp.matches) == 0 {
+				return cp, nil, nil
+			}
+			command := cp.matches[cp.cursor]
+			cp.Hide()
+			return cp, &command, nil
+		case "esc":
+			cp.Hide()
+			return cp, nil, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	query := cp.input.Value()
+	cp.input, cmd = cp.input.Update(msg)
+	if cp.input.Value() != query {
+		cp.filter()
+	}
+	return cp, nil, cmd
+}
+
+// filter keeps the commands matching the typed text, best match first
+func (cp *CommandPalette) filter() {
+	cp.cursor = 0
+	query := cp.input.Value()
+	if strings.TrimSpace(query) == "" {
+		cp.matches = cp.commands
+		return
+	}
+
+	type match struct {
+		command paletteCommand
+		score   int
+	}
+	var matches []match
+	for _, command := range cp.commands {
+		if score, ok := fuzzyScore(query, command.title); ok {
+			matches = append(matches, match{command, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	cp.matches = make([]paletteCommand, len(matches))
+	for i, match := range matches {
+		cp.matches[i] = match.command
+	}
+}
+
+// fuzzyScore reports whether the letters of query appear in text in order,
+// ignoring case and spaces, and scores the match: letters that follow the
+// previous one or start a word count for more
+func fuzzyScore(query, text string) (int, bool) {
+	runes := []rune(strings.ToLower(text))
+	score := 0
+	pos := 0
+	last := -2 // index of the previous matched letter
+	for _, r := range strings.ToLower(query) {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		for pos < len(runes) && runes[pos] != r {
+			pos++
+		}
+		if pos == len(runes) {
+			return 0, false
+		}
+
+		switch {
+		case pos == last+1:
+			score += 3
+		case pos == 0 || !unicode.IsLetter(runes[pos-1]) && !unicode.IsDigit(runes[pos-1]):
+			score += 2
+		default:
+			score++
+		}
+		last = pos
+		pos++
+	}
+	return score, true
+}
+
+// View renders the palette
+func (cp CommandPalette) View() string {
+	if !cp.visible {
+		return ""
+	}
+
+	selectedStyle := lipgloss.NewStyle().Foreground(palette.Primary).Bold(true)
+	keyStyle := lipgloss.NewStyle().Foreground(palette.Muted)
+
+	lines := []string{titleStyle.Render("Command Palette"), cp.input.View(), ""}
+	if len(cp.matches) == 0 {
+		lines = append(lines, helpStyle.UnsetMargins().Render("No matching commands"))
+	}
+
+	// Keep the cursor in the window of rows shown
+	start := max(0, min(cp.cursor-paletteRows/2, len(cp.matches)-paletteRows))
+	end := min(start+paletteRows, len(cp.matches))
+	for i := start; i < end; i++ {
+		command := cp.matches[i]
+		title := fmt.Sprintf("%-44s", command.title)
+		if i == cp.cursor {
+			title = selectedStyle.Render("▸ " + title)
+		} else {
+			title = "  " + title
+		}
+		lines = append(lines, title+" "+keyStyle.Render(command.keys))
+	}
+	if len(cp.matches) > paletteRows {
+		lines = append(lines, keyStyle.Render(fmt.Sprintf("%d of %d", cp.cursor+1, len(cp.matches))))
+	}
+	lines = append(lines, helpStyle.Render("↑/↓ to select, Enter to run, Esc to cancel"))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(palette.Primary).
+		Padding(1).
+		Render(strings.Join(lines, "\n"))
+}
+
+// IsVisible returns whether the palette is open
+func (cp CommandPalette) IsVisible() bool {
+	return cp.visible
+}
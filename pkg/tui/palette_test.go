@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"testing"
+
+	"onioncli/pkg/config"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		query  string
+		text   string
+		wantOK bool
+	}{
+		{"send", "Send request", true},
+		{"SEND", "Send request", true},
+		{"sr", "Send request", true},
+		{"new circ", "New Tor circuit", true},
+		{"circuit new", "New Tor circuit", false},
+		{"xyz", "Send request", false},
+		{"", "Send request", true},
+	}
+
+	for _, tt := range tests {
+		if _, ok := fuzzyScore(tt.query, tt.text); ok != tt.wantOK {
+			t.Errorf("fuzzyScore(%q, %q) ok = %v, want %v", tt.query, tt.text, ok, tt.wantOK)
+		}
+	}
+}
+
+func TestCommandPaletteFilter(t *testing.T) {
+	cp := NewCommandPalette()
+	cp.Show([]paletteCommand{
+		{title: "Show cookies"},
+		{title: "Switch environment: staging"},
+		{title: "Send request"},
+	})
+	if len(cp.matches) != 3 {
+		t.Fatalf("empty filter matched %d commands, want 3", len(cp.matches))
+	}
+
+	// Letters in a row rank above letters scattered across words
+	cp.input.SetValue("sen")
+	cp.filter()
+	if len(cp.matches) != 2 {
+		t.Fatalf("matched %d commands, want 2", len(cp.matches))
+	}
+	if cp.matches[0].title != "Send request" {
+		t.Errorf("best match = %q, want %q", cp.matches[0].title, "Send request")
+	}
+}
+
+func TestPaletteCommandsShareScreenCommands(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	configManager, err := config.NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	model, err := NewModel(configManager)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := *model
+
+	titles := make(map[string]paletteCommand)
+	for _, command := range m.paletteCommands() {
+		titles[command.title] = command
+	}
+	// Commands that don't apply yet are left out
+	for _, title := range []string{"Save request to history", "Response: flatten JSON paths", "Response: unpin"} {
+		if _, ok := titles[title]; ok {
+			t.Errorf("%q offered before there is anything to act on", title)
+		}
+	}
+
+	// From another screen, a builder command switches back to the builder
+	m.state = StateHistory
+	m, _ = titles["Dry run the request"].run(m)
+	if m.state != StateRequestBuilder || !m.dryRunReport.IsVisible() {
+		t.Errorf("Dry run from the palette gave state %v, report visible %v", m.state, m.dryRunReport.IsVisible())
+	}
+}
@@ -0,0 +1,110 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"onioncli/pkg/collections"
+)
+
+// paletteCommands returns the commands offered by the palette on the
+// current screen, in the order listed before any filter is typed
+func (m Model) paletteCommands() []paletteCommand {
+	var commands []paletteCommand
+	// add offers action's command when it applies right now
+	add := func(title, keys string, action keyAction) {
+		command := screenCommands[action]
+		if command.available == nil || command.available(m) {
+			commands = append(commands, paletteCommand{title, keys, command.run})
+		}
+	}
+
+	add("Send request", "Ctrl+Enter", actionSend)
+	add("Dry run the request", "d", actionDryRun)
+	add("New request (clear the form)", "n", actionNewRequest)
+	add("Discard the saved draft", "x", actionDiscardDraft)
+	add("Save request to history", "s", actionSaveHistory)
+	add("Save request to a collection", "S", actionSaveToCollection)
+	add("Copy request as cURL", "C", actionCopyCurl)
+	add("Import a cURL command", "i", actionImportCurl)
+	add("Configure authentication", "a", actionAuth)
+	add("Pick Accept / Accept-Encoding", "", actionNegotiation)
+	add("Switch the URL between http and https", "Ctrl+X", actionToggleScheme)
+	add("Show environment variables", "Ctrl+O", actionShowVariables)
+	if m.hasLastSentBody {
+		add("Diff body against the last sent one", "Ctrl+L", actionDiffBody)
+	}
+	add("Send to every environment and compare", "E", actionEnvCompare)
+
+	add("New Tor circuit", "Ctrl+N", actionNewCircuit)
+	add("Show cookies", "K", actionCookies)
+	commands = append(commands, paletteCommand{"New tab", "Ctrl+T", func(m Model) (Model, tea.Cmd) {
+		return m.openTab(), nil
+	}})
+	if len(m.tabs) > 1 {
+		commands = append(commands, paletteCommand{"Close tab", "Ctrl+W", func(m Model) (Model, tea.Cmd) {
+			return m.closeTab(), nil
+		}})
+	}
+
+	if m.currentResponse != nil {
+		commands = append(commands, paletteCommand{"Response: search", "/", pressKey(StateResponse, "/")})
+	}
+	add("Response: flatten JSON paths", "f", actionFlatten)
+	add("Response: switch HTML view", "h", actionHTMLView)
+	add("Response: show raw exchange", "v", actionRawExchange)
+	add("Response: open in pager or editor", "o", actionOpenExternal)
+	add("Response: save body to a file", "w", actionSaveBody)
+	add("Response: use body as a new request", "b", actionBodyToRequest)
+	add("Response: audit security headers", "s", actionAudit)
+	add("Response: pin to compare with the next", "p", actionPin)
+	add("Response: unpin", "u", actionUnpin)
+
+	// Switching environment activates it straight away
+	for _, env := range m.collectionsManager.GetEnvironments() {
+		if env.IsActive {
+			continue
+		}
+		commands = append(commands, paletteCommand{"Switch environment: " + env.Name, "", switchEnvironment(env)})
+	}
+
+	// History's HAR actions run through their shortcut on the history screen
+	commands = append(commands,
+		paletteCommand{"Export history as HAR", "e", pressKey(StateHistory, "e")},
+		paletteCommand{"Import HAR into history", "i", pressKey(StateHistory, "i")},
+	)
+	add("Go to history", "h", actionGoHistory)
+	add("Go to collections", "c", actionGoCollections)
+	add("Go to environments", "v", actionGoEnvironments)
+	add("Open settings", ",", actionSettings)
+	add("Show keyboard shortcuts", "?", actionHelp)
+	add("Quit", "Ctrl+C", actionQuit)
+	if m.state != StateRequestBuilder {
+		commands = append(commands, paletteCommand{"Go to request builder", "Esc", goTo(StateRequestBuilder)})
+	}
+	return commands
+}
+
+// pressKey returns a command that switches to the given screen and presses
+// key there, so it behaves exactly like the shortcut
+func pressKey(state AppState, key string) func(m Model) (Model, tea.Cmd) {
+	return func(m Model) (Model, tea.Cmd) {
+		m.state = state
+		return m.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+	}
+}
+
+// switchEnvironment returns a command that makes env the active environment
+func switchEnvironment(env collections.Environment) func(m Model) (Model, tea.Cmd) {
+	return func(m Model) (Model, tea.Cmd) {
+		if err := m.collectionsManager.SetActiveEnvironment(env.ID); err != nil {
+			m.statusIndicator.Show(fmt.Sprintf("Could not switch environment: %v", err), StatusError)
+			return m, nil
+		}
+		m.environmentsViewer.refreshEnvironments()
+		return m, func() tea.Msg {
+			return EnvironmentChangedMsg{environment: &env}
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"onioncli/pkg/api"
+)
+
+// pinResponse stashes the current response so it stays on screen, side by
+// side with whatever is sent next
+func (m Model) pinResponse() Model {
+	if m.currentResponse == nil {
+		return m
+	}
+
+	m.pinnedResponse = m.currentResponse
+	m.pinnedLabel = "Pinned"
+	if m.currentRequest != nil {
+		m.pinnedLabel = fmt.Sprintf("Pinned: %s %s", m.currentRequest.Method, m.currentRequest.URL)
+	}
+
+	m.pinnedViewer = NewResponseViewer(m.width, m.height)
+	m.pinnedViewer.SetAccessible(m.configManager.Get().UI.Accessible)
+	m.pinnedViewer.SetResponse(m.pinnedResponse)
+	m = m.resizeResponseViewers()
+
+	m.statusIndicator.Show("Response pinned - the next response is shown alongside it (u to unpin)", StatusInfo)
+	return m
+}
+
+// unpinResponse drops the pinned response and returns to the single view
+func (m Model) unpinResponse() Model {
+	if m.pinnedResponse == nil {
+		return m
+	}
+
+	m.pinnedResponse = nil
+	m.pinnedLabel = ""
+	m = m.resizeResponseViewers()
+	m.statusIndicator.Show("Response unpinned", StatusInfo)
+	return m
+}
+
+// showingPinned reports whether the split view is shown: a response is pinned
+// and a different response has arrived since
+func (m Model) showingPinned() bool {
+	return m.pinnedResponse != nil && m.currentResponse != nil && m.currentResponse != m.pinnedResponse
+}
+
+// resizeResponseViewers gives each viewer half the width while a response is
+// pinned, and the current viewer the full width otherwise
+func (m Model) resizeResponseViewers() Model {
+	if m.pinnedResponse == nil {
+		m.responseViewer.Resize(m.width, m.height)
+		return m
+	}
+
+	half := m.width / 2
+	m.pinnedViewer.Resize(half, m.height)
+	m.responseViewer.Resize(m.width-half, m.height)
+	return m
+}
+
+// renderPinnedSplit renders the pinned response beside the current one
+func (m Model) renderPinnedSplit() string {
+	labelStyle := lipgloss.NewStyle().Foreground(palette.Accent).Bold(true)
+	half := m.width / 2
+
+	currentLabel := "Current"
+	if m.currentRequest != nil {
+		currentLabel = fmt.Sprintf("Current: %s %s", m.currentRequest.Method, m.currentRequest.URL)
+	}
+
+	pinned := lipgloss.JoinVertical(lipgloss.Left,
+		labelStyle.Render(truncateValue(m.pinnedLabel, max(half-2, 10))),
+		m.pinnedViewer.View())
+	current := lipgloss.JoinVertical(lipgloss.Left,
+		labelStyle.Render(truncateValue(currentLabel, max(m.width-half-2, 10))),
+		m.responseViewer.View())
+
+	if m.configManager.Get().UI.Accessible {
+		return lipgloss.JoinVertical(lipgloss.Left, pinned, "", current)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		lipgloss.NewStyle().Width(half).Render(pinned), current)
+}
+
+// pinnedSummary compares the pinned and current responses in one line
+func pinnedSummary(pinned, current *api.Response) string {
+	body := "bodies differ"
+	if current.SameBody(pinned) {
+		body = "bodies identical"
+	}
+	return fmt.Sprintf("Pinned %s (%v) vs current %s (%v), %s",
+		pinned.Status, pinned.Duration, current.Status, current.Duration, body)
+}
@@ -0,0 +1,120 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// maxPreviewLines bounds the header or body lines previewed at once
+const maxPreviewLines = 5
+
+// placeholderPattern matches a {{...}} placeholder left after substitution
+var placeholderPattern = regexp.MustCompile(`\{\{[^{}]+\}\}`)
+
+// previewCache remembers each field's last substitution preview. It is
+// shared by pointer between copies of the Model, so View only substitutes
+// again once a field's text or the active environment changes; that also
+// keeps values from functions such as {{uuid}} from changing every frame.
+type previewCache struct {
+	entries map[string]previewEntry
+}
+
+type previewEntry struct {
+	key   string
+	lines []string
+}
+
+// substitutionPreview returns the lines of a field's text that reference a
+// placeholder, resolved against the active environment
+func (m Model) substitutionPreview(field, text string) []string {
+	if !strings.Contains(text, "{{") {
+		return nil
+	}
+
+	key := text
+	if env := m.collectionsManager.GetActiveEnvironment(); env != nil {
+		key += "\x00" + env.ID + "\x00" + env.UpdatedAt.String()
+	}
+	if m.previewCache != nil {
+		if entry, ok := m.previewCache.entries[field]; ok && entry.key == key {
+			return entry.lines
+		}
+	}
+
+	lines := previewLines(text, m.maskedSubstitute())
+	if m.previewCache != nil {
+		m.previewCache.entries[field] = previewEntry{key: key, lines: lines}
+	}
+	return lines
+}
+
+// maskedSubstitute substitutes the active environment's variables, showing
+// secret ones as secretMask so the preview never puts them on screen. A
+// function given a secret, such as {{base64 "{{password}}"}}, previews its
+// result for the mask rather than the real value.
+func (m Model) maskedSubstitute() func(string) string {
+	env := m.collectionsManager.GetActiveEnvironment()
+	return func(line string) string {
+		if env != nil {
+			for _, key := range env.SecretKeys {
+				line = strings.ReplaceAll(line, "{{"+key+"}}", secretMask)
+			}
+		}
+		return m.collectionsManager.SubstituteVariables(line)
+	}
+}
+
+// previewLines substitutes each line of text that references a placeholder,
+// up to maxPreviewLines, noting how many more there are
+func previewLines(text string, substitute func(string) string) []string {
+	var lines []string
+	more := 0
+	for _, line := range strings.Split(text, "\n") {
+		if !strings.Contains(line, "{{") {
+			continue
+		}
+		if len(lines) == maxPreviewLines {
+			more++
+			continue
+		}
+		lines = append(lines, substitute(strings.TrimSpace(line)))
+	}
+	if more > 0 {
+		lines = append(lines, fmt.Sprintf("… %d more %s", more, plural(more, "line", "lines")))
+	}
+	return lines
+}
+
+// renderPreview renders preview lines under a field, highlighting any
+// placeholder still unresolved
+func renderPreview(lines []string) string {
+	rendered := make([]string, len(lines))
+	for i, line := range lines {
+		rendered[i] = previewStyle.Render("→ ") + highlightUnresolved(line)
+	}
+	return strings.Join(rendered, "\n")
+}
+
+// highlightUnresolved renders text dimmed, with unresolved placeholders in
+// a warning colour
+func highlightUnresolved(text string) string {
+	var b strings.Builder
+	last := 0
+	for _, match := range placeholderPattern.FindAllStringIndex(text, -1) {
+		b.WriteString(previewStyle.Render(text[last:match[0]]))
+		b.WriteString(unresolvedStyle.Render(text[match[0]:match[1]]))
+		last = match[1]
+	}
+	b.WriteString(previewStyle.Render(text[last:]))
+	return b.String()
+}
+
+// withPreview appends field's substitution preview to its rendered contents
+func (m Model) withPreview(contents, field, text string) string {
+	lines := m.substitutionPreview(field, text)
+	if len(lines) == 0 {
+		return contents
+	}
+	return contents + "\n" + renderPreview(lines)
+}
@@ -0,0 +1,30 @@
+package tui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPreviewLines(t *testing.T) {
+	substitute := func(s string) string { return strings.ReplaceAll(s, "{{host}}", "example.onion") }
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"no placeholders", "Accept: */*", nil},
+		{"only lines with placeholders", "Accept: */*\nHost: {{host}}\nX-Missing: {{missing}}", []string{"Host: example.onion", "X-Missing: {{missing}}"}},
+		{"capped", strings.Repeat("{{host}}\n", 7), []string{
+			"example.onion", "example.onion", "example.onion", "example.onion", "example.onion", "… 2 more lines",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := previewLines(tt.text, substitute); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("previewLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package tui
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+
+	"onioncli/pkg/api"
+)
+
+const queryPlaceholder = "Query parameters, one name=value per line; repeat a name to send a list"
+
+// queryParam is one name=value pair from the query editor, decoded
+type queryParam struct {
+	name  string
+	value string
+}
+
+// parseQueryParams reads the query editor: one "name=value" per line, with
+// values unencoded. A name may repeat to send a list. Blank lines and lines
+// starting with # are skipped; a line without "=" sends the name with an
+// empty value, as url.Values does.
+func parseQueryParams(text string) ([]queryParam, error) {
+	var params []queryParam
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, value, _ := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("line %d: expected name=value, got %q", i+1, line)
+		}
+		params = append(params, queryParam{name: name, value: strings.TrimSpace(value)})
+	}
+	return params, nil
+}
+
+// queryAuthConflict reports a param that an API key sent in the query would
+// replace, since auth sets its own param after the editor's are added
+func queryAuthConflict(params []queryParam, auth *api.AuthConfig) error {
+	if auth == nil || auth.Type != api.AuthAPIKey || auth.Location != "query" {
+		return nil
+	}
+	keyName := auth.KeyName
+	if keyName == "" {
+		keyName = "X-API-Key"
+	}
+	for _, p := range params {
+		if p.name == keyName {
+			return fmt.Errorf("%s is replaced by the API key", keyName)
+		}
+	}
+	return nil
+}
+
+// formatQueryParams renders params for the query editor
+func formatQueryParams(params []queryParam) string {
+	lines := make([]string, len(params))
+	for i, p := range params {
+		lines[i] = p.name + "=" + p.value
+	}
+	return strings.Join(lines, "\n")
+}
+
+// encodeQueryParams percent-encodes params into a query string, keeping
+// their order
+func encodeQueryParams(params []queryParam) string {
+	pairs := make([]string, len(params))
+	for i, p := range params {
+		pairs[i] = url.QueryEscape(p.name) + "=" + url.QueryEscape(p.value)
+	}
+	return strings.Join(pairs, "&")
+}
+
+// appendQuery adds an encoded query to rawURL, after any query it already
+// has and before its fragment
+func appendQuery(rawURL, query string) string {
+	if query == "" {
+		return rawURL
+	}
+	base, fragment, hasFragment := strings.Cut(rawURL, "#")
+	switch {
+	case !strings.Contains(base, "?"):
+		base += "?" + query
+	case strings.HasSuffix(base, "?"), strings.HasSuffix(base, "&"):
+		base += query
+	default:
+		base += "&" + query
+	}
+	if hasFragment {
+		base += "#" + fragment
+	}
+	return base
+}
+
+// splitQuery separates rawURL's query into decoded params, returning the URL
+// without it. ok is false when the query can't be shown one param per line
+// without changing it (a malformed escape, a decoded name holding "=", or a
+// value with a line break or surrounding spaces), so it is best left in the URL.
+func splitQuery(rawURL string) (base string, params []queryParam, ok bool) {
+	beforeFragment, fragment, hasFragment := strings.Cut(rawURL, "#")
+	base, query, hasQuery := strings.Cut(beforeFragment, "?")
+	if !hasQuery {
+		return rawURL, nil, true
+	}
+
+	for _, pair := range strings.Split(query, "&") {
+		if pair == "" {
+			continue
+		}
+		rawName, rawValue, _ := strings.Cut(pair, "=")
+		name, nameErr := url.QueryUnescape(rawName)
+		value, valueErr := url.QueryUnescape(rawValue)
+		if nameErr != nil || valueErr != nil || !editableQueryParam(name, value) {
+			return rawURL, nil, false
+		}
+		params = append(params, queryParam{name: name, value: value})
+	}
+
+	if hasFragment {
+		base += "#" + fragment
+	}
+	return base, params, true
+}
+
+// editableQueryParam reports whether a decoded param survives a round trip
+// through the query editor unchanged
+func editableQueryParam(name, value string) bool {
+	if name == "" || strings.TrimSpace(name) != name || strings.HasPrefix(name, "#") {
+		return false
+	}
+	if strings.TrimSpace(value) != value {
+		return false
+	}
+	return !strings.Contains(name, "=") && !strings.ContainsFunc(name+value, func(r rune) bool {
+		return r == '\n' || r == '\r' || !unicode.IsPrint(r)
+	})
+}
+
+// moveQueryToEditor moves the query typed into the URL field into the query
+// editor, after any params already there
+func (m Model) moveQueryToEditor() Model {
+	base, params, ok := splitQuery(m.urlInput.Value())
+	if !ok || len(params) == 0 {
+		return m
+	}
+
+	m.urlInput.SetValue(base)
+	existing := strings.TrimRight(m.queryArea.Value(), "\n")
+	if strings.TrimSpace(existing) == "" {
+		m.queryArea.SetValue(formatQueryParams(params))
+	} else {
+		m.queryArea.SetValue(existing + "\n" + formatQueryParams(params))
+	}
+	return m
+}
+
+// setRequestURL loads rawURL into the builder, its query into the editor
+func (m *Model) setRequestURL(rawURL string) {
+	m.urlInput.SetValue(rawURL)
+	m.queryArea.SetValue("")
+	*m = m.moveQueryToEditor()
+}
@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+
+	"onioncli/pkg/api"
+)
+
+func TestParseQueryParams(t *testing.T) {
+	text := "page=2\n\n# a comment\nids=1\nids=2\n q = a b&c \nflag"
+	want := []queryParam{
+		{name: "page", value: "2"},
+		{name: "ids", value: "1"},
+		{name: "ids", value: "2"},
+		{name: "q", value: "a b&c"},
+		{name: "flag", value: ""},
+	}
+
+	got, err := parseQueryParams(text)
+	if err != nil {
+		t.Fatalf("parseQueryParams() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseQueryParams() = %+v, want %+v", got, want)
+	}
+
+	if _, err := parseQueryParams("=value"); err == nil {
+		t.Error("Expected an error for a param without a name")
+	}
+}
+
+func TestEncodeQueryParams(t *testing.T) {
+	params := []queryParam{
+		{name: "ids", value: "1"},
+		{name: "ids", value: "2"},
+		{name: "q", value: "a b&c=d/é"},
+		{name: "token", value: "{{token}}"},
+	}
+	want := "ids=1&ids=2&q=a+b%26c%3Dd%2F%C3%A9&token=%7B%7Btoken%7D%7D"
+	if got := encodeQueryParams(params); got != want {
+		t.Errorf("encodeQueryParams() = %q, want %q", got, want)
+	}
+}
+
+func TestAppendQuery(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"http://example.onion/api", "http://example.onion/api?a=1"},
+		{"http://example.onion/api?b=2", "http://example.onion/api?b=2&a=1"},
+		{"http://example.onion/api?", "http://example.onion/api?a=1"},
+		{"http://example.onion/api#top", "http://example.onion/api?a=1#top"},
+		{"http://example.onion/api?b=2#top", "http://example.onion/api?b=2&a=1#top"},
+	}
+	for _, tt := range tests {
+		if got := appendQuery(tt.url, "a=1"); got != tt.want {
+			t.Errorf("appendQuery(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+	if got := appendQuery("http://example.onion/", ""); got != "http://example.onion/" {
+		t.Errorf("appendQuery() with no query = %q", got)
+	}
+}
+
+func TestSplitQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		wantBase string
+		want     []queryParam
+		wantOK   bool
+	}{
+		{
+			name:     "no query",
+			url:      "http://example.onion/api",
+			wantBase: "http://example.onion/api",
+			wantOK:   true,
+		},
+		{
+			name:     "repeated and encoded params",
+			url:      "http://example.onion/api?ids=1&ids=2&q=a+b%26c&flag#top",
+			wantBase: "http://example.onion/api#top",
+			want: []queryParam{
+				{name: "ids", value: "1"},
+				{name: "ids", value: "2"},
+				{name: "q", value: "a b&c"},
+				{name: "flag", value: ""},
+			},
+			wantOK: true,
+		},
+		{
+			name:     "variables",
+			url:      "{{base}}/search?q={{term}}",
+			wantBase: "{{base}}/search",
+			want:     []queryParam{{name: "q", value: "{{term}}"}},
+			wantOK:   true,
+		},
+		{name: "malformed escape", url: "http://example.onion/?q=100%", wantBase: "http://example.onion/?q=100%"},
+		{name: "encoded = in name", url: "http://example.onion/?a%3Db=c", wantBase: "http://example.onion/?a%3Db=c"},
+		{name: "encoded line break", url: "http://example.onion/?q=a%0Ab", wantBase: "http://example.onion/?q=a%0Ab"},
+		{name: "trailing space", url: "http://example.onion/?q=a+", wantBase: "http://example.onion/?q=a+"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, params, ok := splitQuery(tt.url)
+			if ok != tt.wantOK {
+				t.Fatalf("splitQuery() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if base != tt.wantBase {
+				t.Errorf("splitQuery() base = %q, want %q", base, tt.wantBase)
+			}
+			if !reflect.DeepEqual(params, tt.want) {
+				t.Errorf("splitQuery() params = %+v, want %+v", params, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitQueryRoundTrip(t *testing.T) {
+	rawURL := "http://example.onion/api?ids=1&ids=2&q=a+b%26c"
+	base, params, ok := splitQuery(rawURL)
+	if !ok {
+		t.Fatal("splitQuery() ok = false")
+	}
+	parsed, err := parseQueryParams(formatQueryParams(params))
+	if err != nil {
+		t.Fatalf("parseQueryParams() error = %v", err)
+	}
+	if got := appendQuery(base, encodeQueryParams(parsed)); got != rawURL {
+		t.Errorf("round trip = %q, want %q", got, rawURL)
+	}
+}
+
+func TestQueryAuthConflict(t *testing.T) {
+	params := []queryParam{{name: "api_key", value: "mine"}, {name: "page", value: "1"}}
+
+	inQuery := &api.AuthConfig{Type: api.AuthAPIKey, APIKey: "secret", KeyName: "api_key", Location: "query"}
+	if queryAuthConflict(params, inQuery) == nil {
+		t.Error("Expected a conflict with an API key sent as the same param")
+	}
+
+	inHeader := &api.AuthConfig{Type: api.AuthAPIKey, APIKey: "secret", KeyName: "api_key", Location: "header"}
+	if err := queryAuthConflict(params, inHeader); err != nil {
+		t.Errorf("Unexpected conflict with an API key header: %v", err)
+	}
+	if err := queryAuthConflict(params, nil); err != nil {
+		t.Errorf("Unexpected conflict without auth: %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"onioncli/pkg/api"
+)
+
+// defaultRateLimitBackoff is used when a 429 response has no usable Retry-After
+const defaultRateLimitBackoff = 30 * time.Second
+
+// rateLimitTickMsg drives the rate limit countdown; seq ties it to one 429
+type rateLimitTickMsg struct {
+	seq int
+}
+
+// rateLimitTick schedules the next countdown tick
+func rateLimitTick(seq int) tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return rateLimitTickMsg{seq: seq}
+	})
+}
+
+// startRateLimit records the server's backoff from a 429 response and starts the countdown
+func (m Model) startRateLimit(resp *api.Response) (Model, tea.Cmd) {
+	delay, ok := resp.RetryAfter()
+	if !ok {
+		delay = defaultRateLimitBackoff
+	}
+
+	m.rateLimitedUntil = time.Now().Add(delay)
+	m.rateLimitSeq++
+	m.autoRetry = false
+	m.retryWarned = false
+	m.statusIndicator.Show(m.rateLimitStatus(), StatusWarning)
+	return m, rateLimitTick(m.rateLimitSeq)
+}
+
+// clearRateLimit forgets any rate limit state
+func (m *Model) clearRateLimit() {
+	m.rateLimitedUntil = time.Time{}
+	m.autoRetry = false
+	m.retryWarned = false
+}
+
+// isRateLimited reports whether the server's requested backoff is still in effect
+func (m Model) isRateLimited() bool {
+	return time.Now().Before(m.rateLimitedUntil)
+}
+
+// rateLimitStatus describes the remaining backoff, e.g. "Rate limited, retry in 42s"
+func (m Model) rateLimitStatus() string {
+	remaining := time.Until(m.rateLimitedUntil).Round(time.Second)
+	status := fmt.Sprintf("Rate limited, retry in %v", remaining)
+	if m.autoRetry {
+		status += " (auto-retry on)"
+	}
+	return status
+}
+
+// handleRateLimitTick updates the countdown and fires the auto-retry when it ends
+func (m Model) handleRateLimitTick(msg rateLimitTickMsg) (Model, tea.Cmd) {
+	if msg.seq != m.rateLimitSeq || m.rateLimitedUntil.IsZero() {
+		return m, nil // Stale tick from an earlier 429
+	}
+
+	if m.isRateLimited() {
+		m.statusIndicator.Show(m.rateLimitStatus(), StatusWarning)
+		return m, rateLimitTick(msg.seq)
+	}
+
+	autoRetry := m.autoRetry
+	m.clearRateLimit()
+	if autoRetry && !m.loading {
+		m.statusIndicator.Show("Rate limit expired, retrying request...", StatusLoading)
+		return m.sendRequest()
+	}
+
+	m.statusIndicator.Show("Rate limit expired, ready to retry", StatusInfo)
+	return m, nil
+}
+
+// guardRateLimit discourages manual retries during a rate limit: the first
+// attempt only warns, a second attempt goes through anyway
+func (m Model) guardRateLimit() (Model, bool) {
+	if !m.isRateLimited() || m.retryWarned {
+		m.retryWarned = false
+		return m, true
+	}
+
+	m.retryWarned = true
+	m.statusIndicator.Show(m.rateLimitStatus()+" - send again to retry anyway", StatusWarning)
+	return m, false
+}
+
+// renderRateLimit renders the rate limit banner for the response view
+func (m Model) renderRateLimit() string {
+	if !m.isRateLimited() {
+		return ""
+	}
+
+	hint := "a to auto-retry when it expires"
+	if m.autoRetry {
+		hint = "a to cancel auto-retry"
+	}
+	if m.configManager.Get().UI.Accessible {
+		return fmt.Sprintf("Warning: %s. Press %s.", m.rateLimitStatus(), hint)
+	}
+	return lipgloss.NewStyle().
+		Foreground(palette.Warning).
+		Bold(true).
+		Render(fmt.Sprintf("⏳ %s • %s", m.rateLimitStatus(), hint))
+}
@@ -0,0 +1,100 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"onioncli/pkg/api"
+)
+
+// ToggleRaw switches between the response details and the raw exchange, in
+// the style of curl -v. Returns false if the response has no dump, as for
+// responses loaded from elsewhere than a request sent here.
+func (rv *ResponseViewer) ToggleRaw() bool {
+	if rv.response == nil || rv.response.RequestDump == "" {
+		return false
+	}
+
+	rv.flat = false
+	rv.raw = !rv.raw
+	rv.refreshRaw()
+	rv.viewport.GotoTop()
+	return true
+}
+
+// ToggleRawMasking shows or hides sensitive header values in the raw
+// exchange. Returns whether they are now masked.
+func (rv *ResponseViewer) ToggleRawMasking() bool {
+	rv.unmasked = !rv.unmasked
+	if rv.raw {
+		rv.refreshRaw()
+	}
+	return !rv.unmasked
+}
+
+// IsRaw returns whether the raw exchange is shown
+func (rv ResponseViewer) IsRaw() bool {
+	return rv.raw
+}
+
+// IsRawMasked returns whether the raw exchange masks sensitive header values
+func (rv ResponseViewer) IsRawMasked() bool {
+	return !rv.unmasked
+}
+
+// refreshRaw renders the details or raw exchange, whichever is selected
+func (rv *ResponseViewer) refreshRaw() {
+	if !rv.raw {
+		rv.setContent(rv.formatResponse(rv.response))
+		return
+	}
+
+	text := rawExchangeText(rv.response, !rv.unmasked)
+	if rv.plain {
+		rv.setContent(text)
+		return
+	}
+	title := "Raw Exchange (m to show secrets)"
+	if rv.unmasked {
+		title = "Raw Exchange (m to mask secrets)"
+	}
+	rv.setContent(lipgloss.NewStyle().
+		Foreground(palette.Primary).
+		Bold(true).
+		Render(title) + "\n\n" + text)
+}
+
+// rawExchangeText renders the request as sent and the response as received,
+// with "> " before request lines and "< " before response lines as curl -v
+// does. Bodies follow their headers unprefixed. The response body is shown
+// after any Content-Encoding was decoded, or described if it is binary.
+func rawExchangeText(resp *api.Response, mask bool) string {
+	requestDump, responseDump := resp.RequestDump, resp.ResponseDump
+	if mask {
+		requestDump = api.MaskDumpHeaders(requestDump)
+		responseDump = api.MaskDumpHeaders(responseDump)
+	}
+
+	var b strings.Builder
+	head, body, _ := strings.Cut(requestDump, "\n\n")
+	for _, line := range strings.Split(head, "\n") {
+		b.WriteString("> " + line + "\n")
+	}
+	b.WriteString(">\n")
+	if body != "" {
+		b.WriteString(body + "\n")
+	}
+
+	b.WriteString("\n")
+	for _, line := range strings.Split(responseDump, "\n") {
+		b.WriteString("< " + line + "\n")
+	}
+	b.WriteString("<\n")
+	if resp.IsBinary() {
+		b.WriteString("[" + binaryDescription(resp) + "] " + binaryHint)
+	} else {
+		b.WriteString(resp.Body)
+	}
+	return b.String()
+}
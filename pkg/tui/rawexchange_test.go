@@ -0,0 +1,42 @@
+package tui
+
+import (
+	"testing"
+
+	"onioncli/pkg/api"
+)
+
+func TestRawExchangeText(t *testing.T) {
+	resp := &api.Response{
+		RequestDump:  "POST /login HTTP/1.1\nHost: example.com\nAuthorization: Bearer abcdefghijkl\n\n{\"user\":\"a\"}",
+		ResponseDump: "HTTP/1.1 200 OK\nContent-Type: application/json",
+		Body:         "{\"ok\":true}",
+	}
+
+	want := "> POST /login HTTP/1.1\n" +
+		"> Host: example.com\n" +
+		"> Authorization: Bearer abc****jkl\n" +
+		">\n" +
+		"{\"user\":\"a\"}\n" +
+		"\n" +
+		"< HTTP/1.1 200 OK\n" +
+		"< Content-Type: application/json\n" +
+		"<\n" +
+		"{\"ok\":true}"
+	if got := rawExchangeText(resp, true); got != want {
+		t.Errorf("rawExchangeText() =\n%s\nwant\n%s", got, want)
+	}
+
+	resp.RequestDump = "GET / HTTP/1.1\nAuthorization: Bearer abcdefghijkl\n\n"
+	want = "> GET / HTTP/1.1\n" +
+		"> Authorization: Bearer abcdefghijkl\n" +
+		">\n" +
+		"\n" +
+		"< HTTP/1.1 200 OK\n" +
+		"< Content-Type: application/json\n" +
+		"<\n" +
+		"{\"ok\":true}"
+	if got := rawExchangeText(resp, false); got != want {
+		t.Errorf("unmasked rawExchangeText() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"onioncli/pkg/api"
+)
+
+// redirectHop is one response in an interactively followed redirect chain
+type redirectHop struct {
+	method string
+	url    string
+	status int
+}
+
+func (h redirectHop) String() string {
+	return fmt.Sprintf("%d %s %s", h.status, h.method, h.url)
+}
+
+// recordRedirectHop adds a response to the redirect path and, if it is a
+// redirect, pauses with a prompt to follow it. Only used when the client
+// returns redirects rather than following them.
+func (m Model) recordRedirectHop(response *api.Response) Model {
+	m.redirectPrompt = false
+	if !m.configManager.Get().HTTP.InteractiveRedirects || m.currentRequest == nil {
+		return m
+	}
+
+	m.redirectPath = append(m.redirectPath, redirectHop{
+		method: m.currentRequest.Method,
+		url:    m.currentRequest.URL,
+		status: response.StatusCode,
+	})
+	m.redirectPrompt = response.IsRedirect()
+	return m
+}
+
+// answerRedirectPrompt follows the paused redirect or stops the chain
+func (m Model) answerRedirectPrompt(follow bool) (Model, tea.Cmd) {
+	m.redirectPrompt = false
+	if !follow {
+		m.statusIndicator.Show(fmt.Sprintf("Stopped after %d %s", len(m.redirectPath), plural(len(m.redirectPath), "hop", "hops")), StatusInfo)
+		return m, nil
+	}
+
+	next, err := api.NextRedirect(m.currentRequest, m.currentResponse)
+	if err != nil || next == nil {
+		m.statusIndicator.Show(fmt.Sprintf("Cannot follow redirect: %v", err), StatusError)
+		return m, nil
+	}
+	// A 307 or 308 repeats a POST, which is confirmed like any other
+	if needsSendConfirmation(next.Method, m.configManager.Get().UI.ConfirmMutations, m.skipSendConfirmation) {
+		m.pendingSend = &preparedRequest{request: next, redirect: true}
+		return m, nil
+	}
+	return m.dispatchRequest(next, nil)
+}
+
+// renderRedirect renders the follow prompt and the chain followed so far
+func (m Model) renderRedirect() string {
+	if len(m.redirectPath) == 0 || (!m.redirectPrompt && len(m.redirectPath) < 2) {
+		return ""
+	}
+
+	hops := make([]string, len(m.redirectPath))
+	for i, hop := range m.redirectPath {
+		hops[i] = hop.String()
+	}
+	location := m.currentResponse.Headers["Location"]
+
+	if m.configManager.Get().UI.Accessible {
+		lines := []string{"Redirect path: " + strings.Join(hops, ", then ") + "."}
+		if m.redirectPrompt {
+			lines = append(lines, fmt.Sprintf("Redirect to %s. Press y to follow or n to stop.", location))
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	lines := []string{lipgloss.NewStyle().Foreground(palette.Accent).Render(
+		"Redirect path: " + strings.Join(hops, " → "))}
+	if m.redirectPrompt {
+		lines = append(lines, lipgloss.NewStyle().Foreground(palette.Info).Bold(true).Render(
+			fmt.Sprintf("↪ Follow redirect to %s? (y/n)", location)))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"onioncli/pkg/api"
+)
+
+// ExportReportMsg asks for a diagnostic report of the error shown in the
+// error viewer, copied to the clipboard, or written to path if set
+type ExportReportMsg struct {
+	path string
+}
+
+// ReportExportedMsg carries the outcome of a report export
+type ReportExportedMsg struct {
+	notice string
+}
+
+// exportReport builds the diagnostic report in the background, running
+// the Tor checks first if the error doesn't carry them, then copies or
+// writes it
+func (m Model) exportReport(msg ExportReportMsg) (Model, tea.Cmd) {
+	diagnostic := m.errorViewer.Diagnostic()
+	if diagnostic == nil {
+		return m, nil
+	}
+	m.errorViewer.reportDialog.Hide()
+	m.errorViewer.SetNotice("Building diagnostic report...")
+
+	info := m.reportInfo(diagnostic)
+	client := m.client
+	timeout := client.GetTimeout() + probeTimeoutMargin
+	return m, func() tea.Msg {
+		if diagnostic.Probes == nil && info.TorEnabled {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			info.Probes = client.DiagnoseTor(ctx)
+			cancel()
+		}
+		report := api.BuildDiagnosticReport(info)
+
+		if msg.path == "" {
+			if err := clipboard.WriteAll(report); err != nil {
+				return ReportExportedMsg{notice: fmt.Sprintf("Could not copy the report (%v), press w to write it to a file", err)}
+			}
+			return ReportExportedMsg{notice: "Diagnostic report copied to the clipboard"}
+		}
+		if err := os.WriteFile(msg.path, []byte(report), 0600); err != nil {
+			return ReportExportedMsg{notice: fmt.Sprintf("Could not write the report: %v", err)}
+		}
+		return ReportExportedMsg{notice: "Diagnostic report written to " + msg.path}
+	}
+}
+
+// reportInfo gathers the context for a diagnostic report of diagnostic
+func (m Model) reportInfo(diagnostic *api.DiagnosticError) api.ReportInfo {
+	cfg := m.configManager.Get()
+
+	return api.ReportInfo{
+		Error:          diagnostic,
+		Request:        m.currentRequest,
+		Auth:           m.authConfig,
+		TorEnabled:     m.client.IsTorEnabled(),
+		TorProxy:       m.client.GetTorProxy(),
+		TorProxySource: m.client.GetTorProxySource(),
+		Config: map[string]string{
+			"tor.enabled":                strconv.FormatBool(cfg.Tor.Enabled),
+			"tor.proxy":                  m.configManager.GetTorProxyAddress(),
+			"tor.auto_detect":            strconv.FormatBool(cfg.Tor.AutoDetect),
+			"tor.timeout":                strconv.Itoa(cfg.Tor.Timeout),
+			"tor.keep_alives":            strconv.FormatBool(cfg.Tor.KeepAlives),
+			"tor.control_port":           strconv.Itoa(cfg.Tor.ControlPort),
+			"tor.control_password":       cfg.Tor.ControlPassword, // masked in the report
+			"http.timeout":               strconv.Itoa(cfg.HTTP.Timeout),
+			"http.follow_redirects":      strconv.FormatBool(cfg.HTTP.FollowRedirects),
+			"http.max_redirects":         strconv.Itoa(cfg.HTTP.MaxRedirects),
+			"http.interactive_redirects": strconv.FormatBool(cfg.HTTP.InteractiveRedirects),
+			"http.verify_ssl":            strconv.FormatBool(cfg.HTTP.VerifySSL),
+			"http.auto_retry":            strconv.FormatBool(cfg.HTTP.AutoRetry),
+			"http.max_retries":           strconv.Itoa(cfg.HTTP.MaxRetries),
+			"http.use_cookie_jar":        strconv.FormatBool(cfg.HTTP.UseCookieJar),
+		},
+		Secrets:   append([]string{cfg.Tor.ControlPassword}, m.collectionsManager.SecretValues()...),
+		Generated: time.Now(),
+	}
+}
@@ -2,21 +2,40 @@ package tui
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
 	"onioncli/pkg/api"
+	"onioncli/pkg/collections"
 )
 
 // ResponseViewer handles the display of HTTP responses
 type ResponseViewer struct {
-	viewport viewport.Model
-	response *api.Response
-	width    int
-	height   int
+	viewport   viewport.Model
+	response   *api.Response
+	tests      []collections.TestResult // from the collection request's tests, if it has any
+	saveDialog FilePromptDialog
+	flat       bool // showing the flattened JSON path list instead of the details
+	raw        bool // showing the raw request and response instead of the details
+	unmasked   bool // raw exchange shows sensitive header values
+	htmlView   htmlViewMode
+	plain      bool // accessible mode: linear plain text, no borders or colours
+	width      int
+	height     int
+
+	// Search within the shown content, see responsesearch.go
+	content       string // the viewport's content before highlighting matches
+	searchInput   textinput.Model
+	searching     bool // the search prompt is open
+	query         string
+	caseSensitive bool
+	matches       []searchMatch
+	current       int // index into matches
 }
 
 // NewResponseViewer creates a new response viewer
@@ -24,44 +43,155 @@ func NewResponseViewer(width, height int) ResponseViewer {
 	vp := viewport.New(width-4, height-10) // Leave space for borders and controls
 	vp.Style = lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#7D56F4")).
 		Padding(1)
 
+	saveDialog := NewFilePromptDialog("Save Response Body", "response.txt", "save",
+		func(path string) tea.Msg { return SaveResponseMsg{path: path} })
+
+	searchInput := textinput.New()
+	searchInput.Prompt = "/"
+	searchInput.Placeholder = "search the response"
+	searchInput.CharLimit = 200
+
 	return ResponseViewer{
-		viewport: vp,
-		width:    width,
-		height:   height,
+		viewport:    vp,
+		saveDialog:  saveDialog,
+		searchInput: searchInput,
+		width:       width,
+		height:      height,
 	}
 }
 
 // SetResponse sets the response to display
 func (rv *ResponseViewer) SetResponse(response *api.Response) {
 	rv.response = response
-	content := rv.formatResponse(response)
-	rv.viewport.SetContent(content)
+	rv.flat = false
+	rv.raw = false
+	rv.setContent(rv.formatResponse(response))
+}
+
+// SetTests sets the test results shown with the next response set
+func (rv *ResponseViewer) SetTests(results []collections.TestResult) {
+	rv.tests = results
+}
+
+// SetAccessible switches accessible plain-text rendering on or off
+func (rv *ResponseViewer) SetAccessible(accessible bool) {
+	rv.plain = accessible
+	if accessible {
+		rv.viewport.Style = lipgloss.NewStyle()
+	}
+	if rv.response != nil {
+		rv.SetResponse(rv.response)
+	}
+}
+
+// ToggleFlatten switches between the response details and the flattened JSON
+// path list. Returns false if the body is not JSON.
+func (rv *ResponseViewer) ToggleFlatten() bool {
+	if rv.response == nil {
+		return false
+	}
+
+	if rv.flat {
+		rv.flat = false
+		rv.setContent(rv.formatResponse(rv.response))
+		rv.viewport.GotoTop()
+		return true
+	}
+
+	values := rv.response.FlattenJSON()
+	if values == nil {
+		return false
+	}
+	rv.flat = true
+	rv.raw = false
+	rv.setContent(formatFlattened(values))
+	rv.viewport.GotoTop()
+	return true
+}
+
+// IsFlattened returns whether the flattened JSON path list is shown
+func (rv ResponseViewer) IsFlattened() bool {
+	return rv.flat
+}
+
+// ShowSaveDialog asks where to save the response body, suggesting name
+func (rv *ResponseViewer) ShowSaveDialog(name string) {
+	rv.saveDialog.ShowPath(name)
+}
+
+// HideSaveDialog closes the save prompt
+func (rv *ResponseViewer) HideSaveDialog() {
+	rv.saveDialog.Hide()
+}
+
+// IsSaving returns whether the save prompt is open
+func (rv ResponseViewer) IsSaving() bool {
+	return rv.saveDialog.IsVisible()
 }
 
-// Update handles viewport updates
+// Update handles viewport updates, or the save or search prompt while it
+// is open
 func (rv ResponseViewer) Update(msg tea.Msg) (ResponseViewer, tea.Cmd) {
 	var cmd tea.Cmd
+	if rv.saveDialog.IsVisible() {
+		rv.saveDialog, cmd = rv.saveDialog.Update(msg)
+		return rv, cmd
+	}
+	if rv.searching {
+		return rv.updateSearch(msg)
+	}
 	rv.viewport, cmd = rv.viewport.Update(msg)
 	return rv, cmd
 }
 
+// GotoTop scrolls to the start of the response
+func (rv *ResponseViewer) GotoTop() {
+	rv.viewport.GotoTop()
+}
+
+// GotoBottom scrolls to the end of the response
+func (rv *ResponseViewer) GotoBottom() {
+	rv.viewport.GotoBottom()
+}
+
+// GotoLine scrolls so the given line, counted from 1, is at the top
+func (rv *ResponseViewer) GotoLine(line int) {
+	rv.viewport.SetYOffset(line - 1)
+}
+
 // View renders the response viewer
 func (rv ResponseViewer) View() string {
 	if rv.response == nil {
-		return rv.viewport.View()
+		return viewportView(rv.viewport, palette.Primary)
+	}
+
+	if rv.saveDialog.IsVisible() {
+		if rv.plain {
+			return "Save the response body to a file. Type a path, Enter saves, Escape cancels.\n" + rv.saveDialog.pathInput.View()
+		}
+		return lipgloss.Place(rv.width, rv.height, lipgloss.Center, lipgloss.Center, rv.saveDialog.View())
+	}
+
+	if rv.plain {
+		return lipgloss.JoinVertical(lipgloss.Left,
+			viewportView(rv.viewport, palette.Primary),
+			rv.searchStatus(),
+			"Keys: up and down scroll, slash searches and n or shift n move between matches, b edit as request, f flatten JSON paths, h switch an HTML body between highlighted, text and raw, v raw request and response, m show or mask secrets there, t resend as JSON or XML, p pin for comparison, u unpin, o open in pager, w save the body to a file, escape returns to the request builder.")
 	}
 
 	// Header with response summary
 	header := rv.renderResponseHeader()
 
 	// Viewport with response details
-	content := rv.viewport.View()
+	content := viewportView(rv.viewport, palette.Primary)
 
-	// Footer with navigation help
+	// Footer with navigation help, under the search prompt or match count
 	footer := rv.renderFooter()
+	if status := rv.searchStatus(); status != "" {
+		footer = lipgloss.JoinVertical(lipgloss.Left, statusStyle.Render(status), footer)
+	}
 
 	return lipgloss.JoinVertical(lipgloss.Left, header, content, footer)
 }
@@ -72,69 +202,154 @@ func (rv ResponseViewer) renderResponseHeader() string {
 		return ""
 	}
 
-	// Status styling based on response code
-	var statusStyle lipgloss.Style
-	if rv.response.IsSuccess() {
-		statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#50FA7B")).Bold(true)
-	} else if rv.response.IsClientError() {
-		statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFB86C")).Bold(true)
-	} else if rv.response.IsServerError() {
-		statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5555")).Bold(true)
-	} else {
-		statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#8BE9FD")).Bold(true)
-	}
-
-	status := statusStyle.Render(fmt.Sprintf("Status: %s", rv.response.Status))
-	duration := lipgloss.NewStyle().Foreground(lipgloss.Color("#F1FA8C")).Render(
+	status := statusCodeStyle(rv.response).Render(fmt.Sprintf("Status: %s", rv.response.Status))
+	duration := lipgloss.NewStyle().Foreground(palette.Highlight).Render(
 		fmt.Sprintf("Duration: %v", rv.response.Duration))
-	timestamp := lipgloss.NewStyle().Foreground(lipgloss.Color("#BD93F9")).Render(
+	timestamp := lipgloss.NewStyle().Foreground(palette.Accent).Render(
 		fmt.Sprintf("Time: %s", rv.response.Timestamp.Format("15:04:05")))
+	hash := lipgloss.NewStyle().Foreground(palette.Muted).Render(
+		fmt.Sprintf("Body: %s", rv.response.ShortBodyHash()))
+
+	header := lipgloss.JoinHorizontal(lipgloss.Left, status, "  ", duration, "  ", timestamp, "  ", hash)
+
+	// Untrusted certificates are flagged where they can't be missed
+	if info := rv.response.TLSInfo; info != nil && info.HasWarnings() {
+		warning := lipgloss.NewStyle().Foreground(palette.Error).Bold(true).Render(
+			"⚠ Certificate " + strings.Join(certificateProblems(info), ", "))
+		header = lipgloss.JoinVertical(lipgloss.Left, header, warning)
+	}
+
+	// A short body is easy to mistake for the whole response
+	if rv.response.LengthMismatch {
+		warning := lipgloss.NewStyle().Foreground(palette.Warning).Bold(true).Render(
+			"⚠ Body length: " + rv.response.LengthWarning)
+		header = lipgloss.JoinVertical(lipgloss.Left, header, warning)
+	}
+
+	// A sniffed type is a guess, so say so
+	if contentType, sniffed := rv.response.ContentType(); sniffed {
+		note := lipgloss.NewStyle().Foreground(palette.Highlight).Render(
+			fmt.Sprintf("ℹ No valid Content-Type, sniffed as %s", contentType))
+		header = lipgloss.JoinVertical(lipgloss.Left, header, note)
+	}
 
-	return lipgloss.JoinHorizontal(lipgloss.Left, status, "  ", duration, "  ", timestamp)
+	// Post-processed bodies are not what the server sent
+	if len(rv.response.Transforms) > 0 {
+		note := lipgloss.NewStyle().Foreground(palette.Highlight).Render(
+			"ℹ Body post-processed: " + strings.Join(rv.response.Transforms, " → "))
+		header = lipgloss.JoinVertical(lipgloss.Left, header, note)
+	}
+
+	if len(rv.tests) > 0 {
+		header = lipgloss.JoinVertical(lipgloss.Left, header, testSummary(rv.tests))
+	}
+
+	return header
 }
 
 // renderFooter renders navigation help
 func (rv ResponseViewer) renderFooter() string {
 	help := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#666666")).
-		Render("↑/↓ scroll • esc back to request builder • q quit")
+		Foreground(palette.Muted).
+		Render("↑/↓ scroll • / search • b edit as request • f flatten JSON paths • h HTML view • v raw exchange • t JSON/XML • p pin • o open in $PAGER/$EDITOR • w save body • esc back to request builder • q quit")
 
 	return help
 }
 
 // formatResponse formats the response for display
 func (rv ResponseViewer) formatResponse(response *api.Response) string {
+	if rv.plain {
+		return formatAccessibleResponse(response, rv.tests, rv.htmlView)
+	}
+
 	var sections []string
 
 	// Request summary (if available)
 	sections = append(sections, lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#7D56F4")).
+		Foreground(palette.Primary).
 		Bold(true).
 		Render("Response Details"))
 	sections = append(sections, "")
 
+	// Test results first, so failures are seen without scrolling
+	if len(rv.tests) > 0 {
+		sections = append(sections, lipgloss.NewStyle().
+			Foreground(palette.Success).
+			Bold(true).
+			Render("Tests:"))
+		sections = append(sections, formatTestResults(rv.tests)...)
+		sections = append(sections, "")
+	}
+
+	// Where a followed redirect chain landed
+	if response.FinalURL != "" {
+		sections = append(sections, fmt.Sprintf("%s %s",
+			lipgloss.NewStyle().Foreground(palette.Accent).Bold(true).Render("Redirected to:"),
+			response.FinalURL))
+		sections = append(sections, "")
+	}
+
 	// Headers section
 	if len(response.Headers) > 0 {
 		sections = append(sections, lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#50FA7B")).
+			Foreground(palette.Success).
 			Bold(true).
 			Render("Headers:"))
 
 		for key, value := range response.Headers {
 			headerLine := fmt.Sprintf("  %s: %s",
-				lipgloss.NewStyle().Foreground(lipgloss.Color("#8BE9FD")).Render(key),
+				lipgloss.NewStyle().Foreground(palette.Info).Render(key),
 				value)
 			sections = append(sections, headerLine)
 		}
 		sections = append(sections, "")
 	}
 
+	// Certificate section (HTTPS only)
+	if info := response.TLSInfo; info != nil {
+		sections = append(sections, lipgloss.NewStyle().
+			Foreground(palette.Success).
+			Bold(true).
+			Render("Certificate:"))
+
+		labelStyle := lipgloss.NewStyle().Foreground(palette.Info)
+		sections = append(sections,
+			fmt.Sprintf("  %s: %s", labelStyle.Render("Subject"), info.Subject),
+			fmt.Sprintf("  %s: %s", labelStyle.Render("Issuer"), info.Issuer),
+			fmt.Sprintf("  %s: %s to %s", labelStyle.Render("Valid"),
+				info.NotBefore.Format("2006-01-02"), info.NotAfter.Format("2006-01-02")),
+			fmt.Sprintf("  %s: %s, %s", labelStyle.Render("Protocol"), info.Version, info.CipherSuite))
+		if len(info.DNSNames) > 0 {
+			sections = append(sections, fmt.Sprintf("  %s: %s", labelStyle.Render("SANs"), strings.Join(info.DNSNames, ", ")))
+		}
+		if info.HasWarnings() {
+			sections = append(sections, lipgloss.NewStyle().
+				Foreground(palette.Error).
+				Bold(true).
+				Render("  ⚠ "+strings.Join(certificateProblems(info), ", ")))
+		}
+		sections = append(sections, "")
+	}
+
 	// Body section
-	if response.Body != "" {
+	if response.IsBinary() {
+		sections = append(sections, lipgloss.NewStyle().
+			Foreground(palette.Success).
+			Bold(true).
+			Render("Response Body: ")+binaryDescription(response))
+		sections = append(sections, binaryPreview(response), "")
+		sections = append(sections, lipgloss.NewStyle().
+			Foreground(palette.Warning).
+			Render(binaryHint))
+	} else if response.Body != "" {
+		heading := "Response Body:"
+		if response.IsHTML() {
+			heading = fmt.Sprintf("Response Body (HTML, %s; h to switch):", rv.htmlView)
+		}
 		sections = append(sections, lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#50FA7B")).
+			Foreground(palette.Success).
 			Bold(true).
-			Render("Response Body:"))
+			Render(heading))
 
 		// Try to pretty-print JSON
 		prettyBody, err := response.PrettyPrintJSON()
@@ -142,15 +357,20 @@ func (rv ResponseViewer) formatResponse(response *api.Response) string {
 			prettyBody = response.Body
 		}
 
-		// Syntax highlighting for JSON (basic)
-		if strings.Contains(response.Headers["Content-Type"], "application/json") {
+		// Syntax highlighting for JSON and XML (basic)
+		if response.IsJSON() {
 			prettyBody = rv.highlightJSON(prettyBody)
+		} else if response.IsHTML() {
+			prettyBody = rv.formatHTML(response)
+		} else if response.IsXML() {
+			prettyBody, _ = response.PrettyPrintXML()
+			prettyBody = rv.highlightXML(prettyBody)
 		}
 
 		sections = append(sections, prettyBody)
 	} else {
 		sections = append(sections, lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#666666")).
+			Foreground(palette.Muted).
 			Italic(true).
 			Render("(No response body)"))
 	}
@@ -158,6 +378,21 @@ func (rv ResponseViewer) formatResponse(response *api.Response) string {
 	return strings.Join(sections, "\n")
 }
 
+// certificateProblems lists the reasons a certificate should not be trusted
+func certificateProblems(info *api.TLSInfo) []string {
+	var problems []string
+	if info.Expired {
+		problems = append(problems, "expired")
+	}
+	if info.NotYetValid {
+		problems = append(problems, "not yet valid")
+	}
+	if info.SelfSigned {
+		problems = append(problems, "self-signed")
+	}
+	return problems
+}
+
 // highlightJSON provides basic JSON syntax highlighting
 func (rv ResponseViewer) highlightJSON(jsonStr string) string {
 	// Basic JSON highlighting - this is a simple implementation
@@ -174,18 +409,18 @@ func (rv ResponseViewer) highlightJSON(jsonStr string) string {
 			// Key-value pairs
 			parts := strings.SplitN(line, ":", 2)
 			if len(parts) == 2 {
-				key := lipgloss.NewStyle().Foreground(lipgloss.Color("#8BE9FD")).Render(parts[0])
+				key := lipgloss.NewStyle().Foreground(palette.Info).Render(parts[0])
 				value := strings.TrimSpace(parts[1])
 
 				// Highlight string values
 				if strings.HasPrefix(value, "\"") && strings.HasSuffix(value, "\"") {
-					value = lipgloss.NewStyle().Foreground(lipgloss.Color("#F1FA8C")).Render(value)
+					value = lipgloss.NewStyle().Foreground(palette.Highlight).Render(value)
 				} else if value == "true" || value == "false" {
 					// Boolean values
-					value = lipgloss.NewStyle().Foreground(lipgloss.Color("#50FA7B")).Render(value)
+					value = lipgloss.NewStyle().Foreground(palette.Success).Render(value)
 				} else if value == "null" {
 					// Null values
-					value = lipgloss.NewStyle().Foreground(lipgloss.Color("#6272A4")).Render(value)
+					value = lipgloss.NewStyle().Foreground(palette.Subtle).Render(value)
 				}
 
 				line = key + ":" + value
@@ -198,6 +433,42 @@ func (rv ResponseViewer) highlightJSON(jsonStr string) string {
 	return strings.Join(highlighted, "\n")
 }
 
+// xmlTagPattern matches a tag, comment, declaration or directive
+var xmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// xmlAttrPattern matches an attribute and its quoted value within a tag
+var xmlAttrPattern = regexp.MustCompile(`([\w:.-]+)(=)("[^"]*"|'[^']*')`)
+
+// highlightXML provides basic XML syntax highlighting: tag names, attribute
+// names and values, and comments or declarations each get their own colour
+func (rv ResponseViewer) highlightXML(xmlStr string) string {
+	tagStyle := lipgloss.NewStyle().Foreground(palette.Info)
+	attrStyle := lipgloss.NewStyle().Foreground(palette.Success)
+	valueStyle := lipgloss.NewStyle().Foreground(palette.Highlight)
+	commentStyle := lipgloss.NewStyle().Foreground(palette.Subtle)
+
+	return xmlTagPattern.ReplaceAllStringFunc(xmlStr, func(tag string) string {
+		if strings.HasPrefix(tag, "<!") || strings.HasPrefix(tag, "<?") {
+			return commentStyle.Render(tag)
+		}
+
+		// Split "<name attrs/>" into the name, its attributes and the closer
+		inner := strings.TrimSuffix(strings.TrimPrefix(tag, "<"), ">")
+		closer := ">"
+		if strings.HasSuffix(inner, "/") {
+			inner, closer = strings.TrimSuffix(inner, "/"), "/>"
+		}
+		name, attrs, _ := strings.Cut(inner, " ")
+		if attrs != "" {
+			attrs = " " + xmlAttrPattern.ReplaceAllStringFunc(attrs, func(attr string) string {
+				parts := xmlAttrPattern.FindStringSubmatch(attr)
+				return attrStyle.Render(parts[1]) + parts[2] + valueStyle.Render(parts[3])
+			})
+		}
+		return tagStyle.Render("<"+name) + attrs + tagStyle.Render(closer)
+	})
+}
+
 // Resize updates the viewport size
 func (rv *ResponseViewer) Resize(width, height int) {
 	rv.width = width
@@ -205,3 +476,29 @@ func (rv *ResponseViewer) Resize(width, height int) {
 	rv.viewport.Width = width - 4
 	rv.viewport.Height = height - 10
 }
+
+// formatFlattened renders one "path = value" line per JSON leaf
+func formatFlattened(values []api.PathValue) string {
+	pathStyle := lipgloss.NewStyle().Foreground(palette.Info)
+
+	lines := []string{
+		lipgloss.NewStyle().
+			Foreground(palette.Primary).
+			Bold(true).
+			Render(fmt.Sprintf("Flattened JSON (%d paths)", len(values))),
+		"",
+	}
+	for _, pv := range values {
+		lines = append(lines, pathStyle.Render(pv.Path)+" = "+pv.Value)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// flattenedText returns the flattened JSON path list as plain text
+func flattenedText(values []api.PathValue) string {
+	lines := make([]string, len(values))
+	for i, pv := range values {
+		lines[i] = pv.String()
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,206 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ansiPattern matches the escape sequences lipgloss styles text with
+var ansiPattern = regexp.MustCompile("\x1b\\[[0-9;]*[A-Za-z]")
+
+// searchMatch is one match of the response search: a line of the viewer's
+// content and the byte range of the match in that line's unstyled text
+type searchMatch struct {
+	line  int
+	start int
+	end   int
+}
+
+// stripANSI removes styling from text
+func stripANSI(text string) string {
+	return ansiPattern.ReplaceAllString(text, "")
+}
+
+// findMatches returns every match of query in content, line by line and
+// ignoring styling. Matching is case-insensitive unless caseSensitive.
+func findMatches(content, query string, caseSensitive bool) []searchMatch {
+	if query == "" {
+		return nil
+	}
+	pattern := regexp.QuoteMeta(query)
+	if !caseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	re := regexp.MustCompile(pattern)
+
+	var matches []searchMatch
+	for i, line := range strings.Split(content, "\n") {
+		for _, loc := range re.FindAllStringIndex(stripANSI(line), -1) {
+			matches = append(matches, searchMatch{line: i, start: loc[0], end: loc[1]})
+		}
+	}
+	return matches
+}
+
+// highlightMatches highlights matches in content, the current one most. Lines
+// with a match lose their own styling so the highlights line up.
+func highlightMatches(content string, matches []searchMatch, current int) string {
+	if len(matches) == 0 {
+		return content
+	}
+
+	lines := strings.Split(content, "\n")
+	for i := 0; i < len(matches); {
+		lineNo := matches[i].line
+		plain := stripANSI(lines[lineNo])
+		var b strings.Builder
+		last := 0
+		for ; i < len(matches) && matches[i].line == lineNo; i++ {
+			style := matchStyle
+			if i == current {
+				style = currentMatchStyle
+			}
+			b.WriteString(plain[last:matches[i].start])
+			b.WriteString(style.Render(plain[matches[i].start:matches[i].end]))
+			last = matches[i].end
+		}
+		b.WriteString(plain[last:])
+		lines[lineNo] = b.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// setContent shows content in the viewport, with the matches of the current
+// search highlighted
+func (rv *ResponseViewer) setContent(content string) {
+	rv.content = content
+	rv.matches = findMatches(content, rv.query, rv.caseSensitive)
+	rv.current = min(rv.current, max(len(rv.matches)-1, 0))
+	rv.viewport.SetContent(highlightMatches(content, rv.matches, rv.current))
+}
+
+// StartSearch opens the search prompt, starting from the last query
+func (rv *ResponseViewer) StartSearch() tea.Cmd {
+	rv.searching = true
+	rv.searchInput.SetValue(rv.query)
+	rv.searchInput.CursorEnd()
+	rv.searchInput.Focus()
+	return textinput.Blink
+}
+
+// IsSearching returns whether the search prompt is open
+func (rv ResponseViewer) IsSearching() bool {
+	return rv.searching
+}
+
+// HasSearch returns whether a search is applied
+func (rv ResponseViewer) HasSearch() bool {
+	return rv.query != ""
+}
+
+// updateSearch handles the search prompt: Enter searches, Esc closes it
+// and Alt+C toggles case sensitivity
+func (rv ResponseViewer) updateSearch(msg tea.Msg) (ResponseViewer, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "enter":
+			rv.searching = false
+			rv.searchInput.Blur()
+			rv.applySearch(rv.searchInput.Value())
+			return rv, nil
+		case "esc":
+			rv.searching = false
+			rv.searchInput.Blur()
+			return rv, nil
+		case "alt+c":
+			rv.caseSensitive = !rv.caseSensitive
+			return rv, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	rv.searchInput, cmd = rv.searchInput.Update(msg)
+	return rv, cmd
+}
+
+// applySearch searches for query and jumps to the first match at or below
+// the top of the view
+func (rv *ResponseViewer) applySearch(query string) {
+	rv.query = query
+	rv.current = 0
+	rv.setContent(rv.content)
+	for i, match := range rv.matches {
+		if match.line >= rv.viewport.YOffset {
+			rv.current = i
+			break
+		}
+	}
+	rv.showCurrentMatch()
+}
+
+// NextMatch moves to the next match, or the previous one if delta is
+// negative, wrapping around. Returns false if there are none.
+func (rv *ResponseViewer) NextMatch(delta int) bool {
+	if len(rv.matches) == 0 {
+		return false
+	}
+	rv.current = (rv.current + delta + len(rv.matches)) % len(rv.matches)
+	rv.showCurrentMatch()
+	return true
+}
+
+// ClearSearch removes the search and its highlights. Returns false if no
+// search was applied.
+func (rv *ResponseViewer) ClearSearch() bool {
+	if rv.query == "" {
+		return false
+	}
+	rv.query = ""
+	rv.current = 0
+	rv.setContent(rv.content)
+	return true
+}
+
+// showCurrentMatch highlights the current match and scrolls it into view,
+// a third of the way down
+func (rv *ResponseViewer) showCurrentMatch() {
+	rv.viewport.SetContent(highlightMatches(rv.content, rv.matches, rv.current))
+	if len(rv.matches) == 0 {
+		return
+	}
+	line := rv.matches[rv.current].line
+	visible := rv.viewport.Height - rv.viewport.Style.GetVerticalFrameSize()
+	if line < rv.viewport.YOffset || line >= rv.viewport.YOffset+visible {
+		rv.viewport.SetYOffset(max(line-visible/3, 0))
+	}
+}
+
+// searchStatus renders the search prompt while it is open, or how many
+// matches the applied search has
+func (rv ResponseViewer) searchStatus() string {
+	mode := "ignoring case"
+	if rv.caseSensitive {
+		mode = "case-sensitive"
+	}
+	switch {
+	case rv.searching:
+		return fmt.Sprintf("%s  (%s, alt+c to toggle • enter search • esc cancel)", rv.searchInput.View(), mode)
+	case rv.query == "":
+		return ""
+	case len(rv.matches) == 0:
+		return fmt.Sprintf("No matches for %q (%s)", rv.query, mode)
+	}
+	return fmt.Sprintf("Match %d of %d for %q (%s) • n/N next/previous • esc clear", rv.current+1, len(rv.matches), rv.query, mode)
+}
+
+// nextResponseMatch moves the response search to the next or previous match
+func (m Model) nextResponseMatch(delta int) Model {
+	if !m.responseViewer.NextMatch(delta) {
+		m.statusIndicator.Show("No matches in the response", StatusInfo)
+	}
+	return m
+}
@@ -0,0 +1,31 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindMatches(t *testing.T) {
+	content := "\x1b[1mResponse Details\x1b[0m\n\n\x1b[96m\"name\"\x1b[0m: \x1b[93m\"Alice\"\x1b[0m\nalice ALICE"
+
+	tests := []struct {
+		name          string
+		query         string
+		caseSensitive bool
+		want          []searchMatch
+	}{
+		{"ignoring case and styling", "alice", false, []searchMatch{{2, 9, 14}, {3, 0, 5}, {3, 6, 11}}},
+		{"case-sensitive", "Alice", true, []searchMatch{{2, 9, 14}}},
+		{"metacharacters are literal", "\".*\"", false, nil},
+		{"across styling", "\"name\": \"", false, []searchMatch{{2, 0, 9}}},
+		{"empty query", "", false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := findMatches(content, tt.query, tt.caseSensitive); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findMatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package tui
+
+import (
+	"fmt"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"onioncli/pkg/api"
+)
+
+// SaveResponseMsg asks for the response body to be written to path
+type SaveResponseMsg struct {
+	path string
+}
+
+// ResponseSavedMsg carries the outcome of saving a response body
+type ResponseSavedMsg struct {
+	path  string
+	bytes int
+	err   error
+}
+
+// responseFileName suggests a file name for a response body: the URL's last
+// path segment, given an extension from the Content-Type if it has none
+func responseFileName(rawURL, contentType string) string {
+	name := ""
+	if u, err := url.Parse(rawURL); err == nil {
+		name = path.Base(u.Path)
+	}
+	if name == "" || name == "." || name == "/" {
+		name = "response"
+	}
+	if path.Ext(name) == "" {
+		name += responseFileExtension(contentType)
+	}
+	return name
+}
+
+// responseFileExtension picks a file extension for a Content-Type: the
+// editor-friendly ones used for the pager, then the system's MIME table,
+// then .txt for text and .bin for anything else
+func responseFileExtension(contentType string) string {
+	if ext := contentTypeExtension(contentType); ext != ".txt" {
+		return ext
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || strings.HasPrefix(mediaType, "text/") {
+		return ".txt"
+	}
+	if extensions, _ := mime.ExtensionsByType(mediaType); len(extensions) > 0 {
+		return extensions[0]
+	}
+	return ".bin"
+}
+
+// promptSaveResponse opens the save prompt with a file name suggested from
+// the request URL and the response's content type
+func (m Model) promptSaveResponse() Model {
+	rawURL := ""
+	if m.currentRequest != nil {
+		rawURL = m.currentRequest.URL
+	}
+	contentType, _ := m.currentResponse.ContentType()
+	m.responseViewer.ShowSaveDialog(responseFileName(rawURL, contentType))
+	return m
+}
+
+// saveResponseBody writes the response body in the background, byte for
+// byte as received, so binary payloads are written back unchanged
+func (m Model) saveResponseBody(msg SaveResponseMsg) (Model, tea.Cmd) {
+	m.responseViewer.HideSaveDialog()
+	if m.currentResponse == nil {
+		return m, nil
+	}
+
+	body := m.currentResponse.BodyBytes()
+	return m, func() tea.Msg {
+		err := os.WriteFile(msg.path, body, 0600)
+		return ResponseSavedMsg{path: msg.path, bytes: len(body), err: err}
+	}
+}
+
+// handleResponseSaved reports a saved body, or raises the error alert
+func (m Model) handleResponseSaved(msg ResponseSavedMsg) Model {
+	if msg.err != nil {
+		m.errorAlert.Show(&api.DiagnosticError{
+			Type:    api.ErrorTypeUnknown,
+			Message: fmt.Sprintf("Could not save the response body to %s", msg.path),
+			Cause:   msg.err,
+			Suggestions: []string{
+				"Check that the directory exists and you can write to it",
+				"Press w to try a different path",
+			},
+		})
+		return m
+	}
+	m.errorAlert.Hide()
+	m.statusIndicator.Show(fmt.Sprintf("Saved %d bytes to %s", msg.bytes, msg.path), StatusSuccess)
+	return m
+}
@@ -0,0 +1,30 @@
+package tui
+
+import "testing"
+
+func TestResponseFileName(t *testing.T) {
+	tests := []struct {
+		name        string
+		url         string
+		contentType string
+		want        string
+	}{
+		{"segment with extension", "http://example.onion/files/report.pdf", "application/pdf", "report.pdf"},
+		{"JSON gets .json", "http://example.onion/api/users", "application/json; charset=utf-8", "users.json"},
+		{"image type from the MIME table", "http://example.onion/avatar", "image/png", "avatar.png"},
+		{"plain text", "http://example.onion/notes", "text/plain", "notes.txt"},
+		{"unknown binary type", "http://example.onion/blob", "application/x-onioncli-test", "blob.bin"},
+		{"no path", "http://example.onion", "text/html", "response.html"},
+		{"trailing slash", "http://example.onion/api/", "application/json", "api.json"},
+		{"query is ignored", "http://example.onion/export?format=csv", "text/csv", "export.csv"},
+		{"no content type", "http://example.onion/data", "", "data.txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := responseFileName(tt.url, tt.contentType); got != tt.want {
+				t.Errorf("responseFileName(%q, %q) = %q, want %q", tt.url, tt.contentType, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,389 @@
+package tui
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"onioncli/pkg/api"
+	"onioncli/pkg/collections"
+)
+
+// Fields of the save-to-collection dialog, in tab order
+const (
+	saveFieldCollection = iota
+	saveFieldNewCollection
+	saveFieldName
+	saveFieldDescription
+)
+
+// SaveToCollectionMsg asks for the builder's request to be added to a
+// collection, creating the collection first if newCollection is set
+type SaveToCollectionMsg struct {
+	collectionID  string
+	newCollection string
+	name          string
+	description   string
+}
+
+// SaveToCollectionDialog picks a collection, or names a new one, and the
+// name and description to save the builder's request under
+type SaveToCollectionDialog struct {
+	collections      []collections.Collection
+	selected         int // index into collections; len(collections) is "new collection"
+	newInput         textinput.Model
+	nameInput        textinput.Model
+	descriptionInput textinput.Model
+	focusedField     int
+	err              string // why the last save was rejected
+	visible          bool
+	plain            bool // accessible mode: text labels instead of colours
+}
+
+// NewSaveToCollectionDialog creates a new save-to-collection dialog
+func NewSaveToCollectionDialog() SaveToCollectionDialog {
+	newInput := textinput.New()
+	newInput.Placeholder = "Enter new collection name..."
+	newInput.CharLimit = 100
+	newInput.Width = 50
+
+	nameInput := textinput.New()
+	nameInput.Placeholder = "Enter request name..."
+	nameInput.CharLimit = 100
+	nameInput.Width = 50
+
+	descriptionInput := textinput.New()
+	descriptionInput.Placeholder = "Enter description (optional)..."
+	descriptionInput.CharLimit = 200
+	descriptionInput.Width = 50
+
+	return SaveToCollectionDialog{
+		newInput:         newInput,
+		nameInput:        nameInput,
+		descriptionInput: descriptionInput,
+	}
+}
+
+// Show opens the dialog over the existing collections with name suggested
+// for the request. With no collections yet it starts on naming a new one.
+func (d *SaveToCollectionDialog) Show(existing []collections.Collection, name string) tea.Cmd {
+	d.collections = existing
+	d.selected = 0
+	d.newInput.SetValue("")
+	d.nameInput.SetValue(name)
+	d.descriptionInput.SetValue("")
+	d.err = ""
+	d.visible = true
+	d.focusedField = saveFieldCollection
+	if len(existing) == 0 {
+		d.focusedField = saveFieldNewCollection
+	}
+	d.updateFocus()
+	return textinput.Blink
+}
+
+// Hide hides the dialog
+func (d *SaveToCollectionDialog) Hide() {
+	d.visible = false
+	d.collections = nil
+	d.err = ""
+	d.newInput.Blur()
+	d.nameInput.Blur()
+	d.descriptionInput.Blur()
+}
+
+// SetError shows why saving failed, keeping the dialog open
+func (d *SaveToCollectionDialog) SetError(err error) {
+	d.err = err.Error()
+}
+
+// SetAccessible switches accessible plain-text rendering on or off
+func (d *SaveToCollectionDialog) SetAccessible(accessible bool) {
+	d.plain = accessible
+}
+
+// IsVisible returns whether the dialog is shown
+func (d SaveToCollectionDialog) IsVisible() bool {
+	return d.visible
+}
+
+// creatingNew reports whether "new collection" is picked
+func (d SaveToCollectionDialog) creatingNew() bool {
+	return d.selected == len(d.collections)
+}
+
+// fields returns the fields in tab order; the new collection name only
+// while "new collection" is picked
+func (d SaveToCollectionDialog) fields() []int {
+	if d.creatingNew() {
+		return []int{saveFieldCollection, saveFieldNewCollection, saveFieldName, saveFieldDescription}
+	}
+	return []int{saveFieldCollection, saveFieldName, saveFieldDescription}
+}
+
+// moveFocus moves to the next (step 1) or previous (step -1) field
+func (d *SaveToCollectionDialog) moveFocus(step int) {
+	fields := d.fields()
+	current := 0
+	for i, field := range fields {
+		if field == d.focusedField {
+			current = i
+		}
+	}
+	d.focusedField = fields[(current+step+len(fields))%len(fields)]
+	d.updateFocus()
+}
+
+// updateFocus focuses the input of the focused field, if it has one
+func (d *SaveToCollectionDialog) updateFocus() {
+	d.newInput.Blur()
+	d.nameInput.Blur()
+	d.descriptionInput.Blur()
+
+	switch d.focusedField {
+	case saveFieldNewCollection:
+		d.newInput.Focus()
+	case saveFieldName:
+		d.nameInput.Focus()
+	case saveFieldDescription:
+		d.descriptionInput.Focus()
+	}
+}
+
+// Update handles dialog updates. Up and down pick the collection while the
+// collection list is focused; Enter saves from any field.
+func (d SaveToCollectionDialog) Update(msg tea.Msg) (SaveToCollectionDialog, tea.Cmd) {
+	if !d.visible {
+		return d, nil
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "tab":
+			d.moveFocus(1)
+			return d, nil
+		case "shift+tab":
+			d.moveFocus(-1)
+			return d, nil
+		case "up", "down":
+			if d.focusedField == saveFieldCollection {
+				count := len(d.collections) + 1
+				if msg.String() == "up" {
+					d.selected = (d.selected + count - 1) % count
+				} else {
+					d.selected = (d.selected + 1) % count
+				}
+				return d, nil
+			}
+		case "enter":
+			return d.save()
+		case "esc":
+			d.Hide()
+			return d, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	switch d.focusedField {
+	case saveFieldNewCollection:
+		d.newInput, cmd = d.newInput.Update(msg)
+	case saveFieldName:
+		d.nameInput, cmd = d.nameInput.Update(msg)
+	case saveFieldDescription:
+		d.descriptionInput, cmd = d.descriptionInput.Update(msg)
+	}
+	return d, cmd
+}
+
+// save validates the fields and emits a SaveToCollectionMsg
+func (d SaveToCollectionDialog) save() (SaveToCollectionDialog, tea.Cmd) {
+	saveMsg := SaveToCollectionMsg{
+		name:        strings.TrimSpace(d.nameInput.Value()),
+		description: strings.TrimSpace(d.descriptionInput.Value()),
+	}
+	if d.creatingNew() {
+		saveMsg.newCollection = strings.TrimSpace(d.newInput.Value())
+		if saveMsg.newCollection == "" {
+			d.err = "Name the new collection"
+			d.focusedField = saveFieldNewCollection
+			d.updateFocus()
+			return d, nil
+		}
+	} else {
+		saveMsg.collectionID = d.collections[d.selected].ID
+	}
+	if saveMsg.name == "" {
+		d.err = "Request name is required"
+		d.focusedField = saveFieldName
+		d.updateFocus()
+		return d, nil
+	}
+
+	d.err = ""
+	return d, func() tea.Msg { return saveMsg }
+}
+
+// collectionLines renders the collection picker, one option per line
+func (d SaveToCollectionDialog) collectionLines() []string {
+	options := make([]string, 0, len(d.collections)+1)
+	for _, collection := range d.collections {
+		options = append(options, fmt.Sprintf("%s (%d requests)", collection.Name, len(collection.Requests)))
+	}
+	options = append(options, "+ New collection")
+
+	lines := make([]string, len(options))
+	for i, option := range options {
+		switch {
+		case i == d.selected && d.plain:
+			lines[i] = "Selected: " + option
+		case i == d.selected:
+			lines[i] = lipgloss.NewStyle().Foreground(palette.Primary).Bold(true).Render("▸ " + option)
+		default:
+			lines[i] = "  " + option
+		}
+	}
+	return lines
+}
+
+// View renders the dialog
+func (d SaveToCollectionDialog) View() string {
+	if !d.visible {
+		return ""
+	}
+
+	sections := []string{titleStyle.Render("Save to Collection")}
+
+	fields := []struct {
+		field int
+		label string
+		view  string
+	}{
+		{saveFieldCollection, "Collection:", strings.Join(d.collectionLines(), "\n")},
+		{saveFieldNewCollection, "New collection name:", d.newInput.View()},
+		{saveFieldName, "Request name:", d.nameInput.View()},
+		{saveFieldDescription, "Description:", d.descriptionInput.View()},
+	}
+	for _, f := range fields {
+		if f.field == saveFieldNewCollection && !d.creatingNew() {
+			continue
+		}
+		style := blurredStyle
+		if d.focusedField == f.field {
+			style = focusedStyle
+		}
+		sections = append(sections, style.Render(fmt.Sprintf("%s\n%s", f.label, f.view)))
+	}
+
+	if d.err != "" {
+		sections = append(sections, errorStyle.Render(d.err))
+	}
+	sections = append(sections, helpStyle.Render("↑/↓ to pick a collection, Tab/Shift+Tab to switch fields, Enter to save, Esc to cancel"))
+
+	content := strings.Join(sections, "\n\n")
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(palette.Primary).
+		Padding(1).
+		Render(content)
+}
+
+// suggestedRequestName names a request after its method and URL path, e.g.
+// "GET /users/{{id}}", falling back to the whole URL when it has no path
+func suggestedRequestName(method, rawURL string) string {
+	rawURL = strings.TrimSpace(rawURL)
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" && u.Path != "" && u.Path != "/" {
+		rawURL = u.Path
+	}
+	return strings.TrimSpace(method + " " + rawURL)
+}
+
+// keepPlaceholders undoes the percent-encoding of {{variable}} braces, so
+// placeholders in query params still resolve once saved
+func keepPlaceholders(query string) string {
+	return strings.NewReplacer("%7B%7B", "{{", "%7D%7D", "}}").Replace(query)
+}
+
+// builderRequest returns the builder's request as typed, before default
+// headers, variables and auth are applied, for saving to a collection
+func (m Model) builderRequest() (*api.Request, error) {
+	if m.bodyForm {
+		return nil, fmt.Errorf("form bodies can't be saved to a collection; switch to a raw body first")
+	}
+
+	rawURL := strings.TrimSpace(m.urlInput.Value())
+	if rawURL == "" {
+		return nil, fmt.Errorf("enter a URL first")
+	}
+	queryParams, err := parseQueryParams(m.queryArea.Value())
+	if err != nil {
+		return nil, fmt.Errorf("invalid query parameters: %v", err)
+	}
+	headersText, _, err := extractTimeout(m.headersArea.Value())
+	if err != nil {
+		return nil, err
+	}
+
+	req := api.NewRequest(m.currentDraft().Method, appendQuery(rawURL, keepPlaceholders(encodeQueryParams(queryParams))))
+	for key, value := range m.parseHeaders(strings.TrimSpace(headersText)) {
+		req.SetHeader(key, value)
+	}
+	if body := strings.TrimSpace(m.bodyArea.Value()); body != "" {
+		req.SetBody(body)
+	}
+	return req, nil
+}
+
+// openSaveToCollection opens the save-to-collection dialog for the
+// builder's request
+func (m Model) openSaveToCollection() (Model, tea.Cmd) {
+	req, err := m.builderRequest()
+	if err != nil {
+		m.statusIndicator.Show("Can't save to a collection: "+err.Error(), StatusWarning)
+		return m, nil
+	}
+	name := suggestedRequestName(req.Method, strings.TrimSpace(m.urlInput.Value()))
+	return m, m.collectionSave.Show(m.collectionsManager.GetCollections(), name)
+}
+
+// updateSaveToCollection handles keys while the save-to-collection dialog
+// is open
+func (m Model) updateSaveToCollection(msg tea.KeyMsg) (Model, tea.Cmd) {
+	if msg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+	var cmd tea.Cmd
+	m.collectionSave, cmd = m.collectionSave.Update(msg)
+	return m, cmd
+}
+
+// saveToCollection adds the builder's request to the chosen collection,
+// creating the collection first if asked
+func (m Model) saveToCollection(msg SaveToCollectionMsg) Model {
+	req, err := m.builderRequest()
+	if err != nil {
+		m.collectionSave.SetError(err)
+		return m
+	}
+
+	collectionID := msg.collectionID
+	if msg.newCollection != "" {
+		collectionID = m.collectionsManager.CreateCollection(msg.newCollection, "").ID
+	}
+	if err := m.collectionsManager.AddRequestToCollection(collectionID, req, msg.name, msg.description); err != nil {
+		m.collectionSave.SetError(err)
+		return m
+	}
+
+	collectionName := msg.newCollection
+	if collection, err := m.collectionsManager.GetCollection(collectionID); err == nil {
+		collectionName = collection.Name
+	}
+	m.collectionSave.Hide()
+	m.collectionsViewer.collectionChanged(collectionID)
+	m.statusIndicator.Show(fmt.Sprintf("Saved %s to %s", msg.name, collectionName), StatusSuccess)
+	return m
+}
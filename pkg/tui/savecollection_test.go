@@ -0,0 +1,31 @@
+package tui
+
+import "testing"
+
+func TestSuggestedRequestName(t *testing.T) {
+	tests := []struct {
+		method string
+		url    string
+		want   string
+	}{
+		{"GET", "http://example.onion/users/{{id}}", "GET /users/{{id}}"},
+		{"POST", "  https://api.example.com/orders?draft=1  ", "POST /orders"},
+		{"GET", "http://example.onion/", "GET http://example.onion/"},
+		{"GET", "http://example.onion", "GET http://example.onion"},
+		{"DELETE", "{{base_url}}/items/1", "DELETE {{base_url}}/items/1"},
+	}
+
+	for _, tt := range tests {
+		if got := suggestedRequestName(tt.method, tt.url); got != tt.want {
+			t.Errorf("suggestedRequestName(%q, %q) = %q, want %q", tt.method, tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestKeepPlaceholders(t *testing.T) {
+	params := []queryParam{{name: "token", value: "{{token}}"}, {name: "q", value: "a b&c"}}
+	want := "token={{token}}&q=a+b%26c"
+	if got := keepPlaceholders(encodeQueryParams(params)); got != want {
+		t.Errorf("keepPlaceholders = %q, want %q", got, want)
+	}
+}
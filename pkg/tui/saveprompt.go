@@ -0,0 +1,45 @@
+package tui
+
+import (
+	"github.com/charmbracelet/lipgloss"
+
+	"onioncli/pkg/api"
+)
+
+// shouldPromptSave reports whether to offer saving the request behind a
+// response: the prompt is enabled, the response is 2xx, and the request is
+// neither saved in history or a collection nor already declined this session
+func (m Model) shouldPromptSave(response *api.Response) bool {
+	if !m.configManager.Get().UI.PromptSave || m.currentRequest == nil || !response.IsSuccess() {
+		return false
+	}
+	if m.declinedSaves[m.currentRequest.Fingerprint()] {
+		return false
+	}
+	return !m.historyManager.Contains(m.currentRequest) && !m.collectionsManager.Contains(m.currentRequest)
+}
+
+// answerSavePrompt handles y/x on the save prompt; declined requests are not
+// offered again until restart
+func (m Model) answerSavePrompt(save bool) Model {
+	m.savePrompt = false
+	if save {
+		m.saveDialog.Show()
+	} else if m.currentRequest != nil {
+		m.declinedSaves[m.currentRequest.Fingerprint()] = true
+	}
+	return m
+}
+
+// renderSavePrompt renders the save prompt shown under a response
+func (m Model) renderSavePrompt() string {
+	if !m.savePrompt {
+		return ""
+	}
+	if m.configManager.Get().UI.Accessible {
+		return "Save this request? Press y to save or x to skip."
+	}
+	return lipgloss.NewStyle().
+		Foreground(palette.Info).
+		Render("💾 Save this request? (y to save, x to skip)")
+}
@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"strings"
+
+	"onioncli/pkg/api"
+)
+
+// urlScheme returns the scheme a URL will be sent with and whether it is
+// assumed because the URL has none. URLs starting with a variable have no
+// known scheme until it is resolved.
+func urlScheme(raw string) (scheme string, assumed bool) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" || strings.HasPrefix(trimmed, "{{") {
+		return "", false
+	}
+	if scheme, _, ok := strings.Cut(trimmed, "://"); ok {
+		return strings.ToLower(scheme), false
+	}
+	return api.AssumedScheme(trimmed), true
+}
+
+// toggledSchemes pairs each scheme with its TLS or plain counterpart
+var toggledSchemes = map[string]string{
+	"http":  "https",
+	"https": "http",
+	"ws":    "wss",
+	"wss":   "ws",
+}
+
+// toggleScheme flips a URL between http:// and https://, or ws:// and
+// wss://, writing out the scheme if it was only assumed. Other schemes are
+// left alone.
+func toggleScheme(raw string) (string, bool) {
+	scheme, _ := urlScheme(raw)
+	toggled, ok := toggledSchemes[scheme]
+	if !ok {
+		return raw, false
+	}
+
+	trimmed := strings.TrimSpace(raw)
+	if _, rest, ok := strings.Cut(trimmed, "://"); ok {
+		trimmed = rest
+	}
+	return toggled + "://" + trimmed, true
+}
+
+// urlLabel titles the URL field with the scheme that will be used
+func urlLabel(raw string) string {
+	scheme, assumed := urlScheme(raw)
+	switch {
+	case scheme == "":
+		return "URL:"
+	case assumed:
+		return "URL (assumed " + scheme + "://, Ctrl+X to switch):"
+	default:
+		return "URL (" + scheme + "://, Ctrl+X to switch):"
+	}
+}
+
+// submitLabel names the builder's submit button: WebSocket URLs connect
+// rather than send
+func submitLabel(raw string) string {
+	if scheme, _ := urlScheme(raw); scheme == "ws" || scheme == "wss" {
+		return "Connect"
+	}
+	return "Send Request"
+}
+
+// toggleURLScheme switches the URL field between http and https, or ws and wss
+func (m Model) toggleURLScheme() Model {
+	toggled, ok := toggleScheme(m.urlInput.Value())
+	if !ok {
+		m.statusIndicator.Show("Enter an http(s) or ws(s) URL to switch its scheme", StatusInfo)
+		return m
+	}
+	m.urlInput.SetValue(toggled)
+	m.urlInput.CursorEnd()
+	scheme, _ := urlScheme(toggled)
+	m.statusIndicator.Show("Scheme set to "+scheme+"://", StatusInfo)
+	return m
+}
@@ -0,0 +1,62 @@
+package tui
+
+import "testing"
+
+func TestToggleScheme(t *testing.T) {
+	tests := []struct {
+		name   string
+		raw    string
+		want   string
+		wantOK bool
+	}{
+		{"http to https", "http://example.onion/api", "https://example.onion/api", true},
+		{"https to http", "https://example.com", "http://example.com", true},
+		{"assumed onion scheme", "example.onion/api", "https://example.onion/api", true},
+		{"assumed clearnet scheme", "example.com/api", "http://example.com/api", true},
+		{"ws to wss", "ws://example.onion/socket", "wss://example.onion/socket", true},
+		{"wss to ws", "WSS://example.com/feed", "ws://example.com/feed", true},
+		{"other scheme", "ftp://example.onion/file", "ftp://example.onion/file", false},
+		{"variable base", "{{base_url}}/users", "{{base_url}}/users", false},
+		{"empty", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := toggleScheme(tt.raw)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("toggleScheme(%q) = %q, %v, want %q, %v", tt.raw, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestURLLabel(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"", "URL:"},
+		{"example.onion", "URL (assumed http://, Ctrl+X to switch):"},
+		{"example.com", "URL (assumed https://, Ctrl+X to switch):"},
+		{"HTTPS://example.onion", "URL (https://, Ctrl+X to switch):"},
+	}
+
+	for _, tt := range tests {
+		if got := urlLabel(tt.raw); got != tt.want {
+			t.Errorf("urlLabel(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestSubmitLabel(t *testing.T) {
+	for raw, want := range map[string]string{
+		"ws://example.onion/socket": "Connect",
+		"WSS://example.com/feed":    "Connect",
+		"https://example.com":       "Send Request",
+		"example.onion":             "Send Request",
+	} {
+		if got := submitLabel(raw); got != want {
+			t.Errorf("submitLabel(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
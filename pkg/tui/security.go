@@ -0,0 +1,116 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"onioncli/pkg/api"
+)
+
+// severityColor colours a finding severity from the active theme
+func severityColor(severity api.Severity) lipgloss.Color {
+	switch severity {
+	case api.SeverityHigh:
+		return palette.Error
+	case api.SeverityMedium:
+		return palette.Warning
+	case api.SeverityLow:
+		return palette.Highlight
+	}
+	return palette.Success
+}
+
+// SecurityAuditView shows the findings of a response-headers security audit
+type SecurityAuditView struct {
+	findings []api.SecurityFinding
+	visible  bool
+	plain    bool // accessible mode: text labels instead of colours
+}
+
+// NewSecurityAuditView creates a new security audit view
+func NewSecurityAuditView() SecurityAuditView {
+	return SecurityAuditView{visible: false}
+}
+
+// Show audits the response headers and displays the findings
+func (sv *SecurityAuditView) Show(resp *api.Response) {
+	sv.findings = api.AuditSecurityHeaders(resp)
+	sv.visible = true
+}
+
+// Hide hides the audit
+func (sv *SecurityAuditView) Hide() {
+	sv.visible = false
+	sv.findings = nil
+}
+
+// SetAccessible switches accessible plain-text rendering on or off
+func (sv *SecurityAuditView) SetAccessible(accessible bool) {
+	sv.plain = accessible
+}
+
+// IsVisible returns whether the audit is shown
+func (sv SecurityAuditView) IsVisible() bool {
+	return sv.visible
+}
+
+// summary counts the findings that need attention, e.g. "1 high, 2 medium, 1 low"
+func (sv SecurityAuditView) summary() string {
+	counts := make(map[api.Severity]int)
+	for _, finding := range sv.findings {
+		counts[finding.Severity]++
+	}
+
+	var parts []string
+	for _, severity := range []api.Severity{api.SeverityHigh, api.SeverityMedium, api.SeverityLow} {
+		if counts[severity] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[severity], severity))
+		}
+	}
+	if len(parts) == 0 {
+		return "No issues found"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// View renders the findings
+func (sv SecurityAuditView) View(width, height int) string {
+	if !sv.visible {
+		return ""
+	}
+
+	if sv.plain {
+		lines := []string{"Security headers audit. " + sv.summary() + "."}
+		for _, finding := range sv.findings {
+			lines = append(lines, fmt.Sprintf("%s, %s: %s", finding.Severity, finding.Header, finding.Message))
+		}
+		lines = append(lines, "Press any key to close.")
+		return strings.Join(lines, "\n")
+	}
+
+	headerStyle := lipgloss.NewStyle().Foreground(palette.Info).Width(26)
+
+	lines := []string{titleStyle.Render("Security Headers Audit"), ""}
+	for _, finding := range sv.findings {
+		severity := lipgloss.NewStyle().
+			Foreground(severityColor(finding.Severity)).
+			Bold(true).
+			Width(7).
+			Render(strings.ToUpper(string(finding.Severity)))
+		lines = append(lines, fmt.Sprintf("%s %s %s", severity, headerStyle.Render(truncateValue(finding.Header, 26)),
+			truncateValue(finding.Message, 70)))
+	}
+
+	lines = append(lines, "", lipgloss.NewStyle().Bold(true).Render(sv.summary()))
+	lines = append(lines, helpStyle.Render("Press any key to close"))
+
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(palette.Primary).
+		Padding(1, 2).
+		Render(strings.Join(lines, "\n"))
+
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, dialog)
+}
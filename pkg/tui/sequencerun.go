@@ -0,0 +1,119 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"onioncli/pkg/collections"
+)
+
+// SequenceCompleteMsg is sent once a sequence run finishes, fails, or is cancelled
+type SequenceCompleteMsg struct {
+	run *collections.SequenceRun
+	err error
+}
+
+// startSequenceRun runs the open collection's requests in order off the UI
+// goroutine, streaming progress messages
+func (cv *CollectionsViewer) startSequenceRun() tea.Cmd {
+	if cv.selectedCollection == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan tea.Msg)
+
+	cv.runCancel = cancel
+	cv.runCh = ch
+	cv.statusMessage = ""
+	cv.runProgress.Show(fmt.Sprintf("Running %s in sequence", cv.selectedCollection.Name), 0)
+
+	manager := cv.manager
+	client := cv.client
+	collectionID := cv.selectedCollection.ID
+	continueOnError := cv.continueOnError
+	go func() {
+		defer close(ch)
+		progress := collections.ProgressFunc(func(done, total int) {
+			select {
+			case ch <- DataRunProgressMsg{done: done, total: total}:
+			case <-ctx.Done():
+			}
+		})
+		run, err := manager.RunSequence(ctx, collectionID, client, continueOnError, progress)
+		ch <- SequenceCompleteMsg{run: run, err: err}
+	}()
+
+	return waitForImport(ch)
+}
+
+// renderSequenceResults renders each request of the last sequence run with
+// its status, the values it extracted and any failed tests
+func renderSequenceResults(run *collections.SequenceRun) string {
+	passStyle := lipgloss.NewStyle().Foreground(palette.Success)
+	failStyle := lipgloss.NewStyle().Foreground(palette.Error)
+	headerStyle := lipgloss.NewStyle().Foreground(palette.Info).Bold(true)
+	noteStyle := lipgloss.NewStyle().Foreground(palette.Muted)
+
+	passed, failed := run.Summary()
+	summary := fmt.Sprintf("%s: %s, %s in %v",
+		run.CollectionName,
+		passStyle.Render(fmt.Sprintf("%d passed", passed)),
+		failStyle.Render(fmt.Sprintf("%d failed", failed)),
+		run.Elapsed.Truncate(time.Millisecond))
+	if len(run.NotRun) > 0 {
+		summary += ", stopped at the first failure"
+	}
+	lines := []string{
+		summary,
+		"",
+		headerStyle.Render(fmt.Sprintf("%-3s %-8s %-10s %s", "#", "Status", "Duration", "Request")),
+	}
+
+	for i, result := range run.Results {
+		status := fmt.Sprintf("%d", result.StatusCode)
+		if result.StatusCode == 0 {
+			status = "ERR"
+		}
+		style := passStyle
+		if !result.Passed() {
+			style = failStyle
+		}
+
+		lines = append(lines, fmt.Sprintf("%-3d %s %-10v %s %s",
+			i+1,
+			style.Render(fmt.Sprintf("%-8s", status)),
+			result.Duration.Truncate(time.Millisecond),
+			result.Method,
+			result.RequestName))
+
+		names := make([]string, 0, len(result.Extracted))
+		for name := range result.Extracted {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			lines = append(lines, noteStyle.Render(fmt.Sprintf("      %s = %s", name, truncateValue(result.Extracted[name], 40))))
+		}
+		for _, test := range result.Tests {
+			if !test.Passed {
+				lines = append(lines, failStyle.Render(fmt.Sprintf("      ✗ %s: %s", test.Test, test.Message)))
+			}
+		}
+		if result.Error != "" {
+			lines = append(lines, failStyle.Render("      "+result.Error))
+		}
+	}
+
+	if len(run.NotRun) > 0 {
+		lines = append(lines, "", noteStyle.Render("Not run: "+strings.Join(run.NotRun, ", ")))
+	}
+
+	return strings.Join(lines, "\n")
+}
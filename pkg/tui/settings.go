@@ -0,0 +1,324 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"onioncli/pkg/config"
+)
+
+// Settings screen fields, in tab order
+const (
+	settingProxyAddr = iota
+	settingProxyPort
+	settingHTTPTimeout
+	settingFollowRedirects
+	settingAutoRetry
+	settingVerifySSL
+	settingUserAgent
+	settingTheme
+	settingCount
+)
+
+// settingsValues are the values entered on the settings screen
+type settingsValues struct {
+	proxyAddr       string
+	proxyPort       int
+	httpTimeout     int // seconds
+	followRedirects bool
+	autoRetry       bool
+	verifySSL       bool
+	userAgent       string
+	theme           string
+}
+
+// SaveSettingsMsg asks for the entered settings to be validated and saved
+type SaveSettingsMsg struct {
+	values settingsValues
+}
+
+// SettingsView edits the commonly changed parts of the configuration
+type SettingsView struct {
+	proxyAddrInput   textinput.Model
+	proxyPortInput   textinput.Model
+	httpTimeoutInput textinput.Model
+	userAgentInput   textinput.Model
+	followRedirects  bool
+	autoRetry        bool
+	verifySSL        bool
+	theme            string
+	focusedField     int
+	err              string // why the last save was rejected
+}
+
+// NewSettingsView creates a new settings screen
+func NewSettingsView() SettingsView {
+	proxyAddrInput := textinput.New()
+	proxyAddrInput.Placeholder = "127.0.0.1"
+	proxyAddrInput.CharLimit = 253
+	proxyAddrInput.Width = 40
+
+	proxyPortInput := textinput.New()
+	proxyPortInput.Placeholder = "9050"
+	proxyPortInput.CharLimit = 5
+	proxyPortInput.Width = 10
+
+	httpTimeoutInput := textinput.New()
+	httpTimeoutInput.Placeholder = "30"
+	httpTimeoutInput.CharLimit = 6
+	httpTimeoutInput.Width = 10
+
+	userAgentInput := textinput.New()
+	userAgentInput.Placeholder = "OnionCLI/1.0"
+	userAgentInput.CharLimit = 200
+	userAgentInput.Width = 50
+
+	return SettingsView{
+		proxyAddrInput:   proxyAddrInput,
+		proxyPortInput:   proxyPortInput,
+		httpTimeoutInput: httpTimeoutInput,
+		userAgentInput:   userAgentInput,
+	}
+}
+
+// Load fills the fields from the current configuration
+func (s *SettingsView) Load(cfg *config.Config) {
+	s.proxyAddrInput.SetValue(cfg.Tor.ProxyAddr)
+	s.proxyPortInput.SetValue(strconv.Itoa(cfg.Tor.ProxyPort))
+	s.httpTimeoutInput.SetValue(strconv.Itoa(cfg.HTTP.Timeout))
+	s.userAgentInput.SetValue(cfg.HTTP.UserAgent)
+	s.followRedirects = cfg.HTTP.FollowRedirects
+	s.autoRetry = cfg.HTTP.AutoRetry
+	s.verifySSL = cfg.HTTP.VerifySSL
+	s.theme = cfg.UI.Theme
+	s.err = ""
+	s.focusedField = settingProxyAddr
+	s.updateFocus()
+}
+
+// SetError shows why saving failed
+func (s *SettingsView) SetError(err error) {
+	s.err = err.Error()
+}
+
+// Update handles keys on the settings screen. Space flips the focused
+// toggle or cycles the theme, previewing it, Enter or Ctrl+S saves.
+func (s SettingsView) Update(msg tea.Msg) (SettingsView, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "tab", "down":
+			s.focusedField = (s.focusedField + 1) % settingCount
+			s.updateFocus()
+			return s, nil
+		case "shift+tab", "up":
+			s.focusedField = (s.focusedField + settingCount - 1) % settingCount
+			s.updateFocus()
+			return s, nil
+		case "enter", "ctrl+s":
+			return s.save()
+		case " ":
+			switch s.focusedField {
+			case settingFollowRedirects:
+				s.followRedirects = !s.followRedirects
+				return s, nil
+			case settingAutoRetry:
+				s.autoRetry = !s.autoRetry
+				return s, nil
+			case settingVerifySSL:
+				s.verifySSL = !s.verifySSL
+				return s, nil
+			case settingTheme:
+				s.theme = nextTheme(s.theme)
+				applyTheme(s.theme)
+				return s, nil
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	switch s.focusedField {
+	case settingProxyAddr:
+		s.proxyAddrInput, cmd = s.proxyAddrInput.Update(msg)
+	case settingProxyPort:
+		s.proxyPortInput, cmd = s.proxyPortInput.Update(msg)
+	case settingHTTPTimeout:
+		s.httpTimeoutInput, cmd = s.httpTimeoutInput.Update(msg)
+	case settingUserAgent:
+		s.userAgentInput, cmd = s.userAgentInput.Update(msg)
+	}
+	return s, cmd
+}
+
+// save checks the fields can be read and emits a SaveSettingsMsg; range
+// checks are left to config.Manager.Validate
+func (s SettingsView) save() (SettingsView, tea.Cmd) {
+	values, err := s.values()
+	if err != nil {
+		s.err = err.Error()
+		return s, nil
+	}
+	s.err = ""
+	return s, func() tea.Msg { return SaveSettingsMsg{values: values} }
+}
+
+// values reads the entered settings
+func (s SettingsView) values() (settingsValues, error) {
+	proxyAddr := strings.TrimSpace(s.proxyAddrInput.Value())
+	if proxyAddr == "" {
+		return settingsValues{}, fmt.Errorf("Tor proxy address is required")
+	}
+	proxyPort, err := strconv.Atoi(strings.TrimSpace(s.proxyPortInput.Value()))
+	if err != nil {
+		return settingsValues{}, fmt.Errorf("Tor proxy port must be a number")
+	}
+	httpTimeout, err := strconv.Atoi(strings.TrimSpace(s.httpTimeoutInput.Value()))
+	if err != nil {
+		return settingsValues{}, fmt.Errorf("HTTP timeout must be a whole number of seconds")
+	}
+
+	return settingsValues{
+		proxyAddr:       proxyAddr,
+		proxyPort:       proxyPort,
+		httpTimeout:     httpTimeout,
+		followRedirects: s.followRedirects,
+		autoRetry:       s.autoRetry,
+		verifySSL:       s.verifySSL,
+		userAgent:       strings.TrimSpace(s.userAgentInput.Value()),
+		theme:           s.theme,
+	}, nil
+}
+
+// updateFocus focuses the text input of the focused field, if it has one
+func (s *SettingsView) updateFocus() {
+	s.proxyAddrInput.Blur()
+	s.proxyPortInput.Blur()
+	s.httpTimeoutInput.Blur()
+	s.userAgentInput.Blur()
+
+	switch s.focusedField {
+	case settingProxyAddr:
+		s.proxyAddrInput.Focus()
+	case settingProxyPort:
+		s.proxyPortInput.Focus()
+	case settingHTTPTimeout:
+		s.httpTimeoutInput.Focus()
+	case settingUserAgent:
+		s.userAgentInput.Focus()
+	}
+}
+
+// View renders the settings screen
+func (s SettingsView) View() string {
+	var sections []string
+	sections = append(sections, titleStyle.Render("Settings"))
+
+	fields := []struct {
+		label string
+		view  string
+	}{
+		{"Tor proxy address:", s.proxyAddrInput.View()},
+		{"Tor proxy port:", s.proxyPortInput.View()},
+		{"HTTP timeout (seconds):", s.httpTimeoutInput.View()},
+		{"Follow redirects:", checkbox(s.followRedirects)},
+		{"Retry network/Tor failures:", checkbox(s.autoRetry)},
+		{"Verify SSL certificates:", checkbox(s.verifySSL)},
+		{"User-Agent:", s.userAgentInput.View()},
+		{"Theme:", "< " + s.theme + " >"},
+	}
+	for i, field := range fields {
+		style := blurredStyle
+		if s.focusedField == i {
+			style = focusedStyle
+		}
+		sections = append(sections, style.Render(fmt.Sprintf("%s %s", field.label, field.view)))
+	}
+
+	if s.err != "" {
+		sections = append(sections, errorStyle.Render(s.err))
+	}
+	sections = append(sections, helpStyle.Render("Tab/↑/↓ to move, Space to toggle, Enter to save, Esc to cancel"))
+
+	content := strings.Join(sections, "\n")
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(palette.Primary).
+		Padding(1).
+		Render(content)
+}
+
+// checkbox renders a boolean setting
+func checkbox(on bool) string {
+	if on {
+		return "[x]"
+	}
+	return "[ ]"
+}
+
+// nextTheme cycles through the known themes, starting over from an
+// unknown one
+func nextTheme(theme string) string {
+	for i, name := range themes {
+		if name == theme {
+			return themes[(i+1)%len(themes)]
+		}
+	}
+	return themes[0]
+}
+
+// openSettings shows the settings screen filled from the current config
+func (m Model) openSettings() Model {
+	m.settingsView.Load(m.configManager.Get())
+	m.state = StateSettings
+	return m
+}
+
+// applySettings updates and validates the configuration, keeping the
+// previous one if it is rejected, then saves it. The theme takes effect
+// straight away; a changed proxy, timeout, redirect, retry, SSL or
+// User-Agent setting rebuilds the client so the next request uses it.
+func (m Model) applySettings(values settingsValues) (Model, tea.Cmd) {
+	cfg := m.configManager.Get()
+	previous := *cfg
+
+	m.configManager.UpdateTorSettings(cfg.Tor.Enabled, values.proxyAddr, values.proxyPort, cfg.Tor.Timeout)
+	m.configManager.UpdateHTTPSettings(values.httpTimeout, values.followRedirects, cfg.HTTP.MaxRedirects, values.verifySSL, values.userAgent)
+	cfg.HTTP.AutoRetry = values.autoRetry
+	m.configManager.UpdateUISettings(values.theme, cfg.UI.ShowLineNumbers, cfg.UI.AutoSave, cfg.UI.ConfirmExit)
+	if err := m.configManager.Validate(); err != nil {
+		m.configManager.Set(&previous)
+		m.settingsView.SetError(err)
+		return m, nil
+	}
+	if err := m.configManager.Save(); err != nil {
+		m.configManager.Set(&previous)
+		m.settingsView.SetError(fmt.Errorf("failed to save settings: %w", err))
+		return m, nil
+	}
+
+	applyTheme(values.theme)
+	m.state = StateRequestBuilder
+	if values.proxyAddr == previous.Tor.ProxyAddr && values.proxyPort == previous.Tor.ProxyPort &&
+		values.httpTimeout == previous.HTTP.Timeout && values.followRedirects == previous.HTTP.FollowRedirects &&
+		values.autoRetry == previous.HTTP.AutoRetry && values.verifySSL == previous.HTTP.VerifySSL &&
+		values.userAgent == previous.HTTP.UserAgent {
+		m.statusIndicator.Show("Settings saved", StatusSuccess)
+		return m, nil
+	}
+
+	client, err := NewClient(m.configManager)
+	if err != nil {
+		m.statusIndicator.Show(fmt.Sprintf("Settings saved, but the client could not be rebuilt: %v", err), StatusError)
+		return m, nil
+	}
+	client.KeepCookies(m.client)
+	m.client = client
+	m.collectionsViewer.client = client
+	m.authManager.SetClient(client)
+	m.statusIndicator.Show("Settings saved, requests now use "+client.GetTorProxy(), StatusSuccess)
+	return m, nil
+}
@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"testing"
+
+	"onioncli/pkg/config"
+)
+
+func TestSettingsValues(t *testing.T) {
+	cfg := &config.Config{
+		Tor:  config.TorConfig{ProxyAddr: "127.0.0.1", ProxyPort: 9050},
+		HTTP: config.HTTPConfig{Timeout: 30, FollowRedirects: true, UserAgent: "OnionCLI/1.0"},
+		UI:   config.UIConfig{Theme: "dark"},
+	}
+
+	tests := []struct {
+		name    string
+		edit    func(s *SettingsView)
+		want    settingsValues
+		wantErr bool
+	}{
+		{
+			name: "loaded values",
+			edit: func(s *SettingsView) {},
+			want: settingsValues{proxyAddr: "127.0.0.1", proxyPort: 9050, httpTimeout: 30, followRedirects: true, userAgent: "OnionCLI/1.0", theme: "dark"},
+		},
+		{
+			name: "edited values are trimmed",
+			edit: func(s *SettingsView) {
+				s.proxyAddrInput.SetValue(" 10.0.0.2 ")
+				s.proxyPortInput.SetValue("9150 ")
+				s.httpTimeoutInput.SetValue("60")
+				s.verifySSL = true
+				s.autoRetry = true
+				s.theme = nextTheme(s.theme)
+			},
+			want: settingsValues{proxyAddr: "10.0.0.2", proxyPort: 9150, httpTimeout: 60, followRedirects: true, autoRetry: true, verifySSL: true, userAgent: "OnionCLI/1.0", theme: "light"},
+		},
+		{
+			name:    "missing proxy address",
+			edit:    func(s *SettingsView) { s.proxyAddrInput.SetValue("  ") },
+			wantErr: true,
+		},
+		{
+			name:    "port is not a number",
+			edit:    func(s *SettingsView) { s.proxyPortInput.SetValue("tor") },
+			wantErr: true,
+		},
+		{
+			name:    "timeout is not a number",
+			edit:    func(s *SettingsView) { s.httpTimeoutInput.SetValue("30s") },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSettingsView()
+			s.Load(cfg)
+			tt.edit(&s)
+
+			got, err := s.values()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("values() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("values() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextTheme(t *testing.T) {
+	tests := map[string]string{"dark": "light", "light": "high-contrast", "high-contrast": "dark", "": "dark", "solarized": "dark"}
+	for theme, want := range tests {
+		if got := nextTheme(theme); got != want {
+			t.Errorf("nextTheme(%q) = %q, want %q", theme, got, want)
+		}
+	}
+}
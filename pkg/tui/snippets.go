@@ -0,0 +1,206 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"onioncli/pkg/collections"
+)
+
+// SnippetsMenu lists saved body snippets for quick insertion into the body
+// editor, and saves the current body as a new snippet
+type SnippetsMenu struct {
+	manager  *collections.Manager
+	snippets []collections.Snippet
+	cursor   int
+	body     string // body editor contents when opened, for saving
+	naming   bool
+	input    textinput.Model
+	message  string
+	visible  bool
+}
+
+// NewSnippetsMenu creates a new snippets menu
+func NewSnippetsMenu(manager *collections.Manager) SnippetsMenu {
+	input := textinput.New()
+	input.Placeholder = "Snippet name"
+	input.CharLimit = 100
+	input.Width = 30
+
+	return SnippetsMenu{
+		manager: manager,
+		input:   input,
+		visible: false,
+	}
+}
+
+// Show opens the menu; body is what "save" stores
+func (sm *SnippetsMenu) Show(body string) {
+	sm.body = body
+	sm.cursor = 0
+	sm.naming = false
+	sm.message = ""
+	sm.visible = true
+	sm.refresh()
+}
+
+// Hide closes the menu
+func (sm *SnippetsMenu) Hide() {
+	sm.visible = false
+	sm.naming = false
+	sm.input.Blur()
+}
+
+// refresh reloads the snippet list
+func (sm *SnippetsMenu) refresh() {
+	snippets, err := sm.manager.Snippets()
+	if err != nil {
+		sm.message = fmt.Sprintf("Failed to load snippets: %v", err)
+	}
+	sm.snippets = snippets
+	if sm.cursor >= len(sm.snippets) {
+		sm.cursor = max(len(sm.snippets)-1, 0)
+	}
+}
+
+// Update handles menu key presses. It returns the chosen snippet once Enter
+// is pressed on one.
+func (sm SnippetsMenu) Update(msg tea.Msg) (SnippetsMenu, *collections.Snippet, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !sm.visible || !ok {
+		if sm.naming {
+			var cmd tea.Cmd
+			sm.input, cmd = sm.input.Update(msg)
+			return sm, nil, cmd
+		}
+		return sm, nil, nil
+	}
+
+	if sm.naming {
+		switch keyMsg.String() {
+		case "enter":
+			snippet, err := sm.manager.SaveSnippet(sm.input.Value(), sm.body)
+			if err != nil {
+				sm.message = fmt.Sprintf("Failed to save: %v", err)
+				return sm, nil, nil
+			}
+			sm.naming = false
+			sm.input.Blur()
+			sm.refresh()
+			sm.message = fmt.Sprintf("Saved snippet %q", snippet.Name)
+			return sm, nil, nil
+		case "esc":
+			sm.naming = false
+			sm.input.Blur()
+			return sm, nil, nil
+		}
+		var cmd tea.Cmd
+		sm.input, cmd = sm.input.Update(msg)
+		return sm, nil, cmd
+	}
+
+	switch keyMsg.String() {
+	case "up", "k":
+		if sm.cursor > 0 {
+			sm.cursor--
+		}
+	case "down", "j":
+		if sm.cursor < len(sm.snippets)-1 {
+			sm.cursor++
+		}
+	case "enter":
+		if len(sm.snippets) == 0 {
+			return sm, nil, nil
+		}
+		snippet := sm.snippets[sm.cursor]
+		sm.Hide()
+		return sm, &snippet, nil
+	case "s":
+		sm.message = ""
+		sm.input.SetValue("")
+		sm.input.Focus()
+		sm.naming = true
+		return sm, nil, textinput.Blink
+	case "d":
+		if len(sm.snippets) == 0 {
+			return sm, nil, nil
+		}
+		snippet := sm.snippets[sm.cursor]
+		if err := sm.manager.DeleteSnippet(snippet.ID); err != nil {
+			sm.message = fmt.Sprintf("Failed to delete: %v", err)
+		} else {
+			sm.message = fmt.Sprintf("Deleted snippet %q", snippet.Name)
+		}
+		sm.refresh()
+	case "esc", "ctrl+y":
+		sm.Hide()
+	}
+
+	return sm, nil, nil
+}
+
+// View renders the menu
+func (sm SnippetsMenu) View() string {
+	if !sm.visible {
+		return ""
+	}
+
+	nameStyle := lipgloss.NewStyle().Foreground(palette.Info)
+	cursorStyle := lipgloss.NewStyle().Foreground(palette.Primary).Bold(true)
+
+	lines := []string{titleStyle.Render("Body Snippets")}
+	if len(sm.snippets) == 0 {
+		lines = append(lines, helpStyle.Render("No snippets yet - press s to save the current body"))
+	}
+	for i, snippet := range sm.snippets {
+		prefix := "  "
+		if i == sm.cursor {
+			prefix = cursorStyle.Render("> ")
+		}
+		preview := strings.Join(strings.Fields(snippet.Body), " ")
+		lines = append(lines, prefix+nameStyle.Render(snippet.Name)+"  "+truncateValue(preview, 24))
+	}
+
+	if sm.naming {
+		lines = append(lines, "", "Save body as: "+sm.input.View())
+	}
+	if sm.message != "" {
+		lines = append(lines, "", statusStyle.Render(sm.message))
+	}
+
+	help := "enter insert • s save body • d delete • esc close"
+	if sm.naming {
+		help = "enter save • esc cancel"
+	}
+	lines = append(lines, helpStyle.Render(help))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(palette.Primary).
+		Padding(0, 1).
+		Width(44).
+		Render(strings.Join(lines, "\n"))
+}
+
+// IsVisible returns whether the menu is open
+func (sm SnippetsMenu) IsVisible() bool {
+	return sm.visible
+}
+
+// insertSnippet inserts a snippet at the body editor's cursor, with variables
+// resolved against the active environment
+func (m Model) insertSnippet(snippet collections.Snippet) Model {
+	body, unresolved := m.collectionsManager.ResolveSnippet(snippet)
+	m.bodyArea.InsertString(body)
+
+	if len(unresolved) > 0 {
+		m.statusIndicator.Show(fmt.Sprintf("Inserted %q; unresolved: %s", snippet.Name, strings.Join(unresolved, ", ")), StatusWarning)
+	} else {
+		m.statusIndicator.Show(fmt.Sprintf("Inserted snippet %q", snippet.Name), StatusInfo)
+	}
+	return m
+}
@@ -0,0 +1,132 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"onioncli/pkg/history"
+)
+
+// maxLatencySamples is how many recent requests the status bar sparkline shows
+const maxLatencySamples = 20
+
+// sparkBlocks are the sparkline levels, lowest first
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// latencySample is the outcome of one sent request
+type latencySample struct {
+	duration time.Duration
+	failed   bool
+}
+
+// recordLatency adds the outcome of a request to url to the sparkline,
+// dropping the oldest once the window is full. A request to another URL
+// than the last starts the sparkline over from that URL's history.
+func (m Model) recordLatency(url string, duration time.Duration, failed bool) Model {
+	if url != m.latencyURL {
+		m.latencySamples = historyLatency(m.historyManager.GetEntries(), url)
+		m.latencyURL = url
+	}
+	samples := append(m.latencySamples, latencySample{duration: duration, failed: failed})
+	if len(samples) > maxLatencySamples {
+		samples = samples[len(samples)-maxLatencySamples:]
+	}
+	m.latencySamples = samples
+	return m
+}
+
+// historyLatency returns the samples of the stored responses to url in
+// entries, which are newest first, oldest first and leaving room for the
+// request being recorded. Server errors count as failed.
+func historyLatency(entries []history.HistoryEntry, url string) []latencySample {
+	var samples []latencySample
+	for _, entry := range entries {
+		if len(samples) == maxLatencySamples-1 {
+			break
+		}
+		if entry.URL != url || entry.Response == nil {
+			continue
+		}
+		samples = append(samples, latencySample{
+			duration: time.Duration(entry.Response.DurationMS) * time.Millisecond,
+			failed:   entry.Response.StatusCode >= 500,
+		})
+	}
+	slices.Reverse(samples)
+	return samples
+}
+
+// latencyColor picks a health colour: green when fast, through yellow and
+// orange, to red for slow or failed requests
+func latencyColor(sample latencySample) lipgloss.Color {
+	switch {
+	case sample.failed || sample.duration >= 10*time.Second:
+		return palette.Error
+	case sample.duration >= 5*time.Second:
+		return palette.Warning
+	case sample.duration >= 2*time.Second:
+		return palette.Highlight
+	default:
+		return palette.Success
+	}
+}
+
+// renderSparkline renders recent request durations as a row of block
+// characters scaled to the slowest request, with the latest duration
+func (m Model) renderSparkline() string {
+	samples := m.latencySamples
+	if len(samples) == 0 {
+		return ""
+	}
+
+	var slowest time.Duration
+	failed := 0
+	for _, sample := range samples {
+		if sample.duration > slowest {
+			slowest = sample.duration
+		}
+		if sample.failed {
+			failed++
+		}
+	}
+	latest := samples[len(samples)-1]
+
+	if m.configManager.Get().UI.Accessible {
+		summary := fmt.Sprintf("Recent requests: %d, median %s, slowest %s",
+			len(samples), describeDuration(medianLatency(samples)), describeDuration(slowest))
+		if failed > 0 {
+			summary += fmt.Sprintf(", %d failed", failed)
+		}
+		return summary + "."
+	}
+
+	var spark strings.Builder
+	for _, sample := range samples {
+		level := len(sparkBlocks) - 1
+		if !sample.failed && slowest > 0 {
+			level = int(float64(sample.duration) / float64(slowest) * float64(len(sparkBlocks)-1))
+		}
+		spark.WriteString(lipgloss.NewStyle().Foreground(latencyColor(sample)).Render(string(sparkBlocks[level])))
+	}
+
+	last := latest.duration.Truncate(10 * time.Millisecond).String()
+	if latest.failed {
+		last = "failed"
+	}
+	return helpStyle.UnsetMargins().Render("Recent: ") + spark.String() + helpStyle.UnsetMargins().Render(" "+last)
+}
+
+// medianLatency returns the median duration of the samples
+func medianLatency(samples []latencySample) time.Duration {
+	durations := make([]time.Duration, len(samples))
+	for i, sample := range samples {
+		durations[i] = sample.duration
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	return durations[len(durations)/2]
+}
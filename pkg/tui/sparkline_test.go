@@ -0,0 +1,39 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"onioncli/pkg/history"
+)
+
+func TestHistoryLatency(t *testing.T) {
+	const url = "http://example.onion/api"
+	entries := []history.HistoryEntry{ // newest first
+		{URL: url, Response: &history.ResponseSnapshot{StatusCode: 503, DurationMS: 900}},
+		{URL: "http://other.onion/", Response: &history.ResponseSnapshot{StatusCode: 200, DurationMS: 50}},
+		{URL: url}, // saved without a response
+		{URL: url, Response: &history.ResponseSnapshot{StatusCode: 200, DurationMS: 120}},
+	}
+
+	want := []latencySample{
+		{duration: 120 * time.Millisecond},
+		{duration: 900 * time.Millisecond, failed: true},
+	}
+	if got := historyLatency(entries, url); !reflect.DeepEqual(got, want) {
+		t.Errorf("historyLatency() = %v, want %v", got, want)
+	}
+
+	var many []history.HistoryEntry
+	for i := 0; i < 2*maxLatencySamples; i++ {
+		many = append(many, history.HistoryEntry{URL: url, Response: &history.ResponseSnapshot{StatusCode: 200, DurationMS: int64(i)}})
+	}
+	got := historyLatency(many, url)
+	if len(got) != maxLatencySamples-1 {
+		t.Fatalf("Expected room left for the new sample, got %d samples", len(got))
+	}
+	if got[len(got)-1].duration != 0 {
+		t.Errorf("Expected the newest entry last, got %v", got[len(got)-1].duration)
+	}
+}
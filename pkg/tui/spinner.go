@@ -22,14 +22,11 @@ type LoadingSpinner struct {
 func NewLoadingSpinner() LoadingSpinner {
 	s := spinner.New()
 	s.Spinner = spinner.Dot
-	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4"))
 
 	return LoadingSpinner{
 		spinner: s,
 		visible: false,
-		style: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#8BE9FD")).
-			Margin(0, 1),
+		style:   lipgloss.NewStyle().Margin(0, 1),
 	}
 }
 
@@ -63,7 +60,9 @@ func (ls LoadingSpinner) View() string {
 		return ""
 	}
 
-	return ls.style.Render(ls.spinner.View() + " " + ls.message)
+	dot := ls.spinner
+	dot.Style = lipgloss.NewStyle().Foreground(palette.Primary)
+	return ls.style.Foreground(palette.Info).Render(dot.View() + " " + ls.message)
 }
 
 // IsVisible returns whether the spinner is currently visible
@@ -84,9 +83,7 @@ type ProgressIndicator struct {
 func NewProgressIndicator() ProgressIndicator {
 	return ProgressIndicator{
 		visible: false,
-		style: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#50FA7B")).
-			Margin(0, 1),
+		style:   lipgloss.NewStyle().Margin(0, 1),
 	}
 }
 
@@ -139,7 +136,10 @@ func (pi ProgressIndicator) View() string {
 
 	// Create a simple progress bar
 	barWidth := 20
-	filled := (pi.current * barWidth) / pi.total
+	filled := 0
+	if pi.total > 0 {
+		filled = (pi.current * barWidth) / pi.total
+	}
 	if filled > barWidth {
 		filled = barWidth
 	}
@@ -153,7 +153,7 @@ func (pi ProgressIndicator) View() string {
 		}
 	}
 
-	return pi.style.Render(
+	return pi.style.Foreground(palette.Success).Render(
 		fmt.Sprintf("%s [%s] %d%% (%d/%d)",
 			pi.message, bar, percentage, pi.current, pi.total))
 }
@@ -175,6 +175,7 @@ type StatusIndicator struct {
 	visible   bool
 	timestamp time.Time
 	timeout   time.Duration
+	plain     bool // accessible mode: text labels instead of icons and colours
 }
 
 // StatusType represents different types of status messages
@@ -210,6 +211,11 @@ func (si *StatusIndicator) ShowWithTimeout(message string, status StatusType, ti
 	si.timeout = timeout
 }
 
+// SetAccessible switches accessible plain-text rendering on or off
+func (si *StatusIndicator) SetAccessible(accessible bool) {
+	si.plain = accessible
+}
+
 // Hide hides the status indicator
 func (si *StatusIndicator) Hide() {
 	si.visible = false
@@ -232,28 +238,32 @@ func (si StatusIndicator) View() string {
 		return ""
 	}
 
+	if si.plain {
+		return fmt.Sprintf("%s: %s", statusLabels[si.status], si.message)
+	}
+
 	var icon string
 	var style lipgloss.Style
 
 	switch si.status {
 	case StatusInfo:
 		icon = "ℹ️"
-		style = lipgloss.NewStyle().Foreground(lipgloss.Color("#8BE9FD"))
+		style = lipgloss.NewStyle().Foreground(palette.Info)
 	case StatusSuccess:
 		icon = "✅"
-		style = lipgloss.NewStyle().Foreground(lipgloss.Color("#50FA7B"))
+		style = lipgloss.NewStyle().Foreground(palette.Success)
 	case StatusWarning:
 		icon = "⚠️"
-		style = lipgloss.NewStyle().Foreground(lipgloss.Color("#F1FA8C"))
+		style = lipgloss.NewStyle().Foreground(palette.Highlight)
 	case StatusError:
 		icon = "❌"
-		style = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5555"))
+		style = lipgloss.NewStyle().Foreground(palette.Error)
 	case StatusLoading:
 		icon = "⏳"
-		style = lipgloss.NewStyle().Foreground(lipgloss.Color("#BD93F9"))
+		style = lipgloss.NewStyle().Foreground(palette.Accent)
 	default:
 		icon = "•"
-		style = lipgloss.NewStyle().Foreground(lipgloss.Color("#F8F8F2"))
+		style = lipgloss.NewStyle().Foreground(palette.Text)
 	}
 
 	return style.Render(fmt.Sprintf("%s %s", icon, si.message))
@@ -264,40 +274,164 @@ func (si StatusIndicator) IsVisible() bool {
 	return si.visible
 }
 
+// shortcut is a single key binding shown in the help overlay
+type shortcut struct {
+	key         string
+	description string
+}
+
 // KeyboardShortcuts provides a help display for keyboard shortcuts
 type KeyboardShortcuts struct {
-	shortcuts map[string]string
+	shortcuts map[AppState][]shortcut
+	keymap    []shortcut // the keymap's keys, shown on every screen
+	state     AppState
 	visible   bool
 	style     lipgloss.Style
 }
 
 // NewKeyboardShortcuts creates a new keyboard shortcuts helper
 func NewKeyboardShortcuts() KeyboardShortcuts {
-	shortcuts := map[string]string{
-		"Tab/Shift+Tab": "Navigate fields",
-		"Enter":         "Send request / Select",
-		"Esc":           "Go back / Cancel",
-		"h":             "View history",
-		"a":             "Configure auth",
-		"s":             "Save request",
-		"e":             "View error details",
-		"c":             "Settings",
-		"r":             "Retry request",
-		"Ctrl+C/q":      "Quit",
-		"?":             "Toggle help",
+	shortcuts := map[AppState][]shortcut{
+		StateRequestBuilder: {
+			{"Tab/Shift+Tab", "Navigate fields"},
+			{"Ctrl+G u/q/m/h/b/s", "Jump to URL/Query/Method/Headers/Body/Submit"},
+			{"Enter/Ctrl+Enter", "Send request (connect for ws:// and wss:// URLs)"},
+			{"Ctrl+T/Ctrl+W", "Open / close a request tab"},
+			{"Ctrl+Tab/Ctrl+PgDn", "Next tab (Ctrl+PgUp: previous)"},
+			{"d", "Dry run: check the request without sending"},
+			{"K", "Show or clear cookies"},
+			{"Ctrl+L", "Diff body against last sent version"},
+			{"Ctrl+O", "Show/edit environment variables"},
+			{"Ctrl+E", "Preview substitution in headers/body too"},
+			{"Ctrl+R", "Insert {{var}} references into headers/body"},
+			{"Ctrl+Y", "Insert, save or delete body snippets"},
+			{"Ctrl+F", "Switch the body between raw and multipart form"},
+			{"Ctrl+J", "Pretty-print the body's JSON or show where it is invalid"},
+			{"Ctrl+P", "Command palette (also picks Accept / Accept-Encoding)"},
+			{"Ctrl+N", "New Tor circuit (control port)"},
+			{"Ctrl+X", "Switch the URL between http:// and https:// (ws:// and wss://)"},
+			{"h", "View history"},
+			{"c", "Browse collections"},
+			{"v", "Manage environments"},
+			{",", "Edit settings"},
+			{"i", "Import a cURL command (or paste one into the URL)"},
+			{"C", "Copy the request as a cURL command"},
+			{"E", "Send to every environment and compare"},
+			{"a", "Configure auth"},
+			{"s/Ctrl+S", "Save request to history"},
+			{"S", "Save request to a collection"},
+			{"r", "Retry request"},
+			{"n", "New request (clear form)"},
+			{"x", "Discard the saved draft and clear the form"},
+			{"e", "View error details"},
+			{"Esc", "Clear messages / Cancel request"},
+			{"?", "Toggle help"},
+			{"Ctrl+C/q", "Quit"},
+		},
+		StateResponse: {
+			{"↑/↓", "Scroll response"},
+			{"PgUp/PgDn", "Scroll by page"},
+			{"/", "Search the response (Alt+C toggles case)"},
+			{"n/N", "Next / previous match; Esc clears the search"},
+			{"b", "Use response body as a new request body"},
+			{"Ctrl+T/Ctrl+W", "Open / close a request tab"},
+			{"Ctrl+Tab/Ctrl+PgDn", "Next tab (Ctrl+PgUp: previous)"},
+			{"f", "Toggle flattened JSON path list"},
+			{"h", "Switch an HTML body: highlighted, text, raw"},
+			{"v", "Toggle raw request and response (curl -v style)"},
+			{"m", "Mask or show secrets in the raw exchange"},
+			{"o", "Open body (or path list) in $PAGER/$EDITOR"},
+			{"w", "Save the body to a file"},
+			{"c", "Copy the request as a cURL command"},
+			{"a", "Toggle auto-retry after a 429 rate limit"},
+			{"t", "Resend asking for JSON or XML"},
+			{"p", "Pin response to compare with the next one"},
+			{"u", "Unpin response"},
+			{"s", "Audit response headers for security issues"},
+			{"K", "Show or clear cookies"},
+			{"S", "Save the request to a collection"},
+			{"y/n", "Follow or stop a paused redirect (http.interactive_redirects)"},
+			{"y/x", "Save or skip on the save prompt (ui.prompt_save)"},
+			{"Esc", "Back to request builder"},
+			{"Ctrl+P", "Command palette"},
+			{"?", "Toggle help"},
+			{"Ctrl+C/q", "Quit"},
+		},
+		StateHistory: {
+			{"↑/↓", "Select entry"},
+			{"Enter", "Load request"},
+			{"/", "Search history"},
+			{"i", "Import requests from a HAR file"},
+			{"e", "Export history as a HAR 1.2 file"},
+			{"f", "Favorite entry (kept past max_entries)"},
+			{"F", "Show only favorites"},
+			{"p", "Compare response with the previous run"},
+			{"v", "View the stored response"},
+			{"r", "Refresh"},
+			{"Space", "Select entry for a bulk delete"},
+			{"d", "Delete selected entries, or the current one (asks y/n)"},
+			{"D", "Delete all entries matching the search"},
+			{"c", "Clear all history"},
+			{"Esc", "Clear selection, or back to request builder"},
+			{"Ctrl+P", "Command palette"},
+			{"?", "Toggle help"},
+			{"Ctrl+C/q", "Quit"},
+		},
+		StateCollections: {
+			{"↑/↓", "Select collection or request"},
+			{"Enter", "Open collection / Load request"},
+			{"n", "New collection"},
+			{"i", "Import collection"},
+			{"D", "Data-driven run of request from CSV"},
+			{"S", "Run collection requests in sequence"},
+			{"c", "Toggle continue on error for sequence runs"},
+			{"R", "Show last run results"},
+			{"H", "Edit collection default headers"},
+			{"d", "Delete collection"},
+			{"r", "Refresh"},
+			{"Backspace", "Back to collections"},
+			{"Esc", "Back / Cancel import or run"},
+			{"Ctrl+P", "Command palette"},
+			{"?", "Toggle help"},
+			{"Ctrl+C/q", "Quit"},
+		},
+		StateEnvironments: {
+			{"↑/↓", "Select environment"},
+			{"Enter/Space", "Activate environment"},
+			{"n", "New environment"},
+			{"e", "Edit environment"},
+			{"c", "Duplicate environment"},
+			{"d", "Delete environment"},
+			{"u", "Audit variable usage"},
+			{"r", "Refresh"},
+			{"Esc", "Back to request builder"},
+			{"Ctrl+P", "Command palette"},
+			{"?", "Toggle help"},
+			{"Ctrl+C/q", "Quit"},
+		},
 	}
 
 	return KeyboardShortcuts{
 		shortcuts: shortcuts,
+		state:     StateRequestBuilder,
 		visible:   false,
 		style: lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#666666")).
 			Padding(1).
 			Margin(1),
 	}
 }
 
+// SetState selects which screen's shortcuts are shown
+func (ks *KeyboardShortcuts) SetState(state AppState) {
+	ks.state = state
+}
+
+// SetKeymapHelp sets the keymap's keys listed after each screen's own
+func (ks *KeyboardShortcuts) SetKeymapHelp(help []shortcut) {
+	ks.keymap = help
+}
+
 // Toggle toggles the visibility of keyboard shortcuts
 func (ks *KeyboardShortcuts) Toggle() {
 	ks.visible = !ks.visible
@@ -319,20 +453,47 @@ func (ks KeyboardShortcuts) View() string {
 		return ""
 	}
 
+	shortcuts, ok := ks.shortcuts[ks.state]
+	if !ok {
+		shortcuts = ks.shortcuts[StateRequestBuilder]
+	}
+
 	var lines []string
-	lines = append(lines, lipgloss.NewStyle().Bold(true).Render("Keyboard Shortcuts:"))
+	lines = append(lines, lipgloss.NewStyle().Bold(true).Render(
+		fmt.Sprintf("Keyboard Shortcuts (%s):", stateTitle(ks.state))))
 	lines = append(lines, "")
 
-	for key, description := range ks.shortcuts {
-		keyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#8BE9FD")).Bold(true)
-		line := fmt.Sprintf("%s: %s", keyStyle.Render(key), description)
-		lines = append(lines, line)
+	keyStyle := lipgloss.NewStyle().Foreground(palette.Info).Bold(true)
+	for _, sc := range shortcuts {
+		lines = append(lines, fmt.Sprintf("%s: %s", keyStyle.Render(sc.key), sc.description))
+	}
+	if len(ks.keymap) > 0 {
+		lines = append(lines, "", lipgloss.NewStyle().Bold(true).Render("Vim keys:"))
+		for _, sc := range ks.keymap {
+			lines = append(lines, fmt.Sprintf("%s: %s", keyStyle.Render(sc.key), sc.description))
+		}
 	}
 
-	return ks.style.Render(strings.Join(lines, "\n"))
+	return ks.style.BorderForeground(palette.Muted).Render(strings.Join(lines, "\n"))
 }
 
 // IsVisible returns whether the shortcuts are visible
 func (ks KeyboardShortcuts) IsVisible() bool {
 	return ks.visible
 }
+
+// stateTitle returns a short human readable name for a screen
+func stateTitle(state AppState) string {
+	switch state {
+	case StateResponse:
+		return "Response"
+	case StateHistory:
+		return "History"
+	case StateCollections:
+		return "Collections"
+	case StateEnvironments:
+		return "Environments"
+	default:
+		return "Request Builder"
+	}
+}
@@ -0,0 +1,77 @@
+package tui
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"onioncli/pkg/api"
+)
+
+// resultSummary is the outcome of the last completed request, shown as a
+// strip under the request builder once the response view is left
+type resultSummary struct {
+	method   string
+	host     string // where a followed redirect chain ended, if it did
+	response *api.Response
+}
+
+// recordResult keeps the outcome of the request that produced response
+func (m Model) recordResult(response *api.Response) Model {
+	summary := &resultSummary{response: response}
+	target := response.FinalURL
+	if m.currentRequest != nil {
+		summary.method = m.currentRequest.Method
+		if target == "" {
+			target = m.currentRequest.URL
+		}
+	}
+	summary.host = target
+	if u, err := url.Parse(target); err == nil && u.Host != "" {
+		summary.host = u.Host
+	}
+	m.lastResult = summary
+	return m
+}
+
+// statusCodeStyle colours a status by its class: the theme's success colour
+// for 2xx, warning for 4xx, error for 5xx and info for anything else
+func statusCodeStyle(response *api.Response) lipgloss.Style {
+	switch {
+	case response.IsSuccess():
+		return lipgloss.NewStyle().Foreground(palette.Success).Bold(true)
+	case response.IsClientError():
+		return lipgloss.NewStyle().Foreground(palette.Warning).Bold(true)
+	case response.IsServerError():
+		return lipgloss.NewStyle().Foreground(palette.Error).Bold(true)
+	}
+	return lipgloss.NewStyle().Foreground(palette.Info).Bold(true)
+}
+
+// renderResultSummary renders the last result on one line, e.g.
+// "Last: GET example.onion  200 OK  1.2 KiB  350ms"
+func (m Model) renderResultSummary() string {
+	summary := m.lastResult
+	if summary == nil {
+		return ""
+	}
+	response := summary.response
+	size := len(response.BodyBytes())
+
+	if m.configManager.Get().UI.Accessible {
+		return fmt.Sprintf("Last request: %s %s returned %s, %s in %s.",
+			summary.method, summary.host, response.Status, describeSize(size), describeDuration(response.Duration))
+	}
+
+	dim := lipgloss.NewStyle().Foreground(palette.Muted)
+	parts := []string{
+		dim.Render("Last:") + " " + summary.method + " " + truncateValue(summary.host, 40),
+		statusCodeStyle(response).Render(response.Status),
+		formatBytes(int64(size)),
+		lipgloss.NewStyle().Foreground(palette.Highlight).Render(response.Duration.Round(time.Millisecond).String()),
+	}
+	return strings.Join(parts, "  ")
+}
@@ -0,0 +1,32 @@
+package tui
+
+import (
+	"testing"
+
+	"onioncli/pkg/api"
+)
+
+func TestRecordResult(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		finalURL string
+		wantHost string
+	}{
+		{"request host", "http://example.onion/api/items", "", "example.onion"},
+		{"redirect target", "http://example.onion/old", "https://other.onion:8443/new", "other.onion:8443"},
+		{"unparsed URL", "{{base}}/items", "", "{{base}}/items"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := Model{currentRequest: api.NewRequest("post", tt.url)}
+			response := &api.Response{StatusCode: 201, Status: "201 Created", FinalURL: tt.finalURL}
+
+			m = m.recordResult(response)
+			if m.lastResult.method != "POST" || m.lastResult.host != tt.wantHost || m.lastResult.response != response {
+				t.Errorf("recordResult() = %+v, want POST %s", *m.lastResult, tt.wantHost)
+			}
+		})
+	}
+}
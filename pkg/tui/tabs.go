@@ -0,0 +1,262 @@
+package tui
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
+
+	"onioncli/pkg/api"
+	"onioncli/pkg/collections"
+)
+
+// tabLabelWidth caps a tab's label so a few tabs fit across the screen
+const tabLabelWidth = 28
+
+// requestTab is one open request: its builder fields and the response it
+// got. The active tab lives in the Model's own fields, which the rest of the
+// TUI works on; the others are parked here until switched to.
+type requestTab struct {
+	state             AppState // StateRequestBuilder or StateResponse
+	focusedField      FocusedField
+	urlInput          textinput.Model
+	queryArea         textarea.Model
+	headersArea       textarea.Model
+	bodyArea          textarea.Model
+	method            string
+	bodyForm          bool
+	postProcess       []api.PostProcessStep
+	collectionRequest *collections.CollectionRequest
+	currentRequest    *api.Request
+	currentResponse   *api.Response
+	lastResult        *resultSummary
+	responseViewer    ResponseViewer
+	errorAlert        ErrorAlert
+	errorMessage      string
+	urlFixes          []string
+	redirectPath      []redirectHop
+	savePrompt        bool
+	lastSentBody      string
+	hasLastSentBody   bool
+	representation    string
+}
+
+// captureTab parks the active tab's state
+func (m Model) captureTab() requestTab {
+	state := m.state
+	if state != StateResponse {
+		state = StateRequestBuilder
+	}
+	return requestTab{
+		state:             state,
+		focusedField:      m.focusedField,
+		urlInput:          m.urlInput,
+		queryArea:         m.queryArea,
+		headersArea:       m.headersArea,
+		bodyArea:          m.bodyArea,
+		method:            m.currentDraft().Method,
+		bodyForm:          m.bodyForm,
+		postProcess:       m.postProcess,
+		collectionRequest: m.collectionRequest,
+		currentRequest:    m.currentRequest,
+		currentResponse:   m.currentResponse,
+		lastResult:        m.lastResult,
+		responseViewer:    m.responseViewer,
+		errorAlert:        m.errorAlert,
+		errorMessage:      m.errorMessage,
+		urlFixes:          m.urlFixes,
+		redirectPath:      m.redirectPath,
+		savePrompt:        m.savePrompt,
+		lastSentBody:      m.lastSentBody,
+		hasLastSentBody:   m.hasLastSentBody,
+		representation:    m.representation,
+	}
+}
+
+// restoreTab makes a parked tab the active one
+func (m *Model) restoreTab(tab requestTab) {
+	m.state = tab.state
+	m.urlInput = tab.urlInput
+	m.queryArea = tab.queryArea
+	m.headersArea = tab.headersArea
+	m.bodyArea = tab.bodyArea
+	m.selectMethod(tab.method)
+	m.setBodyForm(tab.bodyForm)
+	m.postProcess = tab.postProcess
+	m.collectionRequest = tab.collectionRequest
+	m.currentRequest = tab.currentRequest
+	m.currentResponse = tab.currentResponse
+	m.lastResult = tab.lastResult
+	m.responseViewer = tab.responseViewer
+	m.errorAlert = tab.errorAlert
+	m.errorMessage = tab.errorMessage
+	m.urlFixes = tab.urlFixes
+	m.redirectPath = tab.redirectPath
+	m.savePrompt = tab.savePrompt
+	m.lastSentBody = tab.lastSentBody
+	m.hasLastSentBody = tab.hasLastSentBody
+	m.representation = tab.representation
+
+	// The window may have been resized while the tab was parked
+	*m = m.resizeResponseViewers()
+	*m = m.focusField(tab.focusedField)
+}
+
+// tabKeysBlocked reports whether an open dialog or prompt should get the
+// tab keys instead, or holds state tied to the active tab
+func (m Model) tabKeysBlocked() bool {
+	return m.authDialog.visible || m.saveDialog.visible || m.dryRunReport.IsVisible() ||
+		m.negotiationMenu.IsVisible() || m.snippetsMenu.IsVisible() || m.variablePicker.IsVisible() ||
+		m.variablesPanel.IsVisible() || m.curlImport.IsVisible() || m.awaitingFieldJump
+}
+
+// handleTabKey opens, closes or cycles tabs, reporting whether key was a
+// tab shortcut. Ctrl+Tab only arrives from terminals that report it, so
+// Ctrl+PgDown and Ctrl+PgUp cycle as well.
+func (m Model) handleTabKey(key string) (Model, bool) {
+	switch key {
+	case "ctrl+t":
+		return m.openTab(), true
+	case "ctrl+w":
+		return m.closeTab(), true
+	case "ctrl+tab", "ctrl+pgdown":
+		return m.switchTab((m.activeTab + 1) % len(m.tabs)), true
+	case "ctrl+pgup":
+		return m.switchTab((m.activeTab + len(m.tabs) - 1) % len(m.tabs)), true
+	}
+	return m, false
+}
+
+// leaveTab parks the active tab, first saving its draft if it was edited,
+// since the draft follows whichever tab is active
+func (m Model) leaveTab() (Model, bool) {
+	if m.redirectPrompt {
+		m.statusIndicator.Show("Answer the redirect prompt before switching tabs", StatusWarning)
+		return m, false
+	}
+	if err := m.SaveDraft(); err != nil {
+		m.statusIndicator.Show(fmt.Sprintf("Could not save draft: %v", err), StatusWarning)
+	}
+	// A pending rate-limit retry would resend the builder, which is about to change
+	m.autoRetry = false
+	m.tabs[m.activeTab] = m.captureTab()
+	return m, true
+}
+
+// openTab opens a blank request in a new tab after the active one
+func (m Model) openTab() Model {
+	m, ok := m.leaveTab()
+	if !ok {
+		return m
+	}
+
+	m.activeTab++
+	m.tabs = append(m.tabs[:m.activeTab], append([]requestTab{{}}, m.tabs[m.activeTab:]...)...)
+
+	m.state = StateRequestBuilder
+	m = m.clearForm()
+	m.currentRequest = nil
+	m.currentResponse = nil
+	m.lastResult = nil
+	m.responseViewer = NewResponseViewer(80, 24)
+	m.responseViewer.SetAccessible(m.configManager.Get().UI.Accessible)
+	m = m.resizeResponseViewers()
+	m.urlFixes = nil
+	m.redirectPath = nil
+	m.savePrompt = false
+	m.markDraftClean()
+	m.statusMessage = fmt.Sprintf("Opened tab %d of %d", m.activeTab+1, len(m.tabs))
+	return m
+}
+
+// closeTab closes the active tab, moving to the one after it, or before it
+// if it was the last. The only tab stays open.
+func (m Model) closeTab() Model {
+	if len(m.tabs) == 1 {
+		m.statusIndicator.Show("This is the only tab; n clears it", StatusInfo)
+		return m
+	}
+	if m.redirectPrompt {
+		m.statusIndicator.Show("Answer the redirect prompt before closing the tab", StatusWarning)
+		return m
+	}
+	if err := m.SaveDraft(); err != nil {
+		m.statusIndicator.Show(fmt.Sprintf("Could not save draft: %v", err), StatusWarning)
+	}
+
+	m.autoRetry = false
+	m.tabs = append(m.tabs[:m.activeTab], m.tabs[m.activeTab+1:]...)
+	m.activeTab = min(m.activeTab, len(m.tabs)-1)
+	m.restoreTab(m.tabs[m.activeTab])
+	m.markDraftClean()
+	m.statusMessage = fmt.Sprintf("Closed tab, now on %d of %d", m.activeTab+1, len(m.tabs))
+	return m
+}
+
+// switchTab makes tab index the active one
+func (m Model) switchTab(index int) Model {
+	if index == m.activeTab {
+		return m
+	}
+	m, ok := m.leaveTab()
+	if !ok {
+		return m
+	}
+
+	m.activeTab = index
+	m.restoreTab(m.tabs[index])
+	m.markDraftClean()
+	m.statusMessage = ""
+	return m
+}
+
+// tabLabel names a tab by its method and host, or its URL while that has no
+// host yet, e.g. "{{base_url}}/users"
+func tabLabel(method, rawURL string) string {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return method + " (new)"
+	}
+
+	target := rawURL
+	if scheme, assumed := urlScheme(rawURL); scheme != "" {
+		full := rawURL
+		if assumed {
+			full = scheme + "://" + rawURL
+		}
+		if u, err := url.Parse(full); err == nil && u.Host != "" && !strings.Contains(u.Host, "{{") {
+			target = u.Host
+		}
+	}
+	return truncateValue(method+" "+target, tabLabelWidth)
+}
+
+// renderTabBar renders the open tabs, the active one highlighted. Nothing is
+// shown while only one tab is open.
+func (m Model) renderTabBar() string {
+	if len(m.tabs) < 2 {
+		return ""
+	}
+
+	activeStyle := lipgloss.NewStyle().
+		Background(palette.Primary).
+		Foreground(palette.OnPrimary).
+		Bold(true).
+		Padding(0, 1)
+	inactiveStyle := lipgloss.NewStyle().
+		Foreground(palette.Muted).
+		Padding(0, 1)
+
+	labels := make([]string, len(m.tabs))
+	for i, tab := range m.tabs {
+		if i == m.activeTab {
+			labels[i] = activeStyle.Render(fmt.Sprintf("%d %s", i+1, tabLabel(m.currentDraft().Method, m.urlInput.Value())))
+			continue
+		}
+		labels[i] = inactiveStyle.Render(fmt.Sprintf("%d %s", i+1, tabLabel(tab.method, tab.urlInput.Value())))
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, labels...)
+}
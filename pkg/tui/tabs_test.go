@@ -0,0 +1,27 @@
+package tui
+
+import "testing"
+
+func TestTabLabel(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		want   string
+	}{
+		{"empty", "GET", "", "GET (new)"},
+		{"host only", "POST", "http://example.onion/api/users?page=2", "POST example.onion"},
+		{"assumed scheme", "GET", "example.onion/api", "GET example.onion"},
+		{"port kept", "GET", "https://api.example.com:8443/v1", "GET api.example.com:8443"},
+		{"variable base", "GET", "{{base_url}}/users", "GET {{base_url}}/users"},
+		{"long host truncated", "DELETE", "http://abcdefghijklmnopqrstuvwxyz234567abcdefghijklmnopqrstuvwx.onion/", "DELETE abcdefghijklmnopqr..."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tabLabel(tt.method, tt.url); got != tt.want {
+				t.Errorf("tabLabel(%q, %q) = %q, want %q", tt.method, tt.url, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package tui
+
+import (
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme is the palette the TUI draws with. Every colour on screen comes
+// from the active theme, so switching it restyles everything on the next
+// render.
+type Theme struct {
+	Primary       lipgloss.Color // titles, focused borders, buttons
+	PrimaryBright lipgloss.Color // the focused button
+	OnPrimary     lipgloss.Color // text on a Primary background
+	Text          lipgloss.Color // plain text that needs a colour of its own
+	Background    lipgloss.Color // text on a Highlight or Warning background
+	Muted         lipgloss.Color // blurred borders, help and secondary text
+	Subtle        lipgloss.Color // comments, nulls and skipped steps
+	Info          lipgloss.Color // status messages, header names, keys
+	Success       lipgloss.Color
+	Warning       lipgloss.Color
+	Error         lipgloss.Color
+	Highlight     lipgloss.Color // string values, durations, search matches
+	Accent        lipgloss.Color // timestamps, redirects and other asides
+	Critical      lipgloss.Color // the most severe problems
+}
+
+// themes are the values offered for ui.theme, in the order the settings
+// screen cycles through them
+var themes = []string{"dark", "light", "high-contrast"}
+
+// themePresets are the built-in themes by name
+var themePresets = map[string]Theme{
+	"dark": {
+		Primary:       "#7D56F4",
+		PrimaryBright: "#9D7BF4",
+		OnPrimary:     "#FFFFFF",
+		Text:          "#F8F8F2",
+		Background:    "#282A36",
+		Muted:         "#666666",
+		Subtle:        "#6272A4",
+		Info:          "#8BE9FD",
+		Success:       "#50FA7B",
+		Warning:       "#FFB86C",
+		Error:         "#FF5555",
+		Highlight:     "#F1FA8C",
+		Accent:        "#BD93F9",
+		Critical:      "#FF79C6",
+	},
+	"light": {
+		Primary:       "#5B3CC4",
+		PrimaryBright: "#7C5CE0",
+		OnPrimary:     "#FFFFFF",
+		Text:          "#1F2328",
+		Background:    "#FFFFFF",
+		Muted:         "#6E7781",
+		Subtle:        "#8C959F",
+		Info:          "#0969DA",
+		Success:       "#1A7F37",
+		Warning:       "#BC4C00",
+		Error:         "#CF222E",
+		Highlight:     "#9A6700",
+		Accent:        "#8250DF",
+		Critical:      "#BF3989",
+	},
+	"high-contrast": {
+		Primary:       "#FFFF00",
+		PrimaryBright: "#FFFFFF",
+		OnPrimary:     "#000000",
+		Text:          "#FFFFFF",
+		Background:    "#000000",
+		Muted:         "#D0D0D0",
+		Subtle:        "#D0D0D0",
+		Info:          "#00FFFF",
+		Success:       "#00FF00",
+		Warning:       "#FF9900",
+		Error:         "#FF3333",
+		Highlight:     "#FFFF00",
+		Accent:        "#FF66FF",
+		Critical:      "#FF00FF",
+	},
+}
+
+// palette is the active theme; set with applyTheme
+var palette Theme
+
+// Styles shared across the TUI, derived from the active theme by applyTheme
+var (
+	titleStyle         lipgloss.Style
+	focusedStyle       lipgloss.Style
+	blurredStyle       lipgloss.Style
+	buttonStyle        lipgloss.Style
+	buttonFocusedStyle lipgloss.Style
+	errorStyle         lipgloss.Style
+	successStyle       lipgloss.Style
+	statusStyle        lipgloss.Style
+	helpStyle          lipgloss.Style
+
+	testPassedStyle lipgloss.Style
+	testFailedStyle lipgloss.Style
+
+	previewStyle    lipgloss.Style
+	unresolvedStyle lipgloss.Style
+
+	matchStyle        lipgloss.Style
+	currentMatchStyle lipgloss.Style
+)
+
+func init() {
+	applyTheme(themes[0])
+}
+
+// themeFor returns the named theme, or the dark theme for a name it doesn't
+// know
+func themeFor(name string) Theme {
+	if theme, ok := themePresets[name]; ok {
+		return theme
+	}
+	return themePresets[themes[0]]
+}
+
+// applyTheme makes the named theme the active one and rebuilds the shared
+// styles from it
+func applyTheme(name string) {
+	palette = themeFor(name)
+
+	titleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(palette.Primary).
+		MarginBottom(1)
+
+	focusedStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(palette.Primary).
+		Padding(0, 1)
+
+	blurredStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(palette.Muted).
+		Padding(0, 1)
+
+	buttonStyle = lipgloss.NewStyle().
+		Background(palette.Primary).
+		Foreground(palette.OnPrimary).
+		Padding(0, 2).
+		Margin(1, 0)
+
+	buttonFocusedStyle = lipgloss.NewStyle().
+		Background(palette.PrimaryBright).
+		Foreground(palette.OnPrimary).
+		Padding(0, 2).
+		Margin(1, 0).
+		Bold(true)
+
+	errorStyle = lipgloss.NewStyle().
+		Foreground(palette.Error).
+		Bold(true).
+		Margin(1, 0)
+
+	successStyle = lipgloss.NewStyle().
+		Foreground(palette.Success).
+		Bold(true).
+		Margin(1, 0)
+
+	statusStyle = lipgloss.NewStyle().
+		Foreground(palette.Info).
+		Margin(1, 0)
+
+	helpStyle = lipgloss.NewStyle().
+		Foreground(palette.Muted).
+		Margin(1, 0)
+
+	testPassedStyle = successStyle.UnsetMargins()
+	testFailedStyle = errorStyle.UnsetMargins()
+
+	previewStyle = lipgloss.NewStyle().Foreground(palette.Muted)
+	unresolvedStyle = lipgloss.NewStyle().Foreground(palette.Warning).Bold(true)
+
+	matchStyle = lipgloss.NewStyle().Foreground(palette.Background).Background(palette.Highlight)
+	currentMatchStyle = lipgloss.NewStyle().Foreground(palette.Background).Background(palette.Warning).Bold(true)
+}
+
+// viewportView renders a viewport with its border in the given colour.
+// Viewports keep the style they were built with, so this lets those built
+// before a theme change follow it.
+func viewportView(vp viewport.Model, border lipgloss.Color) string {
+	vp.Style = vp.Style.BorderForeground(border)
+	return vp.View()
+}
@@ -0,0 +1,42 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestThemePresets(t *testing.T) {
+	for _, name := range themes {
+		theme, ok := themePresets[name]
+		if !ok {
+			t.Errorf("No preset for theme %q", name)
+			continue
+		}
+		colors := reflect.ValueOf(theme)
+		for i := 0; i < colors.NumField(); i++ {
+			if colors.Field(i).String() == "" {
+				t.Errorf("Theme %q leaves %s unset", name, colors.Type().Field(i).Name)
+			}
+		}
+	}
+}
+
+func TestApplyTheme(t *testing.T) {
+	t.Cleanup(func() { applyTheme("dark") })
+
+	applyTheme("light")
+	if palette != themePresets["light"] {
+		t.Fatalf("applyTheme(light) left palette = %+v", palette)
+	}
+	if got := titleStyle.GetForeground(); got != themePresets["light"].Primary {
+		t.Errorf("titleStyle foreground = %v, want the light theme's primary", got)
+	}
+	if got := testFailedStyle.GetForeground(); got != themePresets["light"].Error {
+		t.Errorf("testFailedStyle foreground = %v, want the light theme's error colour", got)
+	}
+
+	applyTheme("solarized")
+	if palette != themePresets["dark"] {
+		t.Errorf("An unknown theme should fall back to dark, got %+v", palette)
+	}
+}
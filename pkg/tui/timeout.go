@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeoutDirective starts a line in the headers field that sets the request
+// timeout instead of a header, e.g. "@timeout: 90s" for a slow onion service
+const timeoutDirective = "@timeout"
+
+// extractTimeout removes a timeout directive from the headers text, returning
+// the remaining headers and the timeout, or 0 if there is none. The value is
+// a Go duration ("90s", "2m") or a number of seconds.
+func extractTimeout(headersText string) (string, time.Duration, error) {
+	var kept []string
+	var timeout time.Duration
+	for _, line := range strings.Split(headersText, "\n") {
+		value, ok := timeoutDirectiveValue(line)
+		if !ok {
+			kept = append(kept, line)
+			continue
+		}
+
+		parsed, err := parseTimeout(value)
+		if err != nil {
+			return headersText, 0, err
+		}
+		timeout = parsed
+	}
+	return strings.Join(kept, "\n"), timeout, nil
+}
+
+// timeoutDirectiveValue returns the value of an "@timeout: value" line
+func timeoutDirectiveValue(line string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	if len(trimmed) < len(timeoutDirective) || !strings.EqualFold(trimmed[:len(timeoutDirective)], timeoutDirective) {
+		return "", false
+	}
+	rest := trimmed[len(timeoutDirective):]
+	if rest != "" && rest[0] != ':' && rest[0] != ' ' && rest[0] != '\t' {
+		return "", false // a longer word such as "@timeouts"
+	}
+	rest = strings.TrimSpace(rest)
+	rest = strings.TrimSpace(strings.TrimPrefix(rest, ":"))
+	return rest, true
+}
+
+// parseTimeout parses a timeout directive value
+func parseTimeout(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, fmt.Errorf("%s needs a duration, e.g. %s: 90s", timeoutDirective, timeoutDirective)
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		seconds, convErr := strconv.Atoi(value)
+		if convErr != nil {
+			return 0, fmt.Errorf("invalid %s %q, use a duration such as 90s or 2m", timeoutDirective, value)
+		}
+		timeout = time.Duration(seconds) * time.Second
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("%s must be positive, got %q", timeoutDirective, value)
+	}
+	return timeout, nil
+}
@@ -0,0 +1,40 @@
+package tui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExtractTimeout(t *testing.T) {
+	tests := []struct {
+		name        string
+		headers     string
+		wantHeaders string
+		wantTimeout time.Duration
+		wantErr     bool
+	}{
+		{"no directive", "Accept: application/json", "Accept: application/json", 0, false},
+		{"duration", "Accept: application/json\n@timeout: 90s", "Accept: application/json", 90 * time.Second, false},
+		{"minutes without colon", "@timeout 2m\nX-A: 1", "X-A: 1", 2 * time.Minute, false},
+		{"bare seconds", "@Timeout: 45", "", 45 * time.Second, false},
+		{"longer word is not the directive", "@timeouts: 5s", "@timeouts: 5s", 0, false},
+		{"invalid value", "@timeout: soon", "", 0, true},
+		{"missing value", "@timeout:", "", 0, true},
+		{"zero", "@timeout: 0s", "", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers, timeout, err := extractTimeout(tt.headers)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("extractTimeout() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if headers != tt.wantHeaders || timeout != tt.wantTimeout {
+				t.Errorf("extractTimeout() = %q, %v, want %q, %v", headers, timeout, tt.wantHeaders, tt.wantTimeout)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"onioncli/pkg/api"
+)
+
+// probeTimeoutMargin is added to the request timeout to bound all checks,
+// since the check service request alone may take that long
+const probeTimeoutMargin = 10 * time.Second
+
+// TorProbesMsg carries the connection checks run for a Tor error
+type TorProbesMsg struct {
+	diagnostic *api.DiagnosticError
+	results    []api.ProbeResult
+}
+
+// showErrorDetails opens the error viewer. Tor errors also get live
+// connection checks, run in the background the first time they are opened.
+func (m Model) showErrorDetails(diagnostic *api.DiagnosticError) (Model, tea.Cmd) {
+	m.errorViewer.Show(diagnostic)
+	if diagnostic.Type != api.ErrorTypeTor || diagnostic.Probes != nil {
+		return m, nil
+	}
+
+	m.errorViewer.StartProbes()
+	client := m.client
+	timeout := client.GetTimeout() + probeTimeoutMargin
+	return m, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		return TorProbesMsg{diagnostic: diagnostic, results: client.DiagnoseTor(ctx)}
+	}
+}
+
+// NewCircuitMsg reports the result of a new-circuit request
+type NewCircuitMsg struct {
+	err error
+}
+
+// requestNewCircuit asks Tor for new circuits in the background
+func (m Model) requestNewCircuit() (Model, tea.Cmd) {
+	if !m.client.IsTorEnabled() {
+		m.statusIndicator.Show("Tor is disabled, no circuit to renew", StatusInfo)
+		return m, nil
+	}
+
+	m.statusIndicator.Show("Requesting a new Tor circuit...", StatusInfo)
+	client := m.client
+	return m, func() tea.Msg {
+		return NewCircuitMsg{err: client.NewCircuit()}
+	}
+}
+
+// handleNewCircuit shows the outcome of a new-circuit request; failures get
+// the error alert so their suggestions are one key away. A new identity
+// also drops the cookie jar, as cookies would link it to the old one.
+func (m Model) handleNewCircuit(msg NewCircuitMsg) (Model, tea.Cmd) {
+	if msg.err == nil {
+		if len(m.client.Cookies()) > 0 {
+			m.client.ClearCookies()
+			m.statusIndicator.Show("New Tor circuit ready for the next request; cookies cleared", StatusSuccess)
+			return m, nil
+		}
+		m.statusIndicator.Show("New Tor circuit ready for the next request", StatusSuccess)
+		return m, nil
+	}
+
+	var diagnostic *api.DiagnosticError
+	if errors.As(msg.err, &diagnostic) {
+		m.errorAlert.Show(diagnostic)
+		m.errorMessage = diagnostic.Message
+	} else {
+		m.errorMessage = fmt.Sprintf("New circuit failed: %v", msg.err)
+	}
+	m.statusIndicator.Show("New circuit failed", StatusError)
+	return m, nil
+}
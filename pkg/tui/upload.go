@@ -0,0 +1,115 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"onioncli/pkg/api"
+)
+
+// bodyFilePrefix marks a body that names a file to stream, curl-style: @/path/to/file
+const bodyFilePrefix = "@"
+
+// UploadProgressMsg reports progress of a streamed request body
+type UploadProgressMsg struct {
+	seq   int
+	sent  int64
+	total int64
+	ch    <-chan tea.Msg
+}
+
+// bodyFilePath returns the file path of an "@file" body
+func bodyFilePath(body string) (string, bool) {
+	if !strings.HasPrefix(body, bodyFilePrefix) || strings.Contains(body, "\n") {
+		return "", false
+	}
+	path := strings.TrimSpace(strings.TrimPrefix(body, bodyFilePrefix))
+	return path, path != ""
+}
+
+// sendStreamingRequestCmd sends a request whose body is streamed from disk or
+// that may be retried, reporting upload progress and retries before the
+// final result
+func (m Model) sendStreamingRequestCmd(ctx context.Context, seq int, req *api.Request) tea.Cmd {
+	ch := make(chan tea.Msg)
+
+	send := func(msg tea.Msg) {
+		select {
+		case ch <- msg:
+		case <-ctx.Done():
+		}
+	}
+
+	// Only report whole-percent changes so large files don't flood the UI
+	lastPercent := int64(-1)
+	req.UploadProgress = func(sent, total int64) {
+		percent := int64(100)
+		if total > 0 {
+			percent = sent * 100 / total
+		}
+		if percent == lastPercent {
+			return
+		}
+		lastPercent = percent
+		send(UploadProgressMsg{seq: seq, sent: sent, total: total, ch: ch})
+	}
+	req.RetryProgress = func(notice api.RetryNotice) {
+		send(RetryProgressMsg{seq: seq, notice: notice, ch: ch})
+	}
+
+	client := m.client
+	go func() {
+		defer close(ch)
+		resp, err := client.SendContext(ctx, req)
+		if err != nil {
+			send(RequestErrorMsg{err: err, url: req.URL, seq: seq})
+			return
+		}
+		send(RequestSuccessMsg{response: resp, seq: seq})
+	}()
+
+	return waitForImport(ch)
+}
+
+// handleUploadProgress updates the upload progress bar and waits for the next update
+func (m Model) handleUploadProgress(msg UploadProgressMsg) (Model, tea.Cmd) {
+	if msg.seq == m.requestSeq && m.loading {
+		if !m.uploadProgress.IsVisible() {
+			m.uploadProgress.Show("Uploading "+formatBytes(msg.total), int(msg.total))
+		}
+		m.uploadProgress.Update(int(msg.sent))
+	}
+	return m, waitForImport(msg.ch)
+}
+
+// RetryProgressMsg reports that a request is about to be retried
+type RetryProgressMsg struct {
+	seq    int
+	notice api.RetryNotice
+	ch     <-chan tea.Msg
+}
+
+// handleRetryProgress shows the retry on the loading overlay and waits for the next update
+func (m Model) handleRetryProgress(msg RetryProgressMsg) (Model, tea.Cmd) {
+	if msg.seq == m.requestSeq && m.loading {
+		m.retryNotice = msg.notice.String()
+	}
+	return m, waitForImport(msg.ch)
+}
+
+// formatBytes formats a byte count for display, e.g. "1.5 MiB"
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,275 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"onioncli/pkg/collections"
+)
+
+// secretMask stands in for a secret variable's value wherever it is shown
+const secretMask = "********"
+
+// secretPrefix marks a variable as a secret in the environment editors, e.g.
+// "secret token=..."
+const secretPrefix = "secret "
+
+// VariablesPanel is a side panel in the request builder that shows the active
+// environment's variables and allows editing their values in place
+type VariablesPanel struct {
+	manager    *collections.Manager
+	keys       []string
+	unresolved map[string]bool
+	cursor     int
+	editing    bool
+	input      textinput.Model
+	message    string
+	visible    bool
+}
+
+// NewVariablesPanel creates a new variables panel
+func NewVariablesPanel(manager *collections.Manager) VariablesPanel {
+	input := textinput.New()
+	input.CharLimit = 500
+	input.Width = 30
+
+	return VariablesPanel{
+		manager: manager,
+		input:   input,
+		visible: false,
+	}
+}
+
+// Show opens the panel, highlighting variables referenced by the current
+// request that the active environment does not define
+func (vp *VariablesPanel) Show(referenced []string) {
+	vp.visible = true
+	vp.editing = false
+	vp.message = ""
+	vp.cursor = 0
+	vp.refresh(referenced)
+}
+
+// Hide closes the panel
+func (vp *VariablesPanel) Hide() {
+	vp.visible = false
+	vp.editing = false
+	vp.input.Blur()
+}
+
+// refresh rebuilds the key list from the active environment
+func (vp *VariablesPanel) refresh(referenced []string) {
+	vp.keys = nil
+	vp.unresolved = make(map[string]bool)
+
+	env := vp.manager.GetActiveEnvironment()
+	if env != nil {
+		for key := range env.Variables {
+			vp.keys = append(vp.keys, key)
+		}
+	}
+	sort.Strings(vp.keys)
+
+	for _, name := range referenced {
+		if env == nil {
+			vp.unresolved[name] = true
+		} else if _, ok := env.Variables[name]; !ok {
+			vp.unresolved[name] = true
+		}
+	}
+
+	// Unresolved variables are listed first so they can be filled in quickly
+	var missing []string
+	for name := range vp.unresolved {
+		missing = append(missing, name)
+	}
+	sort.Strings(missing)
+	vp.keys = append(missing, vp.keys...)
+
+	if vp.cursor >= len(vp.keys) {
+		vp.cursor = len(vp.keys) - 1
+	}
+	if vp.cursor < 0 {
+		vp.cursor = 0
+	}
+}
+
+// Update handles panel key presses
+func (vp VariablesPanel) Update(msg tea.Msg) (VariablesPanel, tea.Cmd) {
+	if !vp.visible {
+		return vp, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		if vp.editing {
+			var cmd tea.Cmd
+			vp.input, cmd = vp.input.Update(msg)
+			return vp, cmd
+		}
+		return vp, nil
+	}
+
+	if vp.editing {
+		switch keyMsg.String() {
+		case "enter":
+			vp.save(vp.keys[vp.cursor], vp.input.Value())
+			vp.editing = false
+			vp.input.Blur()
+			return vp, nil
+		case "esc":
+			vp.editing = false
+			vp.input.Blur()
+			return vp, nil
+		}
+		var cmd tea.Cmd
+		vp.input, cmd = vp.input.Update(msg)
+		return vp, cmd
+	}
+
+	switch keyMsg.String() {
+	case "up", "k":
+		if vp.cursor > 0 {
+			vp.cursor--
+		}
+	case "down", "j":
+		if vp.cursor < len(vp.keys)-1 {
+			vp.cursor++
+		}
+	case "enter":
+		if len(vp.keys) == 0 || vp.manager.GetActiveEnvironment() == nil {
+			return vp, nil
+		}
+		// A secret is replaced rather than shown for editing
+		key := vp.keys[vp.cursor]
+		value := ""
+		if env := vp.manager.GetActiveEnvironment(); !env.IsSecret(key) {
+			value = env.Variables[key]
+		}
+		vp.input.SetValue(value)
+		vp.input.CursorEnd()
+		vp.input.Focus()
+		vp.editing = true
+		vp.message = ""
+		return vp, textinput.Blink
+	case "esc", "ctrl+o":
+		vp.Hide()
+	}
+
+	return vp, nil
+}
+
+// save writes a single variable back to the active environment; other
+// secrets keep their stored values
+func (vp *VariablesPanel) save(key, value string) {
+	env := vp.manager.GetActiveEnvironment()
+	if env == nil {
+		vp.message = "No active environment"
+		return
+	}
+
+	variables := make(map[string]string, len(env.Variables)+1)
+	for k, v := range env.Variables {
+		variables[k] = v
+	}
+	variables[key] = value
+
+	if err := vp.manager.UpdateEnvironment(env.ID, env.Name, env.Description, variables, env.SecretKeys); err != nil {
+		vp.message = fmt.Sprintf("Failed to save: %v", err)
+		return
+	}
+
+	delete(vp.unresolved, key)
+	var referenced []string
+	for name := range vp.unresolved {
+		referenced = append(referenced, name)
+	}
+	vp.refresh(referenced)
+	vp.message = fmt.Sprintf("Saved %s", key)
+}
+
+// View renders the panel
+func (vp VariablesPanel) View() string {
+	if !vp.visible {
+		return ""
+	}
+
+	keyStyle := lipgloss.NewStyle().Foreground(palette.Info)
+	missingStyle := lipgloss.NewStyle().Foreground(palette.Error).Bold(true)
+	cursorStyle := lipgloss.NewStyle().Foreground(palette.Primary).Bold(true)
+
+	var lines []string
+	env := vp.manager.GetActiveEnvironment()
+	if env == nil {
+		lines = append(lines, titleStyle.Render("Variables (no environment)"))
+	} else {
+		lines = append(lines, titleStyle.Render("Variables: "+env.Name))
+	}
+
+	if len(vp.keys) == 0 {
+		lines = append(lines, helpStyle.Render("No variables defined"))
+	}
+
+	for i, key := range vp.keys {
+		prefix := "  "
+		if i == vp.cursor {
+			prefix = cursorStyle.Render("> ")
+		}
+
+		var line string
+		switch {
+		case vp.editing && i == vp.cursor:
+			line = keyStyle.Render(key) + " = " + vp.input.View()
+		case vp.unresolved[key]:
+			line = missingStyle.Render(key + " (unset)")
+		default:
+			line = keyStyle.Render(key) + " = " + truncateValue(displayValue(env, key), 30)
+		}
+		lines = append(lines, prefix+line)
+	}
+
+	if vp.message != "" {
+		lines = append(lines, "", statusStyle.Render(vp.message))
+	}
+
+	help := "↑/↓ select • enter edit • esc close"
+	if vp.editing {
+		help = "enter save • esc cancel"
+	}
+	lines = append(lines, helpStyle.Render(help))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(palette.Primary).
+		Padding(0, 1).
+		Width(44).
+		Render(strings.Join(lines, "\n"))
+}
+
+// IsVisible returns whether the panel is open
+func (vp VariablesPanel) IsVisible() bool {
+	return vp.visible
+}
+
+// displayValue returns a variable's value for display, masked if it is a
+// secret
+func displayValue(env *collections.Environment, key string) string {
+	if env.IsSecret(key) {
+		return secretMask
+	}
+	return env.Variables[key]
+}
+
+// truncateValue shortens long values for display
+func truncateValue(value string, max int) string {
+	runes := []rune(value)
+	if len(runes) <= max {
+		return value
+	}
+	return string(runes[:max-3]) + "..."
+}
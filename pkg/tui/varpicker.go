@@ -0,0 +1,174 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"onioncli/pkg/collections"
+)
+
+// VariablePicker is a multi-select of the active environment's variables.
+// The chosen variables are inserted as {{name}} references into the focused
+// headers or body editor; nothing is resolved until the request is sent.
+type VariablePicker struct {
+	manager  *collections.Manager
+	keys     []string
+	selected map[string]bool
+	cursor   int
+	visible  bool
+}
+
+// NewVariablePicker creates a new variable picker
+func NewVariablePicker(manager *collections.Manager) VariablePicker {
+	return VariablePicker{
+		manager: manager,
+		visible: false,
+	}
+}
+
+// Show opens the picker with nothing selected
+func (vp *VariablePicker) Show() {
+	vp.keys = nil
+	if env := vp.manager.GetActiveEnvironment(); env != nil {
+		for key := range env.Variables {
+			vp.keys = append(vp.keys, key)
+		}
+	}
+	sort.Strings(vp.keys)
+	vp.selected = make(map[string]bool)
+	vp.cursor = 0
+	vp.visible = true
+}
+
+// Hide closes the picker
+func (vp *VariablePicker) Hide() {
+	vp.visible = false
+}
+
+// Update handles picker key presses. It returns the chosen variable names once
+// Enter is pressed; with nothing ticked, the variable under the cursor is used.
+func (vp VariablePicker) Update(msg tea.Msg) (VariablePicker, []string) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || !vp.visible {
+		return vp, nil
+	}
+
+	switch keyMsg.String() {
+	case "up", "k":
+		if vp.cursor > 0 {
+			vp.cursor--
+		}
+	case "down", "j":
+		if vp.cursor < len(vp.keys)-1 {
+			vp.cursor++
+		}
+	case " ", "x":
+		if len(vp.keys) > 0 {
+			key := vp.keys[vp.cursor]
+			vp.selected[key] = !vp.selected[key]
+		}
+	case "a":
+		all := len(vp.selected) < len(vp.keys)
+		for _, key := range vp.keys {
+			vp.selected[key] = all
+		}
+	case "enter":
+		var names []string
+		for _, key := range vp.keys {
+			if vp.selected[key] {
+				names = append(names, key)
+			}
+		}
+		if len(names) == 0 && len(vp.keys) > 0 {
+			names = []string{vp.keys[vp.cursor]}
+		}
+		vp.Hide()
+		return vp, names
+	case "esc", "ctrl+r":
+		vp.Hide()
+	}
+
+	return vp, nil
+}
+
+// View renders the picker
+func (vp VariablePicker) View() string {
+	if !vp.visible {
+		return ""
+	}
+
+	keyStyle := lipgloss.NewStyle().Foreground(palette.Info)
+	cursorStyle := lipgloss.NewStyle().Foreground(palette.Primary).Bold(true)
+	checkStyle := lipgloss.NewStyle().Foreground(palette.Success)
+
+	var lines []string
+	env := vp.manager.GetActiveEnvironment()
+	if env == nil {
+		lines = append(lines, titleStyle.Render("Insert variables (no environment)"))
+	} else {
+		lines = append(lines, titleStyle.Render("Insert variables: "+env.Name))
+	}
+
+	if len(vp.keys) == 0 {
+		lines = append(lines, helpStyle.Render("No variables defined"))
+	}
+
+	for i, key := range vp.keys {
+		prefix := "  "
+		if i == vp.cursor {
+			prefix = cursorStyle.Render("> ")
+		}
+		check := "[ ]"
+		if vp.selected[key] {
+			check = checkStyle.Render("[x]")
+		}
+		lines = append(lines, fmt.Sprintf("%s%s %s = %s", prefix, check,
+			keyStyle.Render(key), truncateValue(displayValue(env, key), 20)))
+	}
+
+	lines = append(lines, helpStyle.Render("space select • a all • enter insert • esc close"))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(palette.Primary).
+		Padding(0, 1).
+		Width(44).
+		Render(strings.Join(lines, "\n"))
+}
+
+// IsVisible returns whether the picker is open
+func (vp VariablePicker) IsVisible() bool {
+	return vp.visible
+}
+
+// insertVariableReferences inserts {{name}} references at the cursor of the
+// focused headers or body editor
+func (m Model) insertVariableReferences(names []string) Model {
+	if len(names) == 0 {
+		return m
+	}
+
+	refs := make([]string, len(names))
+	for i, name := range names {
+		refs[i] = "{{" + name + "}}"
+	}
+	text := strings.Join(refs, " ")
+
+	switch m.focusedField {
+	case FocusQuery:
+		m.queryArea.InsertString(text)
+	case FocusHeaders:
+		m.headersArea.InsertString(text)
+	case FocusBody:
+		m.bodyArea.InsertString(text)
+	default:
+		return m
+	}
+
+	m.statusIndicator.Show(fmt.Sprintf("Inserted %d variable %s", len(names), plural(len(names), "reference", "references")), StatusInfo)
+	return m
+}
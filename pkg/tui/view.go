@@ -7,55 +7,6 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-// Styles for the TUI
-var (
-	titleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#7D56F4")).
-			MarginBottom(1)
-
-	focusedStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#7D56F4")).
-			Padding(0, 1)
-
-	blurredStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#666666")).
-			Padding(0, 1)
-
-	buttonStyle = lipgloss.NewStyle().
-			Background(lipgloss.Color("#7D56F4")).
-			Foreground(lipgloss.Color("#FFFFFF")).
-			Padding(0, 2).
-			Margin(1, 0)
-
-	buttonFocusedStyle = lipgloss.NewStyle().
-				Background(lipgloss.Color("#9D7BF4")).
-				Foreground(lipgloss.Color("#FFFFFF")).
-				Padding(0, 2).
-				Margin(1, 0).
-				Bold(true)
-
-	errorStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FF5555")).
-			Bold(true).
-			Margin(1, 0)
-
-	successStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#50FA7B")).
-			Bold(true).
-			Margin(1, 0)
-
-	statusStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#8BE9FD")).
-			Margin(1, 0)
-
-	helpStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#666666")).
-			Margin(1, 0)
-)
-
 // View renders the main view
 func (m Model) View() string {
 	// Handle keyboard shortcuts overlay (highest priority)
@@ -64,11 +15,82 @@ func (m Model) View() string {
 		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.keyboardShortcuts.View()) + "\n" + baseView
 	}
 
+	// The command prompt sits on the bottom line
+	if m.commandPrompt.IsVisible() {
+		return m.renderCurrentState() + "\n" + m.commandPrompt.View()
+	}
+
+	// Handle command palette overlay
+	if m.commandPalette.IsVisible() {
+		baseView := m.renderCurrentState()
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.commandPalette.View()) + "\n" + baseView
+	}
+
 	// Handle error viewer overlay
 	if m.errorViewer.IsVisible() {
 		return m.errorViewer.View()
 	}
 
+	// Handle in-flight request overlay
+	if m.loading {
+		return m.renderLoading()
+	}
+
+	// Handle dry-run report overlay
+	if m.dryRunReport.IsVisible() {
+		return m.dryRunReport.View(m.width, m.height)
+	}
+
+	// Handle send confirmation overlay
+	if m.pendingSend != nil {
+		return m.renderSendConfirmation()
+	}
+
+	// Handle security audit overlay
+	if m.securityAudit.IsVisible() {
+		return m.securityAudit.View(m.width, m.height)
+	}
+
+	// Handle cookie viewer overlay
+	if m.cookieViewer.IsVisible() {
+		return m.cookieViewer.View(m.width, m.height)
+	}
+
+	// Handle save-to-collection dialog overlay
+	if m.collectionSave.IsVisible() {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.collectionSave.View())
+	}
+
+	// Handle content negotiation menu overlay
+	if m.negotiationMenu.IsVisible() {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.negotiationMenu.View())
+	}
+
+	// Handle cURL command box overlay
+	if m.curlView.IsVisible() {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.curlView.View())
+	}
+
+	// Handle cURL import dialog overlay
+	if m.curlImport.IsVisible() {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.curlImport.View())
+	}
+
+	// Handle diff viewer overlay
+	if m.diffViewer.IsVisible() {
+		return m.diffViewer.View()
+	}
+
+	// Handle environment comparison overlay
+	if m.envCompare.IsVisible() {
+		return m.envCompare.View()
+	}
+
+	// Handle WebSocket view
+	if m.webSocket.IsVisible() {
+		return m.webSocket.View()
+	}
+
 	// Handle auth dialog overlay
 	if m.authDialog.visible {
 		baseView := m.renderCurrentState()
@@ -88,20 +110,30 @@ func (m Model) View() string {
 func (m Model) renderCurrentState() string {
 	switch m.state {
 	case StateRequestBuilder:
-		return m.renderRequestBuilder()
+		return withTabBar(m.renderTabBar(), m.renderRequestBuilder())
 	case StateResponse:
-		return m.renderResponse()
+		return withTabBar(m.renderTabBar(), m.renderResponse())
 	case StateHistory:
 		return m.renderHistory()
 	case StateCollections:
 		return m.renderCollections()
 	case StateEnvironments:
 		return m.renderEnvironments()
+	case StateSettings:
+		return m.settingsView.View()
 	default:
 		return m.renderRequestBuilder()
 	}
 }
 
+// withTabBar puts the tab bar, if any, above view
+func withTabBar(tabBar, view string) string {
+	if tabBar == "" {
+		return view
+	}
+	return tabBar + "\n" + view
+}
+
 // renderHistory renders the history view
 func (m Model) renderHistory() string {
 	return m.historyViewer.View()
@@ -117,62 +149,74 @@ func (m Model) renderEnvironments() string {
 	return m.environmentsViewer.View()
 }
 
-// renderRequestBuilder renders the request builder interface
-func (m Model) renderRequestBuilder() string {
-	var sections []string
+// builderSection is one block of the request builder's upper part; clicking
+// it focuses field unless it isn't a field at all
+type builderSection struct {
+	view    string
+	field   FocusedField
+	isField bool
+}
 
+// builderSections renders the title and the fields of the request builder,
+// top to bottom
+func (m Model) builderSections() []builderSection {
 	// Title (more compact)
 	title := titleStyle.Render("OnionCLI - .onion API Client")
-	sections = append(sections, title)
 
 	// URL input
-	urlLabel := "URL:"
-	var urlSection string
-	if m.focusedField == FocusURL {
-		urlSection = focusedStyle.Render(fmt.Sprintf("%s\n%s", urlLabel, m.urlInput.View()))
-	} else {
-		urlSection = blurredStyle.Render(fmt.Sprintf("%s\n%s", urlLabel, m.urlInput.View()))
-	}
-	sections = append(sections, urlSection)
+	urlTitle := urlLabel(m.urlInput.Value())
+	urlContents := m.withPreview(fmt.Sprintf("%s\n%s", urlTitle, m.urlInput.View()), "url", m.urlInput.Value())
+
+	// Query parameters
+	queryContents := fmt.Sprintf("%s\n%s", "Query Parameters:", m.queryArea.View())
 
 	// Method selection
-	methodLabel := "HTTP Method:"
-	var methodSection string
-	if m.focusedField == FocusMethod {
-		methodSection = focusedStyle.Render(fmt.Sprintf("%s\n%s", methodLabel, m.methodList.View()))
-	} else {
-		methodSection = blurredStyle.Render(fmt.Sprintf("%s\n%s", methodLabel, m.methodList.View()))
-	}
-	sections = append(sections, methodSection)
+	methodContents := fmt.Sprintf("%s\n%s", "HTTP Method:", m.methodList.View())
 
 	// Headers
-	headersLabel := "Headers:"
-	var headersSection string
-	if m.focusedField == FocusHeaders {
-		headersSection = focusedStyle.Render(fmt.Sprintf("%s\n%s", headersLabel, m.headersArea.View()))
-	} else {
-		headersSection = blurredStyle.Render(fmt.Sprintf("%s\n%s", headersLabel, m.headersArea.View()))
+	headersContents := fmt.Sprintf("%s\n%s", "Headers:", m.headersArea.View())
+	if m.previewAll {
+		headersContents = m.withPreview(headersContents, "headers", m.headersArea.Value())
 	}
-	sections = append(sections, headersSection)
 
 	// Body
-	bodyLabel := "Request Body:"
-	var bodySection string
-	if m.focusedField == FocusBody {
-		bodySection = focusedStyle.Render(fmt.Sprintf("%s\n%s", bodyLabel, m.bodyArea.View()))
-	} else {
-		bodySection = blurredStyle.Render(fmt.Sprintf("%s\n%s", bodyLabel, m.bodyArea.View()))
+	bodyContents := fmt.Sprintf("%s\n%s", m.bodyLabel(), m.bodyArea.View())
+	if m.previewAll {
+		bodyContents = m.withPreview(bodyContents, "body", m.bodyArea.Value())
+	}
+
+	sections := []builderSection{{view: title}}
+	for _, field := range []struct {
+		field    FocusedField
+		contents string
+	}{
+		{FocusURL, urlContents},
+		{FocusQuery, queryContents},
+		{FocusMethod, methodContents},
+		{FocusHeaders, headersContents},
+		{FocusBody, bodyContents},
+	} {
+		style := blurredStyle
+		if m.focusedField == field.field {
+			style = focusedStyle
+		}
+		sections = append(sections, builderSection{view: style.Render(field.contents), field: field.field, isField: true})
 	}
-	sections = append(sections, bodySection)
 
 	// Submit button
-	var submitButton string
+	submitButton := buttonStyle.Render(submitLabel(m.urlInput.Value()))
 	if m.focusedField == FocusSubmit {
-		submitButton = buttonFocusedStyle.Render("Send Request")
-	} else {
-		submitButton = buttonStyle.Render("Send Request")
+		submitButton = buttonFocusedStyle.Render(submitLabel(m.urlInput.Value()))
+	}
+	return append(sections, builderSection{view: submitButton, field: FocusSubmit, isField: true})
+}
+
+// renderRequestBuilder renders the request builder interface
+func (m Model) renderRequestBuilder() string {
+	var sections []string
+	for _, section := range m.builderSections() {
+		sections = append(sections, section.view)
 	}
-	sections = append(sections, submitButton)
 
 	// Error alert (enhanced error display)
 	if m.errorAlert.IsVisible() {
@@ -183,11 +227,6 @@ func (m Model) renderRequestBuilder() string {
 		sections = append(sections, errorStyle.Render("❌ "+m.errorMessage))
 	}
 
-	// Loading spinner
-	if m.loadingSpinner.IsVisible() {
-		sections = append(sections, m.loadingSpinner.View())
-	}
-
 	// Status indicator
 	if m.statusIndicator.IsVisible() {
 		sections = append(sections, m.statusIndicator.View())
@@ -198,11 +237,31 @@ func (m Model) renderRequestBuilder() string {
 		sections = append(sections, statusStyle.Render("ℹ️  "+m.statusMessage))
 	}
 
+	// Outcome of the last request
+	if summary := m.renderResultSummary(); summary != "" {
+		sections = append(sections, summary)
+	}
+
+	// Recent request latency
+	if sparkline := m.renderSparkline(); sparkline != "" {
+		sections = append(sections, sparkline)
+	}
+
 	// Help text
 	help := helpStyle.Render(m.renderHelp())
 	sections = append(sections, help)
 
-	return strings.Join(sections, "\n")
+	builder := strings.Join(sections, "\n")
+	if m.snippetsMenu.IsVisible() {
+		return lipgloss.JoinHorizontal(lipgloss.Top, builder, "  ", m.snippetsMenu.View())
+	}
+	if m.variablePicker.IsVisible() {
+		return lipgloss.JoinHorizontal(lipgloss.Top, builder, "  ", m.variablePicker.View())
+	}
+	if m.variablesPanel.IsVisible() {
+		return lipgloss.JoinHorizontal(lipgloss.Top, builder, "  ", m.variablesPanel.View())
+	}
+	return builder
 }
 
 // renderResponse renders the response view using the response viewer
@@ -211,14 +270,26 @@ func (m Model) renderResponse() string {
 		return titleStyle.Render("No response to display")
 	}
 
-	return m.responseViewer.View()
+	sections := []string{m.responseViewer.View()}
+	if m.showingPinned() {
+		sections = []string{m.renderPinnedSplit(), pinnedSummary(m.pinnedResponse, m.currentResponse)}
+	}
+	for _, banner := range []string{m.renderRepresentation(), m.renderRateLimit(), m.renderRedirect(), m.renderSavePrompt()} {
+		if banner != "" {
+			sections = append(sections, banner)
+		}
+	}
+	if m.errorAlert.IsVisible() {
+		sections = append(sections, m.errorAlert.View())
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
 }
 
 // renderHelp renders the help text
 func (m Model) renderHelp() string {
 	authStatus := "No auth"
 	if m.authConfig != nil {
-		authStatus = fmt.Sprintf("Auth: %s", m.authConfig.Type)
+		authStatus = fmt.Sprintf("Auth: %s", authSummary(m.authManager, m.authConfig))
 	}
 
 	errorHint := ""
@@ -236,6 +307,8 @@ func (m Model) renderHelp() string {
 	switch m.focusedField {
 	case FocusURL:
 		return fmt.Sprintf("Enter a .onion URL. Tab/Shift+Tab to navigate, a for auth, c for collections, v for environments, h for history, s to save, Enter/Ctrl+Enter to send | %s | %s", authStatus, baseHelp)
+	case FocusQuery:
+		return fmt.Sprintf("Enter query parameters as 'name=value', one per line, unencoded. Tab/Shift+Tab to navigate, a for auth, c for collections, v for environments, h for history, Ctrl+Enter to send | %s | %s", authStatus, baseHelp)
 	case FocusMethod:
 		return fmt.Sprintf("Select HTTP method with ↑/↓ arrows. Tab/Shift+Tab to navigate, a for auth, c for collections, v for environments, h for history, Ctrl+Enter to send | %s | %s", authStatus, baseHelp)
 	case FocusHeaders:
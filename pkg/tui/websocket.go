@@ -0,0 +1,374 @@
+package tui
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"onioncli/pkg/api"
+)
+
+// wsBinaryPreviewBytes is how much of a binary frame the log shows in hex
+const wsBinaryPreviewBytes = 32
+
+// WebSocketConnectedMsg reports the outcome of opening a WebSocket
+type WebSocketConnectedMsg struct {
+	conn *api.WebSocket
+	url  string
+	err  error
+}
+
+// WebSocketMessageMsg carries a message received on a WebSocket, or the
+// error that ended the connection
+type WebSocketMessageMsg struct {
+	conn    *api.WebSocket
+	message api.WebSocketMessage
+	err     error
+}
+
+// WebSocketSentMsg reports the outcome of sending a text frame
+type WebSocketSentMsg struct {
+	conn *api.WebSocket
+	text string
+	err  error
+}
+
+// wsEntryKind says what a line of the WebSocket log records
+type wsEntryKind int
+
+const (
+	wsSent wsEntryKind = iota
+	wsReceived
+	wsInfo
+	wsError
+)
+
+// wsLogEntry is one line of the WebSocket log
+type wsLogEntry struct {
+	kind wsEntryKind
+	text string
+	at   time.Time
+}
+
+// WebSocketView is the WebSocket mode: a scrollable log of the messages
+// sent and received, and an input for sending text frames
+type WebSocketView struct {
+	viewport   viewport.Model
+	input      textinput.Model
+	url        string
+	conn       *api.WebSocket
+	connecting bool
+	cancel     context.CancelFunc
+	log        []wsLogEntry
+	visible    bool
+	plain      bool // accessible mode: text labels instead of arrows and colours
+}
+
+// NewWebSocketView creates a new WebSocket view
+func NewWebSocketView(width, height int) WebSocketView {
+	vp := viewport.New(width-4, height-10)
+	vp.Style = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		Padding(0, 1)
+
+	input := textinput.New()
+	input.Placeholder = "Message to send as a text frame"
+	input.Prompt = "> "
+	input.Width = width - 8
+
+	return WebSocketView{viewport: vp, input: input}
+}
+
+// Resize fits the view to the window
+func (wv *WebSocketView) Resize(width, height int) {
+	wv.viewport.Width = width - 4
+	wv.viewport.Height = height - 10
+	wv.input.Width = width - 8
+	wv.refresh()
+}
+
+// SetAccessible switches accessible plain-text rendering on or off
+func (wv *WebSocketView) SetAccessible(accessible bool) {
+	wv.plain = accessible
+	if accessible {
+		wv.viewport.Style = lipgloss.NewStyle()
+	}
+}
+
+// IsVisible returns whether the WebSocket view is shown
+func (wv WebSocketView) IsVisible() bool {
+	return wv.visible
+}
+
+// open shows the view for a connection being made to url, which cancel
+// abandons
+func (wv *WebSocketView) open(url string, cancel context.CancelFunc) tea.Cmd {
+	wv.url = url
+	wv.conn = nil
+	wv.connecting = true
+	wv.cancel = cancel
+	wv.log = nil
+	wv.visible = true
+	wv.input.SetValue("")
+	wv.append(wsInfo, "Connecting to "+url)
+	return wv.input.Focus()
+}
+
+// connected records the open connection
+func (wv *WebSocketView) connected(conn *api.WebSocket) {
+	wv.conn = conn
+	wv.connecting = false
+	wv.cancel = nil
+	wv.append(wsInfo, "Connected")
+}
+
+// disconnected records why the connection ended
+func (wv *WebSocketView) disconnected(diagnostic *api.DiagnosticError) {
+	wv.conn = nil
+	wv.connecting = false
+	wv.cancel = nil
+	wv.append(wsError, diagnostic.Message)
+	for _, suggestion := range diagnostic.Suggestions {
+		wv.append(wsInfo, suggestion)
+	}
+}
+
+// close ends the connection or connection attempt and hides the view
+func (wv *WebSocketView) close() {
+	if wv.cancel != nil {
+		wv.cancel()
+	}
+	if wv.conn != nil {
+		wv.conn.Close()
+	}
+	wv.conn = nil
+	wv.connecting = false
+	wv.cancel = nil
+	wv.visible = false
+	wv.input.Blur()
+}
+
+// append adds a line to the log, keeping the view at the bottom if it was
+// already there
+func (wv *WebSocketView) append(kind wsEntryKind, text string) {
+	wv.log = append(wv.log, wsLogEntry{kind: kind, text: text, at: time.Now()})
+	wv.refresh()
+}
+
+// refresh renders the log into the viewport
+func (wv *WebSocketView) refresh() {
+	following := wv.viewport.AtBottom() || wv.viewport.TotalLineCount() == 0
+	lines := make([]string, len(wv.log))
+	for i, entry := range wv.log {
+		lines[i] = wv.formatEntry(entry)
+	}
+	wv.viewport.SetContent(strings.Join(lines, "\n"))
+	if following {
+		wv.viewport.GotoBottom()
+	}
+}
+
+// formatEntry renders one log line, e.g. "→ 12:03:04 hello"
+func (wv WebSocketView) formatEntry(entry wsLogEntry) string {
+	at := entry.at.Format("15:04:05")
+	if wv.plain {
+		label := map[wsEntryKind]string{wsSent: "Sent", wsReceived: "Received", wsInfo: "Note", wsError: "Error"}[entry.kind]
+		return fmt.Sprintf("%s %s: %s", label, at, entry.text)
+	}
+
+	marker := map[wsEntryKind]string{wsSent: "→", wsReceived: "←", wsInfo: "•", wsError: "✗"}[entry.kind]
+	color := map[wsEntryKind]lipgloss.Color{wsSent: palette.Success, wsReceived: palette.Info, wsInfo: palette.Muted, wsError: palette.Error}[entry.kind]
+	style := lipgloss.NewStyle().Foreground(color)
+	return style.Render(marker+" "+at) + " " + entry.text
+}
+
+// describeWebSocketMessage renders a received message for the log. Control
+// characters in text frames are replaced, so a frame can't restyle the
+// terminal; binary frames show their size and first bytes in hex.
+func describeWebSocketMessage(msg api.WebSocketMessage) string {
+	if msg.Binary {
+		data := []byte(msg.Data)
+		preview := hex.EncodeToString(data[:min(len(data), wsBinaryPreviewBytes)])
+		if len(data) > wsBinaryPreviewBytes {
+			preview += "..."
+		}
+		return fmt.Sprintf("[binary, %s] %s", formatBytes(int64(len(data))), preview)
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && r != '\n' && r != '\t' {
+			return '.'
+		}
+		return r
+	}, msg.Data)
+}
+
+// Update handles the message input and scrolls the log
+func (wv WebSocketView) Update(msg tea.Msg) (WebSocketView, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "up", "down", "pgup", "pgdown":
+			var cmd tea.Cmd
+			wv.viewport, cmd = wv.viewport.Update(msg)
+			return wv, cmd
+		}
+	}
+
+	var cmd tea.Cmd
+	wv.input, cmd = wv.input.Update(msg)
+	return wv, cmd
+}
+
+// View renders the WebSocket view
+func (wv WebSocketView) View() string {
+	if !wv.visible {
+		return ""
+	}
+
+	var status string
+	switch {
+	case wv.connecting:
+		status = statusStyle.Render("Connecting...")
+	case wv.conn != nil:
+		status = statusStyle.Render("Connected")
+	default:
+		status = errorStyle.Render("Disconnected")
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		titleStyle.Render("WebSocket: "+wv.url),
+		status,
+		viewportView(wv.viewport, palette.Primary),
+		wv.input.View(),
+		helpStyle.Render("enter send • ↑/↓ pgup/pgdn scroll • esc disconnect and close"))
+}
+
+// connectWebSocket opens a WebSocket to a prepared ws:// or wss:// URL,
+// with the request's headers and auth
+func (m Model) connectWebSocket(prepared preparedRequest) (Model, tea.Cmd) {
+	if prepared.needsToken {
+		m.statusIndicator.Show("Send an HTTP request first to fetch the OAuth2 token", StatusWarning)
+		return m, nil
+	}
+
+	m.errorMessage = ""
+	m.errorAlert.Hide()
+	req := prepared.request
+	ctx, cancel := context.WithCancel(context.Background())
+	focus := m.webSocket.open(req.URL, cancel)
+
+	client := m.client
+	return m, tea.Batch(focus, func() tea.Msg {
+		conn, err := client.DialWebSocket(ctx, req.URL, req.Headers)
+		return WebSocketConnectedMsg{conn: conn, url: req.URL, err: err}
+	})
+}
+
+// receiveWebSocketCmd waits for the next message on conn
+func receiveWebSocketCmd(conn *api.WebSocket) tea.Cmd {
+	return func() tea.Msg {
+		message, err := conn.Receive()
+		return WebSocketMessageMsg{conn: conn, message: message, err: err}
+	}
+}
+
+// sendWebSocketCmd sends text as a text frame on conn
+func sendWebSocketCmd(conn *api.WebSocket, text string) tea.Cmd {
+	return func() tea.Msg {
+		return WebSocketSentMsg{conn: conn, text: text, err: conn.SendText(text)}
+	}
+}
+
+// updateWebSocket handles keys while the WebSocket view is open
+func (m Model) updateWebSocket(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.webSocket.close()
+		return m, tea.Quit
+	case "esc":
+		m.webSocket.close()
+		return m, nil
+	case "enter":
+		text := m.webSocket.input.Value()
+		if m.webSocket.conn == nil || text == "" {
+			return m, nil
+		}
+		m.webSocket.input.SetValue("")
+		return m, sendWebSocketCmd(m.webSocket.conn, text)
+	}
+
+	var cmd tea.Cmd
+	m.webSocket, cmd = m.webSocket.Update(msg)
+	return m, cmd
+}
+
+// handleWebSocketMsg handles connection, message and send results. Results
+// for a connection that has since been closed are dropped.
+func (m Model) handleWebSocketMsg(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case WebSocketConnectedMsg:
+		if !m.webSocket.connecting || m.webSocket.url != msg.url {
+			if msg.conn != nil {
+				msg.conn.Close()
+			}
+			return m, nil
+		}
+		if msg.err != nil {
+			if errors.Is(msg.err, context.Canceled) {
+				return m, nil
+			}
+			return m.webSocketFailed(msg.err), nil
+		}
+		m.webSocket.connected(msg.conn)
+		m.statusIndicator.Show("WebSocket connected", StatusSuccess)
+		return m, receiveWebSocketCmd(msg.conn)
+
+	case WebSocketMessageMsg:
+		if msg.conn != m.webSocket.conn {
+			return m, nil
+		}
+		if msg.err != nil {
+			msg.conn.Close()
+			return m.webSocketFailed(msg.err), nil
+		}
+		m.webSocket.append(wsReceived, describeWebSocketMessage(msg.message))
+		return m, receiveWebSocketCmd(msg.conn)
+
+	case WebSocketSentMsg:
+		if msg.conn != m.webSocket.conn {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.webSocket.append(wsError, fmt.Sprintf("Could not send: %v", msg.err))
+			return m, nil
+		}
+		m.webSocket.append(wsSent, msg.text)
+	}
+	return m, nil
+}
+
+// webSocketFailed reports a failed connection or a closed socket in the
+// log and through the error alert, which stays up after the view closes
+func (m Model) webSocketFailed(err error) Model {
+	diagnostic := m.errorAnalyzer.AnalyzeError(err, m.webSocket.url)
+	if diagnostic.Type == api.ErrorTypeTor {
+		diagnostic.Suggestions = append(diagnostic.Suggestions,
+			fmt.Sprintf("Tor proxy in use: %s (%s)", m.client.GetTorProxy(), m.client.GetTorProxySource()))
+	}
+	m.webSocket.disconnected(diagnostic)
+	m.errorAlert.Show(diagnostic)
+	if errors.Is(err, api.ErrWebSocketClosed) {
+		m.statusIndicator.Show("WebSocket closed", StatusWarning)
+	} else {
+		m.statusIndicator.Show("WebSocket failed", StatusError)
+	}
+	return m
+}
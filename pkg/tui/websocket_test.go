@@ -0,0 +1,27 @@
+package tui
+
+import (
+	"testing"
+
+	"onioncli/pkg/api"
+)
+
+func TestDescribeWebSocketMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  api.WebSocketMessage
+		want string
+	}{
+		{"text", api.WebSocketMessage{Data: "{\"event\":\"tick\"}"}, "{\"event\":\"tick\"}"},
+		{"escape sequences", api.WebSocketMessage{Data: "\x1b[2Jhi\tthere"}, ".[2Jhi\tthere"},
+		{"binary", api.WebSocketMessage{Data: "\x00\xff", Binary: true}, "[binary, 2 B] 00ff"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := describeWebSocketMessage(tt.msg); got != tt.want {
+				t.Errorf("describeWebSocketMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}